@@ -11,7 +11,7 @@ func main() {
 	a := app.App{}
 	a.Initialize(
 		app.NewDBConfig(
-			"postgres",
+			os.Getenv("DB_DRIVER"),
 			os.Getenv("DB_HOST"),
 			os.Getenv("DB_NAME"),
 			os.Getenv("DB_USER"),
@@ -23,6 +23,10 @@ func main() {
 			os.Getenv("APP_CONTEXT"),
 			os.Getenv("APP_LOG_PATH"),
 			os.Getenv("APP_ALLOWED_ORIGINS"),
+			os.Getenv("APP_SECRET"),
+			os.Getenv("APP_TRASH_RETENTION"),
+			os.Getenv("APP_BOARDS_OWNER"),
+			os.Getenv("APP_WEBHOOKS_ALLOW_PRIVATE"),
 		),
 	)
 
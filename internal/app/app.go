@@ -1,37 +1,113 @@
 package app
 
 import (
+	"context"
+	"crypto/rand"
 	"database/sql"
+	"fmt"
 	"github.com/dnozdrin/detask/internal/app/log"
 	"github.com/dnozdrin/detask/internal/delivery/http"
 	"github.com/dnozdrin/detask/internal/delivery/http/rest"
+	"github.com/dnozdrin/detask/internal/delivery/http/ws"
+	"github.com/dnozdrin/detask/internal/domain/models"
 	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/dnozdrin/detask/internal/infrastructure/events"
+	"github.com/dnozdrin/detask/internal/infrastructure/storage/memory"
 	pg "github.com/dnozdrin/detask/internal/infrastructure/storage/postgres"
+	"github.com/dnozdrin/detask/internal/infrastructure/storage/sqlite"
+	"github.com/dnozdrin/detask/internal/infrastructure/token"
+	"github.com/dnozdrin/detask/internal/infrastructure/webhook"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
 	mg "github.com/golang-migrate/migrate/v4/database/postgres"
+	ms "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file" // migrations from files
 	_ "github.com/joho/godotenv/autoload"                // automatic env vars from .env files
 	_ "github.com/lib/pq"                                // postgres driver
+	_ "github.com/mattn/go-sqlite3"                      // sqlite driver
 	"github.com/rs/cors"
 	"go.uber.org/zap"
 	stdhttp "net/http"
+	"sync"
+	"time"
 )
 
+// tokenTTL is the lifetime of the issued access tokens
+const tokenTTL = 24 * time.Hour
+
+// purgeInterval is the longest period between the trash purges
+const purgeInterval = time.Hour
+
+// eventReplaySize is the number of the recent events kept for the event streams
+// to resume from
+const eventReplaySize = 1000
+
+const (
+	// webhookWorkers is the number of the concurrent webhook delivery workers
+	webhookWorkers = 4
+	// webhookTimeout limits the time of a webhook delivery attempt
+	webhookTimeout = 10 * time.Second
+	// webhookPollInterval is the period the idle workers check the due deliveries with
+	webhookPollInterval = time.Second
+)
+
+const (
+	// outboxPollInterval is the period the idle relay checks the pending events with
+	outboxPollInterval = 200 * time.Millisecond
+	// outboxRetention is the period the delivered events are kept in the outbox for
+	outboxRetention = 24 * time.Hour
+)
+
+// overdueCheckInterval is the period the automation rules check the overdue tasks with
+const overdueCheckInterval = time.Minute
+
+// Storages is the set of the application storages of the configured driver
+type Storages struct {
+	Boards     sv.BoardStorage
+	Columns    sv.ColumnStorage
+	Tasks      sv.TaskStorage
+	Comments   sv.CommentStorage
+	Users      sv.UserStorage
+	Members    sv.MemberStorage
+	Labels     sv.LabelStorage
+	Trash      sv.TrashStorage
+	Activities sv.ActivityStorage
+	Search     sv.SearchStorage
+	Webhooks   sv.WebhookStorage
+	Deliveries sv.DeliveryStorage
+	Outbox     sv.OutboxStorage
+	Rules      sv.RuleStorage
+	Executions sv.ExecutionStorage
+}
+
 // App represents the main application handler
 type App struct {
 	config Config
 	dbConf DbConfig
 
 	DB     *sql.DB
+	memory *memory.Store
 	router *http.Router
 
+	storages Storages
+
 	log *zap.SugaredLogger
 
-	boardService   rest.BoardService
-	columnService  rest.ColumnService
-	taskService    rest.TaskService
-	commentService rest.CommentService
+	boardService    rest.BoardService
+	columnService   rest.ColumnService
+	taskService     rest.TaskService
+	commentService  rest.CommentService
+	memberService   rest.MemberService
+	labelService    rest.LabelService
+	authService     rest.AuthService
+	trashService    *sv.TrashService
+	activityService rest.ActivityService
+	searchService   rest.SearchService
+	eventService    rest.EventService
+	webhookService  *sv.WebhookService
+	ruleService     *sv.AutomationService
+	outboxRelay     *sv.OutboxRelay
 }
 
 // Initialize loads all required for application run dependencies
@@ -42,6 +118,7 @@ func (a *App) Initialize(dbConf DbConfig, config Config) {
 	a.loadLogger()
 	a.connectDB()
 	a.migrateDb()
+	a.loadStorages()
 	a.loadServices()
 	a.setupDelivery()
 }
@@ -49,9 +126,15 @@ func (a *App) Initialize(dbConf DbConfig, config Config) {
 func (a *App) connectDB() {
 	var err error
 
-	a.DB, err = sql.Open(a.dbConf.driver, a.dbConf.toConnString())
-	if err != nil {
-		a.log.Fatalf("DB connection error: %v", err)
+	switch a.dbConf.driver {
+	case Memory:
+		a.memory = memory.NewStore()
+		a.DB = sql.OpenDB(memory.NewConnector(a.memory))
+	default:
+		a.DB, err = sql.Open(a.dbConf.driver, a.dbConf.toConnString())
+		if err != nil {
+			a.log.Fatalf("DB connection error: %v", err)
+		}
 	}
 
 	if err = a.DB.Ping(); err != nil {
@@ -60,7 +143,21 @@ func (a *App) connectDB() {
 }
 
 func (a *App) migrateDb() {
-	driver, err := mg.WithInstance(a.DB, &mg.Config{})
+	if a.dbConf.driver == Memory {
+		a.log.Info("DB migration: not required for the memory driver")
+		return
+	}
+
+	var (
+		driver database.Driver
+		err    error
+	)
+	switch a.dbConf.driver {
+	case Sqlite:
+		driver, err = ms.WithInstance(a.DB, &ms.Config{NoTxWrap: true})
+	default:
+		driver, err = mg.WithInstance(a.DB, &mg.Config{})
+	}
 	if err != nil {
 		a.log.Fatalf("DB migration: failed: %v", err)
 	}
@@ -112,30 +209,142 @@ func (a *App) loadLogger() {
 	a.log = zapLogger.Sugar()
 }
 
+// loadStorages creates the storages of the configured driver
+func (a *App) loadStorages() {
+	switch a.dbConf.driver {
+	case Postgres:
+		a.storages = Storages{
+			Boards:     pg.NewBoardDAO(a.DB, a.log),
+			Columns:    pg.NewColumnDAO(a.DB, a.log),
+			Tasks:      pg.NewTaskDAO(a.DB, a.log),
+			Comments:   pg.NewCommentsDAO(a.DB, a.log),
+			Users:      pg.NewUserDAO(a.DB, a.log),
+			Members:    pg.NewMemberDAO(a.DB, a.log),
+			Labels:     pg.NewLabelDAO(a.DB, a.log),
+			Trash:      pg.NewTrashDAO(a.DB, a.log),
+			Activities: pg.NewActivityDAO(a.DB, a.log),
+			Search:     pg.NewSearchDAO(a.DB, a.log),
+			Webhooks:   pg.NewWebhookDAO(a.DB, a.log),
+			Deliveries: pg.NewDeliveryDAO(a.DB, a.log),
+			Outbox:     pg.NewOutboxDAO(a.DB, a.log),
+			Rules:      pg.NewRuleDAO(a.DB, a.log),
+			Executions: pg.NewExecutionDAO(a.DB, a.log),
+		}
+	case Sqlite:
+		a.storages = Storages{
+			Boards:     sqlite.NewBoardDAO(a.DB, a.log),
+			Columns:    sqlite.NewColumnDAO(a.DB, a.log),
+			Tasks:      sqlite.NewTaskDAO(a.DB, a.log),
+			Comments:   sqlite.NewCommentsDAO(a.DB, a.log),
+			Users:      sqlite.NewUserDAO(a.DB, a.log),
+			Members:    sqlite.NewMemberDAO(a.DB, a.log),
+			Labels:     sqlite.NewLabelDAO(a.DB, a.log),
+			Trash:      sqlite.NewTrashDAO(a.DB, a.log),
+			Activities: sqlite.NewActivityDAO(a.DB, a.log),
+			Search:     sqlite.NewSearchDAO(a.DB, a.log),
+			Webhooks:   sqlite.NewWebhookDAO(a.DB, a.log),
+			Deliveries: sqlite.NewDeliveryDAO(a.DB, a.log),
+			Outbox:     sqlite.NewOutboxDAO(a.DB, a.log),
+			Rules:      sqlite.NewRuleDAO(a.DB, a.log),
+			Executions: sqlite.NewExecutionDAO(a.DB, a.log),
+		}
+	case Memory:
+		a.storages = Storages{
+			Boards:     memory.NewBoardDAO(a.memory, a.log),
+			Columns:    memory.NewColumnDAO(a.memory, a.log),
+			Tasks:      memory.NewTaskDAO(a.memory, a.log),
+			Comments:   memory.NewCommentsDAO(a.memory, a.log),
+			Users:      memory.NewUserDAO(a.memory, a.log),
+			Members:    memory.NewMemberDAO(a.memory, a.log),
+			Labels:     memory.NewLabelDAO(a.memory, a.log),
+			Trash:      memory.NewTrashDAO(a.memory, a.log),
+			Activities: memory.NewActivityDAO(a.memory, a.log),
+			Search:     memory.NewSearchDAO(a.memory, a.log),
+			Webhooks:   memory.NewWebhookDAO(a.memory, a.log),
+			Deliveries: memory.NewDeliveryDAO(a.memory, a.log),
+			Outbox:     memory.NewOutboxDAO(a.memory, a.log),
+			Rules:      memory.NewRuleDAO(a.memory, a.log),
+			Executions: memory.NewExecutionDAO(a.memory, a.log),
+		}
+	default:
+		a.log.Fatalf("%s driver support is not implemented", a.dbConf.driver)
+	}
+}
+
 func (a *App) loadServices() {
 	validatorImpl := NewValidator(validator.New(), a.log)
-
-	var (
-		boardStorage   sv.BoardStorage
-		columnStorage  sv.ColumnStorage
-		taskStorage    sv.TaskStorage
-		commentStorage sv.CommentStorage
+	st := a.storages
+
+	eventBroker := events.NewBroker(eventReplaySize)
+
+	a.boardService = sv.NewBoardService(validatorImpl, st.Boards, st.Columns, st.Members, st.Activities, st.Outbox, a.DB)
+	a.columnService = sv.NewColumnService(validatorImpl, st.Columns, st.Tasks, st.Members, st.Activities, st.Outbox, a.DB)
+	taskService := sv.NewTaskService(validatorImpl, st.Tasks, st.Members, st.Activities, st.Outbox, a.DB)
+	a.taskService = taskService
+	commentService := sv.NewCommentService(validatorImpl, st.Comments, st.Members, st.Activities, st.Outbox, a.DB)
+	a.commentService = commentService
+	memberService := sv.NewMemberService(validatorImpl, st.Members, st.Activities, st.Outbox, a.DB)
+	a.memberService = memberService
+	a.labelService = sv.NewLabelService(validatorImpl, st.Labels, st.Members, st.Activities, st.Outbox, a.DB)
+	a.trashService = sv.NewTrashService(st.Trash, st.Members, st.Activities, st.Outbox, a.DB)
+	a.activityService = sv.NewActivityService(st.Activities, st.Members)
+	a.searchService = sv.NewSearchService(st.Search, st.Members)
+	a.eventService = sv.NewEventService(eventBroker, st.Members)
+	a.webhookService = sv.NewWebhookService(
+		validatorImpl, st.Webhooks, st.Deliveries, st.Members, webhook.NewSender(webhookTimeout, a.config.privateHooks),
+	)
+	a.ruleService = sv.NewAutomationService(
+		validatorImpl, st.Rules, st.Executions, st.Tasks, st.Members, taskService, commentService, a.webhookService,
 	)
+	a.outboxRelay = sv.NewOutboxRelay(st.Outbox)
+	a.outboxRelay.Register("events", func(event models.Event) error {
+		eventBroker.Publish(event)
+		return nil
+	})
+	a.outboxRelay.Register("webhooks", a.webhookService.Notify)
+	a.outboxRelay.Register("automation", a.ruleService.Handle)
+	a.authService = sv.NewAuthService(validatorImpl, st.Users, token.NewJWT(a.loadSecret(), tokenTTL))
 
-	switch a.dbConf.driver {
-	case "postgres":
-		boardStorage = pg.NewBoardDAO(a.DB, a.log)
-		columnStorage = pg.NewColumnDAO(a.DB, a.log)
-		taskStorage = pg.NewTaskDAO(a.DB, a.log)
-		commentStorage = pg.NewCommentsDAO(a.DB, a.log)
-	default:
-		a.log.Fatalf("%s driver support is not implemented", a.dbConf.driver)
+	a.adoptBoards(memberService)
+}
+
+// adoptBoards makes the configured boards owner the owner of the boards that have
+// none, such as the boards created before the board members were introduced
+func (a *App) adoptBoards(memberService *sv.MemberService) {
+	if a.config.boardsOwner == "" {
+		return
 	}
 
-	a.boardService = sv.NewBoardService(validatorImpl, boardStorage, columnStorage, a.DB)
-	a.columnService = sv.NewColumnService(validatorImpl, columnStorage, taskStorage, a.DB)
-	a.taskService = sv.NewTaskService(validatorImpl, taskStorage)
-	a.commentService = sv.NewCommentService(validatorImpl, commentStorage)
+	user, err := a.storages.Users.FindOneByEmail(a.config.boardsOwner)
+	if err != nil {
+		a.log.Warnf("boards adoption: user %s is not found: %v", a.config.boardsOwner, err)
+		return
+	}
+
+	members, err := memberService.Adopt(sv.WithUser(context.Background(), user))
+	if err != nil {
+		a.log.Fatalf("boards adoption error: %v", err)
+	}
+	a.log.Infof("boards adoption: %d boards adopted by %s", len(members), user.Email)
+}
+
+// loadSecret returns the key for access tokens signing. A random key is generated
+// if none is configured, so the issued tokens are valid until the app restarts.
+func (a *App) loadSecret() []byte {
+	if a.config.secret != "" {
+		return []byte(a.config.secret)
+	}
+	if a.config.context == Prod {
+		a.log.Fatal("APP_SECRET is required in the production context")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		a.log.Fatalf("secret generation failed: %v", err)
+	}
+	a.log.Warn("APP_SECRET is not set, a random one is used: tokens will expire on restart")
+
+	return secret
 }
 
 func (a *App) setupDelivery() {
@@ -143,48 +352,124 @@ func (a *App) setupDelivery() {
 	subRouter := a.router.GetSubRouter("/api/v1")
 
 	healthCheckHandler := rest.NewHealthCheck(a.log)
+	authHandler := rest.NewAuthHandler(a.authService, a.log)
 	boardHandle := rest.NewBoardHandler(a.boardService, a.log, subRouter)
 	columnHandler := rest.NewColumnHandler(a.columnService, a.log, subRouter)
 	taskHandler := rest.NewTaskHandler(a.taskService, a.log, subRouter)
 	commentHandler := rest.NewCommentHandler(a.commentService, a.log, subRouter)
+	memberHandler := rest.NewMemberHandler(a.memberService, a.log, subRouter)
+	labelHandler := rest.NewLabelHandler(a.labelService, a.log, subRouter)
+	trashHandler := rest.NewTrashHandler(a.trashService, a.log, subRouter)
+	activityHandler := rest.NewActivityHandler(a.activityService, a.log, subRouter)
+	searchHandler := rest.NewSearchHandler(a.searchService, a.log)
+	eventHandler := rest.NewEventHandler(a.eventService, a.log, subRouter)
+	webhookHandler := rest.NewWebhookHandler(a.webhookService, a.log, subRouter)
+	ruleHandler := rest.NewRuleHandler(a.ruleService, a.log, subRouter)
+	wsHandler := ws.NewHandler(a.eventService, a.log, a.config.allowedOrigins)
+
+	var publicRoutes = http.Routes{
+		http.Route{Pattern: "/health", Method: "GET", Name: "health", HandlerFunc: healthCheckHandler.Status},
+
+		http.Route{Pattern: "/auth/register", Method: "POST", Name: "register", HandlerFunc: authHandler.Register},
+		http.Route{Pattern: "/auth/login", Method: "POST", Name: "login", HandlerFunc: authHandler.Login},
+	}
 
 	var routes = http.Routes{
-		http.Route{Pattern: "/health", Method: "GET", Name: "health", HandlerFunc: healthCheckHandler.Status},
+		http.Route{Pattern: "/me", Method: "GET", Name: "get_current_user", HandlerFunc: authHandler.Me},
+		http.Route{Pattern: "/me/tasks", Method: "GET", Name: "get_my_tasks", HandlerFunc: taskHandler.GetAssigned},
+		http.Route{Pattern: "/ws", Method: "GET", Name: "websocket", HandlerFunc: wsHandler.Serve},
 
 		http.Route{Pattern: "/board", Method: "POST", Name: "new_board", HandlerFunc: boardHandle.Create},
 		http.Route{Pattern: "/boards", Method: "GET", Name: "get_boards", HandlerFunc: boardHandle.Get},
 		http.Route{Pattern: "/boards/{id:[0-9]+}", Method: "GET", Name: "get_board", HandlerFunc: boardHandle.GetOneById},
 		http.Route{Pattern: "/boards/{id:[0-9]+}", Method: "PUT", Name: "update_board", HandlerFunc: boardHandle.Update},
+		http.Route{Pattern: "/boards/{id:[0-9]+}", Method: "PATCH", Name: "patch_board", HandlerFunc: boardHandle.Patch},
 		http.Route{Pattern: "/boards/{id:[0-9]+}", Method: "DELETE", Name: "delete_board", HandlerFunc: boardHandle.Delete},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/clone", Method: "POST", Name: "clone_board", HandlerFunc: boardHandle.Clone},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/restore", Method: "POST", Name: "restore_board", HandlerFunc: trashHandler.Restore(models.TrashBoard)},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/activity", Method: "GET", Name: "get_board_activity", HandlerFunc: activityHandler.GetByBoard},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/events", Method: "GET", Name: "get_board_events", HandlerFunc: eventHandler.Stream},
+
+		http.Route{Pattern: "/boards/{id:[0-9]+}/members", Method: "POST", Name: "new_member", HandlerFunc: memberHandler.Create},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/members", Method: "GET", Name: "get_members", HandlerFunc: memberHandler.Get},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/members/{user:[0-9]+}", Method: "PUT", Name: "update_member", HandlerFunc: memberHandler.Update},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/members/{user:[0-9]+}", Method: "DELETE", Name: "delete_member", HandlerFunc: memberHandler.Delete},
+
+		http.Route{Pattern: "/boards/{id:[0-9]+}/webhooks", Method: "POST", Name: "new_webhook", HandlerFunc: webhookHandler.Create},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/webhooks", Method: "GET", Name: "get_webhooks", HandlerFunc: webhookHandler.GetByBoard},
+		http.Route{Pattern: "/webhooks/{id:[0-9]+}", Method: "GET", Name: "get_webhook", HandlerFunc: webhookHandler.GetOneById},
+		http.Route{Pattern: "/webhooks/{id:[0-9]+}", Method: "PUT", Name: "update_webhook", HandlerFunc: webhookHandler.Update},
+		http.Route{Pattern: "/webhooks/{id:[0-9]+}", Method: "DELETE", Name: "delete_webhook", HandlerFunc: webhookHandler.Delete},
+		http.Route{Pattern: "/webhooks/{id:[0-9]+}/deliveries", Method: "GET", Name: "get_webhook_deliveries", HandlerFunc: webhookHandler.GetDeliveries},
+		http.Route{Pattern: "/webhooks/{id:[0-9]+}/deliveries/{delivery:[0-9]+}/redeliver", Method: "POST", Name: "redeliver_webhook_delivery", HandlerFunc: webhookHandler.Redeliver},
+
+		http.Route{Pattern: "/boards/{id:[0-9]+}/rules", Method: "POST", Name: "new_rule", HandlerFunc: ruleHandler.Create},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/rules", Method: "GET", Name: "get_rules", HandlerFunc: ruleHandler.GetByBoard},
+		http.Route{Pattern: "/rules/{id:[0-9]+}", Method: "GET", Name: "get_rule", HandlerFunc: ruleHandler.GetOneById},
+		http.Route{Pattern: "/rules/{id:[0-9]+}", Method: "PUT", Name: "update_rule", HandlerFunc: ruleHandler.Update},
+		http.Route{Pattern: "/rules/{id:[0-9]+}", Method: "DELETE", Name: "delete_rule", HandlerFunc: ruleHandler.Delete},
+		http.Route{Pattern: "/rules/{id:[0-9]+}/executions", Method: "GET", Name: "get_rule_executions", HandlerFunc: ruleHandler.GetExecutions},
 
 		http.Route{Pattern: "/column", Method: "POST", Name: "new_column", HandlerFunc: columnHandler.Create},
 		http.Route{Pattern: "/columns", Method: "GET", Name: "get_columns", HandlerFunc: columnHandler.Get},
 		http.Route{Pattern: "/columns/{id:[0-9]+}", Method: "GET", Name: "get_column", HandlerFunc: columnHandler.GetOneById},
 		http.Route{Pattern: "/columns/{id:[0-9]+}", Method: "PUT", Name: "update_column", HandlerFunc: columnHandler.Update},
+		http.Route{Pattern: "/columns/{id:[0-9]+}", Method: "PATCH", Name: "patch_column", HandlerFunc: columnHandler.Patch},
 		http.Route{Pattern: "/columns/{id:[0-9]+}", Method: "DELETE", Name: "delete_column", HandlerFunc: columnHandler.Delete},
+		http.Route{Pattern: "/columns/{id:[0-9]+}/move", Method: "POST", Name: "move_column", HandlerFunc: columnHandler.Move},
+		http.Route{Pattern: "/columns/{id:[0-9]+}/restore", Method: "POST", Name: "restore_column", HandlerFunc: trashHandler.Restore(models.TrashColumn)},
+		http.Route{Pattern: "/boards/{id:[0-9]+}/columns/order", Method: "PUT", Name: "reorder_columns", HandlerFunc: columnHandler.Reorder},
+
+		http.Route{Pattern: "/label", Method: "POST", Name: "new_label", HandlerFunc: labelHandler.Create},
+		http.Route{Pattern: "/labels", Method: "GET", Name: "get_labels", HandlerFunc: labelHandler.Get},
+		http.Route{Pattern: "/labels/{id:[0-9]+}", Method: "GET", Name: "get_label", HandlerFunc: labelHandler.GetOneById},
+		http.Route{Pattern: "/labels/{id:[0-9]+}", Method: "PUT", Name: "update_label", HandlerFunc: labelHandler.Update},
+		http.Route{Pattern: "/labels/{id:[0-9]+}", Method: "PATCH", Name: "patch_label", HandlerFunc: labelHandler.Patch},
+		http.Route{Pattern: "/labels/{id:[0-9]+}", Method: "DELETE", Name: "delete_label", HandlerFunc: labelHandler.Delete},
 
 		http.Route{Pattern: "/task", Method: "POST", Name: "create_task", HandlerFunc: taskHandler.Create},
 		http.Route{Pattern: "/tasks", Method: "GET", Name: "get_tasks", HandlerFunc: taskHandler.Get},
 		http.Route{Pattern: "/tasks/{id:[0-9]+}", Method: "GET", Name: "get_task", HandlerFunc: taskHandler.GetOneById},
 		http.Route{Pattern: "/tasks/{id:[0-9]+}", Method: "PUT", Name: "update_task", HandlerFunc: taskHandler.Update},
+		http.Route{Pattern: "/tasks/{id:[0-9]+}", Method: "PATCH", Name: "patch_task", HandlerFunc: taskHandler.Patch},
 		http.Route{Pattern: "/tasks/{id:[0-9]+}", Method: "DELETE", Name: "delete_task", HandlerFunc: taskHandler.Delete},
+		http.Route{Pattern: "/tasks/{id:[0-9]+}/move", Method: "POST", Name: "move_task", HandlerFunc: taskHandler.Move},
+		http.Route{Pattern: "/tasks/{id:[0-9]+}/transfer", Method: "POST", Name: "transfer_task", HandlerFunc: taskHandler.Transfer},
+		http.Route{Pattern: "/tasks/{id:[0-9]+}/restore", Method: "POST", Name: "restore_task", HandlerFunc: trashHandler.Restore(models.TrashTask)},
+		http.Route{Pattern: "/tasks/{id:[0-9]+}/activity", Method: "GET", Name: "get_task_activity", HandlerFunc: activityHandler.GetByTask},
 
 		http.Route{Pattern: "/comment", Method: "POST", Name: "create_comment", HandlerFunc: commentHandler.Create},
 		http.Route{Pattern: "/comments", Method: "GET", Name: "get_comments", HandlerFunc: commentHandler.Get},
 		http.Route{Pattern: "/comments/{id:[0-9]+}", Method: "GET", Name: "get_comment", HandlerFunc: commentHandler.GetOneById},
 		http.Route{Pattern: "/comments/{id:[0-9]+}", Method: "PUT", Name: "update_comment", HandlerFunc: commentHandler.Update},
+		http.Route{Pattern: "/comments/{id:[0-9]+}", Method: "PATCH", Name: "patch_comment", HandlerFunc: commentHandler.Patch},
 		http.Route{Pattern: "/comments/{id:[0-9]+}", Method: "DELETE", Name: "delete_comment", HandlerFunc: commentHandler.Delete},
+		http.Route{Pattern: "/comments/{id:[0-9]+}/restore", Method: "POST", Name: "restore_comment", HandlerFunc: trashHandler.Restore(models.TrashComment)},
+
+		http.Route{Pattern: "/trash", Method: "GET", Name: "get_trash", HandlerFunc: trashHandler.Get},
+
+		http.Route{Pattern: "/search", Method: "GET", Name: "search", HandlerFunc: searchHandler.Get},
 	}
 
-	for _, route := range routes {
+	for _, route := range publicRoutes {
 		subRouter.Register(route)
 	}
+
+	protectedRouter := subRouter.WithMiddleware(authHandler.Authenticate)
+	for _, route := range routes {
+		protectedRouter.Register(route)
+	}
 }
 
 func (a *App) addCORSMiddleware(handler stdhttp.Handler) stdhttp.Handler {
 	c := cors.New(cors.Options{
 		AllowedOrigins: a.config.allowedOrigins,
-		AllowedMethods: []string{"HEAD", "GET", "POST", "DELETE", "PUT"},
+		AllowedMethods: []string{"HEAD", "GET", "POST", "DELETE", "PUT", "PATCH"},
+		AllowedHeaders: []string{
+			"Origin", "Accept", "Content-Type", "X-Requested-With", "Authorization",
+			"If-Match", "If-None-Match", "If-Modified-Since", "Last-Event-ID",
+		},
+		ExposedHeaders: []string{"Link", "ETag", "Last-Modified"},
 		Debug:          a.config.context == Dev,
 	})
 
@@ -192,21 +477,190 @@ func (a *App) addCORSMiddleware(handler stdhttp.Handler) stdhttp.Handler {
 	return c.Handler(handler)
 }
 
-// Run will start the web server on the given address
+// Run will start the web server on the given address along with the periodic
+// purge of the trash, the relay of the events, the webhook delivery workers and
+// the check of the overdue tasks by the automation rules
 func (a *App) Run(addr string) {
+	stop, done := make(chan struct{}), make(chan struct{})
+	relayed, delivered, checked := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go a.purgeTrash(stop, done)
+	go a.relayOutbox(stop, relayed)
+	go a.deliverWebhooks(stop, delivered)
+	go a.checkOverdue(stop, checked)
+
 	if err := http.NewServer(a.addCORSMiddleware(a.router), a.log).Start(addr); err != nil {
 		a.log.Fatalf("http: server: listen and server: %v", err)
 	}
 
+	close(stop)
+	<-done
+	<-relayed
+	<-delivered
+	<-checked
 	a.syncLogger()
 	a.closeDB()
 }
 
+// purgeTrash permanently deletes the records that have been kept in the trash
+// longer than the configured retention period, until the stop channel is closed
+func (a *App) purgeTrash(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	interval := purgeInterval
+	if a.config.trashRetention < interval {
+		interval = a.config.trashRetention
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.trashService.Purge(a.config.trashRetention); err != nil {
+			a.log.Errorf("trash purge error: %v", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relayOutbox passes the pending events of the outbox to their consumers and purges
+// the delivered ones periodically, until the stop channel is closed
+func (a *App) relayOutbox(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	purge := time.NewTicker(purgeInterval)
+	defer purge.Stop()
+
+	for {
+		n, err := a.outboxRelay.Relay()
+		if err != nil {
+			a.log.Errorf("outbox relay error: %v", err)
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-purge.C:
+			if err := a.outboxRelay.Purge(outboxRetention); err != nil {
+				a.log.Errorf("outbox purge error: %v", err)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverWebhooks runs the workers that attempt the due webhook deliveries until the
+// stop channel is closed, the attempts in progress are interrupted on stop
+func (a *App) deliverWebhooks(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < webhookWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ticker := time.NewTicker(webhookPollInterval)
+			defer ticker.Stop()
+
+			for {
+				n, err := a.webhookService.Deliver(ctx)
+				if err != nil {
+					a.log.Errorf("webhook delivery error: %v", err)
+				}
+				if n > 0 && err == nil && ctx.Err() == nil {
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// checkOverdue runs the automation rules triggered by the overdue tasks periodically,
+// until the stop channel is closed
+func (a *App) checkOverdue(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(overdueCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.ruleService.CheckOverdue(); err != nil {
+			a.log.Errorf("overdue tasks check error: %v", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // ServeHTTPInternal is used for end to end tests
 func (a *App) ServeHTTPInternal(w stdhttp.ResponseWriter, req *stdhttp.Request) {
 	a.router.ServeHTTP(w, req)
 }
 
+// RelayInternal is used for end to end tests, it relays all the pending events
+func (a *App) RelayInternal() error {
+	for {
+		n, err := a.outboxRelay.Relay()
+		if n == 0 || err != nil {
+			return err
+		}
+	}
+}
+
+// StoragesInternal is used for end to end tests, it returns the storages to seed
+// and to inspect the records with
+func (a *App) StoragesInternal() Storages {
+	return a.storages
+}
+
+// ResetInternal is used for end to end tests, it removes all the records and
+// restarts the ID sequences of the configured driver
+func (a *App) ResetInternal() error {
+	switch a.dbConf.driver {
+	case Postgres:
+		return pg.Reset(a.DB)
+	case Sqlite:
+		return sqlite.Reset(a.DB)
+	case Memory:
+		a.memory.Reset()
+		return nil
+	default:
+		return fmt.Errorf("%s driver support is not implemented", a.dbConf.driver)
+	}
+}
+
+// CheckOverdueInternal is used for end to end tests, it runs the automation rules
+// triggered by the overdue tasks
+func (a *App) CheckOverdueInternal() error {
+	return a.ruleService.CheckOverdue()
+}
+
 // syncLogger flushes any buffered log entries. Applications should take care
 // to call Sync before exiting. Check for "sync /dev/stderr: invalid argument"
 // error is added for development log preset and should be removed as soon as
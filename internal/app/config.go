@@ -2,7 +2,9 @@ package app
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -14,15 +16,34 @@ const (
 	Dev = "development"
 )
 
+const (
+	// Postgres is the default DB driver
+	Postgres = "postgres"
+	// Sqlite is a DB driver for SQLite database files
+	Sqlite = "sqlite3"
+	// Memory is a DB driver that keeps all the data in the application memory
+	Memory = "memory"
+)
+
+// defaultTrashRetention is the time the deleted records are kept in the trash
+// for if no valid retention period is configured
+const defaultTrashRetention = 30 * 24 * time.Hour
+
 // Config represents the application configuration
 type Config struct {
 	context        string
 	logPath        string
 	allowedOrigins []string
+	secret         string
+	trashRetention time.Duration
+	boardsOwner    string
+	privateHooks   bool
 }
 
-// NewConfig is a Config constructor
-func NewConfig(context, logPath, allowedOrigins string) Config {
+// NewConfig is a Config constructor, the trash retention is a duration string
+// such as "720h", the boards owner is the email of the user that adopts the boards
+// without owners on start, the private hooks flag is a boolean string such as "true"
+func NewConfig(context, logPath, allowedOrigins, secret, trashRetention, boardsOwner, privateHooks string) Config {
 	if context != Prod && context != Test {
 		context = Dev
 	}
@@ -32,10 +53,21 @@ func NewConfig(context, logPath, allowedOrigins string) Config {
 		origins[k] = strings.TrimSpace(origin)
 	}
 
+	retention, err := time.ParseDuration(trashRetention)
+	if err != nil || retention <= 0 {
+		retention = defaultTrashRetention
+	}
+
+	allowPrivate, _ := strconv.ParseBool(privateHooks)
+
 	return Config{
 		context: context,
 		logPath: logPath,
 		allowedOrigins: origins,
+		secret:         secret,
+		trashRetention: retention,
+		boardsOwner:    strings.TrimSpace(boardsOwner),
+		privateHooks:   allowPrivate,
 	}
 }
 
@@ -46,6 +78,10 @@ type DbConfig struct {
 
 // NewDBConfig is a DbConfig constructor
 func NewDBConfig(driver, host, name, user, password, port, mgPath string) DbConfig {
+	if driver == "" {
+		driver = Postgres
+	}
+
 	return DbConfig{
 		driver:   driver,
 		host:     host,
@@ -58,6 +94,10 @@ func NewDBConfig(driver, host, name, user, password, port, mgPath string) DbConf
 }
 
 func (c DbConfig) toConnString() string {
+	if c.driver == Sqlite {
+		return fmt.Sprintf("file:%s?_foreign_keys=1&_busy_timeout=5000&_txlock=immediate", c.name)
+	}
+
 	return fmt.Sprintf(
 		"host=%s dbname=%s user=%s password=%s port=%s sslmode=disable",
 		c.host,
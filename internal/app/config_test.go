@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestNewConfig(t *testing.T) {
@@ -13,6 +14,10 @@ func TestNewConfig(t *testing.T) {
 		context        string
 		logPath        string
 		allowedOrigins string
+		secret         string
+		trashRetention string
+		boardsOwner    string
+		privateHooks   string
 	}
 	tests := []struct {
 		name string
@@ -21,31 +26,104 @@ func TestNewConfig(t *testing.T) {
 	}{
 		{
 			"test_context",
-			args{Test, "stderr", ""},
-			Config{Test, "stderr", []string{""}},
+			args{Test, "stderr", "", "secret", "", "", ""},
+			Config{Test, "stderr", []string{""}, "secret", defaultTrashRetention, "", false},
 		},
 		{
 			"dev_ontext",
-			args{Dev, "stdout", "http://localhost:8080"},
-			Config{Dev, "stdout", []string{"http://localhost:8080"}}},
+			args{Dev, "stdout", "http://localhost:8080", "", "168h", "", ""},
+			Config{Dev, "stdout", []string{"http://localhost:8080"}, "", 168 * time.Hour, "", false}},
 		{
 			"prod_context",
-			args{Prod, "file:///dev/null", "http://localhost:8080,http://localhost:80"},
-			Config{Prod, "file:///dev/null", []string{"http://localhost:8080", "http://localhost:80"}},
+			args{Prod, "file:///dev/null", "http://localhost:8080,http://localhost:80", "secret", "90m", "", ""},
+			Config{Prod, "file:///dev/null", []string{"http://localhost:8080", "http://localhost:80"}, "secret", 90 * time.Minute, "", false},
 		},		{
 			"whitespaces_origings",
-			args{Dev, "stderr", "http://localhost:8080, http://localhost:80 "},
-			Config{Dev, "stderr", []string{"http://localhost:8080", "http://localhost:80"}},
+			args{Dev, "stderr", "http://localhost:8080, http://localhost:80 ", "", "", "", ""},
+			Config{Dev, "stderr", []string{"http://localhost:8080", "http://localhost:80"}, "", defaultTrashRetention, "", false},
 		},
 		{
 			"unknown_context",
-			args{mock.Anything, mock.Anything, ""},
-			Config{Dev, mock.Anything, []string{""}},
+			args{mock.Anything, mock.Anything, "", "", "", "", ""},
+			Config{Dev, mock.Anything, []string{""}, "", defaultTrashRetention, "", false},
+		},
+		{
+			"invalid_trash_retention",
+			args{Dev, "stderr", "", "", "a month", "", ""},
+			Config{Dev, "stderr", []string{""}, "", defaultTrashRetention, "", false},
+		},
+		{
+			"boards_owner",
+			args{Dev, "stderr", "", "", "", " owner@example.com ", ""},
+			Config{Dev, "stderr", []string{""}, "", defaultTrashRetention, "owner@example.com", false},
+		},
+		{
+			"private_hooks",
+			args{Dev, "stderr", "", "", "", "", "true"},
+			Config{Dev, "stderr", []string{""}, "", defaultTrashRetention, "", true},
+		},
+		{
+			"invalid_private_hooks",
+			args{Dev, "stderr", "", "", "", "", "sure"},
+			Config{Dev, "stderr", []string{""}, "", defaultTrashRetention, "", false},
+		},
+		{
+			"negative_trash_retention",
+			args{Dev, "stderr", "", "", "-1h", "", ""},
+			Config{Dev, "stderr", []string{""}, "", defaultTrashRetention, "", false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewConfig(
+				tt.args.context,
+				tt.args.logPath,
+				tt.args.allowedOrigins,
+				tt.args.secret,
+				tt.args.trashRetention,
+				tt.args.boardsOwner,
+				tt.args.privateHooks,
+			))
+		})
+	}
+}
+
+func TestNewDBConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		driver string
+		want   string
+	}{
+		{"default_driver", "", Postgres},
+		{"memory_driver", Memory, Memory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewDBConfig(tt.driver, "", "", "", "", "", "").driver)
+		})
+	}
+}
+
+func TestDbConfig_toConnString(t *testing.T) {
+	tests := []struct {
+		name string
+		conf DbConfig
+		want string
+	}{
+		{
+			"postgres",
+			NewDBConfig(Postgres, "localhost", "test", "user", "pass", "5432", ""),
+			"host=localhost dbname=test user=user password=pass port=5432 sslmode=disable",
+		},
+		{
+			"sqlite",
+			NewDBConfig(Sqlite, "", "/tmp/detask.db", "", "", "", ""),
+			"file:/tmp/detask.db?_foreign_keys=1&_busy_timeout=5000&_txlock=immediate",
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.want, NewConfig(tt.args.context, tt.args.logPath, tt.args.allowedOrigins))
+			assert.Equal(t, tt.want, tt.conf.toConnString())
 		})
 	}
 }
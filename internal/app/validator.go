@@ -4,6 +4,7 @@ import (
 	"github.com/dnozdrin/detask/internal/app/log"
 	"reflect"
 	"strings"
+	"time"
 
 	in "github.com/dnozdrin/detask/internal/domain/validation"
 	pkg "github.com/go-playground/validator/v10"
@@ -15,8 +16,16 @@ type Validator struct {
 	validate *pkg.Validate
 }
 
-// NewValidator is a Validator constructor
+// NewValidator is a Validator constructor, it registers the custom rules
+// of the application on the provided validate instance
 func NewValidator(validate *pkg.Validate, log log.Logger) *Validator {
+	if err := validate.RegisterValidation("afterfield", isAfterField); err != nil {
+		log.Errorf("unable to register the afterfield validation: %v", err)
+	}
+	if err := validate.RegisterValidation("excludedfield", isExcludedField); err != nil {
+		log.Errorf("unable to register the excludedfield validation: %v", err)
+	}
+
 	return &Validator{
 		validate: validate,
 		log:      log,
@@ -49,32 +58,84 @@ func convertValidationErrors(target interface{}, err error) *in.Errors {
 	result := in.NewErrors()
 	validationErrors := err.(pkg.ValidationErrors)
 
-	reflected := reflect.ValueOf(target)
+	typ := reflect.TypeOf(target)
 	for _, e := range validationErrors {
-		field, _ := reflected.Type().FieldByName(e.StructField())
+		name := fieldName(typ, e.StructField())
+		result.Add(in.Error{Field: name, Message: formatMessage(e, name, typ)})
+	}
 
-		var name string
-		if name = field.Tag.Get("json"); name == "" {
-			name = strings.ToLower(e.StructField())
-		}
+	return result
+}
+
+// fieldName returns the name of the struct field in the API representation
+func fieldName(typ reflect.Type, structField string) string {
+	field, _ := typ.FieldByName(structField)
 
-		result.Add(in.Error{Field: name, Message: formatMessage(e, name)})
+	var name string
+	if name = field.Tag.Get("json"); name == "" {
+		name = strings.ToLower(structField)
 	}
 
-	return result
+	return name
 }
 
-func formatMessage(err pkg.FieldError, name string) (message string) {
+func formatMessage(err pkg.FieldError, name string, typ reflect.Type) (message string) {
 	switch err.Tag() {
+	case "afterfield":
+		message = name + " must be after " + fieldName(typ, err.Param())
+	case "excludedfield":
+		message = name + " can not be used along with " + fieldName(typ, err.Param())
 	case "required":
 		message = name + " is required"
 	case "max":
-		message = name + " must be of " + err.Param() + " symbols max"
+		if isNumber(err.Kind()) {
+			message = name + " must not be greater than " + err.Param()
+		} else {
+			message = name + " must be of " + err.Param() + " symbols max"
+		}
 	case "min":
-		message = name + " must be of " + err.Param() + " symbols min"
+		if isNumber(err.Kind()) {
+			message = name + " must not be less than " + err.Param()
+		} else {
+			message = name + " must be of " + err.Param() + " symbols min"
+		}
+	case "oneof":
+		message = name + " must be one of: " + strings.Join(strings.Fields(err.Param()), ", ")
 	default:
 		message = name + " is invalid"
 	}
 
 	return message
 }
+
+// isExcludedField reports if the field provided as the param is not set, the rule
+// is meant to be used with omitempty, so that only one of the fields may be set
+func isExcludedField(fl pkg.FieldLevel) bool {
+	other, _, ok := fl.GetStructFieldOK()
+	if !ok {
+		return true
+	}
+
+	return !other.IsValid() || other.IsZero()
+}
+
+// isNumber reports if the kind is one of the numeric kinds
+func isNumber(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// isAfterField reports if the time of the field is after the time of the field
+// provided as the param, the rule is satisfied if the other field is not set
+func isAfterField(fl pkg.FieldLevel) bool {
+	other, kind, ok := fl.GetStructFieldOK()
+	if !ok || kind != reflect.Struct {
+		return true
+	}
+	before, ok := other.Interface().(time.Time)
+	if !ok {
+		return false
+	}
+	after, ok := fl.Field().Interface().(time.Time)
+
+	return ok && after.After(before)
+}
@@ -4,6 +4,7 @@ package app
 
 import (
 	"testing"
+	"time"
 
 	in "github.com/dnozdrin/detask/internal/domain/validation"
 	validate "github.com/go-playground/validator/v10"
@@ -113,3 +114,94 @@ func TestValidationErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestAfterFieldValidation(t *testing.T) {
+	type dates struct {
+		StartAt *time.Time `json:"start_at"`
+		DueAt   *time.Time `json:"due_at" validate:"omitempty,afterfield=StartAt"`
+	}
+	start, due := time.Unix(1589932800, 0), time.Unix(1590019200, 0)
+
+	tests := []struct {
+		name   string
+		target dates
+		valid  bool
+	}{
+		{"no_dates", dates{}, true},
+		{"start_only", dates{StartAt: &start}, true},
+		{"due_only", dates{DueAt: &due}, true},
+		{"start_before_due", dates{StartAt: &start, DueAt: &due}, true},
+		{"start_after_due", dates{StartAt: &due, DueAt: &start}, false},
+		{"same_time", dates{StartAt: &start, DueAt: &start}, false},
+	}
+	validator := NewValidator(validate.New(), new(LoggerMock))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validator.Validate(test.target)
+			if test.valid {
+				assert.Nil(t, err)
+				return
+			}
+			assert.Equal(t, 1, err.Num())
+			assert.JSONEq(
+				t,
+				`{"error":"validation failed","errors":[{"field":"due_at","message":"due_at must be after start_at"}]}`,
+				marshal(t, err),
+			)
+		})
+	}
+}
+
+func marshal(t *testing.T, err *in.Errors) string {
+	data, e := err.MarshalJSON()
+	assert.NoError(t, e)
+
+	return string(data)
+}
+
+func TestOneOfValidation(t *testing.T) {
+	target := struct {
+		Priority string `json:"priority" validate:"omitempty,oneof=low high"`
+	}{"urgent"}
+
+	err := NewValidator(validate.New(), new(LoggerMock)).Validate(target)
+	assert.JSONEq(
+		t,
+		`{"error":"validation failed","errors":[{"field":"priority","message":"priority must be one of: low, high"}]}`,
+		marshal(t, err),
+	)
+}
+
+func TestNumberRangeValidation(t *testing.T) {
+	target := struct {
+		Limit uint `json:"wip_limit" validate:"max=1000"`
+		Count int  `json:"count" validate:"min=1"`
+	}{5000, 0}
+
+	err := NewValidator(validate.New(), new(LoggerMock)).Validate(target)
+	assert.JSONEq(
+		t,
+		`{"error":"validation failed","errors":[
+			{"field":"wip_limit","message":"wip_limit must not be greater than 1000"},
+			{"field":"count","message":"count must not be less than 1"}
+		]}`,
+		marshal(t, err),
+	)
+}
+
+func TestExcludedFieldValidation(t *testing.T) {
+	type move struct {
+		BeforeID uint `json:"before" validate:"omitempty,excludedfield=AfterID"`
+		AfterID  uint `json:"after"`
+	}
+
+	validator := NewValidator(validate.New(), new(LoggerMock))
+	assert.Nil(t, validator.Validate(move{}))
+	assert.Nil(t, validator.Validate(move{BeforeID: 1}))
+	assert.Nil(t, validator.Validate(move{AfterID: 1}))
+	assert.JSONEq(
+		t,
+		`{"error":"validation failed","errors":[{"field":"before","message":"before can not be used along with after"}]}`,
+		marshal(t, validator.Validate(move{BeforeID: 1, AfterID: 2})),
+	)
+}
@@ -0,0 +1,6 @@
+begin;
+alter table comments drop column if exists created_by;
+alter table tasks drop column if exists created_by;
+alter table boards drop column if exists created_by;
+drop table if exists users cascade;
+commit;
@@ -0,0 +1,21 @@
+begin;
+create table users
+(
+    id            serial primary key,
+    created_at    timestamp    not null default now(),
+    updated_at    timestamp    not null default now(),
+
+    email         varchar(255) not null,
+    name          varchar(255) not null,
+    password_hash varchar(255) not null,
+
+    unique (email)
+);
+
+alter table boards
+    add column created_by int references users (id) on delete set null;
+alter table tasks
+    add column created_by int references users (id) on delete set null;
+alter table comments
+    add column created_by int references users (id) on delete set null;
+commit;
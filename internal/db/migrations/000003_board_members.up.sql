@@ -0,0 +1,20 @@
+begin;
+create table board_members
+(
+    board_id   int         not null references boards (id) on delete cascade,
+    user_id    int         not null references users (id) on delete cascade,
+    role       varchar(16) not null check (role in ('owner', 'editor', 'viewer')),
+    created_at timestamp   not null default now(),
+
+    primary key (board_id, user_id)
+);
+
+create index board_members_user_idx on board_members (user_id);
+
+-- the boards created before the authentication have no creator and are left without owners,
+-- the user configured with APP_BOARDS_OWNER adopts them on the application start
+insert into board_members (board_id, user_id, role)
+select id, created_by, 'owner'
+from boards
+where created_by is not null;
+commit;
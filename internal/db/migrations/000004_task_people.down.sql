@@ -0,0 +1,5 @@
+begin;
+drop table if exists task_assignees;
+alter table tasks
+    drop column if exists reporter;
+commit;
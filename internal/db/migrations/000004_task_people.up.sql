@@ -0,0 +1,17 @@
+begin;
+alter table tasks
+    add column reporter int references users (id) on delete set null;
+
+update tasks
+set reporter = created_by;
+
+create table task_assignees
+(
+    task_id int not null references tasks (id) on delete cascade,
+    user_id int not null references users (id) on delete cascade,
+
+    primary key (task_id, user_id)
+);
+
+create index task_assignees_user_idx on task_assignees (user_id);
+commit;
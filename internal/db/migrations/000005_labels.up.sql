@@ -0,0 +1,25 @@
+begin;
+create table labels
+(
+    id         serial primary key,
+    created_at timestamp   not null default now(),
+    updated_at timestamp   not null default now(),
+
+    name       varchar(50) not null,
+    color      varchar(7)  not null,
+    board      int         not null,
+
+    unique (name, board),
+    foreign key (board) references boards (id) on delete cascade
+);
+
+create table task_labels
+(
+    task_id  int not null references tasks (id) on delete cascade,
+    label_id int not null references labels (id) on delete cascade,
+
+    primary key (task_id, label_id)
+);
+
+create index task_labels_label_idx on task_labels (label_id);
+commit;
@@ -0,0 +1,6 @@
+begin;
+drop index if exists tasks_due_at_idx;
+alter table tasks
+    drop column if exists start_at,
+    drop column if exists due_at;
+commit;
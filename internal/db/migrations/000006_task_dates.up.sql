@@ -0,0 +1,7 @@
+begin;
+alter table tasks
+    add column start_at timestamp,
+    add column due_at   timestamp;
+
+create index tasks_due_at_idx on tasks (due_at);
+commit;
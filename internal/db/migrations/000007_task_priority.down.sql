@@ -0,0 +1,5 @@
+begin;
+drop index if exists tasks_priority_idx;
+alter table tasks
+    drop column if exists priority;
+commit;
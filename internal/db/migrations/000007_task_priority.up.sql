@@ -0,0 +1,7 @@
+begin;
+-- the priority is stored as its rank from 1 (lowest) to 5 (highest)
+alter table tasks
+    add column priority smallint not null default 3 check (priority between 1 and 5);
+
+create index tasks_priority_idx on tasks (priority);
+commit;
@@ -0,0 +1,4 @@
+begin;
+alter table columns
+    drop column if exists wip_limit;
+commit;
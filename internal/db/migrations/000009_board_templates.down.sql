@@ -0,0 +1,5 @@
+begin;
+alter table boards
+    drop column if exists template_id,
+    drop column if exists template;
+commit;
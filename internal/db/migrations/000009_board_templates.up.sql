@@ -0,0 +1,5 @@
+begin;
+alter table boards
+    add column template    boolean not null default false,
+    add column template_id int references boards (id) on delete set null;
+commit;
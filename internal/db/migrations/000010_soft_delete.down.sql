@@ -0,0 +1,25 @@
+begin;
+delete from boards where deleted_at is not null;
+delete from columns where deleted_at is not null;
+delete from tasks where deleted_at is not null;
+delete from comments where deleted_at is not null;
+
+drop index if exists tasks_position_column_key;
+alter table tasks
+    add constraint tasks_position_column_key unique (position, "column");
+
+drop index if exists columns_name_board_key;
+drop index if exists columns_position_board_key;
+alter table columns
+    add constraint columns_name_board_key unique (name, board),
+    add constraint columns_position_board_key unique (position, board);
+
+alter table comments
+    drop column if exists deleted_at;
+alter table tasks
+    drop column if exists deleted_at;
+alter table columns
+    drop column if exists deleted_at;
+alter table boards
+    drop column if exists deleted_at;
+commit;
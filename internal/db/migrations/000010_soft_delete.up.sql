@@ -0,0 +1,27 @@
+begin;
+alter table boards
+    add column deleted_at timestamp;
+alter table columns
+    add column deleted_at timestamp;
+alter table tasks
+    add column deleted_at timestamp;
+alter table comments
+    add column deleted_at timestamp;
+
+-- the deleted records must not hold names and positions, so the unique constraints
+-- are replaced with the partial unique indexes of the same names
+alter table columns
+    drop constraint columns_name_board_key,
+    drop constraint columns_position_board_key;
+create unique index columns_name_board_key on columns (name, board) where deleted_at is null;
+create unique index columns_position_board_key on columns (position, board) where deleted_at is null;
+
+alter table tasks
+    drop constraint tasks_position_column_key;
+create unique index tasks_position_column_key on tasks (position, "column") where deleted_at is null;
+
+create index boards_deleted_at_idx on boards (deleted_at) where deleted_at is not null;
+create index columns_deleted_at_idx on columns (deleted_at) where deleted_at is not null;
+create index tasks_deleted_at_idx on tasks (deleted_at) where deleted_at is not null;
+create index comments_deleted_at_idx on comments (deleted_at) where deleted_at is not null;
+commit;
@@ -0,0 +1,10 @@
+begin;
+alter table boards
+    drop column if exists version;
+alter table columns
+    drop column if exists version;
+alter table tasks
+    drop column if exists version;
+alter table comments
+    drop column if exists version;
+commit;
@@ -0,0 +1,12 @@
+begin;
+-- the version of a record is bumped on every change, so concurrent writers can tell
+-- whether the record has been changed since they have fetched it
+alter table boards
+    add column version integer not null default 1;
+alter table columns
+    add column version integer not null default 1;
+alter table tasks
+    add column version integer not null default 1;
+alter table comments
+    add column version integer not null default 1;
+commit;
@@ -0,0 +1,6 @@
+begin;
+alter table comments
+    drop column if exists search;
+alter table tasks
+    drop column if exists search;
+commit;
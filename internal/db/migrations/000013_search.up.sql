@@ -0,0 +1,14 @@
+begin;
+-- the search vectors are kept up to date by the database, the names of the tasks weigh
+-- more than their descriptions in the ranking of the search hits
+alter table tasks
+    add column search tsvector generated always as (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', description), 'B')
+    ) stored;
+alter table comments
+    add column search tsvector generated always as (to_tsvector('english', coalesce(text, ''))) stored;
+
+create index tasks_search_idx on tasks using gin (search);
+create index comments_search_idx on comments using gin (search);
+commit;
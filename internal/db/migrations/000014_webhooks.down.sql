@@ -0,0 +1,4 @@
+begin;
+drop table if exists webhook_deliveries;
+drop table if exists webhooks;
+commit;
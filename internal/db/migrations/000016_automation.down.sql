@@ -0,0 +1,4 @@
+begin;
+drop table if exists rule_executions;
+drop table if exists rules;
+commit;
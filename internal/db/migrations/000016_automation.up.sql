@@ -0,0 +1,37 @@
+begin;
+-- the trigger of a rule is a JSON object, its conditions and actions are JSON arrays,
+-- the actions are taken on behalf of the author of the rule
+create table rules
+(
+    id         serial primary key,
+    created_at timestamp    not null default now(),
+    updated_at timestamp    not null default now(),
+
+    board      int          not null references boards (id) on delete cascade,
+    name       varchar(255) not null,
+    "trigger"  jsonb        not null,
+    conditions jsonb        not null default '[]',
+    actions    jsonb        not null default '[]',
+    disabled   boolean      not null default false,
+    created_by int          not null references users (id) on delete cascade
+);
+
+create index rules_board_idx on rules (board, id);
+
+-- the event of an execution is the event that has triggered the run, there is none
+-- for the runs on the overdue tasks
+create table rule_executions
+(
+    id         serial primary key,
+    created_at timestamp   not null default now(),
+
+    rule       int         not null references rules (id) on delete cascade,
+    event      int,
+    task       int         not null,
+    status     varchar(16) not null,
+    error      text        not null default ''
+);
+
+create index rule_executions_rule_idx on rule_executions (rule, id);
+create index rule_executions_task_idx on rule_executions (rule, task, id);
+commit;
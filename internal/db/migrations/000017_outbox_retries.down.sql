@@ -0,0 +1,14 @@
+begin;
+drop index if exists rule_executions_event_idx;
+drop index if exists webhook_deliveries_webhook_event_id_key;
+alter table webhook_deliveries
+    drop column if exists event_id;
+
+drop table if exists outbox_consumers;
+drop index if exists outbox_pending_idx;
+alter table outbox
+    drop column if exists attempts,
+    drop column if exists next_attempt,
+    drop column if exists dead_at;
+create index outbox_pending_idx on outbox (id) where delivered_at is null;
+commit;
@@ -0,0 +1,6 @@
+begin;
+drop table if exists comments;
+drop table if exists tasks;
+drop table if exists columns;
+drop table if exists boards;
+commit;
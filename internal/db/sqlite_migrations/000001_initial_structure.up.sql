@@ -0,0 +1,52 @@
+begin;
+create table boards
+(
+    id          integer primary key autoincrement,
+    created_at  timestamp not null default current_timestamp,
+    updated_at  timestamp not null default current_timestamp,
+
+    name        varchar(500),
+    description varchar(1000) not null default ''
+);
+
+create table columns
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp,
+
+    name       varchar(255),
+    board      integer   not null,
+    position   real      not null,
+
+    unique (name, board),
+    unique (position, board),
+    foreign key (board) references boards (id) on delete cascade
+);
+
+create table tasks
+(
+    id          integer primary key autoincrement,
+    created_at  timestamp not null default current_timestamp,
+    updated_at  timestamp not null default current_timestamp,
+
+    name        varchar(500),
+    description varchar(5000) not null default '',
+    "column"    integer   not null,
+    position    real      not null,
+
+    unique (position, "column"),
+    foreign key ("column") references columns (id) on delete cascade
+);
+
+create table comments
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp,
+
+    text       varchar(5000),
+    task       integer   not null,
+    foreign key (task) references tasks (id) on delete cascade
+);
+commit;
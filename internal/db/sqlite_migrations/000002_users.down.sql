@@ -0,0 +1,58 @@
+-- SQLite can not drop columns, so the tables are rebuilt without them
+-- (see https://www.sqlite.org/lang_altertable.html#otheralter)
+pragma foreign_keys = off;
+begin;
+create table boards_new
+(
+    id          integer primary key autoincrement,
+    created_at  timestamp not null default current_timestamp,
+    updated_at  timestamp not null default current_timestamp,
+
+    name        varchar(500),
+    description varchar(1000) not null default ''
+);
+insert into boards_new (id, created_at, updated_at, name, description)
+select id, created_at, updated_at, name, description
+from boards;
+drop table boards;
+alter table boards_new rename to boards;
+
+create table tasks_new
+(
+    id          integer primary key autoincrement,
+    created_at  timestamp not null default current_timestamp,
+    updated_at  timestamp not null default current_timestamp,
+
+    name        varchar(500),
+    description varchar(5000) not null default '',
+    "column"    integer   not null,
+    position    real      not null,
+
+    unique (position, "column"),
+    foreign key ("column") references columns (id) on delete cascade
+);
+insert into tasks_new (id, created_at, updated_at, name, description, "column", position)
+select id, created_at, updated_at, name, description, "column", position
+from tasks;
+drop table tasks;
+alter table tasks_new rename to tasks;
+
+create table comments_new
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp,
+
+    text       varchar(5000),
+    task       integer   not null,
+    foreign key (task) references tasks (id) on delete cascade
+);
+insert into comments_new (id, created_at, updated_at, text, task)
+select id, created_at, updated_at, text, task
+from comments;
+drop table comments;
+alter table comments_new rename to comments;
+
+drop table if exists users;
+commit;
+pragma foreign_keys = on;
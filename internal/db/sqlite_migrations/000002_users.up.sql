@@ -0,0 +1,21 @@
+begin;
+create table users
+(
+    id            integer primary key autoincrement,
+    created_at    timestamp    not null default current_timestamp,
+    updated_at    timestamp    not null default current_timestamp,
+
+    email         varchar(255) not null,
+    name          varchar(255) not null,
+    password_hash varchar(255) not null,
+
+    unique (email)
+);
+
+alter table boards
+    add column created_by integer references users (id) on delete set null;
+alter table tasks
+    add column created_by integer references users (id) on delete set null;
+alter table comments
+    add column created_by integer references users (id) on delete set null;
+commit;
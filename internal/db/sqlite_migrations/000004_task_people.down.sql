@@ -0,0 +1,28 @@
+-- SQLite can not drop columns, so the tasks table is rebuilt without the reporter
+-- (see https://www.sqlite.org/lang_altertable.html#otheralter)
+pragma foreign_keys = off;
+begin;
+drop table if exists task_assignees;
+
+create table tasks_new
+(
+    id          integer primary key autoincrement,
+    created_at  timestamp not null default current_timestamp,
+    updated_at  timestamp not null default current_timestamp,
+
+    name        varchar(500),
+    description varchar(5000) not null default '',
+    "column"    integer   not null,
+    position    real      not null,
+    created_by  integer references users (id) on delete set null,
+
+    unique (position, "column"),
+    foreign key ("column") references columns (id) on delete cascade
+);
+insert into tasks_new (id, created_at, updated_at, name, description, "column", position, created_by)
+select id, created_at, updated_at, name, description, "column", position, created_by
+from tasks;
+drop table tasks;
+alter table tasks_new rename to tasks;
+commit;
+pragma foreign_keys = on;
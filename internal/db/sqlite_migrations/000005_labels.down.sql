@@ -0,0 +1,4 @@
+begin;
+drop table if exists task_labels;
+drop table if exists labels;
+commit;
@@ -0,0 +1,25 @@
+begin;
+create table labels
+(
+    id         integer primary key autoincrement,
+    created_at timestamp   not null default current_timestamp,
+    updated_at timestamp   not null default current_timestamp,
+
+    name       varchar(50) not null,
+    color      varchar(7)  not null,
+    board      integer     not null,
+
+    unique (name, board),
+    foreign key (board) references boards (id) on delete cascade
+);
+
+create table task_labels
+(
+    task_id  integer not null references tasks (id) on delete cascade,
+    label_id integer not null references labels (id) on delete cascade,
+
+    primary key (task_id, label_id)
+);
+
+create index task_labels_label_idx on task_labels (label_id);
+commit;
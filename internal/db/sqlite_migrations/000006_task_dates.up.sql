@@ -0,0 +1,8 @@
+begin;
+alter table tasks
+    add column start_at timestamp;
+alter table tasks
+    add column due_at timestamp;
+
+create index tasks_due_at_idx on tasks (due_at);
+commit;
@@ -0,0 +1,25 @@
+-- SQLite can not drop columns, so the columns table is rebuilt without the WIP limit
+-- (see https://www.sqlite.org/lang_altertable.html#otheralter)
+pragma foreign_keys = off;
+begin;
+create table columns_new
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp,
+
+    name       varchar(255),
+    board      integer   not null,
+    position   real      not null,
+
+    unique (name, board),
+    unique (position, board),
+    foreign key (board) references boards (id) on delete cascade
+);
+insert into columns_new (id, created_at, updated_at, name, board, position)
+select id, created_at, updated_at, name, board, position
+from columns;
+drop table columns;
+alter table columns_new rename to columns;
+commit;
+pragma foreign_keys = on;
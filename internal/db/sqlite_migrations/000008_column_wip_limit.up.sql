@@ -0,0 +1,4 @@
+begin;
+alter table columns
+    add column wip_limit integer check (wip_limit > 0);
+commit;
@@ -0,0 +1,21 @@
+-- SQLite can not drop columns, so the boards table is rebuilt without the template fields
+-- (see https://www.sqlite.org/lang_altertable.html#otheralter)
+pragma foreign_keys = off;
+begin;
+create table boards_new
+(
+    id          integer primary key autoincrement,
+    created_at  timestamp not null default current_timestamp,
+    updated_at  timestamp not null default current_timestamp,
+
+    name        varchar(500),
+    description varchar(1000) not null default '',
+    created_by  integer references users (id) on delete set null
+);
+insert into boards_new (id, created_at, updated_at, name, description, created_by)
+select id, created_at, updated_at, name, description, created_by
+from boards;
+drop table boards;
+alter table boards_new rename to boards;
+commit;
+pragma foreign_keys = on;
@@ -0,0 +1,6 @@
+begin;
+alter table boards
+    add column template boolean not null default false;
+alter table boards
+    add column template_id integer references boards (id) on delete set null;
+commit;
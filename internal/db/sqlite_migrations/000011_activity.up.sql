@@ -0,0 +1,20 @@
+begin;
+-- the entries of a task and of its comments keep the task ID after the task is purged,
+-- so the history of the board stays complete
+create table activities
+(
+    id         integer primary key autoincrement,
+    created_at timestamp   not null default current_timestamp,
+
+    board      integer     not null references boards (id) on delete cascade,
+    task       integer,
+    entity     varchar(16) not null,
+    entity_id  integer     not null,
+    action     varchar(16) not null,
+    actor      integer references users (id) on delete set null,
+    changes    text        not null default '{}'
+);
+
+create index activities_board_idx on activities (board, id);
+create index activities_task_idx on activities (task, id) where task is not null;
+commit;
@@ -0,0 +1,95 @@
+-- SQLite can not drop columns, so the tables are rebuilt without the version
+-- (see https://www.sqlite.org/lang_altertable.html#otheralter)
+pragma foreign_keys = off;
+begin;
+create table boards_new
+(
+    id          integer primary key autoincrement,
+    created_at  timestamp not null default current_timestamp,
+    updated_at  timestamp not null default current_timestamp,
+
+    name        varchar(500),
+    description varchar(1000) not null default '',
+    created_by  integer references users (id) on delete set null,
+    template    boolean   not null default false,
+    template_id integer references boards (id) on delete set null,
+    deleted_at  timestamp
+);
+insert into boards_new (id, created_at, updated_at, name, description, created_by, template, template_id, deleted_at)
+select id, created_at, updated_at, name, description, created_by, template, template_id, deleted_at
+from boards;
+drop table boards;
+alter table boards_new rename to boards;
+
+create table columns_new
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp,
+
+    name       varchar(255),
+    board      integer   not null,
+    position   real      not null,
+    wip_limit  integer check (wip_limit > 0),
+    deleted_at timestamp,
+
+    foreign key (board) references boards (id) on delete cascade
+);
+insert into columns_new (id, created_at, updated_at, name, board, position, wip_limit, deleted_at)
+select id, created_at, updated_at, name, board, position, wip_limit, deleted_at
+from columns;
+drop table columns;
+alter table columns_new rename to columns;
+create unique index columns_name_board_key on columns (name, board) where deleted_at is null;
+create unique index columns_position_board_key on columns (position, board) where deleted_at is null;
+
+create table tasks_new
+(
+    id          integer primary key autoincrement,
+    created_at  timestamp not null default current_timestamp,
+    updated_at  timestamp not null default current_timestamp,
+
+    name        varchar(500),
+    description varchar(5000) not null default '',
+    "column"    integer   not null,
+    position    real      not null,
+    created_by  integer references users (id) on delete set null,
+    reporter    integer references users (id) on delete set null,
+    start_at    timestamp,
+    due_at      timestamp,
+    priority    smallint  not null default 3 check (priority between 1 and 5),
+    deleted_at  timestamp,
+
+    foreign key ("column") references columns (id) on delete cascade
+);
+insert into tasks_new (id, created_at, updated_at, name, description, "column", position, created_by, reporter,
+                       start_at, due_at, priority, deleted_at)
+select id, created_at, updated_at, name, description, "column", position, created_by, reporter,
+       start_at, due_at, priority, deleted_at
+from tasks;
+drop table tasks;
+alter table tasks_new rename to tasks;
+create unique index tasks_position_column_key on tasks (position, "column") where deleted_at is null;
+create index tasks_due_at_idx on tasks (due_at);
+create index tasks_priority_idx on tasks (priority);
+
+create table comments_new
+(
+    id         integer primary key autoincrement,
+    created_at timestamp not null default current_timestamp,
+    updated_at timestamp not null default current_timestamp,
+
+    text       varchar(5000),
+    task       integer   not null,
+    created_by integer references users (id) on delete set null,
+    deleted_at timestamp,
+
+    foreign key (task) references tasks (id) on delete cascade
+);
+insert into comments_new (id, created_at, updated_at, text, task, created_by, deleted_at)
+select id, created_at, updated_at, text, task, created_by, deleted_at
+from comments;
+drop table comments;
+alter table comments_new rename to comments;
+commit;
+pragma foreign_keys = on;
@@ -0,0 +1,12 @@
+begin;
+drop trigger if exists comments_search_ai;
+drop trigger if exists comments_search_au;
+drop trigger if exists comments_search_bd;
+drop trigger if exists comments_search_bu;
+drop trigger if exists tasks_search_ai;
+drop trigger if exists tasks_search_au;
+drop trigger if exists tasks_search_bd;
+drop trigger if exists tasks_search_bu;
+drop table if exists comments_search;
+drop table if exists tasks_search;
+commit;
@@ -0,0 +1,44 @@
+-- the search indexes are the full-text tables over the content of the tasks and of the
+-- comments, they are kept in sync with the content by the triggers
+-- (see https://www.sqlite.org/fts3.html#_external_content_fts4_tables_)
+begin;
+create virtual table tasks_search using fts4(content="tasks", name, description, tokenize=porter);
+create virtual table comments_search using fts4(content="comments", text, tokenize=porter);
+
+create trigger tasks_search_bu before update of name, description on tasks
+begin
+    delete from tasks_search where docid = old.id;
+end;
+create trigger tasks_search_bd before delete on tasks
+begin
+    delete from tasks_search where docid = old.id;
+end;
+create trigger tasks_search_au after update of name, description on tasks
+begin
+    insert into tasks_search (docid, name, description) values (new.id, new.name, new.description);
+end;
+create trigger tasks_search_ai after insert on tasks
+begin
+    insert into tasks_search (docid, name, description) values (new.id, new.name, new.description);
+end;
+
+create trigger comments_search_bu before update of text on comments
+begin
+    delete from comments_search where docid = old.id;
+end;
+create trigger comments_search_bd before delete on comments
+begin
+    delete from comments_search where docid = old.id;
+end;
+create trigger comments_search_au after update of text on comments
+begin
+    insert into comments_search (docid, text) values (new.id, new.text);
+end;
+create trigger comments_search_ai after insert on comments
+begin
+    insert into comments_search (docid, text) values (new.id, new.text);
+end;
+
+insert into tasks_search (tasks_search) values ('rebuild');
+insert into comments_search (comments_search) values ('rebuild');
+commit;
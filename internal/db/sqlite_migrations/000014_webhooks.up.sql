@@ -0,0 +1,39 @@
+begin;
+-- the events of a webhook are a JSON array of the event types, an empty array
+-- subscribes the webhook to all the events
+create table webhooks
+(
+    id         integer primary key autoincrement,
+    created_at timestamp     not null default current_timestamp,
+    updated_at timestamp     not null default current_timestamp,
+
+    board      integer       not null references boards (id) on delete cascade,
+    url        varchar(2000) not null,
+    secret     varchar(255)  not null,
+    events     text          not null default '[]',
+    disabled   boolean       not null default false
+);
+
+create index webhooks_board_idx on webhooks (board, id);
+
+-- the pending deliveries are claimed by the workers once their next attempt is due,
+-- the next attempt of the finished ones is null
+create table webhook_deliveries
+(
+    id            integer primary key autoincrement,
+    created_at    timestamp   not null default current_timestamp,
+    updated_at    timestamp   not null default current_timestamp,
+
+    webhook       integer     not null references webhooks (id) on delete cascade,
+    event         varchar(64) not null,
+    payload       text        not null,
+    status        varchar(16) not null default 'pending',
+    attempts      integer     not null default 0,
+    response_code integer     not null default 0,
+    error         text        not null default '',
+    next_attempt  timestamp
+);
+
+create index webhook_deliveries_webhook_idx on webhook_deliveries (webhook, id);
+create index webhook_deliveries_due_idx on webhook_deliveries (next_attempt, id) where status = 'pending';
+commit;
@@ -0,0 +1,17 @@
+begin;
+-- the change events are saved within the transactions of the changes and relayed
+-- to the consumers in the order of their IDs, the relayed ones are marked delivered
+create table outbox
+(
+    id           integer primary key autoincrement,
+    created_at   timestamp   not null default current_timestamp,
+
+    event        varchar(64) not null,
+    board        integer     not null,
+    payload      text        not null,
+    delivered_at timestamp
+);
+
+create index outbox_pending_idx on outbox (id) where delivered_at is null;
+create index outbox_delivered_idx on outbox (delivered_at) where delivered_at is not null;
+commit;
@@ -0,0 +1,52 @@
+-- SQLite can not drop columns, so the outbox and the webhook deliveries tables are
+-- rebuilt without the new ones (see https://www.sqlite.org/lang_altertable.html#otheralter)
+pragma foreign_keys = off;
+begin;
+drop index if exists rule_executions_event_idx;
+drop table if exists outbox_consumers;
+
+create table outbox_new
+(
+    id           integer primary key autoincrement,
+    created_at   timestamp   not null default current_timestamp,
+
+    event        varchar(64) not null,
+    board        integer     not null,
+    payload      text        not null,
+    delivered_at timestamp
+);
+insert into outbox_new (id, created_at, event, board, payload, delivered_at)
+select id, created_at, event, board, payload, delivered_at
+from outbox;
+drop table outbox;
+alter table outbox_new rename to outbox;
+
+create index outbox_pending_idx on outbox (id) where delivered_at is null;
+create index outbox_delivered_idx on outbox (delivered_at) where delivered_at is not null;
+
+create table webhook_deliveries_new
+(
+    id            integer primary key autoincrement,
+    created_at    timestamp   not null default current_timestamp,
+    updated_at    timestamp   not null default current_timestamp,
+
+    webhook       integer     not null references webhooks (id) on delete cascade,
+    event         varchar(64) not null,
+    payload       text        not null,
+    status        varchar(16) not null default 'pending',
+    attempts      integer     not null default 0,
+    response_code integer     not null default 0,
+    error         text        not null default '',
+    next_attempt  timestamp
+);
+insert into webhook_deliveries_new (id, created_at, updated_at, webhook, event, payload, status, attempts,
+                                    response_code, error, next_attempt)
+select id, created_at, updated_at, webhook, event, payload, status, attempts, response_code, error, next_attempt
+from webhook_deliveries;
+drop table webhook_deliveries;
+alter table webhook_deliveries_new rename to webhook_deliveries;
+
+create index webhook_deliveries_webhook_idx on webhook_deliveries (webhook, id);
+create index webhook_deliveries_due_idx on webhook_deliveries (next_attempt, id) where status = 'pending';
+commit;
+pragma foreign_keys = on;
@@ -0,0 +1,32 @@
+begin;
+-- the relay records the consumers that have accepted an event, so an event one of them
+-- has failed is passed again only to the consumers that have not accepted it yet, once
+-- its next attempt is due. An event failed too many times is dead: it is kept in the
+-- outbox but not relayed anymore
+alter table outbox
+    add column attempts integer not null default 0;
+alter table outbox
+    add column next_attempt timestamp;
+alter table outbox
+    add column dead_at timestamp;
+
+drop index outbox_pending_idx;
+create index outbox_pending_idx on outbox (id) where delivered_at is null and dead_at is null;
+
+create table outbox_consumers
+(
+    event    integer     not null references outbox (id) on delete cascade,
+    consumer varchar(64) not null,
+
+    primary key (event, consumer)
+);
+
+-- the consumers skip the events they have already handled, as the relay passes an event
+-- again if it has failed halfway: a webhook gets a delivery of an event only once and
+-- a rule runs once per event
+alter table webhook_deliveries
+    add column event_id integer;
+
+create unique index webhook_deliveries_webhook_event_id_key on webhook_deliveries (webhook, event_id) where event_id is not null;
+create index rule_executions_event_idx on rule_executions (rule, event) where event is not null;
+commit;
@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// ActivityHandler provides a Rest API http handlers for work with the activity history
+type ActivityHandler struct {
+	service ActivityService
+	log     log.Logger
+	router  routeAware
+	resp    *responder
+}
+
+// NewActivityHandler is an ActivityHandler constructor
+func NewActivityHandler(service ActivityService, logger log.Logger, router routeAware) *ActivityHandler {
+	return &ActivityHandler{
+		service: service,
+		log:     logger,
+		router:  router,
+		resp:    &responder{log: logger},
+	}
+}
+
+// GetByBoard will respond with the requested activity entries of the board or an error
+func (h ActivityHandler) GetByBoard(w http.ResponseWriter, r *http.Request) {
+	h.get(w, r, h.service.FindByBoard)
+}
+
+// GetByTask will respond with the requested activity entries of the task or an error
+func (h ActivityHandler) GetByTask(w http.ResponseWriter, r *http.Request) {
+	h.get(w, r, h.service.FindByTask)
+}
+
+// get will respond with the activity entries of the requested resource found by
+// the provided service method
+func (h ActivityHandler) get(
+	w http.ResponseWriter,
+	r *http.Request,
+	find func(context.Context, uint, services.ActivityDemand, services.Page) ([]*models.Activity, *services.Cursor, error),
+) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
+		return
+	}
+
+	demand, page := make(services.ActivityDemand), services.Page{}
+	if err = parseFilter(r, demand, &page); err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidFilterParams)
+		return
+	}
+
+	entries, next, err := find(r.Context(), ID, demand, page)
+	switch {
+	case err == nil:
+		setNextPageLink(w, r, next)
+		h.resp.respondJSON(w, http.StatusOK, entries)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	default:
+		h.log.Errorf("error while getting records: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+	}
+}
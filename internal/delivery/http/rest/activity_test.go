@@ -0,0 +1,93 @@
+// +build unit
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActivityHandler_GetByBoard(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debug", mock.Anything).Return()
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name   string
+		query  string
+		demand services.ActivityDemand
+		next   *services.Cursor
+		err    error
+		code   int
+	}{
+		{"all", "", services.ActivityDemand{}, nil, nil, http.StatusOK},
+		{"by_actor_and_entity", "?actor=1&entity=task", services.ActivityDemand{"actor": uint(1), "entity": models.EntityTask}, nil, nil, http.StatusOK},
+		{"next_page", "?limit=1", services.ActivityDemand{}, &services.Cursor{ID: 9}, nil, http.StatusOK},
+		{"invalid_entity", "?entity=user", nil, nil, nil, http.StatusBadRequest},
+		{"not_found", "", services.ActivityDemand{}, nil, services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", "", services.ActivityDemand{}, nil, services.ErrForbidden, http.StatusForbidden},
+		{"internal", "", services.ActivityDemand{}, nil, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/boards/2/activity"+tt.query, nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(2), nil)
+			service := new(ActivityServiceMock)
+			service.On("FindByBoard", req.Context(), uint(2), tt.demand, mock.Anything).
+				Return([]*models.Activity{{ID: 9, BoardID: 2}}, tt.next, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewActivityHandler(service, logger, router).GetByBoard(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.demand == nil {
+				service.AssertNotCalled(t, "FindByBoard", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.next != nil {
+				assert.Contains(t, recorder.Header().Get("Link"), "cursor="+tt.next.Encode())
+			}
+		})
+	}
+}
+
+func TestActivityHandler_GetByTask(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	t.Run("success", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/tasks/5/activity", nil)
+		router := new(RouteAwareMock)
+		router.On("GetIDVar", req).Return(uint(5), nil)
+		service := new(ActivityServiceMock)
+		service.On("FindByTask", req.Context(), uint(5), services.ActivityDemand{}, services.Page{}).
+			Return([]*models.Activity{{ID: 3, TaskID: 5}}, (*services.Cursor)(nil), nil)
+
+		recorder := httptest.NewRecorder()
+		NewActivityHandler(service, logger, router).GetByTask(recorder, req)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Link"))
+	})
+
+	t.Run("invalid_identifier", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/tasks/x/activity", nil)
+		router := new(RouteAwareMock)
+		router.On("GetIDVar", req).Return(uint(0), errors.New("dummy"))
+		service := new(ActivityServiceMock)
+
+		recorder := httptest.NewRecorder()
+		NewActivityHandler(service, logger, router).GetByTask(recorder, req)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		service.AssertNotCalled(t, "FindByTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
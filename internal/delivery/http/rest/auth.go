@@ -0,0 +1,158 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+// AuthHandler provides a Rest API http handlers for users registration and
+// authentication as well as the authentication middleware
+type AuthHandler struct {
+	service AuthService
+	log     log.Logger
+	resp    *responder
+}
+
+// NewAuthHandler is AuthHandler constructor
+func NewAuthHandler(service AuthService, logger log.Logger) *AuthHandler {
+	return &AuthHandler{
+		service: service,
+		log:     logger,
+		resp:    &responder{log: logger},
+	}
+}
+
+// Register will call registration of the provided user
+func (h AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var user models.User
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if err := json.Unmarshal(reqBody, &user); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return
+	}
+
+	newUser, err := h.service.Register(&user)
+	switch {
+	case err == nil:
+		h.resp.respondJSON(w, http.StatusCreated, newUser)
+	case errors.Is(err, services.ErrEmailDuplicate):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debug("user was not registered", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("user was not registered: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// Login will respond with a new access token for the provided credentials
+func (h AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var credentials models.Credentials
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if err := json.Unmarshal(reqBody, &credentials); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return
+	}
+
+	token, err := h.service.Login(credentials)
+	switch {
+	case err == nil:
+		h.resp.respondJSON(w, http.StatusOK, struct {
+			Token     string `json:"token"`
+			TokenType string `json:"token_type"`
+		}{token, strings.TrimSpace(bearerPrefix)})
+	case errors.Is(err, services.ErrInvalidCredentials):
+		h.log.Debugf("login failed: %v", err)
+		h.resp.respondError(w, http.StatusUnauthorized, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debug("login failed", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("login failed: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// Me will respond with the authenticated user
+func (h AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		h.unauthorized(w, services.ErrUnauthenticated)
+		return
+	}
+
+	h.resp.respondJSON(w, http.StatusOK, user)
+}
+
+// Authenticate is a middleware that rejects requests without a valid bearer
+// token and puts the authenticated user into the request context
+func (h AuthHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			h.unauthorized(w, services.ErrUnauthenticated)
+			return
+		}
+
+		user, err := h.service.Authenticate(token)
+		switch {
+		case err == nil:
+			next.ServeHTTP(w, r.WithContext(services.WithUser(r.Context(), user)))
+		case errors.Is(err, services.ErrUnauthenticated):
+			h.unauthorized(w, err)
+		default:
+			h.log.Errorf("authentication failed: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	})
+}
+
+func (h AuthHandler) unauthorized(w http.ResponseWriter, err error) {
+	h.log.Debugf("unauthenticated request: %v", err)
+	w.Header().Set("WWW-Authenticate", strings.TrimSpace(bearerPrefix))
+	h.resp.respondError(w, http.StatusUnauthorized, err.Error())
+}
+
+// bearerToken returns the access token of the request from the Authorization header.
+// The event streams and the WebSocket upgrades may pass the token in the access_token
+// query parameter as well, as the browsers do not let them set the headers
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	if strings.HasPrefix(header, bearerPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)), true
+	}
+	if token := r.URL.Query().Get("access_token"); token != "" && r.Method == http.MethodGet &&
+		(isEventStream(r) || websocket.IsWebSocketUpgrade(r)) {
+		return token, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,106 @@
+// +build unit
+
+package rest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthHandler_Authenticate(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	user := &models.User{Model: models.Model{ID: 1}}
+	service := new(AuthServiceMock)
+	service.On("Authenticate", "valid").Return(user, nil)
+	service.On("Authenticate", "invalid").Return(&models.User{}, services.ErrUnauthenticated)
+	service.On("Authenticate", "failing").Return(&models.User{}, errors.New("dummy"))
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated, ok := services.UserFromContext(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, user, authenticated)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	handler := NewAuthHandler(service, logger).Authenticate(next)
+
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		header string
+		code   int
+	}{
+		{"success", "/api/v1/boards", "", "Bearer valid", http.StatusNoContent},
+		{"no_header", "/api/v1/boards", "", "", http.StatusUnauthorized},
+		{"wrong_scheme", "/api/v1/boards", "", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"invalid_token", "/api/v1/boards", "", "Bearer invalid", http.StatusUnauthorized},
+		{"service_error", "/api/v1/boards", "", "Bearer failing", http.StatusInternalServerError},
+		{"stream_query_token", "/api/v1/boards/1/events?access_token=valid", "text/event-stream", "", http.StatusNoContent},
+		{"query_token", "/api/v1/boards?access_token=valid", "application/json", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.code == http.StatusUnauthorized {
+				assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+
+	t.Run("websocket_query_token", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/ws?access_token=valid", nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+}
+
+func TestAuthHandler_Login(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+
+	service := new(AuthServiceMock)
+	service.On("Login", models.Credentials{Email: "john@example.com", Password: "password"}).Return("token", nil)
+	service.On("Login", mock.Anything).Return("", services.ErrInvalidCredentials)
+	handler := NewAuthHandler(service, logger)
+
+	t.Run("success", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"email":"john@example.com","password":"password"}`)
+		recorder := httptest.NewRecorder()
+		handler.Login(recorder, httptest.NewRequest("POST", "/api/v1/auth/login", body))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.JSONEq(t, `{"token":"token","token_type":"Bearer"}`, recorder.Body.String())
+	})
+	t.Run("invalid_credentials", func(t *testing.T) {
+		body := bytes.NewBufferString(`{"email":"john@example.com","password":"dummy"}`)
+		recorder := httptest.NewRecorder()
+		handler.Login(recorder, httptest.NewRequest("POST", "/api/v1/auth/login", body))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+}
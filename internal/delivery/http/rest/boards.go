@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // BoardHandler provides a Rest API http handlers for work with boards
@@ -44,8 +45,9 @@ func (h BoardHandler) Create(w http.ResponseWriter, r *http.Request) {
 		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
 		return
 	}
+	board.CreatedBy = authorID(r)
 
-	newBoard, err := h.service.Create(&board)
+	newBoard, err := h.service.Create(r.Context(), &board)
 	switch {
 	case err == nil:
 		url, err := h.router.GetURL("get_board", "id", strconv.Itoa(int(newBoard.ID)))
@@ -53,10 +55,17 @@ func (h BoardHandler) Create(w http.ResponseWriter, r *http.Request) {
 			h.log.Errorf("unable to build URL: %v", err)
 		}
 		w.Header().Set("Location", url.Path)
+		setETag(w, newBoard.Version)
 		h.resp.respondJSON(w, http.StatusCreated, newBoard)
 	case errors.Is(err, services.ErrRecordAlreadyExist):
 		h.log.Debugf("constraints error: %v", err)
 		h.resp.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrTemplateRelation):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
 	default:
 		if _, ok := err.(*v.Errors); ok {
 			h.log.Debug("resource was not created", err)
@@ -68,6 +77,57 @@ func (h BoardHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Clone will call copying of the requested resource into a new board
+func (h BoardHandler) Clone(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
+		return
+	}
+
+	var clone models.BoardClone
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if len(reqBody) > 0 {
+		if err := json.Unmarshal(reqBody, &clone); err != nil {
+			h.log.Debugf("error on request body parsing: %v", err)
+			h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+			return
+		}
+	}
+
+	newBoard, err := h.service.Clone(r.Context(), ID, clone)
+	switch {
+	case err == nil:
+		url, err := h.router.GetURL("get_board", "id", strconv.Itoa(int(newBoard.ID)))
+		if err != nil {
+			h.log.Errorf("unable to build URL: %v", err)
+		}
+		w.Header().Set("Location", url.Path)
+		setETag(w, newBoard.Version)
+		h.resp.respondJSON(w, http.StatusCreated, newBoard)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("resource was not cloned: %v", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("resource was not cloned: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
 // GetOneById will respond with the requested resource or an error
 func (h BoardHandler) GetOneById(w http.ResponseWriter, r *http.Request) {
 	ID, err := h.router.GetIDVar(r)
@@ -77,29 +137,50 @@ func (h BoardHandler) GetOneById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	board, err := h.service.FindOneById(ID)
+	board, err := h.service.FindOneById(r.Context(), ID)
 	if err != nil {
 		if err == services.ErrRecordNotFound {
 			h.resp.respondError(w, http.StatusNotFound, "resource was not found")
 			return
 		}
+		if err == services.ErrForbidden {
+			h.resp.respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
 		return
 	}
 
-	w.Header().Set("Last-Modified", board.UpdatedAt.Format(http.TimeFormat))
+	if notModified(w, r, entityTag(board.Version), board.UpdatedAt) {
+		h.resp.respond(w, http.StatusNotModified, "")
+		return
+	}
 	h.resp.respondJSON(w, http.StatusOK, board)
 }
 
 // Get will respond with the requested resources or an error
 func (h BoardHandler) Get(w http.ResponseWriter, r *http.Request) {
-	boards, err := h.service.Find()
+	demand, page := make(services.BoardDemand), services.Page{}
+	err := parseFilter(r, demand, &page)
+	if err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidFilterParams)
+		return
+	}
+
+	boards, next, err := h.service.Find(r.Context(), demand, page)
 	if err != nil {
 		h.log.Errorf("error while getting records: %v", err)
 		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
 		return
 	}
 
+	setNextPageLink(w, r, next)
+	tag := collectionTag(len(boards), func(i int) models.Model { return boards[i].Model })
+	if notModified(w, r, tag, time.Time{}) {
+		h.resp.respond(w, http.StatusNotModified, "")
+		return
+	}
 	h.resp.respondJSON(w, http.StatusOK, boards)
 }
 
@@ -112,6 +193,13 @@ func (h BoardHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
 	var board models.Board
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -125,14 +213,57 @@ func (h BoardHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	board.ID = ID
-	updatedBoard, err := h.service.Update(&board)
+	board.ID, board.Version = ID, version
+	updatedBoard, err := h.service.Update(r.Context(), &board)
+	h.respondUpdated(w, ID, updatedBoard, err)
+}
+
+// Patch will trigger patch of the provided resource with the document of the request
+func (h BoardHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
+	patch, ok := h.resp.readPatch(w, r)
+	if !ok {
+		return
+	}
+
+	board, err := h.service.Patch(r.Context(), ID, patch, version)
+	h.respondUpdated(w, ID, board, err)
+}
+
+// respondUpdated makes the response on the update of the board with the provided ID
+func (h BoardHandler) respondUpdated(w http.ResponseWriter, ID uint, board *models.Board, err error) {
 	switch err {
 	case nil:
-		h.resp.respondJSON(w, http.StatusOK, updatedBoard)
+		setETag(w, board.Version)
+		h.resp.respondJSON(w, http.StatusOK, board)
 	case services.ErrRecordNotFound:
 		h.log.Debugf("resource was not found %d", ID)
 		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case services.ErrForbidden:
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case services.ErrVersionMismatch:
+		h.log.Debugf("precondition failed: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, err.Error())
+	case services.ErrInvalidPatch:
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case services.ErrPatchTestFailed:
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
 	default:
 		if _, ok := err.(*v.Errors); ok {
 			h.log.Debugf("resource was not updated: %v", err)
@@ -153,10 +284,28 @@ func (h BoardHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.service.Delete(ID); err != nil {
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
+	err = h.service.Delete(r.Context(), ID, version)
+	switch err {
+	case nil:
+		h.resp.respond(w, http.StatusNoContent, "")
+	case services.ErrRecordNotFound:
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case services.ErrForbidden:
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case services.ErrVersionMismatch:
+		h.log.Debugf("precondition failed: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, err.Error())
+	default:
 		h.log.Errorf("error while deleting a record: %v", err)
 		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
 	}
-
-	h.resp.respond(w, http.StatusNoContent, "")
 }
@@ -3,11 +3,15 @@
 package rest
 
 import (
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -27,6 +31,7 @@ func TestGetIDVarError_Boards(t *testing.T) {
 		{name: "GetOneById", method: boardHandler.GetOneById},
 		{name: "Update", method: boardHandler.Update},
 		{name: "Delete", method: boardHandler.Delete},
+		{name: "Clone", method: boardHandler.Clone},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -38,3 +43,122 @@ func TestGetIDVarError_Boards(t *testing.T) {
 		})
 	}
 }
+
+func TestBoardHandler_CreateFromTemplate(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusCreated},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"not_a_template", services.ErrTemplateRelation, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := &models.Board{Model: models.Model{ID: 2}, Name: "dummy", TemplateID: 1}
+			req := httptest.NewRequest("POST", "/api/v1/board", strings.NewReader(`{"name":"dummy","template_id":1}`))
+			router := new(RouteAwareMock)
+			router.On("GetURL", "get_board", []string{"id", "2"}).Return(&url.URL{Path: "/api/v1/boards/2"}, nil)
+			service := new(BoardServiceMock)
+			service.On("Create", req.Context(), &models.Board{Name: "dummy", TemplateID: 1}).Return(board, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewBoardHandler(service, logger, router).Create(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestBoardHandler_Clone(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name  string
+		body  string
+		clone models.BoardClone
+		err   error
+		code  int
+	}{
+		{"success", `{"name":"copy","include":"tasks"}`, models.BoardClone{Name: "copy", Include: "tasks"}, nil, http.StatusCreated},
+		{"empty_body", ``, models.BoardClone{}, nil, http.StatusCreated},
+		{"not_found", `{}`, models.BoardClone{}, services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", `{}`, models.BoardClone{}, services.ErrForbidden, http.StatusForbidden},
+		{"internal", `{}`, models.BoardClone{}, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := &models.Board{Model: models.Model{ID: 2}, Name: "copy"}
+			req := httptest.NewRequest("POST", "/api/v1/boards/1/clone", strings.NewReader(tt.body))
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			router.On("GetURL", "get_board", []string{"id", "2"}).Return(&url.URL{Path: "/api/v1/boards/2"}, nil)
+			service := new(BoardServiceMock)
+			service.On("Clone", req.Context(), uint(1), tt.clone).Return(board, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewBoardHandler(service, logger, router).Clone(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.err == nil {
+				assert.Equal(t, "/api/v1/boards/2", recorder.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestBoardHandler_Delete(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		version uint
+		err     error
+		code    int
+	}{
+		{"success", "", 0, nil, http.StatusNoContent},
+		{"any_version", "*", 0, nil, http.StatusNoContent},
+		{"matching_version", `"4"`, 4, nil, http.StatusNoContent},
+		{"version_mismatch", `"3"`, 3, services.ErrVersionMismatch, http.StatusPreconditionFailed},
+		{"not_found", "", 0, services.ErrRecordNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/v1/boards/1", nil)
+			req.Header.Set("If-Match", tt.ifMatch)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(BoardServiceMock)
+			service.On("Delete", req.Context(), uint(1), tt.version).Return(tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewBoardHandler(service, logger, router).Delete(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+
+	t.Run("weak_if_match", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/api/v1/boards/1", nil)
+		req.Header.Set("If-Match", `W/"4"`)
+		router := new(RouteAwareMock)
+		router.On("GetIDVar", req).Return(uint(1), nil)
+		service := new(BoardServiceMock)
+
+		recorder := httptest.NewRecorder()
+		NewBoardHandler(service, logger, router).Delete(recorder, req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+		service.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // ColumnHandler provides a Rest API http handlers for work with columns
@@ -45,7 +46,7 @@ func (h ColumnHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newColumn, err := h.service.Create(&column)
+	newColumn, err := h.service.Create(r.Context(), &column)
 	switch {
 	case err == nil:
 		url, err := h.router.GetURL("get_column", "id", strconv.Itoa(int(newColumn.ID)))
@@ -53,6 +54,7 @@ func (h ColumnHandler) Create(w http.ResponseWriter, r *http.Request) {
 			h.log.Errorf("unable to build URL: %v", err)
 		}
 		w.Header().Set("Location", url.Path)
+		setETag(w, newColumn.Version)
 		h.resp.respondJSON(w, http.StatusCreated, newColumn)
 	case errors.Is(err, services.ErrBoardRelation):
 		h.log.Debugf("constraints error: %v", err)
@@ -62,6 +64,9 @@ func (h ColumnHandler) Create(w http.ResponseWriter, r *http.Request) {
 		errors.Is(err, services.ErrNameDuplicate):
 		h.log.Debugf("constraints error: %v", err)
 		h.resp.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
 	default:
 		if _, ok := err.(*v.Errors); ok {
 			h.log.Debug("resource was not created", err)
@@ -82,37 +87,50 @@ func (h ColumnHandler) GetOneById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	column, err := h.service.FindOneById(ID)
+	column, err := h.service.FindOneById(r.Context(), ID)
 	if err != nil {
 		if err == services.ErrRecordNotFound {
 			h.resp.respondError(w, http.StatusNotFound, "resource was not found")
 			return
 		}
+		if err == services.ErrForbidden {
+			h.resp.respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
 		return
 	}
 
-	w.Header().Set("Last-Modified", column.UpdatedAt.Format(http.TimeFormat))
+	if notModified(w, r, entityTag(column.Version), column.UpdatedAt) {
+		h.resp.respond(w, http.StatusNotModified, "")
+		return
+	}
 	h.resp.respondJSON(w, http.StatusOK, column)
 }
 
 // Get will respond with the requested resources or an error
 func (h ColumnHandler) Get(w http.ResponseWriter, r *http.Request) {
-	demand := make(services.ColumnDemand)
-	err := parseFilter(r, &demand)
+	demand, page := make(services.ColumnDemand), services.Page{}
+	err := parseFilter(r, &demand, &page)
 	if err != nil {
 		h.log.Debug(err)
 		h.resp.respondError(w, http.StatusBadRequest, "invalid filter params")
 		return
 	}
 
-	boards, err := h.service.Find(demand)
+	boards, next, err := h.service.Find(r.Context(), demand, page)
 	if err != nil {
 		h.log.Errorf("error while getting records: %v", err)
 		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
 		return
 	}
 
+	setNextPageLink(w, r, next)
+	tag := collectionTag(len(boards), func(i int) models.Model { return boards[i].Model })
+	if notModified(w, r, tag, time.Time{}) {
+		h.resp.respond(w, http.StatusNotModified, "")
+		return
+	}
 	h.resp.respondJSON(w, http.StatusOK, boards)
 }
 
@@ -125,6 +143,13 @@ func (h ColumnHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
 	var column models.Column
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -138,18 +163,61 @@ func (h ColumnHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	column.ID = ID
-	updatedBoard, err := h.service.Update(&column)
+	column.ID, column.Version = ID, version
+	updatedBoard, err := h.service.Update(r.Context(), &column)
+	h.respondUpdated(w, ID, updatedBoard, err)
+}
+
+// Patch will trigger patch of the provided resource with the document of the request
+func (h ColumnHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
+	patch, ok := h.resp.readPatch(w, r)
+	if !ok {
+		return
+	}
+
+	column, err := h.service.Patch(r.Context(), ID, patch, version)
+	h.respondUpdated(w, ID, column, err)
+}
+
+// respondUpdated makes the response on the update of the column with the provided ID
+func (h ColumnHandler) respondUpdated(w http.ResponseWriter, ID uint, column *models.Column, err error) {
 	switch {
 	case err == nil:
-		h.resp.respondJSON(w, http.StatusOK, updatedBoard)
+		setETag(w, column.Version)
+		h.resp.respondJSON(w, http.StatusOK, column)
 	case errors.Is(err, services.ErrRecordNotFound):
 		h.log.Debugf("resource was not found %d", ID)
 		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
 	case errors.Is(err, services.ErrPositionDuplicate),
 		errors.Is(err, services.ErrNameDuplicate):
 		h.log.Debugf("constraints error: %v", err)
 		h.resp.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrVersionMismatch):
+		h.log.Debugf("precondition failed: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, services.ErrInvalidPatch):
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPatchTestFailed):
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
 	default:
 		if _, ok := err.(*v.Errors); ok {
 			h.log.Debugf("resource was not updated: %v", err)
@@ -161,6 +229,107 @@ func (h ColumnHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Move will move the column to the requested place of its board and respond
+// with the moved column
+func (h ColumnHandler) Move(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var move models.ColumnMove
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if err := json.Unmarshal(reqBody, &move); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return
+	}
+
+	movedColumn, err := h.service.Move(r.Context(), ID, move)
+	switch {
+	case err == nil:
+		setETag(w, movedColumn.Version)
+		h.resp.respondJSON(w, http.StatusOK, movedColumn)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrColumnRelation):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPositionDuplicate):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("resource was not moved: %v", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("resource was not moved: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// Reorder will rewrite the positions of the columns of the board following the
+// provided order and respond with the columns in the new order
+func (h ColumnHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	boardID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var order models.ColumnOrder
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if err := json.Unmarshal(reqBody, &order); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return
+	}
+
+	columns, err := h.service.Reorder(r.Context(), boardID, order)
+	switch {
+	case err == nil:
+		h.resp.respondJSON(w, http.StatusOK, columns)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", boardID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrColumnOrder):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPositionDuplicate):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("columns were not reordered: %v", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("columns were not reordered: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
 // Delete will trigger deletion of the provided resource
 func (h ColumnHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ID, err := h.router.GetIDVar(r)
@@ -170,13 +339,29 @@ func (h ColumnHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.service.Delete(ID)
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
+	err = h.service.Delete(r.Context(), ID, version)
 	switch err {
 	case nil:
 		h.resp.respond(w, http.StatusNoContent, "")
+	case services.ErrRecordNotFound:
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case services.ErrForbidden:
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
 	case services.ErrLastColumn:
 		h.log.Debugf("resource was not found %d", ID)
 		h.resp.respondError(w, http.StatusBadRequest, "the last column on the board can not be deleted")
+	case services.ErrVersionMismatch:
+		h.log.Debugf("precondition failed: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, err.Error())
 	default:
 		h.log.Errorf("error while deleting a record: %v", err)
 		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
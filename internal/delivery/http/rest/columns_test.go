@@ -3,11 +3,14 @@
 package rest
 
 import (
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -26,6 +29,8 @@ func TestGetIDVarError_Columns(t *testing.T) {
 	}{
 		{name: "GetOneById", method: boardHandler.GetOneById},
 		{name: "Update", method: boardHandler.Update},
+		{name: "Move", method: boardHandler.Move},
+		{name: "Reorder", method: boardHandler.Reorder},
 		{name: "Delete", method: boardHandler.Delete},
 	}
 	for _, test := range tests {
@@ -38,3 +43,72 @@ func TestGetIDVarError_Columns(t *testing.T) {
 		})
 	}
 }
+
+func TestColumnHandler_Move(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"foreign_column", services.ErrColumnRelation, http.StatusBadRequest},
+		{"position_taken", services.ErrPositionDuplicate, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column := &models.Column{Model: models.Model{ID: 1, Version: 2}, Name: "dummy", BoardID: 1, Position: 500}
+			req := httptest.NewRequest("POST", "/api/v1/columns/1/move", strings.NewReader(`{"before":2}`))
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(ColumnServiceMock)
+			service.On("Move", req.Context(), uint(1), models.ColumnMove{BeforeID: 2}).Return(column, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewColumnHandler(service, logger, router).Move(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.err == nil {
+				assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestColumnHandler_Reorder(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"incomplete_order", services.ErrColumnOrder, http.StatusBadRequest},
+		{"internal", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns := []*models.Column{{Model: models.Model{ID: 2}}, {Model: models.Model{ID: 1}}}
+			req := httptest.NewRequest("PUT", "/api/v1/boards/1/columns/order", strings.NewReader(`{"columns":[2,1]}`))
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(ColumnServiceMock)
+			service.On("Reorder", req.Context(), uint(1), models.ColumnOrder{ColumnIDs: []uint{2, 1}}).Return(columns, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewColumnHandler(service, logger, router).Reorder(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/dnozdrin/detask/internal/domain/models"
 	"github.com/dnozdrin/detask/internal/domain/services"
@@ -45,8 +46,9 @@ func (h CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
 		return
 	}
+	comment.CreatedBy = authorID(r)
 
-	newComment, err := h.service.Create(&comment)
+	newComment, err := h.service.Create(r.Context(), &comment)
 	switch {
 	case err == nil:
 		url, err := h.router.GetURL("get_comment", "id", strconv.Itoa(int(newComment.ID)))
@@ -54,6 +56,7 @@ func (h CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
 			h.log.Errorf("unable to build URL: %v", err)
 		}
 		w.Header().Set("Location", url.Path)
+		setETag(w, newComment.Version)
 		h.resp.respondJSON(w, http.StatusCreated, newComment)
 	case errors.Is(err, services.ErrTaskRelation):
 		h.log.Debugf("constraints error: %v", err)
@@ -61,6 +64,9 @@ func (h CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
 	case errors.Is(err, services.ErrRecordAlreadyExist):
 		h.log.Debugf("constraints error: %v", err)
 		h.resp.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
 	default:
 		if _, ok := err.(*v.Errors); ok {
 			h.log.Debug("resource was not created", err)
@@ -81,37 +87,50 @@ func (h CommentHandler) GetOneById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comment, err := h.service.FindOneById(ID)
+	comment, err := h.service.FindOneById(r.Context(), ID)
 	if err != nil {
 		if err == services.ErrRecordNotFound {
 			h.resp.respondError(w, http.StatusNotFound, "resource was not found")
 			return
 		}
+		if err == services.ErrForbidden {
+			h.resp.respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
 		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
 		return
 	}
 
-	w.Header().Set("Last-Modified", comment.UpdatedAt.Format(http.TimeFormat))
+	if notModified(w, r, entityTag(comment.Version), comment.UpdatedAt) {
+		h.resp.respond(w, http.StatusNotModified, "")
+		return
+	}
 	h.resp.respondJSON(w, http.StatusOK, comment)
 }
 
 // Get will respond with the requested resources or an error
 func (h CommentHandler) Get(w http.ResponseWriter, r *http.Request) {
-	demand := make(services.CommentDemand)
-	err := parseFilter(r, demand)
+	demand, page := make(services.CommentDemand), services.Page{}
+	err := parseFilter(r, demand, &page)
 	if err != nil {
 		h.log.Debug(err)
 		h.resp.respondError(w, http.StatusBadRequest, "invalid filter params")
 		return
 	}
 
-	boards, err := h.service.Find(demand)
+	boards, next, err := h.service.Find(r.Context(), demand, page)
 	if err != nil {
 		h.log.Errorf("error while getting records: %v", err)
 		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
 		return
 	}
 
+	setNextPageLink(w, r, next)
+	tag := collectionTag(len(boards), func(i int) models.Model { return boards[i].Model })
+	if notModified(w, r, tag, time.Time{}) {
+		h.resp.respond(w, http.StatusNotModified, "")
+		return
+	}
 	h.resp.respondJSON(w, http.StatusOK, boards)
 }
 
@@ -124,6 +143,13 @@ func (h CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
 	var comment models.Comment
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -137,14 +163,57 @@ func (h CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comment.ID = ID
-	updatedBoard, err := h.service.Update(&comment)
+	comment.ID, comment.Version = ID, version
+	updatedBoard, err := h.service.Update(r.Context(), &comment)
+	h.respondUpdated(w, ID, updatedBoard, err)
+}
+
+// Patch will trigger patch of the provided resource with the document of the request
+func (h CommentHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
+	patch, ok := h.resp.readPatch(w, r)
+	if !ok {
+		return
+	}
+
+	comment, err := h.service.Patch(r.Context(), ID, patch, version)
+	h.respondUpdated(w, ID, comment, err)
+}
+
+// respondUpdated makes the response on the update of the comment with the provided ID
+func (h CommentHandler) respondUpdated(w http.ResponseWriter, ID uint, comment *models.Comment, err error) {
 	switch {
 	case err == nil:
-		h.resp.respondJSON(w, http.StatusOK, updatedBoard)
+		setETag(w, comment.Version)
+		h.resp.respondJSON(w, http.StatusOK, comment)
 	case errors.Is(err, services.ErrRecordNotFound):
 		h.log.Debugf("resource was not found %d", ID)
 		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrVersionMismatch):
+		h.log.Debugf("precondition failed: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, services.ErrInvalidPatch):
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPatchTestFailed):
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
 	default:
 		if _, ok := err.(*v.Errors); ok {
 			h.log.Debugf("resource was not updated: %v", err)
@@ -165,11 +234,28 @@ func (h CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.service.Delete(ID); err != nil {
-		h.log.Errorf("error while deleting a record: %v", err)
-		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
 		return
 	}
 
-	h.resp.respond(w, http.StatusNoContent, "")
+	err = h.service.Delete(r.Context(), ID, version)
+	switch err {
+	case nil:
+		h.resp.respond(w, http.StatusNoContent, "")
+	case services.ErrRecordNotFound:
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case services.ErrForbidden:
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case services.ErrVersionMismatch:
+		h.log.Debugf("precondition failed: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, err.Error())
+	default:
+		h.log.Errorf("error while deleting a record: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+	}
 }
@@ -1,7 +1,11 @@
 package rest
 
 const (
-	errInvalidJSON         = "invalid JSON payload"
-	errInvalidFilterParams = "invalid filter parameters"
-	errInternalServer      = "internal server error"
+	errInvalidJSON            = "invalid JSON payload"
+	errInvalidFilterParams    = "invalid filter parameters"
+	errInternalServer         = "internal server error"
+	errIfMatchFailed          = "no version of the record matches the If-Match header"
+	errUnsupportedPatch       = "unsupported patch media type"
+	errInvalidLastEventID     = "invalid Last-Event-ID header"
+	errEventStreamNotAccepted = "the request must accept text/event-stream"
 )
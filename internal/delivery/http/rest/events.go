@@ -0,0 +1,126 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// eventStreamType is the media type of the Server-Sent Events streams
+const eventStreamType = "text/event-stream"
+
+// heartbeatInterval is the longest period an event stream stays silent, the
+// heartbeats keep the idle streams open through the proxies
+const heartbeatInterval = 15 * time.Second
+
+// EventHandler provides a Rest API http handlers for streaming the board events
+type EventHandler struct {
+	service   EventService
+	log       log.Logger
+	router    routeAware
+	resp      *responder
+	heartbeat time.Duration
+}
+
+// NewEventHandler is an EventHandler constructor
+func NewEventHandler(service EventService, logger log.Logger, router routeAware) *EventHandler {
+	return &EventHandler{
+		service:   service,
+		log:       logger,
+		router:    router,
+		resp:      &responder{log: logger},
+		heartbeat: heartbeatInterval,
+	}
+}
+
+// Stream will stream the events of the requested board as Server-Sent Events till
+// the client disconnects. The stream resumes after the event with the ID provided
+// in the Last-Event-ID header. The request must accept the event stream, as only
+// such requests are exempt from the write timeout of the server
+func (h EventHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if !isEventStream(r) {
+		h.resp.respondError(w, http.StatusNotAcceptable, errEventStreamNotAccepted)
+		return
+	}
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
+		return
+	}
+	var lastID uint64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		if lastID, err = strconv.ParseUint(header, 10, 0); err != nil {
+			h.log.Debugf("invalid Last-Event-ID header: %v", err)
+			h.resp.respondError(w, http.StatusBadRequest, errInvalidLastEventID)
+			return
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.log.Error("event stream: the response writer does not support flushing")
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		return
+	}
+
+	events, err := h.service.Subscribe(r.Context(), ID, uint(lastID))
+	switch {
+	case err == nil:
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+		return
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+		return
+	default:
+		h.log.Errorf("error while subscribing to events: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		return
+	}
+
+	w.Header().Set("Content-Type", eventStreamType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.log.Errorf("event stream: error while encoding the event: %v", err)
+				return
+			}
+			if _, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				h.log.Debugf("event stream: write error: %v", err)
+				return
+			}
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				h.log.Debugf("event stream: write error: %v", err)
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// isEventStream reports if the request accepts an event stream
+func isEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), eventStreamType)
+}
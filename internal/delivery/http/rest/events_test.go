@@ -0,0 +1,93 @@
+// +build unit
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEventHandler_Stream(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name        string
+		accept      string
+		lastEventID string
+		lastID      uint
+		err         error
+		code        int
+	}{
+		{"success", "text/event-stream", "", 0, nil, http.StatusOK},
+		{"resumed", "text/event-stream", "41", 41, nil, http.StatusOK},
+		{"invalid_last_event_id", "text/event-stream", "first", 0, nil, http.StatusBadRequest},
+		{"not_found", "text/event-stream", "", 0, services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", "text/event-stream", "", 0, services.ErrForbidden, http.StatusForbidden},
+		{"internal", "text/event-stream", "", 0, errors.New("dummy"), http.StatusInternalServerError},
+		{"not_acceptable", "application/json", "", 0, nil, http.StatusNotAcceptable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/boards/2/events", nil)
+			req.Header.Set("Accept", tt.accept)
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(2), nil)
+			events := make(chan models.Event, 1)
+			events <- models.Event{ID: 42, Type: "task.moved", BoardID: 2, Data: []byte(`{"id":7}`)}
+			close(events)
+			service := new(EventServiceMock)
+			service.On("Subscribe", req.Context(), uint(2), tt.lastID).Return((<-chan models.Event)(events), tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewEventHandler(service, logger, router).Stream(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.code == http.StatusNotAcceptable {
+				service.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.code != http.StatusOK {
+				return
+			}
+			assert.Equal(t, "text/event-stream", recorder.Header().Get("Content-Type"))
+			assert.Equal(t, "no-cache", recorder.Header().Get("Cache-Control"))
+			assert.Contains(t, recorder.Body.String(), "id: 42\nevent: task.moved\ndata: {\"id\":42,")
+			assert.Contains(t, recorder.Body.String(), `"data":{"id":7}`)
+		})
+	}
+
+	t.Run("heartbeat", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/boards/2/events", nil)
+		req.Header.Set("Accept", "text/event-stream")
+		router := new(RouteAwareMock)
+		router.On("GetIDVar", req).Return(uint(2), nil)
+		events := make(chan models.Event)
+		service := new(EventServiceMock)
+		service.On("Subscribe", req.Context(), uint(2), uint(0)).Return((<-chan models.Event)(events), nil)
+		handler := NewEventHandler(service, logger, router)
+		handler.heartbeat = time.Millisecond
+
+		recorder, done := httptest.NewRecorder(), make(chan struct{})
+		go func() {
+			defer close(done)
+			handler.Stream(recorder, req)
+		}()
+		time.Sleep(20 * time.Millisecond)
+		close(events)
+		<-done
+
+		assert.Contains(t, recorder.Body.String(), ": heartbeat\n\n")
+	})
+}
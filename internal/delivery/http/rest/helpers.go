@@ -2,19 +2,31 @@ package rest
 
 import (
 	"encoding/json"
+	"fmt"
 	log "github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
 	"github.com/dnozdrin/detask/internal/domain/services"
+	"hash/fnv"
+	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type responder struct {
 	log log.Logger
 }
 
-// respond makes the response with the provided payload
+// respond makes the response with the provided payload, an empty payload
+// is not written as the responses with some statuses must not have a body
 func (r responder) respond(w http.ResponseWriter, status int, payload string) {
 	w.WriteHeader(status)
+	if payload == "" {
+		return
+	}
 	if _, err := w.Write([]byte(payload)); err != nil {
 		r.log.Errorf("error while writing response: %v", err)
 	}
@@ -39,16 +51,44 @@ func (r responder) respondError(w http.ResponseWriter, code int, message string)
 	r.respondJSON(w, code, map[string]string{"error": message})
 }
 
-//parseFilter fetches filter parameter from the request query and parses
-//it into services.Demand
-func parseFilter(r *http.Request, demand services.Demand) error {
+// acceptPatch lists the media types of the patch documents accepted by the PATCH requests
+var acceptPatch = strings.Join([]string{string(services.MergePatch), string(services.JSONPatch)}, ", ")
+
+// readPatch reads the patch document of the request, which type is taken from the
+// Content-Type header. Makes the error response and reports false if the patch
+// can not be read or its type is not supported.
+func (r responder) readPatch(w http.ResponseWriter, req *http.Request) (services.Patch, bool) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	patchType := services.PatchType(mediaType)
+	if err != nil || patchType != services.MergePatch && patchType != services.JSONPatch {
+		r.log.Debugf("unsupported patch media type: %s", req.Header.Get("Content-Type"))
+		w.Header().Set("Accept-Patch", acceptPatch)
+		r.respondError(w, http.StatusUnsupportedMediaType, errUnsupportedPatch)
+		return services.Patch{}, false
+	}
+
+	document, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		r.log.Errorf("error on request body read: %v", err)
+		r.respondError(w, http.StatusBadRequest, "error on request body read")
+		return services.Patch{}, false
+	}
+
+	return services.Patch{Type: patchType, Document: document}, true
+}
+
+//parseFilter fetches filter and pagination parameters from the request query
+//and parses them into services.Demand and services.Page
+func parseFilter(r *http.Request, demand services.Demand, page *services.Page) error {
 	for k, v := range r.URL.Query() {
-		if k != "id" {
-			intVal, err := strconv.Atoi(v[0])
-			if err != nil {
+		switch k {
+		case "id":
+		case "limit", "cursor":
+			if err := page.Add(k, v[0]); err != nil {
 				return err
 			}
-			if err = demand.Add(k, uint(intVal)); err != nil {
+		default:
+			if err := demand.Add(k, v[0]); err != nil {
 				return err
 			}
 		}
@@ -56,3 +96,102 @@ func parseFilter(r *http.Request, demand services.Demand) error {
 
 	return nil
 }
+
+// unfiltered is the demand of the collections that are only paginated
+type unfiltered struct{}
+
+// Add will reject any filter constraint
+func (unfiltered) Add(string, string) error {
+	return services.ErrFilterNotAllowed
+}
+
+// setNextPageLink sets the Link header pointing to the next page of the
+// requested collection, if there is one
+func setNextPageLink(w http.ResponseWriter, r *http.Request, next *services.Cursor) {
+	if next == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	query.Set("cursor", next.Encode())
+	link := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, link.String()))
+}
+
+// authorID returns the ID of the authenticated user that makes the request
+// or zero for anonymous requests
+func authorID(r *http.Request) uint {
+	if user, ok := services.UserFromContext(r.Context()); ok {
+		return user.ID
+	}
+
+	return 0
+}
+
+// setETag sets the ETag header to the provided version of the requested record
+func setETag(w http.ResponseWriter, version uint) {
+	w.Header().Set("ETag", entityTag(version))
+}
+
+// entityTag returns the strong entity tag of the provided version of a record
+func entityTag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// collectionTag returns the weak entity tag of the collection of n records, that
+// changes with any change of the records. Collections have no modification time:
+// the latest update of the records stays the same when a record is deleted, so
+// the conditional requests of collections rely on the tag alone.
+func collectionTag(n int, record func(i int) models.Model) string {
+	hash := fnv.New64a()
+	for i := 0; i < n; i++ {
+		model := record(i)
+		_, _ = fmt.Fprintf(hash, "%d:%d,", model.ID, model.Version)
+	}
+
+	return fmt.Sprintf(`W/"%x"`, hash.Sum64())
+}
+
+// notModified sets the ETag and the Last-Modified headers to the provided validators
+// of the requested representation and reports if the client already has it according
+// to the conditional headers of the request. The If-Modified-Since header is ignored
+// if the If-None-Match header is sent or the modification time is zero.
+func notModified(w http.ResponseWriter, r *http.Request, tag string, modified time.Time) bool {
+	w.Header().Set("ETag", tag)
+	if !modified.IsZero() {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+				return true
+			}
+		}
+
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+
+	return err == nil && !modified.IsZero() && !modified.Truncate(time.Second).After(since)
+}
+
+// ifMatch returns the version of the record required by the If-Match header of
+// the request. The zero version is returned if the header is missing or any
+// version of the record matches it. An error is returned if no version can match
+// the header: the tags are compared strongly, so a weak tag never matches.
+func ifMatch(r *http.Request) (uint, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimSuffix(strings.TrimPrefix(header, `"`), `"`)
+	version, err := strconv.ParseUint(tag, 10, 32)
+	if err != nil || version == 0 || len(tag)+2 != len(header) {
+		return 0, fmt.Errorf("unmatchable If-Match header %q", header)
+	}
+
+	return uint(version), nil
+}
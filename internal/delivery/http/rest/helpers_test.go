@@ -0,0 +1,143 @@
+// +build unit
+
+package rest
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		demand, page := make(services.TaskDemand), services.Page{}
+		cursor := services.Cursor{ID: 5}
+		r := httptest.NewRequest("GET", "/api/v1/tasks?id=1&board=2&limit=10&cursor="+cursor.Encode(), nil)
+
+		assert.NoError(t, parseFilter(r, demand, &page))
+		assert.Equal(t, services.TaskDemand{"board": uint(2)}, demand)
+		assert.Equal(t, uint(10), page.Limit)
+		assert.Equal(t, cursor.ID, page.After.ID)
+	})
+	t.Run("invalid_limit", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/tasks?limit=0", nil)
+		assert.Equal(t, services.ErrInvalidLimit, parseFilter(r, make(services.TaskDemand), &services.Page{}))
+	})
+	t.Run("invalid_cursor", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/tasks?cursor=dummy", nil)
+		assert.Equal(t, services.ErrInvalidCursor, parseFilter(r, make(services.TaskDemand), &services.Page{}))
+	})
+}
+
+func TestSetNextPageLink(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/v1/tasks?board=2&limit=10", nil)
+
+	recorder := httptest.NewRecorder()
+	setNextPageLink(recorder, r, nil)
+	assert.Empty(t, recorder.Header().Get("Link"))
+
+	next := &services.Cursor{ID: 5, Position: 1000}
+	setNextPageLink(recorder, r, next)
+	assert.Equal(
+		t,
+		`</api/v1/tasks?board=2&cursor=`+next.Encode()+`&limit=10>; rel="next"`,
+		recorder.Header().Get("Link"),
+	)
+}
+
+func TestSetETag(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	setETag(recorder, 7)
+
+	assert.Equal(t, `"7"`, recorder.Header().Get("ETag"))
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		version uint
+		valid   bool
+	}{
+		{"missing", "", 0, true},
+		{"any", "*", 0, true},
+		{"version", `"12"`, 12, true},
+		{"unquoted", "12", 0, false},
+		{"weak", `W/"12"`, 0, false},
+		{"zero", `"0"`, 0, false},
+		{"not_a_number", `"abc"`, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "/api/v1/tasks/1", nil)
+			r.Header.Set("If-Match", tt.header)
+
+			version, err := ifMatch(r)
+			assert.Equal(t, tt.valid, err == nil)
+			assert.Equal(t, tt.version, version)
+		})
+	}
+}
+
+func TestCollectionTag(t *testing.T) {
+	records := []models.Model{{ID: 1, Version: 2}, {ID: 2, Version: 1}}
+	collection := func(records []models.Model) string {
+		return collectionTag(len(records), func(i int) models.Model { return records[i] })
+	}
+
+	tag := collection(records)
+	assert.Regexp(t, `^W/"[0-9a-f]+"$`, tag)
+	assert.Equal(t, tag, collection(records))
+	assert.NotEqual(t, tag, collection([]models.Model{records[0], {ID: 2, Version: 2}}))
+	assert.NotEqual(t, tag, collection(records[:1]))
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2020, 8, 1, 10, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		cached  bool
+	}{
+		{"unconditional", nil, false},
+		{"matching_tag", map[string]string{"If-None-Match": `"1", "3"`}, true},
+		{"weak_tag", map[string]string{"If-None-Match": `W/"3"`}, true},
+		{"any_tag", map[string]string{"If-None-Match": "*"}, true},
+		{"other_tag", map[string]string{"If-None-Match": `"2"`}, false},
+		{"not_modified_since", map[string]string{"If-Modified-Since": "Sat, 01 Aug 2020 10:00:00 GMT"}, true},
+		{"modified_since", map[string]string{"If-Modified-Since": "Sat, 01 Aug 2020 09:59:59 GMT"}, false},
+		{"invalid_date", map[string]string{"If-Modified-Since": "dummy"}, false},
+		{
+			"tag_takes_precedence",
+			map[string]string{"If-None-Match": `"2"`, "If-Modified-Since": "Sat, 01 Aug 2020 10:00:00 GMT"},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/v1/tasks/1", nil)
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+
+			recorder := httptest.NewRecorder()
+			assert.Equal(t, tt.cached, notModified(recorder, r, `"3"`, modified))
+			assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
+			assert.Equal(t, "Sat, 01 Aug 2020 10:00:00 GMT", recorder.Header().Get("Last-Modified"))
+		})
+	}
+
+	t.Run("unknown_modification", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/tasks", nil)
+		r.Header.Set("If-Modified-Since", "Sat, 01 Aug 2020 10:00:00 GMT")
+
+		recorder := httptest.NewRecorder()
+		assert.False(t, notModified(recorder, r, `W/"0"`, time.Time{}))
+		assert.Empty(t, recorder.Header().Get("Last-Modified"))
+	})
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	m "github.com/dnozdrin/detask/internal/domain/models"
 	"github.com/dnozdrin/detask/internal/domain/services"
 	"net/http"
@@ -10,40 +11,121 @@ import (
 type routeAware interface {
 	GetURL(name string, params ...string) (*url.URL, error)
 	GetIDVar(r *http.Request) (uint, error)
+	GetUintVar(r *http.Request, name string) (uint, error)
 }
 
 // BoardService provides an interface for work board service layer
 type BoardService interface {
-	Create(board *m.Board) (*m.Board, error)
-	Find() ([]*m.Board, error)
-	FindOneById(ID uint) (*m.Board, error)
-	Update(board *m.Board) (*m.Board, error)
-	Delete(ID uint) error
+	Create(ctx context.Context, board *m.Board) (*m.Board, error)
+	Clone(ctx context.Context, ID uint, clone m.BoardClone) (*m.Board, error)
+	Find(ctx context.Context, demand services.BoardDemand, page services.Page) ([]*m.Board, *services.Cursor, error)
+	FindOneById(ctx context.Context, ID uint) (*m.Board, error)
+	Update(ctx context.Context, board *m.Board) (*m.Board, error)
+	Patch(ctx context.Context, ID uint, patch services.Patch, version uint) (*m.Board, error)
+	Delete(ctx context.Context, ID, version uint) error
 }
 
 // ColumnService provides an interface for work column service layer
 type ColumnService interface {
-	Create(board *m.Column) (*m.Column, error)
-	Find(demand services.ColumnDemand) ([]*m.Column, error)
-	FindOneById(ID uint) (*m.Column, error)
-	Update(board *m.Column) (*m.Column, error)
-	Delete(ID uint) error
+	Create(ctx context.Context, board *m.Column) (*m.Column, error)
+	Find(ctx context.Context, demand services.ColumnDemand, page services.Page) ([]*m.Column, *services.Cursor, error)
+	FindOneById(ctx context.Context, ID uint) (*m.Column, error)
+	Update(ctx context.Context, board *m.Column) (*m.Column, error)
+	Patch(ctx context.Context, ID uint, patch services.Patch, version uint) (*m.Column, error)
+	Move(ctx context.Context, ID uint, move m.ColumnMove) (*m.Column, error)
+	Reorder(ctx context.Context, boardID uint, order m.ColumnOrder) ([]*m.Column, error)
+	Delete(ctx context.Context, ID, version uint) error
 }
 
 // TaskService provides an interface for work task service layer
 type TaskService interface {
-	Create(board *m.Task) (*m.Task, error)
-	Find(demand services.TaskDemand) ([]*m.Task, error)
-	FindOneById(ID uint) (*m.Task, error)
-	Update(board *m.Task) (*m.Task, error)
-	Delete(ID uint) error
+	Create(ctx context.Context, board *m.Task) (*m.Task, error)
+	Find(ctx context.Context, demand services.TaskDemand, page services.Page) ([]*m.Task, *services.Cursor, error)
+	FindAssigned(ctx context.Context, demand services.TaskDemand, page services.Page) ([]*m.Task, *services.Cursor, error)
+	FindOneById(ctx context.Context, ID uint) (*m.Task, error)
+	Update(ctx context.Context, board *m.Task) (*m.Task, error)
+	Patch(ctx context.Context, ID uint, patch services.Patch, version uint) (*m.Task, error)
+	Move(ctx context.Context, ID uint, move m.TaskMove) (*m.Task, error)
+	Transfer(ctx context.Context, ID uint, transfer m.TaskTransfer) (*m.Task, error)
+	Delete(ctx context.Context, ID, version uint) error
 }
 
 // CommentService provides an interface for work comment service layer
 type CommentService interface {
-	Create(board *m.Comment) (*m.Comment, error)
-	Find(demand services.CommentDemand) ([]*m.Comment, error)
-	FindOneById(ID uint) (*m.Comment, error)
-	Update(board *m.Comment) (*m.Comment, error)
-	Delete(ID uint) error
+	Create(ctx context.Context, board *m.Comment) (*m.Comment, error)
+	Find(ctx context.Context, demand services.CommentDemand, page services.Page) ([]*m.Comment, *services.Cursor, error)
+	FindOneById(ctx context.Context, ID uint) (*m.Comment, error)
+	Update(ctx context.Context, board *m.Comment) (*m.Comment, error)
+	Patch(ctx context.Context, ID uint, patch services.Patch, version uint) (*m.Comment, error)
+	Delete(ctx context.Context, ID, version uint) error
+}
+
+// LabelService provides an interface for work label service layer
+type LabelService interface {
+	Create(ctx context.Context, label *m.Label) (*m.Label, error)
+	Find(ctx context.Context, demand services.LabelDemand, page services.Page) ([]*m.Label, *services.Cursor, error)
+	FindOneById(ctx context.Context, ID uint) (*m.Label, error)
+	Update(ctx context.Context, label *m.Label) (*m.Label, error)
+	Patch(ctx context.Context, ID uint, patch services.Patch) (*m.Label, error)
+	Delete(ctx context.Context, ID uint) error
+}
+
+// MemberService provides an interface for work board member service layer
+type MemberService interface {
+	Create(ctx context.Context, member *m.Member) (*m.Member, error)
+	Find(ctx context.Context, boardID uint) ([]*m.Member, error)
+	Update(ctx context.Context, member *m.Member) (*m.Member, error)
+	Delete(ctx context.Context, boardID, userID uint) error
+}
+
+// EventService provides an interface for work with the board events
+type EventService interface {
+	Subscribe(ctx context.Context, boardID, lastID uint) (<-chan m.Event, error)
+}
+
+// AuthService provides an interface for work with users authentication
+type AuthService interface {
+	Register(user *m.User) (*m.User, error)
+	Login(credentials m.Credentials) (string, error)
+	Authenticate(token string) (*m.User, error)
+}
+
+// TrashService provides an interface for work with the deleted records
+type TrashService interface {
+	Find(ctx context.Context, demand services.TrashDemand, page services.Page) ([]*m.TrashItem, *services.Cursor, error)
+	Restore(ctx context.Context, kind m.TrashType, ID uint) error
+}
+
+// ActivityService provides an interface for work with the activity history
+type ActivityService interface {
+	FindByBoard(ctx context.Context, boardID uint, demand services.ActivityDemand, page services.Page) ([]*m.Activity, *services.Cursor, error)
+	FindByTask(ctx context.Context, taskID uint, demand services.ActivityDemand, page services.Page) ([]*m.Activity, *services.Cursor, error)
+}
+
+// SearchService provides an interface for the full-text search of tasks and comments
+type SearchService interface {
+	Search(ctx context.Context, demand services.SearchDemand, page services.Page) ([]*m.SearchHit, *services.Cursor, error)
+}
+
+// WebhookService provides an interface for work with the webhooks of the boards and
+// their deliveries
+type WebhookService interface {
+	Create(ctx context.Context, webhook *m.Webhook) (*m.Webhook, error)
+	FindByBoard(ctx context.Context, boardID uint, page services.Page) ([]*m.Webhook, *services.Cursor, error)
+	FindOneById(ctx context.Context, ID uint) (*m.Webhook, error)
+	Update(ctx context.Context, webhook *m.Webhook) (*m.Webhook, error)
+	Delete(ctx context.Context, ID uint) error
+	FindDeliveries(ctx context.Context, webhookID uint, page services.Page) ([]*m.Delivery, *services.Cursor, error)
+	Redeliver(ctx context.Context, webhookID, deliveryID uint) (*m.Delivery, error)
+}
+
+// RuleService provides an interface for work with the automation rules of the boards
+// and their execution logs
+type RuleService interface {
+	Create(ctx context.Context, rule *m.Rule) (*m.Rule, error)
+	FindByBoard(ctx context.Context, boardID uint, page services.Page) ([]*m.Rule, *services.Cursor, error)
+	FindOneById(ctx context.Context, ID uint) (*m.Rule, error)
+	Update(ctx context.Context, rule *m.Rule) (*m.Rule, error)
+	Delete(ctx context.Context, ID uint) error
+	FindExecutions(ctx context.Context, ruleID uint, page services.Page) ([]*m.RuleExecution, *services.Cursor, error)
 }
@@ -0,0 +1,225 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+)
+
+// LabelHandler provides a Rest API http handlers for work with labels
+type LabelHandler struct {
+	service LabelService
+	log     log.Logger
+	router  routeAware
+	resp    *responder
+}
+
+// NewLabelHandler is a LabelHandler constructor
+func NewLabelHandler(service LabelService, logger log.Logger, router routeAware) *LabelHandler {
+	return &LabelHandler{
+		service: service,
+		log:     logger,
+		router:  router,
+		resp:    &responder{log: logger},
+	}
+}
+
+// Create will call creation of the provided resource
+func (h LabelHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var label models.Label
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if err := json.Unmarshal(reqBody, &label); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return
+	}
+
+	newLabel, err := h.service.Create(r.Context(), &label)
+	switch {
+	case err == nil:
+		url, err := h.router.GetURL("get_label", "id", strconv.Itoa(int(newLabel.ID)))
+		if err != nil {
+			h.log.Errorf("unable to build URL: %v", err)
+		}
+		w.Header().Set("Location", url.Path)
+		h.resp.respondJSON(w, http.StatusCreated, newLabel)
+	case errors.Is(err, services.ErrBoardRelation):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRecordAlreadyExist),
+		errors.Is(err, services.ErrNameDuplicate):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debug("resource was not created", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("resource was not created", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// GetOneById will respond with the requested resource or an error
+func (h LabelHandler) GetOneById(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
+		return
+	}
+
+	label, err := h.service.FindOneById(r.Context(), ID)
+	if err != nil {
+		if err == services.ErrRecordNotFound {
+			h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+			return
+		}
+		if err == services.ErrForbidden {
+			h.resp.respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
+		return
+	}
+
+	w.Header().Set("Last-Modified", label.UpdatedAt.Format(http.TimeFormat))
+	h.resp.respondJSON(w, http.StatusOK, label)
+}
+
+// Get will respond with the requested resources or an error
+func (h LabelHandler) Get(w http.ResponseWriter, r *http.Request) {
+	demand, page := make(services.LabelDemand), services.Page{}
+	err := parseFilter(r, &demand, &page)
+	if err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid filter params")
+		return
+	}
+
+	labels, next, err := h.service.Find(r.Context(), demand, page)
+	if err != nil {
+		h.log.Errorf("error while getting records: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		return
+	}
+
+	setNextPageLink(w, r, next)
+	h.resp.respondJSON(w, http.StatusOK, labels)
+}
+
+// Update will trigger update of the provided resource
+func (h LabelHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var label models.Label
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if err := json.Unmarshal(reqBody, &label); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return
+	}
+
+	label.ID = ID
+	updatedLabel, err := h.service.Update(r.Context(), &label)
+	h.respondUpdated(w, ID, updatedLabel, err)
+}
+
+// Patch will trigger patch of the provided resource with the document of the request
+func (h LabelHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	patch, ok := h.resp.readPatch(w, r)
+	if !ok {
+		return
+	}
+
+	label, err := h.service.Patch(r.Context(), ID, patch)
+	h.respondUpdated(w, ID, label, err)
+}
+
+// respondUpdated makes the response on the update of the label with the provided ID
+func (h LabelHandler) respondUpdated(w http.ResponseWriter, ID uint, label *models.Label, err error) {
+	switch {
+	case err == nil:
+		h.resp.respondJSON(w, http.StatusOK, label)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrNameDuplicate):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidPatch):
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPatchTestFailed):
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("resource was not updated: %v", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("resource was not updated: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// Delete will trigger deletion of the provided resource
+func (h LabelHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	err = h.service.Delete(r.Context(), ID)
+	switch err {
+	case nil:
+		h.resp.respond(w, http.StatusNoContent, "")
+	case services.ErrRecordNotFound:
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case services.ErrForbidden:
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	default:
+		h.log.Errorf("error while deleting a record: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+	}
+}
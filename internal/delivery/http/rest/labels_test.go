@@ -0,0 +1,81 @@
+// +build unit
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLabelHandler_Create(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusCreated},
+		{"missing_board", services.ErrBoardRelation, http.StatusBadRequest},
+		{"duplicate_name", services.ErrNameDuplicate, http.StatusConflict},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"service_error", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label := &models.Label{Name: "bug", Color: "#ff0000", BoardID: 1}
+			req := httptest.NewRequest("POST", "/api/v1/label", strings.NewReader(`{"name":"bug","color":"#ff0000","board":1}`))
+			router := new(RouteAwareMock)
+			router.On("GetURL", "get_label", []string{"id", "0"}).Return(&url.URL{Path: "/api/v1/labels/0"}, nil)
+			service := new(LabelServiceMock)
+			service.On("Create", req.Context(), label).Return(label, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewLabelHandler(service, logger, router).Create(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestLabelHandler_Delete(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusNoContent},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"service_error", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/v1/labels/1", nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(LabelServiceMock)
+			service.On("Delete", req.Context(), uint(1)).Return(tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewLabelHandler(service, logger, router).Delete(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
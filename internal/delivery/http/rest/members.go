@@ -0,0 +1,192 @@
+package rest
+
+import (
+	"encoding/json"
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"net/http"
+)
+
+// MemberHandler provides a Rest API http handlers for work with board members
+type MemberHandler struct {
+	service MemberService
+	log     log.Logger
+	router  routeAware
+	resp    *responder
+}
+
+// NewMemberHandler is a MemberHandler constructor
+func NewMemberHandler(service MemberService, logger log.Logger, router routeAware) *MemberHandler {
+	return &MemberHandler{
+		service: service,
+		log:     logger,
+		router:  router,
+		resp:    &responder{log: logger},
+	}
+}
+
+// Create will add the provided member to the board
+func (h MemberHandler) Create(w http.ResponseWriter, r *http.Request) {
+	boardID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var member models.Member
+	if !h.readMember(w, r, &member) {
+		return
+	}
+
+	member.BoardID = boardID
+	newMember, err := h.service.Create(r.Context(), &member)
+	switch {
+	case err == nil:
+		h.resp.respondJSON(w, http.StatusCreated, newMember)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", boardID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrUserRelation):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrRecordAlreadyExist):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debug("resource was not created", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("resource was not created: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// Get will respond with the members of the requested board or an error
+func (h MemberHandler) Get(w http.ResponseWriter, r *http.Request) {
+	boardID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	members, err := h.service.Find(r.Context(), boardID)
+	switch err {
+	case nil:
+		h.resp.respondJSON(w, http.StatusOK, members)
+	case services.ErrRecordNotFound:
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case services.ErrForbidden:
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	default:
+		h.log.Errorf("error while getting records: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+	}
+}
+
+// Update will change the role of the board member
+func (h MemberHandler) Update(w http.ResponseWriter, r *http.Request) {
+	boardID, userID, ok := h.memberVars(w, r)
+	if !ok {
+		return
+	}
+
+	var member models.Member
+	if !h.readMember(w, r, &member) {
+		return
+	}
+
+	member.BoardID, member.UserID = boardID, userID
+	updatedMember, err := h.service.Update(r.Context(), &member)
+	switch {
+	case err == nil:
+		h.resp.respondJSON(w, http.StatusOK, updatedMember)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("member %d of the board %d was not found", userID, boardID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrLastOwner):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("resource was not updated: %v", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("resource was not updated: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// Delete will remove the user from the board
+func (h MemberHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	boardID, userID, ok := h.memberVars(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.service.Delete(r.Context(), boardID, userID)
+	switch err {
+	case nil:
+		h.resp.respond(w, http.StatusNoContent, "")
+	case services.ErrRecordNotFound:
+		h.log.Debugf("member %d of the board %d was not found", userID, boardID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case services.ErrForbidden:
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case services.ErrLastOwner:
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.log.Errorf("error while deleting a record: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+	}
+}
+
+// memberVars will return the board and the user identifiers of the requested
+// membership or respond with an error
+func (h MemberHandler) memberVars(w http.ResponseWriter, r *http.Request) (boardID, userID uint, ok bool) {
+	boardID, err := h.router.GetIDVar(r)
+	if err == nil {
+		userID, err = h.router.GetUintVar(r, "user")
+	}
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return 0, 0, false
+	}
+
+	return boardID, userID, true
+}
+
+// readMember will decode the request body into the provided member or respond
+// with an error
+func (h MemberHandler) readMember(w http.ResponseWriter, r *http.Request, member *models.Member) bool {
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return false
+	}
+	if err := json.Unmarshal(reqBody, member); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,94 @@
+// +build unit
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMemberHandler_Get(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name    string
+		members []*models.Member
+		err     error
+		code    int
+	}{
+		{"success", []*models.Member{{BoardID: 1, UserID: 1, Role: models.RoleOwner}}, nil, http.StatusOK},
+		{"not_found", nil, services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", nil, services.ErrForbidden, http.StatusForbidden},
+		{"service_error", nil, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/boards/1/members", nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(MemberServiceMock)
+			service.On("Find", req.Context(), uint(1)).Return(tt.members, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewMemberHandler(service, logger, router).Get(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestMemberHandler_Delete(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusNoContent},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"last_owner", services.ErrLastOwner, http.StatusBadRequest},
+		{"service_error", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/v1/boards/1/members/2", nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			router.On("GetUintVar", req, "user").Return(uint(2), nil)
+			service := new(MemberServiceMock)
+			service.On("Delete", req.Context(), uint(1), uint(2)).Return(tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewMemberHandler(service, logger, router).Delete(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestMemberHandler_InvalidUserVar(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest("PUT", "/api/v1/boards/1/members/x", nil)
+	router := new(RouteAwareMock)
+	router.On("GetIDVar", req).Return(uint(1), nil)
+	router.On("GetUintVar", req, "user").Return(uint(0), errors.New("test error"))
+
+	recorder := httptest.NewRecorder()
+	NewMemberHandler(new(MemberServiceMock), logger, router).Update(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -3,6 +3,9 @@
 package rest
 
 import (
+	"context"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/url"
@@ -65,3 +68,407 @@ func (raw *RouteAwareMock) GetIDVar(r *http.Request) (uint, error) {
 	returnValues := raw.Called(r)
 	return returnValues.Get(0).(uint), returnValues.Error(1)
 }
+
+func (raw *RouteAwareMock) GetUintVar(r *http.Request, name string) (uint, error) {
+	returnValues := raw.Called(r, name)
+	return returnValues.Get(0).(uint), returnValues.Error(1)
+}
+
+type BoardServiceMock struct {
+	mock.Mock
+}
+
+func (bs *BoardServiceMock) Create(ctx context.Context, board *models.Board) (*models.Board, error) {
+	returnValues := bs.Called(ctx, board)
+	return returnValues.Get(0).(*models.Board), returnValues.Error(1)
+}
+
+func (bs *BoardServiceMock) Clone(ctx context.Context, ID uint, clone models.BoardClone) (*models.Board, error) {
+	returnValues := bs.Called(ctx, ID, clone)
+	return returnValues.Get(0).(*models.Board), returnValues.Error(1)
+}
+
+func (bs *BoardServiceMock) Find(
+	ctx context.Context,
+	demand services.BoardDemand,
+	page services.Page,
+) ([]*models.Board, *services.Cursor, error) {
+	returnValues := bs.Called(ctx, demand, page)
+	return returnValues.Get(0).([]*models.Board), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (bs *BoardServiceMock) FindOneById(ctx context.Context, ID uint) (*models.Board, error) {
+	returnValues := bs.Called(ctx, ID)
+	return returnValues.Get(0).(*models.Board), returnValues.Error(1)
+}
+
+func (bs *BoardServiceMock) Update(ctx context.Context, board *models.Board) (*models.Board, error) {
+	returnValues := bs.Called(ctx, board)
+	return returnValues.Get(0).(*models.Board), returnValues.Error(1)
+}
+
+func (bs *BoardServiceMock) Patch(
+	ctx context.Context,
+	ID uint,
+	patch services.Patch,
+	version uint,
+) (*models.Board, error) {
+	returnValues := bs.Called(ctx, ID, patch, version)
+	return returnValues.Get(0).(*models.Board), returnValues.Error(1)
+}
+
+func (bs *BoardServiceMock) Delete(ctx context.Context, ID, version uint) error {
+	return bs.Called(ctx, ID, version).Error(0)
+}
+
+type MemberServiceMock struct {
+	mock.Mock
+}
+
+func (ms *MemberServiceMock) Create(ctx context.Context, member *models.Member) (*models.Member, error) {
+	returnValues := ms.Called(ctx, member)
+	return returnValues.Get(0).(*models.Member), returnValues.Error(1)
+}
+
+func (ms *MemberServiceMock) Find(ctx context.Context, boardID uint) ([]*models.Member, error) {
+	returnValues := ms.Called(ctx, boardID)
+	return returnValues.Get(0).([]*models.Member), returnValues.Error(1)
+}
+
+func (ms *MemberServiceMock) Update(ctx context.Context, member *models.Member) (*models.Member, error) {
+	returnValues := ms.Called(ctx, member)
+	return returnValues.Get(0).(*models.Member), returnValues.Error(1)
+}
+
+func (ms *MemberServiceMock) Delete(ctx context.Context, boardID, userID uint) error {
+	return ms.Called(ctx, boardID, userID).Error(0)
+}
+
+type ColumnServiceMock struct {
+	mock.Mock
+}
+
+func (cs *ColumnServiceMock) Create(ctx context.Context, column *models.Column) (*models.Column, error) {
+	returnValues := cs.Called(ctx, column)
+	return returnValues.Get(0).(*models.Column), returnValues.Error(1)
+}
+
+func (cs *ColumnServiceMock) Find(
+	ctx context.Context,
+	demand services.ColumnDemand,
+	page services.Page,
+) ([]*models.Column, *services.Cursor, error) {
+	returnValues := cs.Called(ctx, demand, page)
+	return returnValues.Get(0).([]*models.Column), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (cs *ColumnServiceMock) FindOneById(ctx context.Context, ID uint) (*models.Column, error) {
+	returnValues := cs.Called(ctx, ID)
+	return returnValues.Get(0).(*models.Column), returnValues.Error(1)
+}
+
+func (cs *ColumnServiceMock) Update(ctx context.Context, column *models.Column) (*models.Column, error) {
+	returnValues := cs.Called(ctx, column)
+	return returnValues.Get(0).(*models.Column), returnValues.Error(1)
+}
+
+func (cs *ColumnServiceMock) Patch(
+	ctx context.Context,
+	ID uint,
+	patch services.Patch,
+	version uint,
+) (*models.Column, error) {
+	returnValues := cs.Called(ctx, ID, patch, version)
+	return returnValues.Get(0).(*models.Column), returnValues.Error(1)
+}
+
+func (cs *ColumnServiceMock) Move(ctx context.Context, ID uint, move models.ColumnMove) (*models.Column, error) {
+	returnValues := cs.Called(ctx, ID, move)
+	return returnValues.Get(0).(*models.Column), returnValues.Error(1)
+}
+
+func (cs *ColumnServiceMock) Reorder(
+	ctx context.Context,
+	boardID uint,
+	order models.ColumnOrder,
+) ([]*models.Column, error) {
+	returnValues := cs.Called(ctx, boardID, order)
+	return returnValues.Get(0).([]*models.Column), returnValues.Error(1)
+}
+
+func (cs *ColumnServiceMock) Delete(ctx context.Context, ID, version uint) error {
+	returnValues := cs.Called(ctx, ID, version)
+	return returnValues.Error(0)
+}
+
+type TaskServiceMock struct {
+	mock.Mock
+}
+
+func (ts *TaskServiceMock) Create(ctx context.Context, task *models.Task) (*models.Task, error) {
+	returnValues := ts.Called(ctx, task)
+	return returnValues.Get(0).(*models.Task), returnValues.Error(1)
+}
+
+func (ts *TaskServiceMock) Find(
+	ctx context.Context,
+	demand services.TaskDemand,
+	page services.Page,
+) ([]*models.Task, *services.Cursor, error) {
+	returnValues := ts.Called(ctx, demand, page)
+	return returnValues.Get(0).([]*models.Task), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (ts *TaskServiceMock) FindAssigned(
+	ctx context.Context,
+	demand services.TaskDemand,
+	page services.Page,
+) ([]*models.Task, *services.Cursor, error) {
+	returnValues := ts.Called(ctx, demand, page)
+	return returnValues.Get(0).([]*models.Task), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (ts *TaskServiceMock) FindOneById(ctx context.Context, ID uint) (*models.Task, error) {
+	returnValues := ts.Called(ctx, ID)
+	return returnValues.Get(0).(*models.Task), returnValues.Error(1)
+}
+
+func (ts *TaskServiceMock) Update(ctx context.Context, task *models.Task) (*models.Task, error) {
+	returnValues := ts.Called(ctx, task)
+	return returnValues.Get(0).(*models.Task), returnValues.Error(1)
+}
+
+func (ts *TaskServiceMock) Patch(
+	ctx context.Context,
+	ID uint,
+	patch services.Patch,
+	version uint,
+) (*models.Task, error) {
+	returnValues := ts.Called(ctx, ID, patch, version)
+	return returnValues.Get(0).(*models.Task), returnValues.Error(1)
+}
+
+func (ts *TaskServiceMock) Move(ctx context.Context, ID uint, move models.TaskMove) (*models.Task, error) {
+	returnValues := ts.Called(ctx, ID, move)
+	return returnValues.Get(0).(*models.Task), returnValues.Error(1)
+}
+
+func (ts *TaskServiceMock) Transfer(ctx context.Context, ID uint, transfer models.TaskTransfer) (*models.Task, error) {
+	returnValues := ts.Called(ctx, ID, transfer)
+	return returnValues.Get(0).(*models.Task), returnValues.Error(1)
+}
+
+func (ts *TaskServiceMock) Delete(ctx context.Context, ID, version uint) error {
+	returnValues := ts.Called(ctx, ID, version)
+	return returnValues.Error(0)
+}
+
+type LabelServiceMock struct {
+	mock.Mock
+}
+
+func (ls *LabelServiceMock) Create(ctx context.Context, label *models.Label) (*models.Label, error) {
+	returnValues := ls.Called(ctx, label)
+	return returnValues.Get(0).(*models.Label), returnValues.Error(1)
+}
+
+func (ls *LabelServiceMock) Find(
+	ctx context.Context,
+	demand services.LabelDemand,
+	page services.Page,
+) ([]*models.Label, *services.Cursor, error) {
+	returnValues := ls.Called(ctx, demand, page)
+	return returnValues.Get(0).([]*models.Label), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (ls *LabelServiceMock) FindOneById(ctx context.Context, ID uint) (*models.Label, error) {
+	returnValues := ls.Called(ctx, ID)
+	return returnValues.Get(0).(*models.Label), returnValues.Error(1)
+}
+
+func (ls *LabelServiceMock) Update(ctx context.Context, label *models.Label) (*models.Label, error) {
+	returnValues := ls.Called(ctx, label)
+	return returnValues.Get(0).(*models.Label), returnValues.Error(1)
+}
+
+func (ls *LabelServiceMock) Patch(ctx context.Context, ID uint, patch services.Patch) (*models.Label, error) {
+	returnValues := ls.Called(ctx, ID, patch)
+	return returnValues.Get(0).(*models.Label), returnValues.Error(1)
+}
+
+func (ls *LabelServiceMock) Delete(ctx context.Context, ID uint) error {
+	returnValues := ls.Called(ctx, ID)
+	return returnValues.Error(0)
+}
+
+type AuthServiceMock struct {
+	mock.Mock
+}
+
+func (as *AuthServiceMock) Register(user *models.User) (*models.User, error) {
+	returnValues := as.Called(user)
+	return returnValues.Get(0).(*models.User), returnValues.Error(1)
+}
+
+func (as *AuthServiceMock) Login(credentials models.Credentials) (string, error) {
+	returnValues := as.Called(credentials)
+	return returnValues.String(0), returnValues.Error(1)
+}
+
+func (as *AuthServiceMock) Authenticate(token string) (*models.User, error) {
+	returnValues := as.Called(token)
+	return returnValues.Get(0).(*models.User), returnValues.Error(1)
+}
+
+type TrashServiceMock struct {
+	mock.Mock
+}
+
+func (ts *TrashServiceMock) Find(
+	ctx context.Context,
+	demand services.TrashDemand,
+	page services.Page,
+) ([]*models.TrashItem, *services.Cursor, error) {
+	returnValues := ts.Called(ctx, demand, page)
+	return returnValues.Get(0).([]*models.TrashItem), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (ts *TrashServiceMock) Restore(ctx context.Context, kind models.TrashType, ID uint) error {
+	returnValues := ts.Called(ctx, kind, ID)
+	return returnValues.Error(0)
+}
+
+type SearchServiceMock struct {
+	mock.Mock
+}
+
+func (ss *SearchServiceMock) Search(
+	ctx context.Context,
+	demand services.SearchDemand,
+	page services.Page,
+) ([]*models.SearchHit, *services.Cursor, error) {
+	returnValues := ss.Called(ctx, demand, page)
+	return returnValues.Get(0).([]*models.SearchHit), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+type ActivityServiceMock struct {
+	mock.Mock
+}
+
+func (as *ActivityServiceMock) FindByBoard(
+	ctx context.Context,
+	boardID uint,
+	demand services.ActivityDemand,
+	page services.Page,
+) ([]*models.Activity, *services.Cursor, error) {
+	returnValues := as.Called(ctx, boardID, demand, page)
+	return returnValues.Get(0).([]*models.Activity), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (as *ActivityServiceMock) FindByTask(
+	ctx context.Context,
+	taskID uint,
+	demand services.ActivityDemand,
+	page services.Page,
+) ([]*models.Activity, *services.Cursor, error) {
+	returnValues := as.Called(ctx, taskID, demand, page)
+	return returnValues.Get(0).([]*models.Activity), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+type EventServiceMock struct {
+	mock.Mock
+}
+
+func (es *EventServiceMock) Subscribe(ctx context.Context, boardID, lastID uint) (<-chan models.Event, error) {
+	returnValues := es.Called(ctx, boardID, lastID)
+	return returnValues.Get(0).(<-chan models.Event), returnValues.Error(1)
+}
+
+type WebhookServiceMock struct {
+	mock.Mock
+}
+
+func (ws *WebhookServiceMock) Create(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	returnValues := ws.Called(ctx, webhook)
+	return returnValues.Get(0).(*models.Webhook), returnValues.Error(1)
+}
+
+func (ws *WebhookServiceMock) FindByBoard(
+	ctx context.Context,
+	boardID uint,
+	page services.Page,
+) ([]*models.Webhook, *services.Cursor, error) {
+	returnValues := ws.Called(ctx, boardID, page)
+	return returnValues.Get(0).([]*models.Webhook), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (ws *WebhookServiceMock) FindOneById(ctx context.Context, ID uint) (*models.Webhook, error) {
+	returnValues := ws.Called(ctx, ID)
+	return returnValues.Get(0).(*models.Webhook), returnValues.Error(1)
+}
+
+func (ws *WebhookServiceMock) Update(ctx context.Context, webhook *models.Webhook) (*models.Webhook, error) {
+	returnValues := ws.Called(ctx, webhook)
+	return returnValues.Get(0).(*models.Webhook), returnValues.Error(1)
+}
+
+func (ws *WebhookServiceMock) Delete(ctx context.Context, ID uint) error {
+	returnValues := ws.Called(ctx, ID)
+	return returnValues.Error(0)
+}
+
+func (ws *WebhookServiceMock) FindDeliveries(
+	ctx context.Context,
+	webhookID uint,
+	page services.Page,
+) ([]*models.Delivery, *services.Cursor, error) {
+	returnValues := ws.Called(ctx, webhookID, page)
+	return returnValues.Get(0).([]*models.Delivery), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (ws *WebhookServiceMock) Redeliver(ctx context.Context, webhookID, deliveryID uint) (*models.Delivery, error) {
+	returnValues := ws.Called(ctx, webhookID, deliveryID)
+	return returnValues.Get(0).(*models.Delivery), returnValues.Error(1)
+}
+
+type RuleServiceMock struct {
+	mock.Mock
+}
+
+func (rs *RuleServiceMock) Create(ctx context.Context, rule *models.Rule) (*models.Rule, error) {
+	returnValues := rs.Called(ctx, rule)
+	return returnValues.Get(0).(*models.Rule), returnValues.Error(1)
+}
+
+func (rs *RuleServiceMock) FindByBoard(
+	ctx context.Context,
+	boardID uint,
+	page services.Page,
+) ([]*models.Rule, *services.Cursor, error) {
+	returnValues := rs.Called(ctx, boardID, page)
+	return returnValues.Get(0).([]*models.Rule), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
+
+func (rs *RuleServiceMock) FindOneById(ctx context.Context, ID uint) (*models.Rule, error) {
+	returnValues := rs.Called(ctx, ID)
+	return returnValues.Get(0).(*models.Rule), returnValues.Error(1)
+}
+
+func (rs *RuleServiceMock) Update(ctx context.Context, rule *models.Rule) (*models.Rule, error) {
+	returnValues := rs.Called(ctx, rule)
+	return returnValues.Get(0).(*models.Rule), returnValues.Error(1)
+}
+
+func (rs *RuleServiceMock) Delete(ctx context.Context, ID uint) error {
+	returnValues := rs.Called(ctx, ID)
+	return returnValues.Error(0)
+}
+
+func (rs *RuleServiceMock) FindExecutions(
+	ctx context.Context,
+	ruleID uint,
+	page services.Page,
+) ([]*models.RuleExecution, *services.Cursor, error) {
+	returnValues := rs.Called(ctx, ruleID, page)
+	return returnValues.Get(0).([]*models.RuleExecution), returnValues.Get(1).(*services.Cursor), returnValues.Error(2)
+}
@@ -0,0 +1,214 @@
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+)
+
+// RuleHandler provides a Rest API http handlers for work with the automation rules
+// of the boards and their execution logs
+type RuleHandler struct {
+	service RuleService
+	log     log.Logger
+	router  routeAware
+	resp    *responder
+}
+
+// NewRuleHandler is a RuleHandler constructor
+func NewRuleHandler(service RuleService, logger log.Logger, router routeAware) *RuleHandler {
+	return &RuleHandler{
+		service: service,
+		log:     logger,
+		router:  router,
+		resp:    &responder{log: logger},
+	}
+}
+
+// Create will add the provided automation rule to the board, the authenticated user
+// becomes the author of the rule
+func (h RuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	boardID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var rule models.Rule
+	if !h.readRule(w, r, &rule) {
+		return
+	}
+
+	rule.ID, rule.BoardID, rule.CreatedBy = 0, boardID, authorID(r)
+	newRule, err := h.service.Create(r.Context(), &rule)
+	if err != nil {
+		h.respondError(w, boardID, err, "resource was not created")
+		return
+	}
+
+	url, err := h.router.GetURL("get_rule", "id", strconv.Itoa(int(newRule.ID)))
+	if err != nil {
+		h.log.Errorf("unable to build URL: %v", err)
+	}
+	w.Header().Set("Location", url.Path)
+	h.resp.respondJSON(w, http.StatusCreated, newRule)
+}
+
+// GetByBoard will respond with the automation rules of the requested board or an error
+func (h RuleHandler) GetByBoard(w http.ResponseWriter, r *http.Request) {
+	boardID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	page := services.Page{}
+	if err = parseFilter(r, unfiltered{}, &page); err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidFilterParams)
+		return
+	}
+
+	rules, next, err := h.service.FindByBoard(r.Context(), boardID, page)
+	if err != nil {
+		h.respondError(w, boardID, err, "error while getting records")
+		return
+	}
+
+	setNextPageLink(w, r, next)
+	h.resp.respondJSON(w, http.StatusOK, rules)
+}
+
+// GetOneById will respond with the requested automation rule or an error
+func (h RuleHandler) GetOneById(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	rule, err := h.service.FindOneById(r.Context(), ID)
+	if err != nil {
+		h.respondError(w, ID, err, "error while getting a record")
+		return
+	}
+
+	h.resp.respondJSON(w, http.StatusOK, rule)
+}
+
+// Update will update the provided automation rule, its board and author are kept
+func (h RuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var rule models.Rule
+	if !h.readRule(w, r, &rule) {
+		return
+	}
+
+	rule.ID = ID
+	updatedRule, err := h.service.Update(r.Context(), &rule)
+	if err != nil {
+		h.respondError(w, ID, err, "resource was not updated")
+		return
+	}
+
+	h.resp.respondJSON(w, http.StatusOK, updatedRule)
+}
+
+// Delete will delete the automation rule along with its execution log
+func (h RuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	if err = h.service.Delete(r.Context(), ID); err != nil {
+		h.respondError(w, ID, err, "error while deleting a record")
+		return
+	}
+
+	h.resp.respond(w, http.StatusNoContent, "")
+}
+
+// GetExecutions will respond with the execution log of the automation rule from the
+// newest to the oldest execution or an error
+func (h RuleHandler) GetExecutions(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	page := services.Page{}
+	if err = parseFilter(r, unfiltered{}, &page); err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidFilterParams)
+		return
+	}
+
+	executions, next, err := h.service.FindExecutions(r.Context(), ID, page)
+	if err != nil {
+		h.respondError(w, ID, err, "error while getting records")
+		return
+	}
+
+	setNextPageLink(w, r, next)
+	h.resp.respondJSON(w, http.StatusOK, executions)
+}
+
+// respondError makes the response on the error of the service, the message is logged
+// for the unexpected errors
+func (h RuleHandler) respondError(w http.ResponseWriter, ID uint, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrRecordNotFound), errors.Is(err, services.ErrBoardRelation):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("%s: %v", message, err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("%s: %v", message, err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// readRule will decode the request body into the provided rule or respond
+// with an error
+func (h RuleHandler) readRule(w http.ResponseWriter, r *http.Request, rule *models.Rule) bool {
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return false
+	}
+	if err := json.Unmarshal(reqBody, rule); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return false
+	}
+
+	return true
+}
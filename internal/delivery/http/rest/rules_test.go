@@ -0,0 +1,168 @@
+// +build unit
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRuleHandler_Create(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	validationErr := v.NewErrors()
+	validationErr.Add(v.Error{Field: "actions", Message: "unknown action type"})
+	const body = `{"name":"triage","trigger":{"type":"task.created"},"actions":[{"type":"label","label":1}]}`
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"success", body, nil, http.StatusCreated},
+		{"invalid_json", `{`, nil, http.StatusBadRequest},
+		{"validation_error", `{"name":"triage","actions":[{"type":"delete"}]}`, validationErr, http.StatusBadRequest},
+		{"not_found", body, services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", body, services.ErrForbidden, http.StatusForbidden},
+		{"service_error", body, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/boards/1/rules", strings.NewReader(tt.body))
+			req = req.WithContext(services.WithUser(req.Context(), &models.User{Model: models.Model{ID: 3}}))
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			router.On("GetURL", "get_rule", []string{"id", "2"}).Return(&url.URL{Path: "/api/v1/rules/2"}, nil)
+			service := new(RuleServiceMock)
+			service.On("Create", req.Context(), mock.Anything).Return(&models.Rule{Model: models.Model{ID: 2}}, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewRuleHandler(service, logger, router).Create(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.code == http.StatusCreated {
+				assert.Equal(t, "/api/v1/rules/2", recorder.Header().Get("Location"))
+				rule := service.Calls[0].Arguments.Get(1).(*models.Rule)
+				assert.Equal(t, uint(1), rule.BoardID)
+				assert.Equal(t, uint(3), rule.CreatedBy)
+				assert.Equal(t, models.RuleActions{{Type: models.ActionTypeLabel, LabelID: 1}}, rule.Actions)
+			}
+		})
+	}
+}
+
+func TestRuleHandler_Update(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"service_error", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/v1/rules/1", strings.NewReader(`{"name":"triage","disabled":true}`))
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(RuleServiceMock)
+			service.On("Update", req.Context(), mock.Anything).Return(&models.Rule{Model: models.Model{ID: 1}}, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewRuleHandler(service, logger, router).Update(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			rule := service.Calls[0].Arguments.Get(1).(*models.Rule)
+			assert.Equal(t, uint(1), rule.ID)
+			assert.True(t, rule.Disabled)
+		})
+	}
+}
+
+func TestRuleHandler_Delete(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusNoContent},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"service_error", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/v1/rules/1", nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(RuleServiceMock)
+			service.On("Delete", req.Context(), uint(1)).Return(tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewRuleHandler(service, logger, router).Delete(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestRuleHandler_GetExecutions(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debug", mock.Anything).Return()
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name  string
+		query string
+		next  *services.Cursor
+		err   error
+		code  int
+	}{
+		{"success", "?limit=1", &services.Cursor{ID: 1}, nil, http.StatusOK},
+		{"invalid_filter", "?status=failed", nil, nil, http.StatusBadRequest},
+		{"not_found", "", nil, services.ErrRecordNotFound, http.StatusNotFound},
+		{"service_error", "", nil, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/rules/1/executions"+tt.query, nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(RuleServiceMock)
+			service.On("FindExecutions", req.Context(), uint(1), mock.Anything).
+				Return([]*models.RuleExecution{{ID: 1}}, tt.next, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewRuleHandler(service, logger, router).GetExecutions(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.next != nil {
+				assert.Contains(t, recorder.Header().Get("Link"), `rel="next"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// SearchHandler provides a Rest API http handlers for the full-text search of tasks and comments
+type SearchHandler struct {
+	service SearchService
+	log     log.Logger
+	resp    *responder
+}
+
+// NewSearchHandler is a SearchHandler constructor
+func NewSearchHandler(service SearchService, logger log.Logger) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+		log:     logger,
+		resp:    &responder{log: logger},
+	}
+}
+
+// Get will respond with the tasks and the comments matching the requested query
+// or an error
+func (h SearchHandler) Get(w http.ResponseWriter, r *http.Request) {
+	demand, page := make(services.SearchDemand), services.Page{}
+	err := parseFilter(r, demand, &page)
+	if err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidFilterParams)
+		return
+	}
+
+	hits, next, err := h.service.Search(r.Context(), demand, page)
+	switch {
+	case err == nil:
+		setNextPageLink(w, r, next)
+		h.resp.respondJSON(w, http.StatusOK, hits)
+	case errors.Is(err, services.ErrSearchQuery):
+		h.log.Debugf("search error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.log.Errorf("error while searching records: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+	}
+}
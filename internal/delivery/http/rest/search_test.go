@@ -0,0 +1,51 @@
+// +build unit
+
+package rest
+
+import (
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSearchHandler_Get(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debug", mock.Anything).Return()
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name   string
+		query  string
+		demand services.SearchDemand
+		err    error
+		code   int
+	}{
+		{"by_query", "?q=release+notes", services.SearchDemand{"q": "release notes"}, nil, http.StatusOK},
+		{"by_board", "?q=release&board=2", services.SearchDemand{"q": "release", "board": uint(2)}, nil, http.StatusOK},
+		{"invalid_filter", "?q=release&column=2", nil, nil, http.StatusBadRequest},
+		{"missing_query", "", services.SearchDemand{}, services.ErrSearchQuery, http.StatusBadRequest},
+		{"internal", "?q=release", services.SearchDemand{"q": "release"}, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/search"+tt.query, nil)
+			service := new(SearchServiceMock)
+			service.On("Search", req.Context(), tt.demand, services.Page{}).
+				Return([]*models.SearchHit{{Type: models.EntityTask, ID: 1}}, (*services.Cursor)(nil), tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewSearchHandler(service, logger).Get(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.demand == nil {
+				service.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
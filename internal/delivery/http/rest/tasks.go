@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/dnozdrin/detask/internal/app/log"
 	"github.com/dnozdrin/detask/internal/domain/models"
@@ -9,10 +10,14 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+// taskFinder is a signature of the task service methods that return a page of tasks
+type taskFinder func(context.Context, services.TaskDemand, services.Page) ([]*models.Task, *services.Cursor, error)
+
 // TaskHandler provides a Rest API http handlers for work with tasks
 type TaskHandler struct {
 	service TaskService
@@ -45,8 +50,9 @@ func (h TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
 		return
 	}
+	task.CreatedBy = authorID(r)
 
-	newTask, err := h.service.Create(&task)
+	newTask, err := h.service.Create(r.Context(), &task)
 	switch {
 	case err == nil:
 		url, err := h.router.GetURL("get_task", "id", strconv.Itoa(int(newTask.ID)))
@@ -54,14 +60,22 @@ func (h TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 			h.log.Errorf("unable to build URL: %v", err)
 		}
 		w.Header().Set("Location", url.Path)
+		setETag(w, newTask.Version)
 		h.resp.respondJSON(w, http.StatusCreated, newTask)
-	case errors.Is(err, services.ErrColumnRelation):
+	case errors.Is(err, services.ErrColumnRelation),
+		errors.Is(err, services.ErrUserRelation),
+		errors.Is(err, services.ErrLabelRelation),
+		errors.Is(err, services.ErrNotMember):
 		h.log.Debugf("constraints error: %v", err)
 		h.resp.respondError(w, http.StatusBadRequest, err.Error())
 	case errors.Is(err, services.ErrRecordAlreadyExist),
-		errors.Is(err, services.ErrPositionDuplicate):
+		errors.Is(err, services.ErrPositionDuplicate),
+		errors.Is(err, services.ErrWIPLimitExceeded):
 		h.log.Debugf("constraints error: %v", err)
 		h.resp.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
 	default:
 		if _, ok := err.(*v.Errors); ok {
 			h.log.Debug("resource was not created", err)
@@ -82,36 +96,61 @@ func (h TaskHandler) GetOneById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.service.FindOneById(ID)
+	task, err := h.service.FindOneById(r.Context(), ID)
 	if err != nil {
 		if err == services.ErrRecordNotFound {
 			h.resp.respondError(w, http.StatusNotFound, "resource was not found")
 			return
 		}
-		h.log.Errorf("resource was not found: %v", err)
+		if err == services.ErrForbidden {
+			h.resp.respondError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		h.log.Errorf("error while getting the record: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		return
 	}
 
-	w.Header().Set("Last-Modified", task.UpdatedAt.Format(http.TimeFormat))
+	if notModified(w, r, entityTag(task.Version), task.UpdatedAt) {
+		h.resp.respond(w, http.StatusNotModified, "")
+		return
+	}
 	h.resp.respondJSON(w, http.StatusOK, task)
 }
 
 // Get will respond with the requested resources or an error
 func (h TaskHandler) Get(w http.ResponseWriter, r *http.Request) {
-	demand := make(services.TaskDemand)
-	err := parseFilter(r, &demand)
+	h.find(w, r, h.service.Find)
+}
+
+// GetAssigned will respond with the tasks assigned to the current user or an error
+func (h TaskHandler) GetAssigned(w http.ResponseWriter, r *http.Request) {
+	h.find(w, r, h.service.FindAssigned)
+}
+
+// find will respond with the tasks returned by the provided finder or an error
+func (h TaskHandler) find(w http.ResponseWriter, r *http.Request, finder taskFinder) {
+	demand, page := make(services.TaskDemand), services.Page{}
+	err := parseFilter(r, &demand, &page)
 	if err != nil {
 		h.log.Debug(err)
 		h.resp.respondError(w, http.StatusBadRequest, "invalid filter params")
 		return
 	}
 
-	tasks, err := h.service.Find(demand)
+	tasks, next, err := finder(r.Context(), demand, page)
 	if err != nil {
 		h.log.Errorf("error while getting records: %v", err)
 		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
 		return
 	}
 
+	setNextPageLink(w, r, next)
+	tag := collectionTag(len(tasks), func(i int) models.Model { return tasks[i].Model })
+	if notModified(w, r, tag, time.Time{}) {
+		h.resp.respond(w, http.StatusNotModified, "")
+		return
+	}
 	h.resp.respondJSON(w, http.StatusOK, tasks)
 }
 
@@ -124,6 +163,13 @@ func (h TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
 	var task models.Task
 	reqBody, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -137,20 +183,67 @@ func (h TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task.ID = ID
-	updatedTask, err := h.service.Update(&task)
+	task.ID, task.Version = ID, version
+	updatedTask, err := h.service.Update(r.Context(), &task)
+	h.respondUpdated(w, ID, updatedTask, err)
+}
+
+// Patch will trigger patch of the provided resource with the document of the request
+func (h TaskHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
+	patch, ok := h.resp.readPatch(w, r)
+	if !ok {
+		return
+	}
+
+	task, err := h.service.Patch(r.Context(), ID, patch, version)
+	h.respondUpdated(w, ID, task, err)
+}
+
+// respondUpdated makes the response on the update of the task with the provided ID
+func (h TaskHandler) respondUpdated(w http.ResponseWriter, ID uint, task *models.Task, err error) {
 	switch {
 	case err == nil:
-		h.resp.respondJSON(w, http.StatusOK, updatedTask)
+		setETag(w, task.Version)
+		h.resp.respondJSON(w, http.StatusOK, task)
 	case errors.Is(err, services.ErrRecordNotFound):
 		h.log.Debugf("resource was not found %d", ID)
 		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
-	case errors.Is(err, services.ErrColumnRelation):
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrColumnRelation),
+		errors.Is(err, services.ErrUserRelation),
+		errors.Is(err, services.ErrLabelRelation),
+		errors.Is(err, services.ErrNotMember):
 		h.log.Debugf("constraints error: %v", err)
 		h.resp.respondError(w, http.StatusBadRequest, err.Error())
-	case errors.Is(err, services.ErrPositionDuplicate):
+	case errors.Is(err, services.ErrPositionDuplicate),
+		errors.Is(err, services.ErrWIPLimitExceeded):
 		h.log.Debugf("constraints error: %v", err)
 		h.resp.respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrVersionMismatch):
+		h.log.Debugf("precondition failed: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, services.ErrInvalidPatch):
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPatchTestFailed):
+		h.log.Debugf("patch error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
 	default:
 		if _, ok := err.(*v.Errors); ok {
 			h.log.Debugf("resource was not updated: %v", err)
@@ -162,6 +255,112 @@ func (h TaskHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Move will move the task to the requested place of a column and respond with
+// the moved task
+func (h TaskHandler) Move(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var move models.TaskMove
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if err := json.Unmarshal(reqBody, &move); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return
+	}
+
+	movedTask, err := h.service.Move(r.Context(), ID, move)
+	switch {
+	case err == nil:
+		setETag(w, movedTask.Version)
+		h.resp.respondJSON(w, http.StatusOK, movedTask)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrColumnRelation),
+		errors.Is(err, services.ErrTaskRelation),
+		errors.Is(err, services.ErrLabelRelation):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPositionDuplicate),
+		errors.Is(err, services.ErrWIPLimitExceeded):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("resource was not moved: %v", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("resource was not moved: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// Transfer will move the task to a column of another board and respond with
+// the transferred task
+func (h TaskHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var transfer models.TaskTransfer
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return
+	}
+	if err := json.Unmarshal(reqBody, &transfer); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return
+	}
+
+	transferredTask, err := h.service.Transfer(r.Context(), ID, transfer)
+	switch {
+	case err == nil:
+		setETag(w, transferredTask.Version)
+		h.resp.respondJSON(w, http.StatusOK, transferredTask)
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrColumnRelation):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrPositionDuplicate),
+		errors.Is(err, services.ErrWIPLimitExceeded):
+		h.log.Debugf("constraints error: %v", err)
+		h.resp.respondError(w, http.StatusConflict, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("resource was not transferred: %v", err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("resource was not transferred: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
 // Delete will trigger deletion of the provided resource
 func (h TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ID, err := h.router.GetIDVar(r)
@@ -171,10 +370,28 @@ func (h TaskHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = h.service.Delete(ID); err != nil {
+	version, err := ifMatch(r)
+	if err != nil {
+		h.log.Debugf("error on parsing precondition: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, errIfMatchFailed)
+		return
+	}
+
+	err = h.service.Delete(r.Context(), ID, version)
+	switch err {
+	case nil:
+		h.resp.respond(w, http.StatusNoContent, "")
+	case services.ErrRecordNotFound:
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case services.ErrForbidden:
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	case services.ErrVersionMismatch:
+		h.log.Debugf("precondition failed: %v", err)
+		h.resp.respondError(w, http.StatusPreconditionFailed, err.Error())
+	default:
 		h.log.Errorf("error while deleting a record: %v", err)
 		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
 	}
-
-	h.resp.respond(w, http.StatusNoContent, "")
 }
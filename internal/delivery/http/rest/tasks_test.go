@@ -3,12 +3,16 @@
 package rest
 
 import (
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetIDVarError_Tasks(t *testing.T) {
@@ -38,3 +42,264 @@ func TestGetIDVarError_Tasks(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskHandler_GetAssigned(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debug", mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name  string
+		query string
+		err   error
+		code  int
+	}{
+		{"success", "?board=1", nil, http.StatusOK},
+		{"invalid_filter", "?name=dummy", nil, http.StatusBadRequest},
+		{"service_error", "", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var next *services.Cursor
+			req := httptest.NewRequest("GET", "/api/v1/me/tasks"+tt.query, nil)
+			service := new(TaskServiceMock)
+			service.On("FindAssigned", req.Context(), mock.Anything, services.Page{}).
+				Return([]*models.Task{{Name: "dummy"}}, next, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewTaskHandler(service, logger, new(RouteAwareMock)).GetAssigned(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestTaskHandler_Update(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"missing_column", services.ErrColumnRelation, http.StatusBadRequest},
+		{"position_taken", services.ErrPositionDuplicate, http.StatusConflict},
+		{"wip_limit", services.ErrWIPLimitExceeded, http.StatusConflict},
+		{"version_mismatch", services.ErrVersionMismatch, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &models.Task{Model: models.Model{ID: 1, Version: 3}, Name: "dummy", ColumnID: 2, Position: 1}
+			req := httptest.NewRequest("PUT", "/api/v1/tasks/1", strings.NewReader(`{"name":"dummy","column":2,"position":1}`))
+			req.Header.Set("If-Match", `"3"`)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(TaskServiceMock)
+			service.On("Update", req.Context(), task).Return(task, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewTaskHandler(service, logger, router).Update(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.err == nil {
+				assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Patch(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name        string
+		contentType string
+		err         error
+		code        int
+	}{
+		{"merge_patch", "application/merge-patch+json", nil, http.StatusOK},
+		{"json_patch", "application/json-patch+json; charset=utf-8", nil, http.StatusOK},
+		{"unsupported_type", "application/json", nil, http.StatusUnsupportedMediaType},
+		{"invalid_patch", "application/merge-patch+json", services.ErrInvalidPatch, http.StatusBadRequest},
+		{"test_failed", "application/json-patch+json", services.ErrPatchTestFailed, http.StatusConflict},
+		{"version_mismatch", "application/merge-patch+json", services.ErrVersionMismatch, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &models.Task{Model: models.Model{ID: 1, Version: 4}, Name: "dummy", ColumnID: 2, Position: 1}
+			req := httptest.NewRequest("PATCH", "/api/v1/tasks/1", strings.NewReader(`{"name":"dummy"}`))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("If-Match", `"3"`)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			mediaType := strings.Split(tt.contentType, ";")[0]
+			patch := services.Patch{Type: services.PatchType(mediaType), Document: []byte(`{"name":"dummy"}`)}
+			service := new(TaskServiceMock)
+			service.On("Patch", req.Context(), uint(1), patch, uint(3)).Return(task, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewTaskHandler(service, logger, router).Patch(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			switch tt.code {
+			case http.StatusOK:
+				assert.Equal(t, `"4"`, recorder.Header().Get("ETag"))
+			case http.StatusUnsupportedMediaType:
+				assert.Equal(t, acceptPatch, recorder.Header().Get("Accept-Patch"))
+				service.AssertNotCalled(t, "Patch", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Move(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"missing_column", services.ErrColumnRelation, http.StatusBadRequest},
+		{"foreign_task", services.ErrTaskRelation, http.StatusBadRequest},
+		{"wip_limit", services.ErrWIPLimitExceeded, http.StatusConflict},
+		{"internal", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			move := models.TaskMove{ColumnID: 2, AfterID: 3}
+			task := &models.Task{Model: models.Model{ID: 1, Version: 2}, Name: "dummy", ColumnID: 2, Position: 1}
+			req := httptest.NewRequest("POST", "/api/v1/tasks/1/move", strings.NewReader(`{"column":2,"after":3}`))
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(TaskServiceMock)
+			service.On("Move", req.Context(), uint(1), move).Return(task, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewTaskHandler(service, logger, router).Move(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.err == nil {
+				assert.Equal(t, `"2"`, recorder.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestTaskHandler_Transfer(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"missing_column", services.ErrColumnRelation, http.StatusBadRequest},
+		{"wip_limit", services.ErrWIPLimitExceeded, http.StatusConflict},
+		{"internal", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &models.Task{Model: models.Model{ID: 1, Version: 5}, Name: "dummy", ColumnID: 5, Position: 1000}
+			req := httptest.NewRequest("POST", "/api/v1/tasks/1/transfer", strings.NewReader(`{"column":5}`))
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(TaskServiceMock)
+			service.On("Transfer", req.Context(), uint(1), models.TaskTransfer{ColumnID: 5}).Return(task, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewTaskHandler(service, logger, router).Transfer(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.err == nil {
+				assert.Equal(t, `"5"`, recorder.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetOneById(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+	task := &models.Task{Model: models.Model{ID: 1, Version: 4, UpdatedAt: time.Now()}, Name: "dummy"}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		task        *models.Task
+		err         error
+		code        int
+	}{
+		{"success", "", task, nil, http.StatusOK},
+		{"changed", `"3"`, task, nil, http.StatusOK},
+		{"not_modified", `"4"`, task, nil, http.StatusNotModified},
+		{"service_error", "", nil, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/tasks/1", nil)
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(TaskServiceMock)
+			service.On("FindOneById", req.Context(), uint(1)).Return(tt.task, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewTaskHandler(service, logger, router).GetOneById(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.err != nil {
+				return
+			}
+			assert.Equal(t, `"4"`, recorder.Header().Get("ETag"))
+			if tt.code == http.StatusNotModified {
+				assert.Empty(t, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestTaskHandler_GetNotModified(t *testing.T) {
+	logger := new(LoggerMock)
+	updatedAt := time.Date(2020, 8, 1, 10, 0, 0, 0, time.UTC)
+	tasks := []*models.Task{
+		{Model: models.Model{ID: 1, Version: 1, UpdatedAt: updatedAt}},
+		{Model: models.Model{ID: 2, Version: 3, UpdatedAt: updatedAt.Add(-time.Hour)}},
+	}
+	get := func(header, value string) *httptest.ResponseRecorder {
+		var next *services.Cursor
+		req := httptest.NewRequest("GET", "/api/v1/tasks?board=1", nil)
+		req.Header.Set(header, value)
+		service := new(TaskServiceMock)
+		service.On("Find", req.Context(), mock.Anything, services.Page{}).Return(tasks, next, nil)
+
+		recorder := httptest.NewRecorder()
+		NewTaskHandler(service, logger, new(RouteAwareMock)).Get(recorder, req)
+
+		return recorder
+	}
+
+	response := get("If-Modified-Since", "Sat, 01 Aug 2020 09:00:00 GMT")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Empty(t, response.Header().Get("Last-Modified"))
+
+	// a deleted task does not change the latest update, so the collections rely on the tag
+	assert.Equal(t, http.StatusOK, get("If-Modified-Since", "Sat, 01 Aug 2020 10:00:00 GMT").Code)
+	assert.Equal(t, http.StatusNotModified, get("If-None-Match", response.Header().Get("ETag")).Code)
+}
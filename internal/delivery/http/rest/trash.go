@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// TrashHandler provides a Rest API http handlers for work with the deleted records
+type TrashHandler struct {
+	service TrashService
+	log     log.Logger
+	router  routeAware
+	resp    *responder
+}
+
+// NewTrashHandler is a TrashHandler constructor
+func NewTrashHandler(service TrashService, logger log.Logger, router routeAware) *TrashHandler {
+	return &TrashHandler{
+		service: service,
+		log:     logger,
+		router:  router,
+		resp:    &responder{log: logger},
+	}
+}
+
+// Get will respond with the requested deleted records or an error
+func (h TrashHandler) Get(w http.ResponseWriter, r *http.Request) {
+	demand, page := make(services.TrashDemand), services.Page{}
+	err := parseFilter(r, demand, &page)
+	if err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidFilterParams)
+		return
+	}
+
+	items, next, err := h.service.Find(r.Context(), demand, page)
+	if err != nil {
+		h.log.Errorf("error while getting records: %v", err)
+		h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		return
+	}
+
+	setNextPageLink(w, r, next)
+	h.resp.respondJSON(w, http.StatusOK, items)
+}
+
+// Restore will return a handler that triggers restoring of the deleted resource
+// of the provided type
+func (h TrashHandler) Restore(kind models.TrashType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ID, err := h.router.GetIDVar(r)
+		if err != nil {
+			h.log.Errorf("error on parsing resource identifier: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, "invalid resource identifier")
+			return
+		}
+
+		err = h.service.Restore(r.Context(), kind, ID)
+		switch {
+		case err == nil:
+			h.resp.respond(w, http.StatusNoContent, "")
+		case errors.Is(err, services.ErrRecordNotFound):
+			h.log.Debugf("resource was not found %d", ID)
+			h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+		case errors.Is(err, services.ErrForbidden):
+			h.log.Debugf("access error: %v", err)
+			h.resp.respondError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrTrashedParent),
+			errors.Is(err, services.ErrNameDuplicate),
+			errors.Is(err, services.ErrPositionDuplicate):
+			h.log.Debugf("constraints error: %v", err)
+			h.resp.respondError(w, http.StatusConflict, err.Error())
+		default:
+			h.log.Errorf("error while restoring a record: %v", err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// +build unit
+
+package rest
+
+import (
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrashHandler_Get(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debug", mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name   string
+		query  string
+		demand services.TrashDemand
+		err    error
+		code   int
+	}{
+		{"all", "", services.TrashDemand{}, nil, http.StatusOK},
+		{"by_board_and_type", "?board=2&type=task", services.TrashDemand{"board": uint(2), "type": models.TrashTask}, nil, http.StatusOK},
+		{"invalid_type", "?type=label", nil, nil, http.StatusBadRequest},
+		{"internal", "", services.TrashDemand{}, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/trash"+tt.query, nil)
+			service := new(TrashServiceMock)
+			service.On("Find", req.Context(), tt.demand, services.Page{}).
+				Return([]*models.TrashItem{{Type: models.TrashTask, ID: 1}}, (*services.Cursor)(nil), tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewTrashHandler(service, logger, new(RouteAwareMock)).Get(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.demand == nil {
+				service.AssertNotCalled(t, "Find", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestTrashHandler_Restore(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusNoContent},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"trashed_parent", services.ErrTrashedParent, http.StatusConflict},
+		{"name_duplicate", services.ErrNameDuplicate, http.StatusConflict},
+		{"internal", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/columns/3/restore", nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(3), nil)
+			service := new(TrashServiceMock)
+			service.On("Restore", req.Context(), models.TrashColumn, uint(3)).Return(tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewTrashHandler(service, logger, router).Restore(models.TrashColumn)(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+
+	t.Run("invalid_identifier", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/columns/3/restore", nil)
+		router := new(RouteAwareMock)
+		router.On("GetIDVar", req).Return(uint(0), errors.New("dummy"))
+
+		recorder := httptest.NewRecorder()
+		NewTrashHandler(new(TrashServiceMock), logger, router).Restore(models.TrashColumn)(recorder, req)
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+}
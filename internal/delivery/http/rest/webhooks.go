@@ -0,0 +1,238 @@
+package rest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+)
+
+// WebhookHandler provides a Rest API http handlers for work with webhooks and
+// their deliveries
+type WebhookHandler struct {
+	service WebhookService
+	log     log.Logger
+	router  routeAware
+	resp    *responder
+}
+
+// NewWebhookHandler is a WebhookHandler constructor
+func NewWebhookHandler(service WebhookService, logger log.Logger, router routeAware) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		log:     logger,
+		router:  router,
+		resp:    &responder{log: logger},
+	}
+}
+
+// Create will add the provided webhook to the board, the response contains the secret
+// of the webhook
+func (h WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	boardID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var webhook models.Webhook
+	if !h.readWebhook(w, r, &webhook) {
+		return
+	}
+
+	webhook.ID, webhook.BoardID = 0, boardID
+	newWebhook, err := h.service.Create(r.Context(), &webhook)
+	if err != nil {
+		h.respondError(w, boardID, err, "resource was not created")
+		return
+	}
+
+	url, err := h.router.GetURL("get_webhook", "id", strconv.Itoa(int(newWebhook.ID)))
+	if err != nil {
+		h.log.Errorf("unable to build URL: %v", err)
+	}
+	w.Header().Set("Location", url.Path)
+	h.resp.respondJSON(w, http.StatusCreated, newWebhook)
+}
+
+// GetByBoard will respond with the webhooks of the requested board or an error
+func (h WebhookHandler) GetByBoard(w http.ResponseWriter, r *http.Request) {
+	boardID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	page := services.Page{}
+	if err = parseFilter(r, unfiltered{}, &page); err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidFilterParams)
+		return
+	}
+
+	webhooks, next, err := h.service.FindByBoard(r.Context(), boardID, page)
+	if err != nil {
+		h.respondError(w, boardID, err, "error while getting records")
+		return
+	}
+
+	setNextPageLink(w, r, next)
+	h.resp.respondJSON(w, http.StatusOK, webhooks)
+}
+
+// GetOneById will respond with the requested webhook or an error
+func (h WebhookHandler) GetOneById(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	webhook, err := h.service.FindOneById(r.Context(), ID)
+	if err != nil {
+		h.respondError(w, ID, err, "error while getting a record")
+		return
+	}
+
+	h.resp.respondJSON(w, http.StatusOK, webhook)
+}
+
+// Update will update the provided webhook, the secret is kept unless a new one is
+// provided
+func (h WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	var webhook models.Webhook
+	if !h.readWebhook(w, r, &webhook) {
+		return
+	}
+
+	webhook.ID = ID
+	updatedWebhook, err := h.service.Update(r.Context(), &webhook)
+	if err != nil {
+		h.respondError(w, ID, err, "resource was not updated")
+		return
+	}
+
+	h.resp.respondJSON(w, http.StatusOK, updatedWebhook)
+}
+
+// Delete will delete the webhook along with its deliveries
+func (h WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	if err = h.service.Delete(r.Context(), ID); err != nil {
+		h.respondError(w, ID, err, "error while deleting a record")
+		return
+	}
+
+	h.resp.respond(w, http.StatusNoContent, "")
+}
+
+// GetDeliveries will respond with the delivery history of the webhook from the newest
+// to the oldest delivery or an error
+func (h WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	page := services.Page{}
+	if err = parseFilter(r, unfiltered{}, &page); err != nil {
+		h.log.Debug(err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidFilterParams)
+		return
+	}
+
+	deliveries, next, err := h.service.FindDeliveries(r.Context(), ID, page)
+	if err != nil {
+		h.respondError(w, ID, err, "error while getting records")
+		return
+	}
+
+	setNextPageLink(w, r, next)
+	h.resp.respondJSON(w, http.StatusOK, deliveries)
+}
+
+// Redeliver will queue a new delivery of the payload of the requested delivery and
+// respond with the queued delivery
+func (h WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	ID, err := h.router.GetIDVar(r)
+	var deliveryID uint
+	if err == nil {
+		deliveryID, err = h.router.GetUintVar(r, "delivery")
+	}
+	if err != nil {
+		h.log.Errorf("error on parsing resource identifier: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "invalid resource identifier")
+		return
+	}
+
+	delivery, err := h.service.Redeliver(r.Context(), ID, deliveryID)
+	if err != nil {
+		h.respondError(w, deliveryID, err, "resource was not created")
+		return
+	}
+
+	h.resp.respondJSON(w, http.StatusAccepted, delivery)
+}
+
+// respondError makes the response on the error of the service, the message is logged
+// for the unexpected errors
+func (h WebhookHandler) respondError(w http.ResponseWriter, ID uint, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrRecordNotFound), errors.Is(err, services.ErrBoardRelation):
+		h.log.Debugf("resource was not found %d", ID)
+		h.resp.respondError(w, http.StatusNotFound, "resource was not found")
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		h.resp.respondError(w, http.StatusForbidden, err.Error())
+	default:
+		if _, ok := err.(*v.Errors); ok {
+			h.log.Debugf("%s: %v", message, err)
+			h.resp.respondJSON(w, http.StatusBadRequest, err)
+		} else {
+			h.log.Errorf("%s: %v", message, err)
+			h.resp.respondError(w, http.StatusInternalServerError, errInternalServer)
+		}
+	}
+}
+
+// readWebhook will decode the request body into the provided webhook or respond
+// with an error
+func (h WebhookHandler) readWebhook(w http.ResponseWriter, r *http.Request, webhook *models.Webhook) bool {
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		h.log.Errorf("error on request body read: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, "error on request body read")
+		return false
+	}
+	if err := json.Unmarshal(reqBody, webhook); err != nil {
+		h.log.Debugf("error on request body parsing: %v", err)
+		h.resp.respondError(w, http.StatusBadRequest, errInvalidJSON)
+		return false
+	}
+
+	return true
+}
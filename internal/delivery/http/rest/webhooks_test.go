@@ -0,0 +1,177 @@
+// +build unit
+
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWebhookHandler_Create(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	validationErr := v.NewErrors()
+	validationErr.Add(v.Error{Field: "url", Message: "url is invalid"})
+
+	tests := []struct {
+		name string
+		body string
+		err  error
+		code int
+	}{
+		{"success", `{"url":"https://example.com/hook"}`, nil, http.StatusCreated},
+		{"invalid_json", `{`, nil, http.StatusBadRequest},
+		{"validation_error", `{"url":"ftp://example.com"}`, validationErr, http.StatusBadRequest},
+		{"not_found", `{"url":"https://example.com/hook"}`, services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", `{"url":"https://example.com/hook"}`, services.ErrForbidden, http.StatusForbidden},
+		{"service_error", `{"url":"https://example.com/hook"}`, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/boards/1/webhooks", strings.NewReader(tt.body))
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			router.On("GetURL", "get_webhook", []string{"id", "2"}).Return(&url.URL{Path: "/api/v1/webhooks/2"}, nil)
+			service := new(WebhookServiceMock)
+			service.On("Create", req.Context(), mock.Anything).Return(&models.Webhook{Model: models.Model{ID: 2}}, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewWebhookHandler(service, logger, router).Create(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.code == http.StatusCreated {
+				assert.Equal(t, "/api/v1/webhooks/2", recorder.Header().Get("Location"))
+				webhook := service.Calls[0].Arguments.Get(1).(*models.Webhook)
+				assert.Equal(t, uint(1), webhook.BoardID)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_GetByBoard(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debug", mock.Anything).Return()
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name  string
+		query string
+		next  *services.Cursor
+		err   error
+		code  int
+	}{
+		{"success", "?limit=1", &services.Cursor{ID: 1}, nil, http.StatusOK},
+		{"invalid_filter", "?url=x", nil, nil, http.StatusBadRequest},
+		{"forbidden", "", nil, services.ErrForbidden, http.StatusForbidden},
+		{"service_error", "", nil, errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/boards/1/webhooks"+tt.query, nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(WebhookServiceMock)
+			service.On("FindByBoard", req.Context(), uint(1), mock.Anything).
+				Return([]*models.Webhook{{Model: models.Model{ID: 1}}}, tt.next, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewWebhookHandler(service, logger, router).GetByBoard(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+			if tt.next != nil {
+				assert.Contains(t, recorder.Header().Get("Link"), `rel="next"`)
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_Delete(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusNoContent},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"service_error", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/v1/webhooks/1", nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			service := new(WebhookServiceMock)
+			service.On("Delete", req.Context(), uint(1)).Return(tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewWebhookHandler(service, logger, router).Delete(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestWebhookHandler_Redeliver(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusAccepted},
+		{"not_found", services.ErrRecordNotFound, http.StatusNotFound},
+		{"forbidden", services.ErrForbidden, http.StatusForbidden},
+		{"service_error", errors.New("dummy"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/webhooks/1/deliveries/2/redeliver", nil)
+			router := new(RouteAwareMock)
+			router.On("GetIDVar", req).Return(uint(1), nil)
+			router.On("GetUintVar", req, "delivery").Return(uint(2), nil)
+			service := new(WebhookServiceMock)
+			service.On("Redeliver", req.Context(), uint(1), uint(2)).Return(&models.Delivery{ID: 3}, tt.err)
+
+			recorder := httptest.NewRecorder()
+			NewWebhookHandler(service, logger, router).Redeliver(recorder, req)
+
+			assert.Equal(t, tt.code, recorder.Code)
+		})
+	}
+}
+
+func TestWebhookHandler_InvalidDeliveryVar(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+	req := httptest.NewRequest("POST", "/api/v1/webhooks/1/deliveries/x/redeliver", nil)
+	router := new(RouteAwareMock)
+	router.On("GetIDVar", req).Return(uint(1), nil)
+	router.On("GetUintVar", req, "delivery").Return(uint(0), errors.New("test error"))
+
+	recorder := httptest.NewRecorder()
+	NewWebhookHandler(new(WebhookServiceMock), logger, router).Redeliver(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
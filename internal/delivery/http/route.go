@@ -25,6 +25,9 @@ func NewRouter() *Router {
 	return router
 }
 
+// Middleware wraps a handler with an additional request processing
+type Middleware func(http.Handler) http.Handler
+
 // Route is the model for the router setup
 type Route struct {
 	Pattern     string
@@ -38,6 +41,18 @@ func (r Router) GetSubRouter(pathPrefix string) *Router {
 	return &Router{mux: r.mux.PathPrefix(pathPrefix).Subrouter()}
 }
 
+// WithMiddleware will return a router that shares the routes namespace with
+// the current one and applies the given middleware to all the routes
+// registered in it
+func (r Router) WithMiddleware(middleware ...Middleware) *Router {
+	sub := r.mux.NewRoute().Subrouter()
+	for _, m := range middleware {
+		sub.Use(mux.MiddlewareFunc(m))
+	}
+
+	return &Router{mux: sub}
+}
+
 // Register will add a new route to the router
 func (r Router) Register(route Route) {
 	r.mux.Methods(route.Method).Path(route.Pattern).HandlerFunc(route.HandlerFunc).Name(route.Name)
@@ -56,7 +71,13 @@ func (r Router) GetURL(routeName string, params ...string) (*url.URL, error) {
 
 // GetIDVar will return ID var that was set for the current route or an error
 func (r Router) GetIDVar(req *http.Request) (uint, error) {
-	ID, err := strconv.Atoi(mux.Vars(req)["id"])
+	return r.GetUintVar(req, "id")
+}
+
+// GetUintVar will return the var with the provided name that was set for
+// the current route or an error
+func (r Router) GetUintVar(req *http.Request, name string) (uint, error) {
+	value, err := strconv.Atoi(mux.Vars(req)[name])
 
-	return uint(ID), err
+	return uint(value), err
 }
@@ -0,0 +1,61 @@
+// +build unit
+
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestRouter_WithMiddleware(t *testing.T) {
+	var (
+		assert = testify.New(t)
+		router = NewRouter()
+		sub    = router.GetSubRouter("/api")
+
+		ok = func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+	)
+
+	deny := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+
+	sub.Register(Route{Pattern: "/public", Method: "GET", Name: "public", HandlerFunc: ok})
+	sub.WithMiddleware(deny).Register(Route{Pattern: "/private", Method: "GET", Name: "private", HandlerFunc: ok})
+
+	for path, code := range map[string]int{
+		"/api/public":  http.StatusOK,
+		"/api/private": http.StatusUnauthorized,
+		"/api/missing": http.StatusNotFound,
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest("GET", path, nil))
+		assert.Equal(code, recorder.Code, path)
+	}
+
+	url, err := sub.GetURL("private")
+	assert.NoError(err)
+	assert.Equal("/api/private", url.Path)
+}
+
+func TestRouter_GetUintVar(t *testing.T) {
+	var (
+		assert = testify.New(t)
+		router = NewRouter()
+		user   uint
+		err    error
+	)
+
+	router.Register(Route{Pattern: "/boards/{id}/members/{user}", Method: "GET", Name: "member", HandlerFunc: func(_ http.ResponseWriter, r *http.Request) {
+		user, err = router.GetUintVar(r, "user")
+	}})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/boards/1/members/42", nil))
+
+	assert.NoError(err)
+	assert.Equal(uint(42), user)
+}
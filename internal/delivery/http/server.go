@@ -3,9 +3,11 @@ package http
 import (
 	"context"
 	"github.com/dnozdrin/detask/internal/app/log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 )
 
@@ -19,16 +21,29 @@ const (
 	readTimeout  = 15 * time.Second
 )
 
+// connKey is the key of the connection of a request in the request context
+type connKey struct{}
+
 // NewServer will create a new instance of the web server
 func NewServer(handler http.Handler, log log.Logger) *server {
-	return &server{
+	base, cancel := context.WithCancel(context.Background())
+	s := &server{
 		log: log,
 		http: &http.Server{
-			Handler:      handler,
+			Handler:      streams(handler),
 			WriteTimeout: writeTimeout,
 			ReadTimeout:  readTimeout,
+			BaseContext:  func(net.Listener) context.Context { return base },
+			ConnContext: func(ctx context.Context, conn net.Conn) context.Context {
+				return context.WithValue(ctx, connKey{}, conn)
+			},
 		},
 	}
+	// the contexts of the long-lived requests are cancelled, so that they do not
+	// hold the shutdown
+	s.http.RegisterOnShutdown(cancel)
+
+	return s
 }
 
 // Start will start the web server
@@ -56,3 +71,16 @@ func (s *server) shutdown() {
 		s.log.Errorf("http: server: shutdown: %v", err)
 	}
 }
+
+// streams lifts the write timeout off the connections of the event stream requests,
+// which responses last as long as the clients stay connected
+func streams(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			if conn, ok := r.Context().Value(connKey{}).(net.Conn); ok {
+				_ = conn.SetWriteDeadline(time.Time{})
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
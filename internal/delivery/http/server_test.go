@@ -3,9 +3,14 @@
 package http
 
 import (
-	testify "github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	testify "github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNewServer(t *testing.T) {
@@ -16,8 +21,11 @@ func TestNewServer(t *testing.T) {
 		server  = NewServer(handler, logger)
 	)
 
+	handler.On("ServeHTTP", mock.Anything, mock.Anything).Return()
+	server.http.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
 	assert.Equal(logger, server.log)
-	assert.Equal(handler, server.http.Handler)
+	handler.AssertNumberOfCalls(t, "ServeHTTP", 1)
 	assert.Equal(writeTimeout, server.http.WriteTimeout)
 	assert.Equal(readTimeout, server.http.ReadTimeout)
 	assert.IsType(&http.Server{}, server.http)
@@ -54,3 +62,35 @@ func Test_server_Start(t *testing.T) {
 	logger.AssertNumberOfCalls(t, "Infof", 1)
 	logger.AssertNotCalled(t, "Errorf", 0)
 }
+
+func Test_server_Streams(t *testing.T) {
+	assert := testify.New(t)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte("late"))
+	})
+	ts := httptest.NewUnstartedServer(nil)
+	ts.Config = NewServer(handler, new(LoggerMock)).http
+	ts.Config.WriteTimeout = 20 * time.Millisecond
+	ts.Start()
+	defer ts.Close()
+
+	request := func(accept string) (string, error) {
+		req, _ := http.NewRequest("GET", ts.URL, nil)
+		req.Header.Set("Accept", accept)
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+
+		return string(body), err
+	}
+
+	body, err := request("text/event-stream")
+	assert.Nil(err)
+	assert.Equal("late", body)
+	_, err = request("application/json")
+	assert.Error(err)
+}
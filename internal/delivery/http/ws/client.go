@@ -0,0 +1,153 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/gorilla/websocket"
+)
+
+// client is a connection of a user subscribed to the boards
+type client struct {
+	session uint
+	user    *models.User
+	conn    *websocket.Conn
+	send    chan outbound
+
+	mu     sync.Mutex
+	closed bool
+	boards map[uint]context.CancelFunc
+}
+
+// newClient is a client constructor
+func newClient(session uint, user *models.User, conn *websocket.Conn) *client {
+	return &client{
+		session: session,
+		user:    user,
+		conn:    conn,
+		send:    make(chan outbound, sendBuffer),
+		boards:  make(map[uint]context.CancelFunc),
+	}
+}
+
+// queue will queue the message to be sent to the client. A client that does not
+// keep up with the messages is disconnected, so that it does not hold the others
+func (c *client) queue(msg outbound) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+
+	select {
+	case c.send <- msg:
+	default:
+		c.closed = true
+		_ = c.conn.Close()
+	}
+}
+
+// subscribed reports if the client is subscribed to the board
+func (c *client) subscribed(boardID uint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.boards[boardID]
+
+	return ok
+}
+
+// track will remember the subscription to the board along with its cancellation
+func (c *client) track(boardID uint, cancel context.CancelFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.boards[boardID] = cancel
+}
+
+// untrack will cancel the subscription to the board, it reports if the client
+// has been subscribed to it
+func (c *client) untrack(boardID uint) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cancel, ok := c.boards[boardID]
+	if ok {
+		cancel()
+		delete(c.boards, boardID)
+	}
+
+	return ok
+}
+
+// untrackAll will cancel all the subscriptions and will stop queueing the messages,
+// it returns the boards the client has been subscribed to
+func (c *client) untrackAll() []uint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	boardIDs := make([]uint, 0, len(c.boards))
+	for boardID, cancel := range c.boards {
+		cancel()
+		boardIDs = append(boardIDs, boardID)
+	}
+	c.boards = make(map[uint]context.CancelFunc)
+
+	return boardIDs
+}
+
+// writePump will write the queued messages and the pings to the connection till
+// the context is done or a write fails
+func (c *client) writePump(ctx context.Context, pingPeriod time.Duration) {
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+	defer c.conn.Close()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-ctx.Done():
+			_ = c.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+				time.Now().Add(writeWait),
+			)
+			return
+		}
+	}
+}
+
+// readPump will read the messages of the client and will pass them to the provided
+// function till the connection is closed. A client that stays silent and does not
+// answer the pings for longer than the pong wait is disconnected
+func (c *client) readPump(pongWait time.Duration, handle func(in inbound)) error {
+	c.conn.SetReadLimit(maxMessageSize)
+	_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		_ = c.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var in inbound
+		if err := json.Unmarshal(data, &in); err != nil {
+			c.queue(outbound{Type: typeError, Error: errInvalidJSON})
+			continue
+		}
+		handle(in)
+	}
+}
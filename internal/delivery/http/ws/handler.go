@@ -0,0 +1,142 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait is the time allowed to write a message to a client
+	writeWait = 10 * time.Second
+	// pongWait is the time allowed to read the next pong or message from a client
+	pongWait = 60 * time.Second
+	// pingPeriod is the period of the pings, it must be shorter than the pong wait
+	pingPeriod = pongWait * 9 / 10
+	// maxMessageSize is the largest message allowed from a client
+	maxMessageSize = 4096
+	// sendBuffer is the number of the messages queued for a client
+	sendBuffer = 64
+	// presenceTTL is the time a presence stays without being refreshed
+	presenceTTL = 60 * time.Second
+)
+
+// Handler provides a WebSocket http handler, that streams the board events to the
+// subscribed clients and shares the presence of the users between them
+type Handler struct {
+	log        log.Logger
+	upgrader   websocket.Upgrader
+	hub        *hub
+	pingPeriod time.Duration
+	pongWait   time.Duration
+}
+
+// NewHandler is a Handler constructor, the connections are accepted from the same
+// host and the provided origins, which may contain a wildcard
+func NewHandler(service EventService, logger log.Logger, allowedOrigins []string) *Handler {
+	return &Handler{
+		log: logger,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin:     originChecker(allowedOrigins),
+		},
+		hub:        newHub(service, logger, presenceTTL),
+		pingPeriod: pingPeriod,
+		pongWait:   pongWait,
+	}
+}
+
+// Serve will upgrade the connection to the WebSocket protocol and will serve the
+// client till it disconnects or the server shuts down
+func (h *Handler) Serve(w http.ResponseWriter, r *http.Request) {
+	user, ok := services.UserFromContext(r.Context())
+	if !ok {
+		h.log.Error("websocket: the user is missing in the request context")
+		http.Error(w, errInternalServer, http.StatusInternalServerError)
+		return
+	}
+	// the upgrader responds with the error on its own
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.log.Debugf("websocket: upgrade error: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	c := newClient(h.hub.session(), user, conn)
+	written := make(chan struct{})
+	go func() {
+		c.writePump(ctx, h.pingPeriod)
+		close(written)
+	}()
+
+	err = c.readPump(h.pongWait, func(in inbound) {
+		switch in.Type {
+		case typeSubscribe:
+			h.hub.subscribe(ctx, c, in.BoardID, in.LastEvent)
+		case typeUnsubscribe:
+			h.hub.unsubscribe(c, in.BoardID)
+		case typePresence:
+			h.hub.setPresence(c, in)
+		default:
+			c.queue(outbound{Type: typeError, Error: errInvalidMessageType})
+		}
+	})
+	if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+		h.log.Debugf("websocket: read error: %v", err)
+	}
+
+	h.hub.disconnect(c)
+	cancel()
+	<-written
+}
+
+// originChecker returns the function that checks the origin of the upgrade requests.
+// The requests without the origin come from the non-browser clients and are allowed
+func originChecker(allowedOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		if strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+		origin = strings.ToLower(origin)
+		for _, allowed := range allowedOrigins {
+			if allowed == "" {
+				continue
+			}
+			if allowed == "*" || matchOrigin(strings.ToLower(allowed), origin) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// matchOrigin reports if the origin matches the allowed one, that may contain
+// a single wildcard
+func matchOrigin(allowed, origin string) bool {
+	i := strings.IndexByte(allowed, '*')
+	if i < 0 {
+		return allowed == origin
+	}
+	prefix, suffix := allowed[:i], allowed[i+1:]
+
+	return len(origin) >= len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
+}
@@ -0,0 +1,219 @@
+// +build unit
+
+package ws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	john = &models.User{Model: models.Model{ID: 1}, Name: "John"}
+	jane = &models.User{Model: models.Model{ID: 2}, Name: "Jane"}
+)
+
+// serverStub returns the test server of the handler that authenticates the users by
+// the user query parameter
+func serverStub(t *testing.T, service EventService, origins ...string) (*Handler, *httptest.Server) {
+	logger := new(LoggerMock)
+	logger.On("Debugf", mock.Anything, mock.Anything).Return()
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+	handler := NewHandler(service, logger, origins)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := john
+		if r.URL.Query().Get("user") == "jane" {
+			user = jane
+		}
+		handler.Serve(w, r.WithContext(services.WithUser(r.Context(), user)))
+	}))
+	t.Cleanup(server.Close)
+
+	return handler, server
+}
+
+// dial connects to the test server as the provided user
+func dial(t *testing.T, server *httptest.Server, user string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?user="+user, nil)
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+// receive reads the next message from the connection
+func receive(t *testing.T, conn *websocket.Conn) outbound {
+	var msg outbound
+	require.Nil(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.Nil(t, conn.ReadJSON(&msg))
+
+	return msg
+}
+
+// streamStub returns the service that subscribes to the board with the provided ID
+// to the returned events stream
+func streamStub(boardID uint) (*EventServiceMock, chan models.Event) {
+	events := make(chan models.Event, 10)
+	service := new(EventServiceMock)
+	service.On("Subscribe", mock.Anything, boardID, uint(0)).Return((<-chan models.Event)(events), nil)
+
+	return service, events
+}
+
+func TestHandler_Subscribe(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		service, events := streamStub(2)
+		_, server := serverStub(t, service)
+		conn := dial(t, server, "john")
+
+		require.Nil(t, conn.WriteJSON(inbound{Type: typeSubscribe, BoardID: 2}))
+		assert.Equal(t, outbound{Type: typeSubscribed, BoardID: 2}, receive(t, conn))
+
+		events <- models.Event{ID: 5, Type: "task.created", BoardID: 2}
+		msg := receive(t, conn)
+		assert.Equal(t, typeEvent, msg.Type)
+		assert.Equal(t, uint(5), msg.Event.ID)
+
+		require.Nil(t, conn.WriteJSON(inbound{Type: typeUnsubscribe, BoardID: 2}))
+		assert.Equal(t, outbound{Type: typeUnsubscribed, BoardID: 2}, receive(t, conn))
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		service, events := streamStub(2)
+		_, server := serverStub(t, service)
+		conn := dial(t, server, "john")
+
+		require.Nil(t, conn.WriteJSON(inbound{Type: typeSubscribe, BoardID: 2}))
+		assert.Equal(t, typeSubscribed, receive(t, conn).Type)
+		close(events)
+		assert.Equal(t, outbound{Type: typeUnsubscribed, BoardID: 2}, receive(t, conn))
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		service := new(EventServiceMock)
+		service.On("Subscribe", mock.Anything, uint(3), uint(0)).Return((<-chan models.Event)(nil), services.ErrForbidden)
+		service.On("Subscribe", mock.Anything, uint(4), uint(0)).Return((<-chan models.Event)(nil), services.ErrRecordNotFound)
+		_, server := serverStub(t, service)
+		conn := dial(t, server, "john")
+
+		require.Nil(t, conn.WriteJSON(inbound{Type: typeSubscribe, BoardID: 3}))
+		assert.Equal(t, outbound{Type: typeError, BoardID: 3, Error: services.ErrForbidden.Error()}, receive(t, conn))
+		require.Nil(t, conn.WriteJSON(inbound{Type: typeSubscribe, BoardID: 4}))
+		assert.Equal(t, outbound{Type: typeError, BoardID: 4, Error: errNotFound}, receive(t, conn))
+	})
+
+	t.Run("invalid_messages", func(t *testing.T) {
+		_, server := serverStub(t, new(EventServiceMock))
+		conn := dial(t, server, "john")
+
+		require.Nil(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+		assert.Equal(t, outbound{Type: typeError, Error: errInvalidJSON}, receive(t, conn))
+		require.Nil(t, conn.WriteJSON(inbound{Type: "unknown"}))
+		assert.Equal(t, outbound{Type: typeError, Error: errInvalidMessageType}, receive(t, conn))
+		require.Nil(t, conn.WriteJSON(inbound{Type: typePresence, BoardID: 2, Activity: models.PresenceViewing}))
+		assert.Equal(t, outbound{Type: typeError, BoardID: 2, Error: errNotSubscribed}, receive(t, conn))
+		require.Nil(t, conn.WriteJSON(inbound{Type: typePresence, BoardID: 2, Activity: "sleeping"}))
+		assert.Equal(t, outbound{Type: typeError, BoardID: 2, Error: errInvalidPresence}, receive(t, conn))
+	})
+}
+
+func TestHandler_Presence(t *testing.T) {
+	service, _ := streamStub(2)
+	handler, server := serverStub(t, service)
+	handler.hub.presenceTTL = 200 * time.Millisecond
+
+	first := dial(t, server, "john")
+	require.Nil(t, first.WriteJSON(inbound{Type: typeSubscribe, BoardID: 2}))
+	assert.Equal(t, typeSubscribed, receive(t, first).Type)
+	require.Nil(t, first.WriteJSON(inbound{Type: typePresence, BoardID: 2, TaskID: 12, Activity: models.PresenceViewing}))
+
+	second := dial(t, server, "jane")
+	require.Nil(t, second.WriteJSON(inbound{Type: typeSubscribe, BoardID: 2}))
+	msg := receive(t, second)
+	assert.Equal(t, typeSubscribed, msg.Type)
+	require.Len(t, msg.Presence, 1)
+	assert.Equal(t, "John", msg.Presence[0].Name)
+	assert.Equal(t, uint(12), msg.Presence[0].TaskID)
+	assert.Equal(t, models.PresenceViewing, msg.Presence[0].Activity)
+
+	t.Run("broadcast", func(t *testing.T) {
+		require.Nil(t, second.WriteJSON(inbound{
+			Type: typePresence, BoardID: 2, TaskID: 12, Activity: models.PresenceEditing, Field: "description",
+		}))
+		msg := receive(t, first)
+		assert.Equal(t, typePresence, msg.Type)
+		require.Len(t, msg.Presence, 1)
+		assert.Equal(t, uint(2), msg.Presence[0].UserID)
+		assert.Equal(t, "Jane", msg.Presence[0].Name)
+		assert.Equal(t, models.PresenceEditing, msg.Presence[0].Activity)
+		assert.Equal(t, "description", msg.Presence[0].Field)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		msg := receive(t, second)
+		require.Len(t, msg.Presence, 1)
+		assert.Equal(t, "John", msg.Presence[0].Name)
+		assert.Equal(t, models.PresenceLeft, msg.Presence[0].Activity)
+	})
+
+	t.Run("disconnected", func(t *testing.T) {
+		require.Nil(t, first.WriteJSON(inbound{Type: typePresence, BoardID: 2, Activity: models.PresenceViewing}))
+		msg := receive(t, second)
+		assert.Equal(t, models.PresenceViewing, msg.Presence[0].Activity)
+
+		require.Nil(t, first.Close())
+		msg = receive(t, second)
+		require.Len(t, msg.Presence, 1)
+		assert.Equal(t, "John", msg.Presence[0].Name)
+		assert.Equal(t, models.PresenceLeft, msg.Presence[0].Activity)
+	})
+}
+
+func TestHandler_Origin(t *testing.T) {
+	_, server := serverStub(t, new(EventServiceMock), "", "https://*.example.com")
+	URL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	tests := []struct {
+		name   string
+		origin string
+		ok     bool
+	}{
+		{"no_origin", "", true},
+		{"same_host", server.URL, true},
+		{"allowed", "https://app.example.com", true},
+		{"not_allowed", "https://example.org", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.origin != "" {
+				header.Set("Origin", tt.origin)
+			}
+			conn, resp, err := websocket.DefaultDialer.Dial(URL, header)
+			if tt.ok {
+				require.Nil(t, err)
+				_ = conn.Close()
+				return
+			}
+			assert.Equal(t, websocket.ErrBadHandshake, err)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		})
+	}
+}
+
+func TestMatchOrigin(t *testing.T) {
+	assert.True(t, matchOrigin("https://example.com", "https://example.com"))
+	assert.True(t, matchOrigin("https://*.example.com", "https://app.example.com"))
+	assert.False(t, matchOrigin("https://*.example.com", "https://example.com"))
+	assert.False(t, matchOrigin("https://*.example.com", "https://app.example.org"))
+}
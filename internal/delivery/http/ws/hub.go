@@ -0,0 +1,243 @@
+package ws
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// maxFieldLength is the longest name of a field a presence may refer to
+const maxFieldLength = 64
+
+// hub keeps the clients subscribed to the boards along with their presence and
+// broadcasts the presence changes to the other clients of the boards
+type hub struct {
+	service     EventService
+	log         log.Logger
+	presenceTTL time.Duration
+
+	mu       sync.Mutex
+	sessions uint
+	boards   map[uint]map[*client]*presence
+}
+
+// presence is the presence of a client on a board, which expires unless it is
+// refreshed in time
+type presence struct {
+	models.Presence
+	expiry *time.Timer
+}
+
+// newHub is a hub constructor
+func newHub(service EventService, logger log.Logger, presenceTTL time.Duration) *hub {
+	return &hub{
+		service:     service,
+		log:         logger,
+		presenceTTL: presenceTTL,
+		boards:      make(map[uint]map[*client]*presence),
+	}
+}
+
+// session returns the ID of a new session
+func (h *hub) session() uint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessions++
+
+	return h.sessions
+}
+
+// subscribe will subscribe the client to the events of the board published after
+// the event with the provided ID and will send it the presence of the other clients
+// on the board. The events are forwarded to the client till it unsubscribes or the
+// subscription is revoked
+func (h *hub) subscribe(ctx context.Context, c *client, boardID, lastID uint) {
+	if c.subscribed(boardID) {
+		c.queue(outbound{Type: typeSubscribed, BoardID: boardID, Presence: h.roster(boardID)})
+		return
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	events, err := h.service.Subscribe(subCtx, boardID, lastID)
+	if err != nil {
+		cancel()
+		c.queue(outbound{Type: typeError, BoardID: boardID, Error: h.subscriptionError(boardID, err)})
+		return
+	}
+	c.track(boardID, cancel)
+
+	h.mu.Lock()
+	if h.boards[boardID] == nil {
+		h.boards[boardID] = make(map[*client]*presence)
+	}
+	h.boards[boardID][c] = nil
+	c.queue(outbound{Type: typeSubscribed, BoardID: boardID, Presence: h.rosterLocked(boardID)})
+	h.mu.Unlock()
+
+	go func() {
+		for event := range events {
+			event := event
+			c.queue(outbound{Type: typeEvent, BoardID: boardID, Event: &event})
+		}
+		if subCtx.Err() == nil {
+			// the subscription has been revoked or the client has fallen behind
+			h.unsubscribe(c, boardID)
+		}
+	}()
+}
+
+// subscriptionError returns the message of the error of the subscription to the board
+func (h *hub) subscriptionError(boardID uint, err error) string {
+	switch {
+	case errors.Is(err, services.ErrRecordNotFound):
+		h.log.Debugf("resource was not found %d", boardID)
+		return errNotFound
+	case errors.Is(err, services.ErrForbidden):
+		h.log.Debugf("access error: %v", err)
+		return err.Error()
+	default:
+		h.log.Errorf("error while subscribing to events: %v", err)
+		return errInternalServer
+	}
+}
+
+// unsubscribe will stop the subscription of the client to the board and will let
+// the other clients know that the client has left it
+func (h *hub) unsubscribe(c *client, boardID uint) {
+	if !c.untrack(boardID) {
+		return
+	}
+	h.leave(c, boardID)
+	c.queue(outbound{Type: typeUnsubscribed, BoardID: boardID})
+}
+
+// disconnect will stop all the subscriptions of the client
+func (h *hub) disconnect(c *client) {
+	for _, boardID := range c.untrackAll() {
+		h.leave(c, boardID)
+	}
+}
+
+// leave will forget the client subscribed to the board and will broadcast that
+// the client has left the board if it has been present on it
+func (h *hub) leave(c *client, boardID uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients := h.boards[boardID]
+	p, ok := clients[c]
+	if !ok {
+		return
+	}
+	delete(clients, c)
+	if len(clients) == 0 {
+		delete(h.boards, boardID)
+	}
+	if p != nil {
+		p.expiry.Stop()
+		h.broadcastLocked(boardID, c, left(p.Presence))
+	}
+}
+
+// setPresence will update the presence of the client on the board it is subscribed
+// to and will broadcast it to the other clients on the board. The presence expires
+// if it is not refreshed within the presence TTL
+func (h *hub) setPresence(c *client, in inbound) {
+	switch {
+	case in.Activity != models.PresenceViewing && in.Activity != models.PresenceEditing && in.Activity != models.PresenceLeft,
+		len(in.Field) > maxFieldLength:
+		c.queue(outbound{Type: typeError, BoardID: in.BoardID, Error: errInvalidPresence})
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients := h.boards[in.BoardID]
+	current, ok := clients[c]
+	if !ok {
+		c.queue(outbound{Type: typeError, BoardID: in.BoardID, Error: errNotSubscribed})
+		return
+	}
+	if current != nil {
+		current.expiry.Stop()
+		clients[c] = nil
+	}
+	if in.Activity == models.PresenceLeft {
+		if current != nil {
+			h.broadcastLocked(in.BoardID, c, left(current.Presence))
+		}
+		return
+	}
+
+	updated := &presence{Presence: models.Presence{
+		Session:   c.session,
+		BoardID:   in.BoardID,
+		UserID:    c.user.ID,
+		Name:      c.user.Name,
+		TaskID:    in.TaskID,
+		Activity:  in.Activity,
+		Field:     in.Field,
+		UpdatedAt: time.Now().UTC(),
+	}}
+	updated.expiry = time.AfterFunc(h.presenceTTL, func() { h.expire(c, updated) })
+	clients[c] = updated
+	h.broadcastLocked(in.BoardID, c, updated.Presence)
+}
+
+// expire will remove the presence of the client unless it has been refreshed
+func (h *hub) expire(c *client, p *presence) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if clients := h.boards[p.BoardID]; clients[c] == p {
+		clients[c] = nil
+		h.broadcastLocked(p.BoardID, c, left(p.Presence))
+	}
+}
+
+// roster returns the presence of the clients on the board
+func (h *hub) roster(boardID uint) []models.Presence {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.rosterLocked(boardID)
+}
+
+// rosterLocked returns the presence of the clients on the board ordered by the
+// sessions, the hub must be locked
+func (h *hub) rosterLocked(boardID uint) []models.Presence {
+	roster := make([]models.Presence, 0)
+	for _, p := range h.boards[boardID] {
+		if p != nil {
+			roster = append(roster, p.Presence)
+		}
+	}
+	sort.Slice(roster, func(i, j int) bool { return roster[i].Session < roster[j].Session })
+
+	return roster
+}
+
+// broadcastLocked will send the presence to the clients on the board except for
+// the provided one, the hub must be locked
+func (h *hub) broadcastLocked(boardID uint, from *client, p models.Presence) {
+	for c := range h.boards[boardID] {
+		if c != from {
+			c.queue(outbound{Type: typePresence, BoardID: boardID, Presence: []models.Presence{p}})
+		}
+	}
+}
+
+// left returns the presence of the user that has left the board
+func left(p models.Presence) models.Presence {
+	p.TaskID, p.Field = 0, ""
+	p.Activity, p.UpdatedAt = models.PresenceLeft, time.Now().UTC()
+
+	return p
+}
@@ -0,0 +1,12 @@
+package ws
+
+import (
+	"context"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+)
+
+// EventService provides an interface for the board events service layer
+type EventService interface {
+	Subscribe(ctx context.Context, boardID, lastID uint) (<-chan m.Event, error)
+}
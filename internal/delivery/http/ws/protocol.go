@@ -0,0 +1,49 @@
+package ws
+
+import (
+	"github.com/dnozdrin/detask/internal/domain/models"
+)
+
+// messageType is the type of the messages exchanged over a connection
+type messageType string
+
+const (
+	// the messages sent by the clients
+	typeSubscribe   messageType = "subscribe"
+	typeUnsubscribe messageType = "unsubscribe"
+	typePresence    messageType = "presence"
+
+	// the messages sent by the server, the presence messages are sent both ways
+	typeSubscribed   messageType = "subscribed"
+	typeUnsubscribed messageType = "unsubscribed"
+	typeEvent        messageType = "event"
+	typeError        messageType = "error"
+)
+
+const (
+	errInvalidJSON        = "invalid JSON payload"
+	errInvalidMessageType = "invalid message type"
+	errInvalidPresence    = "invalid presence"
+	errNotSubscribed      = "the board is not subscribed to"
+	errNotFound           = "resource was not found"
+	errInternalServer     = "internal server error"
+)
+
+// inbound is a message sent by a client
+type inbound struct {
+	Type      messageType             `json:"type"`
+	BoardID   uint                    `json:"board"`
+	LastEvent uint                    `json:"last_event,omitempty"`
+	TaskID    uint                    `json:"task,omitempty"`
+	Activity  models.PresenceActivity `json:"activity,omitempty"`
+	Field     string                  `json:"field,omitempty"`
+}
+
+// outbound is a message sent to a client
+type outbound struct {
+	Type     messageType       `json:"type"`
+	BoardID  uint              `json:"board,omitempty"`
+	Event    *models.Event     `json:"event,omitempty"`
+	Presence []models.Presence `json:"presence,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
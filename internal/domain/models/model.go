@@ -1,41 +1,747 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
 
-// Model represents the default fields for persisted structures
+	"github.com/pkg/errors"
+)
+
+// Model represents the default fields for persisted structures. The version
+// of a record is bumped on every change of the record, the records that are
+// not versioned have the zero version.
 type Model struct {
 	ID        uint      `json:"id"`
 	CreatedAt time.Time `json:"-"`
 	UpdatedAt time.Time `json:"-"`
+	Version   uint      `json:"-"`
 }
 
-// Board represents a board (project)
+// Board represents a board (project). A board flagged as a template can be
+// referenced on a board creation to copy its columns, the reference is kept
+// as the template ID of the new board.
 type Board struct {
 	Model
 	Name        string `json:"name" validate:"required,max=500,min=1"`
 	Description string `json:"description" validate:"required,max=1000"`
+	CreatedBy   uint   `json:"created_by"`
+	Template    bool   `json:"template"`
+	TemplateID  uint   `json:"template_id" validate:"omitempty,numeric"`
 }
 
-// Column represents a column (status)
+const (
+	// CloneColumns copies the columns of the board only
+	CloneColumns = "columns"
+	// CloneTasks copies the columns of the board along with its labels, tasks and comments
+	CloneTasks = "tasks"
+)
+
+// BoardClone represents a request to copy a board. The columns of the board are
+// always copied, its labels, tasks and their comments are copied along with them
+// if the tasks are included. The name and the description of the original board
+// are used unless provided.
+type BoardClone struct {
+	Name        string `json:"name" validate:"max=500"`
+	Description string `json:"description" validate:"max=1000"`
+	Include     string `json:"include" validate:"omitempty,oneof=columns tasks"`
+}
+
+// Column represents a column (status). A column can not hold more tasks than
+// its work in progress limit, zero means no limit.
 type Column struct {
 	Model
 	Name     string  `json:"name" validate:"required,max=255,min=1"`
 	BoardID  uint    `json:"board" validate:"required,numeric"`
 	Position float64 `json:"position" validate:"required,numeric"`
+	WIPLimit uint    `json:"wip_limit" validate:"max=1000"`
+}
+
+// ColumnMove represents a request to move a column right before or right after
+// another column of its board. The column is moved to the end of the board if
+// neither of them is provided.
+type ColumnMove struct {
+	BeforeID uint `json:"before" validate:"omitempty,excludedfield=AfterID"`
+	AfterID  uint `json:"after"`
+}
+
+// ColumnOrder represents the full list of the columns of a board in the new order
+type ColumnOrder struct {
+	ColumnIDs []uint `json:"columns" validate:"required"`
 }
 
 // Task represents a task
 type Task struct {
 	Model
-	Name        string  `json:"name" validate:"required,max=500,min=1"`
-	Description string  `json:"description" validate:"required,max=5000"`
-	ColumnID    uint    `json:"column" validate:"required,numeric"`
-	Position    float64 `json:"position" validate:"required,numeric"`
+	Name        string     `json:"name" validate:"required,max=500,min=1"`
+	Description string     `json:"description" validate:"required,max=5000"`
+	ColumnID    uint       `json:"column" validate:"required,numeric"`
+	Position    float64    `json:"position" validate:"required,numeric"`
+	CreatedBy   uint       `json:"created_by"`
+	ReporterID  uint       `json:"reporter"`
+	Assignees   []uint     `json:"assignees" validate:"max=20,unique,dive,required"`
+	Labels      []uint     `json:"labels" validate:"max=20,unique,dive,required"`
+	StartAt     *time.Time `json:"start_at"`
+	DueAt       *time.Time `json:"due_at" validate:"omitempty,afterfield=StartAt"`
+	Priority    Priority   `json:"priority" validate:"omitempty,oneof=lowest low medium high highest"`
+}
+
+// TaskMove represents a request to move a task to the column, right before or
+// right after another task of the column. The task is moved to the end of the
+// column if neither of them is provided.
+type TaskMove struct {
+	ColumnID uint `json:"column" validate:"required,numeric"`
+	BeforeID uint `json:"before" validate:"omitempty,excludedfield=AfterID"`
+	AfterID  uint `json:"after"`
+}
+
+// TaskTransfer represents a request to move a task to a column of another board
+type TaskTransfer struct {
+	ColumnID uint `json:"column" validate:"required,numeric"`
+}
+
+// Priority represents the urgency of a task
+type Priority string
+
+const (
+	// PriorityLowest is for tasks that may be done some day
+	PriorityLowest Priority = "lowest"
+	// PriorityLow is for tasks that may wait
+	PriorityLow Priority = "low"
+	// PriorityMedium is the default priority of tasks
+	PriorityMedium Priority = "medium"
+	// PriorityHigh is for tasks that should be done next
+	PriorityHigh Priority = "high"
+	// PriorityHighest is for tasks that should be done right away
+	PriorityHighest Priority = "highest"
+)
+
+// priorities lists the priorities in ascending order, the index of the
+// priority is its rank
+var priorities = []Priority{"", PriorityLowest, PriorityLow, PriorityMedium, PriorityHigh, PriorityHighest}
+
+// Rank returns the position of the priority in ascending order starting
+// from 1, or 0 if the priority is unknown
+func (p Priority) Rank() int {
+	for rank := 1; rank < len(priorities); rank++ {
+		if priorities[rank] == p {
+			return rank
+		}
+	}
+
+	return 0
+}
+
+// Value stores the priority as its rank, so that the tasks can be sorted by it
+func (p Priority) Value() (driver.Value, error) {
+	return int64(p.Rank()), nil
+}
+
+// Scan restores the priority from its rank
+func (p *Priority) Scan(src interface{}) error {
+	rank, ok := src.(int64)
+	if !ok || rank < 0 || rank >= int64(len(priorities)) {
+		return errors.Errorf("invalid priority rank: %v", src)
+	}
+	*p = priorities[rank]
+
+	return nil
+}
+
+// Label represents a label of tasks from the catalog of a board
+type Label struct {
+	Model
+	Name    string `json:"name" validate:"required,max=50,min=1"`
+	Color   string `json:"color" validate:"required,hexcolor"`
+	BoardID uint   `json:"board" validate:"required,numeric"`
 }
 
 // Comment represents a comment to a task
 type Comment struct {
 	Model
-	Text   string `json:"text" validate:"required,max=5000,min=1"`
-	TaskID uint   `json:"task" validate:"required,numeric"`
+	Text      string `json:"text" validate:"required,max=5000,min=1"`
+	TaskID    uint   `json:"task" validate:"required,numeric"`
+	CreatedBy uint   `json:"created_by"`
+}
+
+// User represents a user of the API
+type User struct {
+	Model
+	Email        string `json:"email" validate:"required,email,max=255"`
+	Name         string `json:"name" validate:"required,max=255,min=1"`
+	Password     string `json:"password,omitempty" validate:"required,min=8,max=72"`
+	PasswordHash string `json:"-"`
+}
+
+// Credentials represents the data required for a user authentication
+type Credentials struct {
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// Role represents a level of access of a user to a board and its records
+type Role string
+
+const (
+	// RoleViewer allows to read the board and all its records
+	RoleViewer Role = "viewer"
+	// RoleEditor allows to read the board and to change its tasks and comments
+	RoleEditor Role = "editor"
+	// RoleOwner allows to change the board itself, its columns and members
+	RoleOwner Role = "owner"
+)
+
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// Allows reports if the role grants at least the access level of the required one
+func (r Role) Allows(required Role) bool {
+	rank, ok := roleRanks[r]
+
+	return ok && rank >= roleRanks[required]
+}
+
+// Member represents a membership of a user on a board
+type Member struct {
+	BoardID uint `json:"board"`
+	UserID  uint `json:"user" validate:"required,numeric"`
+	Role    Role `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+// TrashType is the type of a deleted record
+type TrashType string
+
+const (
+	// TrashBoard is the type of deleted boards
+	TrashBoard TrashType = "board"
+	// TrashColumn is the type of deleted columns
+	TrashColumn TrashType = "column"
+	// TrashTask is the type of deleted tasks
+	TrashTask TrashType = "task"
+	// TrashComment is the type of deleted comments
+	TrashComment TrashType = "comment"
+)
+
+// Valid reports if the trash type is known
+func (t TrashType) Valid() bool {
+	switch t {
+	case TrashBoard, TrashColumn, TrashTask, TrashComment:
+		return true
+	}
+
+	return false
+}
+
+// TrashItem represents a deleted record that may be restored until it is purged.
+// The name of a deleted comment is its text, the parent is the board of a column,
+// the column of a task or the task of a comment.
+type TrashItem struct {
+	Type      TrashType `json:"type"`
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	BoardID   uint      `json:"board"`
+	ParentID  uint      `json:"parent"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Entity is the type of a record the activity history is kept for
+type Entity string
+
+const (
+	// EntityBoard is the type of boards
+	EntityBoard Entity = "board"
+	// EntityColumn is the type of columns
+	EntityColumn Entity = "column"
+	// EntityTask is the type of tasks
+	EntityTask Entity = "task"
+	// EntityComment is the type of comments
+	EntityComment Entity = "comment"
+	// EntityLabel is the type of labels
+	EntityLabel Entity = "label"
+	// EntityMember is the type of board members, the ID of a member is the ID of the user
+	EntityMember Entity = "member"
+)
+
+// Valid reports if the entity type is known
+func (e Entity) Valid() bool {
+	switch e {
+	case EntityBoard, EntityColumn, EntityTask, EntityComment, EntityLabel, EntityMember:
+		return true
+	}
+
+	return false
+}
+
+// Action is the kind of a change recorded in the activity history
+type Action string
+
+const (
+	// ActionCreate is recorded when a record is created
+	ActionCreate Action = "create"
+	// ActionUpdate is recorded when a record is updated
+	ActionUpdate Action = "update"
+	// ActionDelete is recorded when a record is deleted
+	ActionDelete Action = "delete"
+	// ActionMove is recorded when a column or a task changes its position
+	ActionMove Action = "move"
+	// ActionTransfer is recorded when a task is moved to another board
+	ActionTransfer Action = "transfer"
+	// ActionRestore is recorded when a record is restored from the trash
+	ActionRestore Action = "restore"
+)
+
+// Change holds the values of a field before and after a change, a missing
+// value is null
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Changes maps the names of the changed fields to their values
+type Changes map[string]Change
+
+// Value stores the changes as a JSON object
+func (c Changes) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(c)
+
+	return string(data), err
+}
+
+// Scan restores the changes from a JSON object
+func (c *Changes) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, c)
+	case string:
+		return json.Unmarshal([]byte(value), c)
+	default:
+		return errors.Errorf("invalid changes: %v", src)
+	}
+}
+
+// Activity represents an entry of the activity history of a board. An entry on
+// a task or on a comment refers to the task as well. The changes of a created
+// record are from null, the changes of a deleted one are to null.
+type Activity struct {
+	ID        uint      `json:"id"`
+	BoardID   uint      `json:"board"`
+	TaskID    uint      `json:"task,omitempty"`
+	Entity    Entity    `json:"entity"`
+	EntityID  uint      `json:"entity_id"`
+	Action    Action    `json:"action"`
+	ActorID   uint      `json:"actor"`
+	Changes   Changes   `json:"changes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SearchHit represents a task or a comment matching a search query along with the
+// snippet of its text with the matched words highlighted. The task of a comment hit
+// is the commented task and the name is the name of the task. The hits with higher
+// ranks are more relevant to the query.
+type SearchHit struct {
+	Type     Entity  `json:"type"`
+	ID       uint    `json:"id"`
+	TaskID   uint    `json:"task"`
+	ColumnID uint    `json:"column"`
+	BoardID  uint    `json:"board"`
+	Name     string  `json:"name"`
+	Snippet  string  `json:"snippet"`
+	Rank     float64 `json:"rank"`
+}
+
+// EventType is the type of a change event, the changed entity and the action in
+// the past tense, such as task.created or column.deleted
+type EventType string
+
+// pastTenses maps the actions to their past tenses used in the event types
+var pastTenses = map[Action]string{
+	ActionCreate:   "created",
+	ActionUpdate:   "updated",
+	ActionDelete:   "deleted",
+	ActionMove:     "moved",
+	ActionTransfer: "transferred",
+	ActionRestore:  "restored",
+}
+
+// NewEventType returns the type of the events of the action on the entity
+func NewEventType(entity Entity, action Action) EventType {
+	return EventType(string(entity) + "." + pastTenses[action])
+}
+
+// Valid reports if the event type consists of a known entity and a known action
+func (t EventType) Valid() bool {
+	parts := strings.SplitN(string(t), ".", 2)
+	if len(parts) != 2 || !Entity(parts[0]).Valid() {
+		return false
+	}
+	for _, pastTense := range pastTenses {
+		if parts[1] == pastTense {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EventTypes is a list of event types
+type EventTypes []EventType
+
+// Value stores the event types as a JSON array
+func (t EventTypes) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(t)
+
+	return string(data), err
+}
+
+// Scan restores the event types from a JSON array
+func (t *EventTypes) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, t)
+	case string:
+		return json.Unmarshal([]byte(value), t)
+	default:
+		return errors.Errorf("invalid event types: %v", src)
+	}
+}
+
+// Contains reports if the list is empty or contains the provided event type
+func (t EventTypes) Contains(eventType EventType) bool {
+	if len(t) == 0 {
+		return true
+	}
+	for _, listed := range t {
+		if listed == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event represents a committed change of a board or of its columns, labels,
+// members, tasks and comments. The data of an event is the changed record, the
+// deleted record for deletions. The IDs of the events grow in the order of their
+// publication. The rules of an event are the automation rules which actions have
+// led to the change, in the order they have run. The consumers of an event are
+// the names of the outbox consumers that have accepted it, and the attempts are
+// the number of the relays it has failed, both are not published.
+type Event struct {
+	ID        uint            `json:"id"`
+	Type      EventType       `json:"type"`
+	BoardID   uint            `json:"board"`
+	TaskID    uint            `json:"task,omitempty"`
+	Entity    Entity          `json:"entity"`
+	EntityID  uint            `json:"entity_id"`
+	ActorID   uint            `json:"actor"`
+	Data      json.RawMessage `json:"data"`
+	Changes   Changes         `json:"changes,omitempty"`
+	Rules     []uint          `json:"rules,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Consumers []string        `json:"-"`
+	Attempts  uint            `json:"-"`
+}
+
+// PresenceActivity is what a user is doing on a board
+type PresenceActivity string
+
+const (
+	// PresenceViewing is the activity of a user viewing a board or a task of it
+	PresenceViewing PresenceActivity = "viewing"
+	// PresenceEditing is the activity of a user editing a field of a task
+	PresenceEditing PresenceActivity = "editing"
+	// PresenceLeft is the activity of a user that has left a board or has been idle
+	// for too long
+	PresenceLeft PresenceActivity = "left"
+)
+
+// Presence represents the activity of a user on a board within a collaboration
+// session, a user has a session per connection
+type Presence struct {
+	Session   uint             `json:"session"`
+	BoardID   uint             `json:"board"`
+	UserID    uint             `json:"user"`
+	Name      string           `json:"name"`
+	TaskID    uint             `json:"task,omitempty"`
+	Activity  PresenceActivity `json:"activity"`
+	Field     string           `json:"field,omitempty"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// Webhook represents a subscription of an external URL to the events of a board.
+// A webhook receives the events of the listed types, or all the events if none
+// are listed, unless it is disabled. The deliveries are signed with the secret,
+// which is generated unless provided and is returned on the creation only.
+type Webhook struct {
+	Model
+	BoardID  uint       `json:"board"`
+	URL      string     `json:"url" validate:"required,url,startswith=http,max=2000"`
+	Secret   string     `json:"secret,omitempty" validate:"omitempty,min=16,max=255"`
+	Events   EventTypes `json:"events" validate:"max=50,unique"`
+	Disabled bool       `json:"disabled"`
+}
+
+// DeliveryStatus is the state of a delivery of an event to a webhook
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is the status of a delivery waiting for its next attempt
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded is the status of a delivery accepted by the webhook
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryFailed is the status of a delivery that has run out of attempts
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Delivery represents a delivery of an event to a webhook. A failed attempt is
+// retried with an exponential backoff till the delivery succeeds or runs out of
+// attempts, the response code and the error of the last attempt are kept. The
+// payload is the delivered event, the event ID is set for the deliveries of the
+// relayed events only, as a webhook gets a single delivery of such an event.
+type Delivery struct {
+	ID            uint            `json:"id"`
+	WebhookID     uint            `json:"webhook"`
+	EventID       uint            `json:"event_id,omitempty"`
+	EventType     EventType       `json:"event"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        DeliveryStatus  `json:"status"`
+	Attempts      uint            `json:"attempts"`
+	ResponseCode  int             `json:"response_code,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// TriggerType is the kind of the changes of the tasks that run an automation rule
+type TriggerType string
+
+const (
+	// TriggerTaskCreated runs a rule once a task is created
+	TriggerTaskCreated TriggerType = "task.created"
+	// TriggerTaskMoved runs a rule once a task is moved into the column of the
+	// trigger, or into any column if there is none
+	TriggerTaskMoved TriggerType = "task.moved"
+	// TriggerTaskOverdue runs a rule once the due date of a task has passed
+	TriggerTaskOverdue TriggerType = "task.overdue"
+	// TriggerCommentCreated runs a rule once a comment is added to a task
+	TriggerCommentCreated TriggerType = "comment.created"
+)
+
+// Valid reports if the trigger type is known
+func (t TriggerType) Valid() bool {
+	switch t {
+	case TriggerTaskCreated, TriggerTaskMoved, TriggerTaskOverdue, TriggerCommentCreated:
+		return true
+	}
+
+	return false
+}
+
+// RuleTrigger is the change of a task that runs an automation rule
+type RuleTrigger struct {
+	Type     TriggerType `json:"type"`
+	ColumnID uint        `json:"column,omitempty"`
+}
+
+// Value stores the trigger as a JSON object
+func (t RuleTrigger) Value() (driver.Value, error) {
+	data, err := json.Marshal(t)
+
+	return string(data), err
+}
+
+// Scan restores the trigger from a JSON object
+func (t *RuleTrigger) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, t)
+	case string:
+		return json.Unmarshal([]byte(value), t)
+	default:
+		return errors.Errorf("invalid rule trigger: %v", src)
+	}
+}
+
+// ConditionType is the kind of the checks of the task an automation rule has
+// been triggered by
+type ConditionType string
+
+const (
+	// ConditionLabel requires the task to have the label
+	ConditionLabel ConditionType = "label"
+	// ConditionAssignee requires the user to be assigned to the task
+	ConditionAssignee ConditionType = "assignee"
+	// ConditionColumn requires the task to be in the column
+	ConditionColumn ConditionType = "column"
+)
+
+// RuleCondition is a check of the task an automation rule has been triggered by,
+// the field of the condition type refers to the required record
+type RuleCondition struct {
+	Type     ConditionType `json:"type"`
+	LabelID  uint          `json:"label,omitempty"`
+	UserID   uint          `json:"user,omitempty"`
+	ColumnID uint          `json:"column,omitempty"`
+}
+
+// Met reports if the task meets the condition
+func (c RuleCondition) Met(task Task) bool {
+	switch c.Type {
+	case ConditionLabel:
+		return containsID(task.Labels, c.LabelID)
+	case ConditionAssignee:
+		return containsID(task.Assignees, c.UserID)
+	case ConditionColumn:
+		return task.ColumnID == c.ColumnID
+	}
+
+	return false
+}
+
+// RuleConditions is a list of the conditions that all have to be met
+type RuleConditions []RuleCondition
+
+// Value stores the conditions as a JSON array
+func (c RuleConditions) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(c)
+
+	return string(data), err
+}
+
+// Scan restores the conditions from a JSON array
+func (c *RuleConditions) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, c)
+	case string:
+		return json.Unmarshal([]byte(value), c)
+	default:
+		return errors.Errorf("invalid rule conditions: %v", src)
+	}
+}
+
+// Met reports if the task meets all the conditions
+func (c RuleConditions) Met(task Task) bool {
+	for _, condition := range c {
+		if !condition.Met(task) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ActionType is the kind of the changes an automation rule makes
+type ActionType string
+
+const (
+	// ActionTypeMove moves the task to the end of the column
+	ActionTypeMove ActionType = "move"
+	// ActionTypeAssign assigns the user to the task
+	ActionTypeAssign ActionType = "assign"
+	// ActionTypeLabel adds the label to the task
+	ActionTypeLabel ActionType = "label"
+	// ActionTypeComment adds the comment with the text to the task
+	ActionTypeComment ActionType = "comment"
+	// ActionTypeWebhook posts the triggering event to the webhook of the board
+	ActionTypeWebhook ActionType = "webhook"
+)
+
+// RuleAction is a change an automation rule makes, the field of the action type
+// refers to the record or holds the text the change is made with
+type RuleAction struct {
+	Type      ActionType `json:"type"`
+	ColumnID  uint       `json:"column,omitempty"`
+	UserID    uint       `json:"user,omitempty"`
+	LabelID   uint       `json:"label,omitempty"`
+	Text      string     `json:"text,omitempty"`
+	WebhookID uint       `json:"webhook,omitempty"`
+}
+
+// RuleActions is a list of the actions that are taken in order
+type RuleActions []RuleAction
+
+// Value stores the actions as a JSON array
+func (a RuleActions) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(a)
+
+	return string(data), err
+}
+
+// Scan restores the actions from a JSON array
+func (a *RuleActions) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, a)
+	case string:
+		return json.Unmarshal([]byte(value), a)
+	default:
+		return errors.Errorf("invalid rule actions: %v", src)
+	}
+}
+
+// Rule represents an automation rule of a board: once a task of the board goes
+// through the change of the trigger and meets all the conditions, the actions
+// are taken on behalf of the author of the rule, unless it is disabled
+type Rule struct {
+	Model
+	BoardID    uint           `json:"board"`
+	Name       string         `json:"name" validate:"required,max=255,min=1"`
+	Trigger    RuleTrigger    `json:"trigger"`
+	Conditions RuleConditions `json:"conditions" validate:"max=20"`
+	Actions    RuleActions    `json:"actions" validate:"min=1,max=20"`
+	Disabled   bool           `json:"disabled"`
+	CreatedBy  uint           `json:"created_by"`
+}
+
+// ExecutionStatus is the result of a run of an automation rule
+type ExecutionStatus string
+
+const (
+	// ExecutionSucceeded is the status of a run that has taken all the actions
+	ExecutionSucceeded ExecutionStatus = "succeeded"
+	// ExecutionFailed is the status of a run stopped by a failed action
+	ExecutionFailed ExecutionStatus = "failed"
+	// ExecutionSkipped is the status of a run prevented to avoid a loop of rules
+	ExecutionSkipped ExecutionStatus = "skipped"
+)
+
+// RuleExecution represents an entry of the execution log of an automation rule,
+// the event is the one that has triggered the run, none for the overdue tasks
+type RuleExecution struct {
+	ID        uint            `json:"id"`
+	RuleID    uint            `json:"rule"`
+	EventID   uint            `json:"event,omitempty"`
+	TaskID    uint            `json:"task"`
+	Status    ExecutionStatus `json:"status"`
+	Error     string          `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// containsID reports if the IDs contain the provided one
+func containsID(IDs []uint, ID uint) bool {
+	for _, listed := range IDs {
+		if listed == ID {
+			return true
+		}
+	}
+
+	return false
 }
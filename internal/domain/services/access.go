@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+)
+
+// access verifies that the authenticated user has the required role
+// on the board the requested records belong to
+type access struct {
+	memberStorage MemberStorage
+}
+
+// userID will return the ID of the user carried by the provided context
+func (a access) userID(ctx context.Context) (uint, error) {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return 0, ErrUnauthenticated
+	}
+
+	return user.ID, nil
+}
+
+// onBoard will return nil if the user has the required role on the board with
+// the provided ID, ErrRecordNotFound if there is no such board or ErrForbidden
+func (a access) onBoard(ctx context.Context, boardID uint, required m.Role) error {
+	return a.check(ctx, required, func(userID uint) (m.Role, error) {
+		return a.memberStorage.FindRoleByBoard(boardID, userID)
+	})
+}
+
+// onColumn will return nil if the user has the required role on the board of the column
+// with the provided ID, ErrRecordNotFound if there is no such column or ErrForbidden
+func (a access) onColumn(ctx context.Context, columnID uint, required m.Role) error {
+	return a.check(ctx, required, func(userID uint) (m.Role, error) {
+		return a.memberStorage.FindRoleByColumn(columnID, userID)
+	})
+}
+
+// onTask will return nil if the user has the required role on the board of the task
+// with the provided ID, ErrRecordNotFound if there is no such task or ErrForbidden
+func (a access) onTask(ctx context.Context, taskID uint, required m.Role) error {
+	return a.check(ctx, required, func(userID uint) (m.Role, error) {
+		return a.memberStorage.FindRoleByTask(taskID, userID)
+	})
+}
+
+// onDeletedBoard will return nil if the user has the required role on the board with
+// the provided ID, which may be deleted, or ErrForbidden
+func (a access) onDeletedBoard(ctx context.Context, boardID uint, required m.Role) error {
+	return a.check(ctx, required, func(userID uint) (m.Role, error) {
+		members, err := a.memberStorage.Find(boardID)
+		if err != nil {
+			return "", err
+		}
+		for _, member := range members {
+			if member.UserID == userID {
+				return member.Role, nil
+			}
+		}
+
+		return "", nil
+	})
+}
+
+func (a access) check(ctx context.Context, required m.Role, findRole func(userID uint) (m.Role, error)) error {
+	userID, err := a.userID(ctx)
+	if err != nil {
+		return err
+	}
+
+	role, err := findRole(userID)
+	if err != nil {
+		return err
+	}
+	if !role.Allows(required) {
+		return ErrForbidden
+	}
+
+	return nil
+}
+
+// relation replaces ErrRecordNotFound with the provided relation error, so a
+// missing parent record of the created one is reported the same way as by storages
+func relation(err, relationErr error) error {
+	if err == ErrRecordNotFound {
+		return relationErr
+	}
+
+	return err
+}
@@ -0,0 +1,90 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	// testCtx carries the user the services are called by in tests
+	testCtx = WithUser(context.Background(), &m.User{Model: m.Model{ID: 1}})
+	// ownerAccess grants the owner role on any board to any user
+	ownerAccess = access{memberStorage: roleStorage(m.RoleOwner, nil)}
+)
+
+// roleStorage returns a members storage mock that finds the provided role
+// and error for any board, column and task
+func roleStorage(role m.Role, err error) *MockedMemberStorage {
+	memberStorage := new(MockedMemberStorage)
+	memberStorage.On("FindRoleByBoard", mock.Anything, mock.Anything).Return(role, err)
+	memberStorage.On("FindRoleByColumn", mock.Anything, mock.Anything).Return(role, err)
+	memberStorage.On("FindRoleByTask", mock.Anything, mock.Anything).Return(role, err)
+
+	return memberStorage
+}
+
+func TestRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     m.Role
+		required m.Role
+		allows   bool
+	}{
+		{m.RoleOwner, m.RoleOwner, true},
+		{m.RoleOwner, m.RoleViewer, true},
+		{m.RoleEditor, m.RoleEditor, true},
+		{m.RoleEditor, m.RoleOwner, false},
+		{m.RoleViewer, m.RoleViewer, true},
+		{m.RoleViewer, m.RoleEditor, false},
+		{"", m.RoleViewer, false},
+		{"admin", m.RoleViewer, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.role)+"_"+string(tt.required), func(t *testing.T) {
+			assert.Equal(t, tt.allows, tt.role.Allows(tt.required))
+		})
+	}
+}
+
+func TestAccess(t *testing.T) {
+	dbErr := errors.New("dummy")
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		role    m.Role
+		findErr error
+		err     error
+	}{
+		{"allowed", testCtx, m.RoleEditor, nil, nil},
+		{"insufficient_role", testCtx, m.RoleViewer, nil, ErrForbidden},
+		{"not_a_member", testCtx, "", nil, ErrForbidden},
+		{"not_found", testCtx, "", ErrRecordNotFound, ErrRecordNotFound},
+		{"storage_error", testCtx, "", dbErr, dbErr},
+		{"unauthenticated", context.Background(), m.RoleOwner, nil, ErrUnauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := access{memberStorage: roleStorage(tt.role, tt.findErr)}
+
+			assert.Equal(t, tt.err, a.onBoard(tt.ctx, 1, m.RoleEditor))
+			assert.Equal(t, tt.err, a.onColumn(tt.ctx, 1, m.RoleEditor))
+			assert.Equal(t, tt.err, a.onTask(tt.ctx, 1, m.RoleEditor))
+		})
+	}
+}
+
+func TestRelation(t *testing.T) {
+	dbErr := errors.New("dummy")
+
+	assert.Equal(t, ErrBoardRelation, relation(ErrRecordNotFound, ErrBoardRelation))
+	assert.Equal(t, ErrForbidden, relation(ErrForbidden, ErrBoardRelation))
+	assert.Equal(t, dbErr, relation(dbErr, ErrBoardRelation))
+	assert.Nil(t, relation(nil, ErrBoardRelation))
+}
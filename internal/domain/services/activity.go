@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+)
+
+// ActivityService is an interactor for work with the activity history
+type ActivityService struct {
+	activityStorage ActivityStorage
+	access          access
+}
+
+// NewActivityService is an activity service constructor
+func NewActivityService(activityStorage ActivityStorage, memberStorage MemberStorage) *ActivityService {
+	return &ActivityService{
+		activityStorage: activityStorage,
+		access:          access{memberStorage: memberStorage},
+	}
+}
+
+// FindByBoard will return the page of the activity entries of the board with the
+// provided ID that meet the provided demand, from the newest to the oldest, and
+// the cursor of the next page if there is one. Any member of the board can read
+// its activity
+func (s *ActivityService) FindByBoard(ctx context.Context, boardID uint, demand ActivityDemand, page Page) ([]*m.Activity, *Cursor, error) {
+	if err := s.access.onBoard(ctx, boardID, m.RoleViewer); err != nil {
+		return nil, nil, err
+	}
+	demand["board"] = boardID
+
+	return s.find(demand, page)
+}
+
+// FindByTask will return the page of the activity entries of the task with the
+// provided ID and of its comments that meet the provided demand, from the newest
+// to the oldest, and the cursor of the next page if there is one. Any member of
+// the board can read the activity of its tasks
+func (s *ActivityService) FindByTask(ctx context.Context, taskID uint, demand ActivityDemand, page Page) ([]*m.Activity, *Cursor, error) {
+	if err := s.access.onTask(ctx, taskID, m.RoleViewer); err != nil {
+		return nil, nil, err
+	}
+	demand["task"] = taskID
+
+	return s.find(demand, page)
+}
+
+func (s *ActivityService) find(demand ActivityDemand, page Page) ([]*m.Activity, *Cursor, error) {
+	entries, err := s.activityStorage.Find(demand, page.lookAhead())
+	if err != nil || !page.hasMore(len(entries)) {
+		return entries, nil, err
+	}
+
+	entries = entries[:page.Limit]
+
+	return entries, &Cursor{ID: entries[len(entries)-1].ID}, nil
+}
+
+// journal records the changes made by the services into the activity history and
+// saves the events of the changes to the outbox within the same transactions
+type journal struct {
+	activityStorage ActivityStorage
+	outboxStorage   OutboxStorage
+}
+
+// newJournal returns the journal that saves the changes to the activity storage
+// and their events to the outbox storage
+func newJournal(activityStorage ActivityStorage, outboxStorage OutboxStorage) journal {
+	return journal{activityStorage: activityStorage, outboxStorage: outboxStorage}
+}
+
+// record will save the activity entry made by the current user with the changes
+// between the provided states of the record, and the event of the change, within
+// the provided transaction, a missing state is nil
+func (j journal) record(ctx context.Context, tx *sql.Tx, entry m.Activity, before, after interface{}) error {
+	changes, err := diff(before, after)
+	if err != nil {
+		return err
+	}
+	entry.Changes = changes
+	if user, ok := UserFromContext(ctx); ok {
+		entry.ActorID = user.ID
+	}
+
+	saved, err := j.activityStorage.WithTx(tx).Save(&entry)
+	if err != nil {
+		return err
+	}
+	if j.outboxStorage == nil {
+		return nil
+	}
+	event, err := newEvent(saved, before, after)
+	if err != nil {
+		return err
+	}
+	event.Rules = rulesFromContext(ctx)
+	_, err = j.outboxStorage.WithTx(tx).Save(event)
+
+	return err
+}
+
+// diff returns the fields that differ between the provided states of a record,
+// the states are compared by their JSON representation. The ID of the record
+// is never reported as changed.
+func diff(before, after interface{}) (m.Changes, error) {
+	from, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(m.Changes)
+	for _, fields := range []map[string]interface{}{from, to} {
+		for name := range fields {
+			if name != "id" && !reflect.DeepEqual(from[name], to[name]) {
+				changes[name] = m.Change{From: from[name], To: to[name]}
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// jsonFields returns the fields of the JSON representation of the provided state,
+// a nil state has no fields
+func jsonFields(state interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if state == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return fields, json.Unmarshal(data, &fields)
+}
@@ -0,0 +1,174 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// journalStub returns the journal that records any entry within the provided
+// transaction along with the storage the entries are saved to
+func journalStub(tx *sql.Tx) (journal, *MockedActivityStorage) {
+	activityStorage := new(MockedActivityStorage)
+	activityStorage.On("WithTx", tx).Return(activityStorage)
+	activityStorage.On("Save", mock.Anything).Return(&m.Activity{}, nil)
+
+	return journal{activityStorage: activityStorage}, activityStorage
+}
+
+// recorded returns the activity entry saved to the provided storage by the nth call
+func recorded(activityStorage *MockedActivityStorage, n int) *m.Activity {
+	saves := make([]*m.Activity, 0)
+	for _, call := range activityStorage.Calls {
+		if call.Method == "Save" {
+			saves = append(saves, call.Arguments.Get(0).(*m.Activity))
+		}
+	}
+	if n >= len(saves) {
+		return nil
+	}
+
+	return saves[n]
+}
+
+func TestNewActivityService(t *testing.T) {
+	activityStorage := new(MockedActivityStorage)
+	memberStorage := new(MockedMemberStorage)
+	activityService := NewActivityService(activityStorage, memberStorage)
+
+	assert.Equal(t, activityStorage, activityService.activityStorage)
+	assert.Equal(t, memberStorage, activityService.access.memberStorage)
+}
+
+func TestActivityService_FindByBoard(t *testing.T) {
+	entriesIn := []*m.Activity{{ID: 9}, {ID: 7}, {ID: 4}}
+
+	t.Run("success", func(t *testing.T) {
+		activityStorage := new(MockedActivityStorage)
+		activityStorage.On("Find", ActivityDemand{"board": uint(2)}, Page{}).Return(entriesIn, nil)
+		activityService := &ActivityService{access: ownerAccess, activityStorage: activityStorage}
+
+		entriesOut, next, err := activityService.FindByBoard(testCtx, 2, make(ActivityDemand), Page{})
+		assert.Nil(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, entriesIn, entriesOut)
+	})
+
+	t.Run("next_page", func(t *testing.T) {
+		activityStorage := new(MockedActivityStorage)
+		activityStorage.On("Find", ActivityDemand{"board": uint(2), "actor": uint(1)}, Page{Limit: 3}).Return(entriesIn, nil)
+		activityService := &ActivityService{access: ownerAccess, activityStorage: activityStorage}
+
+		entriesOut, next, err := activityService.FindByBoard(testCtx, 2, ActivityDemand{"actor": uint(1)}, Page{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, entriesIn[:2], entriesOut)
+		assert.Equal(t, &Cursor{ID: 7}, next)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		activityStorage := new(MockedActivityStorage)
+		activityService := &ActivityService{access: access{memberStorage: roleStorage("", nil)}, activityStorage: activityStorage}
+
+		_, _, err := activityService.FindByBoard(testCtx, 2, make(ActivityDemand), Page{})
+		assert.Equal(t, ErrForbidden, err)
+		activityStorage.AssertNotCalled(t, "Find", mock.Anything, mock.Anything)
+	})
+}
+
+func TestActivityService_FindByTask(t *testing.T) {
+	entriesIn := []*m.Activity{{ID: 3, TaskID: 5}}
+
+	t.Run("success", func(t *testing.T) {
+		activityStorage := new(MockedActivityStorage)
+		activityStorage.On("Find", ActivityDemand{"task": uint(5)}, Page{}).Return(entriesIn, nil)
+		activityService := &ActivityService{access: ownerAccess, activityStorage: activityStorage}
+
+		entriesOut, next, err := activityService.FindByTask(testCtx, 5, make(ActivityDemand), Page{})
+		assert.Nil(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, entriesIn, entriesOut)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		activityService := &ActivityService{
+			access:          access{memberStorage: roleStorage("", ErrRecordNotFound)},
+			activityStorage: new(MockedActivityStorage),
+		}
+
+		_, _, err := activityService.FindByTask(testCtx, 5, make(ActivityDemand), Page{})
+		assert.Equal(t, ErrRecordNotFound, err)
+	})
+}
+
+func TestJournal_Record(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		_, tx := txStub(t, false)
+		journal, activityStorage := journalStub(tx)
+
+		before := &m.Label{Model: m.Model{ID: 4}, Name: "bug", Color: "#ff0000", BoardID: 2}
+		after := &m.Label{Model: m.Model{ID: 4}, Name: "defect", Color: "#ff0000", BoardID: 2}
+		entry := m.Activity{BoardID: 2, Entity: m.EntityLabel, EntityID: 4, Action: m.ActionUpdate}
+		assert.Nil(t, journal.record(testCtx, tx, entry, before, after))
+
+		saved := recorded(activityStorage, 0)
+		assert.Equal(t, uint(1), saved.ActorID)
+		assert.Equal(t, m.EntityLabel, saved.Entity)
+		assert.Equal(t, m.Changes{"name": {From: "bug", To: "defect"}}, saved.Changes)
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		_, tx := txStub(t, false)
+		dbErr := errors.New("dummy")
+		activityStorage := new(MockedActivityStorage)
+		activityStorage.On("WithTx", tx).Return(activityStorage)
+		activityStorage.On("Save", mock.Anything).Return((*m.Activity)(nil), dbErr)
+
+		err := journal{activityStorage: activityStorage}.record(context.Background(), tx, m.Activity{}, nil, nil)
+		assert.Equal(t, dbErr, err)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	task := &m.Task{Model: m.Model{ID: 3}, Name: "dummy", ColumnID: 1, Labels: []uint{2}}
+
+	tests := []struct {
+		name    string
+		before  interface{}
+		after   interface{}
+		changes m.Changes
+	}{
+		{
+			"created",
+			nil,
+			&m.Comment{Model: m.Model{ID: 1}, Text: "dummy", TaskID: 3},
+			m.Changes{"text": {To: "dummy"}, "task": {To: 3.0}, "created_by": {To: 0.0}},
+		},
+		{
+			"deleted",
+			&m.Member{BoardID: 1, UserID: 2, Role: m.RoleViewer},
+			(*m.Member)(nil),
+			m.Changes{"board": {From: 1.0}, "user": {From: 2.0}, "role": {From: "viewer"}},
+		},
+		{
+			"updated",
+			task,
+			&m.Task{Model: m.Model{ID: 3}, Name: "dummy", ColumnID: 2, Labels: []uint{2, 4}},
+			m.Changes{"column": {From: 1.0, To: 2.0}, "labels": {From: []interface{}{2.0}, To: []interface{}{2.0, 4.0}}},
+		},
+		{"unchanged", task, task, m.Changes{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := diff(tt.before, tt.after)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.changes, changes)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"strings"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthService is an interactor for users registration and authentication
+type AuthService struct {
+	validator   v.Validator
+	userStorage UserStorage
+	tokens      TokenManager
+	hashCost    int
+}
+
+// NewAuthService is an auth service constructor
+func NewAuthService(validator v.Validator, userStorage UserStorage, tokens TokenManager) *AuthService {
+	return &AuthService{
+		validator:   validator,
+		userStorage: userStorage,
+		tokens:      tokens,
+		hashCost:    bcrypt.DefaultCost,
+	}
+}
+
+// Register will create a new user with the provided payload. The password
+// is stored as a hash and is cleared in the returned user
+func (a *AuthService) Register(user *m.User) (*m.User, error) {
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+	if err := a.validator.Validate(*user); err != nil {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), a.hashCost)
+	if err != nil {
+		return nil, err
+	}
+	user.Password, user.PasswordHash = "", string(hash)
+
+	return a.userStorage.Save(user)
+}
+
+// Login will verify the provided credentials and return a new access token
+// for the user they belong to
+func (a *AuthService) Login(credentials m.Credentials) (string, error) {
+	credentials.Email = strings.ToLower(strings.TrimSpace(credentials.Email))
+	if err := a.validator.Validate(credentials); err != nil {
+		return "", err
+	}
+
+	user, err := a.userStorage.FindOneByEmail(credentials.Email)
+	switch err {
+	case nil:
+	case ErrRecordNotFound:
+		return "", ErrInvalidCredentials
+	default:
+		return "", err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password)) != nil {
+		return "", ErrInvalidCredentials
+	}
+
+	return a.tokens.Issue(user.ID)
+}
+
+// Authenticate will return the user the provided access token was issued for
+func (a *AuthService) Authenticate(token string) (*m.User, error) {
+	ID, err := a.tokens.Parse(token)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+
+	user, err := a.userStorage.FindOneById(ID)
+	switch err {
+	case nil:
+		return user, nil
+	case ErrRecordNotFound:
+		return nil, ErrUnauthenticated
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,141 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestNewAuthService(t *testing.T) {
+	userStorage := new(MockedUserStorage)
+	tokens := new(MockedTokenManager)
+	validation := new(MockedValidation)
+	authService := NewAuthService(validation, userStorage, tokens)
+
+	assert.Equal(t, userStorage, authService.userStorage)
+	assert.Equal(t, tokens, authService.tokens)
+	assert.Equal(t, validation, authService.validator)
+}
+
+func TestAuthService_Register(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		var validationErr *v.Errors
+		userIn := &m.User{Email: " John@Example.com", Name: "John", Password: "password"}
+
+		validation := new(MockedValidation)
+		validation.On("Validate", mock.Anything).Return(validationErr)
+		userStorage := new(MockedUserStorage)
+		userStorage.On("Save", userIn).Return(userIn, nil)
+
+		authService := &AuthService{validator: validation, userStorage: userStorage, hashCost: bcrypt.MinCost}
+		userOut, err := authService.Register(userIn)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "john@example.com", userOut.Email)
+		assert.Empty(t, userOut.Password)
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(userOut.PasswordHash), []byte("password")))
+	})
+	t.Run("validation_error", func(t *testing.T) {
+		validationErr := v.NewErrors()
+		validationErr.Add(v.Error{Field: "password", Message: "test"})
+
+		validation := new(MockedValidation)
+		validation.On("Validate", mock.Anything).Return(validationErr)
+
+		authService := &AuthService{validator: validation}
+		userOut, err := authService.Register(&m.User{})
+
+		assert.Equal(t, validationErr, err)
+		assert.Nil(t, userOut)
+	})
+}
+
+func TestAuthService_Login(t *testing.T) {
+	var validationErr *v.Errors
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	user := &m.User{Model: m.Model{ID: 1}, Email: "john@example.com", PasswordHash: string(hash)}
+
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+	userStorage := new(MockedUserStorage)
+	userStorage.On("FindOneByEmail", "john@example.com").Return(user, nil)
+	userStorage.On("FindOneByEmail", "jane@example.com").Return(&m.User{}, ErrRecordNotFound)
+	tokens := new(MockedTokenManager)
+	tokens.On("Issue", uint(1)).Return("token", nil)
+
+	authService := &AuthService{validator: validation, userStorage: userStorage, tokens: tokens}
+
+	tests := []struct {
+		name        string
+		credentials m.Credentials
+		token       string
+		err         error
+	}{
+		{"success", m.Credentials{Email: "John@example.com", Password: "password"}, "token", nil},
+		{"wrong_password", m.Credentials{Email: "john@example.com", Password: "dummy"}, "", ErrInvalidCredentials},
+		{"unknown_email", m.Credentials{Email: "jane@example.com", Password: "password"}, "", ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := authService.Login(tt.credentials)
+
+			assert.Equal(t, tt.token, token)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	user := &m.User{Model: m.Model{ID: 1}}
+	dbErr := errors.New("simple error")
+
+	userStorage := new(MockedUserStorage)
+	userStorage.On("FindOneById", uint(1)).Return(user, nil)
+	userStorage.On("FindOneById", uint(2)).Return(&m.User{}, ErrRecordNotFound)
+	userStorage.On("FindOneById", uint(3)).Return(&m.User{}, dbErr)
+	tokens := new(MockedTokenManager)
+	tokens.On("Parse", "valid").Return(uint(1), nil)
+	tokens.On("Parse", "deleted").Return(uint(2), nil)
+	tokens.On("Parse", "failing").Return(uint(3), nil)
+	tokens.On("Parse", "invalid").Return(uint(0), errors.New("invalid"))
+
+	authService := &AuthService{userStorage: userStorage, tokens: tokens}
+
+	tests := []struct {
+		name  string
+		token string
+		user  *m.User
+		err   error
+	}{
+		{"success", "valid", user, nil},
+		{"invalid_token", "invalid", nil, ErrUnauthenticated},
+		{"deleted_user", "deleted", nil, ErrUnauthenticated},
+		{"database_error", "failing", nil, dbErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userOut, err := authService.Authenticate(tt.token)
+
+			assert.Equal(t, tt.user, userOut)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestUserFromContext(t *testing.T) {
+	_, ok := UserFromContext(context.Background())
+	assert.False(t, ok)
+
+	user := &m.User{Model: m.Model{ID: 1}}
+	found, ok := UserFromContext(WithUser(context.Background(), user))
+	assert.True(t, ok)
+	assert.Equal(t, user, found)
+}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+)
+
+// maxRuleChain is the number of the automation rules that may run one after another,
+// each of them triggered by the changes made by the previous one
+const maxRuleChain = 5
+
+// errRuleLoop is the error of the runs skipped to avoid a loop of the rules
+const errRuleLoop = "the rule has been triggered by its own actions or by a too long chain of rules"
+
+// ruleTasks represents the changes of the tasks the automation rules make
+type ruleTasks interface {
+	FindOneById(ctx context.Context, ID uint) (*m.Task, error)
+	Update(ctx context.Context, task *m.Task) (*m.Task, error)
+	Move(ctx context.Context, ID uint, move m.TaskMove) (*m.Task, error)
+}
+
+// ruleComments represents the comments the automation rules add
+type ruleComments interface {
+	Create(ctx context.Context, comment *m.Comment) (*m.Comment, error)
+}
+
+// ruleWebhooks represents the webhooks the automation rules post the events to
+type ruleWebhooks interface {
+	Dispatch(ctx context.Context, webhookID uint, event m.Event) error
+}
+
+// AutomationService is an interactor for work with the automation rules of the boards
+// and the engine that runs them. Only board owners can manage the rules. The actions
+// of a rule are taken on behalf of its author, so they are limited by the role of the
+// author on the board, and every run of a rule is logged to its executions
+type AutomationService struct {
+	validator        v.Validator
+	ruleStorage      RuleStorage
+	executionStorage ExecutionStorage
+	taskStorage      TaskStorage
+	access           access
+	tasks            ruleTasks
+	comments         ruleComments
+	webhooks         ruleWebhooks
+}
+
+// NewAutomationService is an automation service constructor, the actions of the rules
+// are taken with the provided task, comment and webhook services
+func NewAutomationService(
+	validator v.Validator,
+	ruleStorage RuleStorage,
+	executionStorage ExecutionStorage,
+	taskStorage TaskStorage,
+	memberStorage MemberStorage,
+	taskService *TaskService,
+	commentService *CommentService,
+	webhookService *WebhookService,
+) *AutomationService {
+	return &AutomationService{
+		validator:        validator,
+		ruleStorage:      ruleStorage,
+		executionStorage: executionStorage,
+		taskStorage:      taskStorage,
+		access:           access{memberStorage: memberStorage},
+		tasks:            taskService,
+		comments:         commentService,
+		webhooks:         webhookService,
+	}
+}
+
+// Create will add a new automation rule to the board, the current user becomes its
+// author. Returns the created rule or possible validation or saving errors
+func (s *AutomationService) Create(ctx context.Context, rule *m.Rule) (*m.Rule, error) {
+	if err := s.validate(rule); err != nil {
+		return nil, err
+	}
+	if err := s.access.onBoard(ctx, rule.BoardID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	return s.ruleStorage.Save(rule)
+}
+
+// FindByBoard will return the page of the automation rules of the board with the
+// provided ID and the cursor of the next page if there is one
+func (s *AutomationService) FindByBoard(ctx context.Context, boardID uint, page Page) ([]*m.Rule, *Cursor, error) {
+	if err := s.access.onBoard(ctx, boardID, m.RoleOwner); err != nil {
+		return nil, nil, err
+	}
+
+	rules, err := s.ruleStorage.Find(boardID, page.lookAhead())
+	if err != nil || !page.hasMore(len(rules)) {
+		return rules, nil, err
+	}
+
+	rules = rules[:page.Limit]
+
+	return rules, &Cursor{ID: rules[len(rules)-1].ID}, nil
+}
+
+// FindOneById will return a pointer to the automation rule requested by id and an error
+// in case it occurred while fetching the record from the storage
+func (s *AutomationService) FindOneById(ctx context.Context, ID uint) (*m.Rule, error) {
+	return s.findOne(ctx, ID)
+}
+
+// Update will update the name, the trigger, the conditions, the actions and the disabled
+// flag of the automation rule, its author is kept. Returns the updated rule or possible
+// validation or saving errors
+func (s *AutomationService) Update(ctx context.Context, rule *m.Rule) (*m.Rule, error) {
+	if err := s.validate(rule); err != nil {
+		return nil, err
+	}
+	if _, err := s.findOne(ctx, rule.ID); err != nil {
+		return nil, err
+	}
+
+	return s.ruleStorage.Update(rule)
+}
+
+// Delete will delete the automation rule with the provided ID along with its executions
+func (s *AutomationService) Delete(ctx context.Context, ID uint) error {
+	if _, err := s.findOne(ctx, ID); err != nil {
+		return err
+	}
+
+	return s.ruleStorage.Delete(ID)
+}
+
+// FindExecutions will return the page of the executions of the automation rule with the
+// provided ID from the newest to the oldest and the cursor of the next page if there is one
+func (s *AutomationService) FindExecutions(ctx context.Context, ruleID uint, page Page) ([]*m.RuleExecution, *Cursor, error) {
+	if _, err := s.findOne(ctx, ruleID); err != nil {
+		return nil, nil, err
+	}
+
+	executions, err := s.executionStorage.Find(ruleID, page.lookAhead())
+	if err != nil || !page.hasMore(len(executions)) {
+		return executions, nil, err
+	}
+
+	executions = executions[:page.Limit]
+
+	return executions, &Cursor{ID: executions[len(executions)-1].ID}, nil
+}
+
+// Handle will run the enabled automation rules of the board of the event that are
+// triggered by it. The failed actions are logged to the executions of the rules, an
+// error is returned only if the rules or their executions can not be stored
+func (s *AutomationService) Handle(event m.Event) error {
+	trigger, taskID, columnID, ok := triggerOf(event)
+	if !ok {
+		return nil
+	}
+	rules, err := s.ruleStorage.FindTriggered(trigger, event.BoardID)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if trigger == m.TriggerTaskMoved && rule.Trigger.ColumnID != 0 && rule.Trigger.ColumnID != columnID {
+			continue
+		}
+		if err = s.run(rule, taskID, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckOverdue will run the enabled automation rules triggered by the overdue tasks on
+// the tasks of their boards which due dates have passed since the rules last ran on them
+func (s *AutomationService) CheckOverdue() error {
+	rules, err := s.ruleStorage.FindTriggered(m.TriggerTaskOverdue, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		tasks, err := s.taskStorage.Find(TaskDemand{"board": rule.BoardID, "overdue": true}, Page{})
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			last, err := s.executionStorage.FindLast(rule.ID, task.ID)
+			if err == nil && last.CreatedAt.After(*task.DueAt) {
+				continue
+			}
+			if err != nil && err != ErrRecordNotFound {
+				return err
+			}
+
+			data, err := json.Marshal(task)
+			if err != nil {
+				return err
+			}
+			event := m.Event{
+				Type:      m.EventType(m.TriggerTaskOverdue),
+				BoardID:   rule.BoardID,
+				TaskID:    task.ID,
+				Entity:    m.EntityTask,
+				EntityID:  task.ID,
+				Data:      data,
+				CreatedAt: time.Now().UTC(),
+			}
+			if err = s.run(rule, task.ID, event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// run will take the actions of the rule on the task if it meets the conditions of the
+// rule and log the execution. The runs triggered by the actions of the rule itself or
+// by a too long chain of rules are skipped, as well as the runs by the events that
+// have already run the rule, since a relayed event may be passed again
+func (s *AutomationService) run(rule *m.Rule, taskID uint, event m.Event) error {
+	if event.ID != 0 {
+		_, err := s.executionStorage.FindByEvent(rule.ID, event.ID)
+		if err == nil {
+			return nil
+		}
+		if err != ErrRecordNotFound {
+			return err
+		}
+	}
+
+	execution := &m.RuleExecution{RuleID: rule.ID, EventID: event.ID, TaskID: taskID, Status: m.ExecutionSucceeded}
+	if hasID(event.Rules, rule.ID) || len(event.Rules) >= maxRuleChain {
+		execution.Status, execution.Error = m.ExecutionSkipped, errRuleLoop
+	} else {
+		met, err := s.execute(rule, taskID, event)
+		if !met {
+			return nil
+		}
+		if err != nil {
+			execution.Status, execution.Error = m.ExecutionFailed, err.Error()
+		}
+	}
+
+	_, err := s.executionStorage.Save(execution)
+
+	return err
+}
+
+// execute will take the actions of the rule on the task in order on behalf of the author
+// of the rule if the task meets the conditions of the rule, and stop at the first failed
+// action. Reports if the conditions are met, the task that can not be read fails them
+func (s *AutomationService) execute(rule *m.Rule, taskID uint, event m.Event) (bool, error) {
+	ctx := WithUser(context.Background(), &m.User{Model: m.Model{ID: rule.CreatedBy}})
+	ctx = withRules(ctx, append(append([]uint{}, event.Rules...), rule.ID))
+
+	task, err := s.tasks.FindOneById(ctx, taskID)
+	if err != nil {
+		return true, err
+	}
+	if !rule.Conditions.Met(*task) {
+		return false, nil
+	}
+
+	for i, action := range rule.Actions {
+		if task, err = s.act(ctx, rule, task, action, event); err != nil {
+			return true, fmt.Errorf("action %d (%s): %v", i+1, action.Type, err)
+		}
+	}
+
+	return true, nil
+}
+
+// act will take the action on the task and return the task after the change, the
+// actions that would change nothing are not taken
+func (s *AutomationService) act(ctx context.Context, rule *m.Rule, task *m.Task, action m.RuleAction, event m.Event) (*m.Task, error) {
+	switch action.Type {
+	case m.ActionTypeMove:
+		if task.ColumnID == action.ColumnID {
+			return task, nil
+		}
+		return s.tasks.Move(ctx, task.ID, m.TaskMove{ColumnID: action.ColumnID})
+	case m.ActionTypeAssign:
+		if hasID(task.Assignees, action.UserID) {
+			return task, nil
+		}
+		changed := *task
+		changed.Assignees = append(append([]uint{}, task.Assignees...), action.UserID)
+		return s.tasks.Update(ctx, &changed)
+	case m.ActionTypeLabel:
+		if hasID(task.Labels, action.LabelID) {
+			return task, nil
+		}
+		changed := *task
+		changed.Labels = append(append([]uint{}, task.Labels...), action.LabelID)
+		return s.tasks.Update(ctx, &changed)
+	case m.ActionTypeComment:
+		_, err := s.comments.Create(ctx, &m.Comment{Text: action.Text, TaskID: task.ID, CreatedBy: rule.CreatedBy})
+		return task, err
+	case m.ActionTypeWebhook:
+		return task, s.webhooks.Dispatch(ctx, action.WebhookID, event)
+	}
+
+	return task, nil
+}
+
+// validate will validate the rule along with its trigger, conditions and actions, each
+// of them has to refer to the record its type requires
+func (s *AutomationService) validate(rule *m.Rule) error {
+	if err := s.validator.Validate(*rule); err != nil {
+		return err
+	}
+
+	errs := v.NewErrors()
+	if !rule.Trigger.Type.Valid() {
+		errs.Add(v.Error{Field: "trigger", Message: fmt.Sprintf("unknown trigger type %q", rule.Trigger.Type)})
+	}
+	if rule.Trigger.ColumnID != 0 && rule.Trigger.Type != m.TriggerTaskMoved {
+		errs.Add(v.Error{Field: "trigger", Message: "only the task.moved trigger may refer to a column"})
+	}
+	for _, condition := range rule.Conditions {
+		if message := conditionProblem(condition); message != "" {
+			errs.Add(v.Error{Field: "conditions", Message: message})
+		}
+	}
+	for _, action := range rule.Actions {
+		if message := actionProblem(action); message != "" {
+			errs.Add(v.Error{Field: "actions", Message: message})
+		}
+	}
+	if errs.Num() > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// findOne will return the automation rule with the provided ID if the current user is
+// an owner of its board
+func (s *AutomationService) findOne(ctx context.Context, ID uint) (*m.Rule, error) {
+	rule, err := s.ruleStorage.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.access.onBoard(ctx, rule.BoardID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// triggerOf returns the type of the automation rules triggered by the event along with
+// the task the rules run on and the column the task has been moved into, if it has
+func triggerOf(event m.Event) (trigger m.TriggerType, taskID, columnID uint, ok bool) {
+	switch event.Type {
+	case m.NewEventType(m.EntityTask, m.ActionCreate):
+		return m.TriggerTaskCreated, event.EntityID, 0, true
+	case m.NewEventType(m.EntityComment, m.ActionCreate):
+		return m.TriggerCommentCreated, event.TaskID, 0, true
+	case m.NewEventType(m.EntityTask, m.ActionUpdate),
+		m.NewEventType(m.EntityTask, m.ActionMove),
+		m.NewEventType(m.EntityTask, m.ActionTransfer):
+		if _, moved := event.Changes["column"]; !moved {
+			return "", 0, 0, false
+		}
+		var task m.Task
+		if err := json.Unmarshal(event.Data, &task); err != nil {
+			return "", 0, 0, false
+		}
+		return m.TriggerTaskMoved, event.EntityID, task.ColumnID, true
+	}
+
+	return "", 0, 0, false
+}
+
+// conditionProblem describes why the condition is invalid, if it is
+func conditionProblem(condition m.RuleCondition) string {
+	switch condition.Type {
+	case m.ConditionLabel:
+		if condition.LabelID == 0 {
+			return "the label condition requires the label"
+		}
+	case m.ConditionAssignee:
+		if condition.UserID == 0 {
+			return "the assignee condition requires the user"
+		}
+	case m.ConditionColumn:
+		if condition.ColumnID == 0 {
+			return "the column condition requires the column"
+		}
+	default:
+		return fmt.Sprintf("unknown condition type %q", condition.Type)
+	}
+
+	return ""
+}
+
+// actionProblem describes why the action is invalid, if it is
+func actionProblem(action m.RuleAction) string {
+	switch action.Type {
+	case m.ActionTypeMove:
+		if action.ColumnID == 0 {
+			return "the move action requires the column"
+		}
+	case m.ActionTypeAssign:
+		if action.UserID == 0 {
+			return "the assign action requires the user"
+		}
+	case m.ActionTypeLabel:
+		if action.LabelID == 0 {
+			return "the label action requires the label"
+		}
+	case m.ActionTypeComment:
+		if action.Text == "" || len(action.Text) > 5000 {
+			return "the comment action requires the text of up to 5000 characters"
+		}
+	case m.ActionTypeWebhook:
+		if action.WebhookID == 0 {
+			return "the webhook action requires the webhook"
+		}
+	default:
+		return fmt.Sprintf("unknown action type %q", action.Type)
+	}
+
+	return ""
+}
+
+// hasID reports if the IDs contain the provided one
+func hasID(IDs []uint, ID uint) bool {
+	for _, listed := range IDs {
+		if listed == ID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,438 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// validRule returns the validator that accepts any rule
+func validRule() *MockedValidation {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+
+	return validation
+}
+
+// savedExecutions returns the execution storage mock that saves any execution, the
+// rules have not run by any event yet
+func savedExecutions() *MockedExecutionStorage {
+	executionStorage := new(MockedExecutionStorage)
+	executionStorage.On("FindByEvent", mock.Anything, mock.Anything).Return((*m.RuleExecution)(nil), ErrRecordNotFound)
+	executionStorage.On("Save", mock.Anything).Return(&m.RuleExecution{}, nil)
+
+	return executionStorage
+}
+
+// saved returns the executions saved to the execution storage mock
+func saved(executionStorage *MockedExecutionStorage) []*m.RuleExecution {
+	executions := make([]*m.RuleExecution, 0)
+	for _, call := range executionStorage.Calls {
+		if call.Method == "Save" {
+			executions = append(executions, call.Arguments.Get(0).(*m.RuleExecution))
+		}
+	}
+
+	return executions
+}
+
+// taskEvent returns the event of the change of the task with the provided changes
+func taskEvent(t *testing.T, eventType m.EventType, task m.Task, changes m.Changes) m.Event {
+	data, err := json.Marshal(task)
+	require.Nil(t, err)
+
+	return m.Event{
+		ID:       7,
+		Type:     eventType,
+		BoardID:  1,
+		TaskID:   task.ID,
+		Entity:   m.EntityTask,
+		EntityID: task.ID,
+		Data:     data,
+		Changes:  changes,
+	}
+}
+
+func TestNewAutomationService(t *testing.T) {
+	validation := new(MockedValidation)
+	ruleStorage := new(MockedRuleStorage)
+	executionStorage := new(MockedExecutionStorage)
+	taskStorage := new(MockedTaskStorage)
+	memberStorage := new(MockedMemberStorage)
+	taskService := &TaskService{}
+	commentService := &CommentService{}
+	webhookService := &WebhookService{}
+	automationService := NewAutomationService(
+		validation,
+		ruleStorage,
+		executionStorage,
+		taskStorage,
+		memberStorage,
+		taskService,
+		commentService,
+		webhookService,
+	)
+
+	assert.Equal(t, validation, automationService.validator)
+	assert.Equal(t, ruleStorage, automationService.ruleStorage)
+	assert.Equal(t, executionStorage, automationService.executionStorage)
+	assert.Equal(t, taskStorage, automationService.taskStorage)
+	assert.Equal(t, memberStorage, automationService.access.memberStorage)
+	assert.Equal(t, taskService, automationService.tasks)
+	assert.Equal(t, commentService, automationService.comments)
+	assert.Equal(t, webhookService, automationService.webhooks)
+}
+
+func TestAutomationService_Create(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		rule := &m.Rule{
+			BoardID: 1,
+			Name:    "dummy",
+			Trigger: m.RuleTrigger{Type: m.TriggerTaskMoved, ColumnID: 2},
+			Conditions: m.RuleConditions{
+				{Type: m.ConditionLabel, LabelID: 1},
+				{Type: m.ConditionAssignee, UserID: 1},
+			},
+			Actions: m.RuleActions{
+				{Type: m.ActionTypeComment, Text: "done"},
+				{Type: m.ActionTypeWebhook, WebhookID: 1},
+			},
+		}
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("Save", rule).Return(rule, nil)
+		automationService := &AutomationService{validator: validRule(), ruleStorage: ruleStorage, access: ownerAccess}
+
+		created, err := automationService.Create(testCtx, rule)
+		require.Nil(t, err)
+		assert.Equal(t, rule, created)
+	})
+
+	t.Run("invalid_parts", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		automationService := &AutomationService{validator: validRule(), ruleStorage: ruleStorage, access: ownerAccess}
+
+		_, err := automationService.Create(testCtx, &m.Rule{
+			BoardID:    1,
+			Name:       "dummy",
+			Trigger:    m.RuleTrigger{Type: m.TriggerTaskCreated, ColumnID: 2},
+			Conditions: m.RuleConditions{{Type: m.ConditionColumn}, {Type: "priority"}},
+			Actions:    m.RuleActions{{Type: m.ActionTypeMove}, {Type: m.ActionTypeComment}, {Type: "delete"}},
+		})
+		errs, ok := err.(*v.Errors)
+		require.True(t, ok)
+		assert.Equal(t, 6, errs.Num())
+		ruleStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("editor_forbidden", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		automationService := &AutomationService{
+			validator:   validRule(),
+			ruleStorage: ruleStorage,
+			access:      access{memberStorage: roleStorage(m.RoleEditor, nil)},
+		}
+
+		_, err := automationService.Create(testCtx, &m.Rule{
+			BoardID: 1,
+			Name:    "dummy",
+			Trigger: m.RuleTrigger{Type: m.TriggerTaskCreated},
+			Actions: m.RuleActions{{Type: m.ActionTypeLabel, LabelID: 1}},
+		})
+		assert.Equal(t, ErrForbidden, err)
+		ruleStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
+
+func TestAutomationService_FindByBoard(t *testing.T) {
+	ruleStorage := new(MockedRuleStorage)
+	ruleStorage.On("Find", uint(1), Page{Limit: 2}).Return([]*m.Rule{
+		{Model: m.Model{ID: 1}},
+		{Model: m.Model{ID: 2}},
+	}, nil)
+	automationService := &AutomationService{ruleStorage: ruleStorage, access: ownerAccess}
+
+	rules, next, err := automationService.FindByBoard(testCtx, 1, Page{Limit: 1})
+	require.Nil(t, err)
+	assert.Equal(t, []*m.Rule{{Model: m.Model{ID: 1}}}, rules)
+	assert.Equal(t, &Cursor{ID: 1}, next)
+}
+
+func TestAutomationService_Delete(t *testing.T) {
+	t.Run("deleted", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindOneById", uint(1)).Return(&m.Rule{Model: m.Model{ID: 1}, BoardID: 1}, nil)
+		ruleStorage.On("Delete", uint(1)).Return(nil)
+		automationService := &AutomationService{ruleStorage: ruleStorage, access: ownerAccess}
+
+		assert.Nil(t, automationService.Delete(testCtx, 1))
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindOneById", uint(2)).Return((*m.Rule)(nil), ErrRecordNotFound)
+		automationService := &AutomationService{ruleStorage: ruleStorage, access: ownerAccess}
+
+		assert.Equal(t, ErrRecordNotFound, automationService.Delete(testCtx, 2))
+		ruleStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestAutomationService_FindExecutions(t *testing.T) {
+	ruleStorage := new(MockedRuleStorage)
+	ruleStorage.On("FindOneById", uint(1)).Return(&m.Rule{Model: m.Model{ID: 1}, BoardID: 1}, nil)
+	executionStorage := new(MockedExecutionStorage)
+	executionStorage.On("Find", uint(1), Page{Limit: 2, After: &Cursor{ID: 9}}).Return([]*m.RuleExecution{
+		{ID: 8},
+		{ID: 7},
+	}, nil)
+	automationService := &AutomationService{ruleStorage: ruleStorage, executionStorage: executionStorage, access: ownerAccess}
+
+	executions, next, err := automationService.FindExecutions(testCtx, 1, Page{Limit: 1, After: &Cursor{ID: 9}})
+	require.Nil(t, err)
+	assert.Equal(t, []*m.RuleExecution{{ID: 8}}, executions)
+	assert.Equal(t, &Cursor{ID: 8}, next)
+}
+
+func TestAutomationService_Handle(t *testing.T) {
+	task := &m.Task{Model: m.Model{ID: 3}, ColumnID: 2, Labels: []uint{1}}
+
+	t.Run("actions_taken", func(t *testing.T) {
+		rule := &m.Rule{
+			Model:      m.Model{ID: 5},
+			BoardID:    1,
+			Trigger:    m.RuleTrigger{Type: m.TriggerTaskCreated},
+			Conditions: m.RuleConditions{{Type: m.ConditionLabel, LabelID: 1}},
+			Actions: m.RuleActions{
+				{Type: m.ActionTypeMove, ColumnID: 4},
+				{Type: m.ActionTypeAssign, UserID: 2},
+				{Type: m.ActionTypeLabel, LabelID: 1},
+				{Type: m.ActionTypeComment, Text: "triaged"},
+				{Type: m.ActionTypeWebhook, WebhookID: 6},
+			},
+			CreatedBy: 2,
+		}
+		moved := &m.Task{Model: m.Model{ID: 3}, ColumnID: 4, Labels: []uint{1}}
+		assigned := &m.Task{Model: m.Model{ID: 3}, ColumnID: 4, Labels: []uint{1}, Assignees: []uint{2}}
+		event := taskEvent(t, "task.created", *task, nil)
+
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindTriggered", m.TriggerTaskCreated, uint(1)).Return([]*m.Rule{rule}, nil)
+		tasks := new(mockedRuleTasks)
+		tasks.On("FindOneById", mock.Anything, uint(3)).Return(task, nil)
+		tasks.On("Move", mock.Anything, uint(3), m.TaskMove{ColumnID: 4}).Return(moved, nil)
+		tasks.On("Update", mock.Anything, assigned).Return(assigned, nil)
+		comments := new(mockedRuleComments)
+		comments.On("Create", mock.Anything, &m.Comment{Text: "triaged", TaskID: 3, CreatedBy: 2}).Return(&m.Comment{}, nil)
+		webhooks := new(mockedRuleWebhooks)
+		webhooks.On("Dispatch", mock.Anything, uint(6), event).Return(nil)
+		executionStorage := savedExecutions()
+		automationService := &AutomationService{
+			ruleStorage:      ruleStorage,
+			executionStorage: executionStorage,
+			tasks:            tasks,
+			comments:         comments,
+			webhooks:         webhooks,
+		}
+
+		require.Nil(t, automationService.Handle(event))
+
+		// the actions are taken on behalf of the author within the chain of the rule
+		ctx := tasks.Calls[1].Arguments.Get(0).(context.Context)
+		user, ok := UserFromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, uint(2), user.ID)
+		assert.Equal(t, []uint{5}, rulesFromContext(ctx))
+		tasks.AssertNumberOfCalls(t, "Update", 1)
+		executionStorage.AssertCalled(t, "Save", &m.RuleExecution{
+			RuleID:  5,
+			EventID: 7,
+			TaskID:  3,
+			Status:  m.ExecutionSucceeded,
+		})
+	})
+
+	t.Run("conditions_unmet", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindTriggered", m.TriggerTaskCreated, uint(1)).Return([]*m.Rule{{
+			Model:      m.Model{ID: 5},
+			Conditions: m.RuleConditions{{Type: m.ConditionAssignee, UserID: 2}},
+			Actions:    m.RuleActions{{Type: m.ActionTypeMove, ColumnID: 4}},
+		}}, nil)
+		tasks := new(mockedRuleTasks)
+		tasks.On("FindOneById", mock.Anything, uint(3)).Return(task, nil)
+		executionStorage := savedExecutions()
+		automationService := &AutomationService{ruleStorage: ruleStorage, executionStorage: executionStorage, tasks: tasks}
+
+		require.Nil(t, automationService.Handle(taskEvent(t, "task.created", *task, nil)))
+		tasks.AssertNotCalled(t, "Move", mock.Anything, mock.Anything, mock.Anything)
+		executionStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("moved_into_column", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindTriggered", m.TriggerTaskMoved, uint(1)).Return([]*m.Rule{
+			{Model: m.Model{ID: 5}, Trigger: m.RuleTrigger{Type: m.TriggerTaskMoved, ColumnID: 9}},
+			{
+				Model:   m.Model{ID: 6},
+				Trigger: m.RuleTrigger{Type: m.TriggerTaskMoved, ColumnID: 2},
+				Actions: m.RuleActions{{Type: m.ActionTypeComment, Text: "arrived"}},
+			},
+		}, nil)
+		tasks := new(mockedRuleTasks)
+		tasks.On("FindOneById", mock.Anything, uint(3)).Return(task, nil)
+		comments := new(mockedRuleComments)
+		comments.On("Create", mock.Anything, mock.Anything).Return(&m.Comment{}, nil)
+		executionStorage := savedExecutions()
+		automationService := &AutomationService{
+			ruleStorage:      ruleStorage,
+			executionStorage: executionStorage,
+			tasks:            tasks,
+			comments:         comments,
+		}
+
+		changes := m.Changes{"column": {From: float64(1), To: float64(2)}}
+		require.Nil(t, automationService.Handle(taskEvent(t, "task.moved", *task, changes)))
+		executions := saved(executionStorage)
+		require.Len(t, executions, 1)
+		assert.Equal(t, uint(6), executions[0].RuleID)
+
+		// the changes of the other fields do not trigger the rules
+		require.Nil(t, automationService.Handle(taskEvent(t, "task.updated", *task, m.Changes{"name": {}})))
+		ruleStorage.AssertNumberOfCalls(t, "FindTriggered", 1)
+	})
+
+	t.Run("loop_skipped", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindTriggered", m.TriggerCommentCreated, uint(1)).Return([]*m.Rule{
+			{Model: m.Model{ID: 5}, Actions: m.RuleActions{{Type: m.ActionTypeComment, Text: "echo"}}},
+		}, nil)
+		tasks := new(mockedRuleTasks)
+		executionStorage := savedExecutions()
+		automationService := &AutomationService{ruleStorage: ruleStorage, executionStorage: executionStorage, tasks: tasks}
+
+		event := m.Event{ID: 8, Type: "comment.created", BoardID: 1, TaskID: 3, Entity: m.EntityComment, EntityID: 4, Rules: []uint{5}}
+		require.Nil(t, automationService.Handle(event))
+		event.Rules = []uint{1, 2, 3, 4, 6}
+		require.Nil(t, automationService.Handle(event))
+
+		tasks.AssertNotCalled(t, "FindOneById", mock.Anything, mock.Anything)
+		executions := saved(executionStorage)
+		require.Len(t, executions, 2)
+		for _, execution := range executions {
+			assert.Equal(t, m.ExecutionSkipped, execution.Status)
+			assert.Equal(t, errRuleLoop, execution.Error)
+		}
+	})
+
+	t.Run("action_failed", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindTriggered", m.TriggerTaskCreated, uint(1)).Return([]*m.Rule{{
+			Model: m.Model{ID: 5},
+			Actions: m.RuleActions{
+				{Type: m.ActionTypeWebhook, WebhookID: 6},
+				{Type: m.ActionTypeComment, Text: "never"},
+			},
+		}}, nil)
+		tasks := new(mockedRuleTasks)
+		tasks.On("FindOneById", mock.Anything, uint(3)).Return(task, nil)
+		comments := new(mockedRuleComments)
+		webhooks := new(mockedRuleWebhooks)
+		webhooks.On("Dispatch", mock.Anything, uint(6), mock.Anything).Return(ErrWebhookRelation)
+		executionStorage := savedExecutions()
+		automationService := &AutomationService{
+			ruleStorage:      ruleStorage,
+			executionStorage: executionStorage,
+			tasks:            tasks,
+			comments:         comments,
+			webhooks:         webhooks,
+		}
+
+		require.Nil(t, automationService.Handle(taskEvent(t, "task.created", *task, nil)))
+		comments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		execution := saved(executionStorage)[0]
+		assert.Equal(t, m.ExecutionFailed, execution.Status)
+		assert.Equal(t, "action 1 (webhook): "+ErrWebhookRelation.Error(), execution.Error)
+	})
+
+	t.Run("event_handled", func(t *testing.T) {
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindTriggered", m.TriggerTaskCreated, uint(1)).Return([]*m.Rule{
+			{Model: m.Model{ID: 5}, Actions: m.RuleActions{{Type: m.ActionTypeComment, Text: "once"}}},
+		}, nil)
+		tasks := new(mockedRuleTasks)
+		executionStorage := new(MockedExecutionStorage)
+		executionStorage.On("FindByEvent", uint(5), uint(7)).Return(&m.RuleExecution{RuleID: 5, EventID: 7}, nil)
+		automationService := &AutomationService{ruleStorage: ruleStorage, executionStorage: executionStorage, tasks: tasks}
+
+		// the event has run the rule on the previous relay
+		require.Nil(t, automationService.Handle(taskEvent(t, "task.created", *task, nil)))
+		tasks.AssertNotCalled(t, "FindOneById", mock.Anything, mock.Anything)
+		executionStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("execution_storage_error", func(t *testing.T) {
+		dbErr := errors.New("dummy")
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindTriggered", m.TriggerTaskCreated, uint(1)).Return([]*m.Rule{{Model: m.Model{ID: 5}}}, nil)
+		executionStorage := new(MockedExecutionStorage)
+		executionStorage.On("FindByEvent", uint(5), uint(7)).Return((*m.RuleExecution)(nil), dbErr)
+		automationService := &AutomationService{ruleStorage: ruleStorage, executionStorage: executionStorage}
+
+		assert.Equal(t, dbErr, automationService.Handle(taskEvent(t, "task.created", *task, nil)))
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		dbErr := errors.New("dummy")
+		ruleStorage := new(MockedRuleStorage)
+		ruleStorage.On("FindTriggered", m.TriggerTaskCreated, uint(1)).Return([]*m.Rule(nil), dbErr)
+		automationService := &AutomationService{ruleStorage: ruleStorage}
+
+		assert.Equal(t, dbErr, automationService.Handle(taskEvent(t, "task.created", *task, nil)))
+	})
+}
+
+func TestAutomationService_CheckOverdue(t *testing.T) {
+	dueAt := time.Now().UTC().Add(-time.Hour)
+	rule := &m.Rule{
+		Model:   m.Model{ID: 5},
+		BoardID: 1,
+		Trigger: m.RuleTrigger{Type: m.TriggerTaskOverdue},
+		Actions: m.RuleActions{{Type: m.ActionTypeLabel, LabelID: 2}},
+	}
+	first := &m.Task{Model: m.Model{ID: 3}, DueAt: &dueAt}
+	second := &m.Task{Model: m.Model{ID: 4}, DueAt: &dueAt}
+	labeled := &m.Task{Model: m.Model{ID: 3}, DueAt: &dueAt, Labels: []uint{2}}
+
+	ruleStorage := new(MockedRuleStorage)
+	ruleStorage.On("FindTriggered", m.TriggerTaskOverdue, uint(0)).Return([]*m.Rule{rule}, nil)
+	taskStorage := new(MockedTaskStorage)
+	taskStorage.On("Find", TaskDemand{"board": uint(1), "overdue": true}, Page{}).Return([]*m.Task{first, second}, nil)
+	executionStorage := savedExecutions()
+	executionStorage.On("FindLast", uint(5), uint(3)).Return((*m.RuleExecution)(nil), ErrRecordNotFound)
+	// the rule has already run on the second task since it has become overdue
+	executionStorage.On("FindLast", uint(5), uint(4)).Return(&m.RuleExecution{CreatedAt: dueAt.Add(time.Minute)}, nil)
+	tasks := new(mockedRuleTasks)
+	tasks.On("FindOneById", mock.Anything, uint(3)).Return(first, nil)
+	tasks.On("Update", mock.Anything, labeled).Return(labeled, nil)
+	automationService := &AutomationService{
+		ruleStorage:      ruleStorage,
+		executionStorage: executionStorage,
+		taskStorage:      taskStorage,
+		tasks:            tasks,
+	}
+
+	require.Nil(t, automationService.CheckOverdue())
+	tasks.AssertNotCalled(t, "FindOneById", mock.Anything, uint(4))
+	executionStorage.AssertCalled(t, "Save", &m.RuleExecution{RuleID: 5, TaskID: 3, Status: m.ExecutionSucceeded})
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"database/sql"
+
 	m "github.com/dnozdrin/detask/internal/domain/models"
 	v "github.com/dnozdrin/detask/internal/domain/validation"
 )
@@ -10,7 +13,10 @@ type BoardService struct {
 	validator     v.Validator
 	boardStorage  BoardStorage
 	columnStorage ColumnStorage
+	memberStorage MemberStorage
 	txBeginner    TxBeginner
+	access        access
+	journal       journal
 }
 
 // NewBoardService is a board service constructor
@@ -18,42 +24,114 @@ func NewBoardService(
 	validator v.Validator,
 	boardStorage BoardStorage,
 	columnStorage ColumnStorage,
+	memberStorage MemberStorage,
+	activityStorage ActivityStorage,
+	outboxStorage OutboxStorage,
 	txBeginner TxBeginner,
 ) *BoardService {
 	return &BoardService{
 		validator:     validator,
 		boardStorage:  boardStorage,
 		columnStorage: columnStorage,
+		memberStorage: memberStorage,
 		txBeginner:    txBeginner,
+		access:        access{memberStorage: memberStorage},
+		journal:       newJournal(activityStorage, outboxStorage),
 	}
 }
 
-// Create will create a new board with the provided payload
-// and a default column
-func (b *BoardService) Create(board *m.Board) (*m.Board, error) {
+// Create will create a new board with the provided payload. The columns of the
+// referenced template are copied to the board, otherwise it gets a default column.
+// The current user becomes the board owner
+func (b *BoardService) Create(ctx context.Context, board *m.Board) (*m.Board, error) {
 	if err := b.validator.Validate(*board); err != nil {
 		return nil, err
 	}
+	userID, err := b.access.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if board.TemplateID > 0 {
+		if err = b.checkTemplate(ctx, board.TemplateID); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.create(ctx, board, userID, func(tx *sql.Tx, board *m.Board) error {
+		if board.TemplateID > 0 {
+			return b.boardStorage.WithTx(tx).Copy(board.TemplateID, board.ID, false, userID)
+		}
+
+		column := &m.Column{
+			Name:     "Default",
+			BoardID:  board.ID,
+			Position: DefaultColPos,
+		}
+		_, err := b.columnStorage.WithTx(tx).Save(column)
+		return err
+	})
+}
+
+// Clone will create a new board with the columns of the board with the provided ID,
+// along with its labels, tasks and their comments if the tasks are included. Any
+// member of the board can clone it and becomes the owner of the new board
+func (b *BoardService) Clone(ctx context.Context, ID uint, clone m.BoardClone) (*m.Board, error) {
+	if err := b.validator.Validate(clone); err != nil {
+		return nil, err
+	}
+	if err := b.access.onBoard(ctx, ID, m.RoleViewer); err != nil {
+		return nil, err
+	}
+	userID, err := b.access.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := b.boardStorage.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	board := &m.Board{Name: source.Name, Description: source.Description, CreatedBy: userID}
+	if clone.Name != "" {
+		board.Name = clone.Name
+	}
+	if clone.Description != "" {
+		board.Description = clone.Description
+	}
+
+	return b.create(ctx, board, userID, func(tx *sql.Tx, board *m.Board) error {
+		return b.boardStorage.WithTx(tx).Copy(ID, board.ID, clone.Include == m.CloneTasks, userID)
+	})
+}
 
+// create will save the board, fill the saved board with the provided function, make
+// the user with the provided ID its owner and record the creation within a single transaction
+func (b *BoardService) create(
+	ctx context.Context,
+	board *m.Board,
+	userID uint,
+	fill func(*sql.Tx, *m.Board) error,
+) (*m.Board, error) {
 	tx, err := b.txBeginner.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	boardStorage := b.boardStorage.WithTx(tx)
-	board, err = boardStorage.Save(board)
+	board, err = b.boardStorage.WithTx(tx).Save(board)
 	if err != nil {
 		return nil, err
 	}
+	if err = fill(tx, board); err != nil {
+		return nil, err
+	}
 
-	column := &m.Column{
-		Name:     "Default",
-		BoardID:  board.ID,
-		Position: DefaultColPos,
+	owner := &m.Member{BoardID: board.ID, UserID: userID, Role: m.RoleOwner}
+	memberStorage := b.memberStorage.WithTx(tx)
+	if _, err = memberStorage.Save(owner); err != nil {
+		return nil, err
 	}
-	columnStorage := b.columnStorage.WithTx(tx)
-	if _, err = columnStorage.Save(column); err != nil {
+	if err = b.record(ctx, tx, m.ActionCreate, nil, board); err != nil {
 		return nil, err
 	}
 
@@ -64,29 +142,156 @@ func (b *BoardService) Create(board *m.Board) (*m.Board, error) {
 	return board, nil
 }
 
-// Find will return all not deleted boards and an error in case
-// it occurred while fetching records from the storage
-func (b *BoardService) Find() ([]*m.Board, error) {
-	return b.boardStorage.Find()
+// checkTemplate will check that the board with the provided ID is a template
+// and the current user is a member of it
+func (b *BoardService) checkTemplate(ctx context.Context, ID uint) error {
+	if err := b.access.onBoard(ctx, ID, m.RoleViewer); err != nil {
+		return relation(err, ErrTemplateRelation)
+	}
+
+	template, err := b.boardStorage.FindOneById(ID)
+	if err != nil {
+		return relation(err, ErrTemplateRelation)
+	}
+	if !template.Template {
+		return ErrTemplateRelation
+	}
+
+	return nil
+}
+
+// Find will return the page of boards the current user is a member of that meet
+// the provided demand, the cursor of the next page if there is one, and an error
+// in case it occurred while fetching records from the storage
+func (b *BoardService) Find(ctx context.Context, demand BoardDemand, page Page) ([]*m.Board, *Cursor, error) {
+	userID, err := b.access.userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	demand[memberConstraint] = userID
+
+	boards, err := b.boardStorage.Find(demand, page.lookAhead())
+	if err != nil || !page.hasMore(len(boards)) {
+		return boards, nil, err
+	}
+
+	boards = boards[:page.Limit]
+	last := boards[len(boards)-1]
+
+	return boards, &Cursor{ID: last.ID}, nil
 }
 
 // FindOneById will return a pointer to the board requested by id and
 // an error in case it occurred while fetching the record from the storage
-func (b *BoardService) FindOneById(ID uint) (*m.Board, error) {
+func (b *BoardService) FindOneById(ctx context.Context, ID uint) (*m.Board, error) {
+	if err := b.access.onBoard(ctx, ID, m.RoleViewer); err != nil {
+		return nil, err
+	}
+
 	return b.boardStorage.FindOneById(ID)
 }
 
-// Update will update the board record
-func (b *BoardService) Update(board *m.Board) (*m.Board, error) {
+// Update will update the board record. The board is updated only if it has
+// the version of the provided one, unless it is zero. Only owners can update the board
+func (b *BoardService) Update(ctx context.Context, board *m.Board) (*m.Board, error) {
 	if err := b.validator.Validate(*board); err != nil {
 		return nil, err
 	}
+	if err := b.access.onBoard(ctx, board.ID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	tx, err := b.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	boardStorage := b.boardStorage.WithTx(tx)
+	before, err := boardStorage.FindOneById(board.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = matchVersion(before.Version, board.Version); err != nil {
+		return nil, err
+	}
+	board.Version = before.Version
+	if board, err = boardStorage.Update(board); err != nil {
+		return nil, versioned(err)
+	}
+	if err = b.record(ctx, tx, m.ActionUpdate, before, board); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return board, nil
+}
+
+// Patch will apply the patch to the board with the provided ID and update the board
+// with the result, which is validated as a whole. The board is patched only if it
+// has the provided version, unless it is zero, and it is updated only if it has not
+// been changed since it was read. Only owners can patch the board
+func (b *BoardService) Patch(ctx context.Context, ID uint, patch Patch, version uint) (*m.Board, error) {
+	stored, err := b.FindOneById(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = matchVersion(stored.Version, version); err != nil {
+		return nil, err
+	}
+
+	board := new(m.Board)
+	if err = patch.apply(stored, board); err != nil {
+		return nil, err
+	}
+	board.ID, board.Version = stored.ID, stored.Version
 
-	return b.boardStorage.Update(board)
+	return b.Update(ctx, board)
 }
 
 // Delete will mark a record with the given ID as deleted as well as all
-// the dependant records
-func (b *BoardService) Delete(ID uint) error {
-	return b.boardStorage.Delete(ID)
+// the dependant records, the board may be restored from the trash until it is
+// purged. The board is deleted only if it has the provided version, unless it is
+// zero. Only owners can delete the board
+func (b *BoardService) Delete(ctx context.Context, ID, version uint) error {
+	if err := b.access.onBoard(ctx, ID, m.RoleOwner); err != nil {
+		return err
+	}
+
+	tx, err := b.txBeginner.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	boardStorage := b.boardStorage.WithTx(tx)
+	board, err := boardStorage.FindOneById(ID)
+	if err != nil {
+		return err
+	}
+	if err = matchVersion(board.Version, version); err != nil {
+		return err
+	}
+	if err = boardStorage.Delete(ID); err != nil {
+		return err
+	}
+	if err = b.record(ctx, tx, m.ActionDelete, board, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// record will save the change of the board into its activity history, the board
+// is missing before its creation and after its deletion
+func (b *BoardService) record(ctx context.Context, tx *sql.Tx, action m.Action, before, after *m.Board) error {
+	board := after
+	if board == nil {
+		board = before
+	}
+	entry := m.Activity{BoardID: board.ID, Entity: m.EntityBoard, EntityID: board.ID, Action: action}
+
+	return b.journal.record(ctx, tx, entry, before, after)
 }
@@ -17,12 +17,19 @@ func TestNewBoardService(t *testing.T) {
 	boardStorage := new(MockedBoardStorage)
 	columnStorage := new(MockedColumnStorage)
 	validation := new(MockedValidation)
+	memberStorage := new(MockedMemberStorage)
+	activityStorage := new(MockedActivityStorage)
+	outboxStorage := new(MockedOutboxStorage)
 	txBeginner := new(MockedTxBeginner)
-	boardService := NewBoardService(validation, boardStorage, columnStorage, txBeginner)
+	boardService := NewBoardService(validation, boardStorage, columnStorage, memberStorage, activityStorage, outboxStorage, txBeginner)
 
 	assert.Equal(t, validation, boardService.validator)
 	assert.Equal(t, boardStorage, boardService.boardStorage)
 	assert.Equal(t, columnStorage, boardService.columnStorage)
+	assert.Equal(t, memberStorage, boardService.memberStorage)
+	assert.Equal(t, memberStorage, boardService.access.memberStorage)
+	assert.Equal(t, activityStorage, boardService.journal.activityStorage)
+	assert.Equal(t, outboxStorage, boardService.journal.outboxStorage)
 	assert.Equal(t, txBeginner, boardService.txBeginner)
 }
 
@@ -54,17 +61,26 @@ func TestBoardService_Create(t *testing.T) {
 		columnStorage.On("Save", column).Return(column, nil)
 		columnStorage.On("WithTx", tx).Return(columnStorage)
 
+		owner := &m.Member{BoardID: savedBoard.ID, UserID: 1, Role: m.RoleOwner}
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("Save", owner).Return(owner, nil)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+
 		txBeginner := new(MockedTxBeginner)
 		txBeginner.On("Begin").Return(tx, nil)
 
+		history, _ := journalStub(tx)
 		boardService := &BoardService{
+			access:        ownerAccess,
 			validator:     validation,
 			boardStorage:  boardStorage,
 			columnStorage: columnStorage,
+			memberStorage: memberStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
 
-		resultBoard, err := boardService.Create(boardIn)
+		resultBoard, err := boardService.Create(testCtx, boardIn)
 
 		assert.NotNil(t, resultBoard)
 		assert.Nil(t, err)
@@ -77,8 +93,8 @@ func TestBoardService_Create(t *testing.T) {
 		validation := new(MockedValidation)
 		validation.On("Validate", *boardIn).Return(validationErr)
 
-		boardService := &BoardService{validator: validation}
-		boardOut, resultOut := boardService.Create(boardIn)
+		boardService := &BoardService{access: ownerAccess, validator: validation}
+		boardOut, resultOut := boardService.Create(testCtx, boardIn)
 
 		assert.Equal(t, validationErr, resultOut)
 		assert.Empty(t, boardOut)
@@ -106,12 +122,15 @@ func TestBoardService_Create(t *testing.T) {
 		txBeginner := new(MockedTxBeginner)
 		txBeginner.On("Begin").Return(tx, nil)
 
+		history, _ := journalStub(tx)
 		boardService := &BoardService{
+			access:       ownerAccess,
 			validator:    validation,
 			boardStorage: boardStorage,
 			txBeginner:   txBeginner,
+			journal:      history,
 		}
-		boardOut, err := boardService.Create(boardIn)
+		boardOut, err := boardService.Create(testCtx, boardIn)
 
 		assert.Empty(t, boardOut)
 		assert.Equal(t, dbErr, err)
@@ -144,13 +163,64 @@ func TestBoardService_Create(t *testing.T) {
 		txBeginner := new(MockedTxBeginner)
 		txBeginner.On("Begin").Return(tx, nil)
 
+		history, _ := journalStub(tx)
+		boardService := &BoardService{
+			access:        ownerAccess,
+			validator:     validation,
+			boardStorage:  boardStorage,
+			columnStorage: columnStorage,
+			txBeginner:    txBeginner,
+			journal:       history,
+		}
+		boardOut, err := boardService.Create(testCtx, boardIn)
+
+		assert.Empty(t, boardOut)
+		assert.Equal(t, dbErr, err)
+	})
+	t.Run("member_save_error", func(t *testing.T) {
+		dbErr := errors.New("simple error")
+		var validationErr *v.Errors
+
+		db, dbmock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+		dbmock.ExpectBegin()
+		dbmock.ExpectRollback()
+		tx, _ := db.Begin()
+
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("Save", boardIn).Return(&m.Board{Model: m.Model{ID: 123}}, nil)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+
+		column := &m.Column{Name: "Default", Position: DefaultColPos, BoardID: 123}
+		columnStorage := new(MockedColumnStorage)
+		columnStorage.On("Save", column).Return(column, nil)
+		columnStorage.On("WithTx", tx).Return(columnStorage)
+
+		validation := new(MockedValidation)
+		validation.On("Validate", *boardIn).Return(validationErr)
+
+		owner := &m.Member{BoardID: 123, UserID: 1, Role: m.RoleOwner}
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("Save", owner).Return(&m.Member{}, dbErr)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+
+		txBeginner := new(MockedTxBeginner)
+		txBeginner.On("Begin").Return(tx, nil)
+
+		history, _ := journalStub(tx)
 		boardService := &BoardService{
+			access:        ownerAccess,
 			validator:     validation,
 			boardStorage:  boardStorage,
 			columnStorage: columnStorage,
+			memberStorage: memberStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		boardOut, err := boardService.Create(boardIn)
+		boardOut, err := boardService.Create(testCtx, boardIn)
 
 		assert.Empty(t, boardOut)
 		assert.Equal(t, dbErr, err)
@@ -173,11 +243,14 @@ func TestBoardService_Create(t *testing.T) {
 		txBeginner := new(MockedTxBeginner)
 		txBeginner.On("Begin").Return(tx, txErr)
 
+		history, _ := journalStub(tx)
 		boardService := &BoardService{
+			access:     ownerAccess,
 			validator:  validation,
 			txBeginner: txBeginner,
+			journal:    history,
 		}
-		boardOut, err := boardService.Create(boardIn)
+		boardOut, err := boardService.Create(testCtx, boardIn)
 
 		assert.Empty(t, boardOut)
 		assert.Equal(t, txErr, err)
@@ -209,22 +282,224 @@ func TestBoardService_Create(t *testing.T) {
 		columnStorage.On("Save", column).Return(column, nil)
 		columnStorage.On("WithTx", tx).Return(columnStorage)
 
+		owner := &m.Member{BoardID: savedBoard.ID, UserID: 1, Role: m.RoleOwner}
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("Save", owner).Return(owner, nil)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+
 		txBeginner := new(MockedTxBeginner)
 		txBeginner.On("Begin").Return(tx, nil)
 
+		history, _ := journalStub(tx)
 		boardService := &BoardService{
+			access:        ownerAccess,
 			validator:     validation,
 			boardStorage:  boardStorage,
 			columnStorage: columnStorage,
+			memberStorage: memberStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
 
-		resultBoard, err := boardService.Create(boardIn)
+		resultBoard, err := boardService.Create(testCtx, boardIn)
 		assert.Empty(t, resultBoard)
 		assert.Equal(t, txErr, err)
 	})
 }
 
+func TestBoardService_CreateFromTemplate(t *testing.T) {
+	var validationErr *v.Errors
+	boardIn := &m.Board{Name: "dummy", TemplateID: 7}
+	validation := new(MockedValidation)
+	validation.On("Validate", *boardIn).Return(validationErr)
+
+	t.Run("success", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
+		savedBoard := &m.Board{Model: m.Model{ID: 123}, Name: "dummy", TemplateID: 7}
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(7)).Return(&m.Board{Model: m.Model{ID: 7}, Template: true}, nil)
+		boardStorage.On("Save", boardIn).Return(savedBoard, nil)
+		boardStorage.On("Copy", uint(7), uint(123), false, uint(1)).Return(nil)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+
+		owner := &m.Member{BoardID: savedBoard.ID, UserID: 1, Role: m.RoleOwner}
+		memberStorage := roleStorage(m.RoleViewer, nil)
+		memberStorage.On("Save", owner).Return(owner, nil)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+
+		columnStorage := new(MockedColumnStorage)
+		history, _ := journalStub(tx)
+		boardService := &BoardService{
+			access:        access{memberStorage: memberStorage},
+			validator:     validation,
+			boardStorage:  boardStorage,
+			columnStorage: columnStorage,
+			memberStorage: memberStorage,
+			txBeginner:    txBeginner,
+			journal:       history,
+		}
+
+		boardOut, err := boardService.Create(testCtx, boardIn)
+
+		assert.Nil(t, err)
+		assert.Equal(t, savedBoard, boardOut)
+		boardStorage.AssertExpectations(t)
+		columnStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+	t.Run("not_a_template", func(t *testing.T) {
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(7)).Return(&m.Board{Model: m.Model{ID: 7}}, nil)
+		boardService := &BoardService{
+			access:       access{memberStorage: roleStorage(m.RoleOwner, nil)},
+			validator:    validation,
+			boardStorage: boardStorage,
+		}
+
+		boardOut, err := boardService.Create(testCtx, boardIn)
+
+		assert.Nil(t, boardOut)
+		assert.Equal(t, ErrTemplateRelation, err)
+		boardStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+	t.Run("missing_template", func(t *testing.T) {
+		boardService := &BoardService{
+			access:    access{memberStorage: roleStorage("", ErrRecordNotFound)},
+			validator: validation,
+		}
+
+		_, err := boardService.Create(testCtx, boardIn)
+
+		assert.Equal(t, ErrTemplateRelation, err)
+	})
+	t.Run("non_member_of_template", func(t *testing.T) {
+		boardService := &BoardService{
+			access:    access{memberStorage: roleStorage("", nil)},
+			validator: validation,
+		}
+
+		_, err := boardService.Create(testCtx, boardIn)
+
+		assert.Equal(t, ErrForbidden, err)
+	})
+}
+
+func TestBoardService_Clone(t *testing.T) {
+	var validationErr *v.Errors
+	source := &m.Board{Model: m.Model{ID: 7}, Name: "source", Description: "original", Template: true}
+
+	t.Run("success", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			clone     m.BoardClone
+			saved     *m.Board
+			withTasks bool
+		}{
+			{
+				name:  "columns_only",
+				clone: m.BoardClone{},
+				saved: &m.Board{Name: "source", Description: "original", CreatedBy: 1},
+			},
+			{
+				name:      "with_tasks",
+				clone:     m.BoardClone{Name: "copy", Include: m.CloneTasks},
+				saved:     &m.Board{Name: "copy", Description: "original", CreatedBy: 1},
+				withTasks: true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				txBeginner, tx := txStub(t, true)
+				validation := new(MockedValidation)
+				validation.On("Validate", tt.clone).Return(validationErr)
+
+				boardStorage := new(MockedBoardStorage)
+				boardStorage.On("FindOneById", source.ID).Return(source, nil)
+				saved := *tt.saved
+				saved.ID = 123
+				boardStorage.On("Save", tt.saved).Return(&saved, nil)
+				boardStorage.On("Copy", source.ID, uint(123), tt.withTasks, uint(1)).Return(nil)
+				boardStorage.On("WithTx", tx).Return(boardStorage)
+
+				owner := &m.Member{BoardID: 123, UserID: 1, Role: m.RoleOwner}
+				memberStorage := roleStorage(m.RoleViewer, nil)
+				memberStorage.On("Save", owner).Return(owner, nil)
+				memberStorage.On("WithTx", tx).Return(memberStorage)
+
+				history, _ := journalStub(tx)
+				boardService := &BoardService{
+					access:        access{memberStorage: memberStorage},
+					validator:     validation,
+					boardStorage:  boardStorage,
+					memberStorage: memberStorage,
+					txBeginner:    txBeginner,
+					journal:       history,
+				}
+
+				boardOut, err := boardService.Clone(testCtx, source.ID, tt.clone)
+
+				assert.Nil(t, err)
+				assert.Equal(t, uint(123), boardOut.ID)
+				assert.False(t, boardOut.Template)
+				boardStorage.AssertExpectations(t)
+			})
+		}
+	})
+	t.Run("validation_error", func(t *testing.T) {
+		clone := m.BoardClone{Include: "everything"}
+		validationErr := v.NewErrors()
+		validationErr.Add(v.Error{Field: "include", Message: "test"})
+		validation := new(MockedValidation)
+		validation.On("Validate", clone).Return(validationErr)
+
+		boardService := &BoardService{access: ownerAccess, validator: validation}
+		boardOut, err := boardService.Clone(testCtx, source.ID, clone)
+
+		assert.Nil(t, boardOut)
+		assert.Equal(t, validationErr, err)
+	})
+	t.Run("non_member", func(t *testing.T) {
+		validation := new(MockedValidation)
+		validation.On("Validate", m.BoardClone{}).Return(validationErr)
+		boardStorage := new(MockedBoardStorage)
+
+		boardService := &BoardService{
+			access:       access{memberStorage: roleStorage("", nil)},
+			validator:    validation,
+			boardStorage: boardStorage,
+		}
+		_, err := boardService.Clone(testCtx, source.ID, m.BoardClone{})
+
+		assert.Equal(t, ErrForbidden, err)
+		boardStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+	t.Run("copy_error", func(t *testing.T) {
+		dbErr := errors.New("simple error")
+		txBeginner, tx := txStub(t, false)
+		validation := new(MockedValidation)
+		validation.On("Validate", m.BoardClone{}).Return(validationErr)
+
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", source.ID).Return(source, nil)
+		boardStorage.On("Save", mock.Anything).Return(&m.Board{Model: m.Model{ID: 123}}, nil)
+		boardStorage.On("Copy", source.ID, uint(123), false, uint(1)).Return(dbErr)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+
+		history, _ := journalStub(tx)
+		boardService := &BoardService{
+			access:       ownerAccess,
+			validator:    validation,
+			boardStorage: boardStorage,
+			txBeginner:   txBeginner,
+			journal:      history,
+		}
+		boardOut, err := boardService.Clone(testCtx, source.ID, m.BoardClone{})
+
+		assert.Nil(t, boardOut)
+		assert.Equal(t, dbErr, err)
+	})
+}
+
 func TestBoardService_FindOneById(t *testing.T) {
 	const dummyID = 1234
 	boardIn := &m.Board{Model: m.Model{ID: dummyID}}
@@ -232,8 +507,8 @@ func TestBoardService_FindOneById(t *testing.T) {
 	t.Run("found", func(t *testing.T) {
 		boardStorage := new(MockedBoardStorage)
 		boardStorage.On("FindOneById", mock.Anything).Return(boardIn, nil)
-		boardService := &BoardService{boardStorage: boardStorage}
-		boardOut, err := boardService.FindOneById(dummyID)
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage}
+		boardOut, err := boardService.FindOneById(testCtx, dummyID)
 		assert.Nil(t, err)
 		assert.Equal(t, boardIn, boardOut)
 	})
@@ -241,8 +516,8 @@ func TestBoardService_FindOneById(t *testing.T) {
 	t.Run("not_found", func(t *testing.T) {
 		boardStorage := new(MockedBoardStorage)
 		boardStorage.On("FindOneById", mock.Anything).Return(boardIn, errors.New(""))
-		boardService := &BoardService{boardStorage: boardStorage}
-		boardOut, err := boardService.FindOneById(dummyID)
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage}
+		boardOut, err := boardService.FindOneById(testCtx, dummyID)
 		assert.Error(t, err)
 		assert.Equal(t, boardIn, boardOut)
 	})
@@ -255,42 +530,80 @@ func TestBoardService_Find(t *testing.T) {
 			{Name: "Test2"},
 		}
 		boardStorage := new(MockedBoardStorage)
-		boardStorage.On("Find").Return(boardsIn, nil)
-		boardService := &BoardService{boardStorage: boardStorage}
-		boardsOut, err := boardService.Find()
+		boardStorage.On("Find", mock.Anything, mock.Anything).Return(boardsIn, nil)
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage}
+		boardsOut, _, err := boardService.Find(testCtx, make(BoardDemand), Page{})
 		assert.Nil(t, err)
 		assert.Equal(t, boardsIn, boardsOut)
 	})
 
 	t.Run("not_found", func(t *testing.T) {
 		boardStorage := new(MockedBoardStorage)
-		boardStorage.On("Find", mock.Anything).Return([]*m.Board{}, errors.New(mock.Anything))
-		boardService := &BoardService{boardStorage: boardStorage}
-		boardOut, err := boardService.Find()
+		boardStorage.On("Find", mock.Anything, mock.Anything).Return([]*m.Board{}, errors.New(mock.Anything))
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage}
+		boardOut, _, err := boardService.Find(testCtx, make(BoardDemand), Page{})
 		assert.Error(t, err)
 		assert.Empty(t, boardOut)
 	})
+
+	t.Run("member_boards", func(t *testing.T) {
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("Find", BoardDemand{"member": uint(1)}, mock.Anything).Return([]*m.Board{}, nil)
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage}
+		_, _, err := boardService.Find(testCtx, make(BoardDemand), Page{})
+		assert.Nil(t, err)
+		boardStorage.AssertExpectations(t)
+	})
+
+	t.Run("next_page", func(t *testing.T) {
+		boardsIn := []*m.Board{
+			{Model: m.Model{ID: 1}, Name: "Test1"},
+			{Model: m.Model{ID: 2}, Name: "Test2"},
+			{Model: m.Model{ID: 3}, Name: "Test3"},
+		}
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("Find", mock.Anything, Page{Limit: 3}).Return(boardsIn, nil)
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage}
+		boardsOut, next, err := boardService.Find(testCtx, make(BoardDemand), Page{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, boardsIn[:2], boardsOut)
+		assert.Equal(t, &Cursor{ID: 2}, next)
+	})
 }
 
 func TestBoardService_Update(t *testing.T) {
-	var boardIn = &m.Board{Name: "dummy"}
+	var boardIn = &m.Board{Model: m.Model{ID: 3}, Name: "dummy"}
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", *boardIn).Return(validationErr)
 
 	t.Run("success", func(t *testing.T) {
-		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, true)
 		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(3)).Return(&m.Board{Model: m.Model{ID: 3}, Name: "old"}, nil)
 		boardStorage.On("Update", boardIn).Return(boardIn, nil)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
 
-		validation := new(MockedValidation)
-		validation.On("Validate", *boardIn).Return(validationErr)
-
+		history, activityStorage := journalStub(tx)
 		boardService := &BoardService{
+			access:       ownerAccess,
 			boardStorage: boardStorage,
 			validator:    validation,
+			txBeginner:   txBeginner,
+			journal:      history,
 		}
-		boardOut, err := boardService.Update(boardIn)
+		boardOut, err := boardService.Update(testCtx, boardIn)
 
 		assert.NotNil(t, boardOut)
 		assert.Nil(t, err)
+		assert.Equal(t, &m.Activity{
+			BoardID:  3,
+			Entity:   m.EntityBoard,
+			EntityID: 3,
+			Action:   m.ActionUpdate,
+			ActorID:  1,
+			Changes:  m.Changes{"name": {From: "old", To: "dummy"}},
+		}, recorded(activityStorage, 0))
 	})
 
 	t.Run("validation_error", func(t *testing.T) {
@@ -300,8 +613,8 @@ func TestBoardService_Update(t *testing.T) {
 		validation := new(MockedValidation)
 		validation.On("Validate", *boardIn).Return(validationErr)
 
-		boardService := &BoardService{validator: validation}
-		boardOut, resultOut := boardService.Update(boardIn)
+		boardService := &BoardService{access: ownerAccess, validator: validation}
+		boardOut, resultOut := boardService.Update(testCtx, boardIn)
 
 		assert.Equal(t, validationErr, resultOut)
 		assert.Empty(t, boardOut)
@@ -309,39 +622,195 @@ func TestBoardService_Update(t *testing.T) {
 
 	t.Run("database_error", func(t *testing.T) {
 		dbErr := errors.New("simple error")
-		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, false)
 		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(3)).Return(boardIn, nil)
 		boardStorage.On("Update", boardIn).Return(&m.Board{}, dbErr)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
 
-		validation := new(MockedValidation)
-		validation.On("Validate", *boardIn).Return(validationErr)
-
+		history, activityStorage := journalStub(tx)
 		boardService := &BoardService{
+			access:       ownerAccess,
 			boardStorage: boardStorage,
 			validator:    validation,
+			txBeginner:   txBeginner,
+			journal:      history,
 		}
-		boardOut, err := boardService.Update(boardIn)
+		boardOut, err := boardService.Update(testCtx, boardIn)
 
 		assert.Empty(t, boardOut)
 		assert.Equal(t, err, dbErr)
+		activityStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("version_mismatch", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(3)).Return(&m.Board{Model: m.Model{ID: 3, Version: 5}, Name: "old"}, nil)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+		validation := new(MockedValidation)
+		validation.On("Validate", mock.Anything).Return(validationErr)
+
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage, validator: validation, txBeginner: txBeginner}
+		_, err := boardService.Update(testCtx, &m.Board{Model: m.Model{ID: 3, Version: 4}, Name: "dummy"})
+
+		assert.Equal(t, ErrVersionMismatch, err)
+		boardStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("changed_concurrently", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(3)).Return(&m.Board{Model: m.Model{ID: 3, Version: 4}, Name: "old"}, nil)
+		boardStorage.On("Update", mock.MatchedBy(func(board *m.Board) bool { return board.Version == 4 })).
+			Return((*m.Board)(nil), ErrRecordNotFound)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+		validation := new(MockedValidation)
+		validation.On("Validate", mock.Anything).Return(validationErr)
+
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage, validator: validation, txBeginner: txBeginner}
+		_, err := boardService.Update(testCtx, &m.Board{Model: m.Model{ID: 3}, Name: "dummy"})
+
+		assert.Equal(t, ErrVersionMismatch, err)
+	})
+}
+
+func TestBoardService_Patch(t *testing.T) {
+	var validationErr *v.Errors
+	patch := Patch{Type: MergePatch, Document: []byte(`{"name":"dummy"}`)}
+
+	t.Run("success", func(t *testing.T) {
+		stored := &m.Board{Model: m.Model{ID: 3, Version: 4}, Name: "old", Description: "kept", CreatedBy: 1}
+		patched := &m.Board{Model: m.Model{ID: 3, Version: 4}, Name: "dummy", Description: "kept", CreatedBy: 1}
+		txBeginner, tx := txStub(t, true)
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(3)).Return(stored, nil)
+		boardStorage.On("Update", patched).Return(patched, nil)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+		validation := new(MockedValidation)
+		validation.On("Validate", *patched).Return(validationErr)
+
+		history, _ := journalStub(tx)
+		boardService := &BoardService{
+			access:       ownerAccess,
+			boardStorage: boardStorage,
+			validator:    validation,
+			txBeginner:   txBeginner,
+			journal:      history,
+		}
+		boardOut, err := boardService.Patch(testCtx, 3, patch, 4)
+
+		assert.Nil(t, err)
+		assert.Equal(t, patched, boardOut)
+	})
+
+	t.Run("version_mismatch", func(t *testing.T) {
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(3)).Return(&m.Board{Model: m.Model{ID: 3, Version: 5}, Name: "old"}, nil)
+
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage}
+		_, err := boardService.Patch(testCtx, 3, patch, 4)
+
+		assert.Equal(t, ErrVersionMismatch, err)
+		boardStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("invalid_patch", func(t *testing.T) {
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("FindOneById", uint(3)).Return(&m.Board{Model: m.Model{ID: 3, Version: 5}, Name: "old"}, nil)
+
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage}
+		_, err := boardService.Patch(testCtx, 3, Patch{Type: MergePatch, Document: []byte(`{"name":1}`)}, 0)
+
+		assert.Equal(t, ErrInvalidPatch, err)
+		boardStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestBoardService_Access(t *testing.T) {
+	boardIn := &m.Board{Model: m.Model{ID: 1}, Name: "dummy"}
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", *boardIn).Return(validationErr)
+	boardStorage := new(MockedBoardStorage)
+	boardStorage.On("FindOneById", boardIn.ID).Return(boardIn, nil)
+
+	t.Run("viewer_can_read", func(t *testing.T) {
+		boardService := &BoardService{access: access{memberStorage: roleStorage(m.RoleViewer, nil)}, boardStorage: boardStorage}
+		boardOut, err := boardService.FindOneById(testCtx, boardIn.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, boardIn, boardOut)
+	})
+
+	t.Run("non_member_can_not_read", func(t *testing.T) {
+		boardService := &BoardService{access: access{memberStorage: roleStorage("", nil)}, boardStorage: boardStorage}
+		_, err := boardService.FindOneById(testCtx, boardIn.ID)
+		assert.Equal(t, ErrForbidden, err)
+	})
+
+	t.Run("editor_can_not_update", func(t *testing.T) {
+		boardService := &BoardService{
+			access:       access{memberStorage: roleStorage(m.RoleEditor, nil)},
+			boardStorage: boardStorage,
+			validator:    validation,
+		}
+		_, err := boardService.Update(testCtx, boardIn)
+		assert.Equal(t, ErrForbidden, err)
+		boardStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("editor_can_not_delete", func(t *testing.T) {
+		boardService := &BoardService{access: access{memberStorage: roleStorage(m.RoleEditor, nil)}, boardStorage: boardStorage}
+		err := boardService.Delete(testCtx, boardIn.ID, 0)
+		assert.Equal(t, ErrForbidden, err)
+		boardStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("missing_board", func(t *testing.T) {
+		boardService := &BoardService{access: access{memberStorage: roleStorage("", ErrRecordNotFound)}, boardStorage: boardStorage}
+		err := boardService.Delete(testCtx, boardIn.ID, 0)
+		assert.Equal(t, ErrRecordNotFound, err)
 	})
 }
 
 func TestBoardService_Delete(t *testing.T) {
 	t.Run("successful_delete", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
 		boardStorage := new(MockedBoardStorage)
-		boardStorage.On("Delete", mock.Anything).Return(nil)
-		boardService := &BoardService{boardStorage: boardStorage}
-		err := boardService.Delete(0)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+		boardStorage.On("FindOneById", uint(2)).Return(&m.Board{Model: m.Model{ID: 2}, Name: "dummy"}, nil)
+		boardStorage.On("Delete", uint(2)).Return(nil)
+		history, activityStorage := journalStub(tx)
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage, journal: history, txBeginner: txBeginner}
+		err := boardService.Delete(testCtx, 2, 0)
 		assert.Nil(t, err)
+
+		entry := recorded(activityStorage, 0)
+		assert.Equal(t, m.ActionDelete, entry.Action)
+		assert.Equal(t, m.EntityBoard, entry.Entity)
+		assert.Equal(t, uint(2), entry.EntityID)
+		assert.Equal(t, "dummy", entry.Changes["name"].From)
 	})
 
 	t.Run("database_error", func(t *testing.T) {
 		errorIn := errors.New("test")
+		txBeginner, tx := txStub(t, false)
 		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+		boardStorage.On("FindOneById", mock.Anything).Return(&m.Board{}, nil)
 		boardStorage.On("Delete", mock.Anything).Return(errorIn)
-		boardService := &BoardService{boardStorage: boardStorage}
-		err := boardService.Delete(0)
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage, txBeginner: txBeginner}
+		err := boardService.Delete(testCtx, 0, 0)
 		assert.Equal(t, errorIn, err)
 	})
+	t.Run("version_mismatch", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		boardStorage := new(MockedBoardStorage)
+		boardStorage.On("WithTx", tx).Return(boardStorage)
+		boardStorage.On("FindOneById", uint(2)).Return(&m.Board{Model: m.Model{ID: 2, Version: 3}}, nil)
+		boardService := &BoardService{access: ownerAccess, boardStorage: boardStorage, txBeginner: txBeginner}
+
+		assert.Equal(t, ErrVersionMismatch, boardService.Delete(testCtx, 2, 2))
+		boardStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
 }
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"database/sql"
+
 	m "github.com/dnozdrin/detask/internal/domain/models"
 	v "github.com/dnozdrin/detask/internal/domain/validation"
 )
@@ -14,6 +17,8 @@ type ColumnService struct {
 	columnStorage ColumnStorage
 	taskStorage   TaskStorage
 	txBeginner    TxBeginner
+	access        access
+	journal       journal
 }
 
 // NewColumnService is a column service constructor
@@ -21,6 +26,9 @@ func NewColumnService(
 	validator v.Validator,
 	columnStorage ColumnStorage,
 	taskStorage TaskStorage,
+	memberStorage MemberStorage,
+	activityStorage ActivityStorage,
+	outboxStorage OutboxStorage,
 	txBeginner TxBeginner,
 ) ColumnService {
 	return ColumnService{
@@ -28,45 +36,299 @@ func NewColumnService(
 		taskStorage:   taskStorage,
 		validator:     validator,
 		txBeginner:    txBeginner,
+		access:        access{memberStorage: memberStorage},
+		journal:       newJournal(activityStorage, outboxStorage),
 	}
 }
 
 // Create will create a new column with the provided payload. Returns the
-// operation result with possible validation or saving errors
-func (c ColumnService) Create(column *m.Column) (*m.Column, error) {
+// operation result with possible validation or saving errors. Only board
+// owners can create columns
+func (c ColumnService) Create(ctx context.Context, column *m.Column) (*m.Column, error) {
 	if err := c.validator.Validate(*column); err != nil {
 		return nil, err
 	}
+	if err := c.access.onBoard(ctx, column.BoardID, m.RoleOwner); err != nil {
+		return nil, relation(err, ErrBoardRelation)
+	}
 
-	return c.columnStorage.Save(column)
+	return c.write(ctx, m.ActionCreate, func(columnStorage ColumnStorage) (*m.Column, *m.Column, error) {
+		saved, err := columnStorage.Save(column)
+		return nil, saved, err
+	})
 }
 
-// Find will return all not deleted columns and an error in case
-// it occurred while fetching records from the storage
-func (c ColumnService) Find(demand ColumnDemand) ([]*m.Column, error) {
-	return c.columnStorage.Find(demand)
+// Find will return the page of columns of the boards the current user is a member
+// of that meet the provided demand, the cursor of the next page if there is one,
+// and an error in case it occurred while fetching records from the storage
+func (c ColumnService) Find(ctx context.Context, demand ColumnDemand, page Page) ([]*m.Column, *Cursor, error) {
+	userID, err := c.access.userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	demand[memberConstraint] = userID
+
+	columns, err := c.columnStorage.Find(demand, page.lookAhead())
+	if err != nil || !page.hasMore(len(columns)) {
+		return columns, nil, err
+	}
+
+	columns = columns[:page.Limit]
+	last := columns[len(columns)-1]
+
+	return columns, &Cursor{ID: last.ID, Position: last.Position}, nil
 }
 
 // FindOneById will return a pointer to the column requested by id and
 // an error in case it occurred while fetching the record from the storage
-func (c ColumnService) FindOneById(ID uint) (*m.Column, error) {
+func (c ColumnService) FindOneById(ctx context.Context, ID uint) (*m.Column, error) {
+	if err := c.access.onColumn(ctx, ID, m.RoleViewer); err != nil {
+		return nil, err
+	}
+
 	return c.columnStorage.FindOneById(ID)
 }
 
-// Update will update the column record. Returns the operation result
-// with possible validation or saving errors
-func (c ColumnService) Update(column *m.Column) (*m.Column, error) {
+// Update will update the column record. The column is updated only if it has the
+// version of the provided one, unless it is zero. Returns the operation result
+// with possible validation or saving errors. Only board owners can update columns
+func (c ColumnService) Update(ctx context.Context, column *m.Column) (*m.Column, error) {
 	if err := c.validator.Validate(*column); err != nil {
 		return nil, err
 	}
+	if err := c.access.onColumn(ctx, column.ID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	return c.write(ctx, m.ActionUpdate, func(columnStorage ColumnStorage) (*m.Column, *m.Column, error) {
+		before, err := columnStorage.FindOneById(column.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = matchVersion(before.Version, column.Version); err != nil {
+			return nil, nil, err
+		}
+		column.Version = before.Version
+		updated, err := columnStorage.Update(column)
+		return before, updated, versioned(err)
+	})
+}
+
+// Patch will apply the patch to the column with the provided ID and update the column
+// with the result, which is validated as a whole. The column is patched only if it
+// has the provided version, unless it is zero, and it is updated only if it has not
+// been changed since it was read. Only board owners can patch columns
+func (c ColumnService) Patch(ctx context.Context, ID uint, patch Patch, version uint) (*m.Column, error) {
+	stored, err := c.FindOneById(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = matchVersion(stored.Version, version); err != nil {
+		return nil, err
+	}
 
-	return c.columnStorage.Update(column)
+	column := new(m.Column)
+	if err = patch.apply(stored, column); err != nil {
+		return nil, err
+	}
+	column.ID, column.Version = stored.ID, stored.Version
+
+	return c.Update(ctx, column)
+}
+
+// write will change the column with the provided function, that returns the states
+// of the column before and after the change, and record the change within a single
+// transaction
+func (c ColumnService) write(
+	ctx context.Context,
+	action m.Action,
+	change func(ColumnStorage) (before, after *m.Column, err error),
+) (*m.Column, error) {
+	tx, err := c.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	before, after, err := change(c.columnStorage.WithTx(tx))
+	if err != nil {
+		return nil, err
+	}
+	if err = c.record(ctx, tx, action, before, after); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// record will save the change of the column into the activity history of its board,
+// the column is missing before its creation and after its deletion
+func (c ColumnService) record(ctx context.Context, tx *sql.Tx, action m.Action, before, after *m.Column) error {
+	column := after
+	if column == nil {
+		column = before
+	}
+	entry := m.Activity{BoardID: column.BoardID, Entity: m.EntityColumn, EntityID: column.ID, Action: action}
+
+	return c.journal.record(ctx, tx, entry, before, after)
+}
+
+// Move will move the column right before or right after another column of its
+// board, or to the end of the board. The position of the column is computed
+// between the positions of its new neighbours, all the columns of the board are
+// rebalanced when there is no room left between them. Only board owners can
+// move columns
+func (c ColumnService) Move(ctx context.Context, ID uint, move m.ColumnMove) (*m.Column, error) {
+	if err := c.validator.Validate(move); err != nil {
+		return nil, err
+	}
+	if err := c.access.onColumn(ctx, ID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	columnStorage := c.columnStorage.WithTx(tx)
+	column, err := columnStorage.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	before := *column
+	columns, err := columnStorage.Find(ColumnDemand{"board": column.BoardID}, Page{})
+	if err != nil {
+		return nil, err
+	}
+
+	IDs, positions := make([]uint, 0, len(columns)), make([]float64, 0, len(columns))
+	for _, other := range columns {
+		if other.ID != ID {
+			IDs, positions = append(IDs, other.ID), append(positions, other.Position)
+		}
+	}
+	index, ok := moveIndex(IDs, move.BeforeID, move.AfterID)
+	if !ok {
+		return nil, ErrColumnRelation
+	}
+
+	if position, ok := positionAt(positions, index); ok {
+		column.Position = position
+		if _, err = columnStorage.Update(column); err != nil {
+			return nil, err
+		}
+	} else if err = columnStorage.Rebalance(column.BoardID, insertID(IDs, index, ID), positionStep); err != nil {
+		return nil, err
+	}
+
+	if column, err = columnStorage.FindOneById(ID); err != nil {
+		return nil, err
+	}
+	if err = c.record(ctx, tx, m.ActionMove, &before, column); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return column, nil
+}
+
+// Reorder will rewrite the positions of the columns of the board following the
+// provided order, which must list every column of the board exactly once. Returns
+// the columns of the board in the new order. Only board owners can reorder columns
+func (c ColumnService) Reorder(ctx context.Context, boardID uint, order m.ColumnOrder) ([]*m.Column, error) {
+	if err := c.validator.Validate(order); err != nil {
+		return nil, err
+	}
+	if err := c.access.onBoard(ctx, boardID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	tx, err := c.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	columnStorage := c.columnStorage.WithTx(tx)
+	demand := ColumnDemand{"board": boardID}
+	before, err := columnStorage.Find(demand, Page{})
+	if err != nil {
+		return nil, err
+	}
+	if !listsEvery(order.ColumnIDs, before) {
+		return nil, ErrColumnOrder
+	}
+	if err = columnStorage.Rebalance(boardID, order.ColumnIDs, positionStep); err != nil {
+		return nil, err
+	}
+
+	columns, err := columnStorage.Find(demand, Page{})
+	if err != nil {
+		return nil, err
+	}
+	if err = c.recordMoves(ctx, tx, before, columns); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// recordMoves will record the move of every column that has changed its position
+func (c ColumnService) recordMoves(ctx context.Context, tx *sql.Tx, before, after []*m.Column) error {
+	positions := make(map[uint]*m.Column, len(before))
+	for _, column := range before {
+		positions[column.ID] = column
+	}
+	for _, column := range after {
+		if previous, ok := positions[column.ID]; ok && previous.Position != column.Position {
+			if err := c.record(ctx, tx, m.ActionMove, previous, column); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// listsEvery reports if the provided IDs list every one of the columns exactly once
+func listsEvery(IDs []uint, columns []*m.Column) bool {
+	if len(IDs) != len(columns) {
+		return false
+	}
+	listed := make(map[uint]bool, len(IDs))
+	for _, ID := range IDs {
+		listed[ID] = true
+	}
+	for _, column := range columns {
+		if !listed[column.ID] {
+			return false
+		}
+	}
+
+	return true
 }
 
 // Delete will the column with the provided ID. The last column cannot be deleted.
 // When a column is deleted, its tasks are moved to the column to the left of the
-// current or to the right of the current if the curring is the leftmost
-func (c ColumnService) Delete(ID uint) error {
+// current or to the right of the current if the curring is the leftmost. The
+// column may be restored from the trash until it is purged. The column is deleted
+// only if it has the provided version, unless it is zero.
+// Only board owners can delete columns
+func (c ColumnService) Delete(ctx context.Context, ID, version uint) error {
+	if err := c.access.onColumn(ctx, ID, m.RoleOwner); err != nil {
+		return err
+	}
+
 	tx, err := c.txBeginner.Begin()
 	if err != nil {
 		return err
@@ -78,6 +340,9 @@ func (c ColumnService) Delete(ID uint) error {
 	if err != nil {
 		return ErrRecordNotFound
 	}
+	if err = matchVersion(column.Version, version); err != nil {
+		return err
+	}
 
 	columnsNum, err := columnStorage.CountColumnsByBoard(column.BoardID)
 	if err != nil {
@@ -102,6 +367,9 @@ func (c ColumnService) Delete(ID uint) error {
 	if err = columnStorage.Delete(ID); err != nil {
 		return err
 	}
+	if err = c.record(ctx, tx, m.ActionDelete, column, nil); err != nil {
+		return err
+	}
 
 	return tx.Commit()
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
+	"math"
 	"testing"
 
 	m "github.com/dnozdrin/detask/internal/domain/models"
@@ -18,32 +19,50 @@ func TestNewColumnService(t *testing.T) {
 	validation := new(MockedValidation)
 	txBeginner := new(MockedTxBeginner)
 	taskStorage := new(MockedTaskStorage)
-	columnService := NewColumnService(validation, columnStorage, taskStorage, txBeginner)
+	memberStorage := new(MockedMemberStorage)
+	activityStorage := new(MockedActivityStorage)
+	outboxStorage := new(MockedOutboxStorage)
+	columnService := NewColumnService(validation, columnStorage, taskStorage, memberStorage, activityStorage, outboxStorage, txBeginner)
 
 	assert.Equal(t, columnStorage, columnService.columnStorage)
 	assert.Equal(t, taskStorage, columnService.taskStorage)
 	assert.Equal(t, txBeginner, columnService.txBeginner)
 	assert.Equal(t, validation, columnService.validator)
+	assert.Equal(t, memberStorage, columnService.access.memberStorage)
+	assert.Equal(t, activityStorage, columnService.journal.activityStorage)
+	assert.Equal(t, outboxStorage, columnService.journal.outboxStorage)
 }
 
 func TestColumnService_Create(t *testing.T) {
 	var columnIn = &m.Column{Name: "dummy"}
 	t.Run("success", func(t *testing.T) {
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, true)
 		columnStorage := new(MockedColumnStorage)
-		columnStorage.On("Save", columnIn).Return(columnIn, nil)
+		columnStorage.On("WithTx", tx).Return(columnStorage)
+		columnStorage.On("Save", columnIn).Return(&m.Column{Model: m.Model{ID: 4}, Name: "dummy", BoardID: 2}, nil)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *columnIn).Return(validationErr)
 
+		history, activityStorage := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			validator:     validation,
 			columnStorage: columnStorage,
+			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		columnOut, err := columnService.Create(columnIn)
+		columnOut, err := columnService.Create(testCtx, columnIn)
 
 		assert.NotNil(t, columnOut)
 		assert.Nil(t, err)
+
+		entry := recorded(activityStorage, 0)
+		assert.Equal(t, uint(2), entry.BoardID)
+		assert.Equal(t, m.EntityColumn, entry.Entity)
+		assert.Equal(t, uint(4), entry.EntityID)
+		assert.Equal(t, m.ActionCreate, entry.Action)
 	})
 	t.Run("validation_error", func(t *testing.T) {
 		validationErr := v.NewErrors()
@@ -52,8 +71,8 @@ func TestColumnService_Create(t *testing.T) {
 		validation := new(MockedValidation)
 		validation.On("Validate", *columnIn).Return(validationErr)
 
-		columnService := &ColumnService{validator: validation}
-		columnOut, err := columnService.Create(columnIn)
+		columnService := &ColumnService{access: ownerAccess, validator: validation}
+		columnOut, err := columnService.Create(testCtx, columnIn)
 
 		assert.Equal(t, validationErr, err)
 		assert.Empty(t, columnOut)
@@ -62,23 +81,62 @@ func TestColumnService_Create(t *testing.T) {
 		var validationErr *v.Errors
 		err := errors.New("simple error")
 
+		txBeginner, tx := txStub(t, false)
 		columnStorage := new(MockedColumnStorage)
+		columnStorage.On("WithTx", tx).Return(columnStorage)
 		columnStorage.On("Save", columnIn).Return(&m.Column{}, err)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *columnIn).Return(validationErr)
 
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			validator:     validation,
 			columnStorage: columnStorage,
+			txBeginner:    txBeginner,
 		}
-		columnOut, resultOut := columnService.Create(columnIn)
+		columnOut, resultOut := columnService.Create(testCtx, columnIn)
 
 		assert.Equal(t, resultOut, err)
 		assert.Empty(t, columnOut)
 	})
 }
 
+func TestColumnService_Access(t *testing.T) {
+	columnIn := &m.Column{Name: "dummy", BoardID: 1}
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", *columnIn).Return(validationErr)
+	columnStorage := new(MockedColumnStorage)
+
+	t.Run("editor_can_not_create", func(t *testing.T) {
+		columnService := &ColumnService{
+			access:        access{memberStorage: roleStorage(m.RoleEditor, nil)},
+			columnStorage: columnStorage,
+			validator:     validation,
+		}
+		_, err := columnService.Create(testCtx, columnIn)
+		assert.Equal(t, ErrForbidden, err)
+		columnStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("missing_board", func(t *testing.T) {
+		columnService := &ColumnService{
+			access:        access{memberStorage: roleStorage("", ErrRecordNotFound)},
+			columnStorage: columnStorage,
+			validator:     validation,
+		}
+		_, err := columnService.Create(testCtx, columnIn)
+		assert.Equal(t, ErrBoardRelation, err)
+	})
+
+	t.Run("editor_can_not_delete", func(t *testing.T) {
+		columnService := &ColumnService{access: access{memberStorage: roleStorage(m.RoleEditor, nil)}}
+		err := columnService.Delete(testCtx, 1, 0)
+		assert.Equal(t, ErrForbidden, err)
+	})
+}
+
 func TestColumnService_FindOneById(t *testing.T) {
 	const dummyID = 1234
 	columnIn := &m.Column{Model: m.Model{ID: dummyID}}
@@ -86,8 +144,8 @@ func TestColumnService_FindOneById(t *testing.T) {
 	t.Run("found", func(t *testing.T) {
 		columnStorage := new(MockedColumnStorage)
 		columnStorage.On("FindOneById", mock.Anything).Return(columnIn, nil)
-		columnService := &ColumnService{columnStorage: columnStorage}
-		columnOut, err := columnService.FindOneById(dummyID)
+		columnService := &ColumnService{access: ownerAccess, columnStorage: columnStorage}
+		columnOut, err := columnService.FindOneById(testCtx, dummyID)
 		assert.Nil(t, err)
 		assert.Equal(t, columnIn, columnOut)
 	})
@@ -95,8 +153,8 @@ func TestColumnService_FindOneById(t *testing.T) {
 	t.Run("not_found", func(t *testing.T) {
 		columnStorage := new(MockedColumnStorage)
 		columnStorage.On("FindOneById", mock.Anything).Return(columnIn, errors.New(""))
-		columnService := &ColumnService{columnStorage: columnStorage}
-		columnOut, err := columnService.FindOneById(dummyID)
+		columnService := &ColumnService{access: ownerAccess, columnStorage: columnStorage}
+		columnOut, err := columnService.FindOneById(testCtx, dummyID)
 		assert.Error(t, err)
 		assert.Equal(t, columnIn, columnOut)
 	})
@@ -109,18 +167,18 @@ func TestColumnService_Find(t *testing.T) {
 			{Name: "Test2"},
 		}
 		columnStorage := new(MockedColumnStorage)
-		columnStorage.On("Find", mock.Anything).Return(columnsIn, nil)
-		columnService := &ColumnService{columnStorage: columnStorage}
-		columnsOut, err := columnService.Find(make(ColumnDemand))
+		columnStorage.On("Find", mock.Anything, mock.Anything).Return(columnsIn, nil)
+		columnService := &ColumnService{access: ownerAccess, columnStorage: columnStorage}
+		columnsOut, _, err := columnService.Find(testCtx, make(ColumnDemand), Page{})
 		assert.Nil(t, err)
 		assert.Equal(t, columnsIn, columnsOut)
 	})
 
 	t.Run("not_found", func(t *testing.T) {
 		columnStorage := new(MockedColumnStorage)
-		columnStorage.On("Find", mock.Anything).Return([]*m.Column{}, errors.New(""))
-		columnService := &ColumnService{columnStorage: columnStorage}
-		columnOut, err := columnService.Find(make(ColumnDemand))
+		columnStorage.On("Find", mock.Anything, mock.Anything).Return([]*m.Column{}, errors.New(""))
+		columnService := &ColumnService{access: ownerAccess, columnStorage: columnStorage}
+		columnOut, _, err := columnService.Find(testCtx, make(ColumnDemand), Page{})
 		assert.Error(t, err)
 		assert.Empty(t, columnOut)
 	})
@@ -131,20 +189,28 @@ func TestColumnService_Update(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, true)
 		columnStorage := new(MockedColumnStorage)
+		columnStorage.On("WithTx", tx).Return(columnStorage)
+		columnStorage.On("FindOneById", uint(0)).Return(&m.Column{Name: "old"}, nil)
 		columnStorage.On("Update", columnIn).Return(columnIn, nil)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *columnIn).Return(validationErr)
 
+		history, activityStorage := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			validator:     validation,
+			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		columnOut, resultOut := columnService.Update(columnIn)
+		columnOut, resultOut := columnService.Update(testCtx, columnIn)
 
 		assert.NotNil(t, columnOut)
 		assert.Nil(t, resultOut)
+		assert.Equal(t, m.Changes{"name": {From: "old", To: "dummy"}}, recorded(activityStorage, 0).Changes)
 	})
 
 	t.Run("validation_error", func(t *testing.T) {
@@ -154,8 +220,8 @@ func TestColumnService_Update(t *testing.T) {
 		validation := new(MockedValidation)
 		validation.On("Validate", *columnIn).Return(validationErr)
 
-		columnService := &ColumnService{validator: validation}
-		columnOut, err := columnService.Update(columnIn)
+		columnService := &ColumnService{access: ownerAccess, validator: validation}
+		columnOut, err := columnService.Update(testCtx, columnIn)
 
 		assert.Equal(t, validationErr, err)
 		assert.Empty(t, columnOut)
@@ -163,17 +229,22 @@ func TestColumnService_Update(t *testing.T) {
 
 	t.Run("database_error", func(t *testing.T) {
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, false)
 		columnStorage := new(MockedColumnStorage)
+		columnStorage.On("WithTx", tx).Return(columnStorage)
+		columnStorage.On("FindOneById", uint(0)).Return(columnIn, nil)
 		columnStorage.On("Update", columnIn).Return(&m.Column{}, errors.New("simple error"))
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *columnIn).Return(validationErr)
 
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			validator:     validation,
 			columnStorage: columnStorage,
+			txBeginner:    txBeginner,
 		}
-		columnOut, resultOut := columnService.Update(columnIn)
+		columnOut, resultOut := columnService.Update(testCtx, columnIn)
 
 		assert.Error(t, resultOut)
 		assert.Empty(t, columnOut)
@@ -214,12 +285,15 @@ func TestColumnService_Delete(t *testing.T) {
 		columnStorage.On("CountColumnsByBoard", boardId).Return(columnsOnBoard, nil)
 		columnStorage.On("FindColumnToTheLeft", currColID).Return(leftColID, nil)
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			taskStorage:   taskStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Nil(t, err)
 	})
 
@@ -246,11 +320,14 @@ func TestColumnService_Delete(t *testing.T) {
 		columnStorage.On("FindOneById", currColID).Return(currColumn, nil)
 		columnStorage.On("CountColumnsByBoard", boardId).Return(1, nil)
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Equal(t, ErrLastColumn, err)
 	})
 
@@ -268,10 +345,13 @@ func TestColumnService_Delete(t *testing.T) {
 		txBeginner := new(MockedTxBeginner)
 		txBeginner.On("Begin").Return(tx, txErr)
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:     ownerAccess,
 			txBeginner: txBeginner,
+			journal:    history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Equal(t, txErr, err)
 	})
 
@@ -293,11 +373,14 @@ func TestColumnService_Delete(t *testing.T) {
 		columnStorage.On("WithTx", tx).Return(columnStorage)
 		columnStorage.On("FindOneById", currColID).Return(&m.Column{}, errors.New("not found"))
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Equal(t, ErrRecordNotFound, err)
 	})
 
@@ -322,11 +405,14 @@ func TestColumnService_Delete(t *testing.T) {
 		columnStorage.On("FindOneById", currColID).Return(&m.Column{BoardID: boardId}, nil)
 		columnStorage.On("CountColumnsByBoard", boardId).Return(0, countErr)
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Equal(t, countErr, err)
 	})
 
@@ -357,12 +443,15 @@ func TestColumnService_Delete(t *testing.T) {
 		columnStorage.On("FindColumnToTheLeft", currColID).Return(uint(0), searchErr)
 		columnStorage.On("FindColumnToTheRight", currColID).Return(rightColID, nil)
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			taskStorage:   taskStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Nil(t, err)
 	})
 
@@ -389,11 +478,14 @@ func TestColumnService_Delete(t *testing.T) {
 		columnStorage.On("FindColumnToTheLeft", currColID).Return(uint(0), searchErr)
 		columnStorage.On("FindColumnToTheRight", currColID).Return(uint(0), searchErr)
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Equal(t, ErrTargetColumn, err)
 	})
 
@@ -423,12 +515,15 @@ func TestColumnService_Delete(t *testing.T) {
 		columnStorage.On("CountColumnsByBoard", boardId).Return(columnsOnBoard, nil)
 		columnStorage.On("FindColumnToTheLeft", currColID).Return(leftColID, nil)
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			taskStorage:   taskStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Equal(t, moveErr, err)
 	})
 
@@ -458,12 +553,187 @@ func TestColumnService_Delete(t *testing.T) {
 		columnStorage.On("CountColumnsByBoard", boardId).Return(columnsOnBoard, nil)
 		columnStorage.On("FindColumnToTheLeft", currColID).Return(leftColID, nil)
 
+		history, _ := journalStub(tx)
 		columnService := &ColumnService{
+			access:        ownerAccess,
 			columnStorage: columnStorage,
 			taskStorage:   taskStorage,
 			txBeginner:    txBeginner,
+			journal:       history,
 		}
-		err = columnService.Delete(currColID)
+		err = columnService.Delete(testCtx, currColID, 0)
 		assert.Equal(t, dbErr, err)
 	})
 }
+
+func TestColumnService_Move(t *testing.T) {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+	board := []*m.Column{
+		{Model: m.Model{ID: 1}, BoardID: 1, Position: 1000},
+		{Model: m.Model{ID: 2}, BoardID: 1, Position: 2000},
+		{Model: m.Model{ID: 3}, BoardID: 1, Position: 3000},
+	}
+
+	tests := []struct {
+		name     string
+		move     m.ColumnMove
+		position float64
+	}{
+		{"to_the_end", m.ColumnMove{}, 4000},
+		{"before_the_first", m.ColumnMove{BeforeID: 1}, 500},
+		{"after_the_first", m.ColumnMove{AfterID: 1}, 2000},
+		{"after_the_last", m.ColumnMove{AfterID: 3}, 4000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stored := &m.Column{Model: m.Model{ID: 2}, BoardID: 1, Position: 2000}
+			txBeginner, tx := txStub(t, true)
+			columnStorage := new(MockedColumnStorage)
+			columnStorage.On("WithTx", tx).Return(columnStorage)
+			columnStorage.On("FindOneById", uint(2)).Return(stored, nil)
+			columnStorage.On("Find", ColumnDemand{"board": uint(1)}, Page{}).Return(board, nil)
+			columnStorage.On("Update", mock.MatchedBy(func(column *m.Column) bool {
+				return column.ID == 2 && column.Position == test.position
+			})).Return(stored, nil)
+
+			history, _ := journalStub(tx)
+			columnService := &ColumnService{
+				access:        ownerAccess,
+				columnStorage: columnStorage,
+				txBeginner:    txBeginner,
+				journal:       history,
+				validator:     validation,
+			}
+			_, err := columnService.Move(testCtx, 2, test.move)
+			assert.Nil(t, err)
+			columnStorage.AssertNotCalled(t, "Rebalance", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("rebalance", func(t *testing.T) {
+		crowded := []*m.Column{
+			{Model: m.Model{ID: 1}, BoardID: 1, Position: math.SmallestNonzeroFloat64},
+			{Model: m.Model{ID: 2}, BoardID: 1, Position: 1},
+		}
+		txBeginner, tx := txStub(t, true)
+		columnStorage := new(MockedColumnStorage)
+		columnStorage.On("WithTx", tx).Return(columnStorage)
+		columnStorage.On("FindOneById", uint(2)).Return(crowded[1], nil)
+		columnStorage.On("Find", ColumnDemand{"board": uint(1)}, Page{}).Return(crowded, nil)
+		columnStorage.On("Rebalance", uint(1), []uint{2, 1}, float64(positionStep)).Return(nil)
+
+		history, _ := journalStub(tx)
+		columnService := &ColumnService{
+			access:        ownerAccess,
+			columnStorage: columnStorage,
+			txBeginner:    txBeginner,
+			journal:       history,
+			validator:     validation,
+		}
+		_, err := columnService.Move(testCtx, 2, m.ColumnMove{BeforeID: 1})
+		assert.Nil(t, err)
+		columnStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("foreign_column", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		columnStorage := new(MockedColumnStorage)
+		columnStorage.On("WithTx", tx).Return(columnStorage)
+		columnStorage.On("FindOneById", uint(2)).Return(board[1], nil)
+		columnStorage.On("Find", ColumnDemand{"board": uint(1)}, Page{}).Return(board, nil)
+
+		history, _ := journalStub(tx)
+		columnService := &ColumnService{
+			access:        ownerAccess,
+			columnStorage: columnStorage,
+			txBeginner:    txBeginner,
+			journal:       history,
+			validator:     validation,
+		}
+		_, err := columnService.Move(testCtx, 2, m.ColumnMove{AfterID: 9})
+		assert.Equal(t, ErrColumnRelation, err)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		columnService := &ColumnService{
+			access:    access{memberStorage: roleStorage(m.RoleEditor, nil)},
+			validator: validation,
+		}
+		_, err := columnService.Move(testCtx, 2, m.ColumnMove{})
+		assert.Equal(t, ErrForbidden, err)
+	})
+}
+
+func TestColumnService_Reorder(t *testing.T) {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+	board := []*m.Column{
+		{Model: m.Model{ID: 1}, BoardID: 1, Position: 1000},
+		{Model: m.Model{ID: 2}, BoardID: 1, Position: 2000},
+		{Model: m.Model{ID: 3}, BoardID: 1, Position: 3000},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
+		columnStorage := new(MockedColumnStorage)
+		columnStorage.On("WithTx", tx).Return(columnStorage)
+		reordered := []*m.Column{
+			{Model: m.Model{ID: 3}, BoardID: 1, Position: 1000},
+			{Model: m.Model{ID: 1}, BoardID: 1, Position: 2000},
+			{Model: m.Model{ID: 2}, BoardID: 1, Position: 3000},
+		}
+		columnStorage.On("Find", ColumnDemand{"board": uint(1)}, Page{}).Return(board, nil).Once()
+		columnStorage.On("Rebalance", uint(1), []uint{3, 1, 2}, float64(positionStep)).Return(nil)
+		columnStorage.On("Find", ColumnDemand{"board": uint(1)}, Page{}).Return(reordered, nil).Once()
+
+		history, activityStorage := journalStub(tx)
+		columnService := &ColumnService{
+			access:        ownerAccess,
+			columnStorage: columnStorage,
+			txBeginner:    txBeginner,
+			journal:       history,
+			validator:     validation,
+		}
+		columns, err := columnService.Reorder(testCtx, 1, m.ColumnOrder{ColumnIDs: []uint{3, 1, 2}})
+		assert.Nil(t, err)
+		assert.Equal(t, reordered, columns)
+		for n, ID := range []uint{3, 1, 2} {
+			entry := recorded(activityStorage, n)
+			assert.Equal(t, m.ActionMove, entry.Action)
+			assert.Equal(t, ID, entry.EntityID)
+		}
+	})
+
+	tests := []struct {
+		name string
+		IDs  []uint
+	}{
+		{"missing_column", []uint{3, 1}},
+		{"foreign_column", []uint{3, 1, 4}},
+		{"duplicate_column", []uint{3, 1, 1}},
+		{"extra_column", []uint{3, 1, 2, 4}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			txBeginner, tx := txStub(t, false)
+			columnStorage := new(MockedColumnStorage)
+			columnStorage.On("WithTx", tx).Return(columnStorage)
+			columnStorage.On("Find", ColumnDemand{"board": uint(1)}, Page{}).Return(board, nil)
+
+			history, _ := journalStub(tx)
+			columnService := &ColumnService{
+				access:        ownerAccess,
+				columnStorage: columnStorage,
+				txBeginner:    txBeginner,
+				journal:       history,
+				validator:     validation,
+			}
+			_, err := columnService.Reorder(testCtx, 1, m.ColumnOrder{ColumnIDs: test.IDs})
+			assert.Equal(t, ErrColumnOrder, err)
+			columnStorage.AssertNotCalled(t, "Rebalance", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
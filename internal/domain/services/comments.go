@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"database/sql"
+
 	m "github.com/dnozdrin/detask/internal/domain/models"
 	v "github.com/dnozdrin/detask/internal/domain/validation"
 )
@@ -9,49 +12,188 @@ import (
 type CommentService struct {
 	validator      v.Validator
 	commentStorage CommentStorage
+	txBeginner     TxBeginner
+	access         access
+	journal        journal
 }
 
 // NewCommentService is a comment service constructor
-func NewCommentService(validator v.Validator, commentStorage CommentStorage) *CommentService {
+func NewCommentService(
+	validator v.Validator,
+	commentStorage CommentStorage,
+	memberStorage MemberStorage,
+	activityStorage ActivityStorage,
+	outboxStorage OutboxStorage,
+	txBeginner TxBeginner,
+) *CommentService {
 	return &CommentService{
 		commentStorage: commentStorage,
 		validator:      validator,
+		txBeginner:     txBeginner,
+		access:         access{memberStorage: memberStorage},
+		journal:        newJournal(activityStorage, outboxStorage),
 	}
 }
 
 // Create will create a new comment  with the provided payload. Returns the
-// operation result with possible validation or saving errors
-func (c *CommentService) Create(comment *m.Comment) (*m.Comment, error) {
+// operation result with possible validation or saving errors. Only board
+// editors and owners can create comments
+func (c *CommentService) Create(ctx context.Context, comment *m.Comment) (*m.Comment, error) {
 	if err := c.validator.Validate(*comment); err != nil {
 		return nil, err
 	}
+	if err := c.access.onTask(ctx, comment.TaskID, m.RoleEditor); err != nil {
+		return nil, relation(err, ErrTaskRelation)
+	}
 
-	return c.commentStorage.Save(comment)
+	return c.write(ctx, m.ActionCreate, nil, func(commentStorage CommentStorage) (*m.Comment, error) {
+		return commentStorage.Save(comment)
+	})
 }
 
-// Find will return all comments that meet the provided demand and an
-// error in case it occurred while fetching records from the storage
-func (c *CommentService) Find(demand CommentDemand) ([]*m.Comment, error) {
-	return c.commentStorage.Find(demand)
+// Find will return the page of comments of the boards the current user is a member
+// of that meet the provided demand, the cursor of the next page if there is one,
+// and an error in case it occurred while fetching records from the storage
+func (c *CommentService) Find(ctx context.Context, demand CommentDemand, page Page) ([]*m.Comment, *Cursor, error) {
+	userID, err := c.access.userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	demand[memberConstraint] = userID
+
+	comments, err := c.commentStorage.Find(demand, page.lookAhead())
+	if err != nil || !page.hasMore(len(comments)) {
+		return comments, nil, err
+	}
+
+	comments = comments[:page.Limit]
+	last := comments[len(comments)-1]
+
+	return comments, &Cursor{ID: last.ID, CreatedAt: last.CreatedAt}, nil
 }
 
 // FindOneById will return a pointer to the comment requested by id and
 // an error in case it occurred while fetching the record from the storage
-func (c *CommentService) FindOneById(ID uint) (*m.Comment, error) {
-	return c.commentStorage.FindOneById(ID)
+func (c *CommentService) FindOneById(ctx context.Context, ID uint) (*m.Comment, error) {
+	return c.findOneWithRole(ctx, ID, m.RoleViewer)
 }
 
-// Update will update the comment record. Returns the operation result
-// with possible validation or saving errors
-func (c *CommentService) Update(comment *m.Comment) (*m.Comment, error) {
+// Update will update the comment record. The comment is updated only if it has
+// the version of the provided one, unless it is zero. Returns the operation result
+// with possible validation or saving errors. Only board editors and owners
+// can update comments
+func (c *CommentService) Update(ctx context.Context, comment *m.Comment) (*m.Comment, error) {
 	if err := c.validator.Validate(*comment); err != nil {
 		return nil, err
 	}
+	before, err := c.findOneWithRole(ctx, comment.ID, m.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err = matchVersion(before.Version, comment.Version); err != nil {
+		return nil, err
+	}
+	comment.Version = before.Version
+
+	return c.write(ctx, m.ActionUpdate, before, func(commentStorage CommentStorage) (*m.Comment, error) {
+		updated, err := commentStorage.Update(comment)
+		return updated, versioned(err)
+	})
+}
+
+// Patch will apply the patch to the comment with the provided ID and update the comment
+// with the result, which is validated as a whole. The comment is patched only if it
+// has the provided version, unless it is zero, and it is updated only if it has not
+// been changed since it was read. Only board editors and owners can patch comments
+func (c *CommentService) Patch(ctx context.Context, ID uint, patch Patch, version uint) (*m.Comment, error) {
+	stored, err := c.FindOneById(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = matchVersion(stored.Version, version); err != nil {
+		return nil, err
+	}
+
+	comment := new(m.Comment)
+	if err = patch.apply(stored, comment); err != nil {
+		return nil, err
+	}
+	comment.ID, comment.Version = stored.ID, stored.Version
+
+	return c.Update(ctx, comment)
+}
+
+// Delete will mark a record with the given ID as deleted, the comment may be
+// restored from the trash until it is purged. The comment is deleted only if it
+// has the provided version, unless it is zero. Only board editors and owners
+// can delete comments
+func (c *CommentService) Delete(ctx context.Context, ID, version uint) error {
+	before, err := c.findOneWithRole(ctx, ID, m.RoleEditor)
+	if err != nil {
+		return err
+	}
+	if err = matchVersion(before.Version, version); err != nil {
+		return err
+	}
+
+	_, err = c.write(ctx, m.ActionDelete, before, func(commentStorage CommentStorage) (*m.Comment, error) {
+		return nil, commentStorage.Delete(ID)
+	})
+
+	return err
+}
+
+// write will change the comment with the provided function, that returns the state
+// of the comment after the change, and record the change from the provided state
+// within a single transaction. The comment is missing before its creation and after
+// its deletion
+func (c *CommentService) write(
+	ctx context.Context,
+	action m.Action,
+	before *m.Comment,
+	change func(CommentStorage) (*m.Comment, error),
+) (*m.Comment, error) {
+	tx, err := c.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	after, err := change(c.commentStorage.WithTx(tx))
+	if err != nil {
+		return nil, err
+	}
+	if err = c.record(ctx, tx, action, before, after); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// record will save the change of the comment into the activity history of its task
+func (c *CommentService) record(ctx context.Context, tx *sql.Tx, action m.Action, before, after *m.Comment) error {
+	comment := after
+	if comment == nil {
+		comment = before
+	}
+	entry := m.Activity{TaskID: comment.TaskID, Entity: m.EntityComment, EntityID: comment.ID, Action: action}
 
-	return c.commentStorage.Update(comment)
+	return c.journal.record(ctx, tx, entry, before, after)
 }
 
-// Delete will delete a record with the given ID
-func (c *CommentService) Delete(ID uint) error {
-	return c.commentStorage.Delete(ID)
+// findOneWithRole will return the comment with the provided ID if the current
+// user has the required role on the board of the comment task
+func (c *CommentService) findOneWithRole(ctx context.Context, ID uint, required m.Role) (*m.Comment, error) {
+	comment, err := c.commentStorage.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.access.onTask(ctx, comment.TaskID, required); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
 }
@@ -15,30 +15,50 @@ import (
 func TestNewCommentService(t *testing.T) {
 	commentStorage := new(MockedCommentStorage)
 	validation := new(MockedValidation)
-	commentService := NewCommentService(validation, commentStorage)
+	memberStorage := new(MockedMemberStorage)
+	activityStorage := new(MockedActivityStorage)
+	outboxStorage := new(MockedOutboxStorage)
+	txBeginner := new(MockedTxBeginner)
+	commentService := NewCommentService(validation, commentStorage, memberStorage, activityStorage, outboxStorage, txBeginner)
 
 	assert.Equal(t, commentStorage, commentService.commentStorage)
 	assert.Equal(t, validation, commentService.validator)
+	assert.Equal(t, memberStorage, commentService.access.memberStorage)
+	assert.Equal(t, activityStorage, commentService.journal.activityStorage)
+	assert.Equal(t, outboxStorage, commentService.journal.outboxStorage)
+	assert.Equal(t, txBeginner, commentService.txBeginner)
 }
 
 func TestCommentService_Create(t *testing.T) {
 	var commentIn = &m.Comment{Text: "dummy"}
 	t.Run("success", func(t *testing.T) {
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, true)
 		commentStorage := new(MockedCommentStorage)
-		commentStorage.On("Save", commentIn).Return(commentIn, nil)
+		commentStorage.On("WithTx", tx).Return(commentStorage)
+		commentStorage.On("Save", commentIn).Return(&m.Comment{Model: m.Model{ID: 6}, Text: "dummy", TaskID: 3}, nil)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *commentIn).Return(validationErr)
 
+		history, activityStorage := journalStub(tx)
 		commentService := &CommentService{
+			access:         ownerAccess,
 			commentStorage: commentStorage,
 			validator:      validation,
+			txBeginner:     txBeginner,
+			journal:        history,
 		}
-		commentOut, err := commentService.Create(commentIn)
+		commentOut, err := commentService.Create(testCtx, commentIn)
 
 		assert.NotNil(t, commentOut)
 		assert.Nil(t, err)
+
+		entry := recorded(activityStorage, 0)
+		assert.Equal(t, uint(3), entry.TaskID)
+		assert.Equal(t, m.EntityComment, entry.Entity)
+		assert.Equal(t, uint(6), entry.EntityID)
+		assert.Equal(t, m.ActionCreate, entry.Action)
 	})
 	t.Run("validation_error", func(t *testing.T) {
 		validationErr := v.NewErrors()
@@ -47,8 +67,8 @@ func TestCommentService_Create(t *testing.T) {
 		validation := new(MockedValidation)
 		validation.On("Validate", *commentIn).Return(validationErr)
 
-		commentService := &CommentService{validator: validation}
-		commentOut, err := commentService.Create(commentIn)
+		commentService := &CommentService{access: ownerAccess, validator: validation}
+		commentOut, err := commentService.Create(testCtx, commentIn)
 
 		assert.Equal(t, validationErr, err)
 		assert.Empty(t, commentOut)
@@ -56,18 +76,22 @@ func TestCommentService_Create(t *testing.T) {
 	t.Run("database_error", func(t *testing.T) {
 		var validationErr *v.Errors
 		dbErr := errors.New("simple error")
+		txBeginner, tx := txStub(t, false)
 		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("WithTx", tx).Return(commentStorage)
 		commentStorage.On("Save", commentIn).Return(&m.Comment{}, dbErr)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *commentIn).Return(validationErr)
 
 		commentService := &CommentService{
+			access:         ownerAccess,
 			commentStorage: commentStorage,
 			validator:      validation,
+			txBeginner:     txBeginner,
 		}
 
-		commentOut, err := commentService.Create(commentIn)
+		commentOut, err := commentService.Create(testCtx, commentIn)
 
 		assert.Empty(t, commentOut)
 		assert.Equal(t, err, dbErr)
@@ -81,8 +105,8 @@ func TestCommentService_FindOneById(t *testing.T) {
 	t.Run("found", func(t *testing.T) {
 		commentStorage := new(MockedCommentStorage)
 		commentStorage.On("FindOneById", mock.Anything).Return(commentIn, nil)
-		commentService := &CommentService{commentStorage: commentStorage}
-		commentOut, err := commentService.FindOneById(dummyID)
+		commentService := &CommentService{access: ownerAccess, commentStorage: commentStorage}
+		commentOut, err := commentService.FindOneById(testCtx, dummyID)
 		assert.Nil(t, err)
 		assert.Equal(t, commentIn, commentOut)
 	})
@@ -90,10 +114,22 @@ func TestCommentService_FindOneById(t *testing.T) {
 	t.Run("not_found", func(t *testing.T) {
 		commentStorage := new(MockedCommentStorage)
 		commentStorage.On("FindOneById", mock.Anything).Return(commentIn, errors.New(""))
-		commentService := &CommentService{commentStorage: commentStorage}
-		commentOut, err := commentService.FindOneById(dummyID)
+		commentService := &CommentService{access: ownerAccess, commentStorage: commentStorage}
+		commentOut, err := commentService.FindOneById(testCtx, dummyID)
 		assert.Error(t, err)
-		assert.Equal(t, commentIn, commentOut)
+		assert.Nil(t, commentOut)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("FindOneById", mock.Anything).Return(commentIn, nil)
+		commentService := &CommentService{
+			access:         access{memberStorage: roleStorage("", nil)},
+			commentStorage: commentStorage,
+		}
+		commentOut, err := commentService.FindOneById(testCtx, dummyID)
+		assert.Equal(t, ErrForbidden, err)
+		assert.Nil(t, commentOut)
 	})
 }
 
@@ -104,18 +140,18 @@ func TestCommentService_Find(t *testing.T) {
 			{Text: "Test2"},
 		}
 		commentStorage := new(MockedCommentStorage)
-		commentStorage.On("Find", mock.Anything).Return(commentsIn, nil)
-		commentService := &CommentService{commentStorage: commentStorage}
-		commentsOut, err := commentService.Find(make(CommentDemand))
+		commentStorage.On("Find", mock.Anything, mock.Anything).Return(commentsIn, nil)
+		commentService := &CommentService{access: ownerAccess, commentStorage: commentStorage}
+		commentsOut, _, err := commentService.Find(testCtx, make(CommentDemand), Page{})
 		assert.Nil(t, err)
 		assert.Equal(t, commentsIn, commentsOut)
 	})
 
 	t.Run("not_found", func(t *testing.T) {
 		commentStorage := new(MockedCommentStorage)
-		commentStorage.On("Find", mock.Anything).Return([]*m.Comment{}, errors.New(""))
-		commentService := &CommentService{commentStorage: commentStorage}
-		commentOut, err := commentService.Find(make(CommentDemand))
+		commentStorage.On("Find", mock.Anything, mock.Anything).Return([]*m.Comment{}, errors.New(""))
+		commentService := &CommentService{access: ownerAccess, commentStorage: commentStorage}
+		commentOut, _, err := commentService.Find(testCtx, make(CommentDemand), Page{})
 		assert.Error(t, err)
 		assert.Empty(t, commentOut)
 	})
@@ -126,20 +162,28 @@ func TestCommentService_Update(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, true)
 		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("WithTx", tx).Return(commentStorage)
+		commentStorage.On("FindOneById", commentIn.ID).Return(&m.Comment{Text: "old"}, nil)
 		commentStorage.On("Update", commentIn).Return(commentIn, nil)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *commentIn).Return(validationErr)
 
+		history, activityStorage := journalStub(tx)
 		commentService := &CommentService{
+			access:         ownerAccess,
 			commentStorage: commentStorage,
 			validator:      validation,
+			txBeginner:     txBeginner,
+			journal:        history,
 		}
-		commentOut, err := commentService.Update(commentIn)
+		commentOut, err := commentService.Update(testCtx, commentIn)
 
 		assert.NotNil(t, commentOut)
 		assert.Nil(t, err)
+		assert.Equal(t, m.Changes{"text": {From: "old", To: "dummy"}}, recorded(activityStorage, 0).Changes)
 	})
 
 	t.Run("validation_error", func(t *testing.T) {
@@ -149,8 +193,8 @@ func TestCommentService_Update(t *testing.T) {
 		validation := new(MockedValidation)
 		validation.On("Validate", *commentIn).Return(validationErr)
 
-		commentService := &CommentService{validator: validation}
-		commentOut, err := commentService.Update(commentIn)
+		commentService := &CommentService{access: ownerAccess, validator: validation}
+		commentOut, err := commentService.Update(testCtx, commentIn)
 
 		assert.Equal(t, validationErr, err)
 		assert.Empty(t, commentOut)
@@ -159,38 +203,102 @@ func TestCommentService_Update(t *testing.T) {
 	t.Run("database_error", func(t *testing.T) {
 		dbErr := errors.New("simple error")
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, false)
 		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("WithTx", tx).Return(commentStorage)
+		commentStorage.On("FindOneById", commentIn.ID).Return(commentIn, nil)
 		commentStorage.On("Update", commentIn).Return(&m.Comment{}, dbErr)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *commentIn).Return(validationErr)
 
 		commentService := &CommentService{
+			access:         ownerAccess,
 			commentStorage: commentStorage,
 			validator:      validation,
+			txBeginner:     txBeginner,
 		}
-		commentOut, err := commentService.Update(commentIn)
+		commentOut, err := commentService.Update(testCtx, commentIn)
 
 		assert.Empty(t, commentOut)
 		assert.Equal(t, err, dbErr)
 	})
+
+	t.Run("changed_concurrently", func(t *testing.T) {
+		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, false)
+		commentIn := &m.Comment{Model: m.Model{ID: 4, Version: 2}, Text: "dummy"}
+		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("WithTx", tx).Return(commentStorage)
+		commentStorage.On("FindOneById", uint(4)).Return(&m.Comment{Model: m.Model{ID: 4, Version: 2}, Text: "old"}, nil)
+		commentStorage.On("Update", commentIn).Return((*m.Comment)(nil), ErrRecordNotFound)
+
+		validation := new(MockedValidation)
+		validation.On("Validate", *commentIn).Return(validationErr)
+
+		commentService := &CommentService{
+			access:         ownerAccess,
+			commentStorage: commentStorage,
+			validator:      validation,
+			txBeginner:     txBeginner,
+		}
+		_, err := commentService.Update(testCtx, commentIn)
+
+		assert.Equal(t, ErrVersionMismatch, err)
+	})
 }
 
 func TestCommentService_Delete(t *testing.T) {
 	t.Run("successful_delete", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
 		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("WithTx", tx).Return(commentStorage)
+		commentStorage.On("FindOneById", mock.Anything).Return(&m.Comment{Text: "dummy"}, nil)
 		commentStorage.On("Delete", mock.Anything).Return(nil)
-		commentService := &CommentService{commentStorage: commentStorage}
-		err := commentService.Delete(0)
+		history, activityStorage := journalStub(tx)
+		commentService := &CommentService{
+			access:         ownerAccess,
+			commentStorage: commentStorage,
+			txBeginner:     txBeginner,
+			journal:        history,
+		}
+		err := commentService.Delete(testCtx, 0, 0)
 		assert.Nil(t, err)
+
+		entry := recorded(activityStorage, 0)
+		assert.Equal(t, m.ActionDelete, entry.Action)
+		assert.Equal(t, "dummy", entry.Changes["text"].From)
 	})
 
 	t.Run("database_error", func(t *testing.T) {
 		errorIn := errors.New("test")
+		txBeginner, tx := txStub(t, false)
 		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("WithTx", tx).Return(commentStorage)
+		commentStorage.On("FindOneById", mock.Anything).Return(&m.Comment{}, nil)
 		commentStorage.On("Delete", mock.Anything).Return(errorIn)
-		commentService := &CommentService{commentStorage: commentStorage}
-		err := commentService.Delete(0)
+		commentService := &CommentService{access: ownerAccess, commentStorage: commentStorage, txBeginner: txBeginner}
+		err := commentService.Delete(testCtx, 0, 0)
 		assert.Equal(t, errorIn, err)
 	})
+
+	t.Run("viewer_forbidden", func(t *testing.T) {
+		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("FindOneById", mock.Anything).Return(&m.Comment{}, nil)
+		commentService := &CommentService{
+			access:         access{memberStorage: roleStorage(m.RoleViewer, nil)},
+			commentStorage: commentStorage,
+		}
+		err := commentService.Delete(testCtx, 0, 0)
+		assert.Equal(t, ErrForbidden, err)
+		commentStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+	t.Run("version_mismatch", func(t *testing.T) {
+		commentStorage := new(MockedCommentStorage)
+		commentStorage.On("FindOneById", uint(4)).Return(&m.Comment{Model: m.Model{ID: 4, Version: 3}}, nil)
+		commentService := &CommentService{access: ownerAccess, commentStorage: commentStorage}
+
+		assert.Equal(t, ErrVersionMismatch, commentService.Delete(testCtx, 4, 1))
+		commentStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
 }
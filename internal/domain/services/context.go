@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+)
+
+type contextKey int
+
+const (
+	userKey contextKey = iota
+	rulesKey
+)
+
+// WithUser will return a copy of the provided context that carries the
+// authenticated user
+func WithUser(ctx context.Context, user *m.User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFromContext will return the authenticated user carried by the provided
+// context, if there is one
+func UserFromContext(ctx context.Context) (*m.User, bool) {
+	user, ok := ctx.Value(userKey).(*m.User)
+
+	return user, ok && user != nil
+}
+
+// withRules will return a copy of the provided context that carries the chain of
+// the automation rules which actions are being taken
+func withRules(ctx context.Context, rules []uint) context.Context {
+	return context.WithValue(ctx, rulesKey, rules)
+}
+
+// rulesFromContext will return the chain of the automation rules carried by the
+// provided context, if there is one
+func rulesFromContext(ctx context.Context) []uint {
+	rules, _ := ctx.Value(rulesKey).([]uint)
+
+	return rules
+}
@@ -1,6 +1,13 @@
 package services
 
-import "github.com/pkg/errors"
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+)
 
 // ErrFilterNotAllowed is returned in case of unsupported filter parameters
 // are passed
@@ -8,32 +15,144 @@ var ErrFilterNotAllowed = errors.Errorf("filter demand is not allowed")
 
 // Demand represents an interface for constraints container
 type Demand interface {
-	Add(string, uint) error
+	Add(field, value string) error
 }
 
-type constraints map[string]uint
+// constraints maps the filtered fields to their typed values: uint for
+// identifiers, time.Time for dates, bool for flags and string for texts
+type constraints map[string]interface{}
+
+// filterKind parses the raw value of a filter into its typed value
+type filterKind func(string) (interface{}, error)
 
-var allowedColumnFilter = map[string]struct{}{
-	"board": {},
+var (
+	idFilter filterKind = func(value string) (interface{}, error) {
+		ID, err := strconv.ParseUint(value, 10, 0)
+		return uint(ID), err
+	}
+	timeFilter filterKind = func(value string) (interface{}, error) {
+		t, err := time.Parse(time.RFC3339, value)
+		return t.UTC(), err
+	}
+	boolFilter filterKind = func(value string) (interface{}, error) {
+		return strconv.ParseBool(value)
+	}
+	stringFilter filterKind = func(value string) (interface{}, error) {
+		return value, nil
+	}
+	trashTypeFilter filterKind = func(value string) (interface{}, error) {
+		kind := m.TrashType(value)
+		if !kind.Valid() {
+			return nil, errors.Errorf("unknown type %q", value)
+		}
+		return kind, nil
+	}
+	entityFilter filterKind = func(value string) (interface{}, error) {
+		entity := m.Entity(value)
+		if !entity.Valid() {
+			return nil, errors.Errorf("unknown entity %q", value)
+		}
+		return entity, nil
+	}
+	priorityFilter filterKind = func(value string) (interface{}, error) {
+		priority := m.Priority(value)
+		if priority.Rank() == 0 {
+			return nil, errors.Errorf("unknown priority %q", value)
+		}
+		return priority, nil
+	}
+)
+
+// sortFilter returns the kind of the sort parameter, that is a comma separated
+// list of the provided fields, each of them may be prefixed with "-" for the
+// descending order
+func sortFilter(fields ...string) filterKind {
+	return func(value string) (interface{}, error) {
+		sort := make(Sort, 0)
+		seen := make(map[string]bool)
+		for _, field := range strings.Split(value, ",") {
+			key := SortKey{Field: strings.TrimPrefix(field, "-"), Desc: strings.HasPrefix(field, "-")}
+			if !containsField(fields, key.Field) || seen[key.Field] {
+				return nil, errors.Errorf("unable to sort by %q", field)
+			}
+			seen[key.Field] = true
+			sort = append(sort, key)
+		}
+		return sort, nil
+	}
 }
 
-// ColumnDemand is a constraints container for tasks
-type ColumnDemand constraints
+// containsField reports if the provided fields contain the given one
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
 
-// Add will add allowed filter constraints to the ColumnDemand or will
-// return an error if the field / value constraint is not in allowlist
-func (cd ColumnDemand) Add(field string, value uint) error {
-	if _, ok := allowedColumnFilter[field]; !ok {
+	return false
+}
+
+// add parses the value with the kind the field has in the allowlist and adds
+// it to the constraints
+func (c constraints) add(allowed map[string]filterKind, field, value string) error {
+	kind, ok := allowed[field]
+	if !ok {
 		return ErrFilterNotAllowed
 	}
+	typed, err := kind(value)
+	if err != nil {
+		return errors.Wrapf(err, "invalid value of the %s filter", field)
+	}
 
-	cd[field] = value
+	c[field] = typed
 	return nil
 }
 
-var allowedTaskFilter = map[string]struct{}{
-	"board":  {},
-	"column": {},
+// memberConstraint limits the found records to the boards the user with the
+// provided ID is a member of. It is set by services and can not be requested
+// by the API clients, as it is absent in the allowlists.
+const memberConstraint = "member"
+
+var allowedBoardFilter = map[string]filterKind{
+	"template": boolFilter,
+}
+
+// BoardDemand is a constraints container for boards
+type BoardDemand constraints
+
+// Add will add allowed filter constraints to the BoardDemand or will
+// return an error if the field / value constraint is not in allowlist
+func (bd BoardDemand) Add(field, value string) error {
+	return constraints(bd).add(allowedBoardFilter, field, value)
+}
+
+var allowedColumnFilter = map[string]filterKind{
+	"board": idFilter,
+}
+
+// ColumnDemand is a constraints container for tasks
+type ColumnDemand constraints
+
+// Add will add allowed filter constraints to the ColumnDemand or will
+// return an error if the field / value constraint is not in allowlist
+func (cd ColumnDemand) Add(field, value string) error {
+	return constraints(cd).add(allowedColumnFilter, field, value)
+}
+
+var allowedTaskFilter = map[string]filterKind{
+	"board":    idFilter,
+	"column":   idFilter,
+	"assignee": idFilter,
+	"label":    idFilter,
+
+	"due_before": timeFilter,
+	"due_after":  timeFilter,
+	"overdue":    boolFilter,
+	"priority":   priorityFilter,
+	"q":          stringFilter,
+
+	"sort": sortFilter("position", "priority", "due"),
 }
 
 // TaskDemand is a constraints container for tasks
@@ -41,17 +160,50 @@ type TaskDemand constraints
 
 // Add will add allowed filter constraints to the TaskDemand or will
 // return an error if the field / value constraint is not in allowlist
-func (td TaskDemand) Add(field string, value uint) error {
-	if _, ok := allowedTaskFilter[field]; !ok {
-		return ErrFilterNotAllowed
+func (td TaskDemand) Add(field, value string) error {
+	return constraints(td).add(allowedTaskFilter, field, value)
+}
+
+// Sort returns the requested order of the tasks, the tasks are sorted by
+// position unless another order is requested
+func (td TaskDemand) Sort() Sort {
+	if sort, ok := td["sort"].(Sort); ok {
+		return sort
 	}
 
-	td[field] = value
-	return nil
+	return Sort{{Field: "position"}}
+}
+
+var allowedSearchFilter = map[string]filterKind{
+	"q":     stringFilter,
+	"board": idFilter,
 }
 
-var allowedCommentFilter = map[string]struct{}{
-	"task": {},
+// SearchDemand is a constraints container for the search of tasks and comments,
+// the q constraint is the searched text
+type SearchDemand constraints
+
+// Add will add allowed filter constraints to the SearchDemand or will
+// return an error if the field / value constraint is not in allowlist
+func (sd SearchDemand) Add(field, value string) error {
+	return constraints(sd).add(allowedSearchFilter, field, value)
+}
+
+var allowedLabelFilter = map[string]filterKind{
+	"board": idFilter,
+}
+
+// LabelDemand is a constraints container for labels
+type LabelDemand constraints
+
+// Add will add allowed filter constraints to the LabelDemand or will
+// return an error if the field / value constraint is not in allowlist
+func (ld LabelDemand) Add(field, value string) error {
+	return constraints(ld).add(allowedLabelFilter, field, value)
+}
+
+var allowedCommentFilter = map[string]filterKind{
+	"task": idFilter,
 }
 
 // CommentDemand is a constraints container for comments
@@ -59,11 +211,35 @@ type CommentDemand constraints
 
 // Add will add allowed filter constraints to the CommentDemand or will
 // return an error if the field / value constraint is not in allowlist
-func (cd CommentDemand) Add(field string, value uint) error {
-	if _, ok := allowedCommentFilter[field]; !ok {
-		return ErrFilterNotAllowed
-	}
+func (cd CommentDemand) Add(field, value string) error {
+	return constraints(cd).add(allowedCommentFilter, field, value)
+}
 
-	cd[field] = value
-	return nil
+var allowedTrashFilter = map[string]filterKind{
+	"board": idFilter,
+	"type":  trashTypeFilter,
+}
+
+// TrashDemand is a constraints container for deleted records
+type TrashDemand constraints
+
+// Add will add allowed filter constraints to the TrashDemand or will
+// return an error if the field / value constraint is not in allowlist
+func (td TrashDemand) Add(field, value string) error {
+	return constraints(td).add(allowedTrashFilter, field, value)
+}
+
+var allowedActivityFilter = map[string]filterKind{
+	"actor":  idFilter,
+	"entity": entityFilter,
+}
+
+// ActivityDemand is a constraints container for activity entries, the board or
+// the task the entries are requested for is set by services
+type ActivityDemand constraints
+
+// Add will add allowed filter constraints to the ActivityDemand or will
+// return an error if the field / value constraint is not in allowlist
+func (ad ActivityDemand) Add(field, value string) error {
+	return constraints(ad).add(allowedActivityFilter, field, value)
 }
@@ -4,22 +4,25 @@ package services
 
 import (
 	"testing"
+	"time"
 
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestCommentDemand_Add(t *testing.T) {
 	type args struct {
 		field string
-		value uint
+		value string
 	}
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
-		{"success_task", args{"task", 1}, false},
-		{"error", args{mock.Anything, 1}, true},
+		{"success_task", args{"task", "1"}, false},
+		{"error", args{mock.Anything, "1"}, true},
 	}
 	demand := make(CommentDemand)
 	for _, tt := range tests {
@@ -34,16 +37,31 @@ func TestCommentDemand_Add(t *testing.T) {
 func TestTaskDemand_Add(t *testing.T) {
 	type args struct {
 		field string
-		value uint
+		value string
 	}
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
-		{"success_board", args{"board", 1}, false},
-		{"success_column", args{"column", 1}, false},
-		{"error", args{mock.Anything, 1}, true},
+		{"success_board", args{"board", "1"}, false},
+		{"success_column", args{"column", "1"}, false},
+		{"success_assignee", args{"assignee", "1"}, false},
+		{"success_label", args{"label", "1"}, false},
+		{"success_due_before", args{"due_before", "2020-05-20T00:00:00Z"}, false},
+		{"success_due_after", args{"due_after", "2020-05-20T03:00:00+03:00"}, false},
+		{"success_overdue", args{"overdue", "true"}, false},
+		{"success_priority", args{"priority", "highest"}, false},
+		{"success_sort", args{"sort", "priority,-due"}, false},
+		{"success_q", args{"q", "release notes"}, false},
+		{"error", args{mock.Anything, "1"}, true},
+		{"error_id", args{"board", "-1"}, true},
+		{"error_time", args{"due_before", "2020-05-20"}, true},
+		{"error_bool", args{"overdue", "dummy"}, true},
+		{"error_priority", args{"priority", "urgent"}, true},
+		{"error_sort_field", args{"sort", "name"}, true},
+		{"error_sort_duplicate", args{"sort", "due,-due"}, true},
+		{"error_sort_empty", args{"sort", ""}, true},
 	}
 	demand := make(TaskDemand)
 	for _, tt := range tests {
@@ -55,18 +73,41 @@ func TestTaskDemand_Add(t *testing.T) {
 	}
 }
 
+func TestTaskDemand_Types(t *testing.T) {
+	demand := make(TaskDemand)
+	assert.NoError(t, demand.Add("board", "2"))
+	assert.NoError(t, demand.Add("due_after", "2020-05-20T03:00:00+03:00"))
+	assert.NoError(t, demand.Add("overdue", "false"))
+	assert.NoError(t, demand.Add("priority", "high"))
+
+	assert.Equal(t, TaskDemand{
+		"board":     uint(2),
+		"due_after": time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC),
+		"overdue":   false,
+		"priority":  m.PriorityHigh,
+	}, demand)
+}
+
+func TestTaskDemand_Sort(t *testing.T) {
+	demand := make(TaskDemand)
+	assert.Equal(t, Sort{{Field: "position"}}, demand.Sort())
+
+	assert.NoError(t, demand.Add("sort", "-priority,due"))
+	assert.Equal(t, Sort{{Field: "priority", Desc: true}, {Field: "due"}}, demand.Sort())
+}
+
 func TestColumnDemand_Add(t *testing.T) {
 	type args struct {
 		field string
-		value uint
+		value string
 	}
 	tests := []struct {
 		name    string
 		args    args
 		wantErr bool
 	}{
-		{"success_board", args{"board", 1}, false},
-		{"error", args{mock.Anything, 1}, true},
+		{"success_board", args{"board", "1"}, false},
+		{"error", args{mock.Anything, "1"}, true},
 	}
 	demand := make(ColumnDemand)
 	for _, tt := range tests {
@@ -77,3 +118,84 @@ func TestColumnDemand_Add(t *testing.T) {
 		})
 	}
 }
+
+func TestBoardDemand_Add(t *testing.T) {
+	if err := make(BoardDemand).Add(mock.Anything, "1"); err != ErrFilterNotAllowed {
+		t.Errorf("Add() error = %v, wantErr %v", err, ErrFilterNotAllowed)
+	}
+}
+
+func TestLabelDemand_Add(t *testing.T) {
+	type args struct {
+		field string
+		value string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"success_board", args{"board", "1"}, false},
+		{"error", args{mock.Anything, "1"}, true},
+	}
+	demand := make(LabelDemand)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := demand.Add(tt.args.field, tt.args.value); (err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", err, ErrFilterNotAllowed)
+			}
+		})
+	}
+}
+
+func TestActivityDemand_Add(t *testing.T) {
+	type args struct {
+		field string
+		value string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"success_actor", args{"actor", "1"}, false},
+		{"success_entity", args{"entity", "comment"}, false},
+		{"error", args{"board", "1"}, true},
+		{"error_entity", args{"entity", "user"}, true},
+	}
+	demand := make(ActivityDemand)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := demand.Add(tt.args.field, tt.args.value); (err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	assert.Equal(t, m.EntityComment, demand["entity"])
+}
+
+func TestSearchDemand_Add(t *testing.T) {
+	type args struct {
+		field string
+		value string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"success_q", args{"q", "release notes"}, false},
+		{"success_board", args{"board", "1"}, false},
+		{"error", args{"column", "1"}, true},
+		{"error_board", args{"board", "first"}, true},
+	}
+	demand := make(SearchDemand)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := demand.Add(tt.args.field, tt.args.value); (err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	assert.Equal(t, SearchDemand{"q": "release notes", "board": uint(1)}, demand)
+}
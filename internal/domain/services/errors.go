@@ -35,6 +35,72 @@ var (
 	// ErrLastColumn is used for cases when there is an attempt to delete the last column on a board.
 	ErrLastColumn = errors.New("the last column can not be deleted")
 
+	// ErrEmailDuplicate is used for cases when there is an attempt to register a user
+	// with an email that has been already taken.
+	ErrEmailDuplicate = errors.New("a user with this email already exists")
+
+	// ErrInvalidCredentials is used for cases when the provided email and password
+	// do not match any user.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+
+	// ErrUnauthenticated is used for cases when the provided access token is missing,
+	// malformed, expired or belongs to a user that does not exist anymore.
+	ErrUnauthenticated = errors.New("authentication required")
+
+	// ErrForbidden is used for cases when the authenticated user has no access to the
+	// requested record or the role of the user on the board does not allow the operation.
+	ErrForbidden = errors.New("access to the resource is forbidden")
+
+	// ErrUserRelation is used for cases when there is an attempt to create a relation with a
+	// user that does not exist in the system.
+	ErrUserRelation = errors.New("a user with the provided ID was not found")
+
+	// ErrLastOwner is used for cases when there is an attempt to remove or demote the last
+	// owner of a board.
+	ErrLastOwner = errors.New("the last owner of the board can not be removed or demoted")
+
+	// ErrNotMember is used for cases when a user that is not a member of the board is
+	// assigned to a task or is set as its reporter.
+	ErrNotMember = errors.New("assignees and the reporter of the task must be members of its board")
+
+	// ErrLabelRelation is used for cases when there is an attempt to attach a label that
+	// does not exist on the board of the task.
+	ErrLabelRelation = errors.New("a label with the provided ID was not found on the board of the task")
+
+	// ErrWIPLimitExceeded is used for cases when there is an attempt to add a task to a column
+	// that already holds as many tasks as its work in progress limit allows.
+	ErrWIPLimitExceeded = errors.New("the work in progress limit of the column is exceeded")
+
+	// ErrColumnOrder is used for cases when the new order of the columns of a board does not
+	// list every column of the board exactly once.
+	ErrColumnOrder = errors.New("the order must list every column of the board exactly once")
+
+	// ErrTemplateRelation is used for cases when a board is created from a template that does
+	// not exist in the system or is not flagged as a template.
+	ErrTemplateRelation = errors.New("a template with the provided ID was not found")
+
+	// ErrTrashedParent is used for cases when there is an attempt to restore a record
+	// the parent of which is deleted.
+	ErrTrashedParent = errors.New("the parent of the record is deleted, restore it first")
+
+	// ErrVersionMismatch is used for cases when there is an attempt to modify or delete a version
+	// of a record that has been already changed.
+	ErrVersionMismatch = errors.New("the record has been changed since the requested version")
+
+	// ErrInvalidPatch is used for cases when a patch document is malformed or can not be
+	// applied to the record.
+	ErrInvalidPatch = errors.New("the patch is invalid or can not be applied to the record")
+
+	// ErrPatchTestFailed is used for cases when a test operation of a JSON patch fails.
+	ErrPatchTestFailed = errors.New("the test operation of the patch has failed")
+
+	// ErrSearchQuery is used for cases when a search is requested without a query.
+	ErrSearchQuery = errors.New("the search query is required")
+
+	// ErrWebhookRelation is used for cases when there is an attempt to refer to a webhook that
+	// does not exist on the board.
+	ErrWebhookRelation = errors.New("a webhook with the provided ID was not found on the board")
+
 	// ErrTargetColumn is used for cases when the target column for tasks on a column deletion was not found
 	ErrTargetColumn = errors.Errorf("columns storage: target column for tasks transfer not found")
 )
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+)
+
+// EventService is an interactor for work with the events of the board changes
+type EventService struct {
+	eventBroker EventBroker
+	access      access
+}
+
+// NewEventService is an event service constructor
+func NewEventService(eventBroker EventBroker, memberStorage MemberStorage) *EventService {
+	return &EventService{
+		eventBroker: eventBroker,
+		access:      access{memberStorage: memberStorage},
+	}
+}
+
+// Subscribe will return the channel of the events of the board with the provided ID
+// published after the event with the provided ID, the recent events are replayed.
+// Any member of the board can subscribe to its events. The channel is closed once the
+// context is done, the subscriber falls behind the events, the board is deleted or the
+// current user leaves it
+func (s *EventService) Subscribe(ctx context.Context, boardID, lastID uint) (<-chan m.Event, error) {
+	if err := s.access.onBoard(ctx, boardID, m.RoleViewer); err != nil {
+		return nil, err
+	}
+	userID, err := s.access.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events, cancel := s.eventBroker.Subscribe(boardID, lastID)
+	stream := make(chan m.Event)
+	go func() {
+		defer close(stream)
+		defer cancel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case stream <- event:
+				}
+				if revokes(event, userID) {
+					return
+				}
+			}
+		}
+	}()
+
+	return stream, nil
+}
+
+// revokes reports if the event ends the subscription of the user to the events of
+// the board: the board is deleted or the user is not its member anymore
+func revokes(event m.Event, userID uint) bool {
+	switch event.Type {
+	case m.NewEventType(m.EntityBoard, m.ActionDelete):
+		return true
+	case m.NewEventType(m.EntityMember, m.ActionDelete):
+		return event.EntityID == userID
+	}
+
+	return false
+}
+
+// newEvent returns the event of the change recorded by the activity entry, the data
+// of the event is the state after the change or the state before it for deletions
+func newEvent(entry *m.Activity, before, after interface{}) (*m.Event, error) {
+	state := after
+	if entry.Action == m.ActionDelete {
+		state = before
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &m.Event{
+		Type:      m.NewEventType(entry.Entity, entry.Action),
+		BoardID:   entry.BoardID,
+		TaskID:    entry.TaskID,
+		Entity:    entry.Entity,
+		EntityID:  entry.EntityID,
+		ActorID:   entry.ActorID,
+		Data:      data,
+		Changes:   entry.Changes,
+		CreatedAt: entry.CreatedAt,
+	}, nil
+}
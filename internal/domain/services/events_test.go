@@ -0,0 +1,138 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewEventService(t *testing.T) {
+	eventBroker := new(MockedEventBroker)
+	memberStorage := new(MockedMemberStorage)
+	eventService := NewEventService(eventBroker, memberStorage)
+
+	assert.Equal(t, eventBroker, eventService.eventBroker)
+	assert.Equal(t, memberStorage, eventService.access.memberStorage)
+}
+
+// brokerStub returns the broker mock that subscribes to the board with the provided
+// ID to the provided events, along with the channel closed on the cancellation
+func brokerStub(boardID, lastID uint, events ...m.Event) (*MockedEventBroker, chan struct{}) {
+	stream, cancelled := make(chan m.Event, len(events)), make(chan struct{})
+	for _, event := range events {
+		stream <- event
+	}
+	eventBroker := new(MockedEventBroker)
+	eventBroker.On("Subscribe", boardID, lastID).Return((<-chan m.Event)(stream), func() { close(cancelled) })
+
+	return eventBroker, cancelled
+}
+
+func TestEventService_Subscribe(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		created := m.Event{ID: 3, Type: "task.created", BoardID: 2}
+		eventBroker, cancelled := brokerStub(2, 2, created)
+		eventService := &EventService{access: ownerAccess, eventBroker: eventBroker}
+		ctx, cancel := context.WithCancel(testCtx)
+
+		events, err := eventService.Subscribe(ctx, 2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, created, <-events)
+
+		cancel()
+		<-cancelled
+		_, ok := <-events
+		assert.False(t, ok)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		eventBroker, cancelled := brokerStub(2, 0,
+			m.Event{ID: 1, Type: "member.deleted", BoardID: 2, EntityID: 7},
+			m.Event{ID: 2, Type: "member.deleted", BoardID: 2, EntityID: 1},
+			m.Event{ID: 3, Type: "task.created", BoardID: 2},
+		)
+		eventService := &EventService{access: ownerAccess, eventBroker: eventBroker}
+
+		events, err := eventService.Subscribe(testCtx, 2, 0)
+		assert.Nil(t, err)
+		IDs := make([]uint, 0)
+		for event := range events {
+			IDs = append(IDs, event.ID)
+		}
+		<-cancelled
+		assert.Equal(t, []uint{1, 2}, IDs)
+	})
+
+	t.Run("not_member", func(t *testing.T) {
+		eventBroker := new(MockedEventBroker)
+		eventService := &EventService{access: access{memberStorage: roleStorage("", nil)}, eventBroker: eventBroker}
+
+		_, err := eventService.Subscribe(testCtx, 2, 0)
+		assert.Equal(t, ErrForbidden, err)
+		eventBroker.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything)
+	})
+}
+
+func TestJournal_Events(t *testing.T) {
+	label := &m.Label{Model: m.Model{ID: 4}, Name: "bug", Color: "#ff0000", BoardID: 2}
+	entry := m.Activity{ID: 9, BoardID: 2, Entity: m.EntityLabel, EntityID: 4, Action: m.ActionDelete, ActorID: 1}
+	eventJournal := func(outboxStorage OutboxStorage) journal {
+		activityStorage := new(MockedActivityStorage)
+		activityStorage.On("WithTx", mock.Anything).Return(activityStorage)
+		activityStorage.On("Save", mock.Anything).Return(&entry, nil)
+
+		return newJournal(activityStorage, outboxStorage)
+	}
+
+	t.Run("saved_within_tx", func(t *testing.T) {
+		_, tx := txStub(t, true)
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("WithTx", tx).Return(outboxStorage)
+		outboxStorage.On("Save", mock.Anything).Return(&m.Event{}, nil)
+		journal := eventJournal(outboxStorage)
+
+		assert.Nil(t, journal.record(testCtx, tx, entry, label, nil))
+		assert.Nil(t, tx.Commit())
+
+		outboxStorage.AssertNumberOfCalls(t, "Save", 1)
+		event := outboxStorage.Calls[1].Arguments.Get(0).(*m.Event)
+		assert.Equal(t, m.EventType("label.deleted"), event.Type)
+		assert.Equal(t, uint(2), event.BoardID)
+		assert.Equal(t, uint(4), event.EntityID)
+		assert.Equal(t, uint(1), event.ActorID)
+		data, _ := json.Marshal(label)
+		assert.JSONEq(t, string(data), string(event.Data))
+	})
+
+	t.Run("outbox_error", func(t *testing.T) {
+		_, tx := txStub(t, false)
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("WithTx", tx).Return(outboxStorage)
+		outboxStorage.On("Save", mock.Anything).Return((*m.Event)(nil), errors.New("dummy"))
+		journal := eventJournal(outboxStorage)
+
+		assert.Error(t, journal.record(testCtx, tx, entry, label, nil))
+		assert.Nil(t, tx.Rollback())
+	})
+
+	t.Run("no_outbox", func(t *testing.T) {
+		_, tx := txStub(t, true)
+		journal := eventJournal(nil)
+
+		assert.Nil(t, journal.record(testCtx, tx, entry, label, nil))
+		assert.Nil(t, tx.Commit())
+	})
+}
+
+func TestNewEventType(t *testing.T) {
+	assert.Equal(t, m.EventType("task.moved"), m.NewEventType(m.EntityTask, m.ActionMove))
+	assert.Equal(t, m.EventType("column.deleted"), m.NewEventType(m.EntityColumn, m.ActionDelete))
+	assert.Equal(t, m.EventType("task.transferred"), m.NewEventType(m.EntityTask, m.ActionTransfer))
+}
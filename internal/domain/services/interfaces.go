@@ -1,7 +1,10 @@
 package services
 
 import (
+	"context"
 	"database/sql"
+	"time"
+
 	m "github.com/dnozdrin/detask/internal/domain/models"
 )
 
@@ -12,15 +15,19 @@ type BoardStorage interface {
 	Save(*m.Board) (*m.Board, error)
 	// FindOneById should return a board with the provided ID
 	FindOneById(uint) (*m.Board, error)
-	// Find should return a slice of boards pointers sorted by name, that meet the
-	// provided demand
-	Find() ([]*m.Board, error)
+	// Find should return a slice of boards pointers sorted by ID, that meet the
+	// provided demand and fit the provided page
+	Find(BoardDemand, Page) ([]*m.Board, error)
 	// Update should update all board fields by the provided data
 	Update(*m.Board) (*m.Board, error)
-	// Delete should delete a board with the provided ID as well as all dependant records
+	// Delete should move a board with the provided ID to the trash as well as all dependant records
 	Delete(uint) error
 	// WithTx should return the boardStorage that will use the provided transaction
 	WithTx(*sql.Tx) BoardStorage
+	// Copy should copy the columns of the source board to the target board, along with
+	// the labels, the tasks and their comments if the tasks are requested. The copied
+	// tasks are created and reported by the provided user and have no assignees
+	Copy(sourceID, targetID uint, withTasks bool, userID uint) error
 }
 
 // ColumnStorage represents an interface for interaction with columns DAO
@@ -29,12 +36,12 @@ type ColumnStorage interface {
 	Save(*m.Column) (*m.Column, error)
 	// FindOneById should return a column with the provided ID
 	FindOneById(uint) (*m.Column, error)
-	// Find should return a slice of columns pointers sorted by position, that meet the
-	// provided demand
-	Find(ColumnDemand) ([]*m.Column, error)
+	// Find should return a slice of columns pointers sorted by position and ID, that
+	// meet the provided demand and fit the provided page
+	Find(ColumnDemand, Page) ([]*m.Column, error)
 	// Update should update all column fields by the provided data
 	Update(*m.Column) (*m.Column, error)
-	// Delete should delete a column with the provided ID as well as all dependant records
+	// Delete should move a column with the provided ID to the trash as well as all dependant records
 	Delete(uint) error
 	// WithTx should return the columnStorage that will use the provided transaction
 	WithTx(*sql.Tx) ColumnStorage
@@ -46,6 +53,9 @@ type ColumnStorage interface {
 	// FindColumnToTheRight should find a ID of a column that is to the right of the current
 	// and is related to the same board
 	FindColumnToTheRight(uint) (uint, error)
+	// Rebalance should place the columns with the provided IDs in the given order,
+	// with the positions evenly spread by the step
+	Rebalance(boardID uint, IDs []uint, step float64) error
 }
 
 // TaskStorage represents an interface for interaction with tasks DAO
@@ -54,17 +64,50 @@ type TaskStorage interface {
 	Save(*m.Task) (*m.Task, error)
 	// FindOneById should return a task with the provided ID
 	FindOneById(uint) (*m.Task, error)
-	// Find should return a slice of boards pointers sorted by name, that meet the
-	// provided demand
-	Find(TaskDemand) ([]*m.Task, error)
+	// Find should return a slice of tasks pointers sorted by position and ID, that
+	// meet the provided demand and fit the provided page
+	Find(TaskDemand, Page) ([]*m.Task, error)
 	// Update should update the name and the description of the task
 	Update(*m.Task) (*m.Task, error)
-	// Delete should delete a task with the provided ID as well as all dependant records
+	// SetAssignees should replace the assignees of the task with the provided ID
+	SetAssignees(uint, []uint) error
+	// SetLabels should replace the labels of the task with the provided ID, the
+	// labels must belong to the board of the task
+	SetLabels(uint, []uint) error
+	// Delete should move a task with the provided ID to the trash as well as all dependant records
 	Delete(uint) error
 	// WithTx should return the taskStorage that will use the provided transaction
 	WithTx(*sql.Tx) TaskStorage
 	// MoveToColumn should move all task from one column to another
 	MoveToColumn(from, to uint) error
+	// ColumnLoad should return the WIP limit of the column with the provided ID and the
+	// number of tasks in it, the column should be locked until the end of the transaction
+	ColumnLoad(columnID uint) (limit, count uint, err error)
+	// ColumnBoard should return the ID of the board of the column with the provided ID
+	ColumnBoard(columnID uint) (boardID uint, err error)
+	// Rebalance should place the tasks with the provided IDs into the column in the given
+	// order, with the positions evenly spread by the step
+	Rebalance(columnID uint, IDs []uint, step float64) error
+	// MatchLabels should replace the labels of the task with the labels of the same names
+	// on the board of its column, the labels without a match should be dropped
+	MatchLabels(taskID uint) error
+}
+
+// LabelStorage represents an interface for interaction with labels DAO
+type LabelStorage interface {
+	// Save will persist the provided label
+	Save(*m.Label) (*m.Label, error)
+	// FindOneById should return a label with the provided ID
+	FindOneById(uint) (*m.Label, error)
+	// Find should return a slice of labels pointers sorted by ID, that meet the
+	// provided demand and fit the provided page
+	Find(LabelDemand, Page) ([]*m.Label, error)
+	// Update should update the name and the color of the label
+	Update(*m.Label) (*m.Label, error)
+	// Delete should delete a label with the provided ID and detach it from tasks
+	Delete(uint) error
+	// WithTx should return the labelStorage that will use the provided transaction
+	WithTx(*sql.Tx) LabelStorage
 }
 
 // CommentStorage represents an interface for interaction with comments DAO
@@ -73,15 +116,231 @@ type CommentStorage interface {
 	Save(*m.Comment) (*m.Comment, error)
 	// FindOneById should return a comment with the provided ID
 	FindOneById(uint) (*m.Comment, error)
-	// Find should return a slice of comments pointers sorted by creation date
-	// (from newest to oldest), that meet the provided demand
-	Find(CommentDemand) ([]*m.Comment, error)
+	// Find should return a slice of comments pointers sorted by creation date and ID
+	// (from newest to oldest), that meet the provided demand and fit the provided page
+	Find(CommentDemand, Page) ([]*m.Comment, error)
 	// Update should update the comment text
 	Update(*m.Comment) (*m.Comment, error)
-	// Delete should delete a comment with the provided ID as well as all dependant records
+	// Delete should move a comment with the provided ID to the trash
+	Delete(uint) error
+	// WithTx should return the commentStorage that will use the provided transaction
+	WithTx(*sql.Tx) CommentStorage
+}
+
+// UserStorage represents an interface for interaction with users DAO
+type UserStorage interface {
+	// Save will persist the provided user
+	Save(*m.User) (*m.User, error)
+	// FindOneById should return a user with the provided ID
+	FindOneById(uint) (*m.User, error)
+	// FindOneByEmail should return a user with the provided email
+	FindOneByEmail(string) (*m.User, error)
+}
+
+// MemberStorage represents an interface for interaction with board members DAO
+type MemberStorage interface {
+	// Save will persist the provided membership
+	Save(*m.Member) (*m.Member, error)
+	// Find should return a slice of members pointers of the board with the
+	// provided ID sorted by user ID
+	Find(boardID uint) ([]*m.Member, error)
+	// Update should update the role of the member
+	Update(*m.Member) (*m.Member, error)
+	// Delete should delete the membership of the user on the board
+	Delete(boardID, userID uint) error
+	// CountOwners should count owners of the board with the provided ID
+	CountOwners(boardID uint) (int, error)
+	// FindOwnerless should return the IDs of the boards that are not deleted and have
+	// no owners, sorted by ID
+	FindOwnerless() ([]uint, error)
+	// FindRoleByBoard should return the role of the user on the board with the
+	// provided ID, an empty role if the user is not a member of the board or
+	// ErrRecordNotFound if there is no such board
+	FindRoleByBoard(boardID, userID uint) (m.Role, error)
+	// FindRoleByColumn should return the role of the user on the board of the column
+	// with the provided ID, an empty role if the user is not a member of the board
+	// or ErrRecordNotFound if there is no such column
+	FindRoleByColumn(columnID, userID uint) (m.Role, error)
+	// FindRoleByTask should return the role of the user on the board of the task
+	// with the provided ID, an empty role if the user is not a member of the board
+	// or ErrRecordNotFound if there is no such task
+	FindRoleByTask(taskID, userID uint) (m.Role, error)
+	// WithTx should return the memberStorage that will use the provided transaction
+	WithTx(*sql.Tx) MemberStorage
+}
+
+// TrashStorage represents an interface for interaction with the deleted records.
+// A record is deleted along with all its dependant records, they share the deletion time.
+type TrashStorage interface {
+	// Find should return a slice of deleted records pointers sorted by the deletion time
+	// (from newest to oldest), the type and the ID, that meet the provided demand and fit
+	// the provided page. The records deleted along with their parents should not be listed
+	Find(TrashDemand, Page) ([]*m.TrashItem, error)
+	// FindOne should return the deleted record of the provided type with the provided ID
+	FindOne(m.TrashType, uint) (*m.TrashItem, error)
+	// Restore should restore the deleted record of the provided type with the provided ID
+	// along with the records deleted with it, or return ErrTrashedParent if its parent is
+	// deleted. A restored column or task that lost its position should be placed after the
+	// last one by the provided step
+	Restore(kind m.TrashType, ID uint, step float64) error
+	// Purge should permanently delete the records deleted before the provided time
+	Purge(before time.Time) error
+	// WithTx should return the trashStorage that will use the provided transaction
+	WithTx(*sql.Tx) TrashStorage
+}
+
+// ActivityStorage represents an interface for interaction with the activity history
+type ActivityStorage interface {
+	// Save should persist the provided activity entry, the board of an entry on
+	// a task should be resolved from the task
+	Save(*m.Activity) (*m.Activity, error)
+	// Find should return a slice of activity entries pointers sorted from the newest
+	// to the oldest, that meet the provided demand and fit the provided page
+	Find(ActivityDemand, Page) ([]*m.Activity, error)
+	// WithTx should return the activityStorage that will use the provided transaction
+	WithTx(*sql.Tx) ActivityStorage
+}
+
+// RuleStorage represents an interface for interaction with the automation rules DAO
+type RuleStorage interface {
+	// Save will persist the provided rule
+	Save(*m.Rule) (*m.Rule, error)
+	// FindOneById should return a rule with the provided ID
+	FindOneById(uint) (*m.Rule, error)
+	// Find should return a slice of rules pointers of the board with the provided ID
+	// sorted by ID, that fit the provided page
+	Find(boardID uint, page Page) ([]*m.Rule, error)
+	// FindTriggered should return a slice of the enabled rules pointers with the provided
+	// trigger type sorted by ID, of the board with the provided ID or of all the boards
+	// that are not deleted for the zero ID
+	FindTriggered(trigger m.TriggerType, boardID uint) ([]*m.Rule, error)
+	// Update should update the name, the trigger, the conditions, the actions and the
+	// disabled flag of the rule
+	Update(*m.Rule) (*m.Rule, error)
+	// Delete should delete a rule with the provided ID along with its execution log
+	Delete(uint) error
+}
+
+// ExecutionStorage represents an interface for interaction with the execution log of
+// the automation rules
+type ExecutionStorage interface {
+	// Save will persist the provided execution
+	Save(*m.RuleExecution) (*m.RuleExecution, error)
+	// Find should return a slice of executions pointers of the rule with the provided ID
+	// sorted from the newest to the oldest, that fit the provided page
+	Find(ruleID uint, page Page) ([]*m.RuleExecution, error)
+	// FindLast should return the latest execution of the rule with the provided ID on
+	// the task with the provided ID
+	FindLast(ruleID, taskID uint) (*m.RuleExecution, error)
+	// FindByEvent should return the execution of the rule with the provided ID run by
+	// the event with the provided ID
+	FindByEvent(ruleID, eventID uint) (*m.RuleExecution, error)
+}
+
+// OutboxStorage represents an interface for interaction with the outbox of the change
+// events. The events are saved within the transactions of the changes and relayed to
+// the consumers once the transactions are committed
+type OutboxStorage interface {
+	// Save should persist the provided event and assign its ID, the IDs should grow in
+	// the order the events are saved
+	Save(*m.Event) (*m.Event, error)
+	// FindPending should return a slice of up to the limit of the events pointers that
+	// are neither delivered nor dead yet and which next attempts are due by the provided
+	// time, sorted by ID, with the consumers that have accepted them and the number of
+	// the relays they have failed
+	FindPending(now time.Time, limit uint) ([]*m.Event, error)
+	// MarkConsumed should record that the consumer with the provided name has accepted
+	// the event with the provided ID, recording it again should have no effect
+	MarkConsumed(ID uint, consumer string) error
+	// MarkFailed should count a failed relay of the event with the provided ID and
+	// postpone its next attempt till the provided time, the nil time marks the event
+	// dead and it should not be found pending anymore
+	MarkFailed(ID uint, next *time.Time) error
+	// MarkDelivered should mark the event with the provided ID as delivered
+	MarkDelivered(uint) error
+	// Purge should delete the events delivered before the provided time, the dead
+	// events should be kept
+	Purge(before time.Time) error
+	// WithTx should return the outboxStorage that will use the provided transaction
+	WithTx(*sql.Tx) OutboxStorage
+}
+
+// SearchStorage represents an interface for the full-text search of tasks and comments
+type SearchStorage interface {
+	// Find should return a slice of the tasks and the comments pointers matching the query
+	// of the provided demand, sorted by the rank (from highest to lowest), the type and
+	// the ID, that meet the other constraints of the demand and fit the provided page.
+	// The deleted records should not be found
+	Find(SearchDemand, Page) ([]*m.SearchHit, error)
+}
+
+// WebhookStorage represents an interface for interaction with webhooks DAO
+type WebhookStorage interface {
+	// Save will persist the provided webhook
+	Save(*m.Webhook) (*m.Webhook, error)
+	// FindOneById should return a webhook with the provided ID along with its secret,
+	// the webhooks of the deleted boards included
+	FindOneById(uint) (*m.Webhook, error)
+	// Find should return a slice of webhooks pointers of the board with the provided ID
+	// sorted by ID, that fit the provided page, along with their secrets
+	Find(boardID uint, page Page) ([]*m.Webhook, error)
+	// Update should update the URL, the secret, the event types and the disabled flag
+	// of the webhook
+	Update(*m.Webhook) (*m.Webhook, error)
+	// Delete should delete a webhook with the provided ID along with its deliveries
 	Delete(uint) error
 }
 
+// DeliveryStorage represents an interface for interaction with the webhook deliveries
+// queue. The workers claim the pending deliveries, which next attempts are due, and
+// update them with the results of the attempts
+type DeliveryStorage interface {
+	// Save will persist the provided delivery
+	Save(*m.Delivery) (*m.Delivery, error)
+	// FindOneById should return a delivery with the provided ID
+	FindOneById(uint) (*m.Delivery, error)
+	// Find should return a slice of deliveries pointers of the webhook with the provided
+	// ID sorted from the newest to the oldest, that fit the provided page
+	Find(webhookID uint, page Page) ([]*m.Delivery, error)
+	// Claim should postpone the next attempts of up to the limit of the pending deliveries
+	// due by the provided time till the lease expires and return them, the oldest due ones
+	// first. A delivery should be claimed once until its lease expires
+	Claim(now, lease time.Time, limit uint) ([]*m.Delivery, error)
+	// Update should update the status, the number of attempts, the response code, the
+	// error and the next attempt of the delivery
+	Update(*m.Delivery) (*m.Delivery, error)
+}
+
+// WebhookSender represents an interface for posting the deliveries to the webhooks
+type WebhookSender interface {
+	// Send should post the payload of the delivery to the URL of the webhook signed with
+	// its secret and return the status code of the response, or an error if the request
+	// has failed or the response status is not successful
+	Send(ctx context.Context, webhook m.Webhook, delivery m.Delivery) (int, error)
+}
+
+// EventBroker represents an interface for the delivery of the change events to the
+// subscribers of the boards
+type EventBroker interface {
+	// Publish should assign the next ID to the provided event and deliver it to the
+	// subscribers of its board
+	Publish(m.Event)
+	// Subscribe should return the channel of the events of the board published after
+	// the event with the provided ID, the recent events should be replayed from a buffer,
+	// and the function that cancels the subscription. The channel should be closed once
+	// the subscription is cancelled or the subscriber falls behind the events
+	Subscribe(boardID, lastID uint) (<-chan m.Event, func())
+}
+
+// TokenManager represents an interface for issuing and verifying access tokens
+type TokenManager interface {
+	// Issue should return a signed access token for the user with the provided ID
+	Issue(uint) (string, error)
+	// Parse should verify the provided access token and return the ID of the user
+	// it was issued for
+	Parse(string) (uint, error)
+}
+
 // TxBeginner provides a method for starting database transactions
 type TxBeginner interface {
 	Begin() (*sql.Tx, error)
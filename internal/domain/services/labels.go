@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+)
+
+// LabelService is an interactor for work with labels
+type LabelService struct {
+	validator    v.Validator
+	labelStorage LabelStorage
+	txBeginner   TxBeginner
+	access       access
+	journal      journal
+}
+
+// NewLabelService is a label service constructor
+func NewLabelService(
+	validator v.Validator,
+	labelStorage LabelStorage,
+	memberStorage MemberStorage,
+	activityStorage ActivityStorage,
+	outboxStorage OutboxStorage,
+	txBeginner TxBeginner,
+) *LabelService {
+	return &LabelService{
+		validator:    validator,
+		labelStorage: labelStorage,
+		txBeginner:   txBeginner,
+		access:       access{memberStorage: memberStorage},
+		journal:      newJournal(activityStorage, outboxStorage),
+	}
+}
+
+// Create will add a new label to the catalog of the board. Returns the operation
+// result with possible validation or saving errors. Only board editors and owners
+// can create labels
+func (l *LabelService) Create(ctx context.Context, label *m.Label) (*m.Label, error) {
+	if err := l.validator.Validate(*label); err != nil {
+		return nil, err
+	}
+	if err := l.access.onBoard(ctx, label.BoardID, m.RoleEditor); err != nil {
+		return nil, relation(err, ErrBoardRelation)
+	}
+
+	return l.write(ctx, m.ActionCreate, nil, func(labelStorage LabelStorage) (*m.Label, error) {
+		return labelStorage.Save(label)
+	})
+}
+
+// Find will return the page of labels of the boards the current user is a member
+// of that meet the provided demand, the cursor of the next page if there is one,
+// and an error in case it occurred while fetching records from the storage
+func (l *LabelService) Find(ctx context.Context, demand LabelDemand, page Page) ([]*m.Label, *Cursor, error) {
+	userID, err := l.access.userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	demand[memberConstraint] = userID
+
+	labels, err := l.labelStorage.Find(demand, page.lookAhead())
+	if err != nil || !page.hasMore(len(labels)) {
+		return labels, nil, err
+	}
+
+	labels = labels[:page.Limit]
+
+	return labels, &Cursor{ID: labels[len(labels)-1].ID}, nil
+}
+
+// FindOneById will return a pointer to the label requested by id and
+// an error in case it occurred while fetching the record from the storage
+func (l *LabelService) FindOneById(ctx context.Context, ID uint) (*m.Label, error) {
+	return l.findOneWithRole(ctx, ID, m.RoleViewer)
+}
+
+// Update will update the name and the color of the label. Returns the operation
+// result with possible validation or saving errors. Only board editors and owners
+// can update labels
+func (l *LabelService) Update(ctx context.Context, label *m.Label) (*m.Label, error) {
+	if err := l.validator.Validate(*label); err != nil {
+		return nil, err
+	}
+	before, err := l.findOneWithRole(ctx, label.ID, m.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.write(ctx, m.ActionUpdate, before, func(labelStorage LabelStorage) (*m.Label, error) {
+		return labelStorage.Update(label)
+	})
+}
+
+// Patch will apply the patch to the label with the provided ID and update the name
+// and the color of the label with the result, which is validated as a whole. Only
+// board editors and owners can patch labels
+func (l *LabelService) Patch(ctx context.Context, ID uint, patch Patch) (*m.Label, error) {
+	stored, err := l.FindOneById(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	label := new(m.Label)
+	if err = patch.apply(stored, label); err != nil {
+		return nil, err
+	}
+	label.ID = stored.ID
+
+	return l.Update(ctx, label)
+}
+
+// Delete will delete the label with the given ID and detach it from all the
+// tasks. Only board editors and owners can delete labels
+func (l *LabelService) Delete(ctx context.Context, ID uint) error {
+	before, err := l.findOneWithRole(ctx, ID, m.RoleEditor)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.write(ctx, m.ActionDelete, before, func(labelStorage LabelStorage) (*m.Label, error) {
+		return nil, labelStorage.Delete(ID)
+	})
+
+	return err
+}
+
+// write will change the label with the provided function, that returns the state
+// of the label after the change, and record the change from the provided state
+// within a single transaction. The label is missing before its creation and after
+// its deletion
+func (l *LabelService) write(
+	ctx context.Context,
+	action m.Action,
+	before *m.Label,
+	change func(LabelStorage) (*m.Label, error),
+) (*m.Label, error) {
+	tx, err := l.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	after, err := change(l.labelStorage.WithTx(tx))
+	if err != nil {
+		return nil, err
+	}
+	if err = l.record(ctx, tx, action, before, after); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
+
+// record will save the change of the label into the activity history of its board
+func (l *LabelService) record(ctx context.Context, tx *sql.Tx, action m.Action, before, after *m.Label) error {
+	label := after
+	if label == nil {
+		label = before
+	}
+	entry := m.Activity{BoardID: label.BoardID, Entity: m.EntityLabel, EntityID: label.ID, Action: action}
+
+	return l.journal.record(ctx, tx, entry, before, after)
+}
+
+// findOneWithRole will return the label with the provided ID if the current
+// user has the required role on the board of the label
+func (l *LabelService) findOneWithRole(ctx context.Context, ID uint, required m.Role) (*m.Label, error) {
+	label, err := l.labelStorage.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = l.access.onBoard(ctx, label.BoardID, required); err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
@@ -0,0 +1,204 @@
+// +build unit
+
+package services
+
+import (
+	"testing"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewLabelService(t *testing.T) {
+	labelStorage := new(MockedLabelStorage)
+	validation := new(MockedValidation)
+	memberStorage := new(MockedMemberStorage)
+	activityStorage := new(MockedActivityStorage)
+	outboxStorage := new(MockedOutboxStorage)
+	txBeginner := new(MockedTxBeginner)
+	labelService := NewLabelService(validation, labelStorage, memberStorage, activityStorage, outboxStorage, txBeginner)
+
+	assert.Equal(t, validation, labelService.validator)
+	assert.Equal(t, labelStorage, labelService.labelStorage)
+	assert.Equal(t, memberStorage, labelService.access.memberStorage)
+	assert.Equal(t, activityStorage, labelService.journal.activityStorage)
+	assert.Equal(t, outboxStorage, labelService.journal.outboxStorage)
+	assert.Equal(t, txBeginner, labelService.txBeginner)
+}
+
+func TestLabelService_Create(t *testing.T) {
+	var validationErr *v.Errors
+	labelIn := &m.Label{Name: "bug", Color: "#ff0000", BoardID: 1}
+	validation := new(MockedValidation)
+	validation.On("Validate", *labelIn).Return(validationErr)
+
+	t.Run("success", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
+		labelStorage := new(MockedLabelStorage)
+		labelStorage.On("WithTx", tx).Return(labelStorage)
+		labelStorage.On("Save", labelIn).Return(labelIn, nil)
+		history, activityStorage := journalStub(tx)
+		labelService := &LabelService{
+			validator:    validation,
+			labelStorage: labelStorage,
+			access:       ownerAccess,
+			txBeginner:   txBeginner,
+			journal:      history,
+		}
+
+		labelOut, err := labelService.Create(testCtx, labelIn)
+		assert.Nil(t, err)
+		assert.Equal(t, labelIn, labelOut)
+
+		entry := recorded(activityStorage, 0)
+		assert.Equal(t, uint(1), entry.BoardID)
+		assert.Equal(t, m.EntityLabel, entry.Entity)
+		assert.Equal(t, m.ActionCreate, entry.Action)
+	})
+
+	t.Run("viewer_forbidden", func(t *testing.T) {
+		labelStorage := new(MockedLabelStorage)
+		labelService := &LabelService{
+			validator:    validation,
+			labelStorage: labelStorage,
+			access:       access{memberStorage: roleStorage(m.RoleViewer, nil)},
+		}
+
+		_, err := labelService.Create(testCtx, labelIn)
+		assert.Equal(t, ErrForbidden, err)
+		labelStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("missing_board", func(t *testing.T) {
+		labelService := &LabelService{
+			validator: validation,
+			access:    access{memberStorage: roleStorage("", ErrRecordNotFound)},
+		}
+
+		_, err := labelService.Create(testCtx, labelIn)
+		assert.Equal(t, ErrBoardRelation, err)
+	})
+
+	t.Run("validation_error", func(t *testing.T) {
+		validationErr := v.NewErrors()
+		validationErr.Add(v.Error{Field: "color", Message: "test"})
+		validation := new(MockedValidation)
+		validation.On("Validate", mock.Anything).Return(validationErr)
+		labelService := &LabelService{validator: validation}
+
+		_, err := labelService.Create(testCtx, &m.Label{Color: "red"})
+		assert.Equal(t, validationErr, err)
+	})
+}
+
+func TestLabelService_Find(t *testing.T) {
+	labelsIn := []*m.Label{{Model: m.Model{ID: 1}}, {Model: m.Model{ID: 2}}, {Model: m.Model{ID: 3}}}
+	labelStorage := new(MockedLabelStorage)
+	labelStorage.On("Find", LabelDemand{"board": uint(1), "member": uint(1)}, Page{Limit: 3}).Return(labelsIn, nil)
+	labelService := &LabelService{labelStorage: labelStorage, access: ownerAccess}
+
+	labelsOut, next, err := labelService.Find(testCtx, LabelDemand{"board": uint(1)}, Page{Limit: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, labelsIn[:2], labelsOut)
+	assert.Equal(t, &Cursor{ID: 2}, next)
+}
+
+func TestLabelService_FindOneById(t *testing.T) {
+	labelIn := &m.Label{Model: m.Model{ID: 1}, BoardID: 2}
+
+	t.Run("found", func(t *testing.T) {
+		labelStorage := new(MockedLabelStorage)
+		labelStorage.On("FindOneById", uint(1)).Return(labelIn, nil)
+		labelService := &LabelService{labelStorage: labelStorage, access: access{memberStorage: roleStorage(m.RoleViewer, nil)}}
+
+		labelOut, err := labelService.FindOneById(testCtx, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, labelIn, labelOut)
+	})
+
+	t.Run("not_a_member", func(t *testing.T) {
+		labelStorage := new(MockedLabelStorage)
+		labelStorage.On("FindOneById", uint(1)).Return(labelIn, nil)
+		labelService := &LabelService{labelStorage: labelStorage, access: access{memberStorage: roleStorage("", nil)}}
+
+		_, err := labelService.FindOneById(testCtx, 1)
+		assert.Equal(t, ErrForbidden, err)
+	})
+}
+
+func TestLabelService_Update(t *testing.T) {
+	var validationErr *v.Errors
+	labelIn := &m.Label{Model: m.Model{ID: 1}, Name: "bug", Color: "#ff0000"}
+	validation := new(MockedValidation)
+	validation.On("Validate", *labelIn).Return(validationErr)
+
+	t.Run("success", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
+		labelStorage := new(MockedLabelStorage)
+		labelStorage.On("WithTx", tx).Return(labelStorage)
+		labelStorage.On("FindOneById", uint(1)).Return(&m.Label{Model: m.Model{ID: 1}, Name: "bug", Color: "#00ff00"}, nil)
+		labelStorage.On("Update", labelIn).Return(labelIn, nil)
+		history, activityStorage := journalStub(tx)
+		labelService := &LabelService{
+			validator:    validation,
+			labelStorage: labelStorage,
+			access:       ownerAccess,
+			txBeginner:   txBeginner,
+			journal:      history,
+		}
+
+		labelOut, err := labelService.Update(testCtx, labelIn)
+		assert.Nil(t, err)
+		assert.Equal(t, labelIn, labelOut)
+		assert.Equal(t, m.Changes{"color": {From: "#00ff00", To: "#ff0000"}}, recorded(activityStorage, 0).Changes)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		labelStorage := new(MockedLabelStorage)
+		labelStorage.On("FindOneById", uint(1)).Return(&m.Label{}, ErrRecordNotFound)
+		labelService := &LabelService{validator: validation, labelStorage: labelStorage, access: ownerAccess}
+
+		_, err := labelService.Update(testCtx, labelIn)
+		assert.Equal(t, ErrRecordNotFound, err)
+		labelStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestLabelService_Delete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
+		labelStorage := new(MockedLabelStorage)
+		labelStorage.On("WithTx", tx).Return(labelStorage)
+		labelStorage.On("FindOneById", uint(1)).Return(&m.Label{Model: m.Model{ID: 1}, BoardID: 2}, nil)
+		labelStorage.On("Delete", uint(1)).Return(nil)
+		history, activityStorage := journalStub(tx)
+		labelService := &LabelService{labelStorage: labelStorage, access: ownerAccess, txBeginner: txBeginner, journal: history}
+
+		assert.Nil(t, labelService.Delete(testCtx, 1))
+		assert.Equal(t, m.ActionDelete, recorded(activityStorage, 0).Action)
+	})
+
+	t.Run("viewer_forbidden", func(t *testing.T) {
+		labelStorage := new(MockedLabelStorage)
+		labelStorage.On("FindOneById", uint(1)).Return(&m.Label{Model: m.Model{ID: 1}, BoardID: 2}, nil)
+		labelService := &LabelService{labelStorage: labelStorage, access: access{memberStorage: roleStorage(m.RoleViewer, nil)}}
+
+		assert.Equal(t, ErrForbidden, labelService.Delete(testCtx, 1))
+		labelStorage.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		dbErr := errors.New("db error")
+		txBeginner, tx := txStub(t, false)
+		labelStorage := new(MockedLabelStorage)
+		labelStorage.On("WithTx", tx).Return(labelStorage)
+		labelStorage.On("FindOneById", uint(1)).Return(&m.Label{Model: m.Model{ID: 1}, BoardID: 2}, nil)
+		labelStorage.On("Delete", uint(1)).Return(dbErr)
+		labelService := &LabelService{labelStorage: labelStorage, access: ownerAccess, txBeginner: txBeginner}
+
+		assert.Equal(t, dbErr, labelService.Delete(testCtx, 1))
+	})
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+)
+
+// MemberService is an interactor for work with board members
+type MemberService struct {
+	validator     v.Validator
+	memberStorage MemberStorage
+	txBeginner    TxBeginner
+	access        access
+	journal       journal
+}
+
+// NewMemberService is a member service constructor
+func NewMemberService(
+	validator v.Validator,
+	memberStorage MemberStorage,
+	activityStorage ActivityStorage,
+	outboxStorage OutboxStorage,
+	txBeginner TxBeginner,
+) *MemberService {
+	return &MemberService{
+		validator:     validator,
+		memberStorage: memberStorage,
+		txBeginner:    txBeginner,
+		access:        access{memberStorage: memberStorage},
+		journal:       newJournal(activityStorage, outboxStorage),
+	}
+}
+
+// Create will add the user to the board with the provided role. Only board
+// owners can manage members
+func (s *MemberService) Create(ctx context.Context, member *m.Member) (*m.Member, error) {
+	if err := s.validator.Validate(*member); err != nil {
+		return nil, err
+	}
+	if err := s.access.onBoard(ctx, member.BoardID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if member, err = s.memberStorage.WithTx(tx).Save(member); err != nil {
+		return nil, err
+	}
+	if err = s.record(ctx, tx, m.ActionCreate, nil, member); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// Find will return all members of the board with the provided ID
+func (s *MemberService) Find(ctx context.Context, boardID uint) ([]*m.Member, error) {
+	if err := s.access.onBoard(ctx, boardID, m.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.memberStorage.Find(boardID)
+}
+
+// Update will change the role of the board member. Only board owners can
+// manage members, the last owner of the board can not be demoted
+func (s *MemberService) Update(ctx context.Context, member *m.Member) (*m.Member, error) {
+	if err := s.validator.Validate(*member); err != nil {
+		return nil, err
+	}
+	if err := s.access.onBoard(ctx, member.BoardID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	memberStorage := s.memberStorage.WithTx(tx)
+	before, err := s.findOne(memberStorage, member.BoardID, member.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Role != m.RoleOwner {
+		if err = s.keepLastOwner(memberStorage, before); err != nil {
+			return nil, err
+		}
+	}
+	if member, err = memberStorage.Update(member); err != nil {
+		return nil, err
+	}
+	if err = s.record(ctx, tx, m.ActionUpdate, before, member); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// Delete will remove the user from the board. Only board owners can manage
+// members, the last owner of the board can not be removed
+func (s *MemberService) Delete(ctx context.Context, boardID, userID uint) error {
+	if err := s.access.onBoard(ctx, boardID, m.RoleOwner); err != nil {
+		return err
+	}
+
+	tx, err := s.txBeginner.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	memberStorage := s.memberStorage.WithTx(tx)
+	before, err := s.findOne(memberStorage, boardID, userID)
+	if err != nil {
+		return err
+	}
+	if err = s.keepLastOwner(memberStorage, before); err != nil {
+		return err
+	}
+	if err = memberStorage.Delete(boardID, userID); err != nil {
+		return err
+	}
+	if err = s.record(ctx, tx, m.ActionDelete, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Adopt will make the user of the context the owner of the boards that have no owners,
+// such as the boards created before the memberships were introduced, the user keeps
+// no other role on them. It is not exposed by the API, the application runs it on
+// start for the configured boards owner. Returns the memberships of the user on the
+// adopted boards
+func (s *MemberService) Adopt(ctx context.Context) ([]*m.Member, error) {
+	userID, err := s.access.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	memberStorage := s.memberStorage.WithTx(tx)
+	boardIDs, err := memberStorage.FindOwnerless()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*m.Member, 0, len(boardIDs))
+	for _, boardID := range boardIDs {
+		member := &m.Member{BoardID: boardID, UserID: userID, Role: m.RoleOwner}
+		before, err := s.findOne(memberStorage, boardID, userID)
+		switch err {
+		case nil:
+			if member, err = memberStorage.Update(member); err != nil {
+				return nil, err
+			}
+			err = s.record(ctx, tx, m.ActionUpdate, before, member)
+		case ErrRecordNotFound:
+			if member, err = memberStorage.Save(member); err != nil {
+				return nil, err
+			}
+			err = s.record(ctx, tx, m.ActionCreate, nil, member)
+		}
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// findOne will return the membership of the user on the board or ErrRecordNotFound
+// if the user is not a member of the board
+func (s *MemberService) findOne(memberStorage MemberStorage, boardID, userID uint) (*m.Member, error) {
+	role, err := memberStorage.FindRoleByBoard(boardID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, ErrRecordNotFound
+	}
+
+	return &m.Member{BoardID: boardID, UserID: userID, Role: role}, nil
+}
+
+// keepLastOwner will return ErrLastOwner if the member is the only owner of the board
+func (s *MemberService) keepLastOwner(memberStorage MemberStorage, member *m.Member) error {
+	if member.Role != m.RoleOwner {
+		return nil
+	}
+
+	owners, err := memberStorage.CountOwners(member.BoardID)
+	if err != nil {
+		return err
+	}
+	if owners == 1 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
+
+// record will save the change of the membership into the activity history of the
+// board, the membership is missing before its creation and after its deletion
+func (s *MemberService) record(ctx context.Context, tx *sql.Tx, action m.Action, before, after *m.Member) error {
+	member := after
+	if member == nil {
+		member = before
+	}
+	entry := m.Activity{BoardID: member.BoardID, Entity: m.EntityMember, EntityID: member.UserID, Action: action}
+
+	return s.journal.record(ctx, tx, entry, before, after)
+}
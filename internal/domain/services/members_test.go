@@ -0,0 +1,242 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemberService(t *testing.T) {
+	memberStorage := new(MockedMemberStorage)
+	validation := new(MockedValidation)
+	activityStorage := new(MockedActivityStorage)
+	outboxStorage := new(MockedOutboxStorage)
+	txBeginner := new(MockedTxBeginner)
+	memberService := NewMemberService(validation, memberStorage, activityStorage, outboxStorage, txBeginner)
+
+	assert.Equal(t, validation, memberService.validator)
+	assert.Equal(t, memberStorage, memberService.memberStorage)
+	assert.Equal(t, txBeginner, memberService.txBeginner)
+	assert.Equal(t, memberStorage, memberService.access.memberStorage)
+	assert.Equal(t, activityStorage, memberService.journal.activityStorage)
+	assert.Equal(t, outboxStorage, memberService.journal.outboxStorage)
+}
+
+func TestMemberService_Create(t *testing.T) {
+	var validationErr *v.Errors
+	memberIn := &m.Member{BoardID: 1, UserID: 2, Role: m.RoleEditor}
+	validation := new(MockedValidation)
+	validation.On("Validate", *memberIn).Return(validationErr)
+
+	t.Run("success", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
+		memberStorage := roleStorage(m.RoleOwner, nil)
+		memberStorage.On("Save", memberIn).Return(memberIn, nil)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+		history, activityStorage := journalStub(tx)
+		memberService := &MemberService{
+			validator:     validation,
+			memberStorage: memberStorage,
+			txBeginner:    txBeginner,
+			journal:       history,
+			access:        access{memberStorage: memberStorage},
+		}
+
+		memberOut, err := memberService.Create(testCtx, memberIn)
+		assert.Nil(t, err)
+		assert.Equal(t, memberIn, memberOut)
+		assert.Equal(t, &m.Activity{
+			BoardID:  1,
+			Entity:   m.EntityMember,
+			EntityID: 2,
+			Action:   m.ActionCreate,
+			ActorID:  1,
+			Changes:  m.Changes{"board": {To: 1.0}, "user": {To: 2.0}, "role": {To: "editor"}},
+		}, recorded(activityStorage, 0))
+	})
+
+	t.Run("editor_forbidden", func(t *testing.T) {
+		memberStorage := roleStorage(m.RoleEditor, nil)
+		memberService := &MemberService{validator: validation, memberStorage: memberStorage, access: access{memberStorage: memberStorage}}
+
+		_, err := memberService.Create(testCtx, memberIn)
+		assert.Equal(t, ErrForbidden, err)
+		memberStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("validation_error", func(t *testing.T) {
+		validationErr := v.NewErrors()
+		validationErr.Add(v.Error{Field: "role", Message: "test"})
+		validation := new(MockedValidation)
+		validation.On("Validate", mock.Anything).Return(validationErr)
+		memberService := &MemberService{validator: validation}
+
+		_, err := memberService.Create(testCtx, &m.Member{Role: "admin"})
+		assert.Equal(t, validationErr, err)
+	})
+}
+
+func TestMemberService_Find(t *testing.T) {
+	membersIn := []*m.Member{{BoardID: 1, UserID: 1, Role: m.RoleOwner}}
+	memberStorage := roleStorage(m.RoleViewer, nil)
+	memberStorage.On("Find", uint(1)).Return(membersIn, nil)
+	memberService := &MemberService{memberStorage: memberStorage, access: access{memberStorage: memberStorage}}
+
+	membersOut, err := memberService.Find(testCtx, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, membersIn, membersOut)
+}
+
+func TestMemberService_Update(t *testing.T) {
+	var validationErr *v.Errors
+	memberIn := &m.Member{BoardID: 1, UserID: 2, Role: m.RoleViewer}
+	validation := new(MockedValidation)
+	validation.On("Validate", *memberIn).Return(validationErr)
+
+	tests := []struct {
+		name    string
+		current m.Role
+		owners  int
+		err     error
+	}{
+		{"demote_editor", m.RoleEditor, 1, nil},
+		{"demote_one_of_owners", m.RoleOwner, 2, nil},
+		{"demote_last_owner", m.RoleOwner, 1, ErrLastOwner},
+		{"not_a_member", "", 1, ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, dbmock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+			dbmock.ExpectBegin()
+			if tt.err == nil {
+				dbmock.ExpectCommit()
+			} else {
+				dbmock.ExpectRollback()
+			}
+			tx, _ := db.Begin()
+
+			txBeginner := new(MockedTxBeginner)
+			txBeginner.On("Begin").Return(tx, nil)
+
+			memberStorage := new(MockedMemberStorage)
+			memberStorage.On("FindRoleByBoard", memberIn.BoardID, uint(1)).Return(m.RoleOwner, nil)
+			memberStorage.On("FindRoleByBoard", memberIn.BoardID, memberIn.UserID).Return(tt.current, nil)
+			memberStorage.On("CountOwners", memberIn.BoardID).Return(tt.owners, nil)
+			memberStorage.On("Update", memberIn).Return(memberIn, nil)
+			memberStorage.On("WithTx", tx).Return(memberStorage)
+
+			history, _ := journalStub(tx)
+			memberService := &MemberService{
+				validator:     validation,
+				memberStorage: memberStorage,
+				txBeginner:    txBeginner,
+				journal:       history,
+				access:        access{memberStorage: memberStorage},
+			}
+			_, err = memberService.Update(testCtx, memberIn)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestMemberService_Delete(t *testing.T) {
+	t.Run("last_owner", func(t *testing.T) {
+		db, dbmock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer db.Close()
+		dbmock.ExpectBegin()
+		dbmock.ExpectRollback()
+		tx, _ := db.Begin()
+
+		txBeginner := new(MockedTxBeginner)
+		txBeginner.On("Begin").Return(tx, nil)
+
+		memberStorage := roleStorage(m.RoleOwner, nil)
+		memberStorage.On("CountOwners", uint(1)).Return(1, nil)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+
+		memberService := &MemberService{memberStorage: memberStorage, txBeginner: txBeginner, access: access{memberStorage: memberStorage}}
+		err = memberService.Delete(testCtx, 1, 1)
+		assert.Equal(t, ErrLastOwner, err)
+		memberStorage.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("tx_begin_error", func(t *testing.T) {
+		var tx *sql.Tx
+		txErr := errors.New("tx error")
+		txBeginner := new(MockedTxBeginner)
+		txBeginner.On("Begin").Return(tx, txErr)
+
+		memberStorage := roleStorage(m.RoleOwner, nil)
+		memberService := &MemberService{memberStorage: memberStorage, txBeginner: txBeginner, access: access{memberStorage: memberStorage}}
+		err := memberService.Delete(testCtx, 1, 2)
+		assert.Equal(t, txErr, err)
+	})
+}
+
+func TestMemberService_Adopt(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+		memberStorage.On("FindOwnerless").Return([]uint{3, 4}, nil)
+		memberStorage.On("FindRoleByBoard", uint(3), uint(1)).Return(m.Role(""), nil)
+		memberStorage.On("FindRoleByBoard", uint(4), uint(1)).Return(m.RoleViewer, nil)
+		memberStorage.On("Save", mock.Anything).Return(&m.Member{BoardID: 3, UserID: 1, Role: m.RoleOwner}, nil)
+		memberStorage.On("Update", mock.Anything).Return(&m.Member{BoardID: 4, UserID: 1, Role: m.RoleOwner}, nil)
+		history, activityStorage := journalStub(tx)
+		memberService := &MemberService{
+			memberStorage: memberStorage,
+			txBeginner:    txBeginner,
+			journal:       history,
+			access:        access{memberStorage: memberStorage},
+		}
+
+		members, err := memberService.Adopt(testCtx)
+		require.Nil(t, err)
+		assert.Equal(t, []*m.Member{
+			{BoardID: 3, UserID: 1, Role: m.RoleOwner},
+			{BoardID: 4, UserID: 1, Role: m.RoleOwner},
+		}, members)
+		memberStorage.AssertCalled(t, "Save", &m.Member{BoardID: 3, UserID: 1, Role: m.RoleOwner})
+		// the viewer of the board is promoted
+		memberStorage.AssertCalled(t, "Update", &m.Member{BoardID: 4, UserID: 1, Role: m.RoleOwner})
+		assert.Equal(t, m.ActionCreate, recorded(activityStorage, 0).Action)
+		assert.Equal(t, m.Changes{"role": {From: "viewer", To: "owner"}}, recorded(activityStorage, 1).Changes)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		memberService := &MemberService{}
+
+		_, err := memberService.Adopt(context.Background())
+		assert.Equal(t, ErrUnauthenticated, err)
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		dbErr := errors.New("dummy")
+		txBeginner, tx := txStub(t, false)
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+		memberStorage.On("FindOwnerless").Return([]uint(nil), dbErr)
+		memberService := &MemberService{memberStorage: memberStorage, txBeginner: txBeginner}
+
+		_, err := memberService.Adopt(testCtx)
+		assert.Equal(t, dbErr, err)
+	})
+}
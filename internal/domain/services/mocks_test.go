@@ -3,10 +3,12 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	m "github.com/dnozdrin/detask/internal/domain/models"
 	v "github.com/dnozdrin/detask/internal/domain/validation"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type MockedValidation struct {
@@ -34,8 +36,8 @@ func (bs *MockedBoardStorage) FindOneById(ID uint) (*m.Board, error) {
 	return returnValues.Get(0).(*m.Board), returnValues.Error(1)
 }
 
-func (bs *MockedBoardStorage) Find() ([]*m.Board, error) {
-	returnValues := bs.Called()
+func (bs *MockedBoardStorage) Find(demand BoardDemand, page Page) ([]*m.Board, error) {
+	returnValues := bs.Called(demand, page)
 	return returnValues.Get(0).([]*m.Board), returnValues.Error(1)
 }
 
@@ -54,6 +56,11 @@ func (bs *MockedBoardStorage) WithTx(tx *sql.Tx) BoardStorage {
 	return returnValues.Get(0).(BoardStorage)
 }
 
+func (bs *MockedBoardStorage) Copy(sourceID, targetID uint, withTasks bool, userID uint) error {
+	returnValues := bs.Called(sourceID, targetID, withTasks, userID)
+	return returnValues.Error(0)
+}
+
 var _ ColumnStorage = new(MockedColumnStorage)
 
 type MockedColumnStorage struct {
@@ -70,8 +77,8 @@ func (cs *MockedColumnStorage) FindOneById(ID uint) (*m.Column, error) {
 	return returnValues.Get(0).(*m.Column), returnValues.Error(1)
 }
 
-func (cs *MockedColumnStorage) Find(demand ColumnDemand) ([]*m.Column, error) {
-	returnValues := cs.Called(demand)
+func (cs *MockedColumnStorage) Find(demand ColumnDemand, page Page) ([]*m.Column, error) {
+	returnValues := cs.Called(demand, page)
 	return returnValues.Get(0).([]*m.Column), returnValues.Error(1)
 }
 
@@ -105,6 +112,11 @@ func (cs *MockedColumnStorage) FindColumnToTheRight(ID uint) (uint, error) {
 	return returnValues.Get(0).(uint), returnValues.Error(1)
 }
 
+func (cs *MockedColumnStorage) Rebalance(boardID uint, IDs []uint, step float64) error {
+	returnValues := cs.Called(boardID, IDs, step)
+	return returnValues.Error(0)
+}
+
 var _ TaskStorage = new(MockedTaskStorage)
 
 type MockedTaskStorage struct {
@@ -121,8 +133,8 @@ func (ts *MockedTaskStorage) FindOneById(ID uint) (*m.Task, error) {
 	return returnValues.Get(0).(*m.Task), returnValues.Error(1)
 }
 
-func (ts *MockedTaskStorage) Find(demand TaskDemand) ([]*m.Task, error) {
-	returnValues := ts.Called(demand)
+func (ts *MockedTaskStorage) Find(demand TaskDemand, page Page) ([]*m.Task, error) {
+	returnValues := ts.Called(demand, page)
 	return returnValues.Get(0).([]*m.Task), returnValues.Error(1)
 }
 
@@ -131,6 +143,16 @@ func (ts *MockedTaskStorage) Update(task *m.Task) (*m.Task, error) {
 	return returnValues.Get(0).(*m.Task), returnValues.Error(1)
 }
 
+func (ts *MockedTaskStorage) SetAssignees(taskID uint, userIDs []uint) error {
+	returnValues := ts.Called(taskID, userIDs)
+	return returnValues.Error(0)
+}
+
+func (ts *MockedTaskStorage) SetLabels(taskID uint, labelIDs []uint) error {
+	returnValues := ts.Called(taskID, labelIDs)
+	return returnValues.Error(0)
+}
+
 func (ts *MockedTaskStorage) Delete(ID uint) error {
 	returnValues := ts.Called(ID)
 	return returnValues.Error(0)
@@ -146,6 +168,26 @@ func (ts *MockedTaskStorage) MoveToColumn(from, to uint) error {
 	return returnValues.Error(0)
 }
 
+func (ts *MockedTaskStorage) MatchLabels(taskID uint) error {
+	returnValues := ts.Called(taskID)
+	return returnValues.Error(0)
+}
+
+func (ts *MockedTaskStorage) Rebalance(columnID uint, IDs []uint, step float64) error {
+	returnValues := ts.Called(columnID, IDs, step)
+	return returnValues.Error(0)
+}
+
+func (ts *MockedTaskStorage) ColumnLoad(columnID uint) (uint, uint, error) {
+	returnValues := ts.Called(columnID)
+	return returnValues.Get(0).(uint), returnValues.Get(1).(uint), returnValues.Error(2)
+}
+
+func (ts *MockedTaskStorage) ColumnBoard(columnID uint) (uint, error) {
+	returnValues := ts.Called(columnID)
+	return returnValues.Get(0).(uint), returnValues.Error(1)
+}
+
 var _ CommentStorage = new(MockedCommentStorage)
 
 type MockedCommentStorage struct {
@@ -162,8 +204,8 @@ func (coms *MockedCommentStorage) FindOneById(ID uint) (*m.Comment, error) {
 	return returnValues.Get(0).(*m.Comment), returnValues.Error(1)
 }
 
-func (coms *MockedCommentStorage) Find(demand CommentDemand) ([]*m.Comment, error) {
-	returnValues := coms.Called(demand)
+func (coms *MockedCommentStorage) Find(demand CommentDemand, page Page) ([]*m.Comment, error) {
+	returnValues := coms.Called(demand, page)
 	return returnValues.Get(0).([]*m.Comment), returnValues.Error(1)
 }
 
@@ -177,6 +219,47 @@ func (coms *MockedCommentStorage) Delete(ID uint) error {
 	return returnValues.Error(0)
 }
 
+func (coms *MockedCommentStorage) WithTx(tx *sql.Tx) CommentStorage {
+	returnValues := coms.Called(tx)
+	return returnValues.Get(0).(CommentStorage)
+}
+
+var _ LabelStorage = new(MockedLabelStorage)
+
+type MockedLabelStorage struct {
+	mock.Mock
+}
+
+func (ls *MockedLabelStorage) Save(label *m.Label) (*m.Label, error) {
+	returnValues := ls.Called(label)
+	return returnValues.Get(0).(*m.Label), returnValues.Error(1)
+}
+
+func (ls *MockedLabelStorage) FindOneById(ID uint) (*m.Label, error) {
+	returnValues := ls.Called(ID)
+	return returnValues.Get(0).(*m.Label), returnValues.Error(1)
+}
+
+func (ls *MockedLabelStorage) Find(demand LabelDemand, page Page) ([]*m.Label, error) {
+	returnValues := ls.Called(demand, page)
+	return returnValues.Get(0).([]*m.Label), returnValues.Error(1)
+}
+
+func (ls *MockedLabelStorage) Update(label *m.Label) (*m.Label, error) {
+	returnValues := ls.Called(label)
+	return returnValues.Get(0).(*m.Label), returnValues.Error(1)
+}
+
+func (ls *MockedLabelStorage) Delete(ID uint) error {
+	returnValues := ls.Called(ID)
+	return returnValues.Error(0)
+}
+
+func (ls *MockedLabelStorage) WithTx(tx *sql.Tx) LabelStorage {
+	returnValues := ls.Called(tx)
+	return returnValues.Get(0).(LabelStorage)
+}
+
 var _ TxBeginner = new(MockedTxBeginner)
 
 type MockedTxBeginner struct {
@@ -187,3 +270,389 @@ func (txb *MockedTxBeginner) Begin() (*sql.Tx, error) {
 	returnValues := txb.Called()
 	return returnValues.Get(0).(*sql.Tx), returnValues.Error(1)
 }
+
+var _ UserStorage = new(MockedUserStorage)
+
+type MockedUserStorage struct {
+	mock.Mock
+}
+
+func (us *MockedUserStorage) Save(user *m.User) (*m.User, error) {
+	returnValues := us.Called(user)
+	return returnValues.Get(0).(*m.User), returnValues.Error(1)
+}
+
+func (us *MockedUserStorage) FindOneById(ID uint) (*m.User, error) {
+	returnValues := us.Called(ID)
+	return returnValues.Get(0).(*m.User), returnValues.Error(1)
+}
+
+func (us *MockedUserStorage) FindOneByEmail(email string) (*m.User, error) {
+	returnValues := us.Called(email)
+	return returnValues.Get(0).(*m.User), returnValues.Error(1)
+}
+
+var _ TokenManager = new(MockedTokenManager)
+
+type MockedTokenManager struct {
+	mock.Mock
+}
+
+func (tm *MockedTokenManager) Issue(userID uint) (string, error) {
+	returnValues := tm.Called(userID)
+	return returnValues.String(0), returnValues.Error(1)
+}
+
+func (tm *MockedTokenManager) Parse(token string) (uint, error) {
+	returnValues := tm.Called(token)
+	return returnValues.Get(0).(uint), returnValues.Error(1)
+}
+
+var _ MemberStorage = new(MockedMemberStorage)
+
+type MockedMemberStorage struct {
+	mock.Mock
+}
+
+func (ms *MockedMemberStorage) Save(member *m.Member) (*m.Member, error) {
+	returnValues := ms.Called(member)
+	return returnValues.Get(0).(*m.Member), returnValues.Error(1)
+}
+
+func (ms *MockedMemberStorage) Find(boardID uint) ([]*m.Member, error) {
+	returnValues := ms.Called(boardID)
+	return returnValues.Get(0).([]*m.Member), returnValues.Error(1)
+}
+
+func (ms *MockedMemberStorage) Update(member *m.Member) (*m.Member, error) {
+	returnValues := ms.Called(member)
+	return returnValues.Get(0).(*m.Member), returnValues.Error(1)
+}
+
+func (ms *MockedMemberStorage) Delete(boardID, userID uint) error {
+	returnValues := ms.Called(boardID, userID)
+	return returnValues.Error(0)
+}
+
+func (ms *MockedMemberStorage) CountOwners(boardID uint) (int, error) {
+	returnValues := ms.Called(boardID)
+	return returnValues.Int(0), returnValues.Error(1)
+}
+
+func (ms *MockedMemberStorage) FindOwnerless() ([]uint, error) {
+	returnValues := ms.Called()
+	return returnValues.Get(0).([]uint), returnValues.Error(1)
+}
+
+func (ms *MockedMemberStorage) FindRoleByBoard(boardID, userID uint) (m.Role, error) {
+	returnValues := ms.Called(boardID, userID)
+	return returnValues.Get(0).(m.Role), returnValues.Error(1)
+}
+
+func (ms *MockedMemberStorage) FindRoleByColumn(columnID, userID uint) (m.Role, error) {
+	returnValues := ms.Called(columnID, userID)
+	return returnValues.Get(0).(m.Role), returnValues.Error(1)
+}
+
+func (ms *MockedMemberStorage) FindRoleByTask(taskID, userID uint) (m.Role, error) {
+	returnValues := ms.Called(taskID, userID)
+	return returnValues.Get(0).(m.Role), returnValues.Error(1)
+}
+
+func (ms *MockedMemberStorage) WithTx(tx *sql.Tx) MemberStorage {
+	returnValues := ms.Called(tx)
+	return returnValues.Get(0).(MemberStorage)
+}
+
+var _ TrashStorage = new(MockedTrashStorage)
+
+type MockedTrashStorage struct {
+	mock.Mock
+}
+
+func (ts *MockedTrashStorage) Find(demand TrashDemand, page Page) ([]*m.TrashItem, error) {
+	returnValues := ts.Called(demand, page)
+	return returnValues.Get(0).([]*m.TrashItem), returnValues.Error(1)
+}
+
+func (ts *MockedTrashStorage) FindOne(kind m.TrashType, ID uint) (*m.TrashItem, error) {
+	returnValues := ts.Called(kind, ID)
+	return returnValues.Get(0).(*m.TrashItem), returnValues.Error(1)
+}
+
+func (ts *MockedTrashStorage) Restore(kind m.TrashType, ID uint, step float64) error {
+	returnValues := ts.Called(kind, ID, step)
+	return returnValues.Error(0)
+}
+
+func (ts *MockedTrashStorage) Purge(before time.Time) error {
+	returnValues := ts.Called(before)
+	return returnValues.Error(0)
+}
+
+func (ts *MockedTrashStorage) WithTx(tx *sql.Tx) TrashStorage {
+	returnValues := ts.Called(tx)
+	return returnValues.Get(0).(TrashStorage)
+}
+
+var _ ActivityStorage = new(MockedActivityStorage)
+
+type MockedActivityStorage struct {
+	mock.Mock
+}
+
+func (as *MockedActivityStorage) Save(entry *m.Activity) (*m.Activity, error) {
+	returnValues := as.Called(entry)
+	return returnValues.Get(0).(*m.Activity), returnValues.Error(1)
+}
+
+func (as *MockedActivityStorage) Find(demand ActivityDemand, page Page) ([]*m.Activity, error) {
+	returnValues := as.Called(demand, page)
+	return returnValues.Get(0).([]*m.Activity), returnValues.Error(1)
+}
+
+func (as *MockedActivityStorage) WithTx(tx *sql.Tx) ActivityStorage {
+	returnValues := as.Called(tx)
+	return returnValues.Get(0).(ActivityStorage)
+}
+
+type MockedEventBroker struct {
+	mock.Mock
+}
+
+func (eb *MockedEventBroker) Publish(event m.Event) {
+	eb.Called(event)
+}
+
+func (eb *MockedEventBroker) Subscribe(boardID, lastID uint) (<-chan m.Event, func()) {
+	returnValues := eb.Called(boardID, lastID)
+	return returnValues.Get(0).(<-chan m.Event), returnValues.Get(1).(func())
+}
+
+var _ OutboxStorage = new(MockedOutboxStorage)
+
+type MockedOutboxStorage struct {
+	mock.Mock
+}
+
+func (ob *MockedOutboxStorage) Save(event *m.Event) (*m.Event, error) {
+	returnValues := ob.Called(event)
+	return returnValues.Get(0).(*m.Event), returnValues.Error(1)
+}
+
+func (ob *MockedOutboxStorage) FindPending(now time.Time, limit uint) ([]*m.Event, error) {
+	returnValues := ob.Called(now, limit)
+	return returnValues.Get(0).([]*m.Event), returnValues.Error(1)
+}
+
+func (ob *MockedOutboxStorage) MarkConsumed(ID uint, consumer string) error {
+	returnValues := ob.Called(ID, consumer)
+	return returnValues.Error(0)
+}
+
+func (ob *MockedOutboxStorage) MarkFailed(ID uint, next *time.Time) error {
+	returnValues := ob.Called(ID, next)
+	return returnValues.Error(0)
+}
+
+func (ob *MockedOutboxStorage) MarkDelivered(ID uint) error {
+	returnValues := ob.Called(ID)
+	return returnValues.Error(0)
+}
+
+func (ob *MockedOutboxStorage) Purge(before time.Time) error {
+	returnValues := ob.Called(before)
+	return returnValues.Error(0)
+}
+
+func (ob *MockedOutboxStorage) WithTx(tx *sql.Tx) OutboxStorage {
+	returnValues := ob.Called(tx)
+	return returnValues.Get(0).(OutboxStorage)
+}
+
+type MockedSearchStorage struct {
+	mock.Mock
+}
+
+func (ss *MockedSearchStorage) Find(demand SearchDemand, page Page) ([]*m.SearchHit, error) {
+	returnValues := ss.Called(demand, page)
+	return returnValues.Get(0).([]*m.SearchHit), returnValues.Error(1)
+}
+
+var _ WebhookStorage = new(MockedWebhookStorage)
+
+type MockedWebhookStorage struct {
+	mock.Mock
+}
+
+func (ws *MockedWebhookStorage) Save(webhook *m.Webhook) (*m.Webhook, error) {
+	returnValues := ws.Called(webhook)
+	return returnValues.Get(0).(*m.Webhook), returnValues.Error(1)
+}
+
+func (ws *MockedWebhookStorage) FindOneById(ID uint) (*m.Webhook, error) {
+	returnValues := ws.Called(ID)
+	return returnValues.Get(0).(*m.Webhook), returnValues.Error(1)
+}
+
+func (ws *MockedWebhookStorage) Find(boardID uint, page Page) ([]*m.Webhook, error) {
+	returnValues := ws.Called(boardID, page)
+	return returnValues.Get(0).([]*m.Webhook), returnValues.Error(1)
+}
+
+func (ws *MockedWebhookStorage) Update(webhook *m.Webhook) (*m.Webhook, error) {
+	returnValues := ws.Called(webhook)
+	return returnValues.Get(0).(*m.Webhook), returnValues.Error(1)
+}
+
+func (ws *MockedWebhookStorage) Delete(ID uint) error {
+	returnValues := ws.Called(ID)
+	return returnValues.Error(0)
+}
+
+var _ DeliveryStorage = new(MockedDeliveryStorage)
+
+type MockedDeliveryStorage struct {
+	mock.Mock
+}
+
+func (ds *MockedDeliveryStorage) Save(delivery *m.Delivery) (*m.Delivery, error) {
+	returnValues := ds.Called(delivery)
+	return returnValues.Get(0).(*m.Delivery), returnValues.Error(1)
+}
+
+func (ds *MockedDeliveryStorage) FindOneById(ID uint) (*m.Delivery, error) {
+	returnValues := ds.Called(ID)
+	return returnValues.Get(0).(*m.Delivery), returnValues.Error(1)
+}
+
+func (ds *MockedDeliveryStorage) Find(webhookID uint, page Page) ([]*m.Delivery, error) {
+	returnValues := ds.Called(webhookID, page)
+	return returnValues.Get(0).([]*m.Delivery), returnValues.Error(1)
+}
+
+func (ds *MockedDeliveryStorage) Claim(now, lease time.Time, limit uint) ([]*m.Delivery, error) {
+	returnValues := ds.Called(now, lease, limit)
+	return returnValues.Get(0).([]*m.Delivery), returnValues.Error(1)
+}
+
+func (ds *MockedDeliveryStorage) Update(delivery *m.Delivery) (*m.Delivery, error) {
+	returnValues := ds.Called(delivery)
+	return returnValues.Get(0).(*m.Delivery), returnValues.Error(1)
+}
+
+var _ WebhookSender = new(MockedWebhookSender)
+
+type MockedWebhookSender struct {
+	mock.Mock
+}
+
+func (ws *MockedWebhookSender) Send(ctx context.Context, webhook m.Webhook, delivery m.Delivery) (int, error) {
+	returnValues := ws.Called(ctx, webhook, delivery)
+	return returnValues.Int(0), returnValues.Error(1)
+}
+
+var _ RuleStorage = new(MockedRuleStorage)
+
+type MockedRuleStorage struct {
+	mock.Mock
+}
+
+func (rs *MockedRuleStorage) Save(rule *m.Rule) (*m.Rule, error) {
+	returnValues := rs.Called(rule)
+	return returnValues.Get(0).(*m.Rule), returnValues.Error(1)
+}
+
+func (rs *MockedRuleStorage) FindOneById(ID uint) (*m.Rule, error) {
+	returnValues := rs.Called(ID)
+	return returnValues.Get(0).(*m.Rule), returnValues.Error(1)
+}
+
+func (rs *MockedRuleStorage) Find(boardID uint, page Page) ([]*m.Rule, error) {
+	returnValues := rs.Called(boardID, page)
+	return returnValues.Get(0).([]*m.Rule), returnValues.Error(1)
+}
+
+func (rs *MockedRuleStorage) FindTriggered(trigger m.TriggerType, boardID uint) ([]*m.Rule, error) {
+	returnValues := rs.Called(trigger, boardID)
+	return returnValues.Get(0).([]*m.Rule), returnValues.Error(1)
+}
+
+func (rs *MockedRuleStorage) Update(rule *m.Rule) (*m.Rule, error) {
+	returnValues := rs.Called(rule)
+	return returnValues.Get(0).(*m.Rule), returnValues.Error(1)
+}
+
+func (rs *MockedRuleStorage) Delete(ID uint) error {
+	returnValues := rs.Called(ID)
+	return returnValues.Error(0)
+}
+
+var _ ExecutionStorage = new(MockedExecutionStorage)
+
+type MockedExecutionStorage struct {
+	mock.Mock
+}
+
+func (es *MockedExecutionStorage) Save(execution *m.RuleExecution) (*m.RuleExecution, error) {
+	returnValues := es.Called(execution)
+	return returnValues.Get(0).(*m.RuleExecution), returnValues.Error(1)
+}
+
+func (es *MockedExecutionStorage) Find(ruleID uint, page Page) ([]*m.RuleExecution, error) {
+	returnValues := es.Called(ruleID, page)
+	return returnValues.Get(0).([]*m.RuleExecution), returnValues.Error(1)
+}
+
+func (es *MockedExecutionStorage) FindLast(ruleID, taskID uint) (*m.RuleExecution, error) {
+	returnValues := es.Called(ruleID, taskID)
+	return returnValues.Get(0).(*m.RuleExecution), returnValues.Error(1)
+}
+
+func (es *MockedExecutionStorage) FindByEvent(ruleID, eventID uint) (*m.RuleExecution, error) {
+	returnValues := es.Called(ruleID, eventID)
+	return returnValues.Get(0).(*m.RuleExecution), returnValues.Error(1)
+}
+
+var _ ruleTasks = new(mockedRuleTasks)
+
+type mockedRuleTasks struct {
+	mock.Mock
+}
+
+func (rt *mockedRuleTasks) FindOneById(ctx context.Context, ID uint) (*m.Task, error) {
+	returnValues := rt.Called(ctx, ID)
+	return returnValues.Get(0).(*m.Task), returnValues.Error(1)
+}
+
+func (rt *mockedRuleTasks) Update(ctx context.Context, task *m.Task) (*m.Task, error) {
+	returnValues := rt.Called(ctx, task)
+	return returnValues.Get(0).(*m.Task), returnValues.Error(1)
+}
+
+func (rt *mockedRuleTasks) Move(ctx context.Context, ID uint, move m.TaskMove) (*m.Task, error) {
+	returnValues := rt.Called(ctx, ID, move)
+	return returnValues.Get(0).(*m.Task), returnValues.Error(1)
+}
+
+var _ ruleComments = new(mockedRuleComments)
+
+type mockedRuleComments struct {
+	mock.Mock
+}
+
+func (rc *mockedRuleComments) Create(ctx context.Context, comment *m.Comment) (*m.Comment, error) {
+	returnValues := rc.Called(ctx, comment)
+	return returnValues.Get(0).(*m.Comment), returnValues.Error(1)
+}
+
+var _ ruleWebhooks = new(mockedRuleWebhooks)
+
+type mockedRuleWebhooks struct {
+	mock.Mock
+}
+
+func (rw *mockedRuleWebhooks) Dispatch(ctx context.Context, webhookID uint, event m.Event) error {
+	returnValues := rw.Called(ctx, webhookID, event)
+	return returnValues.Error(0)
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// outboxBatch is the number of the pending events relayed at once
+	outboxBatch = 100
+	// outboxBackoff is the delay of the first retry of a failed event, the delay is
+	// doubled with every next attempt up to the maximal backoff
+	outboxBackoff = 5 * time.Second
+	// maxOutboxBackoff is the longest delay between the relays of an event
+	maxOutboxBackoff = 5 * time.Minute
+	// outboxAttempts is the number of the relays an event may fail before it is dead
+	outboxAttempts = 10
+)
+
+// OutboxRelay passes the events saved to the outbox within the transactions of the
+// changes to the registered consumers, such as the event streams and the webhooks,
+// once the transactions are committed
+type OutboxRelay struct {
+	outboxStorage OutboxStorage
+	backoff       time.Duration
+	maxBackoff    time.Duration
+	maxAttempts   uint
+
+	mu        sync.Mutex
+	consumers []outboxConsumer
+}
+
+// outboxConsumer is a named function the relayed events are passed to
+type outboxConsumer struct {
+	name    string
+	consume func(m.Event) error
+}
+
+// NewOutboxRelay is an outbox relay constructor
+func NewOutboxRelay(outboxStorage OutboxStorage) *OutboxRelay {
+	return &OutboxRelay{
+		outboxStorage: outboxStorage,
+		backoff:       outboxBackoff,
+		maxBackoff:    maxOutboxBackoff,
+		maxAttempts:   outboxAttempts,
+	}
+}
+
+// Register will add the consumer with the provided name, the events are passed to
+// the consumers in the order they are registered
+func (r *OutboxRelay) Register(name string, consume func(m.Event) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.consumers = append(r.consumers, outboxConsumer{name: name, consume: consume})
+}
+
+// Relay will pass the batch of the pending events to the consumers in the order of
+// their IDs and mark them delivered once all the consumers have accepted them. Every
+// consumer that accepts an event is recorded, so an event a consumer has failed is
+// passed again only to the consumers that have not accepted it yet, once the retry
+// is due, while the relay goes on with the next events. The consumers get every
+// event at least once, the ones that have failed an event may get the next events
+// before it. An event that has failed the maximal number of relays is dead and is
+// not relayed anymore. Returns the number of the relayed events
+func (r *OutboxRelay) Relay() (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	events, err := r.outboxStorage.FindPending(now, outboxBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var failures []string
+	for _, event := range events {
+		failed, err := r.relay(event)
+		if err != nil {
+			return 0, err
+		}
+		if len(failed) == 0 {
+			if err = r.outboxStorage.MarkDelivered(event.ID); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		var next *time.Time
+		if attempts := event.Attempts + 1; attempts < r.maxAttempts {
+			retry := now.Add(backoffDelay(r.backoff, r.maxBackoff, attempts))
+			next = &retry
+		} else {
+			failed = append(failed, fmt.Sprintf("the event %d is dead after %d attempts", event.ID, attempts))
+		}
+		if err = r.outboxStorage.MarkFailed(event.ID, next); err != nil {
+			return 0, err
+		}
+		failures = append(failures, failed...)
+	}
+
+	if len(failures) > 0 {
+		return len(events), errors.New(strings.Join(failures, "; "))
+	}
+
+	return len(events), nil
+}
+
+// relay will pass the event to the consumers that have not accepted it yet and record
+// the ones that accept it. Returns the failures of the consumers
+func (r *OutboxRelay) relay(event *m.Event) ([]string, error) {
+	consumed := make(map[string]bool, len(event.Consumers))
+	for _, name := range event.Consumers {
+		consumed[name] = true
+	}
+
+	var failed []string
+	for _, consumer := range r.consumers {
+		if consumed[consumer.name] {
+			continue
+		}
+		if err := consumer.consume(*event); err != nil {
+			failed = append(failed, fmt.Sprintf("%s consumer failed the event %d: %v", consumer.name, event.ID, err))
+			continue
+		}
+		if err := r.outboxStorage.MarkConsumed(event.ID, consumer.name); err != nil {
+			return nil, err
+		}
+	}
+
+	return failed, nil
+}
+
+// Purge will delete the events that have been delivered longer than the provided
+// retention period ago
+func (r *OutboxRelay) Purge(retention time.Duration) error {
+	return r.outboxStorage.Purge(time.Now().Add(-retention))
+}
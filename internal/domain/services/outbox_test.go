@@ -0,0 +1,179 @@
+// +build unit
+
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewOutboxRelay(t *testing.T) {
+	outboxStorage := new(MockedOutboxStorage)
+	relay := NewOutboxRelay(outboxStorage)
+
+	assert.Equal(t, outboxStorage, relay.outboxStorage)
+	assert.Equal(t, outboxBackoff, relay.backoff)
+	assert.Equal(t, maxOutboxBackoff, relay.maxBackoff)
+	assert.Equal(t, uint(outboxAttempts), relay.maxAttempts)
+	assert.Empty(t, relay.consumers)
+}
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	pending := func() []*m.Event {
+		return []*m.Event{{ID: 3, BoardID: 1}, {ID: 5, BoardID: 2}}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("FindPending", mock.Anything, uint(outboxBatch)).Return(pending(), nil)
+		outboxStorage.On("MarkConsumed", mock.Anything, mock.Anything).Return(nil)
+		outboxStorage.On("MarkDelivered", mock.Anything).Return(nil)
+		relay := NewOutboxRelay(outboxStorage)
+		consumed := make([]string, 0)
+		for _, name := range []string{"first", "second"} {
+			name := name
+			relay.Register(name, func(event m.Event) error {
+				consumed = append(consumed, fmt.Sprintf("%s:%d", name, event.ID))
+				return nil
+			})
+		}
+
+		n, err := relay.Relay()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+		assert.Equal(t, []string{"first:3", "second:3", "first:5", "second:5"}, consumed)
+		outboxStorage.AssertCalled(t, "MarkConsumed", uint(3), "first")
+		outboxStorage.AssertCalled(t, "MarkConsumed", uint(5), "second")
+		outboxStorage.AssertCalled(t, "MarkDelivered", uint(3))
+		outboxStorage.AssertCalled(t, "MarkDelivered", uint(5))
+		now := outboxStorage.Calls[0].Arguments.Get(0).(time.Time)
+		assert.WithinDuration(t, time.Now(), now, time.Minute)
+	})
+
+	t.Run("consumed_skipped", func(t *testing.T) {
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("FindPending", mock.Anything, uint(outboxBatch)).
+			Return([]*m.Event{{ID: 3, Consumers: []string{"first"}, Attempts: 1}}, nil)
+		outboxStorage.On("MarkConsumed", uint(3), "second").Return(nil)
+		outboxStorage.On("MarkDelivered", uint(3)).Return(nil)
+		relay := NewOutboxRelay(outboxStorage)
+		consumed := make([]string, 0)
+		for _, name := range []string{"first", "second"} {
+			name := name
+			relay.Register(name, func(event m.Event) error {
+				consumed = append(consumed, fmt.Sprintf("%s:%d", name, event.ID))
+				return nil
+			})
+		}
+
+		n, err := relay.Relay()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, []string{"second:3"}, consumed)
+		outboxStorage.AssertNotCalled(t, "MarkConsumed", uint(3), "first")
+	})
+
+	t.Run("consumer_error", func(t *testing.T) {
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("FindPending", mock.Anything, uint(outboxBatch)).Return(pending(), nil)
+		outboxStorage.On("MarkConsumed", mock.Anything, mock.Anything).Return(nil)
+		outboxStorage.On("MarkFailed", uint(3), mock.Anything).Return(nil)
+		outboxStorage.On("MarkDelivered", uint(5)).Return(nil)
+		relay := NewOutboxRelay(outboxStorage)
+		relay.Register("failing", func(event m.Event) error {
+			if event.ID == 3 {
+				return errors.New("dummy")
+			}
+			return nil
+		})
+		relay.Register("dummy", func(m.Event) error { return nil })
+
+		n, err := relay.Relay()
+
+		assert.EqualError(t, err, "failing consumer failed the event 3: dummy")
+		assert.Equal(t, 2, n)
+		outboxStorage.AssertNotCalled(t, "MarkConsumed", uint(3), "failing")
+		outboxStorage.AssertCalled(t, "MarkConsumed", uint(3), "dummy")
+		outboxStorage.AssertNotCalled(t, "MarkDelivered", uint(3))
+		for _, call := range outboxStorage.Calls {
+			if call.Method == "MarkFailed" {
+				next := call.Arguments.Get(1).(*time.Time)
+				if assert.NotNil(t, next) {
+					assert.WithinDuration(t, time.Now().Add(outboxBackoff), *next, time.Minute)
+				}
+			}
+		}
+	})
+
+	t.Run("dead", func(t *testing.T) {
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("FindPending", mock.Anything, uint(outboxBatch)).
+			Return([]*m.Event{{ID: 3, Attempts: outboxAttempts - 1}}, nil)
+		outboxStorage.On("MarkFailed", uint(3), (*time.Time)(nil)).Return(nil)
+		relay := NewOutboxRelay(outboxStorage)
+		relay.Register("dummy", func(event m.Event) error {
+			return errors.New("dummy")
+		})
+
+		n, err := relay.Relay()
+
+		assert.EqualError(t, err, "dummy consumer failed the event 3: dummy; the event 3 is dead after 10 attempts")
+		assert.Equal(t, 1, n)
+		outboxStorage.AssertExpectations(t)
+	})
+
+	t.Run("storage_error", func(t *testing.T) {
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("FindPending", mock.Anything, uint(outboxBatch)).Return(([]*m.Event)(nil), errors.New("dummy"))
+		relay := NewOutboxRelay(outboxStorage)
+
+		n, err := relay.Relay()
+
+		assert.Error(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("mark_error", func(t *testing.T) {
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("FindPending", mock.Anything, uint(outboxBatch)).Return(pending(), nil)
+		outboxStorage.On("MarkDelivered", uint(3)).Return(errors.New("dummy"))
+		relay := NewOutboxRelay(outboxStorage)
+
+		n, err := relay.Relay()
+
+		assert.Error(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("mark_consumed_error", func(t *testing.T) {
+		outboxStorage := new(MockedOutboxStorage)
+		outboxStorage.On("FindPending", mock.Anything, uint(outboxBatch)).Return(pending(), nil)
+		outboxStorage.On("MarkConsumed", uint(3), "dummy").Return(errors.New("dummy"))
+		relay := NewOutboxRelay(outboxStorage)
+		relay.Register("dummy", func(m.Event) error { return nil })
+
+		n, err := relay.Relay()
+
+		assert.Error(t, err)
+		assert.Zero(t, n)
+		outboxStorage.AssertNotCalled(t, "MarkDelivered", mock.Anything)
+	})
+}
+
+func TestOutboxRelay_Purge(t *testing.T) {
+	outboxStorage := new(MockedOutboxStorage)
+	outboxStorage.On("Purge", mock.Anything).Return(nil)
+	relay := NewOutboxRelay(outboxStorage)
+
+	assert.Nil(t, relay.Purge(time.Hour))
+	before := outboxStorage.Calls[0].Arguments.Get(0).(time.Time)
+	assert.WithinDuration(t, time.Now().Add(-time.Hour), before, time.Minute)
+}
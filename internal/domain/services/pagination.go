@@ -0,0 +1,124 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+)
+
+// MaxPageLimit is the maximum number of records that may be requested at once
+const MaxPageLimit = 500
+
+var (
+	// ErrInvalidCursor is returned in case of a malformed pagination cursor
+	ErrInvalidCursor = errors.New("pagination cursor is invalid")
+
+	// ErrInvalidLimit is returned in case of a pagination limit that is out of range
+	ErrInvalidLimit = errors.Errorf("pagination limit must be between 1 and %d", MaxPageLimit)
+)
+
+// Cursor points to the last record of a page in a sorted result set. It holds
+// the values of all the fields the result set is sorted by, so the next page
+// may be fetched by the keyset pagination.
+type Cursor struct {
+	ID        uint       `json:"id"`
+	Position  float64    `json:"position,omitempty"`
+	Priority  m.Priority `json:"priority,omitempty"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	Type      string     `json:"type,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	Rank      float64    `json:"rank,omitempty"`
+}
+
+// Encode will return the opaque string representation of the cursor
+func (c Cursor) Encode() string {
+	c.CreatedAt = c.CreatedAt.UTC()
+	if c.DueAt != nil {
+		due := c.DueAt.UTC()
+		c.DueAt = &due
+	}
+	if c.DeletedAt != nil {
+		deleted := c.DeletedAt.UTC()
+		c.DeletedAt = &deleted
+	}
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor will parse a cursor from its opaque string representation
+func DecodeCursor(encoded string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	cursor := &Cursor{}
+	if err = json.Unmarshal(data, cursor); err != nil || cursor.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// Page represents keyset pagination parameters
+type Page struct {
+	// Limit is the maximum number of records on the page, zero means no limit
+	Limit uint
+	// After points to the last record of the previous page, nil means the first page
+	After *Cursor
+}
+
+// Add will set the pagination parameter or will return an error if
+// the field is unknown or the value is invalid
+func (p *Page) Add(field, value string) error {
+	switch field {
+	case "limit":
+		limit, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || limit == 0 || limit > MaxPageLimit {
+			return ErrInvalidLimit
+		}
+		p.Limit = uint(limit)
+	case "cursor":
+		cursor, err := DecodeCursor(value)
+		if err != nil {
+			return err
+		}
+		p.After = cursor
+	default:
+		return ErrFilterNotAllowed
+	}
+
+	return nil
+}
+
+// SortKey is a field a result set is sorted by along with the direction
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// Sort is the list of keys a result set is sorted by, the records with equal
+// keys are sorted by ID
+type Sort []SortKey
+
+// lookAhead returns the page extended by one record, which tells if there
+// is a next page
+func (p Page) lookAhead() Page {
+	if p.Limit > 0 {
+		p.Limit++
+	}
+
+	return p
+}
+
+// hasMore checks if the number of the records fetched with the look ahead
+// page means there is a next page
+func (p Page) hasMore(num int) bool {
+	return p.Limit > 0 && num > int(p.Limit)
+}
@@ -0,0 +1,80 @@
+// +build unit
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor_Encode(t *testing.T) {
+	cursor := Cursor{ID: 10, Position: 1500.5, CreatedAt: time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC)}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, cursor.ID, decoded.ID)
+	assert.Equal(t, cursor.Position, decoded.Position)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+}
+
+func TestCursor_EncodeTaskKeys(t *testing.T) {
+	due := time.Date(2020, 7, 1, 13, 0, 0, 0, time.FixedZone("EEST", 3*60*60))
+	cursor := Cursor{ID: 10, Priority: m.PriorityHigh, DueAt: &due}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, m.PriorityHigh, decoded.Priority)
+	assert.Equal(t, time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC), *decoded.DueAt)
+}
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"not_base64", "!!!"},
+		{"not_json", "ZHVtbXk"},
+		{"no_id", Cursor{Position: 1}.Encode()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := DecodeCursor(tt.encoded)
+			assert.Nil(t, cursor)
+			assert.Equal(t, ErrInvalidCursor, err)
+		})
+	}
+}
+
+func TestPage_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		value   string
+		wantErr error
+	}{
+		{"success_limit", "limit", "10", nil},
+		{"success_cursor", "cursor", Cursor{ID: 1}.Encode(), nil},
+		{"error_limit_zero", "limit", "0", ErrInvalidLimit},
+		{"error_limit_too_big", "limit", "501", ErrInvalidLimit},
+		{"error_limit_not_number", "limit", "ten", ErrInvalidLimit},
+		{"error_cursor", "cursor", "dummy", ErrInvalidCursor},
+		{"error_field", "dummy", "1", ErrFilterNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := Page{}
+			assert.Equal(t, tt.wantErr, page.Add(tt.field, tt.value))
+		})
+	}
+}
+
+func TestPage_hasMore(t *testing.T) {
+	assert.False(t, Page{}.hasMore(10))
+	assert.False(t, Page{Limit: 10}.hasMore(10))
+	assert.True(t, Page{Limit: 10}.hasMore(11))
+	assert.Equal(t, uint(11), Page{Limit: 10}.lookAhead().Limit)
+	assert.Equal(t, uint(0), Page{}.lookAhead().Limit)
+}
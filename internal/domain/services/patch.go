@@ -0,0 +1,342 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// PatchType is the media type of a patch document
+type PatchType string
+
+const (
+	// MergePatch is the media type of JSON Merge Patch (RFC 7396) documents
+	MergePatch PatchType = "application/merge-patch+json"
+	// JSONPatch is the media type of JSON Patch (RFC 6902) documents
+	JSONPatch PatchType = "application/json-patch+json"
+)
+
+// Patch is a partial change of a record described by a document of the patch type,
+// the patch is applied to the JSON representation of the record
+type Patch struct {
+	Type     PatchType
+	Document []byte
+}
+
+// apply will apply the patch to the JSON representation of the provided record
+// and decode the result into the patched record
+func (p Patch) apply(record, patched interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err = decodeJSON(data, &doc); err != nil {
+		return err
+	}
+
+	switch p.Type {
+	case MergePatch:
+		var patch interface{}
+		if err = decodeJSON(p.Document, &patch); err != nil {
+			return ErrInvalidPatch
+		}
+		doc = mergePatch(doc, patch)
+	case JSONPatch:
+		var operations []patchOperation
+		if err = decodeJSON(p.Document, &operations); err != nil {
+			return ErrInvalidPatch
+		}
+		for _, operation := range operations {
+			if doc, err = operation.apply(doc); err != nil {
+				return err
+			}
+		}
+	default:
+		return ErrInvalidPatch
+	}
+
+	if data, err = json.Marshal(doc); err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, patched); err != nil {
+		return ErrInvalidPatch
+	}
+
+	return nil
+}
+
+// mergePatch returns the target document merged with the patch: the members of
+// the patch replace the members of the target, recursively for objects, and the
+// null members remove them
+func mergePatch(target, patch interface{}) interface{} {
+	members, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	doc, ok := target.(map[string]interface{})
+	if !ok {
+		doc = make(map[string]interface{})
+	}
+	for name, value := range members {
+		if value == nil {
+			delete(doc, name)
+		} else {
+			doc[name] = mergePatch(doc[name], value)
+		}
+	}
+
+	return doc
+}
+
+// patchOperation is an operation of a JSON patch
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// apply returns the document changed by the operation
+func (o patchOperation) apply(doc interface{}) (interface{}, error) {
+	path, err := parsePointer(o.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch o.Op {
+	case "add":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		return path.add(doc, value)
+	case "remove":
+		return path.remove(doc)
+	case "replace":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return value, nil
+		}
+		if doc, err = path.remove(doc); err != nil {
+			return nil, err
+		}
+		return path.add(doc, value)
+	case "move", "copy":
+		from, err := parsePointer(o.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := from.get(doc)
+		if err != nil {
+			return nil, err
+		}
+		if o.Op == "copy" {
+			if value, err = copyJSON(value); err != nil {
+				return nil, err
+			}
+		} else {
+			if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+				return nil, ErrInvalidPatch
+			}
+			if doc, err = from.remove(doc); err != nil {
+				return nil, err
+			}
+		}
+		return path.add(doc, value)
+	case "test":
+		value, err := o.value()
+		if err != nil {
+			return nil, err
+		}
+		if actual, err := path.get(doc); err != nil || !reflect.DeepEqual(actual, value) {
+			return nil, ErrPatchTestFailed
+		}
+		return doc, nil
+	}
+
+	return nil, ErrInvalidPatch
+}
+
+// value returns the decoded value of the operation, which is required
+func (o patchOperation) value() (interface{}, error) {
+	var value interface{}
+	if o.Value == nil || decodeJSON(o.Value, &value) != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	return value, nil
+}
+
+// pointer is a parsed JSON Pointer (RFC 6901) to a value of a document,
+// the empty pointer refers to the whole document
+type pointer []string
+
+// parsePointer returns the pointer with the provided string representation
+func parsePointer(path string) (pointer, error) {
+	if path == "" {
+		return pointer{}, nil
+	}
+	if !strings.HasPrefix(path, "/") {
+		return nil, ErrInvalidPatch
+	}
+
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = unescape.Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// get returns the value of the document the pointer refers to
+func (p pointer) get(doc interface{}) (interface{}, error) {
+	for _, token := range p {
+		switch container := doc.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, ErrInvalidPatch
+			}
+			doc = value
+		case []interface{}:
+			i, err := index(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			doc = container[i]
+		default:
+			return nil, ErrInvalidPatch
+		}
+	}
+
+	return doc, nil
+}
+
+// add returns the document with the value added at the pointer, the value of an
+// object member is replaced and the value of an array is inserted
+func (p pointer) add(doc, value interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return value, nil
+	}
+
+	return p.change(doc, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			i := len(container)
+			if token != "-" {
+				var err error
+				if i, err = index(token, len(container)+1); err != nil {
+					return nil, err
+				}
+			}
+			container = append(container, nil)
+			copy(container[i+1:], container[i:])
+			container[i] = value
+			return container, nil
+		}
+		return nil, ErrInvalidPatch
+	})
+}
+
+// remove returns the document without the value the pointer refers to
+func (p pointer) remove(doc interface{}) (interface{}, error) {
+	if len(p) == 0 {
+		return nil, ErrInvalidPatch
+	}
+
+	return p.change(doc, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok {
+				return nil, ErrInvalidPatch
+			}
+			delete(container, token)
+			return container, nil
+		case []interface{}:
+			i, err := index(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			return append(container[:i], container[i+1:]...), nil
+		}
+		return nil, ErrInvalidPatch
+	})
+}
+
+// change returns the document with the container, that holds the value the pointer
+// refers to, replaced by the result of the provided function for the last token
+func (p pointer) change(
+	doc interface{},
+	fn func(container interface{}, token string) (interface{}, error),
+) (interface{}, error) {
+	if len(p) == 1 {
+		return fn(doc, p[0])
+	}
+
+	child, err := p[:1].get(doc)
+	if err != nil {
+		return nil, err
+	}
+	if child, err = p[1:].change(child, fn); err != nil {
+		return nil, err
+	}
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		container[p[0]] = child
+	case []interface{}:
+		i, _ := index(p[0], len(container))
+		container[i] = child
+	}
+
+	return doc, nil
+}
+
+// index returns the array index the token refers to, it must be less than n
+func index(token string, n int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= n || strconv.Itoa(i) != token {
+		return 0, ErrInvalidPatch
+	}
+
+	return i, nil
+}
+
+// copyJSON returns the deep copy of the decoded JSON value
+func copyJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var copied interface{}
+	if err = decodeJSON(data, &copied); err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
+
+// decodeJSON decodes the single JSON value of the data, the numbers are kept
+// as they are written
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return ErrInvalidPatch
+	}
+
+	return nil
+}
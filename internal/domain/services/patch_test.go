@@ -0,0 +1,88 @@
+// +build unit
+
+package services
+
+import (
+	"testing"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatch_Apply(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    Patch
+		expected m.Task
+		err      error
+	}{
+		{
+			name:     "merge_patch",
+			patch:    Patch{Type: MergePatch, Document: []byte(`{"name":"new","priority":null,"labels":[4]}`)},
+			expected: m.Task{Model: m.Model{ID: 1}, Name: "new", Description: "dummy", ColumnID: 2, Position: 1, Assignees: []uint{1, 2}, Labels: []uint{4}},
+		},
+		{
+			name: "json_patch",
+			patch: Patch{Type: JSONPatch, Document: []byte(`[
+				{"op":"test","path":"/name","value":"old"},
+				{"op":"replace","path":"/name","value":"new"},
+				{"op":"add","path":"/assignees/0","value":3},
+				{"op":"remove","path":"/assignees/2"},
+				{"op":"copy","from":"/assignees/1","path":"/labels/-"},
+				{"op":"move","from":"/position","path":"/column"}
+			]`)},
+			expected: m.Task{Model: m.Model{ID: 1}, Name: "new", Description: "dummy", ColumnID: 1, Assignees: []uint{3, 1}, Labels: []uint{5, 1}, Priority: m.PriorityHigh},
+		},
+		{
+			name:  "test_failed",
+			patch: Patch{Type: JSONPatch, Document: []byte(`[{"op":"test","path":"/name","value":"other"}]`)},
+			err:   ErrPatchTestFailed,
+		},
+		{
+			name:  "missing_path",
+			patch: Patch{Type: JSONPatch, Document: []byte(`[{"op":"remove","path":"/assignees/5"}]`)},
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "unknown_operation",
+			patch: Patch{Type: JSONPatch, Document: []byte(`[{"op":"drop","path":"/name"}]`)},
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "move_into_child",
+			patch: Patch{Type: JSONPatch, Document: []byte(`[{"op":"move","from":"/labels","path":"/labels/0"}]`)},
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "malformed_document",
+			patch: Patch{Type: MergePatch, Document: []byte(`{"name":"new"}{}`)},
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "mismatched_type",
+			patch: Patch{Type: MergePatch, Document: []byte(`{"column":"first"}`)},
+			err:   ErrInvalidPatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := &m.Task{
+				Model:       m.Model{ID: 1, Version: 3},
+				Name:        "old",
+				Description: "dummy",
+				ColumnID:    2,
+				Position:    1,
+				Assignees:   []uint{1, 2},
+				Labels:      []uint{5},
+				Priority:    m.PriorityHigh,
+			}
+			patched := new(m.Task)
+			err := tt.patch.apply(stored, patched)
+
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Equal(t, tt.expected, *patched)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+// positionStep is the distance between the positions of the neighbouring records
+// after a rebalancing, as well as between the last record and the one moved to
+// the end
+const positionStep = 1000
+
+// minPositionGap is the smallest distance between a moved record and its
+// neighbours, the records are rebalanced instead of being squeezed closer
+const minPositionGap = 0.001
+
+// moveIndex returns the index a record is moved to within the IDs of its new
+// neighbours sorted by position, right before or right after the one with the
+// provided ID, or to the end if neither is provided. ok is false if the record
+// the move refers to is not among the neighbours.
+func moveIndex(IDs []uint, beforeID, afterID uint) (index int, ok bool) {
+	referenceID := beforeID
+	if referenceID == 0 {
+		referenceID = afterID
+	}
+	if referenceID == 0 {
+		return len(IDs), true
+	}
+
+	for i, ID := range IDs {
+		if ID == referenceID {
+			if afterID != 0 {
+				i++
+			}
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// positionAt returns the position between the neighbours of the provided index
+// within the sorted positions, ok is false if it would be closer than the minimal
+// gap to any of them. The zero position is never returned, as records are
+// required to have a non-zero one, so a position before a positive one keeps the
+// minimal gap from zero as well.
+func positionAt(positions []float64, index int) (position float64, ok bool) {
+	switch {
+	case len(positions) == 0:
+		return positionStep, true
+	case index == len(positions):
+		prev := positions[index-1]
+		position = prev + positionStep
+		return position, position-prev >= minPositionGap && position != 0
+	case index == 0:
+		next := positions[0]
+		if next > 0 {
+			position = next / 2
+			return position, position >= minPositionGap
+		}
+		position = next - positionStep
+		return position, next-position >= minPositionGap
+	default:
+		prev, next := positions[index-1], positions[index]
+		position = prev + (next-prev)/2
+		return position, position-prev >= minPositionGap && next-position >= minPositionGap && position != 0
+	}
+}
+
+// insertID returns a copy of the IDs with the provided one inserted at the index
+func insertID(IDs []uint, index int, ID uint) []uint {
+	inserted := make([]uint, 0, len(IDs)+1)
+	inserted = append(inserted, IDs[:index]...)
+	inserted = append(inserted, ID)
+
+	return append(inserted, IDs[index:]...)
+}
@@ -0,0 +1,76 @@
+// +build unit
+
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveIndex(t *testing.T) {
+	IDs := []uint{3, 1, 2}
+
+	tests := []struct {
+		name     string
+		beforeID uint
+		afterID  uint
+		index    int
+		ok       bool
+	}{
+		{"to_the_end", 0, 0, 3, true},
+		{"before_the_first", 3, 0, 0, true},
+		{"before_the_last", 2, 0, 2, true},
+		{"after_the_first", 0, 3, 1, true},
+		{"after_the_last", 0, 2, 3, true},
+		{"missing_reference", 9, 0, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index, ok := moveIndex(IDs, test.beforeID, test.afterID)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.index, index)
+		})
+	}
+}
+
+func TestPositionAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions []float64
+		index     int
+		position  float64
+		ok        bool
+	}{
+		{"empty", nil, 0, 1000, true},
+		{"first_positive", []float64{10, 20}, 0, 5, true},
+		{"first_negative", []float64{-10, 20}, 0, -1010, true},
+		{"between", []float64{10, 20}, 1, 15, true},
+		{"last", []float64{10, 20}, 2, 1020, true},
+		{"last_reaching_zero", []float64{-1000}, 1, 0, false},
+		{"no_room_between", []float64{1, math.Nextafter(1, 2)}, 1, 1, false},
+		{"no_room_before", []float64{math.SmallestNonzeroFloat64}, 0, 0, false},
+		{"gap_too_small_between", []float64{1, 1.001}, 1, 0, false},
+		{"gap_too_small_before", []float64{0.0015}, 0, 0, false},
+		{"gap_too_small_last", []float64{1e20}, 1, 0, false},
+		{"gap_too_small_first", []float64{-1e20}, 0, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			position, ok := positionAt(test.positions, test.index)
+			assert.Equal(t, test.ok, ok)
+			if ok {
+				assert.Equal(t, test.position, position)
+			}
+		})
+	}
+}
+
+func TestInsertID(t *testing.T) {
+	IDs := []uint{1, 2}
+	assert.Equal(t, []uint{3, 1, 2}, insertID(IDs, 0, 3))
+	assert.Equal(t, []uint{1, 3, 2}, insertID(IDs, 1, 3))
+	assert.Equal(t, []uint{1, 2, 3}, insertID(IDs, 2, 3))
+	assert.Equal(t, []uint{1, 2}, IDs)
+}
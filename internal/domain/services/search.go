@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+)
+
+// SearchService is an interactor for the full-text search of tasks and comments
+type SearchService struct {
+	searchStorage SearchStorage
+	access        access
+}
+
+// NewSearchService is a search service constructor
+func NewSearchService(searchStorage SearchStorage, memberStorage MemberStorage) *SearchService {
+	return &SearchService{
+		searchStorage: searchStorage,
+		access:        access{memberStorage: memberStorage},
+	}
+}
+
+// Search will return the page of the tasks and the comments of the boards the current
+// user is a member of that match the query of the provided demand, from the most relevant
+// to the least relevant, and the cursor of the next page if there is one. Returns
+// ErrSearchQuery if the query is missing or blank
+func (s *SearchService) Search(ctx context.Context, demand SearchDemand, page Page) ([]*m.SearchHit, *Cursor, error) {
+	if query, _ := demand["q"].(string); strings.TrimSpace(query) == "" {
+		return nil, nil, ErrSearchQuery
+	}
+	userID, err := s.access.userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	demand[memberConstraint] = userID
+
+	hits, err := s.searchStorage.Find(demand, page.lookAhead())
+	if err != nil || !page.hasMore(len(hits)) {
+		return hits, nil, err
+	}
+
+	hits = hits[:page.Limit]
+	last := hits[len(hits)-1]
+
+	return hits, &Cursor{ID: last.ID, Type: string(last.Type), Rank: last.Rank}, nil
+}
@@ -0,0 +1,66 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewSearchService(t *testing.T) {
+	searchStorage := new(MockedSearchStorage)
+	memberStorage := new(MockedMemberStorage)
+	searchService := NewSearchService(searchStorage, memberStorage)
+
+	assert.Equal(t, searchStorage, searchService.searchStorage)
+	assert.Equal(t, memberStorage, searchService.access.memberStorage)
+}
+
+func TestSearchService_Search(t *testing.T) {
+	hitsIn := []*m.SearchHit{
+		{Type: m.EntityTask, ID: 3, TaskID: 3, Rank: 0.6},
+		{Type: m.EntityComment, ID: 1, TaskID: 3, Rank: 0.3},
+		{Type: m.EntityTask, ID: 5, TaskID: 5, Rank: 0.3},
+	}
+
+	t.Run("members_only", func(t *testing.T) {
+		searchStorage := new(MockedSearchStorage)
+		searchStorage.On("Find", SearchDemand{"q": "release", "member": uint(1)}, Page{}).Return(hitsIn, nil)
+		searchService := &SearchService{access: ownerAccess, searchStorage: searchStorage}
+
+		hitsOut, next, err := searchService.Search(testCtx, SearchDemand{"q": "release"}, Page{})
+		assert.Nil(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, hitsIn, hitsOut)
+	})
+
+	t.Run("next_page", func(t *testing.T) {
+		searchStorage := new(MockedSearchStorage)
+		searchStorage.On("Find", mock.Anything, Page{Limit: 3}).Return(hitsIn, nil)
+		searchService := &SearchService{access: ownerAccess, searchStorage: searchStorage}
+
+		hitsOut, next, err := searchService.Search(testCtx, SearchDemand{"q": "release"}, Page{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, hitsIn[:2], hitsOut)
+		assert.Equal(t, &Cursor{ID: 1, Type: "comment", Rank: 0.3}, next)
+	})
+
+	t.Run("blank_query", func(t *testing.T) {
+		searchStorage := new(MockedSearchStorage)
+		searchService := &SearchService{access: ownerAccess, searchStorage: searchStorage}
+
+		_, _, err := searchService.Search(testCtx, SearchDemand{"q": "  "}, Page{})
+		assert.Equal(t, ErrSearchQuery, err)
+		searchStorage.AssertNotCalled(t, "Find", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		searchService := &SearchService{access: ownerAccess, searchStorage: new(MockedSearchStorage)}
+		_, _, err := searchService.Search(context.Background(), SearchDemand{"q": "release"}, Page{})
+		assert.Equal(t, ErrUnauthenticated, err)
+	})
+}
@@ -1,6 +1,11 @@
 package services
 
 import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
 	m "github.com/dnozdrin/detask/internal/domain/models"
 	v "github.com/dnozdrin/detask/internal/domain/validation"
 )
@@ -9,49 +14,543 @@ import (
 type TaskService struct {
 	validator   v.Validator
 	taskStorage TaskStorage
+	txBeginner  TxBeginner
+	access      access
+	journal     journal
 }
 
 // NewTaskService is a task service constructor
-func NewTaskService(validator v.Validator, taskStorage TaskStorage) *TaskService {
+func NewTaskService(
+	validator v.Validator,
+	taskStorage TaskStorage,
+	memberStorage MemberStorage,
+	activityStorage ActivityStorage,
+	outboxStorage OutboxStorage,
+	txBeginner TxBeginner,
+) *TaskService {
 	return &TaskService{
 		taskStorage: taskStorage,
 		validator:   validator,
+		txBeginner:  txBeginner,
+		access:      access{memberStorage: memberStorage},
+		journal:     newJournal(activityStorage, outboxStorage),
 	}
 }
 
 // Create will create a new task with the provided payload. Returns the
-// operation result with possible validation or saving errors
-func (t *TaskService) Create(task *m.Task) (*m.Task, error) {
+// operation result with possible validation or saving errors. Only board
+// editors and owners can create tasks. The author of the task becomes its
+// reporter unless another one is provided. Tasks have the medium priority
+// by default.
+func (t *TaskService) Create(ctx context.Context, task *m.Task) (*m.Task, error) {
 	if err := t.validator.Validate(*task); err != nil {
 		return nil, err
 	}
+	if err := t.access.onColumn(ctx, task.ColumnID, m.RoleEditor); err != nil {
+		return nil, relation(err, ErrColumnRelation)
+	}
+	if task.ReporterID == 0 {
+		task.ReporterID = task.CreatedBy
+	}
+	if task.Priority == "" {
+		task.Priority = m.PriorityMedium
+	}
+	if err := t.checkMembers(task); err != nil {
+		return nil, err
+	}
 
-	return t.taskStorage.Save(task)
+	return t.save(ctx, task, m.ActionCreate, TaskStorage.Save)
 }
 
-// Find will return all tasks that meet the provided demand and an
-// error in case it occurred while fetching records from the storage
-func (t *TaskService) Find(demand TaskDemand) ([]*m.Task, error) {
-	return t.taskStorage.Find(demand)
+// Find will return the page of tasks of the boards the current user is a member
+// of that meet the provided demand, the cursor of the next page if there is one,
+// and an error in case it occurred while fetching records from the storage
+func (t *TaskService) Find(ctx context.Context, demand TaskDemand, page Page) ([]*m.Task, *Cursor, error) {
+	userID, err := t.access.userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	demand[memberConstraint] = userID
+
+	tasks, err := t.taskStorage.Find(demand, page.lookAhead())
+	if err != nil || !page.hasMore(len(tasks)) {
+		return tasks, nil, err
+	}
+
+	tasks = tasks[:page.Limit]
+	last := tasks[len(tasks)-1]
+
+	return tasks, &Cursor{ID: last.ID, Position: last.Position, Priority: last.Priority, DueAt: last.DueAt}, nil
+}
+
+// FindAssigned will return the page of tasks assigned to the current user across
+// all the boards the user is a member of, that meet the provided demand
+func (t *TaskService) FindAssigned(ctx context.Context, demand TaskDemand, page Page) ([]*m.Task, *Cursor, error) {
+	userID, err := t.access.userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	demand["assignee"] = userID
+
+	return t.Find(ctx, demand, page)
 }
 
 // FindOneById will return a pointer to the task requested by id and
 // an error in case it occurred while fetching the record from the storage
-func (t *TaskService) FindOneById(ID uint) (*m.Task, error) {
+func (t *TaskService) FindOneById(ctx context.Context, ID uint) (*m.Task, error) {
+	if err := t.access.onTask(ctx, ID, m.RoleViewer); err != nil {
+		return nil, err
+	}
+
 	return t.taskStorage.FindOneById(ID)
 }
 
-// Update will update the task record. Returns the operation result
-// with possible validation or saving errors
-func (t *TaskService) Update(task *m.Task) (*m.Task, error) {
+// Update will update the task record. The task is updated only if it has the
+// version of the provided one, unless it is zero. Returns the operation result
+// with possible validation or saving errors. Only board editors and owners can
+// update tasks, a task can be moved only to a column of its board, it is moved to
+// another board by Transfer.
+// The reporter and the priority of the task are kept unless new ones are provided.
+func (t *TaskService) Update(ctx context.Context, task *m.Task) (*m.Task, error) {
 	if err := t.validator.Validate(*task); err != nil {
 		return nil, err
 	}
+	if err := t.access.onTask(ctx, task.ID, m.RoleEditor); err != nil {
+		return nil, err
+	}
+	if err := t.access.onColumn(ctx, task.ColumnID, m.RoleEditor); err != nil {
+		return nil, relation(err, ErrColumnRelation)
+	}
+	if err := t.checkMembers(task); err != nil {
+		return nil, err
+	}
+
+	return t.save(ctx, task, m.ActionUpdate, TaskStorage.Update)
+}
+
+// Patch will apply the patch to the task with the provided ID and update the task
+// with the result, which is validated as a whole. The task is patched only if it
+// has the provided version, unless it is zero, and it is updated only if it has not
+// been changed since it was read. Only board editors and owners can patch tasks
+func (t *TaskService) Patch(ctx context.Context, ID uint, patch Patch, version uint) (*m.Task, error) {
+	stored, err := t.FindOneById(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = matchVersion(stored.Version, version); err != nil {
+		return nil, err
+	}
+
+	task := new(m.Task)
+	if err = patch.apply(stored, task); err != nil {
+		return nil, err
+	}
+	task.ID, task.Version = stored.ID, stored.Version
+
+	return t.Update(ctx, task)
+}
+
+// Move will move the task to the column of its board, right before or right after
+// another task of the column, or to the end of the column. The position of the task
+// is computed between the positions of its new neighbours, all the tasks of the
+// column are rebalanced when there is no room left between them. Only board
+// editors and owners can move tasks, the WIP limit of the column is respected.
+// A task is moved to another board by Transfer.
+func (t *TaskService) Move(ctx context.Context, ID uint, move m.TaskMove) (*m.Task, error) {
+	if err := t.validator.Validate(move); err != nil {
+		return nil, err
+	}
+	if err := t.access.onTask(ctx, ID, m.RoleEditor); err != nil {
+		return nil, err
+	}
+	if err := t.access.onColumn(ctx, move.ColumnID, m.RoleEditor); err != nil {
+		return nil, relation(err, ErrColumnRelation)
+	}
+
+	tx, err := t.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	taskStorage := t.taskStorage.WithTx(tx)
+	task, err := taskStorage.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	before := *task
+	if err = checkBoard(taskStorage, task.ColumnID, move.ColumnID, true); err != nil {
+		return nil, err
+	}
+	limit, count, err := taskStorage.ColumnLoad(move.ColumnID)
+	if err != nil {
+		return nil, err
+	}
+	changesColumn := task.ColumnID != move.ColumnID
+	if changesColumn && limit > 0 && count >= limit {
+		return nil, ErrWIPLimitExceeded
+	}
+
+	siblings, err := taskStorage.Find(TaskDemand{"column": move.ColumnID}, Page{})
+	if err != nil {
+		return nil, err
+	}
+	IDs, positions := make([]uint, 0, len(siblings)), make([]float64, 0, len(siblings))
+	for _, sibling := range siblings {
+		if sibling.ID != ID {
+			IDs, positions = append(IDs, sibling.ID), append(positions, sibling.Position)
+		}
+	}
+	index, ok := moveIndex(IDs, move.BeforeID, move.AfterID)
+	if !ok {
+		return nil, ErrTaskRelation
+	}
+
+	if position, ok := positionAt(positions, index); ok {
+		task.ColumnID, task.Position = move.ColumnID, position
+		if _, err = taskStorage.Update(task); err != nil {
+			return nil, err
+		}
+	} else if err = taskStorage.Rebalance(move.ColumnID, insertID(IDs, index, ID), positionStep); err != nil {
+		return nil, err
+	}
+	if changesColumn {
+		// the labels are checked against the board of the new column
+		if err = taskStorage.SetLabels(ID, task.Labels); err != nil {
+			return nil, err
+		}
+	}
+
+	if task, err = taskStorage.FindOneById(ID); err != nil {
+		return nil, err
+	}
+	if err = t.record(ctx, tx, m.ActionMove, &before, task); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// Transfer will move the task along with its comments to the end of a column of
+// another board. The labels of the task are replaced with the labels of the same
+// names on the new board, the assignees that are not members of the new board are
+// unassigned, and the current user becomes the reporter unless the reporter is a
+// member of it. Only editors and owners of both boards can transfer tasks, the WIP
+// limit of the target column is respected. A task is moved within its board by Move.
+func (t *TaskService) Transfer(ctx context.Context, ID uint, transfer m.TaskTransfer) (*m.Task, error) {
+	if err := t.validator.Validate(transfer); err != nil {
+		return nil, err
+	}
+	if err := t.access.onTask(ctx, ID, m.RoleEditor); err != nil {
+		return nil, err
+	}
+	if err := t.access.onColumn(ctx, transfer.ColumnID, m.RoleEditor); err != nil {
+		return nil, relation(err, ErrColumnRelation)
+	}
+	userID, err := t.access.userID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := t.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	taskStorage := t.taskStorage.WithTx(tx)
+	task, err := taskStorage.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	before := *task
+	if err = checkBoard(taskStorage, task.ColumnID, transfer.ColumnID, false); err != nil {
+		return nil, err
+	}
+	memberStorage := t.access.memberStorage.WithTx(tx)
+	assignees, reporterID, err := keptPeople(memberStorage, task, transfer.ColumnID, userID)
+	if err != nil {
+		return nil, err
+	}
+	limit, count, err := taskStorage.ColumnLoad(transfer.ColumnID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ColumnID != transfer.ColumnID && limit > 0 && count >= limit {
+		return nil, ErrWIPLimitExceeded
+	}
+
+	siblings, err := taskStorage.Find(TaskDemand{"column": transfer.ColumnID}, Page{})
+	if err != nil {
+		return nil, err
+	}
+	IDs, positions := make([]uint, 0, len(siblings)), make([]float64, 0, len(siblings))
+	for _, sibling := range siblings {
+		if sibling.ID != ID {
+			IDs, positions = append(IDs, sibling.ID), append(positions, sibling.Position)
+		}
+	}
+	position, ok := positionAt(positions, len(positions))
+	if !ok {
+		if err = taskStorage.Rebalance(transfer.ColumnID, IDs, positionStep); err != nil {
+			return nil, err
+		}
+		position = positionStep * float64(len(IDs)+1)
+	}
 
-	return t.taskStorage.Update(task)
+	task.ColumnID, task.Position, task.ReporterID = transfer.ColumnID, position, reporterID
+	if _, err = taskStorage.Update(task); err != nil {
+		return nil, err
+	}
+	if err = taskStorage.SetAssignees(ID, assignees); err != nil {
+		return nil, err
+	}
+	if err = taskStorage.MatchLabels(ID); err != nil {
+		return nil, err
+	}
+
+	if task, err = taskStorage.FindOneById(ID); err != nil {
+		return nil, err
+	}
+	if err = t.record(ctx, tx, m.ActionTransfer, &before, task); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return task, nil
 }
 
-// Delete will delete a record with the given ID
-func (t *TaskService) Delete(ID uint) error {
-	return t.taskStorage.Delete(ID)
+// Delete will mark a record with the given ID as deleted along with its comments,
+// the task may be restored from the trash until it is purged. Only board editors
+// and owners can delete tasks. The task is deleted only if it has the provided
+// version, unless it is zero
+func (t *TaskService) Delete(ctx context.Context, ID, version uint) error {
+	if err := t.access.onTask(ctx, ID, m.RoleEditor); err != nil {
+		return err
+	}
+
+	tx, err := t.txBeginner.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	taskStorage := t.taskStorage.WithTx(tx)
+	task, err := taskStorage.FindOneById(ID)
+	if err != nil {
+		return err
+	}
+	if err = matchVersion(task.Version, version); err != nil {
+		return err
+	}
+	if err = taskStorage.Delete(ID); err != nil {
+		return err
+	}
+	if err = t.record(ctx, tx, m.ActionDelete, task, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// save will write the task with the provided storage method, replace its
+// assignees and labels and record the change within one transaction. A task
+// can not be added to a column that has reached its WIP limit.
+func (t *TaskService) save(
+	ctx context.Context,
+	task *m.Task,
+	action m.Action,
+	write func(TaskStorage, *m.Task) (*m.Task, error),
+) (*m.Task, error) {
+	tx, err := t.txBeginner.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	taskStorage := t.taskStorage.WithTx(tx)
+	var before *m.Task
+	if task.ID != 0 {
+		if before, err = taskStorage.FindOneById(task.ID); err != nil {
+			return nil, err
+		}
+		if err = matchVersion(before.Version, task.Version); err != nil {
+			return nil, err
+		}
+		task.Version = before.Version
+		// the task is moved to another board by Transfer, which keeps the members
+		// and the labels of the task consistent with the board
+		if before.ColumnID != task.ColumnID {
+			if err = checkBoard(taskStorage, before.ColumnID, task.ColumnID, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = checkWIPLimit(taskStorage, before, task); err != nil {
+		return nil, err
+	}
+	assignees, labels := sortedIDs(task.Assignees), sortedIDs(task.Labels)
+	task.StartAt, task.DueAt = inUTC(task.StartAt), inUTC(task.DueAt)
+	if task, err = write(taskStorage, task); err != nil {
+		return nil, versioned(err)
+	}
+	if err = taskStorage.SetAssignees(task.ID, assignees); err != nil {
+		return nil, err
+	}
+	if err = taskStorage.SetLabels(task.ID, labels); err != nil {
+		return nil, err
+	}
+	task.Assignees, task.Labels = assignees, labels
+	if err = t.record(ctx, tx, action, before, task); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// record will save the change of the task into the activity history of its board,
+// the task is missing before its creation and after its deletion
+func (t *TaskService) record(ctx context.Context, tx *sql.Tx, action m.Action, before, after *m.Task) error {
+	task := after
+	if task == nil {
+		task = before
+	}
+	entry := m.Activity{TaskID: task.ID, Entity: m.EntityTask, EntityID: task.ID, Action: action}
+
+	return t.journal.record(ctx, tx, entry, before, after)
+}
+
+// checkWIPLimit verifies that the column of the task is able to hold one more task,
+// unless the stored task, if there is one, is already in the column. It must be called
+// within the transaction that writes the task, so that the column stays locked till
+// the write.
+func checkWIPLimit(taskStorage TaskStorage, stored, task *m.Task) error {
+	if stored != nil && stored.ColumnID == task.ColumnID {
+		return nil
+	}
+
+	limit, count, err := taskStorage.ColumnLoad(task.ColumnID)
+	if err != nil {
+		return err
+	}
+	if limit > 0 && count >= limit {
+		return ErrWIPLimitExceeded
+	}
+
+	return nil
+}
+
+// sortedIDs returns a sorted copy of the provided IDs
+func sortedIDs(IDs []uint) []uint {
+	sorted := make([]uint, len(IDs))
+	copy(sorted, IDs)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return sorted
+}
+
+// inUTC returns a copy of the provided optional time in UTC, the dates of tasks
+// are stored in UTC so that the storages are able to compare them
+func inUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+
+	return &utc
+}
+
+// checkBoard verifies that the column the task is moved to is on the same board as
+// the current column of the task, or on another board if same is false. A column on
+// the wrong board is reported as a validation error pointing to the other way of
+// moving the task.
+func checkBoard(taskStorage TaskStorage, currentID, columnID uint, same bool) error {
+	currentBoardID, err := taskStorage.ColumnBoard(currentID)
+	if err != nil {
+		return err
+	}
+	boardID, err := taskStorage.ColumnBoard(columnID)
+	if err != nil {
+		return err
+	}
+	if (currentBoardID == boardID) == same {
+		return nil
+	}
+
+	errs := v.NewErrors()
+	if same {
+		errs.Add(v.Error{Field: "column", Message: "the column is on another board, transfer the task instead"})
+	} else {
+		errs.Add(v.Error{Field: "column", Message: "the column is on the board of the task, move the task instead"})
+	}
+
+	return errs
+}
+
+// keptPeople returns the assignees of the task that are members of the board of
+// the column, and the reporter of the task if it is a member of the board or the
+// provided user otherwise. The members storage has to work within the transaction
+// that moves the task, so that the members are not removed meanwhile.
+func keptPeople(memberStorage MemberStorage, task *m.Task, columnID, userID uint) (assignees []uint, reporterID uint, err error) {
+	assignees = make([]uint, 0, len(task.Assignees))
+	for _, assigneeID := range task.Assignees {
+		member, err := isMember(memberStorage, columnID, assigneeID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if member {
+			assignees = append(assignees, assigneeID)
+		}
+	}
+
+	reporterID = userID
+	if task.ReporterID != 0 {
+		member, err := isMember(memberStorage, columnID, task.ReporterID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if member {
+			reporterID = task.ReporterID
+		}
+	}
+
+	return assignees, reporterID, nil
+}
+
+// isMember reports if the user is a member of the board the column belongs to
+func isMember(memberStorage MemberStorage, columnID, userID uint) (bool, error) {
+	role, err := memberStorage.FindRoleByColumn(columnID, userID)
+	if err != nil {
+		return false, relation(err, ErrColumnRelation)
+	}
+
+	return role != "", nil
+}
+
+// checkMembers verifies that the reporter and the assignees of the task are
+// members of the board the task belongs to
+func (t *TaskService) checkMembers(task *m.Task) error {
+	users := task.Assignees
+	if task.ReporterID != 0 {
+		users = append([]uint{task.ReporterID}, users...)
+	}
+	for _, userID := range users {
+		member, err := isMember(t.access.memberStorage, task.ColumnID, userID)
+		if err != nil {
+			return err
+		}
+		if !member {
+			return ErrNotMember
+		}
+	}
+
+	return nil
 }
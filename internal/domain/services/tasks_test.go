@@ -3,9 +3,13 @@
 package services
 
 import (
+	"database/sql"
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
+	"math"
 	"testing"
+	"time"
 
 	m "github.com/dnozdrin/detask/internal/domain/models"
 	v "github.com/dnozdrin/detask/internal/domain/validation"
@@ -15,30 +19,70 @@ import (
 func TestNewTaskService(t *testing.T) {
 	taskStorage := new(MockedTaskStorage)
 	validation := new(MockedValidation)
-	taskService := NewTaskService(validation, taskStorage)
+	memberStorage := new(MockedMemberStorage)
+	activityStorage := new(MockedActivityStorage)
+	outboxStorage := new(MockedOutboxStorage)
+	txBeginner := new(MockedTxBeginner)
+	taskService := NewTaskService(validation, taskStorage, memberStorage, activityStorage, outboxStorage, txBeginner)
 
 	assert.Equal(t, validation, taskService.validator)
 	assert.Equal(t, taskStorage, taskService.taskStorage)
+	assert.Equal(t, memberStorage, taskService.access.memberStorage)
+	assert.Equal(t, activityStorage, taskService.journal.activityStorage)
+	assert.Equal(t, outboxStorage, taskService.journal.outboxStorage)
+	assert.Equal(t, txBeginner, taskService.txBeginner)
+}
+
+// txStub returns a transaction beginner that starts a stub transaction,
+// which is expected to be either committed or rolled back
+func txStub(t *testing.T, commit bool) (*MockedTxBeginner, *sql.Tx) {
+	db, dbmock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	dbmock.ExpectBegin()
+	if commit {
+		dbmock.ExpectCommit()
+	} else {
+		dbmock.ExpectRollback()
+	}
+	tx, _ := db.Begin()
+
+	txBeginner := new(MockedTxBeginner)
+	txBeginner.On("Begin").Return(tx, nil)
+
+	return txBeginner, tx
 }
 
 func TestTaskService_Create(t *testing.T) {
 	var taskIn = &m.Task{Name: "dummy"}
 	t.Run("success", func(t *testing.T) {
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, true)
 		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("ColumnLoad", mock.Anything).Return(uint(0), uint(0), nil)
 		taskStorage.On("Save", taskIn).Return(taskIn, nil)
+		taskStorage.On("SetAssignees", taskIn.ID, []uint{}).Return(nil)
+		taskStorage.On("SetLabels", taskIn.ID, []uint{}).Return(nil)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *taskIn).Return(validationErr)
 
+		history, _ := journalStub(tx)
 		taskService := &TaskService{
+			access:      ownerAccess,
 			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
 			validator:   validation,
 		}
-		taskOut, err := taskService.Create(taskIn)
+		taskOut, err := taskService.Create(testCtx, taskIn)
 
 		assert.NotNil(t, taskOut)
 		assert.Nil(t, err)
+		assert.Equal(t, m.PriorityMedium, taskOut.Priority)
 	})
 	t.Run("validation_error", func(t *testing.T) {
 		validationErr := v.NewErrors()
@@ -47,8 +91,8 @@ func TestTaskService_Create(t *testing.T) {
 		validation := new(MockedValidation)
 		validation.On("Validate", *taskIn).Return(validationErr)
 
-		taskService := &TaskService{validator: validation}
-		taskOut, err := taskService.Create(taskIn)
+		taskService := &TaskService{access: ownerAccess, validator: validation}
+		taskOut, err := taskService.Create(testCtx, taskIn)
 
 		assert.Equal(t, validationErr, err)
 		assert.Empty(t, taskOut)
@@ -56,23 +100,254 @@ func TestTaskService_Create(t *testing.T) {
 	t.Run("database_error", func(t *testing.T) {
 		dbErr := errors.New("simple error")
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, false)
 		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("ColumnLoad", mock.Anything).Return(uint(0), uint(0), nil)
 		taskStorage.On("Save", taskIn).Return(&m.Task{}, dbErr)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *taskIn).Return(validationErr)
 
+		history, _ := journalStub(tx)
 		taskService := &TaskService{
+			access:      ownerAccess,
 			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
 			validator:   validation,
 		}
-		taskOut, err := taskService.Create(taskIn)
+		taskOut, err := taskService.Create(testCtx, taskIn)
 
 		assert.Equal(t, dbErr, err)
 		assert.Empty(t, taskOut)
 	})
 }
 
+func TestTaskService_Access(t *testing.T) {
+	taskIn := &m.Task{Model: m.Model{ID: 1}, Name: "dummy", ColumnID: 2}
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", *taskIn).Return(validationErr)
+
+	t.Run("viewer_can_not_create", func(t *testing.T) {
+		taskStorage := new(MockedTaskStorage)
+		taskService := &TaskService{
+			access:      access{memberStorage: roleStorage(m.RoleViewer, nil)},
+			taskStorage: taskStorage,
+			validator:   validation,
+		}
+		_, err := taskService.Create(testCtx, taskIn)
+		assert.Equal(t, ErrForbidden, err)
+		taskStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("missing_column", func(t *testing.T) {
+		taskService := &TaskService{
+			access:    access{memberStorage: roleStorage("", ErrRecordNotFound)},
+			validator: validation,
+		}
+		_, err := taskService.Create(testCtx, taskIn)
+		assert.Equal(t, ErrColumnRelation, err)
+	})
+
+	t.Run("move_to_foreign_board", func(t *testing.T) {
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("FindRoleByTask", taskIn.ID, uint(1)).Return(m.RoleEditor, nil)
+		memberStorage.On("FindRoleByColumn", taskIn.ColumnID, uint(1)).Return(m.RoleViewer, nil)
+		taskStorage := new(MockedTaskStorage)
+		taskService := &TaskService{
+			access:      access{memberStorage: memberStorage},
+			taskStorage: taskStorage,
+			validator:   validation,
+		}
+		_, err := taskService.Update(testCtx, taskIn)
+		assert.Equal(t, ErrForbidden, err)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestTaskService_People(t *testing.T) {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+
+	t.Run("author_is_reporter", func(t *testing.T) {
+		taskIn := &m.Task{Name: "dummy", ColumnID: 2, CreatedBy: 1, Assignees: []uint{3, 1}}
+		txBeginner, tx := txStub(t, true)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("ColumnLoad", mock.Anything).Return(uint(0), uint(0), nil)
+		taskStorage.On("Save", taskIn).Return(taskIn, nil)
+		taskStorage.On("SetAssignees", taskIn.ID, []uint{1, 3}).Return(nil)
+		taskStorage.On("SetLabels", taskIn.ID, []uint{}).Return(nil)
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("FindRoleByColumn", uint(2), mock.Anything).Return(m.RoleEditor, nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      access{memberStorage: memberStorage},
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		taskOut, err := taskService.Create(testCtx, taskIn)
+		assert.Nil(t, err)
+		assert.Equal(t, uint(1), taskOut.ReporterID)
+		assert.Equal(t, []uint{1, 3}, taskOut.Assignees)
+	})
+
+	t.Run("assignee_not_member", func(t *testing.T) {
+		taskIn := &m.Task{Name: "dummy", ColumnID: 2, CreatedBy: 1, Assignees: []uint{4}}
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("FindRoleByColumn", uint(2), uint(1)).Return(m.RoleEditor, nil)
+		memberStorage.On("FindRoleByColumn", uint(2), uint(4)).Return(m.Role(""), nil)
+		taskStorage := new(MockedTaskStorage)
+
+		taskService := &TaskService{
+			access:      access{memberStorage: memberStorage},
+			taskStorage: taskStorage,
+			validator:   validation,
+		}
+		_, err := taskService.Create(testCtx, taskIn)
+		assert.Equal(t, ErrNotMember, err)
+		taskStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("find_assigned", func(t *testing.T) {
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("Find", TaskDemand{"assignee": uint(1), "member": uint(1)}, Page{}).Return([]*m.Task{}, nil)
+		taskService := &TaskService{access: ownerAccess, taskStorage: taskStorage}
+
+		_, _, err := taskService.FindAssigned(testCtx, make(TaskDemand), Page{})
+		assert.Nil(t, err)
+		taskStorage.AssertExpectations(t)
+	})
+}
+
+func TestTaskService_Labels(t *testing.T) {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+
+	t.Run("sorted", func(t *testing.T) {
+		taskIn := &m.Task{Name: "dummy", Labels: []uint{5, 2}}
+		txBeginner, tx := txStub(t, true)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("ColumnLoad", mock.Anything).Return(uint(0), uint(0), nil)
+		taskStorage.On("Save", taskIn).Return(taskIn, nil)
+		taskStorage.On("SetAssignees", taskIn.ID, []uint{}).Return(nil)
+		taskStorage.On("SetLabels", taskIn.ID, []uint{2, 5}).Return(nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		taskOut, err := taskService.Create(testCtx, taskIn)
+		assert.Nil(t, err)
+		assert.Equal(t, []uint{2, 5}, taskOut.Labels)
+	})
+
+	t.Run("foreign_label", func(t *testing.T) {
+		taskIn := &m.Task{Name: "dummy", Labels: []uint{7}}
+		txBeginner, tx := txStub(t, false)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("ColumnLoad", mock.Anything).Return(uint(0), uint(0), nil)
+		taskStorage.On("Save", taskIn).Return(taskIn, nil)
+		taskStorage.On("SetAssignees", taskIn.ID, []uint{}).Return(nil)
+		taskStorage.On("SetLabels", taskIn.ID, []uint{7}).Return(ErrLabelRelation)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Create(testCtx, taskIn)
+		assert.Equal(t, ErrLabelRelation, err)
+	})
+}
+
+func TestTaskService_WIPLimit(t *testing.T) {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+
+	t.Run("create_in_full_column", func(t *testing.T) {
+		taskIn := &m.Task{Name: "dummy", ColumnID: 2}
+		txBeginner, tx := txStub(t, false)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("ColumnLoad", uint(2)).Return(uint(3), uint(3), nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Create(testCtx, taskIn)
+		assert.Equal(t, ErrWIPLimitExceeded, err)
+		taskStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("move_to_full_column", func(t *testing.T) {
+		taskIn := &m.Task{Model: m.Model{ID: 1}, Name: "dummy", ColumnID: 2}
+		txBeginner, tx := txStub(t, false)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(1)).Return(&m.Task{Model: m.Model{ID: 1}, ColumnID: 3}, nil)
+		taskStorage.On("ColumnBoard", mock.Anything).Return(uint(1), nil)
+		taskStorage.On("ColumnLoad", uint(2)).Return(uint(1), uint(1), nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Update(testCtx, taskIn)
+		assert.Equal(t, ErrWIPLimitExceeded, err)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("update_within_full_column", func(t *testing.T) {
+		taskIn := &m.Task{Model: m.Model{ID: 1}, Name: "dummy", ColumnID: 2}
+		txBeginner, tx := txStub(t, true)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(1)).Return(&m.Task{Model: m.Model{ID: 1}, ColumnID: 2}, nil)
+		taskStorage.On("Update", taskIn).Return(taskIn, nil)
+		taskStorage.On("SetAssignees", taskIn.ID, []uint{}).Return(nil)
+		taskStorage.On("SetLabels", taskIn.ID, []uint{}).Return(nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Update(testCtx, taskIn)
+		assert.Nil(t, err)
+		taskStorage.AssertNotCalled(t, "ColumnLoad", mock.Anything)
+	})
+}
+
 func TestTaskService_FindOneById(t *testing.T) {
 	const dummyID = 1234
 	taskIn := &m.Task{Model: m.Model{ID: dummyID}}
@@ -80,8 +355,8 @@ func TestTaskService_FindOneById(t *testing.T) {
 	t.Run("found", func(t *testing.T) {
 		taskStorage := new(MockedTaskStorage)
 		taskStorage.On("FindOneById", mock.Anything).Return(taskIn, nil)
-		taskService := &TaskService{taskStorage: taskStorage}
-		taskOut, err := taskService.FindOneById(dummyID)
+		taskService := &TaskService{access: ownerAccess, taskStorage: taskStorage}
+		taskOut, err := taskService.FindOneById(testCtx, dummyID)
 		assert.Nil(t, err)
 		assert.Equal(t, taskIn, taskOut)
 	})
@@ -89,8 +364,8 @@ func TestTaskService_FindOneById(t *testing.T) {
 	t.Run("not_found", func(t *testing.T) {
 		taskStorage := new(MockedTaskStorage)
 		taskStorage.On("FindOneById", mock.Anything).Return(taskIn, errors.New(""))
-		taskService := &TaskService{taskStorage: taskStorage}
-		taskOut, err := taskService.FindOneById(dummyID)
+		taskService := &TaskService{access: ownerAccess, taskStorage: taskStorage}
+		taskOut, err := taskService.FindOneById(testCtx, dummyID)
 		assert.Error(t, err)
 		assert.Equal(t, taskIn, taskOut)
 	})
@@ -103,18 +378,33 @@ func TestTaskService_Find(t *testing.T) {
 			{Name: "Test2"},
 		}
 		taskStorage := new(MockedTaskStorage)
-		taskStorage.On("Find", mock.Anything).Return(tasksIn, nil)
-		taskService := &TaskService{taskStorage: taskStorage}
-		tasksOut, err := taskService.Find(make(TaskDemand))
+		taskStorage.On("Find", mock.Anything, mock.Anything).Return(tasksIn, nil)
+		taskService := &TaskService{access: ownerAccess, taskStorage: taskStorage}
+		tasksOut, _, err := taskService.Find(testCtx, make(TaskDemand), Page{})
 		assert.Nil(t, err)
 		assert.Equal(t, tasksIn, tasksOut)
 	})
 
+	t.Run("next_page", func(t *testing.T) {
+		due := time.Now()
+		tasksIn := []*m.Task{
+			{Model: m.Model{ID: 1}, Position: 2, Priority: m.PriorityLow, DueAt: &due},
+			{Model: m.Model{ID: 2}, Position: 1, Priority: m.PriorityHigh},
+		}
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("Find", mock.Anything, Page{Limit: 2}).Return(tasksIn, nil)
+		taskService := &TaskService{access: ownerAccess, taskStorage: taskStorage}
+		tasksOut, next, err := taskService.Find(testCtx, make(TaskDemand), Page{Limit: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, tasksIn[:1], tasksOut)
+		assert.Equal(t, &Cursor{ID: 1, Position: 2, Priority: m.PriorityLow, DueAt: &due}, next)
+	})
+
 	t.Run("not_found", func(t *testing.T) {
 		taskStorage := new(MockedTaskStorage)
-		taskStorage.On("Find", mock.Anything).Return([]*m.Task{}, errors.New(""))
-		taskService := &TaskService{taskStorage: taskStorage}
-		taskOut, err := taskService.Find(make(TaskDemand))
+		taskStorage.On("Find", mock.Anything, mock.Anything).Return([]*m.Task{}, errors.New(""))
+		taskService := &TaskService{access: ownerAccess, taskStorage: taskStorage}
+		taskOut, _, err := taskService.Find(testCtx, make(TaskDemand), Page{})
 		assert.Error(t, err)
 		assert.Empty(t, taskOut)
 	})
@@ -125,17 +415,26 @@ func TestTaskService_Update(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, true)
 		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("ColumnLoad", mock.Anything).Return(uint(0), uint(0), nil)
 		taskStorage.On("Update", taskIn).Return(taskIn, nil)
+		taskStorage.On("SetAssignees", taskIn.ID, []uint{}).Return(nil)
+		taskStorage.On("SetLabels", taskIn.ID, []uint{}).Return(nil)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *taskIn).Return(validationErr)
 
+		history, _ := journalStub(tx)
 		taskService := &TaskService{
+			access:      ownerAccess,
 			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
 			validator:   validation,
 		}
-		taskOut, err := taskService.Update(taskIn)
+		taskOut, err := taskService.Update(testCtx, taskIn)
 
 		assert.NotNil(t, taskOut)
 		assert.Nil(t, err)
@@ -148,8 +447,8 @@ func TestTaskService_Update(t *testing.T) {
 		validation := new(MockedValidation)
 		validation.On("Validate", *taskIn).Return(validationErr)
 
-		taskService := &TaskService{validator: validation}
-		taskOut, err := taskService.Update(taskIn)
+		taskService := &TaskService{access: ownerAccess, validator: validation}
+		taskOut, err := taskService.Update(testCtx, taskIn)
 
 		assert.Equal(t, validationErr, err)
 		assert.Empty(t, taskOut)
@@ -158,39 +457,346 @@ func TestTaskService_Update(t *testing.T) {
 	t.Run("database_error", func(t *testing.T) {
 		var validationErr *v.Errors
 		dbErr := errors.New("simple error")
+		txBeginner, tx := txStub(t, false)
 		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("ColumnLoad", mock.Anything).Return(uint(0), uint(0), nil)
 		taskStorage.On("Update", taskIn).Return(&m.Task{}, dbErr)
 
 		validation := new(MockedValidation)
 		validation.On("Validate", *taskIn).Return(validationErr)
 
+		history, _ := journalStub(tx)
 		taskService := &TaskService{
+			access:      ownerAccess,
 			validator:   validation,
 			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
 		}
 
-		taskOut, err := taskService.Update(taskIn)
+		taskOut, err := taskService.Update(testCtx, taskIn)
 
 		assert.Equal(t, dbErr, err)
 		assert.Empty(t, taskOut)
 	})
+
+	t.Run("version_mismatch", func(t *testing.T) {
+		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, false)
+		taskIn := &m.Task{Model: m.Model{ID: 5, Version: 2}, Name: "dummy"}
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(5)).Return(&m.Task{Model: m.Model{ID: 5, Version: 3}, Name: "old"}, nil)
+
+		validation := new(MockedValidation)
+		validation.On("Validate", *taskIn).Return(validationErr)
+
+		taskService := &TaskService{access: ownerAccess, validator: validation, taskStorage: taskStorage, txBeginner: txBeginner}
+		_, err := taskService.Update(testCtx, taskIn)
+
+		assert.Equal(t, ErrVersionMismatch, err)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("to_another_board", func(t *testing.T) {
+		var validationErr *v.Errors
+		txBeginner, tx := txStub(t, false)
+		taskIn := &m.Task{Model: m.Model{ID: 5}, Name: "dummy", ColumnID: 9}
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(5)).Return(&m.Task{Model: m.Model{ID: 5, Version: 1}, ColumnID: 3}, nil)
+		taskStorage.On("ColumnBoard", uint(3)).Return(uint(1), nil)
+		taskStorage.On("ColumnBoard", uint(9)).Return(uint(2), nil)
+
+		validation := new(MockedValidation)
+		validation.On("Validate", *taskIn).Return(validationErr)
+
+		taskService := &TaskService{access: ownerAccess, validator: validation, taskStorage: taskStorage, txBeginner: txBeginner}
+		_, err := taskService.Update(testCtx, taskIn)
+
+		assert.IsType(t, new(v.Errors), err)
+		taskStorage.AssertNotCalled(t, "ColumnLoad", mock.Anything)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
 }
 
 func TestTaskService_Delete(t *testing.T) {
 	t.Run("successful_delete", func(t *testing.T) {
+		txBeginner, tx := txStub(t, true)
 		taskStorage := new(MockedTaskStorage)
-		taskStorage.On("Delete", mock.Anything).Return(nil)
-		taskService := &TaskService{taskStorage: taskStorage}
-		err := taskService.Delete(0)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(5)).Return(&m.Task{Model: m.Model{ID: 5}, Name: "dummy"}, nil)
+		taskStorage.On("Delete", uint(5)).Return(nil)
+		history, activityStorage := journalStub(tx)
+		taskService := &TaskService{access: ownerAccess, taskStorage: taskStorage, journal: history, txBeginner: txBeginner}
+		err := taskService.Delete(testCtx, 5, 0)
 		assert.Nil(t, err)
+
+		entry := recorded(activityStorage, 0)
+		assert.Equal(t, uint(5), entry.TaskID)
+		assert.Equal(t, m.EntityTask, entry.Entity)
+		assert.Equal(t, m.ActionDelete, entry.Action)
+		assert.Equal(t, "dummy", entry.Changes["name"].From)
 	})
 
 	t.Run("database_error", func(t *testing.T) {
 		errorIn := errors.New("test")
+		txBeginner, tx := txStub(t, false)
 		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", mock.Anything).Return(&m.Task{}, nil)
 		taskStorage.On("Delete", mock.Anything).Return(errorIn)
-		taskService := &TaskService{taskStorage: taskStorage}
-		err := taskService.Delete(0)
+		taskService := &TaskService{access: ownerAccess, taskStorage: taskStorage, txBeginner: txBeginner}
+		err := taskService.Delete(testCtx, 0, 0)
 		assert.Equal(t, errorIn, err)
 	})
 }
+
+func TestTaskService_Move(t *testing.T) {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+	column := []*m.Task{
+		{Model: m.Model{ID: 1}, ColumnID: 2, Position: 1000},
+		{Model: m.Model{ID: 2}, ColumnID: 2, Position: 2000},
+		{Model: m.Model{ID: 3}, ColumnID: 2, Position: 3000},
+	}
+
+	tests := []struct {
+		name     string
+		move     m.TaskMove
+		position float64
+	}{
+		{"to_the_end", m.TaskMove{ColumnID: 2}, 4000},
+		{"before_the_first", m.TaskMove{ColumnID: 2, BeforeID: 1}, 500},
+		{"before_the_second", m.TaskMove{ColumnID: 2, BeforeID: 2}, 1500},
+		{"after_the_second", m.TaskMove{ColumnID: 2, AfterID: 2}, 2500},
+		{"after_the_last", m.TaskMove{ColumnID: 2, AfterID: 3}, 4000},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stored := &m.Task{Model: m.Model{ID: 4}, ColumnID: 2, Position: 5000}
+			txBeginner, tx := txStub(t, true)
+			taskStorage := new(MockedTaskStorage)
+			taskStorage.On("WithTx", tx).Return(taskStorage)
+			taskStorage.On("FindOneById", uint(4)).Return(stored, nil)
+			taskStorage.On("ColumnBoard", mock.Anything).Return(uint(1), nil)
+			taskStorage.On("ColumnLoad", uint(2)).Return(uint(4), uint(4), nil)
+			taskStorage.On("Find", TaskDemand{"column": uint(2)}, Page{}).
+				Return(append(column, stored), nil)
+			taskStorage.On("Update", mock.MatchedBy(func(task *m.Task) bool {
+				return task.ColumnID == 2 && task.Position == test.position
+			})).Return(stored, nil)
+
+			history, activityStorage := journalStub(tx)
+			taskService := &TaskService{
+				access:      ownerAccess,
+				taskStorage: taskStorage,
+				txBeginner:  txBeginner,
+				journal:     history,
+				validator:   validation,
+			}
+			taskOut, err := taskService.Move(testCtx, 4, test.move)
+			assert.Nil(t, err)
+			assert.Equal(t, stored, taskOut)
+			assert.Equal(t, m.ActionMove, recorded(activityStorage, 0).Action)
+			taskStorage.AssertNotCalled(t, "Rebalance", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("rebalance", func(t *testing.T) {
+		crowded := []*m.Task{
+			{Model: m.Model{ID: 1}, ColumnID: 2, Position: 1},
+			{Model: m.Model{ID: 2}, ColumnID: 2, Position: math.Nextafter(1, 2)},
+		}
+		stored := &m.Task{Model: m.Model{ID: 4}, ColumnID: 3, Labels: []uint{7}}
+		txBeginner, tx := txStub(t, true)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(4)).Return(stored, nil)
+		taskStorage.On("ColumnBoard", mock.Anything).Return(uint(1), nil)
+		taskStorage.On("ColumnLoad", uint(2)).Return(uint(0), uint(2), nil)
+		taskStorage.On("Find", TaskDemand{"column": uint(2)}, Page{}).Return(crowded, nil)
+		taskStorage.On("Rebalance", uint(2), []uint{1, 4, 2}, float64(positionStep)).Return(nil)
+		taskStorage.On("SetLabels", uint(4), []uint{7}).Return(nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Move(testCtx, 4, m.TaskMove{ColumnID: 2, AfterID: 1})
+		assert.Nil(t, err)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("foreign_task", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(4)).Return(&m.Task{Model: m.Model{ID: 4}, ColumnID: 2}, nil)
+		taskStorage.On("ColumnBoard", mock.Anything).Return(uint(1), nil)
+		taskStorage.On("ColumnLoad", uint(2)).Return(uint(0), uint(3), nil)
+		taskStorage.On("Find", TaskDemand{"column": uint(2)}, Page{}).Return(column, nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Move(testCtx, 4, m.TaskMove{ColumnID: 2, BeforeID: 9})
+		assert.Equal(t, ErrTaskRelation, err)
+	})
+
+	t.Run("to_full_column", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(4)).Return(&m.Task{Model: m.Model{ID: 4}, ColumnID: 3}, nil)
+		taskStorage.On("ColumnBoard", mock.Anything).Return(uint(1), nil)
+		taskStorage.On("ColumnLoad", uint(2)).Return(uint(3), uint(3), nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Move(testCtx, 4, m.TaskMove{ColumnID: 2})
+		assert.Equal(t, ErrWIPLimitExceeded, err)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("to_another_board", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(4)).Return(&m.Task{Model: m.Model{ID: 4}, ColumnID: 3}, nil)
+		taskStorage.On("ColumnBoard", uint(3)).Return(uint(1), nil)
+		taskStorage.On("ColumnBoard", uint(9)).Return(uint(2), nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Move(testCtx, 4, m.TaskMove{ColumnID: 9})
+		assert.IsType(t, new(v.Errors), err)
+		taskStorage.AssertNotCalled(t, "ColumnLoad", mock.Anything)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestTaskService_Transfer(t *testing.T) {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+
+	t.Run("success", func(t *testing.T) {
+		stored := &m.Task{Model: m.Model{ID: 4}, ColumnID: 2, Position: 1000, ReporterID: 5, Assignees: []uint{1, 3, 5}}
+		target := []*m.Task{{Model: m.Model{ID: 7}, ColumnID: 9, Position: 3000}}
+		txBeginner, tx := txStub(t, true)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(4)).Return(stored, nil)
+		taskStorage.On("ColumnBoard", uint(2)).Return(uint(1), nil)
+		taskStorage.On("ColumnBoard", uint(9)).Return(uint(2), nil)
+		taskStorage.On("ColumnLoad", uint(9)).Return(uint(0), uint(1), nil)
+		taskStorage.On("Find", TaskDemand{"column": uint(9)}, Page{}).Return(target, nil)
+		taskStorage.On("Update", mock.MatchedBy(func(task *m.Task) bool {
+			return task.ColumnID == 9 && task.Position == 4000 && task.ReporterID == 1
+		})).Return(stored, nil)
+		taskStorage.On("SetAssignees", uint(4), []uint{1, 3}).Return(nil)
+		taskStorage.On("MatchLabels", uint(4)).Return(nil)
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+		memberStorage.On("FindRoleByTask", uint(4), uint(1)).Return(m.RoleEditor, nil)
+		memberStorage.On("FindRoleByColumn", uint(9), uint(1)).Return(m.RoleEditor, nil)
+		memberStorage.On("FindRoleByColumn", uint(9), uint(3)).Return(m.RoleViewer, nil)
+		memberStorage.On("FindRoleByColumn", uint(9), uint(5)).Return(m.Role(""), nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      access{memberStorage: memberStorage},
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Transfer(testCtx, 4, m.TaskTransfer{ColumnID: 9})
+		assert.Nil(t, err)
+		taskStorage.AssertExpectations(t)
+	})
+
+	t.Run("target_board_forbidden", func(t *testing.T) {
+		memberStorage := new(MockedMemberStorage)
+		memberStorage.On("FindRoleByTask", uint(4), uint(1)).Return(m.RoleOwner, nil)
+		memberStorage.On("FindRoleByColumn", uint(9), uint(1)).Return(m.RoleViewer, nil)
+		taskStorage := new(MockedTaskStorage)
+
+		taskService := &TaskService{
+			access:      access{memberStorage: memberStorage},
+			taskStorage: taskStorage,
+			validator:   validation,
+		}
+		_, err := taskService.Transfer(testCtx, 4, m.TaskTransfer{ColumnID: 9})
+		assert.Equal(t, ErrForbidden, err)
+		taskStorage.AssertNotCalled(t, "WithTx", mock.Anything)
+	})
+
+	t.Run("to_full_column", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(4)).Return(&m.Task{Model: m.Model{ID: 4}, ColumnID: 2}, nil)
+		taskStorage.On("ColumnBoard", uint(2)).Return(uint(1), nil)
+		taskStorage.On("ColumnBoard", uint(9)).Return(uint(2), nil)
+		taskStorage.On("ColumnLoad", uint(9)).Return(uint(2), uint(2), nil)
+		memberStorage := roleStorage(m.RoleOwner, nil)
+		memberStorage.On("WithTx", tx).Return(memberStorage)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      access{memberStorage: memberStorage},
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Transfer(testCtx, 4, m.TaskTransfer{ColumnID: 9})
+		assert.Equal(t, ErrWIPLimitExceeded, err)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("to_the_same_board", func(t *testing.T) {
+		txBeginner, tx := txStub(t, false)
+		taskStorage := new(MockedTaskStorage)
+		taskStorage.On("WithTx", tx).Return(taskStorage)
+		taskStorage.On("FindOneById", uint(4)).Return(&m.Task{Model: m.Model{ID: 4}, ColumnID: 2}, nil)
+		taskStorage.On("ColumnBoard", mock.Anything).Return(uint(1), nil)
+
+		history, _ := journalStub(tx)
+		taskService := &TaskService{
+			access:      ownerAccess,
+			taskStorage: taskStorage,
+			txBeginner:  txBeginner,
+			journal:     history,
+			validator:   validation,
+		}
+		_, err := taskService.Transfer(testCtx, 4, m.TaskTransfer{ColumnID: 3})
+		assert.IsType(t, new(v.Errors), err)
+		taskStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
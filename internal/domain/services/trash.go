@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+)
+
+// TrashService is an interactor for work with the deleted records
+type TrashService struct {
+	trashStorage TrashStorage
+	txBeginner   TxBeginner
+	access       access
+	journal      journal
+}
+
+// NewTrashService is a trash service constructor
+func NewTrashService(
+	trashStorage TrashStorage,
+	memberStorage MemberStorage,
+	activityStorage ActivityStorage,
+	outboxStorage OutboxStorage,
+	txBeginner TxBeginner,
+) *TrashService {
+	return &TrashService{
+		trashStorage: trashStorage,
+		txBeginner:   txBeginner,
+		access:       access{memberStorage: memberStorage},
+		journal:      newJournal(activityStorage, outboxStorage),
+	}
+}
+
+// Find will return the page of the deleted records of the boards the current user
+// is a member of that meet the provided demand, the cursor of the next page if there
+// is one, and an error in case it occurred while fetching records from the storage
+func (t *TrashService) Find(ctx context.Context, demand TrashDemand, page Page) ([]*m.TrashItem, *Cursor, error) {
+	userID, err := t.access.userID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	demand[memberConstraint] = userID
+
+	items, err := t.trashStorage.Find(demand, page.lookAhead())
+	if err != nil || !page.hasMore(len(items)) {
+		return items, nil, err
+	}
+
+	items = items[:page.Limit]
+	last := items[len(items)-1]
+	deletedAt := last.DeletedAt
+
+	return items, &Cursor{ID: last.ID, Type: string(last.Type), DeletedAt: &deletedAt}, nil
+}
+
+// Restore will restore the deleted record of the provided type with the provided ID
+// along with the records deleted with it. The parent of the record must not be deleted.
+// Only board owners can restore boards and columns, tasks and comments may be restored
+// by board editors as well
+func (t *TrashService) Restore(ctx context.Context, kind m.TrashType, ID uint) error {
+	item, err := t.trashStorage.FindOne(kind, ID)
+	if err != nil {
+		return err
+	}
+	required := m.RoleEditor
+	if kind == m.TrashBoard || kind == m.TrashColumn {
+		required = m.RoleOwner
+	}
+	if err = t.access.onDeletedBoard(ctx, item.BoardID, required); err != nil {
+		return err
+	}
+
+	tx, err := t.txBeginner.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = t.trashStorage.WithTx(tx).Restore(kind, ID, positionStep); err != nil {
+		return err
+	}
+
+	entry := m.Activity{BoardID: item.BoardID, Entity: m.Entity(kind), EntityID: ID, Action: m.ActionRestore}
+	switch kind {
+	case m.TrashTask:
+		entry.TaskID = ID
+	case m.TrashComment:
+		entry.TaskID = item.ParentID
+	}
+	before, after := map[string]interface{}{"deleted_at": item.DeletedAt}, map[string]interface{}{"deleted_at": nil}
+	if err = t.journal.record(ctx, tx, entry, before, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Purge will permanently delete the records that have been deleted longer than
+// the provided retention period ago
+func (t *TrashService) Purge(retention time.Duration) error {
+	return t.trashStorage.Purge(time.Now().Add(-retention))
+}
@@ -0,0 +1,134 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewTrashService(t *testing.T) {
+	trashStorage := new(MockedTrashStorage)
+	memberStorage := new(MockedMemberStorage)
+	activityStorage := new(MockedActivityStorage)
+	outboxStorage := new(MockedOutboxStorage)
+	txBeginner := new(MockedTxBeginner)
+	trashService := NewTrashService(trashStorage, memberStorage, activityStorage, outboxStorage, txBeginner)
+
+	assert.Equal(t, trashStorage, trashService.trashStorage)
+	assert.Equal(t, memberStorage, trashService.access.memberStorage)
+	assert.Equal(t, activityStorage, trashService.journal.activityStorage)
+	assert.Equal(t, outboxStorage, trashService.journal.outboxStorage)
+	assert.Equal(t, txBeginner, trashService.txBeginner)
+}
+
+func TestTrashService_Find(t *testing.T) {
+	deletedAt := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	itemsIn := []*m.TrashItem{
+		{Type: m.TrashTask, ID: 3, DeletedAt: deletedAt},
+		{Type: m.TrashBoard, ID: 1, DeletedAt: deletedAt},
+		{Type: m.TrashComment, ID: 7, DeletedAt: deletedAt},
+	}
+
+	t.Run("members_only", func(t *testing.T) {
+		trashStorage := new(MockedTrashStorage)
+		trashStorage.On("Find", TrashDemand{"member": uint(1)}, Page{}).Return(itemsIn, nil)
+		trashService := &TrashService{access: ownerAccess, trashStorage: trashStorage}
+
+		itemsOut, next, err := trashService.Find(testCtx, make(TrashDemand), Page{})
+		assert.Nil(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, itemsIn, itemsOut)
+	})
+
+	t.Run("next_page", func(t *testing.T) {
+		trashStorage := new(MockedTrashStorage)
+		trashStorage.On("Find", mock.Anything, Page{Limit: 3}).Return(itemsIn, nil)
+		trashService := &TrashService{access: ownerAccess, trashStorage: trashStorage}
+
+		itemsOut, next, err := trashService.Find(testCtx, make(TrashDemand), Page{Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, itemsIn[:2], itemsOut)
+		assert.Equal(t, &Cursor{ID: 1, Type: "board", DeletedAt: &deletedAt}, next)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		trashService := &TrashService{access: ownerAccess, trashStorage: new(MockedTrashStorage)}
+		_, _, err := trashService.Find(context.Background(), make(TrashDemand), Page{})
+		assert.Equal(t, ErrUnauthenticated, err)
+	})
+}
+
+func TestTrashService_Restore(t *testing.T) {
+	dbErr := errors.New("dummy")
+
+	tests := []struct {
+		name       string
+		kind       m.TrashType
+		role       m.Role
+		findErr    error
+		restoreErr error
+		err        error
+	}{
+		{"owner_restores_board", m.TrashBoard, m.RoleOwner, nil, nil, nil},
+		{"editor_can_not_restore_board", m.TrashBoard, m.RoleEditor, nil, nil, ErrForbidden},
+		{"editor_can_not_restore_column", m.TrashColumn, m.RoleEditor, nil, nil, ErrForbidden},
+		{"editor_restores_task", m.TrashTask, m.RoleEditor, nil, nil, nil},
+		{"viewer_can_not_restore_comment", m.TrashComment, m.RoleViewer, nil, nil, ErrForbidden},
+		{"not_a_member", m.TrashTask, "", nil, nil, ErrForbidden},
+		{"not_found", m.TrashTask, m.RoleOwner, ErrRecordNotFound, nil, ErrRecordNotFound},
+		{"trashed_parent", m.TrashTask, m.RoleOwner, nil, ErrTrashedParent, ErrTrashedParent},
+		{"storage_error", m.TrashColumn, m.RoleOwner, nil, dbErr, dbErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txBeginner, tx := txStub(t, tt.restoreErr == nil)
+
+			trashStorage := new(MockedTrashStorage)
+			trashStorage.On("FindOne", tt.kind, uint(5)).Return(&m.TrashItem{Type: tt.kind, ID: 5, BoardID: 2}, tt.findErr)
+			trashStorage.On("WithTx", tx).Return(trashStorage)
+			trashStorage.On("Restore", tt.kind, uint(5), float64(positionStep)).Return(tt.restoreErr)
+
+			memberStorage := new(MockedMemberStorage)
+			memberStorage.On("Find", uint(2)).Return([]*m.Member{
+				{BoardID: 2, UserID: 7, Role: m.RoleOwner},
+				{BoardID: 2, UserID: 1, Role: tt.role},
+			}, nil)
+
+			history, activityStorage := journalStub(tx)
+			trashService := &TrashService{
+				access:       access{memberStorage: memberStorage},
+				trashStorage: trashStorage,
+				txBeginner:   txBeginner,
+				journal:      history,
+			}
+			assert.Equal(t, tt.err, trashService.Restore(testCtx, tt.kind, 5))
+			if tt.findErr != nil || tt.err == ErrForbidden {
+				trashStorage.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.err == nil {
+				entry := recorded(activityStorage, 0)
+				assert.Equal(t, m.ActionRestore, entry.Action)
+				assert.Equal(t, m.Entity(tt.kind), entry.Entity)
+				assert.Equal(t, uint(5), entry.EntityID)
+			}
+		})
+	}
+}
+
+func TestTrashService_Purge(t *testing.T) {
+	trashStorage := new(MockedTrashStorage)
+	trashStorage.On("Purge", mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) >= time.Hour && time.Since(before) < time.Hour+time.Minute
+	})).Return(nil)
+	trashService := &TrashService{trashStorage: trashStorage}
+
+	assert.Nil(t, trashService.Purge(time.Hour))
+	trashStorage.AssertExpectations(t)
+}
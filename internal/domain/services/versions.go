@@ -0,0 +1,22 @@
+package services
+
+// matchVersion returns ErrVersionMismatch if the record has another version than
+// the required one, any version matches the zero version
+func matchVersion(current, required uint) error {
+	if required != 0 && required != current {
+		return ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// versioned maps the error of an update of the record, that has been found within
+// the same transaction, to ErrVersionMismatch when the record is missing, as only
+// a change of its version may have happened since then
+func versioned(err error) error {
+	if err == ErrRecordNotFound {
+		return ErrVersionMismatch
+	}
+
+	return err
+}
@@ -0,0 +1,368 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+)
+
+const (
+	// secretSize is the number of the random bytes of a generated webhook secret
+	secretSize = 32
+	// deliveryBatch is the number of the deliveries claimed at once
+	deliveryBatch = 10
+	// deliveryLease is the time a claimed delivery is kept from the other workers,
+	// it must be longer than a delivery attempt takes
+	deliveryLease = time.Minute
+	// retryBackoff is the delay of the first retry of a failed delivery, the delay is
+	// doubled with every next attempt up to the maximal backoff
+	retryBackoff = 30 * time.Second
+	// maxRetryBackoff is the longest delay between the attempts of a delivery
+	maxRetryBackoff = time.Hour
+	// maxDeliveryAttempts is the number of the attempts a delivery fails after
+	maxDeliveryAttempts = 10
+)
+
+// errWebhookDisabled is the error of the deliveries to the disabled webhooks
+const errWebhookDisabled = "the webhook is disabled"
+
+// WebhookService is an interactor for work with webhooks and their deliveries. Only
+// board owners can manage the webhooks of the board
+type WebhookService struct {
+	validator       v.Validator
+	webhookStorage  WebhookStorage
+	deliveryStorage DeliveryStorage
+	sender          WebhookSender
+	access          access
+	backoff         time.Duration
+	maxBackoff      time.Duration
+	maxAttempts     uint
+}
+
+// NewWebhookService is a webhook service constructor
+func NewWebhookService(
+	validator v.Validator,
+	webhookStorage WebhookStorage,
+	deliveryStorage DeliveryStorage,
+	memberStorage MemberStorage,
+	sender WebhookSender,
+) *WebhookService {
+	return &WebhookService{
+		validator:       validator,
+		webhookStorage:  webhookStorage,
+		deliveryStorage: deliveryStorage,
+		sender:          sender,
+		access:          access{memberStorage: memberStorage},
+		backoff:         retryBackoff,
+		maxBackoff:      maxRetryBackoff,
+		maxAttempts:     maxDeliveryAttempts,
+	}
+}
+
+// Create will add a new webhook to the board and generate its secret unless it is
+// provided. Returns the created webhook along with its secret or possible validation
+// or saving errors
+func (s *WebhookService) Create(ctx context.Context, webhook *m.Webhook) (*m.Webhook, error) {
+	if err := s.validate(webhook); err != nil {
+		return nil, err
+	}
+	if err := s.access.onBoard(ctx, webhook.BoardID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		secret := make([]byte, secretSize)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+
+	return s.webhookStorage.Save(webhook)
+}
+
+// FindByBoard will return the page of the webhooks of the board with the provided ID
+// and the cursor of the next page if there is one, the secrets are not returned
+func (s *WebhookService) FindByBoard(ctx context.Context, boardID uint, page Page) ([]*m.Webhook, *Cursor, error) {
+	if err := s.access.onBoard(ctx, boardID, m.RoleOwner); err != nil {
+		return nil, nil, err
+	}
+
+	webhooks, err := s.webhookStorage.Find(boardID, page.lookAhead())
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	if !page.hasMore(len(webhooks)) {
+		return webhooks, nil, nil
+	}
+
+	webhooks = webhooks[:page.Limit]
+
+	return webhooks, &Cursor{ID: webhooks[len(webhooks)-1].ID}, nil
+}
+
+// FindOneById will return a pointer to the webhook requested by id without its
+// secret and an error in case it occurred while fetching the record from the storage
+func (s *WebhookService) FindOneById(ctx context.Context, ID uint) (*m.Webhook, error) {
+	webhook, err := s.findOne(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+// Update will update the URL, the event types and the disabled flag of the webhook,
+// the secret is replaced only if a new one is provided. Returns the updated webhook
+// without its secret or possible validation or saving errors
+func (s *WebhookService) Update(ctx context.Context, webhook *m.Webhook) (*m.Webhook, error) {
+	if err := s.validate(webhook); err != nil {
+		return nil, err
+	}
+	stored, err := s.findOne(ctx, webhook.ID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = stored.Secret
+	}
+
+	if webhook, err = s.webhookStorage.Update(webhook); err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+
+	return webhook, nil
+}
+
+// Delete will delete the webhook with the provided ID along with its deliveries
+func (s *WebhookService) Delete(ctx context.Context, ID uint) error {
+	if _, err := s.findOne(ctx, ID); err != nil {
+		return err
+	}
+
+	return s.webhookStorage.Delete(ID)
+}
+
+// FindDeliveries will return the page of the deliveries of the webhook with the provided
+// ID from the newest to the oldest and the cursor of the next page if there is one
+func (s *WebhookService) FindDeliveries(ctx context.Context, webhookID uint, page Page) ([]*m.Delivery, *Cursor, error) {
+	if _, err := s.findOne(ctx, webhookID); err != nil {
+		return nil, nil, err
+	}
+
+	deliveries, err := s.deliveryStorage.Find(webhookID, page.lookAhead())
+	if err != nil || !page.hasMore(len(deliveries)) {
+		return deliveries, nil, err
+	}
+
+	deliveries = deliveries[:page.Limit]
+
+	return deliveries, &Cursor{ID: deliveries[len(deliveries)-1].ID}, nil
+}
+
+// Redeliver will queue a new delivery of the payload of the delivery with the provided
+// ID to its webhook, the new delivery is attempted as soon as possible
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID, deliveryID uint) (*m.Delivery, error) {
+	if _, err := s.findOne(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.deliveryStorage.FindOneById(deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.WebhookID != webhookID {
+		return nil, ErrRecordNotFound
+	}
+
+	return s.deliveryStorage.Save(pending(webhookID, delivery.EventType, delivery.Payload))
+}
+
+// Notify will queue the deliveries of the event to the enabled webhooks of its board
+// subscribed to its type. The events are relayed after their changes are committed,
+// so the webhooks created after the change are skipped. A relayed event may be passed
+// again, so the webhooks that already have a delivery of it are skipped as well
+func (s *WebhookService) Notify(event m.Event) error {
+	webhooks, err := s.webhookStorage.Find(event.BoardID, Page{})
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, webhook := range webhooks {
+		if webhook.Disabled || !webhook.Events.Contains(event.Type) || webhook.CreatedAt.After(event.CreatedAt) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+		delivery := pending(webhook.ID, event.Type, payload)
+		delivery.EventID = event.ID
+		if _, err = s.deliveryStorage.Save(delivery); err != nil && err != ErrRecordAlreadyExist {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Dispatch will queue the delivery of the event to the webhook of its board with the
+// provided ID regardless of the event types the webhook is subscribed to. Only board
+// owners can dispatch the events to the webhooks
+func (s *WebhookService) Dispatch(ctx context.Context, webhookID uint, event m.Event) error {
+	webhook, err := s.findOne(ctx, webhookID)
+	if err == ErrRecordNotFound || err == nil && webhook.BoardID != event.BoardID {
+		return ErrWebhookRelation
+	}
+	if err != nil {
+		return err
+	}
+	if webhook.Disabled {
+		return errors.New(errWebhookDisabled)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.deliveryStorage.Save(pending(webhook.ID, event.Type, payload))
+
+	return err
+}
+
+// Deliver will claim the batch of the deliveries due by now and attempt them. A failed
+// delivery is retried with an exponential backoff till it runs out of attempts. Returns
+// the number of the attempted deliveries
+func (s *WebhookService) Deliver(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	deliveries, err := s.deliveryStorage.Claim(now, now.Add(deliveryLease), deliveryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, delivery := range deliveries {
+		if ctx.Err() != nil {
+			// the unattempted deliveries are claimed again once their lease expires
+			return i, nil
+		}
+		if err = s.attempt(ctx, delivery); err != nil {
+			return i, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// attempt will post the delivery to its webhook and update the delivery with the result.
+// The deliveries to the disabled webhooks fail without being attempted
+func (s *WebhookService) attempt(ctx context.Context, delivery *m.Delivery) error {
+	webhook, err := s.webhookStorage.FindOneById(delivery.WebhookID)
+	switch {
+	case err == ErrRecordNotFound:
+		// the deliveries are deleted along with their webhook
+		return nil
+	case err != nil:
+		return err
+	case webhook.Disabled:
+		delivery.Status, delivery.Error, delivery.NextAttemptAt = m.DeliveryFailed, errWebhookDisabled, nil
+		_, err = s.deliveryStorage.Update(delivery)
+		return err
+	}
+
+	delivery.ResponseCode, err = s.sender.Send(ctx, *webhook, *delivery)
+	if err != nil && ctx.Err() != nil {
+		// the attempt interrupted by the shutdown is not counted
+		return nil
+	}
+
+	delivery.Attempts++
+	delivery.Error, delivery.NextAttemptAt = "", nil
+	switch {
+	case err == nil:
+		delivery.Status = m.DeliverySucceeded
+	case delivery.Attempts >= s.maxAttempts:
+		delivery.Status, delivery.Error = m.DeliveryFailed, err.Error()
+	default:
+		next := time.Now().UTC().Add(s.retryDelay(delivery.Attempts))
+		delivery.Status, delivery.Error, delivery.NextAttemptAt = m.DeliveryPending, err.Error(), &next
+	}
+
+	_, err = s.deliveryStorage.Update(delivery)
+
+	return err
+}
+
+// retryDelay returns the delay of the retry after the provided number of attempts
+func (s *WebhookService) retryDelay(attempts uint) time.Duration {
+	return backoffDelay(s.backoff, s.maxBackoff, attempts)
+}
+
+// backoffDelay returns the delay of the retry after the provided number of attempts,
+// the delay of the first retry is doubled with every next attempt up to the maximum
+func backoffDelay(delay, max time.Duration, attempts uint) time.Duration {
+	for i := uint(1); i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	return delay
+}
+
+// validate will validate the webhook along with its event types
+func (s *WebhookService) validate(webhook *m.Webhook) error {
+	if err := s.validator.Validate(*webhook); err != nil {
+		return err
+	}
+
+	errs := v.NewErrors()
+	for _, eventType := range webhook.Events {
+		if !eventType.Valid() {
+			errs.Add(v.Error{Field: "events", Message: fmt.Sprintf("unknown event type %q", eventType)})
+		}
+	}
+	if errs.Num() > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// findOne will return the webhook with the provided ID along with its secret if the
+// current user is an owner of its board
+func (s *WebhookService) findOne(ctx context.Context, ID uint) (*m.Webhook, error) {
+	webhook, err := s.webhookStorage.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.access.onBoard(ctx, webhook.BoardID, m.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// pending returns a new pending delivery of the payload to the webhook, that is due now
+func pending(webhookID uint, eventType m.EventType, payload json.RawMessage) *m.Delivery {
+	now := time.Now().UTC()
+
+	return &m.Delivery{
+		WebhookID:     webhookID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        m.DeliveryPending,
+		NextAttemptAt: &now,
+	}
+}
@@ -0,0 +1,344 @@
+// +build unit
+
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	m "github.com/dnozdrin/detask/internal/domain/models"
+	v "github.com/dnozdrin/detask/internal/domain/validation"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// validWebhook returns the validator that accepts any webhook
+func validWebhook() *MockedValidation {
+	var validationErr *v.Errors
+	validation := new(MockedValidation)
+	validation.On("Validate", mock.Anything).Return(validationErr)
+
+	return validation
+}
+
+func TestNewWebhookService(t *testing.T) {
+	validation := new(MockedValidation)
+	webhookStorage := new(MockedWebhookStorage)
+	deliveryStorage := new(MockedDeliveryStorage)
+	memberStorage := new(MockedMemberStorage)
+	sender := new(MockedWebhookSender)
+	webhookService := NewWebhookService(validation, webhookStorage, deliveryStorage, memberStorage, sender)
+
+	assert.Equal(t, validation, webhookService.validator)
+	assert.Equal(t, webhookStorage, webhookService.webhookStorage)
+	assert.Equal(t, deliveryStorage, webhookService.deliveryStorage)
+	assert.Equal(t, memberStorage, webhookService.access.memberStorage)
+	assert.Equal(t, sender, webhookService.sender)
+	assert.Equal(t, uint(maxDeliveryAttempts), webhookService.maxAttempts)
+}
+
+func TestWebhookService_Create(t *testing.T) {
+	t.Run("generated_secret", func(t *testing.T) {
+		webhookStorage := new(MockedWebhookStorage)
+		webhookStorage.On("Save", mock.Anything).Return(&m.Webhook{Model: m.Model{ID: 1}}, nil)
+		webhookService := &WebhookService{validator: validWebhook(), webhookStorage: webhookStorage, access: ownerAccess}
+
+		webhook := &m.Webhook{BoardID: 1, URL: "https://example.com/hook"}
+		_, err := webhookService.Create(testCtx, webhook)
+		require.Nil(t, err)
+		assert.Len(t, webhook.Secret, 2*secretSize)
+	})
+
+	t.Run("provided_secret", func(t *testing.T) {
+		webhook := &m.Webhook{BoardID: 1, URL: "https://example.com/hook", Secret: "0123456789abcdef"}
+		webhookStorage := new(MockedWebhookStorage)
+		webhookStorage.On("Save", webhook).Return(webhook, nil)
+		webhookService := &WebhookService{validator: validWebhook(), webhookStorage: webhookStorage, access: ownerAccess}
+
+		created, err := webhookService.Create(testCtx, webhook)
+		require.Nil(t, err)
+		assert.Equal(t, "0123456789abcdef", created.Secret)
+	})
+
+	t.Run("unknown_event_type", func(t *testing.T) {
+		webhookStorage := new(MockedWebhookStorage)
+		webhookService := &WebhookService{validator: validWebhook(), webhookStorage: webhookStorage, access: ownerAccess}
+
+		_, err := webhookService.Create(testCtx, &m.Webhook{
+			BoardID: 1,
+			URL:     "https://example.com/hook",
+			Events:  m.EventTypes{"task.created", "task.exploded"},
+		})
+		errs, ok := err.(*v.Errors)
+		require.True(t, ok)
+		assert.Equal(t, 1, errs.Num())
+		webhookStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+
+	t.Run("editor_forbidden", func(t *testing.T) {
+		webhookStorage := new(MockedWebhookStorage)
+		webhookService := &WebhookService{
+			validator:      validWebhook(),
+			webhookStorage: webhookStorage,
+			access:         access{memberStorage: roleStorage(m.RoleEditor, nil)},
+		}
+
+		_, err := webhookService.Create(testCtx, &m.Webhook{BoardID: 1, URL: "https://example.com/hook"})
+		assert.Equal(t, ErrForbidden, err)
+		webhookStorage.AssertNotCalled(t, "Save", mock.Anything)
+	})
+}
+
+func TestWebhookService_FindByBoard(t *testing.T) {
+	webhookStorage := new(MockedWebhookStorage)
+	webhookStorage.On("Find", uint(1), Page{Limit: 2}).Return([]*m.Webhook{
+		{Model: m.Model{ID: 1}, Secret: "first secret"},
+		{Model: m.Model{ID: 2}, Secret: "second secret"},
+	}, nil)
+	webhookService := &WebhookService{webhookStorage: webhookStorage, access: ownerAccess}
+
+	webhooks, next, err := webhookService.FindByBoard(testCtx, 1, Page{Limit: 1})
+	require.Nil(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Empty(t, webhooks[0].Secret)
+	assert.Equal(t, &Cursor{ID: 1}, next)
+}
+
+func TestWebhookService_Update(t *testing.T) {
+	stored := &m.Webhook{Model: m.Model{ID: 1}, BoardID: 1, URL: "https://example.com/old", Secret: "0123456789abcdef"}
+
+	t.Run("kept_secret", func(t *testing.T) {
+		webhookStorage := new(MockedWebhookStorage)
+		webhookStorage.On("FindOneById", uint(1)).Return(stored, nil)
+		webhookStorage.On("Update", mock.Anything).Return(&m.Webhook{Model: m.Model{ID: 1}, Secret: "0123456789abcdef"}, nil)
+		webhookService := &WebhookService{validator: validWebhook(), webhookStorage: webhookStorage, access: ownerAccess}
+
+		updated, err := webhookService.Update(testCtx, &m.Webhook{Model: m.Model{ID: 1}, URL: "https://example.com/new"})
+		require.Nil(t, err)
+		assert.Empty(t, updated.Secret)
+		passed := webhookStorage.Calls[1].Arguments.Get(0).(*m.Webhook)
+		assert.Equal(t, "0123456789abcdef", passed.Secret)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		webhookStorage := new(MockedWebhookStorage)
+		webhookStorage.On("FindOneById", uint(2)).Return((*m.Webhook)(nil), ErrRecordNotFound)
+		webhookService := &WebhookService{validator: validWebhook(), webhookStorage: webhookStorage, access: ownerAccess}
+
+		_, err := webhookService.Update(testCtx, &m.Webhook{Model: m.Model{ID: 2}, URL: "https://example.com/new"})
+		assert.Equal(t, ErrRecordNotFound, err)
+	})
+}
+
+func TestWebhookService_Delete(t *testing.T) {
+	webhookStorage := new(MockedWebhookStorage)
+	webhookStorage.On("FindOneById", uint(1)).Return(&m.Webhook{Model: m.Model{ID: 1}, BoardID: 1}, nil)
+	webhookStorage.On("Delete", uint(1)).Return(nil)
+	webhookService := &WebhookService{webhookStorage: webhookStorage, access: ownerAccess}
+
+	assert.Nil(t, webhookService.Delete(testCtx, 1))
+	webhookStorage.AssertExpectations(t)
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	webhookStorage := new(MockedWebhookStorage)
+	webhookStorage.On("FindOneById", uint(1)).Return(&m.Webhook{Model: m.Model{ID: 1}, BoardID: 1}, nil)
+	deliveryStorage := new(MockedDeliveryStorage)
+	deliveryStorage.On("FindOneById", uint(5)).Return(&m.Delivery{
+		ID:        5,
+		WebhookID: 1,
+		EventType: "task.created",
+		Payload:   json.RawMessage(`{}`),
+		Status:    m.DeliveryFailed,
+		Attempts:  10,
+	}, nil)
+	deliveryStorage.On("FindOneById", uint(6)).Return(&m.Delivery{ID: 6, WebhookID: 2}, nil)
+	deliveryStorage.On("Save", mock.Anything).Return(&m.Delivery{ID: 7}, nil)
+	webhookService := &WebhookService{webhookStorage: webhookStorage, deliveryStorage: deliveryStorage, access: ownerAccess}
+
+	t.Run("success", func(t *testing.T) {
+		_, err := webhookService.Redeliver(testCtx, 1, 5)
+		require.Nil(t, err)
+
+		queued := deliveryStorage.Calls[1].Arguments.Get(0).(*m.Delivery)
+		assert.Equal(t, uint(1), queued.WebhookID)
+		assert.Equal(t, m.EventType("task.created"), queued.EventType)
+		assert.Equal(t, m.DeliveryPending, queued.Status)
+		assert.Equal(t, uint(0), queued.Attempts)
+		assert.NotNil(t, queued.NextAttemptAt)
+	})
+
+	t.Run("other_webhook", func(t *testing.T) {
+		_, err := webhookService.Redeliver(testCtx, 1, 6)
+		assert.Equal(t, ErrRecordNotFound, err)
+	})
+}
+
+func TestWebhookService_Notify(t *testing.T) {
+	createdAt := time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC)
+	webhookStorage := new(MockedWebhookStorage)
+	webhookStorage.On("Find", uint(1), Page{}).Return([]*m.Webhook{
+		{Model: m.Model{ID: 1}},
+		{Model: m.Model{ID: 2}, Events: m.EventTypes{"task.created"}},
+		{Model: m.Model{ID: 3}, Events: m.EventTypes{"comment.created"}},
+		{Model: m.Model{ID: 4}, Disabled: true},
+		{Model: m.Model{ID: 5, CreatedAt: createdAt.Add(time.Second)}},
+	}, nil)
+	deliveryStorage := new(MockedDeliveryStorage)
+	// the first webhook has got the delivery of the event on the previous relay
+	deliveryStorage.On("Save", mock.MatchedBy(func(delivery *m.Delivery) bool { return delivery.WebhookID == 1 })).
+		Return((*m.Delivery)(nil), ErrRecordAlreadyExist)
+	deliveryStorage.On("Save", mock.Anything).Return(&m.Delivery{}, nil)
+	webhookService := &WebhookService{webhookStorage: webhookStorage, deliveryStorage: deliveryStorage}
+
+	require.Nil(t, webhookService.Notify(m.Event{ID: 9, Type: "task.created", BoardID: 1, CreatedAt: createdAt}))
+
+	require.Len(t, deliveryStorage.Calls, 2)
+	for i, webhookID := range []uint{1, 2} {
+		delivery := deliveryStorage.Calls[i].Arguments.Get(0).(*m.Delivery)
+		assert.Equal(t, webhookID, delivery.WebhookID)
+		assert.Equal(t, uint(9), delivery.EventID)
+		assert.Equal(t, m.EventType("task.created"), delivery.EventType)
+		var event m.Event
+		require.Nil(t, json.Unmarshal(delivery.Payload, &event))
+		assert.Equal(t, uint(9), event.ID)
+	}
+}
+
+func TestWebhookService_Dispatch(t *testing.T) {
+	event := m.Event{ID: 9, Type: "task.overdue", BoardID: 1}
+	webhookStorage := new(MockedWebhookStorage)
+	webhookStorage.On("FindOneById", uint(1)).Return(&m.Webhook{Model: m.Model{ID: 1}, BoardID: 1}, nil)
+	webhookStorage.On("FindOneById", uint(2)).Return(&m.Webhook{Model: m.Model{ID: 2}, BoardID: 2}, nil)
+	webhookStorage.On("FindOneById", uint(3)).Return(&m.Webhook{Model: m.Model{ID: 3}, BoardID: 1, Disabled: true}, nil)
+	webhookStorage.On("FindOneById", uint(4)).Return((*m.Webhook)(nil), ErrRecordNotFound)
+	deliveryStorage := new(MockedDeliveryStorage)
+	deliveryStorage.On("Save", mock.Anything).Return(&m.Delivery{}, nil)
+	webhookService := &WebhookService{webhookStorage: webhookStorage, deliveryStorage: deliveryStorage, access: ownerAccess}
+
+	require.Nil(t, webhookService.Dispatch(testCtx, 1, event))
+	assert.Equal(t, ErrWebhookRelation, webhookService.Dispatch(testCtx, 2, event))
+	assert.EqualError(t, webhookService.Dispatch(testCtx, 3, event), errWebhookDisabled)
+	assert.Equal(t, ErrWebhookRelation, webhookService.Dispatch(testCtx, 4, event))
+
+	// the event is delivered regardless of the event types of the webhook
+	require.Len(t, deliveryStorage.Calls, 1)
+	delivery := deliveryStorage.Calls[0].Arguments.Get(0).(*m.Delivery)
+	assert.Equal(t, uint(1), delivery.WebhookID)
+	assert.Equal(t, m.EventType("task.overdue"), delivery.EventType)
+}
+
+func TestWebhookService_Deliver(t *testing.T) {
+	webhook := &m.Webhook{Model: m.Model{ID: 1}, URL: "https://example.com/hook", Secret: "0123456789abcdef"}
+
+	// deliverStub returns the service that attempts the provided delivery with the
+	// result of the sender and the storage of the deliveries
+	deliverStub := func(webhook *m.Webhook, delivery *m.Delivery, code int, sendErr error) (*WebhookService, *MockedDeliveryStorage) {
+		webhookStorage := new(MockedWebhookStorage)
+		webhookStorage.On("FindOneById", uint(1)).Return(webhook, nil)
+		webhookStorage.On("FindOneById", uint(2)).Return((*m.Webhook)(nil), ErrRecordNotFound)
+		deliveryStorage := new(MockedDeliveryStorage)
+		deliveryStorage.On("Claim", mock.Anything, mock.Anything, uint(deliveryBatch)).Return([]*m.Delivery{delivery}, nil)
+		deliveryStorage.On("Update", delivery).Return(delivery, nil)
+		sender := new(MockedWebhookSender)
+		sender.On("Send", mock.Anything, mock.Anything, mock.Anything).Return(code, sendErr)
+
+		return &WebhookService{
+			webhookStorage:  webhookStorage,
+			deliveryStorage: deliveryStorage,
+			sender:          sender,
+			backoff:         time.Minute,
+			maxBackoff:      time.Hour,
+			maxAttempts:     3,
+		}, deliveryStorage
+	}
+
+	t.Run("succeeded", func(t *testing.T) {
+		delivery := &m.Delivery{ID: 1, WebhookID: 1, Status: m.DeliveryPending}
+		webhookService, deliveryStorage := deliverStub(webhook, delivery, 204, nil)
+
+		n, err := webhookService.Deliver(context.Background())
+		require.Nil(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, m.DeliverySucceeded, delivery.Status)
+		assert.Equal(t, uint(1), delivery.Attempts)
+		assert.Equal(t, 204, delivery.ResponseCode)
+		assert.Nil(t, delivery.NextAttemptAt)
+		deliveryStorage.AssertCalled(t, "Update", delivery)
+	})
+
+	t.Run("retried", func(t *testing.T) {
+		delivery := &m.Delivery{ID: 1, WebhookID: 1, Status: m.DeliveryPending, Attempts: 1}
+		webhookService, _ := deliverStub(webhook, delivery, 500, errors.New("unexpected response status"))
+
+		_, err := webhookService.Deliver(context.Background())
+		require.Nil(t, err)
+		assert.Equal(t, m.DeliveryPending, delivery.Status)
+		assert.Equal(t, uint(2), delivery.Attempts)
+		assert.Equal(t, 500, delivery.ResponseCode)
+		assert.Equal(t, "unexpected response status", delivery.Error)
+		require.NotNil(t, delivery.NextAttemptAt)
+		assert.WithinDuration(t, time.Now().Add(2*time.Minute), *delivery.NextAttemptAt, 5*time.Second)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		delivery := &m.Delivery{ID: 1, WebhookID: 1, Status: m.DeliveryPending, Attempts: 2}
+		webhookService, _ := deliverStub(webhook, delivery, 0, errors.New("connection refused"))
+
+		_, err := webhookService.Deliver(context.Background())
+		require.Nil(t, err)
+		assert.Equal(t, m.DeliveryFailed, delivery.Status)
+		assert.Equal(t, uint(3), delivery.Attempts)
+		assert.Nil(t, delivery.NextAttemptAt)
+	})
+
+	t.Run("disabled_webhook", func(t *testing.T) {
+		disabled := *webhook
+		disabled.Disabled = true
+		delivery := &m.Delivery{ID: 1, WebhookID: 1, Status: m.DeliveryPending}
+		webhookService, _ := deliverStub(&disabled, delivery, 0, nil)
+
+		_, err := webhookService.Deliver(context.Background())
+		require.Nil(t, err)
+		assert.Equal(t, m.DeliveryFailed, delivery.Status)
+		assert.Equal(t, uint(0), delivery.Attempts)
+		assert.Equal(t, errWebhookDisabled, delivery.Error)
+		webhookService.sender.(*MockedWebhookSender).AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("deleted_webhook", func(t *testing.T) {
+		delivery := &m.Delivery{ID: 1, WebhookID: 2, Status: m.DeliveryPending}
+		webhookService, deliveryStorage := deliverStub(webhook, delivery, 0, nil)
+
+		n, err := webhookService.Deliver(context.Background())
+		require.Nil(t, err)
+		assert.Equal(t, 1, n)
+		deliveryStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		delivery := &m.Delivery{ID: 1, WebhookID: 1, Status: m.DeliveryPending}
+		webhookService, deliveryStorage := deliverStub(webhook, delivery, 0, context.Canceled)
+
+		n, err := webhookService.Deliver(ctx)
+		require.Nil(t, err)
+		assert.Equal(t, 0, n)
+		deliveryStorage.AssertNotCalled(t, "Update", mock.Anything)
+	})
+}
+
+func TestWebhookService_RetryDelay(t *testing.T) {
+	webhookService := &WebhookService{backoff: time.Minute, maxBackoff: 10 * time.Minute}
+
+	assert.Equal(t, time.Minute, webhookService.retryDelay(1))
+	assert.Equal(t, 2*time.Minute, webhookService.retryDelay(2))
+	assert.Equal(t, 8*time.Minute, webhookService.retryDelay(4))
+	assert.Equal(t, 10*time.Minute, webhookService.retryDelay(5))
+	assert.Equal(t, 10*time.Minute, webhookService.retryDelay(50))
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+)
+
+// subscriberBuffer is the number of the events a subscriber may fall behind before
+// it is unsubscribed
+const subscriberBuffer = 64
+
+// Broker delivers the events to the subscribers of the boards within the process.
+// The events keep the IDs they have in the outbox, so the recent events are kept
+// in a bounded buffer and the subscribers that have reconnected can resume from
+// the last event they got
+type Broker struct {
+	mu          sync.Mutex
+	last        uint
+	replay      []models.Event
+	size        int
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber receives the events of a board
+type subscriber struct {
+	boardID uint
+	events  chan models.Event
+}
+
+// NewBroker is a Broker constructor, the provided size is the number of the recent
+// events kept for the replay
+func NewBroker(size int) *Broker {
+	return &Broker{
+		replay:      make([]models.Event, 0, size),
+		size:        size,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// Publish will keep the event for the replay and deliver it to the subscribers of
+// its board. The events are published in the order of their IDs, an event which ID
+// is not greater than the one of the last published event has been published
+// already and is skipped. The subscribers that have fallen behind the events are
+// unsubscribed
+func (b *Broker) Publish(event models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if event.ID <= b.last {
+		return
+	}
+	b.last = event.ID
+	if len(b.replay) == b.size && b.size > 0 {
+		b.replay = append(b.replay[:0], b.replay[1:]...)
+	}
+	if b.size > 0 {
+		b.replay = append(b.replay, event)
+	}
+
+	for s := range b.subscribers {
+		if s.boardID != event.BoardID {
+			continue
+		}
+		select {
+		case s.events <- event:
+		default:
+			b.unsubscribe(s)
+		}
+	}
+}
+
+// Subscribe will return the channel of the events of the board published after the
+// event with the provided ID that are still kept for the replay and of the events
+// published later on, and the function that cancels the subscription. Nothing is
+// replayed for the zero ID
+func (b *Broker) Subscribe(boardID, lastID uint) (<-chan models.Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	missed := make([]models.Event, 0)
+	for _, event := range b.replay {
+		if lastID > 0 && event.ID > lastID && event.BoardID == boardID {
+			missed = append(missed, event)
+		}
+	}
+
+	s := &subscriber{boardID: boardID, events: make(chan models.Event, len(missed)+subscriberBuffer)}
+	for _, event := range missed {
+		s.events <- event
+	}
+	b.subscribers[s] = struct{}{}
+
+	return s.events, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.unsubscribe(s)
+	}
+}
+
+// unsubscribe will close the channel of the subscriber unless it is already
+// unsubscribed, the broker must be locked
+func (b *Broker) unsubscribe(s *subscriber) {
+	if _, ok := b.subscribers[s]; ok {
+		delete(b.subscribers, s)
+		close(s.events)
+	}
+}
@@ -0,0 +1,84 @@
+// +build unit
+
+package events
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// received returns the events that are waiting in the channel
+func received(events <-chan models.Event) []uint {
+	IDs := make([]uint, 0)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return IDs
+			}
+			IDs = append(IDs, event.ID)
+		default:
+			return IDs
+		}
+	}
+}
+
+func TestBroker_Publish(t *testing.T) {
+	broker := NewBroker(10)
+	first, cancel := broker.Subscribe(1, 0)
+	defer cancel()
+	second, cancelSecond := broker.Subscribe(2, 0)
+
+	broker.Publish(models.Event{ID: 1, BoardID: 1, Type: "task.created"})
+	broker.Publish(models.Event{ID: 2, BoardID: 2})
+	broker.Publish(models.Event{ID: 3, BoardID: 1})
+	broker.Publish(models.Event{ID: 3, BoardID: 1})
+
+	event := <-first
+	assert.Equal(t, uint(1), event.ID)
+	assert.Equal(t, models.EventType("task.created"), event.Type)
+	assert.Equal(t, []uint{3}, received(first))
+	assert.Equal(t, []uint{2}, received(second))
+
+	cancelSecond()
+	cancelSecond()
+	_, ok := <-second
+	assert.False(t, ok)
+}
+
+func TestBroker_Subscribe(t *testing.T) {
+	broker := NewBroker(3)
+	for i := 1; i <= 5; i++ {
+		broker.Publish(models.Event{ID: uint(i), BoardID: 1})
+	}
+	broker.Publish(models.Event{ID: 6, BoardID: 2})
+
+	events, cancel := broker.Subscribe(1, 1)
+	defer cancel()
+	assert.Equal(t, []uint{4, 5}, received(events))
+
+	events, cancel = broker.Subscribe(1, 4)
+	defer cancel()
+	broker.Publish(models.Event{ID: 7, BoardID: 1})
+	assert.Equal(t, []uint{5, 7}, received(events))
+
+	events, cancel = broker.Subscribe(1, 0)
+	defer cancel()
+	assert.Empty(t, received(events))
+}
+
+func TestBroker_SlowSubscriber(t *testing.T) {
+	broker := NewBroker(0)
+	events, cancel := broker.Subscribe(1, 0)
+	defer cancel()
+
+	for i := 1; i <= subscriberBuffer+1; i++ {
+		broker.Publish(models.Event{ID: uint(i), BoardID: 1})
+	}
+
+	assert.Len(t, received(events), subscriberBuffer)
+	_, ok := <-events
+	assert.False(t, ok)
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// ActivityDAO is a data access object for the activity history
+type ActivityDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewActivityDAO represents an ActivityDAO constructor
+func NewActivityDAO(store *Store, log log.Logger) ActivityDAO {
+	return ActivityDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided activity entry and return a pointer to the saved
+// entry. The board of an entry on a task is resolved from the task, which may be
+// deleted. Returns nil and an error in case of error.
+func (dao ActivityDAO) Save(entry *models.Activity) (*models.Activity, error) {
+	if entry == nil {
+		dao.log.Error("activity storage: nil pointer given")
+		return nil, errors.New("nil activity pointer given")
+	}
+	if entry.ID > 0 {
+		dao.log.Warnf("activity storage: %v, ID: %d", sv.ErrRecordAlreadyExist, entry.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	saved := *entry
+	if saved.Changes == nil {
+		saved.Changes = make(models.Changes)
+	}
+	if task, ok := data.anyTask(entry.TaskID); ok {
+		saved.BoardID = data.columnBoard(task.ColumnID)
+	}
+	_, alive := data.boards[saved.BoardID]
+	if _, deleted := data.bin.boards[saved.BoardID]; !alive && !deleted {
+		return nil, sv.ErrBoardRelation
+	}
+
+	data.seq.activities++
+	saved.ID, saved.CreatedAt = data.seq.activities, time.Now().UTC()
+	data.activities = append(data.activities, saved)
+
+	return &saved, nil
+}
+
+// Find will return the activity entries that meet the provided demand and fit the
+// provided page from the newest to the oldest
+func (dao ActivityDAO) Find(demand sv.ActivityDemand, page sv.Page) ([]*models.Activity, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	boardID, byBoard := demand["board"].(uint)
+	taskID, byTask := demand["task"].(uint)
+	actorID, byActor := demand["actor"].(uint)
+	entity, byEntity := demand["entity"].(models.Entity)
+	entries := make([]*models.Activity, 0)
+	for i := len(data.activities) - 1; i >= 0; i-- {
+		entry := data.activities[i]
+		if byBoard && entry.BoardID != boardID {
+			continue
+		}
+		if byTask && entry.TaskID != taskID {
+			continue
+		}
+		if byActor && entry.ActorID != actorID {
+			continue
+		}
+		if byEntity && entry.Entity != entity {
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+
+	from, to := paginate(len(entries), page, func(i int) bool {
+		return entries[i].ID < page.After.ID
+	})
+
+	return entries[from:to], nil
+}
+
+// WithTx will return the ActivityDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao ActivityDAO) WithTx(*sql.Tx) sv.ActivityStorage {
+	dao.inTx = true
+	return dao
+}
+
+// anyTask returns the task with the provided ID, which may be deleted
+func (d *dataset) anyTask(ID uint) (models.Task, bool) {
+	if task, ok := d.tasks[ID]; ok {
+		return task, true
+	}
+	task, ok := d.bin.tasks[ID]
+
+	return task, ok
+}
@@ -0,0 +1,219 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// BoardDAO is a data access object for boards
+type BoardDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewBoardDAO represents a BoardDAO constructor
+func NewBoardDAO(store *Store, log log.Logger) BoardDAO {
+	return BoardDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided board and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao BoardDAO) Save(board *models.Board) (*models.Board, error) {
+	if board == nil {
+		dao.log.Error("boards storage: nil pointer given")
+		return nil, errors.New("nil board pointer given")
+	}
+	if board.ID > 0 {
+		dao.log.Warnf("boards storage: %v, ID: %d", sv.ErrRecordAlreadyExist, board.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	data.seq.boards++
+	now := time.Now()
+	board.ID = data.seq.boards
+	board.CreatedAt, board.UpdatedAt, board.Version = now, now, 1
+	data.track(data.boards, board.ID)
+	data.boards[board.ID] = *board
+
+	return board, nil
+}
+
+// FindOneById will return a pointer to a board with the provided ID or
+// nil and an error
+func (dao BoardDAO) FindOneById(ID uint) (*models.Board, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	board, ok := dao.store.data.boards[ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return &board, nil
+}
+
+// Find will return all found boards that meet the provided demand and fit
+// the provided page
+func (dao BoardDAO) Find(demand sv.BoardDemand, page sv.Page) ([]*models.Board, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	userID, byMember := demand["member"].(uint)
+	template, byTemplate := demand["template"].(bool)
+	boards := make([]*models.Board, 0, len(data.boards))
+	for _, board := range data.boards {
+		if byMember && !data.isMember(board.ID, userID) {
+			continue
+		}
+		if byTemplate && board.Template != template {
+			continue
+		}
+		board := board
+		boards = append(boards, &board)
+	}
+	sort.Slice(boards, func(i, j int) bool { return boards[i].ID < boards[j].ID })
+
+	from, to := paginate(len(boards), page, func(i int) bool { return boards[i].ID > page.After.ID })
+
+	return boards[from:to], nil
+}
+
+// Update will update the name, the description and the template flag of the board
+// and bump its version. The board is updated only if it has the version of the
+// provided one, unless it is zero
+func (dao BoardDAO) Update(board *models.Board) (*models.Board, error) {
+	if board == nil {
+		dao.log.Error("boards storage: nil pointer given")
+		return nil, errors.New("nil board pointer given")
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	stored, ok := dao.store.data.boards[board.ID]
+	if !ok || !sameVersion(stored.Model, board.Model) {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	stored.UpdatedAt = time.Now()
+	stored.Version++
+	stored.Name = board.Name
+	stored.Description = board.Description
+	stored.Template = board.Template
+	dao.store.data.track(dao.store.data.boards, board.ID)
+	dao.store.data.boards[board.ID] = stored
+	*board = stored
+
+	return board, nil
+}
+
+// Delete will move the board to the bin as well as all dependant records
+func (dao BoardDAO) Delete(ID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	dao.store.data.trashBoard(ID, time.Now())
+
+	return nil
+}
+
+// Copy will copy the columns of the source board to the target board, along with
+// the labels, the tasks and their comments if the tasks are requested
+func (dao BoardDAO) Copy(sourceID, targetID uint, withTasks bool, userID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+	now := time.Now()
+
+	columns := make([]models.Column, 0)
+	for _, column := range data.columns {
+		if column.BoardID == sourceID {
+			columns = append(columns, column)
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i].ID < columns[j].ID })
+	columnIDs := make(map[uint]uint, len(columns))
+	for _, column := range columns {
+		data.seq.columns++
+		columnIDs[column.ID] = data.seq.columns
+		column.ID, column.BoardID = data.seq.columns, targetID
+		column.CreatedAt, column.UpdatedAt, column.Version = now, now, 1
+		data.track(data.columns, column.ID)
+		data.columns[column.ID] = column
+	}
+	if !withTasks {
+		return nil
+	}
+
+	labels := make([]models.Label, 0)
+	for _, label := range data.labels {
+		if label.BoardID == sourceID {
+			labels = append(labels, label)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].ID < labels[j].ID })
+	labelIDs := make(map[uint]uint, len(labels))
+	for _, label := range labels {
+		data.seq.labels++
+		labelIDs[label.ID] = data.seq.labels
+		label.ID, label.BoardID = data.seq.labels, targetID
+		label.CreatedAt, label.UpdatedAt = now, now
+		data.track(data.labels, label.ID)
+		data.labels[label.ID] = label
+	}
+
+	tasks := make([]models.Task, 0)
+	for _, task := range data.tasks {
+		if _, ok := columnIDs[task.ColumnID]; ok {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	taskIDs := make(map[uint]uint, len(tasks))
+	for _, task := range tasks {
+		data.seq.tasks++
+		taskIDs[task.ID] = data.seq.tasks
+		copied := make([]uint, 0, len(task.Labels))
+		for _, labelID := range task.Labels {
+			copied = append(copied, labelIDs[labelID])
+		}
+		task.ID, task.ColumnID = data.seq.tasks, columnIDs[task.ColumnID]
+		task.CreatedAt, task.UpdatedAt, task.Version = now, now, 1
+		task.CreatedBy, task.ReporterID = userID, userID
+		task.Assignees, task.Labels = make([]uint, 0), copied
+		task.StartAt, task.DueAt = cloneTime(task.StartAt), cloneTime(task.DueAt)
+		data.track(data.tasks, task.ID)
+		data.tasks[task.ID] = task
+	}
+
+	comments := make([]models.Comment, 0)
+	for _, comment := range data.comments {
+		if _, ok := taskIDs[comment.TaskID]; ok {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+	for _, comment := range comments {
+		data.seq.comments++
+		comment.ID, comment.TaskID = data.seq.comments, taskIDs[comment.TaskID]
+		comment.UpdatedAt, comment.Version = now, 1
+		data.track(data.comments, comment.ID)
+		data.comments[comment.ID] = comment
+	}
+
+	return nil
+}
+
+// WithTx will return the BoardDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao BoardDAO) WithTx(*sql.Tx) sv.BoardStorage {
+	dao.inTx = true
+	return dao
+}
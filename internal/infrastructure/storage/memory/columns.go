@@ -0,0 +1,286 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// ColumnDAO is a data access object for columns
+type ColumnDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewColumnDAO represents a ColumnDAO constructor
+func NewColumnDAO(store *Store, log log.Logger) ColumnDAO {
+	return ColumnDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided column and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao ColumnDAO) Save(column *models.Column) (*models.Column, error) {
+	if column == nil {
+		dao.log.Error("columns storage: nil pointer given")
+		return nil, errors.New("nil column pointer given")
+	}
+	if column.ID > 0 {
+		dao.log.Warnf("columns storage: %v, ID: %d", sv.ErrRecordAlreadyExist, column.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	if _, ok := data.boards[column.BoardID]; !ok {
+		return nil, sv.ErrBoardRelation
+	}
+	if err := data.checkColumnConstraints(*column); err != nil {
+		return nil, err
+	}
+
+	data.seq.columns++
+	now := time.Now()
+	column.ID = data.seq.columns
+	column.CreatedAt, column.UpdatedAt, column.Version = now, now, 1
+	data.track(data.columns, column.ID)
+	data.columns[column.ID] = *column
+
+	return column, nil
+}
+
+// FindOneById will return a pointer to a column with the provided ID or
+// nil and an error
+func (dao ColumnDAO) FindOneById(ID uint) (*models.Column, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	column, ok := dao.store.data.columns[ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return &column, nil
+}
+
+// Find will return all found columns that fit the provided page sorted by position
+func (dao ColumnDAO) Find(demand sv.ColumnDemand, page sv.Page) ([]*models.Column, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	data := dao.store.data
+
+	boardID, byBoard := demand["board"].(uint)
+	userID, byMember := demand["member"].(uint)
+	columns := make([]*models.Column, 0)
+	for _, column := range data.columns {
+		if byBoard && column.BoardID != boardID {
+			continue
+		}
+		if byMember && !data.isMember(column.BoardID, userID) {
+			continue
+		}
+		column := column
+		columns = append(columns, &column)
+	}
+	sortColumns(columns)
+
+	from, to := paginate(len(columns), page, func(i int) bool {
+		if columns[i].Position == page.After.Position {
+			return columns[i].ID > page.After.ID
+		}
+		return columns[i].Position > page.After.Position
+	})
+
+	return columns[from:to], nil
+}
+
+// Update will update the name, the position and the WIP limit of the column and bump
+// its version. The column is updated only if it has the version of the provided one,
+// unless it is zero
+func (dao ColumnDAO) Update(column *models.Column) (*models.Column, error) {
+	if column == nil {
+		dao.log.Error("columns storage: nil pointer given")
+		return nil, errors.New("nil column pointer given")
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	stored, ok := data.columns[column.ID]
+	if !ok || !sameVersion(stored.Model, column.Model) {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	stored.Name = column.Name
+	stored.Position = column.Position
+	stored.WIPLimit = column.WIPLimit
+	if err := data.checkColumnConstraints(stored); err != nil {
+		return nil, err
+	}
+
+	stored.UpdatedAt = time.Now()
+	stored.Version++
+	data.track(data.columns, column.ID)
+	data.columns[column.ID] = stored
+	*column = stored
+
+	return column, nil
+}
+
+// Delete will move the column with the provided ID to the bin as well as all dependant records
+func (dao ColumnDAO) Delete(ID uint) error {
+	defer dao.store.lock(dao.inTx)()
+
+	if _, ok := dao.store.data.columns[ID]; !ok {
+		err := errors.Errorf("tried to delete %d rows, want 1", 0)
+		dao.log.Error(err)
+		return err
+	}
+	dao.store.data.trashColumn(ID, time.Now())
+
+	return nil
+}
+
+// Rebalance will place the columns with the provided IDs in the given order, with
+// the positions evenly spread by the step
+func (dao ColumnDAO) Rebalance(boardID uint, IDs []uint, step float64) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	now := time.Now()
+	moved := make(map[uint]models.Column, len(IDs))
+	for i, ID := range IDs {
+		column, ok := data.columns[ID]
+		if !ok || column.BoardID != boardID {
+			return sv.ErrRecordNotFound
+		}
+		column.Position, column.UpdatedAt = step*float64(i+1), now
+		column.Version++
+		moved[ID] = column
+	}
+	previous := make(map[uint]models.Column, len(moved))
+	for ID, column := range moved {
+		data.track(data.columns, ID)
+		previous[ID], data.columns[ID] = data.columns[ID], column
+	}
+	for _, column := range moved {
+		if err := data.checkColumnConstraints(column); err != nil {
+			for ID, column := range previous {
+				data.columns[ID] = column
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithTx will return the ColumnDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao ColumnDAO) WithTx(*sql.Tx) sv.ColumnStorage {
+	dao.inTx = true
+	return dao
+}
+
+// CountColumnsByBoard will count columns that are related to the provided board ID
+func (dao ColumnDAO) CountColumnsByBoard(ID uint) (int, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	var num int
+	for _, column := range dao.store.data.columns {
+		if column.BoardID == ID {
+			num++
+		}
+	}
+
+	return num, nil
+}
+
+// FindColumnToTheLeft will find an ID of the column to the left of the one with the provided ID
+func (dao ColumnDAO) FindColumnToTheLeft(ID uint) (uint, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	columns, idx := dao.store.data.boardColumns(ID)
+	if idx < 1 {
+		err := errors.New("columns storage: invalid left column record")
+		dao.log.Errorf("%v: %d", err, ID)
+		return 0, err
+	}
+
+	return columns[idx-1].ID, nil
+}
+
+// FindColumnToTheRight will find an ID of the column to the right of the one with the provided ID
+func (dao ColumnDAO) FindColumnToTheRight(ID uint) (uint, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	columns, idx := dao.store.data.boardColumns(ID)
+	if idx < 0 || idx+1 >= len(columns) {
+		err := errors.New("columns storage: invalid right column record")
+		dao.log.Errorf("%v: %d", err, ID)
+		return 0, err
+	}
+
+	return columns[idx+1].ID, nil
+}
+
+// checkColumnConstraints emulates unique (name, board) and unique (position, board)
+func (d *dataset) checkColumnConstraints(column models.Column) error {
+	for _, c := range d.columns {
+		if c.ID == column.ID || c.BoardID != column.BoardID {
+			continue
+		}
+		if c.Name == column.Name {
+			return sv.ErrNameDuplicate
+		}
+		if c.Position == column.Position {
+			return sv.ErrPositionDuplicate
+		}
+	}
+
+	return nil
+}
+
+// boardColumns returns the columns of the board the column with the provided
+// ID belongs to sorted by position, and the index of the column in the slice
+// or -1 if the column does not exist
+func (d *dataset) boardColumns(ID uint) ([]*models.Column, int) {
+	current, ok := d.columns[ID]
+	if !ok {
+		return nil, -1
+	}
+
+	columns := make([]*models.Column, 0)
+	for _, column := range d.columns {
+		if column.BoardID == current.BoardID {
+			column := column
+			columns = append(columns, &column)
+		}
+	}
+	sortColumns(columns)
+
+	for i, column := range columns {
+		if column.ID == ID {
+			return columns, i
+		}
+	}
+
+	return columns, -1
+}
+
+func sortColumns(columns []*models.Column) {
+	sort.Slice(columns, func(i, j int) bool {
+		if columns[i].Position == columns[j].Position {
+			return columns[i].ID < columns[j].ID
+		}
+		return columns[i].Position < columns[j].Position
+	})
+}
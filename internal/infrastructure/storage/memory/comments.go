@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// CommentsDAO is a data access object for comments
+type CommentsDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewCommentsDAO represents a CommentsDAO constructor
+func NewCommentsDAO(store *Store, log log.Logger) *CommentsDAO {
+	return &CommentsDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided comment and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao CommentsDAO) Save(comment *models.Comment) (*models.Comment, error) {
+	if comment == nil {
+		dao.log.Error("comments storage: nil pointer given")
+		return nil, errors.New("nil comment pointer given")
+	}
+	if comment.ID > 0 {
+		dao.log.Warnf("comments storage: %v, ID: %d", services.ErrRecordAlreadyExist, comment.ID)
+		return nil, services.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	if _, ok := data.tasks[comment.TaskID]; !ok {
+		return nil, services.ErrTaskRelation
+	}
+
+	data.seq.comments++
+	now := time.Now()
+	comment.ID = data.seq.comments
+	comment.CreatedAt, comment.UpdatedAt, comment.Version = now, now, 1
+	data.track(data.comments, comment.ID)
+	data.comments[comment.ID] = *comment
+
+	return comment, nil
+}
+
+// FindOneById will return a pointer to a comment with the provided ID or
+// nil and an error
+func (dao CommentsDAO) FindOneById(ID uint) (*models.Comment, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	comment, ok := dao.store.data.comments[ID]
+	if !ok {
+		return nil, services.ErrRecordNotFound
+	}
+
+	return &comment, nil
+}
+
+// Find will return all found comments that meet the provided demand and fit
+// the provided page sorted by creation date from newest to oldest
+func (dao CommentsDAO) Find(demand services.CommentDemand, page services.Page) ([]*models.Comment, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	data := dao.store.data
+
+	taskID, byTask := demand["task"].(uint)
+	userID, byMember := demand["member"].(uint)
+	comments := make([]*models.Comment, 0)
+	for _, comment := range data.comments {
+		if byTask && comment.TaskID != taskID {
+			continue
+		}
+		if byMember && !data.isMember(data.columns[data.tasks[comment.TaskID].ColumnID].BoardID, userID) {
+			continue
+		}
+		comment := comment
+		comments = append(comments, &comment)
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		if comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].ID > comments[j].ID
+		}
+		return comments[i].CreatedAt.After(comments[j].CreatedAt)
+	})
+
+	from, to := paginate(len(comments), page, func(i int) bool {
+		if comments[i].CreatedAt.Equal(page.After.CreatedAt) {
+			return comments[i].ID < page.After.ID
+		}
+		return comments[i].CreatedAt.Before(page.After.CreatedAt)
+	})
+
+	return comments[from:to], nil
+}
+
+// Update will update text of the comment and bump its version. The comment is updated
+// only if it has the version of the provided one, unless it is zero
+func (dao CommentsDAO) Update(comment *models.Comment) (*models.Comment, error) {
+	if comment == nil {
+		dao.log.Error("comments storage: nil pointer given")
+		return nil, errors.New("nil comment pointer given")
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	stored, ok := dao.store.data.comments[comment.ID]
+	if !ok || !sameVersion(stored.Model, comment.Model) {
+		return nil, services.ErrRecordNotFound
+	}
+
+	stored.UpdatedAt = time.Now()
+	stored.Version++
+	stored.Text = comment.Text
+	dao.store.data.track(dao.store.data.comments, comment.ID)
+	dao.store.data.comments[comment.ID] = stored
+	*comment = stored
+
+	return comment, nil
+}
+
+// Delete will move the comment with the provided ID to the bin
+func (dao CommentsDAO) Delete(ID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	dao.store.data.trashComment(ID, time.Now())
+
+	return nil
+}
+
+// WithTx will return the CommentsDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao CommentsDAO) WithTx(*sql.Tx) services.CommentStorage {
+	dao.inTx = true
+	return dao
+}
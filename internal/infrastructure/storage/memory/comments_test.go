@@ -0,0 +1,46 @@
+// +build unit
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentsDAO(t *testing.T) {
+	store := NewStore()
+	_, columns := seedColumns(t, store)
+	task, err := NewTaskDAO(store, new(LoggerMock)).Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1})
+	assert.NoError(t, err)
+	commentsDAO := NewCommentsDAO(store, new(LoggerMock))
+
+	_, err = commentsDAO.Save(&models.Comment{Text: "dummy", TaskID: task.ID + 1})
+	assert.Equal(t, services.ErrTaskRelation, err)
+
+	first, err := commentsDAO.Save(&models.Comment{Text: "first", TaskID: task.ID})
+	assert.NoError(t, err)
+	second, err := commentsDAO.Save(&models.Comment{Text: "second", TaskID: task.ID})
+	assert.NoError(t, err)
+
+	comments, err := commentsDAO.Find(services.CommentDemand{"task": task.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{second.ID, first.ID}, []uint{comments[0].ID, comments[1].ID})
+
+	comments, err = commentsDAO.Find(
+		services.CommentDemand{"task": task.ID},
+		services.Page{Limit: 1, After: &services.Cursor{ID: second.ID, CreatedAt: second.CreatedAt}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{first.ID}, []uint{comments[0].ID})
+
+	updated, err := commentsDAO.Update(&models.Comment{Model: models.Model{ID: first.ID}, Text: "updated"})
+	assert.NoError(t, err)
+	assert.Equal(t, task.ID, updated.TaskID)
+
+	assert.NoError(t, commentsDAO.Delete(first.ID))
+	_, err = commentsDAO.FindOneById(first.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotSupported is returned for any attempt to run an SQL statement
+// against the memory storage
+var ErrNotSupported = errors.New("memory storage: SQL statements are not supported")
+
+// NewConnector returns a database/sql connector for the provided store. It is
+// meant to be used with sql.OpenDB, so the services may keep on starting
+// transactions with the *sql.DB, while the memory DAOs are the ones that
+// work with the data.
+func NewConnector(store *Store) driver.Connector {
+	return connector{store: store}
+}
+
+type connector struct {
+	store *Store
+}
+
+// Connect returns a new connection to the store
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	return conn{store: c.store}, nil
+}
+
+// Driver returns the underlying driver of the connector
+func (c connector) Driver() driver.Driver {
+	return memDriver{}
+}
+
+type memDriver struct{}
+
+// Open is not supported since the memory storage has no DSN, use NewConnector instead
+func (memDriver) Open(string) (driver.Conn, error) {
+	return nil, ErrNotSupported
+}
+
+type conn struct {
+	store *Store
+}
+
+// Prepare is not supported by the memory storage
+func (c conn) Prepare(string) (driver.Stmt, error) {
+	return nil, ErrNotSupported
+}
+
+// Close does nothing since the data belongs to the store
+func (c conn) Close() error {
+	return nil
+}
+
+// Begin starts a transaction. It blocks until all the operations
+// and the transactions that are in progress are finished.
+func (c conn) Begin() (driver.Tx, error) {
+	c.store.begin()
+
+	return tx{store: c.store}, nil
+}
+
+type tx struct {
+	store *Store
+}
+
+// Commit keeps the changes made during the transaction
+func (t tx) Commit() error {
+	t.store.commit()
+
+	return nil
+}
+
+// Rollback restores the data as it was before the transaction start
+func (t tx) Rollback() error {
+	t.store.rollback()
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// LabelDAO is a data access object for labels
+type LabelDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewLabelDAO represents a LabelDAO constructor
+func NewLabelDAO(store *Store, log log.Logger) LabelDAO {
+	return LabelDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided label and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao LabelDAO) Save(label *models.Label) (*models.Label, error) {
+	if label == nil {
+		dao.log.Error("labels storage: nil pointer given")
+		return nil, errors.New("nil label pointer given")
+	}
+	if label.ID > 0 {
+		dao.log.Warnf("labels storage: %v, ID: %d", sv.ErrRecordAlreadyExist, label.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	if _, ok := data.boards[label.BoardID]; !ok {
+		return nil, sv.ErrBoardRelation
+	}
+	if err := data.checkLabelConstraints(*label); err != nil {
+		return nil, err
+	}
+
+	data.seq.labels++
+	now := time.Now()
+	label.ID = data.seq.labels
+	label.CreatedAt, label.UpdatedAt = now, now
+	data.track(data.labels, label.ID)
+	data.labels[label.ID] = *label
+
+	return label, nil
+}
+
+// FindOneById will return a pointer to a label with the provided ID or
+// nil and an error
+func (dao LabelDAO) FindOneById(ID uint) (*models.Label, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	label, ok := dao.store.data.labels[ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+	if _, ok := dao.store.data.boards[label.BoardID]; !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return &label, nil
+}
+
+// Find will return all found labels that meet the provided demand and fit
+// the provided page sorted by ID
+func (dao LabelDAO) Find(demand sv.LabelDemand, page sv.Page) ([]*models.Label, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	boardID, byBoard := demand["board"].(uint)
+	userID, byMember := demand["member"].(uint)
+	labels := make([]*models.Label, 0)
+	for _, label := range data.labels {
+		if byBoard && label.BoardID != boardID {
+			continue
+		}
+		if _, ok := data.boards[label.BoardID]; byMember && (!ok || !data.isMember(label.BoardID, userID)) {
+			continue
+		}
+		label := label
+		labels = append(labels, &label)
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].ID < labels[j].ID })
+
+	from, to := paginate(len(labels), page, func(i int) bool {
+		return labels[i].ID > page.After.ID
+	})
+
+	return labels[from:to], nil
+}
+
+// Update will update the name and the color of the label
+func (dao LabelDAO) Update(label *models.Label) (*models.Label, error) {
+	if label == nil {
+		dao.log.Error("labels storage: nil pointer given")
+		return nil, errors.New("nil label pointer given")
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	stored, ok := data.labels[label.ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	stored.Name = label.Name
+	stored.Color = label.Color
+	if err := data.checkLabelConstraints(stored); err != nil {
+		return nil, err
+	}
+
+	stored.UpdatedAt = time.Now()
+	data.track(data.labels, label.ID)
+	data.labels[label.ID] = stored
+	*label = stored
+
+	return label, nil
+}
+
+// Delete will delete the label with the provided ID and detach it from the tasks,
+// the versions of the tasks are bumped
+func (dao LabelDAO) Delete(ID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	dao.store.data.deleteLabel(ID)
+
+	return nil
+}
+
+// checkLabelConstraints emulates unique (name, board)
+func (d *dataset) checkLabelConstraints(label models.Label) error {
+	for _, l := range d.labels {
+		if l.ID != label.ID && l.BoardID == label.BoardID && l.Name == label.Name {
+			return sv.ErrNameDuplicate
+		}
+	}
+
+	return nil
+}
+
+// WithTx will return the LabelDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao LabelDAO) WithTx(*sql.Tx) sv.LabelStorage {
+	dao.inTx = true
+	return dao
+}
@@ -0,0 +1,61 @@
+// +build unit
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelDAO(t *testing.T) {
+	store := NewStore()
+	boardDAO := NewBoardDAO(store, new(LoggerMock))
+	board, err := boardDAO.Save(&models.Board{Name: "dummy"})
+	assert.NoError(t, err)
+	otherBoard, err := boardDAO.Save(&models.Board{Name: "other"})
+	assert.NoError(t, err)
+	column, err := NewColumnDAO(store, new(LoggerMock)).Save(&models.Column{Name: "dummy", BoardID: board.ID})
+	assert.NoError(t, err)
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+	task, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: column.ID})
+	assert.NoError(t, err)
+	labelDAO := NewLabelDAO(store, new(LoggerMock))
+
+	_, err = labelDAO.Save(&models.Label{Name: "bug", Color: "#ff0000", BoardID: board.ID + 10})
+	assert.Equal(t, services.ErrBoardRelation, err)
+
+	bug, err := labelDAO.Save(&models.Label{Name: "bug", Color: "#ff0000", BoardID: board.ID})
+	assert.NoError(t, err)
+	_, err = labelDAO.Save(&models.Label{Name: "bug", Color: "#00ff00", BoardID: board.ID})
+	assert.Equal(t, services.ErrNameDuplicate, err)
+	foreign, err := labelDAO.Save(&models.Label{Name: "bug", Color: "#00ff00", BoardID: otherBoard.ID})
+	assert.NoError(t, err)
+
+	labels, err := labelDAO.Find(services.LabelDemand{"board": board.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Label{bug}, labels)
+
+	assert.Equal(t, services.ErrLabelRelation, taskDAO.SetLabels(task.ID, []uint{foreign.ID}))
+	assert.NoError(t, taskDAO.SetLabels(task.ID, []uint{bug.ID}))
+	tasks, err := taskDAO.Find(services.TaskDemand{"label": bug.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, []uint{bug.ID}, tasks[0].Labels)
+
+	updated, err := labelDAO.Update(&models.Label{Model: models.Model{ID: bug.ID}, Name: "defect", Color: "#0000ff"})
+	assert.NoError(t, err)
+	assert.Equal(t, board.ID, updated.BoardID)
+
+	assert.NoError(t, labelDAO.Delete(bug.ID))
+	stored, err := taskDAO.FindOneById(task.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, stored.Labels)
+	assert.Equal(t, task.Version+1, stored.Version)
+
+	assert.NoError(t, boardDAO.Delete(otherBoard.ID))
+	_, err = labelDAO.FindOneById(foreign.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+}
@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// MemberDAO is a data access object for board members
+type MemberDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewMemberDAO represents a MemberDAO constructor
+func NewMemberDAO(store *Store, log log.Logger) MemberDAO {
+	return MemberDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided membership and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao MemberDAO) Save(member *models.Member) (*models.Member, error) {
+	if member == nil {
+		dao.log.Error("members storage: nil pointer given")
+		return nil, errors.New("nil member pointer given")
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	if _, ok := data.boards[member.BoardID]; !ok {
+		return nil, sv.ErrBoardRelation
+	}
+	if _, ok := data.users[member.UserID]; !ok {
+		return nil, sv.ErrUserRelation
+	}
+	key := memberKey{boardID: member.BoardID, userID: member.UserID}
+	if _, ok := data.members[key]; ok {
+		return nil, sv.ErrRecordAlreadyExist
+	}
+	data.track(data.members, key)
+	data.members[key] = *member
+
+	return member, nil
+}
+
+// Find will return all members of the board with the provided ID sorted by user ID
+func (dao MemberDAO) Find(boardID uint) ([]*models.Member, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	members := make([]*models.Member, 0)
+	for key, member := range dao.store.data.members {
+		if key.boardID != boardID {
+			continue
+		}
+		member := member
+		members = append(members, &member)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+
+	return members, nil
+}
+
+// Update will update the role of the member
+func (dao MemberDAO) Update(member *models.Member) (*models.Member, error) {
+	if member == nil {
+		dao.log.Error("members storage: nil pointer given")
+		return nil, errors.New("nil member pointer given")
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	key := memberKey{boardID: member.BoardID, userID: member.UserID}
+	if _, ok := dao.store.data.members[key]; !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+	dao.store.data.track(dao.store.data.members, key)
+	dao.store.data.members[key] = *member
+
+	return member, nil
+}
+
+// Delete will delete the membership of the user on the board
+func (dao MemberDAO) Delete(boardID, userID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	key := memberKey{boardID: boardID, userID: userID}
+	if _, ok := dao.store.data.members[key]; !ok {
+		return sv.ErrRecordNotFound
+	}
+	dao.store.data.track(dao.store.data.members, key)
+	delete(dao.store.data.members, key)
+
+	return nil
+}
+
+// CountOwners will return the number of owners of the board with the provided ID
+func (dao MemberDAO) CountOwners(boardID uint) (int, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	var num int
+	for key, member := range dao.store.data.members {
+		if key.boardID == boardID && member.Role == models.RoleOwner {
+			num++
+		}
+	}
+
+	return num, nil
+}
+
+// FindOwnerless will return the IDs of the boards that are not deleted and have
+// no owners sorted by ID
+func (dao MemberDAO) FindOwnerless() ([]uint, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	owned := make(map[uint]bool)
+	for key, member := range data.members {
+		if member.Role == models.RoleOwner {
+			owned[key.boardID] = true
+		}
+	}
+	IDs := make([]uint, 0)
+	for ID := range data.boards {
+		if !owned[ID] {
+			IDs = append(IDs, ID)
+		}
+	}
+	sort.Slice(IDs, func(i, j int) bool { return IDs[i] < IDs[j] })
+
+	return IDs, nil
+}
+
+// FindRoleByBoard will return the role of the user on the board with the provided ID
+func (dao MemberDAO) FindRoleByBoard(boardID, userID uint) (models.Role, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	return dao.store.data.findRole(boardID, userID)
+}
+
+// FindRoleByColumn will return the role of the user on the board of the column
+// with the provided ID
+func (dao MemberDAO) FindRoleByColumn(columnID, userID uint) (models.Role, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	column, ok := data.columns[columnID]
+	if !ok {
+		return "", sv.ErrRecordNotFound
+	}
+
+	return data.findRole(column.BoardID, userID)
+}
+
+// FindRoleByTask will return the role of the user on the board of the task
+// with the provided ID
+func (dao MemberDAO) FindRoleByTask(taskID, userID uint) (models.Role, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	task, ok := data.tasks[taskID]
+	if !ok {
+		return "", sv.ErrRecordNotFound
+	}
+
+	return data.findRole(data.columns[task.ColumnID].BoardID, userID)
+}
+
+// WithTx will return the MemberDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao MemberDAO) WithTx(*sql.Tx) sv.MemberStorage {
+	dao.inTx = true
+	return dao
+}
@@ -0,0 +1,64 @@
+// +build unit
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemberDAO(t *testing.T) {
+	store := NewStore()
+	user, err := NewUserDAO(store, new(LoggerMock)).Save(&models.User{Email: "john@example.com", Name: "John"})
+	assert.NoError(t, err)
+	board, err := NewBoardDAO(store, new(LoggerMock)).Save(&models.Board{Name: "dummy", CreatedBy: user.ID})
+	assert.NoError(t, err)
+	column, err := NewColumnDAO(store, new(LoggerMock)).Save(&models.Column{Name: "dummy", BoardID: board.ID})
+	assert.NoError(t, err)
+	memberDAO := NewMemberDAO(store, new(LoggerMock))
+
+	_, err = memberDAO.Save(&models.Member{BoardID: board.ID, UserID: user.ID + 1, Role: models.RoleOwner})
+	assert.Equal(t, services.ErrUserRelation, err)
+
+	_, err = memberDAO.Save(&models.Member{BoardID: board.ID + 1, UserID: user.ID, Role: models.RoleOwner})
+	assert.Equal(t, services.ErrBoardRelation, err)
+
+	_, err = memberDAO.Save(&models.Member{BoardID: board.ID, UserID: user.ID, Role: models.RoleOwner})
+	assert.NoError(t, err)
+
+	_, err = memberDAO.Save(&models.Member{BoardID: board.ID, UserID: user.ID, Role: models.RoleViewer})
+	assert.Equal(t, services.ErrRecordAlreadyExist, err)
+
+	role, err := memberDAO.FindRoleByColumn(column.ID, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleOwner, role)
+
+	role, err = memberDAO.FindRoleByBoard(board.ID, user.ID+1)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Role(""), role)
+
+	_, err = memberDAO.FindRoleByColumn(column.ID+1, user.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	owners, err := memberDAO.CountOwners(board.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, owners)
+	ownerless, err := memberDAO.FindOwnerless()
+	assert.NoError(t, err)
+	assert.Empty(t, ownerless)
+
+	_, err = memberDAO.Update(&models.Member{BoardID: board.ID, UserID: user.ID, Role: models.RoleEditor})
+	assert.NoError(t, err)
+	members, err := memberDAO.Find(board.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Member{{BoardID: board.ID, UserID: user.ID, Role: models.RoleEditor}}, members)
+	ownerless, err = memberDAO.FindOwnerless()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{board.ID}, ownerless)
+
+	assert.NoError(t, memberDAO.Delete(board.ID, user.ID))
+	assert.Equal(t, services.ErrRecordNotFound, memberDAO.Delete(board.ID, user.ID))
+}
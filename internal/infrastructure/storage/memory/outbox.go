@@ -0,0 +1,181 @@
+package memory
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// outboxEntry is an event kept in the outbox, the delivery time of the pending
+// events is nil. The consumers are the names of the consumers that have accepted
+// the event, the next attempt is set for the failed events only and the time of
+// death for the dead ones only
+type outboxEntry struct {
+	event       models.Event
+	consumers   []string
+	attempts    uint
+	nextAttempt *time.Time
+	deliveredAt *time.Time
+	deadAt      *time.Time
+}
+
+// OutboxDAO is a data access object for the outbox of the change events
+type OutboxDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewOutboxDAO represents an OutboxDAO constructor
+func NewOutboxDAO(store *Store, log log.Logger) OutboxDAO {
+	return OutboxDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided event and return a pointer to the saved event.
+// Returns nil and an error in case of error.
+func (dao OutboxDAO) Save(event *models.Event) (*models.Event, error) {
+	if event == nil {
+		dao.log.Error("outbox storage: nil pointer given")
+		return nil, errors.New("nil event pointer given")
+	}
+	if event.ID > 0 {
+		dao.log.Warnf("outbox storage: %v, ID: %d", sv.ErrRecordAlreadyExist, event.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	data.seq.outbox++
+	event.ID = data.seq.outbox
+	data.outbox = append(data.outbox, outboxEntry{event: copyEvent(*event)})
+
+	return event, nil
+}
+
+// FindPending will return up to the limit of the events that are neither delivered
+// nor dead yet and are due by the provided time sorted by ID
+func (dao OutboxDAO) FindPending(now time.Time, limit uint) ([]*models.Event, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	events := make([]*models.Event, 0)
+	for _, entry := range dao.store.data.outbox {
+		if uint(len(events)) == limit {
+			break
+		}
+		due := entry.nextAttempt == nil || !entry.nextAttempt.After(now)
+		if entry.deliveredAt == nil && entry.deadAt == nil && due {
+			event := copyEvent(entry.event)
+			event.Consumers = append([]string{}, entry.consumers...)
+			event.Attempts = entry.attempts
+			events = append(events, &event)
+		}
+	}
+
+	return events, nil
+}
+
+// MarkConsumed will record that the consumer with the provided name has accepted
+// the event with the provided ID
+func (dao OutboxDAO) MarkConsumed(ID uint, consumer string) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	for i := range data.outbox {
+		if data.outbox[i].event.ID != ID {
+			continue
+		}
+		for _, name := range data.outbox[i].consumers {
+			if name == consumer {
+				return nil
+			}
+		}
+		// the consumers are copied, as the entries share them with the undo log
+		// of the transaction
+		consumers := append([]string{}, data.outbox[i].consumers...)
+		data.track(data.outbox, i)
+		data.outbox[i].consumers = append(consumers, consumer)
+		return nil
+	}
+
+	return sv.ErrRecordNotFound
+}
+
+// MarkFailed will count a failed relay of the event with the provided ID and postpone
+// its next attempt, the nil time marks the event dead
+func (dao OutboxDAO) MarkFailed(ID uint, next *time.Time) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	for i := range data.outbox {
+		if data.outbox[i].event.ID == ID {
+			data.track(data.outbox, i)
+			data.outbox[i].attempts++
+			if next == nil {
+				now := time.Now().UTC()
+				data.outbox[i].deadAt = &now
+			} else {
+				nextAttempt := next.UTC()
+				data.outbox[i].nextAttempt = &nextAttempt
+			}
+			return nil
+		}
+	}
+
+	return sv.ErrRecordNotFound
+}
+
+// MarkDelivered will mark the event with the provided ID as delivered
+func (dao OutboxDAO) MarkDelivered(ID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	for i := range data.outbox {
+		if data.outbox[i].event.ID == ID {
+			now := time.Now().UTC()
+			data.track(data.outbox, i)
+			data.outbox[i].deliveredAt = &now
+			return nil
+		}
+	}
+
+	return sv.ErrRecordNotFound
+}
+
+// Purge will delete the events delivered before the provided time, the dead events
+// are kept
+func (dao OutboxDAO) Purge(before time.Time) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	kept := make([]outboxEntry, 0, len(data.outbox))
+	for _, entry := range data.outbox {
+		if entry.deliveredAt == nil || !entry.deliveredAt.Before(before) {
+			kept = append(kept, entry)
+		}
+	}
+	data.outbox = kept
+
+	return nil
+}
+
+// WithTx will return the OutboxDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao OutboxDAO) WithTx(*sql.Tx) sv.OutboxStorage {
+	dao.inTx = true
+	return dao
+}
+
+// copyEvent returns the copy of the event that shares no memory with it
+func copyEvent(event models.Event) models.Event {
+	event.Data = append([]byte{}, event.Data...)
+
+	return event
+}
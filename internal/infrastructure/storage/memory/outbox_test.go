@@ -0,0 +1,78 @@
+// +build unit
+
+package memory
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxDAO(t *testing.T) {
+	store := NewStore()
+	outboxDAO := NewOutboxDAO(store, new(LoggerMock))
+	createdAt := time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	first, err := outboxDAO.Save(&models.Event{
+		Type:      "task.moved",
+		BoardID:   1,
+		TaskID:    2,
+		Entity:    models.EntityTask,
+		EntityID:  2,
+		ActorID:   3,
+		Data:      json.RawMessage(`{"id":2}`),
+		CreatedAt: createdAt,
+	})
+	require.NoError(t, err)
+	second, err := outboxDAO.Save(&models.Event{Type: "board.deleted", BoardID: 1, Data: json.RawMessage(`{}`)})
+	require.NoError(t, err)
+	assert.True(t, first.ID < second.ID)
+	first.Consumers, second.Consumers = []string{}, []string{}
+
+	pending, err := outboxDAO.FindPending(time.Now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Event{first, second}, pending)
+
+	require.NoError(t, outboxDAO.MarkDelivered(first.ID))
+	assert.Equal(t, services.ErrRecordNotFound, outboxDAO.MarkDelivered(second.ID+10))
+	pending, err = outboxDAO.FindPending(time.Now(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Event{second}, pending)
+
+	require.NoError(t, outboxDAO.Purge(time.Now().Add(-time.Hour)))
+	require.NoError(t, outboxDAO.MarkDelivered(first.ID))
+	require.NoError(t, outboxDAO.Purge(time.Now().Add(time.Hour)))
+	assert.Equal(t, services.ErrRecordNotFound, outboxDAO.MarkDelivered(first.ID))
+	pending, err = outboxDAO.FindPending(time.Now(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Event{second}, pending)
+
+	require.NoError(t, outboxDAO.MarkConsumed(second.ID, "events"))
+	require.NoError(t, outboxDAO.MarkConsumed(second.ID, "events"))
+	require.NoError(t, outboxDAO.MarkConsumed(second.ID, "webhooks"))
+	assert.Equal(t, services.ErrRecordNotFound, outboxDAO.MarkConsumed(second.ID+10, "events"))
+	next := time.Now().Add(time.Minute)
+	require.NoError(t, outboxDAO.MarkFailed(second.ID, &next))
+	assert.Equal(t, services.ErrRecordNotFound, outboxDAO.MarkFailed(second.ID+10, &next))
+	pending, err = outboxDAO.FindPending(time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	pending, err = outboxDAO.FindPending(next.Add(time.Second), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, second.ID, pending[0].ID)
+	assert.Equal(t, uint(1), pending[0].Attempts)
+	assert.ElementsMatch(t, []string{"events", "webhooks"}, pending[0].Consumers)
+
+	require.NoError(t, outboxDAO.MarkFailed(second.ID, nil))
+	require.NoError(t, outboxDAO.Purge(time.Now().Add(time.Hour)))
+	pending, err = outboxDAO.FindPending(time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	assert.Nil(t, outboxDAO.MarkDelivered(second.ID), "the dead events are kept")
+}
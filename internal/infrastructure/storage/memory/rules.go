@@ -0,0 +1,279 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// RuleDAO is a data access object for the automation rules
+type RuleDAO struct {
+	store *Store
+	log   log.Logger
+}
+
+// NewRuleDAO represents a RuleDAO constructor
+func NewRuleDAO(store *Store, log log.Logger) RuleDAO {
+	return RuleDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided rule and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao RuleDAO) Save(rule *models.Rule) (*models.Rule, error) {
+	if rule == nil {
+		dao.log.Error("rules storage: nil pointer given")
+		return nil, errors.New("nil rule pointer given")
+	}
+	if rule.ID > 0 {
+		dao.log.Warnf("rules storage: %v, ID: %d", sv.ErrRecordAlreadyExist, rule.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	if _, ok := data.boards[rule.BoardID]; !ok {
+		return nil, sv.ErrBoardRelation
+	}
+
+	data.seq.rules++
+	now := time.Now()
+	rule.ID = data.seq.rules
+	rule.CreatedAt, rule.UpdatedAt = now, now
+	*rule = copyRule(*rule)
+	data.track(data.rules, rule.ID)
+	data.rules[rule.ID] = copyRule(*rule)
+
+	return rule, nil
+}
+
+// FindOneById will return a pointer to a rule with the provided ID or
+// nil and an error
+func (dao RuleDAO) FindOneById(ID uint) (*models.Rule, error) {
+	defer dao.store.rlock(false)()
+
+	rule, ok := dao.store.data.rules[ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+	rule = copyRule(rule)
+
+	return &rule, nil
+}
+
+// Find will return the rules of the board that fit the provided page sorted by ID
+func (dao RuleDAO) Find(boardID uint, page sv.Page) ([]*models.Rule, error) {
+	defer dao.store.rlock(false)()
+
+	rules := dao.store.data.findRules(func(rule models.Rule) bool {
+		return rule.BoardID == boardID
+	})
+	from, to := paginate(len(rules), page, func(i int) bool {
+		return rules[i].ID > page.After.ID
+	})
+
+	return rules[from:to], nil
+}
+
+// FindTriggered will return the enabled rules with the provided trigger type sorted by
+// ID, of the board with the provided ID or of all the boards that are not deleted for
+// the zero ID
+func (dao RuleDAO) FindTriggered(trigger models.TriggerType, boardID uint) ([]*models.Rule, error) {
+	defer dao.store.rlock(false)()
+	data := dao.store.data
+
+	return data.findRules(func(rule models.Rule) bool {
+		if rule.Disabled || rule.Trigger.Type != trigger {
+			return false
+		}
+		if boardID != 0 {
+			return rule.BoardID == boardID
+		}
+		_, alive := data.boards[rule.BoardID]
+
+		return alive
+	}), nil
+}
+
+// Update will update the name, the trigger, the conditions, the actions and the
+// disabled flag of the rule
+func (dao RuleDAO) Update(rule *models.Rule) (*models.Rule, error) {
+	if rule == nil {
+		dao.log.Error("rules storage: nil pointer given")
+		return nil, errors.New("nil rule pointer given")
+	}
+
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	stored, ok := data.rules[rule.ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	stored.Name = rule.Name
+	stored.Trigger = rule.Trigger
+	stored.Conditions = rule.Conditions
+	stored.Actions = rule.Actions
+	stored.Disabled = rule.Disabled
+	stored.UpdatedAt = time.Now()
+	stored = copyRule(stored)
+	data.track(data.rules, rule.ID)
+	data.rules[rule.ID] = stored
+	*rule = copyRule(stored)
+
+	return rule, nil
+}
+
+// Delete will delete the rule with the provided ID along with its executions
+func (dao RuleDAO) Delete(ID uint) error {
+	defer dao.store.lock(false)()
+	if _, ok := dao.store.data.rules[ID]; !ok {
+		return sv.ErrRecordNotFound
+	}
+	dao.store.data.deleteRule(ID)
+
+	return nil
+}
+
+// findRules returns the copies of the rules that match the filter sorted by ID
+func (d *dataset) findRules(match func(models.Rule) bool) []*models.Rule {
+	rules := make([]*models.Rule, 0)
+	for _, rule := range d.rules {
+		if !match(rule) {
+			continue
+		}
+		rule := copyRule(rule)
+		rules = append(rules, &rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+
+	return rules
+}
+
+// deleteRule removes the rule along with its executions
+func (d *dataset) deleteRule(ID uint) {
+	for executionID, execution := range d.executions {
+		if execution.RuleID == ID {
+			d.track(d.executions, executionID)
+			delete(d.executions, executionID)
+		}
+	}
+	d.track(d.rules, ID)
+	delete(d.rules, ID)
+}
+
+// copyRule returns the copy of the rule that shares no memory with it, the missing
+// conditions and actions are stored as empty lists like in the databases
+func copyRule(rule models.Rule) models.Rule {
+	rule.Conditions = append(models.RuleConditions{}, rule.Conditions...)
+	rule.Actions = append(models.RuleActions{}, rule.Actions...)
+
+	return rule
+}
+
+// ExecutionDAO is a data access object for the execution log of the automation rules
+type ExecutionDAO struct {
+	store *Store
+	log   log.Logger
+}
+
+// NewExecutionDAO represents an ExecutionDAO constructor
+func NewExecutionDAO(store *Store, log log.Logger) ExecutionDAO {
+	return ExecutionDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided execution and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao ExecutionDAO) Save(execution *models.RuleExecution) (*models.RuleExecution, error) {
+	if execution == nil {
+		dao.log.Error("executions storage: nil pointer given")
+		return nil, errors.New("nil execution pointer given")
+	}
+	if execution.ID > 0 {
+		dao.log.Warnf("executions storage: %v, ID: %d", sv.ErrRecordAlreadyExist, execution.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	if _, ok := data.rules[execution.RuleID]; !ok {
+		return nil, errors.Errorf("executions storage: rule %d was not found", execution.RuleID)
+	}
+
+	data.seq.executions++
+	execution.ID = data.seq.executions
+	execution.CreatedAt = time.Now().UTC()
+	data.track(data.executions, execution.ID)
+	data.executions[execution.ID] = *execution
+
+	return execution, nil
+}
+
+// Find will return the executions of the rule that fit the provided page from
+// the newest to the oldest
+func (dao ExecutionDAO) Find(ruleID uint, page sv.Page) ([]*models.RuleExecution, error) {
+	defer dao.store.rlock(false)()
+
+	executions := dao.store.data.findExecutions(ruleID, 0)
+	from, to := paginate(len(executions), page, func(i int) bool {
+		return executions[i].ID < page.After.ID
+	})
+
+	return executions[from:to], nil
+}
+
+// FindLast will return a pointer to the latest execution of the rule on the task or
+// nil and an error
+func (dao ExecutionDAO) FindLast(ruleID, taskID uint) (*models.RuleExecution, error) {
+	defer dao.store.rlock(false)()
+
+	executions := dao.store.data.findExecutions(ruleID, taskID)
+	if len(executions) == 0 {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return executions[0], nil
+}
+
+// FindByEvent will return a pointer to the execution of the rule run by the event or
+// nil and an error
+func (dao ExecutionDAO) FindByEvent(ruleID, eventID uint) (*models.RuleExecution, error) {
+	defer dao.store.rlock(false)()
+
+	executions := dao.store.data.findExecutions(ruleID, 0)
+	for i := len(executions) - 1; i >= 0; i-- {
+		if executions[i].EventID == eventID {
+			return executions[i], nil
+		}
+	}
+
+	return nil, sv.ErrRecordNotFound
+}
+
+// findExecutions returns the copies of the executions of the rule from the newest to
+// the oldest, only the ones on the task with the provided ID unless it is zero
+func (d *dataset) findExecutions(ruleID, taskID uint) []*models.RuleExecution {
+	executions := make([]*models.RuleExecution, 0)
+	for _, execution := range d.executions {
+		if execution.RuleID != ruleID || taskID != 0 && execution.TaskID != taskID {
+			continue
+		}
+		execution := execution
+		executions = append(executions, &execution)
+	}
+	sort.Slice(executions, func(i, j int) bool { return executions[i].ID > executions[j].ID })
+
+	return executions
+}
@@ -0,0 +1,152 @@
+// +build unit
+
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleDAO(t *testing.T) {
+	store := NewStore()
+	user, err := NewUserDAO(store, new(LoggerMock)).Save(&models.User{Email: "john@example.com", Name: "John"})
+	require.NoError(t, err)
+	boardDAO := NewBoardDAO(store, new(LoggerMock))
+	board, err := boardDAO.Save(&models.Board{Name: "dummy"})
+	require.NoError(t, err)
+	deleted, err := boardDAO.Save(&models.Board{Name: "deleted"})
+	require.NoError(t, err)
+	ruleDAO := NewRuleDAO(store, new(LoggerMock))
+
+	_, err = ruleDAO.Save(&models.Rule{BoardID: board.ID + 10, Name: "dummy", CreatedBy: user.ID})
+	assert.Equal(t, services.ErrBoardRelation, err)
+
+	first, err := ruleDAO.Save(&models.Rule{
+		BoardID:    board.ID,
+		Name:       "first",
+		Trigger:    models.RuleTrigger{Type: models.TriggerTaskMoved, ColumnID: 1},
+		Conditions: models.RuleConditions{{Type: models.ConditionLabel, LabelID: 2}},
+		Actions:    models.RuleActions{{Type: models.ActionTypeComment, Text: "moved"}},
+		CreatedBy:  user.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.RuleTrigger{Type: models.TriggerTaskMoved, ColumnID: 1}, first.Trigger)
+	assert.Equal(t, models.RuleConditions{{Type: models.ConditionLabel, LabelID: 2}}, first.Conditions)
+	second, err := ruleDAO.Save(&models.Rule{
+		BoardID:   board.ID,
+		Name:      "second",
+		Trigger:   models.RuleTrigger{Type: models.TriggerTaskOverdue},
+		Actions:   models.RuleActions{{Type: models.ActionTypeLabel, LabelID: 2}},
+		CreatedBy: user.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.RuleConditions{}, second.Conditions)
+	_, err = ruleDAO.Save(&models.Rule{
+		BoardID:   deleted.ID,
+		Name:      "deleted",
+		Trigger:   models.RuleTrigger{Type: models.TriggerTaskOverdue},
+		CreatedBy: user.ID,
+	})
+	require.NoError(t, err)
+	require.NoError(t, boardDAO.Delete(deleted.ID))
+
+	rules, err := ruleDAO.Find(board.ID, services.Page{Limit: 1, After: &services.Cursor{ID: first.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Rule{second}, rules)
+
+	rules, err = ruleDAO.FindTriggered(models.TriggerTaskOverdue, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Rule{second}, rules)
+	rules, err = ruleDAO.FindTriggered(models.TriggerTaskMoved, board.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Rule{first}, rules)
+
+	updated, err := ruleDAO.Update(&models.Rule{
+		Model:    models.Model{ID: first.ID},
+		Name:     "updated",
+		Trigger:  models.RuleTrigger{Type: models.TriggerTaskCreated},
+		Actions:  models.RuleActions{{Type: models.ActionTypeAssign, UserID: user.ID}},
+		Disabled: true,
+	})
+	require.NoError(t, err)
+	stored, err := ruleDAO.FindOneById(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, stored)
+	assert.Equal(t, board.ID, stored.BoardID)
+	assert.Equal(t, user.ID, stored.CreatedBy)
+	assert.Equal(t, "updated", stored.Name)
+	assert.True(t, stored.Disabled)
+
+	rules, err = ruleDAO.FindTriggered(models.TriggerTaskCreated, board.ID)
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+
+	_, err = ruleDAO.Update(&models.Rule{Model: models.Model{ID: second.ID + 10}})
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	assert.NoError(t, ruleDAO.Delete(second.ID))
+	assert.Equal(t, services.ErrRecordNotFound, ruleDAO.Delete(second.ID))
+}
+
+func TestExecutionDAO(t *testing.T) {
+	store := NewStore()
+	user, err := NewUserDAO(store, new(LoggerMock)).Save(&models.User{Email: "john@example.com", Name: "John"})
+	require.NoError(t, err)
+	board, err := NewBoardDAO(store, new(LoggerMock)).Save(&models.Board{Name: "dummy"})
+	require.NoError(t, err)
+	ruleDAO := NewRuleDAO(store, new(LoggerMock))
+	rule, err := ruleDAO.Save(&models.Rule{
+		BoardID:   board.ID,
+		Name:      "dummy",
+		Trigger:   models.RuleTrigger{Type: models.TriggerTaskCreated},
+		CreatedBy: user.ID,
+	})
+	require.NoError(t, err)
+	executionDAO := NewExecutionDAO(store, new(LoggerMock))
+
+	_, err = executionDAO.FindLast(rule.ID, 1)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	before := time.Now().UTC().Add(-time.Second)
+	first, err := executionDAO.Save(&models.RuleExecution{RuleID: rule.ID, EventID: 7, TaskID: 1, Status: models.ExecutionSucceeded})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), first.EventID)
+	assert.True(t, first.CreatedAt.After(before))
+	second, err := executionDAO.Save(&models.RuleExecution{
+		RuleID: rule.ID,
+		TaskID: 1,
+		Status: models.ExecutionFailed,
+		Error:  "dummy",
+	})
+	require.NoError(t, err)
+	assert.Zero(t, second.EventID)
+	third, err := executionDAO.Save(&models.RuleExecution{RuleID: rule.ID, TaskID: 2, Status: models.ExecutionSkipped})
+	require.NoError(t, err)
+
+	last, err := executionDAO.FindLast(rule.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, second, last)
+
+	byEvent, err := executionDAO.FindByEvent(rule.ID, 7)
+	require.NoError(t, err)
+	assert.Equal(t, first, byEvent)
+	_, err = executionDAO.FindByEvent(rule.ID, 8)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	executions, err := executionDAO.Find(rule.ID, services.Page{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []*models.RuleExecution{third, second}, executions)
+	executions, err = executionDAO.Find(rule.ID, services.Page{After: &services.Cursor{ID: second.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []*models.RuleExecution{first}, executions)
+
+	require.NoError(t, ruleDAO.Delete(rule.ID))
+	executions, err = executionDAO.Find(rule.ID, services.Page{})
+	require.NoError(t, err)
+	assert.Empty(t, executions)
+}
@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+)
+
+// snippetWords is the number of the words of a text kept in its snippet, the snippet
+// starts snippetLead words before the first matched word
+const (
+	snippetWords = 32
+	snippetLead  = 8
+)
+
+// SearchDAO is a data access object for the full-text search of tasks and comments
+type SearchDAO struct {
+	store *Store
+	log   log.Logger
+}
+
+// NewSearchDAO represents a SearchDAO constructor
+func NewSearchDAO(store *Store, log log.Logger) SearchDAO {
+	return SearchDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Find will return the tasks and the comments matching the query of the provided demand
+// that meet its other constraints and fit the provided page, from the highest rank to
+// the lowest. The rank of a record is the number of the matched words in its text.
+func (dao SearchDAO) Find(demand sv.SearchDemand, page sv.Page) ([]*models.SearchHit, error) {
+	defer dao.store.rlock(false)()
+	data := dao.store.data
+
+	text, _ := demand["q"].(string)
+	query := parseQuery(text)
+	boardID, byBoard := demand["board"].(uint)
+	userID, byMember := demand["member"].(uint)
+	found := func(task models.Task) bool {
+		taskBoardID := data.columns[task.ColumnID].BoardID
+		return (!byBoard || taskBoardID == boardID) && (!byMember || data.isMember(taskBoardID, userID))
+	}
+
+	hits := make([]*models.SearchHit, 0)
+	for _, task := range data.tasks {
+		rank := query.rank(task.Name, task.Description)
+		if rank == 0 || !found(task) {
+			continue
+		}
+		snippet := query.snippet(task.Name)
+		if query.rank(task.Description) > query.rank(task.Name) {
+			snippet = query.snippet(task.Description)
+		}
+		hits = append(hits, &models.SearchHit{
+			Type:     models.EntityTask,
+			ID:       task.ID,
+			TaskID:   task.ID,
+			ColumnID: task.ColumnID,
+			BoardID:  data.columns[task.ColumnID].BoardID,
+			Name:     task.Name,
+			Snippet:  snippet,
+			Rank:     float64(rank),
+		})
+	}
+	for _, comment := range data.comments {
+		rank := query.rank(comment.Text)
+		task, ok := data.tasks[comment.TaskID]
+		if rank == 0 || !ok || !found(task) {
+			continue
+		}
+		hits = append(hits, &models.SearchHit{
+			Type:     models.EntityComment,
+			ID:       comment.ID,
+			TaskID:   task.ID,
+			ColumnID: task.ColumnID,
+			BoardID:  data.columns[task.ColumnID].BoardID,
+			Name:     task.Name,
+			Snippet:  query.snippet(comment.Text),
+			Rank:     float64(rank),
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool { return searchLess(hits[i], hits[j]) })
+
+	from, to := paginate(len(hits), page, func(i int) bool {
+		return searchLess(&models.SearchHit{
+			Type: models.Entity(page.After.Type),
+			ID:   page.After.ID,
+			Rank: page.After.Rank,
+		}, hits[i])
+	})
+
+	return hits[from:to], nil
+}
+
+// searchLess reports if the hit a goes before the hit b, the hits are sorted by
+// the rank from the highest to the lowest, by the type and by the ID
+func searchLess(a, b *models.SearchHit) bool {
+	if a.Rank != b.Rank {
+		return a.Rank > b.Rank
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+
+	return a.ID < b.ID
+}
+
+// searchQuery is the list of the lowercased words of a search query. A word of
+// a text matches the query if it starts with one of the query words, so the
+// inflected forms of the words are matched as well.
+type searchQuery []string
+
+// parseQuery returns the search query of the provided text
+func parseQuery(text string) searchQuery {
+	return words(text)
+}
+
+// matches reports if the word matches the query
+func (q searchQuery) matches(word string) bool {
+	word = strings.ToLower(word)
+	for _, prefix := range q {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rank returns the number of the words of the texts matching the query, it is zero
+// unless every word of the query is matched
+func (q searchQuery) rank(texts ...string) int {
+	rank, matched := 0, make(map[string]bool)
+	for _, text := range texts {
+		for _, word := range words(text) {
+			for _, prefix := range q {
+				if strings.HasPrefix(word, prefix) {
+					matched[prefix] = true
+				}
+			}
+			if q.matches(word) {
+				rank++
+			}
+		}
+	}
+	if len(q) == 0 || len(matched) < len(q) {
+		return 0
+	}
+
+	return rank
+}
+
+// snippet returns the part of the text around the first matched word with the
+// matched words highlighted
+func (q searchQuery) snippet(text string) string {
+	type span struct{ from, to int }
+	spans, start := make([]span, 0), -1
+	for i, r := range text {
+		if isWordRune(r) && start < 0 {
+			start = i
+		} else if !isWordRune(r) && start >= 0 {
+			spans, start = append(spans, span{start, i}), -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{start, len(text)})
+	}
+	if len(spans) == 0 {
+		return ""
+	}
+
+	first := 0
+	for first < len(spans) && !q.matches(text[spans[first].from:spans[first].to]) {
+		first++
+	}
+	from := 0
+	if first < len(spans) && first > snippetLead {
+		from = first - snippetLead
+	}
+	to := from + snippetWords
+	if to > len(spans) {
+		to = len(spans)
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("...")
+	}
+	pos := spans[from].from
+	for _, s := range spans[from:to] {
+		b.WriteString(text[pos:s.from])
+		if word := text[s.from:s.to]; q.matches(word) {
+			b.WriteString("<b>" + word + "</b>")
+		} else {
+			b.WriteString(word)
+		}
+		pos = s.to
+	}
+	if to < len(spans) {
+		b.WriteString("...")
+	} else {
+		b.WriteString(text[pos:])
+	}
+
+	return b.String()
+}
+
+// words returns the lowercased words of the text
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !isWordRune(r) })
+}
+
+// isWordRune reports if the rune is a part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
@@ -0,0 +1,106 @@
+// +build unit
+
+package memory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchDAO(t *testing.T) {
+	store := NewStore()
+	user, err := NewUserDAO(store, new(LoggerMock)).Save(&models.User{Email: "john@example.com", Name: "John"})
+	assert.NoError(t, err)
+	boardID, columns := seedColumns(t, store)
+	_, err = NewMemberDAO(store, new(LoggerMock)).Save(&models.Member{BoardID: boardID, UserID: user.ID, Role: models.RoleOwner})
+	assert.NoError(t, err)
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+	release, err := taskDAO.Save(&models.Task{
+		Name:        "Release notes",
+		Description: "Write the notes of the release",
+		ColumnID:    columns[0].ID,
+		Position:    1000,
+	})
+	assert.NoError(t, err)
+	deploy, err := taskDAO.Save(&models.Task{Name: "Deploy", Description: "Deploy to production", ColumnID: columns[1].ID, Position: 1000})
+	assert.NoError(t, err)
+	commentDAO := NewCommentsDAO(store, new(LoggerMock))
+	comment, err := commentDAO.Save(&models.Comment{Text: "Deploy after the release.", TaskID: deploy.ID})
+	assert.NoError(t, err)
+	searchDAO := NewSearchDAO(store, new(LoggerMock))
+
+	hits, err := searchDAO.Find(services.SearchDemand{"q": "release", "member": user.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.SearchHit{
+		{
+			Type:     models.EntityTask,
+			ID:       release.ID,
+			TaskID:   release.ID,
+			ColumnID: columns[0].ID,
+			BoardID:  boardID,
+			Name:     "Release notes",
+			Snippet:  "<b>Release</b> notes",
+			Rank:     2,
+		},
+		{
+			Type:     models.EntityComment,
+			ID:       comment.ID,
+			TaskID:   deploy.ID,
+			ColumnID: columns[1].ID,
+			BoardID:  boardID,
+			Name:     "Deploy",
+			Snippet:  "Deploy after the <b>release</b>.",
+			Rank:     1,
+		},
+	}, hits)
+
+	hits, err = searchDAO.Find(services.SearchDemand{"q": "deploy"}, services.Page{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, deploy.ID, hits[0].ID)
+	hits, err = searchDAO.Find(
+		services.SearchDemand{"q": "deploy"},
+		services.Page{After: &services.Cursor{ID: hits[0].ID, Type: string(hits[0].Type), Rank: hits[0].Rank}},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, comment.ID, hits[0].ID)
+
+	hits, err = searchDAO.Find(services.SearchDemand{"q": "release", "member": user.ID + 1}, services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	hits, err = searchDAO.Find(services.SearchDemand{"q": "release", "board": boardID + 1}, services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+	hits, err = searchDAO.Find(services.SearchDemand{"q": "?!"}, services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+
+	assert.NoError(t, taskDAO.Delete(release.ID))
+	hits, err = searchDAO.Find(services.SearchDemand{"q": "release notes"}, services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, hits)
+
+	tasks, err := taskDAO.Find(services.TaskDemand{"q": "deploying to production"}, services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+	tasks, err = taskDAO.Find(services.TaskDemand{"q": "deploy prod"}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, deploy.ID, tasks[0].ID)
+}
+
+func TestSearchQuery_Snippet(t *testing.T) {
+	text := strings.Repeat("lorem ipsum ", 10) + "the release day, " + strings.Repeat("dolor sit ", 20)
+	snippet := parseQuery("release").snippet(text)
+
+	assert.True(t, strings.HasPrefix(snippet, "...ipsum lorem ipsum"))
+	assert.Contains(t, snippet, "the <b>release</b> day, dolor")
+	assert.True(t, strings.HasSuffix(snippet, "sit..."))
+	assert.Equal(t, snippetWords, len(words(strings.NewReplacer("<b>", "", "</b>", "").Replace(snippet))))
+	assert.Equal(t, "", parseQuery("release").snippet("?!"))
+}
@@ -0,0 +1,310 @@
+package memory
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+)
+
+// Store is a thread-safe in-memory data set shared by the memory DAOs.
+// A transaction started through the connector returned by NewConnector
+// holds the exclusive lock of the store until it is committed or rolled
+// back, so the transactional DAOs (see WithTx) work with the data without
+// locking it again.
+type Store struct {
+	mu   sync.RWMutex
+	data *dataset
+}
+
+// NewStore is a Store constructor
+func NewStore() *Store {
+	return &Store{data: newDataset()}
+}
+
+type sequences struct {
+	boards, columns, tasks, comments, users, labels, activities, webhooks, deliveries, outbox, rules, executions uint
+}
+
+type dataset struct {
+	seq        sequences
+	boards     map[uint]models.Board
+	columns    map[uint]models.Column
+	tasks      map[uint]models.Task
+	comments   map[uint]models.Comment
+	users      map[uint]models.User
+	members    map[memberKey]models.Member
+	labels     map[uint]models.Label
+	webhooks   map[uint]models.Webhook
+	deliveries map[uint]models.Delivery
+	rules      map[uint]models.Rule
+	executions map[uint]models.RuleExecution
+	bin        bin
+	// activities are kept in the order of their IDs
+	activities []models.Activity
+	// outbox is kept in the order of the event IDs
+	outbox []outboxEntry
+	// undo is the log of the running transaction, nil if there is none
+	undo *undoLog
+}
+
+// undoLog holds the state the dataset had before the transaction changed it. The
+// sequences and the headers of the slices are saved on begin, the records are saved
+// right before they are changed, so the transaction costs as much as the changes
+// it makes rather than the size of the data set. The slices are appended to or
+// replaced rather than shrunk in place, so their saved headers keep seeing the
+// records they had, the changed entries of the slices are saved as the records are.
+type undoLog struct {
+	seq        sequences
+	activities []models.Activity
+	outbox     []outboxEntry
+	changes    []change
+}
+
+// change is the saved value of a key of a map or of an index of a slice, the zero
+// value stands for a missing key
+type change struct {
+	table, key, value reflect.Value
+}
+
+// memberKey identifies a membership of a user on a board
+type memberKey struct {
+	boardID, userID uint
+}
+
+func newDataset() *dataset {
+	return &dataset{
+		boards:     make(map[uint]models.Board),
+		columns:    make(map[uint]models.Column),
+		tasks:      make(map[uint]models.Task),
+		comments:   make(map[uint]models.Comment),
+		users:      make(map[uint]models.User),
+		members:    make(map[memberKey]models.Member),
+		labels:     make(map[uint]models.Label),
+		webhooks:   make(map[uint]models.Webhook),
+		deliveries: make(map[uint]models.Delivery),
+		rules:      make(map[uint]models.Rule),
+		executions: make(map[uint]models.RuleExecution),
+		bin:        newBin(),
+	}
+}
+
+// lock acquires the exclusive lock unless the caller is a part of a transaction
+// that already holds it. The returned function releases the lock.
+func (s *Store) lock(inTx bool) func() {
+	if inTx {
+		return func() {}
+	}
+	s.mu.Lock()
+
+	return s.mu.Unlock
+}
+
+// rlock acquires the shared lock unless the caller is a part of a transaction
+// that already holds the exclusive one. The returned function releases the lock.
+func (s *Store) rlock(inTx bool) func() {
+	if inTx {
+		return func() {}
+	}
+	s.mu.RLock()
+
+	return s.mu.RUnlock
+}
+
+// Reset removes all the records and restarts the ID sequences. It is used for
+// end to end tests.
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = newDataset()
+}
+
+func (s *Store) begin() {
+	s.mu.Lock()
+	s.data.undo = &undoLog{
+		seq:        s.data.seq,
+		activities: s.data.activities,
+		outbox:     s.data.outbox,
+	}
+}
+
+func (s *Store) commit() {
+	s.data.undo = nil
+	s.mu.Unlock()
+}
+
+func (s *Store) rollback() {
+	s.data.revert()
+	s.mu.Unlock()
+}
+
+// track saves the current value of the key of the table, which is a map or a
+// slice of the dataset, so that it is restored if the transaction is rolled back.
+// It is called right before the value is changed and does nothing out of transactions.
+func (d *dataset) track(table, key interface{}) {
+	if d.undo == nil {
+		return
+	}
+	t, k := reflect.ValueOf(table), reflect.ValueOf(key)
+	var value reflect.Value
+	if t.Kind() == reflect.Slice {
+		value = reflect.New(t.Type().Elem()).Elem()
+		value.Set(t.Index(int(k.Int())))
+	} else {
+		value = t.MapIndex(k)
+	}
+	d.undo.changes = append(d.undo.changes, change{table: t, key: k, value: value})
+}
+
+// revert restores the dataset to the state it had before the transaction
+func (d *dataset) revert() {
+	for i := len(d.undo.changes) - 1; i >= 0; i-- {
+		c := d.undo.changes[i]
+		if c.table.Kind() == reflect.Slice {
+			c.table.Index(int(c.key.Int())).Set(c.value)
+		} else {
+			c.table.SetMapIndex(c.key, c.value)
+		}
+	}
+	d.seq, d.activities, d.outbox = d.undo.seq, d.undo.activities, d.undo.outbox
+	d.undo = nil
+}
+
+// trashBoard moves the board and all the dependant records to the bin
+func (d *dataset) trashBoard(ID uint, now time.Time) {
+	board, ok := d.boards[ID]
+	if !ok {
+		return
+	}
+	for columnID, column := range d.columns {
+		if column.BoardID == ID {
+			d.trashColumn(columnID, now)
+		}
+	}
+	d.track(d.boards, ID)
+	delete(d.boards, ID)
+	d.track(d.bin.boards, ID)
+	d.bin.boards[ID] = board
+	key := binKey{kind: models.TrashBoard, ID: ID}
+	d.track(d.bin.deletedAt, key)
+	d.bin.deletedAt[key] = now
+}
+
+// trashColumn moves the column and all the dependant records to the bin
+func (d *dataset) trashColumn(ID uint, now time.Time) {
+	column, ok := d.columns[ID]
+	if !ok {
+		return
+	}
+	for taskID, task := range d.tasks {
+		if task.ColumnID == ID {
+			d.trashTask(taskID, now)
+		}
+	}
+	d.track(d.columns, ID)
+	delete(d.columns, ID)
+	d.track(d.bin.columns, ID)
+	d.bin.columns[ID] = column
+	key := binKey{kind: models.TrashColumn, ID: ID}
+	d.track(d.bin.deletedAt, key)
+	d.bin.deletedAt[key] = now
+}
+
+// trashTask moves the task and all the dependant records to the bin
+func (d *dataset) trashTask(ID uint, now time.Time) {
+	task, ok := d.tasks[ID]
+	if !ok {
+		return
+	}
+	for commentID, comment := range d.comments {
+		if comment.TaskID == ID {
+			d.trashComment(commentID, now)
+		}
+	}
+	d.track(d.tasks, ID)
+	delete(d.tasks, ID)
+	d.track(d.bin.tasks, ID)
+	d.bin.tasks[ID] = task
+	key := binKey{kind: models.TrashTask, ID: ID}
+	d.track(d.bin.deletedAt, key)
+	d.bin.deletedAt[key] = now
+}
+
+// trashComment moves the comment to the bin
+func (d *dataset) trashComment(ID uint, now time.Time) {
+	comment, ok := d.comments[ID]
+	if !ok {
+		return
+	}
+	d.track(d.comments, ID)
+	delete(d.comments, ID)
+	d.track(d.bin.comments, ID)
+	d.bin.comments[ID] = comment
+	key := binKey{kind: models.TrashComment, ID: ID}
+	d.track(d.bin.deletedAt, key)
+	d.bin.deletedAt[key] = now
+}
+
+// deleteLabel removes the label and detaches it from the tasks, the deleted ones included
+func (d *dataset) deleteLabel(ID uint) {
+	now := time.Now().UTC()
+	for _, tasks := range []map[uint]models.Task{d.tasks, d.bin.tasks} {
+		for taskID, task := range tasks {
+			if containsID(task.Labels, ID) {
+				labels := make([]uint, 0, len(task.Labels)-1)
+				for _, labelID := range task.Labels {
+					if labelID != ID {
+						labels = append(labels, labelID)
+					}
+				}
+				task.Labels, task.UpdatedAt = labels, now
+				task.Version++
+				d.track(tasks, taskID)
+				tasks[taskID] = task
+			}
+		}
+	}
+	d.track(d.labels, ID)
+	delete(d.labels, ID)
+}
+
+// isMember reports if the user is a member of the board
+func (d *dataset) isMember(boardID, userID uint) bool {
+	_, ok := d.members[memberKey{boardID: boardID, userID: userID}]
+
+	return ok
+}
+
+// findRole returns the role of the user on the board with the provided ID or
+// an empty role if the user is not a member of the board
+func (d *dataset) findRole(boardID, userID uint) (models.Role, error) {
+	if _, ok := d.boards[boardID]; !ok {
+		return "", sv.ErrRecordNotFound
+	}
+
+	return d.members[memberKey{boardID: boardID, userID: userID}].Role, nil
+}
+
+// paginate returns the bounds of the page within the sorted result set of
+// the provided length, isAfter reports if the record with the provided index
+// is placed after the page cursor
+func paginate(num int, page sv.Page, isAfter func(i int) bool) (from, to int) {
+	if page.After != nil {
+		from = sort.Search(num, isAfter)
+	}
+	to = num
+	if page.Limit > 0 && from+int(page.Limit) < num {
+		to = from + int(page.Limit)
+	}
+
+	return from, to
+}
+
+// sameVersion reports if the stored record has the version of the provided one,
+// any version matches the zero version
+func sameVersion(stored, provided models.Model) bool {
+	return provided.Version == 0 || provided.Version == stored.Version
+}
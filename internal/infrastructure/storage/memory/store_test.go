@@ -0,0 +1,113 @@
+// +build unit
+
+package memory
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_Transactions(t *testing.T) {
+	t.Run("commit", func(t *testing.T) {
+		store := NewStore()
+		db := sql.OpenDB(NewConnector(store))
+		defer db.Close()
+
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		boardDAO := NewBoardDAO(store, new(LoggerMock)).WithTx(tx)
+		_, err = boardDAO.Save(&models.Board{Name: "dummy"})
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+
+		boards, err := NewBoardDAO(store, new(LoggerMock)).Find(nil, services.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, boards, 1)
+	})
+	t.Run("rollback", func(t *testing.T) {
+		store := NewStore()
+		db := sql.OpenDB(NewConnector(store))
+		defer db.Close()
+
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		boardDAO := NewBoardDAO(store, new(LoggerMock)).WithTx(tx)
+		_, err = boardDAO.Save(&models.Board{Name: "dummy"})
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Rollback())
+
+		boards, err := NewBoardDAO(store, new(LoggerMock)).Find(nil, services.Page{})
+		assert.NoError(t, err)
+		assert.Len(t, boards, 0)
+	})
+	t.Run("rollback_changes", func(t *testing.T) {
+		store := NewStore()
+		db := sql.OpenDB(NewConnector(store))
+		defer db.Close()
+		board, _ := NewBoardDAO(store, new(LoggerMock)).Save(&models.Board{Name: "dummy"})
+		column, _ := NewColumnDAO(store, new(LoggerMock)).Save(&models.Column{Name: "dummy", BoardID: board.ID, Position: 1})
+		event, _ := NewOutboxDAO(store, new(LoggerMock)).Save(&models.Event{BoardID: board.ID})
+
+		tx, err := db.Begin()
+		assert.NoError(t, err)
+		boardDAO := NewBoardDAO(store, new(LoggerMock)).WithTx(tx)
+		_, err = boardDAO.Update(&models.Board{Model: models.Model{ID: board.ID}, Name: "updated"})
+		assert.NoError(t, err)
+		_, err = boardDAO.Save(&models.Board{Name: "dummy"})
+		assert.NoError(t, err)
+		assert.NoError(t, boardDAO.Delete(board.ID))
+		assert.NoError(t, NewOutboxDAO(store, new(LoggerMock)).WithTx(tx).MarkDelivered(event.ID))
+		assert.NoError(t, tx.Rollback())
+
+		assert.Len(t, store.data.boards, 1)
+		assert.Equal(t, "dummy", store.data.boards[board.ID].Name)
+		assert.Contains(t, store.data.columns, column.ID)
+		assert.Empty(t, store.data.bin.boards)
+		assert.Empty(t, store.data.bin.deletedAt)
+		assert.Nil(t, store.data.outbox[0].deliveredAt)
+		saved, _ := NewBoardDAO(store, new(LoggerMock)).Save(&models.Board{Name: "dummy"})
+		assert.Equal(t, board.ID+1, saved.ID)
+	})
+	t.Run("sql_not_supported", func(t *testing.T) {
+		db := sql.OpenDB(NewConnector(NewStore()))
+		defer db.Close()
+
+		_, err := db.Exec("select 1")
+		assert.Equal(t, ErrNotSupported, err)
+	})
+}
+
+func TestStore_Reset(t *testing.T) {
+	store := NewStore()
+	boardDAO := NewBoardDAO(store, new(LoggerMock))
+	_, err := boardDAO.Save(&models.Board{Name: "dummy"})
+	assert.NoError(t, err)
+
+	store.Reset()
+
+	boards, err := boardDAO.Find(nil, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, boards, 0)
+	board, err := boardDAO.Save(&models.Board{Name: "dummy"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), board.ID)
+}
+
+func TestDataset_Cascade(t *testing.T) {
+	store := NewStore()
+	board, _ := NewBoardDAO(store, new(LoggerMock)).Save(&models.Board{Name: "dummy"})
+	column, _ := NewColumnDAO(store, new(LoggerMock)).Save(&models.Column{Name: "dummy", BoardID: board.ID, Position: 1})
+	task, _ := NewTaskDAO(store, new(LoggerMock)).Save(&models.Task{Name: "dummy", ColumnID: column.ID, Position: 1})
+	_, err := NewCommentsDAO(store, new(LoggerMock)).Save(&models.Comment{Text: "dummy", TaskID: task.ID})
+	assert.NoError(t, err)
+
+	assert.NoError(t, NewBoardDAO(store, new(LoggerMock)).Delete(board.ID))
+	assert.Len(t, store.data.boards, 0)
+	assert.Len(t, store.data.columns, 0)
+	assert.Len(t, store.data.tasks, 0)
+	assert.Len(t, store.data.comments, 0)
+}
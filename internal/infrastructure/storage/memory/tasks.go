@@ -0,0 +1,462 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// TaskDAO is a data access object for tasks
+type TaskDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewTaskDAO represents a TaskDAO constructor
+func NewTaskDAO(store *Store, log log.Logger) TaskDAO {
+	return TaskDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided task and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao TaskDAO) Save(task *models.Task) (*models.Task, error) {
+	if task == nil {
+		dao.log.Error("tasks storage: nil pointer given")
+		return nil, errors.New("nil tasks pointer given")
+	}
+	if task.ID > 0 {
+		dao.log.Warnf("tasks storage: %v, ID: %d", sv.ErrRecordAlreadyExist, task.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	if err := data.checkTaskConstraints(*task); err != nil {
+		return nil, err
+	}
+
+	data.seq.tasks++
+	now := time.Now()
+	task.ID = data.seq.tasks
+	task.CreatedAt, task.UpdatedAt, task.Version = now, now, 1
+	task.Assignees, task.Labels = make([]uint, 0), make([]uint, 0)
+	task.StartAt, task.DueAt = cloneTime(task.StartAt), cloneTime(task.DueAt)
+	if task.Priority == "" {
+		task.Priority = models.PriorityMedium
+	}
+	data.track(data.tasks, task.ID)
+	data.tasks[task.ID] = *task
+
+	return task, nil
+}
+
+// FindOneById will return a pointer to a task with the provided ID or
+// nil and an error
+func (dao TaskDAO) FindOneById(ID uint) (*models.Task, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	task, ok := dao.store.data.tasks[ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+	task.Assignees, task.Labels = cloneIDs(task.Assignees), cloneIDs(task.Labels)
+	task.StartAt, task.DueAt = cloneTime(task.StartAt), cloneTime(task.DueAt)
+
+	return &task, nil
+}
+
+// Find will return all found tasks that meet the provided demand and fit
+// the provided page sorted in the requested order
+func (dao TaskDAO) Find(demand sv.TaskDemand, page sv.Page) ([]*models.Task, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	boardID, byBoard := demand["board"].(uint)
+	columnID, byColumn := demand["column"].(uint)
+	assigneeID, byAssignee := demand["assignee"].(uint)
+	labelID, byLabel := demand["label"].(uint)
+	dueBefore, byDueBefore := demand["due_before"].(time.Time)
+	dueAfter, byDueAfter := demand["due_after"].(time.Time)
+	overdue, byOverdue := demand["overdue"].(bool)
+	priority, byPriority := demand["priority"].(models.Priority)
+	query, byQuery := demand["q"].(string)
+	userID, byMember := demand["member"].(uint)
+	now := time.Now()
+	tasks := make([]*models.Task, 0)
+	for _, task := range data.tasks {
+		if byBoard && data.columns[task.ColumnID].BoardID != boardID {
+			continue
+		}
+		if byColumn && task.ColumnID != columnID {
+			continue
+		}
+		if byAssignee && !containsID(task.Assignees, assigneeID) {
+			continue
+		}
+		if byLabel && !containsID(task.Labels, labelID) {
+			continue
+		}
+		if byDueBefore && (task.DueAt == nil || !task.DueAt.Before(dueBefore)) {
+			continue
+		}
+		if byDueAfter && (task.DueAt == nil || !task.DueAt.After(dueAfter)) {
+			continue
+		}
+		if byOverdue && overdue != (task.DueAt != nil && task.DueAt.Before(now)) {
+			continue
+		}
+		if byPriority && task.Priority != priority {
+			continue
+		}
+		if byQuery && parseQuery(query).rank(task.Name, task.Description) == 0 {
+			continue
+		}
+		if byMember && !data.isMember(data.columns[task.ColumnID].BoardID, userID) {
+			continue
+		}
+		task := task
+		task.Assignees, task.Labels = cloneIDs(task.Assignees), cloneIDs(task.Labels)
+		task.StartAt, task.DueAt = cloneTime(task.StartAt), cloneTime(task.DueAt)
+		tasks = append(tasks, &task)
+	}
+	order := demand.Sort()
+	sort.Slice(tasks, func(i, j int) bool { return taskLess(order, tasks[i], tasks[j]) })
+
+	from, to := paginate(len(tasks), page, func(i int) bool {
+		after := &models.Task{
+			Model:    models.Model{ID: page.After.ID},
+			Position: page.After.Position,
+			Priority: page.After.Priority,
+			DueAt:    page.After.DueAt,
+		}
+		return taskLess(order, after, tasks[i])
+	})
+
+	return tasks[from:to], nil
+}
+
+// Update will update the name, the description, the position, the column and the dates
+// of the task and bump its version, the reporter and the priority of the task are updated
+// only if new ones are provided. The task is updated only if it has the version of the
+// provided one, unless it is zero
+func (dao TaskDAO) Update(task *models.Task) (*models.Task, error) {
+	if task == nil {
+		dao.log.Error("tasks storage: nil pointer given")
+		return nil, errors.New("nil tasks pointer given")
+	}
+
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	stored, ok := data.tasks[task.ID]
+	if !ok || !sameVersion(stored.Model, task.Model) {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	stored.Name = task.Name
+	stored.Description = task.Description
+	stored.Position = task.Position
+	stored.ColumnID = task.ColumnID
+	stored.StartAt, stored.DueAt = cloneTime(task.StartAt), cloneTime(task.DueAt)
+	if task.ReporterID != 0 {
+		stored.ReporterID = task.ReporterID
+	}
+	if task.Priority != "" {
+		stored.Priority = task.Priority
+	}
+	if err := data.checkTaskConstraints(stored); err != nil {
+		return nil, err
+	}
+
+	stored.UpdatedAt = time.Now()
+	stored.Version++
+	data.track(data.tasks, task.ID)
+	data.tasks[task.ID] = stored
+	*task = stored
+	task.Assignees, task.Labels = cloneIDs(stored.Assignees), cloneIDs(stored.Labels)
+	task.StartAt, task.DueAt = cloneTime(stored.StartAt), cloneTime(stored.DueAt)
+
+	return task, nil
+}
+
+// SetAssignees will replace the assignees of the task with the provided ID
+func (dao TaskDAO) SetAssignees(taskID uint, userIDs []uint) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	task, ok := data.tasks[taskID]
+	if !ok {
+		return sv.ErrRecordNotFound
+	}
+	for _, userID := range userIDs {
+		if _, ok := data.users[userID]; !ok {
+			return sv.ErrUserRelation
+		}
+	}
+
+	task.Assignees = cloneIDs(userIDs)
+	sort.Slice(task.Assignees, func(i, j int) bool { return task.Assignees[i] < task.Assignees[j] })
+	data.track(data.tasks, taskID)
+	data.tasks[taskID] = task
+
+	return nil
+}
+
+// SetLabels will replace the labels of the task with the provided ID,
+// the labels must belong to the board of the task
+func (dao TaskDAO) SetLabels(taskID uint, labelIDs []uint) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	task, ok := data.tasks[taskID]
+	if !ok {
+		return sv.ErrRecordNotFound
+	}
+	for _, labelID := range labelIDs {
+		label, ok := data.labels[labelID]
+		if !ok || label.BoardID != data.columns[task.ColumnID].BoardID {
+			return sv.ErrLabelRelation
+		}
+	}
+
+	task.Labels = cloneIDs(labelIDs)
+	sort.Slice(task.Labels, func(i, j int) bool { return task.Labels[i] < task.Labels[j] })
+	data.track(data.tasks, taskID)
+	data.tasks[taskID] = task
+
+	return nil
+}
+
+// MatchLabels will replace the labels of the task with the labels of the same names
+// on the board of its column, the labels without a match are dropped
+func (dao TaskDAO) MatchLabels(taskID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	task, ok := data.tasks[taskID]
+	if !ok {
+		return sv.ErrRecordNotFound
+	}
+	boardID := data.columns[task.ColumnID].BoardID
+	onBoard := make(map[string]uint)
+	for _, label := range data.labels {
+		if label.BoardID == boardID {
+			onBoard[label.Name] = label.ID
+		}
+	}
+
+	matched := make([]uint, 0, len(task.Labels))
+	for _, labelID := range task.Labels {
+		if ID, ok := onBoard[data.labels[labelID].Name]; ok {
+			matched = append(matched, ID)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return matched[i] < matched[j] })
+	task.Labels = matched
+	data.track(data.tasks, taskID)
+	data.tasks[taskID] = task
+
+	return nil
+}
+
+// MoveToColumn will move all tasks from source column to target column
+func (dao TaskDAO) MoveToColumn(sourceID, targetID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	if _, ok := data.columns[targetID]; !ok {
+		return sv.ErrColumnRelation
+	}
+
+	moved := make(map[uint]models.Task)
+	for ID, task := range data.tasks {
+		if task.ColumnID == sourceID {
+			task.ColumnID = targetID
+			task.Version++
+			moved[ID] = task
+		}
+	}
+	for _, task := range moved {
+		if err := data.checkTaskConstraints(task); err != nil {
+			dao.log.Errorf(
+				"tasks storage: error while moving tasks from column %d to column %d: %v",
+				sourceID,
+				targetID,
+				err,
+			)
+			return err
+		}
+	}
+	for ID, task := range moved {
+		data.track(data.tasks, ID)
+		data.tasks[ID] = task
+	}
+
+	return nil
+}
+
+// ColumnLoad will return the WIP limit of the column with the provided ID and the
+// number of tasks in it. A transaction holds the exclusive lock of the store, so
+// that concurrent transactions can not add tasks to the column meanwhile.
+func (dao TaskDAO) ColumnLoad(columnID uint) (limit, count uint, err error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	column, ok := data.columns[columnID]
+	if !ok {
+		return 0, 0, sv.ErrColumnRelation
+	}
+	for _, task := range data.tasks {
+		if task.ColumnID == columnID {
+			count++
+		}
+	}
+
+	return column.WIPLimit, count, nil
+}
+
+// ColumnBoard will return the ID of the board of the column with the provided ID
+func (dao TaskDAO) ColumnBoard(columnID uint) (uint, error) {
+	defer dao.store.rlock(dao.inTx)()
+
+	column, ok := dao.store.data.columns[columnID]
+	if !ok {
+		return 0, sv.ErrColumnRelation
+	}
+
+	return column.BoardID, nil
+}
+
+// Rebalance will place the tasks with the provided IDs into the column in the given
+// order, with the positions evenly spread by the step
+func (dao TaskDAO) Rebalance(columnID uint, IDs []uint, step float64) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	if _, ok := data.columns[columnID]; !ok {
+		return sv.ErrColumnRelation
+	}
+	now := time.Now()
+	moved := make(map[uint]models.Task, len(IDs))
+	for i, ID := range IDs {
+		task, ok := data.tasks[ID]
+		if !ok {
+			return sv.ErrRecordNotFound
+		}
+		task.ColumnID, task.Position, task.UpdatedAt = columnID, step*float64(i+1), now
+		task.Version++
+		moved[ID] = task
+	}
+	previous := make(map[uint]models.Task, len(moved))
+	for ID, task := range moved {
+		data.track(data.tasks, ID)
+		previous[ID], data.tasks[ID] = data.tasks[ID], task
+	}
+	for _, task := range moved {
+		if err := data.checkTaskConstraints(task); err != nil {
+			for ID, task := range previous {
+				data.tasks[ID] = task
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete will move the task to the bin as well as all dependant records
+func (dao TaskDAO) Delete(ID uint) error {
+	defer dao.store.lock(dao.inTx)()
+	dao.store.data.trashTask(ID, time.Now())
+
+	return nil
+}
+
+// WithTx will return the TaskDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao TaskDAO) WithTx(*sql.Tx) sv.TaskStorage {
+	dao.inTx = true
+	return dao
+}
+
+// checkTaskConstraints emulates the column and the reporter foreign keys and
+// unique (position, column)
+func (d *dataset) checkTaskConstraints(task models.Task) error {
+	if _, ok := d.columns[task.ColumnID]; !ok {
+		return sv.ErrColumnRelation
+	}
+	if _, ok := d.users[task.ReporterID]; task.ReporterID != 0 && !ok {
+		return sv.ErrUserRelation
+	}
+	for _, t := range d.tasks {
+		if t.ID != task.ID && t.ColumnID == task.ColumnID && t.Position == task.Position {
+			return sv.ErrPositionDuplicate
+		}
+	}
+
+	return nil
+}
+
+// taskLess reports if the task a goes before the task b in the provided sort
+// order, the tasks without a due date go last in both directions
+func taskLess(order sv.Sort, a, b *models.Task) bool {
+	for _, key := range order {
+		var less, greater bool
+		switch key.Field {
+		case "position":
+			less, greater = a.Position < b.Position, a.Position > b.Position
+		case "priority":
+			less, greater = a.Priority.Rank() < b.Priority.Rank(), a.Priority.Rank() > b.Priority.Rank()
+		case "due":
+			if a.DueAt == nil || b.DueAt == nil {
+				if a.DueAt != b.DueAt {
+					return b.DueAt == nil
+				}
+				continue
+			}
+			less, greater = a.DueAt.Before(*b.DueAt), a.DueAt.After(*b.DueAt)
+		}
+		if less || greater {
+			return less != key.Desc
+		}
+	}
+
+	return a.ID < b.ID
+}
+
+// cloneIDs returns a copy of the provided IDs, so the stored records do not
+// share memory with the returned ones
+func cloneIDs(IDs []uint) []uint {
+	return append(make([]uint, 0, len(IDs)), IDs...)
+}
+
+// cloneTime returns a copy of the provided optional time
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+
+	return &c
+}
+
+// containsID reports if the provided IDs contain the given one
+func containsID(IDs []uint, ID uint) bool {
+	for _, v := range IDs {
+		if v == ID {
+			return true
+		}
+	}
+
+	return false
+}
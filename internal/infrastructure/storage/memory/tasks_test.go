@@ -0,0 +1,342 @@
+// +build unit
+
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTaskDAO_Save(t *testing.T) {
+	t.Run("error_on_existing_ID", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+		res, err := NewTaskDAO(NewStore(), logger).Save(&models.Task{Model: models.Model{ID: 1}})
+
+		assert.Nil(t, res)
+		assert.Equal(t, services.ErrRecordAlreadyExist, err)
+	})
+	t.Run("constraints", func(t *testing.T) {
+		store := NewStore()
+		_, columns := seedColumns(t, store)
+		taskDAO := NewTaskDAO(store, new(LoggerMock))
+
+		_, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: 100, Position: 1})
+		assert.Equal(t, services.ErrColumnRelation, err)
+
+		_, err = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1})
+		assert.NoError(t, err)
+
+		_, err = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1})
+		assert.Equal(t, services.ErrPositionDuplicate, err)
+
+		_, err = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[1].ID, Position: 1})
+		assert.NoError(t, err)
+	})
+}
+
+func TestTaskDAO_Find(t *testing.T) {
+	store := NewStore()
+	boardID, columns := seedColumns(t, store)
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+	for i, position := range []float64{3, 1, 2} {
+		_, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[i].ID, Position: position})
+		assert.NoError(t, err)
+	}
+
+	tasks, err := taskDAO.Find(services.TaskDemand{"board": boardID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 3)
+	assert.Equal(t, []float64{1, 2, 3}, []float64{tasks[0].Position, tasks[1].Position, tasks[2].Position})
+
+	tasks, err = taskDAO.Find(services.TaskDemand{"column": columns[0].ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+
+	tasks, err = taskDAO.Find(services.TaskDemand{"board": boardID + 1}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 0)
+}
+
+func TestTaskDAO_MoveToColumn(t *testing.T) {
+	store := NewStore()
+	_, columns := seedColumns(t, store)
+	logger := new(LoggerMock)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+	taskDAO := NewTaskDAO(store, logger)
+
+	_, _ = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1})
+	_, _ = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[1].ID, Position: 1})
+	_, _ = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[2].ID, Position: 2})
+
+	assert.Equal(t, services.ErrPositionDuplicate, taskDAO.MoveToColumn(columns[0].ID, columns[1].ID))
+	assert.NoError(t, taskDAO.MoveToColumn(columns[0].ID, columns[2].ID))
+
+	tasks, err := taskDAO.Find(services.TaskDemand{"column": columns[2].ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+}
+
+func TestTaskDAO_Assignees(t *testing.T) {
+	store := NewStore()
+	_, columns := seedColumns(t, store)
+	userDAO := NewUserDAO(store, new(LoggerMock))
+	users := make([]uint, 0)
+	for _, email := range []string{"john@example.com", "jane@example.com"} {
+		user, err := userDAO.Save(&models.User{Email: email, Name: "dummy", PasswordHash: "hash"})
+		assert.NoError(t, err)
+		users = append(users, user.ID)
+	}
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+
+	task, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1, ReporterID: users[0]})
+	assert.NoError(t, err)
+	_, err = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 2})
+	assert.NoError(t, err)
+
+	assert.Equal(t, services.ErrUserRelation, taskDAO.SetAssignees(task.ID, []uint{users[1] + 1}))
+	assert.NoError(t, taskDAO.SetAssignees(task.ID, []uint{users[1], users[0]}))
+
+	found, err := taskDAO.FindOneById(task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, users[0], found.ReporterID)
+	assert.Equal(t, users, found.Assignees)
+
+	tasks, err := taskDAO.Find(services.TaskDemand{"assignee": users[1]}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, users, tasks[0].Assignees)
+
+	found.ReporterID = 0
+	updated, err := taskDAO.Update(found)
+	assert.NoError(t, err)
+	assert.Equal(t, users[0], updated.ReporterID)
+
+	assert.NoError(t, taskDAO.SetAssignees(task.ID, nil))
+	found, err = taskDAO.FindOneById(task.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, found.Assignees)
+}
+
+func TestTaskDAO_Dates(t *testing.T) {
+	store := NewStore()
+	_, columns := seedColumns(t, store)
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+	now := time.Now().UTC()
+	past, future := now.Add(-48*time.Hour), now.Add(48*time.Hour)
+	start := past.Add(-time.Hour)
+
+	overdue, err := taskDAO.Save(&models.Task{Name: "overdue", ColumnID: columns[0].ID, Position: 1, StartAt: &start, DueAt: &past})
+	assert.NoError(t, err)
+	assert.WithinDuration(t, past, *overdue.DueAt, time.Second)
+	assert.WithinDuration(t, start, *overdue.StartAt, time.Second)
+	upcoming, err := taskDAO.Save(&models.Task{Name: "upcoming", ColumnID: columns[0].ID, Position: 2, DueAt: &future})
+	assert.NoError(t, err)
+	undated, err := taskDAO.Save(&models.Task{Name: "undated", ColumnID: columns[0].ID, Position: 3})
+	assert.NoError(t, err)
+	assert.Nil(t, undated.DueAt)
+
+	tests := []struct {
+		name   string
+		demand services.TaskDemand
+		names  []string
+	}{
+		{"due_before", services.TaskDemand{"due_before": now}, []string{overdue.Name}},
+		{"due_after", services.TaskDemand{"due_after": now}, []string{upcoming.Name}},
+		{"due_between", services.TaskDemand{"due_after": past.Add(-time.Hour), "due_before": future.Add(time.Hour)}, []string{overdue.Name, upcoming.Name}},
+		{"overdue", services.TaskDemand{"overdue": true}, []string{overdue.Name}},
+		{"not_overdue", services.TaskDemand{"overdue": false}, []string{upcoming.Name, undated.Name}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := taskDAO.Find(tt.demand, services.Page{})
+			assert.NoError(t, err)
+			names := make([]string, 0)
+			for _, task := range tasks {
+				names = append(names, task.Name)
+			}
+			assert.Equal(t, tt.names, names)
+		})
+	}
+
+	upcoming.DueAt = nil
+	updated, err := taskDAO.Update(upcoming)
+	assert.NoError(t, err)
+	assert.Nil(t, updated.DueAt)
+}
+
+func TestTaskDAO_Sort(t *testing.T) {
+	store := NewStore()
+	_, columns := seedColumns(t, store)
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+	early := time.Now().UTC().Add(time.Hour)
+	late := early.Add(time.Hour)
+
+	for _, task := range []*models.Task{
+		{Name: "a", Position: 1, Priority: models.PriorityLow, DueAt: &late},
+		{Name: "b", Position: 2, Priority: models.PriorityHigh},
+		{Name: "c", Position: 3, Priority: models.PriorityHigh, DueAt: &early},
+		{Name: "d", Position: 4, Priority: models.PriorityLow},
+		{Name: "e", Position: 5, DueAt: &early},
+	} {
+		task.ColumnID = columns[0].ID
+		_, err := taskDAO.Save(task)
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		sort  string
+		names []string
+	}{
+		{"position", []string{"a", "b", "c", "d", "e"}},
+		{"-priority,due", []string{"c", "b", "e", "a", "d"}},
+		{"priority,-due", []string{"a", "d", "e", "c", "b"}},
+		{"due,-position", []string{"e", "c", "a", "d", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			demand := make(services.TaskDemand)
+			assert.NoError(t, demand.Add("sort", tt.sort))
+
+			names, page := make([]string, 0), services.Page{Limit: 2}
+			for {
+				tasks, err := taskDAO.Find(demand, page)
+				assert.NoError(t, err)
+				if len(tasks) == 0 {
+					break
+				}
+				for _, task := range tasks {
+					names = append(names, task.Name)
+				}
+				last := tasks[len(tasks)-1]
+				page.After = &services.Cursor{ID: last.ID, Position: last.Position, Priority: last.Priority, DueAt: last.DueAt}
+			}
+			assert.Equal(t, tt.names, names)
+		})
+	}
+
+	tasks, err := taskDAO.Find(services.TaskDemand{"priority": models.PriorityMedium}, services.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 1) {
+		assert.Equal(t, "e", tasks[0].Name)
+	}
+
+	tasks[0].Priority = ""
+	updated, err := taskDAO.Update(tasks[0])
+	assert.NoError(t, err)
+	assert.Equal(t, models.PriorityMedium, updated.Priority)
+}
+
+func TestTaskDAO_ColumnLoad(t *testing.T) {
+	store := NewStore()
+	boardID, columns := seedColumns(t, store)
+	columnDAO := NewColumnDAO(store, new(LoggerMock))
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+
+	column := columns[0]
+	column.WIPLimit = 2
+	_, err := columnDAO.Update(column)
+	assert.NoError(t, err)
+	stored, err := columnDAO.FindOneById(column.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), stored.WIPLimit)
+
+	for _, position := range []float64{1, 2} {
+		_, err = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: column.ID, Position: position})
+		assert.NoError(t, err)
+	}
+
+	limit, count, err := taskDAO.ColumnLoad(column.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), limit)
+	assert.Equal(t, uint(2), count)
+
+	limit, count, err = taskDAO.ColumnLoad(columns[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), limit)
+	assert.Equal(t, uint(0), count)
+
+	_, _, err = taskDAO.ColumnLoad(columns[2].ID + 10)
+	assert.Equal(t, services.ErrColumnRelation, err)
+
+	board, err := taskDAO.ColumnBoard(column.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, boardID, board)
+	_, err = taskDAO.ColumnBoard(columns[2].ID + 10)
+	assert.Equal(t, services.ErrColumnRelation, err)
+}
+
+func TestTaskDAO_Rebalance(t *testing.T) {
+	store := NewStore()
+	_, columns := seedColumns(t, store)
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+
+	source, target := columns[0], columns[1]
+	IDs := make([]uint, 0)
+	for _, position := range []float64{1, 1.5, 2} {
+		task, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: target.ID, Position: position})
+		assert.NoError(t, err)
+		IDs = append(IDs, task.ID)
+	}
+	moved, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: source.ID, Position: 1000})
+	assert.NoError(t, err)
+
+	order := []uint{IDs[0], moved.ID, IDs[1], IDs[2]}
+	assert.NoError(t, taskDAO.Rebalance(target.ID, order, 1000))
+
+	tasks, err := taskDAO.Find(services.TaskDemand{"column": target.ID}, services.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 4) {
+		for i, task := range tasks {
+			assert.Equal(t, order[i], task.ID)
+			assert.Equal(t, target.ID, task.ColumnID)
+			assert.Equal(t, float64(1000*(i+1)), task.Position)
+		}
+	}
+}
+
+func TestTaskDAO_MatchLabels(t *testing.T) {
+	store := NewStore()
+	boardDAO := NewBoardDAO(store, new(LoggerMock))
+	columnDAO := NewColumnDAO(store, new(LoggerMock))
+	labelDAO := NewLabelDAO(store, new(LoggerMock))
+	taskDAO := NewTaskDAO(store, new(LoggerMock))
+
+	columns := make([]*models.Column, 0)
+	labels := make(map[string]uint)
+	for _, name := range []string{"source", "target"} {
+		board, err := boardDAO.Save(&models.Board{Name: name})
+		assert.NoError(t, err)
+		column, err := columnDAO.Save(&models.Column{Name: name, BoardID: board.ID, Position: 1})
+		assert.NoError(t, err)
+		columns = append(columns, column)
+		for _, labelName := range []string{"bug", name} {
+			label, err := labelDAO.Save(&models.Label{Name: labelName, Color: "#ff0000", BoardID: board.ID})
+			assert.NoError(t, err)
+			labels[name+"/"+labelName] = label.ID
+		}
+	}
+
+	task, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, taskDAO.SetLabels(task.ID, []uint{labels["source/bug"], labels["source/source"]}))
+
+	assert.NoError(t, taskDAO.MatchLabels(task.ID))
+	stored, err := taskDAO.FindOneById(task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{labels["source/bug"], labels["source/source"]}, stored.Labels)
+
+	stored.ColumnID = columns[1].ID
+	_, err = taskDAO.Update(stored)
+	assert.NoError(t, err)
+	assert.NoError(t, taskDAO.MatchLabels(task.ID))
+	stored, err = taskDAO.FindOneById(task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{labels["target/bug"]}, stored.Labels)
+}
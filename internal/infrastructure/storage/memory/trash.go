@@ -0,0 +1,385 @@
+package memory
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+)
+
+// binKey identifies a deleted record
+type binKey struct {
+	kind models.TrashType
+	ID   uint
+}
+
+// bin holds the deleted records apart from the alive ones, so that the DAOs
+// do not see them until they are restored
+type bin struct {
+	boards    map[uint]models.Board
+	columns   map[uint]models.Column
+	tasks     map[uint]models.Task
+	comments  map[uint]models.Comment
+	deletedAt map[binKey]time.Time
+}
+
+func newBin() bin {
+	return bin{
+		boards:    make(map[uint]models.Board),
+		columns:   make(map[uint]models.Column),
+		tasks:     make(map[uint]models.Task),
+		comments:  make(map[uint]models.Comment),
+		deletedAt: make(map[binKey]time.Time),
+	}
+}
+
+// TrashDAO is a data access object for the deleted boards, columns, tasks and comments
+type TrashDAO struct {
+	store *Store
+	inTx  bool
+	log   log.Logger
+}
+
+// NewTrashDAO represents a TrashDAO constructor
+func NewTrashDAO(store *Store, log log.Logger) TrashDAO {
+	return TrashDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Find will return the deleted records that meet the provided demand and fit the provided
+// page. The records deleted along with their parents are not listed.
+func (dao TrashDAO) Find(demand sv.TrashDemand, page sv.Page) ([]*models.TrashItem, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	boardID, byBoard := demand["board"].(uint)
+	kind, byType := demand["type"].(models.TrashType)
+	userID, byMember := demand["member"].(uint)
+	items := make([]*models.TrashItem, 0)
+	for key := range data.bin.deletedAt {
+		item, parentDeleted := data.trashItem(key)
+		if parentDeleted {
+			continue
+		}
+		if byBoard && item.BoardID != boardID {
+			continue
+		}
+		if byType && item.Type != kind {
+			continue
+		}
+		if byMember && !data.isMember(item.BoardID, userID) {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return trashLess(items[i], items[j]) })
+
+	from, to := paginate(len(items), page, func(i int) bool {
+		if page.After.DeletedAt == nil {
+			return false
+		}
+		return trashLess(&models.TrashItem{
+			Type:      models.TrashType(page.After.Type),
+			ID:        page.After.ID,
+			DeletedAt: *page.After.DeletedAt,
+		}, items[i])
+	})
+
+	return items[from:to], nil
+}
+
+// FindOne will return the deleted record of the provided type with the provided ID
+// or ErrRecordNotFound if there is no such deleted record
+func (dao TrashDAO) FindOne(kind models.TrashType, ID uint) (*models.TrashItem, error) {
+	defer dao.store.rlock(dao.inTx)()
+	data := dao.store.data
+
+	key := binKey{kind: kind, ID: ID}
+	if _, ok := data.bin.deletedAt[key]; !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+	item, _ := data.trashItem(key)
+
+	return item, nil
+}
+
+// Restore will restore the deleted record of the provided type with the provided ID
+// along with the records deleted with it. A restored column or task is placed after
+// the last one by the provided step if its position has been taken meanwhile.
+func (dao TrashDAO) Restore(kind models.TrashType, ID uint, step float64) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	key := binKey{kind: kind, ID: ID}
+	deletedAt, ok := data.bin.deletedAt[key]
+	if !ok {
+		return sv.ErrRecordNotFound
+	}
+	if _, parentDeleted := data.trashItem(key); parentDeleted {
+		return sv.ErrTrashedParent
+	}
+
+	switch kind {
+	case models.TrashColumn:
+		column := data.bin.columns[ID]
+		if data.columnPositionTaken(column) {
+			column.Position = data.lastColumnPosition(column.BoardID) + step
+			column.Version++
+		}
+		if err := data.checkColumnConstraints(column); err != nil {
+			return err
+		}
+		data.track(data.bin.columns, ID)
+		data.bin.columns[ID] = column
+	case models.TrashTask:
+		task := data.bin.tasks[ID]
+		if data.taskPositionTaken(task) {
+			task.Position = data.lastTaskPosition(task.ColumnID) + step
+			task.Version++
+		}
+		data.track(data.bin.tasks, ID)
+		data.bin.tasks[ID] = task
+	}
+	data.restore(key, deletedAt)
+
+	return nil
+}
+
+// Purge will permanently delete the records deleted before the provided time along
+// with all the dependant records
+func (dao TrashDAO) Purge(before time.Time) error {
+	defer dao.store.lock(dao.inTx)()
+	data := dao.store.data
+
+	for key, deletedAt := range data.bin.deletedAt {
+		if deletedAt.Before(before) {
+			data.purge(key)
+		}
+	}
+
+	return nil
+}
+
+// WithTx will return the TrashDAO that will work within the provided transaction.
+// The transaction must be started with a *sql.DB opened by the store connector.
+func (dao TrashDAO) WithTx(*sql.Tx) sv.TrashStorage {
+	dao.inTx = true
+	return dao
+}
+
+// trashItem describes the deleted record with the provided key and reports if its
+// parent is deleted as well
+func (d *dataset) trashItem(key binKey) (*models.TrashItem, bool) {
+	item := &models.TrashItem{Type: key.kind, ID: key.ID, DeletedAt: d.bin.deletedAt[key]}
+	var parentDeleted bool
+	switch key.kind {
+	case models.TrashBoard:
+		item.Name, item.BoardID = d.bin.boards[key.ID].Name, key.ID
+	case models.TrashColumn:
+		column := d.bin.columns[key.ID]
+		_, alive := d.boards[column.BoardID]
+		item.Name, item.BoardID, item.ParentID, parentDeleted = column.Name, column.BoardID, column.BoardID, !alive
+	case models.TrashTask:
+		task := d.bin.tasks[key.ID]
+		_, alive := d.columns[task.ColumnID]
+		item.Name, item.BoardID, item.ParentID, parentDeleted = task.Name, d.columnBoard(task.ColumnID), task.ColumnID, !alive
+	case models.TrashComment:
+		comment := d.bin.comments[key.ID]
+		task, alive := d.tasks[comment.TaskID]
+		if !alive {
+			task = d.bin.tasks[comment.TaskID]
+		}
+		item.Name, item.BoardID, item.ParentID, parentDeleted = comment.Text, d.columnBoard(task.ColumnID), comment.TaskID, !alive
+	}
+
+	return item, parentDeleted
+}
+
+// columnBoard returns the board ID of the column with the provided ID, which may be deleted
+func (d *dataset) columnBoard(columnID uint) uint {
+	if column, ok := d.columns[columnID]; ok {
+		return column.BoardID
+	}
+
+	return d.bin.columns[columnID].BoardID
+}
+
+// restore moves the record with the provided key back from the bin along with the
+// dependant records deleted at the same time
+func (d *dataset) restore(key binKey, deletedAt time.Time) {
+	if at, ok := d.bin.deletedAt[key]; !ok || !at.Equal(deletedAt) {
+		return
+	}
+	d.track(d.bin.deletedAt, key)
+	delete(d.bin.deletedAt, key)
+
+	switch key.kind {
+	case models.TrashBoard:
+		d.track(d.boards, key.ID)
+		d.boards[key.ID] = d.bin.boards[key.ID]
+		d.track(d.bin.boards, key.ID)
+		delete(d.bin.boards, key.ID)
+		for ID, column := range d.bin.columns {
+			if column.BoardID == key.ID {
+				d.restore(binKey{kind: models.TrashColumn, ID: ID}, deletedAt)
+			}
+		}
+	case models.TrashColumn:
+		d.track(d.columns, key.ID)
+		d.columns[key.ID] = d.bin.columns[key.ID]
+		d.track(d.bin.columns, key.ID)
+		delete(d.bin.columns, key.ID)
+		for ID, task := range d.bin.tasks {
+			if task.ColumnID == key.ID {
+				d.restore(binKey{kind: models.TrashTask, ID: ID}, deletedAt)
+			}
+		}
+	case models.TrashTask:
+		d.track(d.tasks, key.ID)
+		d.tasks[key.ID] = d.bin.tasks[key.ID]
+		d.track(d.bin.tasks, key.ID)
+		delete(d.bin.tasks, key.ID)
+		for ID, comment := range d.bin.comments {
+			if comment.TaskID == key.ID {
+				d.restore(binKey{kind: models.TrashComment, ID: ID}, deletedAt)
+			}
+		}
+	case models.TrashComment:
+		d.track(d.comments, key.ID)
+		d.comments[key.ID] = d.bin.comments[key.ID]
+		d.track(d.bin.comments, key.ID)
+		delete(d.bin.comments, key.ID)
+	}
+}
+
+// purge removes the deleted record with the provided key along with all the dependant
+// records, the members, the labels, the webhooks, the automation rules and the activity
+// of a purged board are removed as well
+func (d *dataset) purge(key binKey) {
+	d.track(d.bin.deletedAt, key)
+	delete(d.bin.deletedAt, key)
+
+	switch key.kind {
+	case models.TrashBoard:
+		d.track(d.bin.boards, key.ID)
+		delete(d.bin.boards, key.ID)
+		for ID, column := range d.bin.columns {
+			if column.BoardID == key.ID {
+				d.purge(binKey{kind: models.TrashColumn, ID: ID})
+			}
+		}
+		for memberKey := range d.members {
+			if memberKey.boardID == key.ID {
+				d.track(d.members, memberKey)
+				delete(d.members, memberKey)
+			}
+		}
+		for labelID, label := range d.labels {
+			if label.BoardID == key.ID {
+				d.deleteLabel(labelID)
+			}
+		}
+		for webhookID, webhook := range d.webhooks {
+			if webhook.BoardID == key.ID {
+				d.deleteWebhook(webhookID)
+			}
+		}
+		for ruleID, rule := range d.rules {
+			if rule.BoardID == key.ID {
+				d.deleteRule(ruleID)
+			}
+		}
+		// the activities are filtered into a new slice, as a rolled back transaction
+		// restores the previous one
+		activities := make([]models.Activity, 0, len(d.activities))
+		for _, entry := range d.activities {
+			if entry.BoardID != key.ID {
+				activities = append(activities, entry)
+			}
+		}
+		d.activities = activities
+	case models.TrashColumn:
+		d.track(d.bin.columns, key.ID)
+		delete(d.bin.columns, key.ID)
+		for ID, task := range d.bin.tasks {
+			if task.ColumnID == key.ID {
+				d.purge(binKey{kind: models.TrashTask, ID: ID})
+			}
+		}
+	case models.TrashTask:
+		d.track(d.bin.tasks, key.ID)
+		delete(d.bin.tasks, key.ID)
+		for ID, comment := range d.bin.comments {
+			if comment.TaskID == key.ID {
+				d.purge(binKey{kind: models.TrashComment, ID: ID})
+			}
+		}
+	case models.TrashComment:
+		d.track(d.bin.comments, key.ID)
+		delete(d.bin.comments, key.ID)
+	}
+}
+
+// columnPositionTaken reports if an alive column of the board holds the position of the provided one
+func (d *dataset) columnPositionTaken(column models.Column) bool {
+	for _, c := range d.columns {
+		if c.ID != column.ID && c.BoardID == column.BoardID && c.Position == column.Position {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lastColumnPosition returns the highest position of the alive columns of the board
+func (d *dataset) lastColumnPosition(boardID uint) float64 {
+	var last float64
+	for _, c := range d.columns {
+		if c.BoardID == boardID && c.Position > last {
+			last = c.Position
+		}
+	}
+
+	return last
+}
+
+// taskPositionTaken reports if an alive task of the column holds the position of the provided one
+func (d *dataset) taskPositionTaken(task models.Task) bool {
+	for _, t := range d.tasks {
+		if t.ID != task.ID && t.ColumnID == task.ColumnID && t.Position == task.Position {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lastTaskPosition returns the highest position of the alive tasks of the column
+func (d *dataset) lastTaskPosition(columnID uint) float64 {
+	var last float64
+	for _, t := range d.tasks {
+		if t.ColumnID == columnID && t.Position > last {
+			last = t.Position
+		}
+	}
+
+	return last
+}
+
+// trashLess reports if the deleted record a goes before the record b, that is from
+// the newest to the oldest, then by type and ID
+func trashLess(a, b *models.TrashItem) bool {
+	if !a.DeletedAt.Equal(b.DeletedAt) {
+		return a.DeletedAt.After(b.DeletedAt)
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+
+	return a.ID < b.ID
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// UserDAO is a data access object for users
+type UserDAO struct {
+	store *Store
+	log   log.Logger
+}
+
+// NewUserDAO represents a UserDAO constructor
+func NewUserDAO(store *Store, log log.Logger) UserDAO {
+	return UserDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided user and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao UserDAO) Save(user *models.User) (*models.User, error) {
+	if user == nil {
+		dao.log.Error("users storage: nil pointer given")
+		return nil, errors.New("nil user pointer given")
+	}
+	if user.ID > 0 {
+		dao.log.Warnf("users storage: %v, ID: %d", sv.ErrRecordAlreadyExist, user.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	for _, stored := range data.users {
+		if stored.Email == user.Email {
+			return nil, sv.ErrEmailDuplicate
+		}
+	}
+
+	data.seq.users++
+	now := time.Now()
+	user.ID = data.seq.users
+	user.CreatedAt, user.UpdatedAt = now, now
+	data.track(data.users, user.ID)
+	data.users[user.ID] = *user
+
+	return user, nil
+}
+
+// FindOneById will return a pointer to a user with the provided ID or
+// nil and an error
+func (dao UserDAO) FindOneById(ID uint) (*models.User, error) {
+	defer dao.store.rlock(false)()
+
+	user, ok := dao.store.data.users[ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return &user, nil
+}
+
+// FindOneByEmail will return a pointer to a user with the provided email or
+// nil and an error
+func (dao UserDAO) FindOneByEmail(email string) (*models.User, error) {
+	defer dao.store.rlock(false)()
+
+	for _, user := range dao.store.data.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+
+	return nil, sv.ErrRecordNotFound
+}
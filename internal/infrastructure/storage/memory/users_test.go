@@ -0,0 +1,32 @@
+// +build unit
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserDAO(t *testing.T) {
+	userDAO := NewUserDAO(NewStore(), new(LoggerMock))
+
+	user, err := userDAO.Save(&models.User{Email: "john@example.com", Name: "John", PasswordHash: "hash"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), user.ID)
+
+	_, err = userDAO.Save(&models.User{Email: "john@example.com", Name: "Johnny", PasswordHash: "hash"})
+	assert.Equal(t, services.ErrEmailDuplicate, err)
+
+	found, err := userDAO.FindOneByEmail("john@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	_, err = userDAO.FindOneByEmail("jane@example.com")
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	_, err = userDAO.FindOneById(user.ID + 1)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+}
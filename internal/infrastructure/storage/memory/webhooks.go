@@ -0,0 +1,308 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// WebhookDAO is a data access object for webhooks
+type WebhookDAO struct {
+	store *Store
+	log   log.Logger
+}
+
+// NewWebhookDAO represents a WebhookDAO constructor
+func NewWebhookDAO(store *Store, log log.Logger) WebhookDAO {
+	return WebhookDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided webhook and return a pointer to the saved
+// entity. Returns nil and an error in case of error.
+func (dao WebhookDAO) Save(webhook *models.Webhook) (*models.Webhook, error) {
+	if webhook == nil {
+		dao.log.Error("webhooks storage: nil pointer given")
+		return nil, errors.New("nil webhook pointer given")
+	}
+	if webhook.ID > 0 {
+		dao.log.Warnf("webhooks storage: %v, ID: %d", sv.ErrRecordAlreadyExist, webhook.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	if _, ok := data.boards[webhook.BoardID]; !ok {
+		return nil, sv.ErrBoardRelation
+	}
+
+	data.seq.webhooks++
+	now := time.Now()
+	webhook.ID = data.seq.webhooks
+	webhook.CreatedAt, webhook.UpdatedAt = now, now
+	webhook.Events = append(models.EventTypes{}, webhook.Events...)
+	data.track(data.webhooks, webhook.ID)
+	data.webhooks[webhook.ID] = *webhook
+
+	return webhook, nil
+}
+
+// FindOneById will return a pointer to a webhook with the provided ID or
+// nil and an error
+func (dao WebhookDAO) FindOneById(ID uint) (*models.Webhook, error) {
+	defer dao.store.rlock(false)()
+
+	webhook, ok := dao.store.data.webhooks[ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+	webhook.Events = append(models.EventTypes{}, webhook.Events...)
+
+	return &webhook, nil
+}
+
+// Find will return the webhooks of the board that fit the provided page sorted by ID
+func (dao WebhookDAO) Find(boardID uint, page sv.Page) ([]*models.Webhook, error) {
+	defer dao.store.rlock(false)()
+
+	webhooks := make([]*models.Webhook, 0)
+	for _, webhook := range dao.store.data.webhooks {
+		if webhook.BoardID != boardID {
+			continue
+		}
+		webhook := webhook
+		webhook.Events = append(models.EventTypes{}, webhook.Events...)
+		webhooks = append(webhooks, &webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+
+	from, to := paginate(len(webhooks), page, func(i int) bool {
+		return webhooks[i].ID > page.After.ID
+	})
+
+	return webhooks[from:to], nil
+}
+
+// Update will update the URL, the secret, the event types and the disabled flag
+// of the webhook
+func (dao WebhookDAO) Update(webhook *models.Webhook) (*models.Webhook, error) {
+	if webhook == nil {
+		dao.log.Error("webhooks storage: nil pointer given")
+		return nil, errors.New("nil webhook pointer given")
+	}
+
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	stored, ok := data.webhooks[webhook.ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	stored.URL = webhook.URL
+	stored.Secret = webhook.Secret
+	stored.Events = append(models.EventTypes{}, webhook.Events...)
+	stored.Disabled = webhook.Disabled
+	stored.UpdatedAt = time.Now()
+	data.track(data.webhooks, webhook.ID)
+	data.webhooks[webhook.ID] = stored
+	*webhook = stored
+	webhook.Events = append(models.EventTypes{}, stored.Events...)
+
+	return webhook, nil
+}
+
+// Delete will delete the webhook with the provided ID along with its deliveries
+func (dao WebhookDAO) Delete(ID uint) error {
+	defer dao.store.lock(false)()
+	if _, ok := dao.store.data.webhooks[ID]; !ok {
+		return sv.ErrRecordNotFound
+	}
+	dao.store.data.deleteWebhook(ID)
+
+	return nil
+}
+
+// deleteWebhook removes the webhook along with its deliveries
+func (d *dataset) deleteWebhook(ID uint) {
+	for deliveryID, delivery := range d.deliveries {
+		if delivery.WebhookID == ID {
+			d.track(d.deliveries, deliveryID)
+			delete(d.deliveries, deliveryID)
+		}
+	}
+	d.track(d.webhooks, ID)
+	delete(d.webhooks, ID)
+}
+
+// DeliveryDAO is a data access object for the webhook deliveries queue
+type DeliveryDAO struct {
+	store *Store
+	log   log.Logger
+}
+
+// NewDeliveryDAO represents a DeliveryDAO constructor
+func NewDeliveryDAO(store *Store, log log.Logger) DeliveryDAO {
+	return DeliveryDAO{
+		store: store,
+		log:   log,
+	}
+}
+
+// Save will store the provided delivery and return a pointer to the saved
+// entity. Returns nil and an error in case of error, ErrRecordAlreadyExist if
+// the webhook already has a delivery of the event.
+func (dao DeliveryDAO) Save(delivery *models.Delivery) (*models.Delivery, error) {
+	if delivery == nil {
+		dao.log.Error("deliveries storage: nil pointer given")
+		return nil, errors.New("nil delivery pointer given")
+	}
+	if delivery.ID > 0 {
+		dao.log.Warnf("deliveries storage: %v, ID: %d", sv.ErrRecordAlreadyExist, delivery.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	if _, ok := data.webhooks[delivery.WebhookID]; !ok {
+		return nil, errors.Errorf("deliveries storage: webhook %d was not found", delivery.WebhookID)
+	}
+	if delivery.EventID > 0 {
+		for _, stored := range data.deliveries {
+			if stored.WebhookID == delivery.WebhookID && stored.EventID == delivery.EventID {
+				return nil, sv.ErrRecordAlreadyExist
+			}
+		}
+	}
+
+	data.seq.deliveries++
+	now := time.Now().UTC()
+	delivery.ID = data.seq.deliveries
+	delivery.CreatedAt, delivery.UpdatedAt = now, now
+	data.track(data.deliveries, delivery.ID)
+	data.deliveries[delivery.ID] = copyDelivery(*delivery)
+
+	return delivery, nil
+}
+
+// FindOneById will return a pointer to a delivery with the provided ID or
+// nil and an error
+func (dao DeliveryDAO) FindOneById(ID uint) (*models.Delivery, error) {
+	defer dao.store.rlock(false)()
+
+	delivery, ok := dao.store.data.deliveries[ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+	delivery = copyDelivery(delivery)
+
+	return &delivery, nil
+}
+
+// Find will return the deliveries of the webhook that fit the provided page from
+// the newest to the oldest
+func (dao DeliveryDAO) Find(webhookID uint, page sv.Page) ([]*models.Delivery, error) {
+	defer dao.store.rlock(false)()
+
+	deliveries := make([]*models.Delivery, 0)
+	for _, delivery := range dao.store.data.deliveries {
+		if delivery.WebhookID != webhookID {
+			continue
+		}
+		delivery := copyDelivery(delivery)
+		deliveries = append(deliveries, &delivery)
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+
+	from, to := paginate(len(deliveries), page, func(i int) bool {
+		return deliveries[i].ID < page.After.ID
+	})
+
+	return deliveries[from:to], nil
+}
+
+// Claim will postpone the next attempts of the pending deliveries due by the provided
+// time till the lease expires and return them, the longest due ones first
+func (dao DeliveryDAO) Claim(now, lease time.Time, limit uint) ([]*models.Delivery, error) {
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	due := make([]models.Delivery, 0)
+	for _, delivery := range data.deliveries {
+		if delivery.Status == models.DeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(*due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if uint(len(due)) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*models.Delivery, 0, len(due))
+	for _, delivery := range due {
+		stored := copyDelivery(delivery)
+		next := lease
+		stored.NextAttemptAt = &next
+		data.track(data.deliveries, delivery.ID)
+		data.deliveries[delivery.ID] = stored
+
+		delivery := copyDelivery(delivery)
+		claimed = append(claimed, &delivery)
+	}
+
+	return claimed, nil
+}
+
+// Update will update the status, the number of attempts, the response code, the
+// error and the next attempt of the delivery
+func (dao DeliveryDAO) Update(delivery *models.Delivery) (*models.Delivery, error) {
+	if delivery == nil {
+		dao.log.Error("deliveries storage: nil pointer given")
+		return nil, errors.New("nil delivery pointer given")
+	}
+
+	defer dao.store.lock(false)()
+	data := dao.store.data
+
+	stored, ok := data.deliveries[delivery.ID]
+	if !ok {
+		return nil, sv.ErrRecordNotFound
+	}
+
+	stored.Status = delivery.Status
+	stored.Attempts = delivery.Attempts
+	stored.ResponseCode = delivery.ResponseCode
+	stored.Error = delivery.Error
+	stored.NextAttemptAt = delivery.NextAttemptAt
+	stored.UpdatedAt = time.Now().UTC()
+	stored = copyDelivery(stored)
+	data.track(data.deliveries, delivery.ID)
+	data.deliveries[delivery.ID] = stored
+	*delivery = copyDelivery(stored)
+
+	return delivery, nil
+}
+
+// copyDelivery returns the copy of the delivery that shares no memory with it
+func copyDelivery(delivery models.Delivery) models.Delivery {
+	delivery.Payload = append([]byte{}, delivery.Payload...)
+	if delivery.NextAttemptAt != nil {
+		next := *delivery.NextAttemptAt
+		delivery.NextAttemptAt = &next
+	}
+
+	return delivery
+}
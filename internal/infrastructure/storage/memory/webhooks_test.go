@@ -0,0 +1,138 @@
+// +build unit
+
+package memory
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDAO(t *testing.T) {
+	store := NewStore()
+	board, err := NewBoardDAO(store, new(LoggerMock)).Save(&models.Board{Name: "dummy"})
+	require.NoError(t, err)
+	webhookDAO := NewWebhookDAO(store, new(LoggerMock))
+
+	_, err = webhookDAO.Save(&models.Webhook{BoardID: board.ID + 10, URL: "https://example.com"})
+	assert.Equal(t, services.ErrBoardRelation, err)
+
+	first, err := webhookDAO.Save(&models.Webhook{
+		BoardID: board.ID,
+		URL:     "https://example.com/first",
+		Secret:  "0123456789abcdef",
+		Events:  models.EventTypes{"task.created"},
+	})
+	require.NoError(t, err)
+	second, err := webhookDAO.Save(&models.Webhook{BoardID: board.ID, URL: "https://example.com/second"})
+	require.NoError(t, err)
+
+	webhooks, err := webhookDAO.Find(board.ID, services.Page{Limit: 1, After: &services.Cursor{ID: first.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []*models.Webhook{second}, webhooks)
+
+	updated, err := webhookDAO.Update(&models.Webhook{
+		Model:    models.Model{ID: first.ID},
+		URL:      "https://example.com/updated",
+		Secret:   "fedcba9876543210",
+		Events:   models.EventTypes{"task.moved"},
+		Disabled: true,
+	})
+	require.NoError(t, err)
+	stored, err := webhookDAO.FindOneById(first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, stored)
+	assert.Equal(t, board.ID, stored.BoardID)
+	assert.Equal(t, "fedcba9876543210", stored.Secret)
+	assert.Equal(t, models.EventTypes{"task.moved"}, stored.Events)
+	assert.True(t, stored.Disabled)
+
+	_, err = webhookDAO.Update(&models.Webhook{Model: models.Model{ID: second.ID + 10}})
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	assert.NoError(t, webhookDAO.Delete(second.ID))
+	assert.Equal(t, services.ErrRecordNotFound, webhookDAO.Delete(second.ID))
+}
+
+func TestDeliveryDAO(t *testing.T) {
+	store := NewStore()
+	board, err := NewBoardDAO(store, new(LoggerMock)).Save(&models.Board{Name: "dummy"})
+	require.NoError(t, err)
+	webhookDAO := NewWebhookDAO(store, new(LoggerMock))
+	webhook, err := webhookDAO.Save(&models.Webhook{BoardID: board.ID, URL: "https://example.com"})
+	require.NoError(t, err)
+	deliveryDAO := NewDeliveryDAO(store, new(LoggerMock))
+
+	now := time.Now().UTC()
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	deliveries := make([]*models.Delivery, 0)
+	for _, next := range []*time.Time{at(-time.Second), at(-time.Minute), at(time.Minute), nil} {
+		delivery, err := deliveryDAO.Save(&models.Delivery{
+			WebhookID:     webhook.ID,
+			EventType:     "task.created",
+			Payload:       json.RawMessage(`{"id":1}`),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: next,
+		})
+		require.NoError(t, err)
+		deliveries = append(deliveries, delivery)
+	}
+
+	claimed, err := deliveryDAO.Claim(now, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, deliveries[1].ID, claimed[0].ID)
+	assert.Equal(t, deliveries[0].ID, claimed[1].ID)
+	assert.JSONEq(t, `{"id":1}`, string(claimed[0].Payload))
+
+	claimed, err = deliveryDAO.Claim(now, now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	delivery := deliveries[1]
+	delivery.Status, delivery.Attempts, delivery.ResponseCode, delivery.NextAttemptAt = models.DeliverySucceeded, 1, 204, nil
+	_, err = deliveryDAO.Update(delivery)
+	require.NoError(t, err)
+	stored, err := deliveryDAO.FindOneById(delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliverySucceeded, stored.Status)
+	assert.Equal(t, uint(1), stored.Attempts)
+	assert.Equal(t, 204, stored.ResponseCode)
+	assert.Nil(t, stored.NextAttemptAt)
+
+	found, err := deliveryDAO.Find(webhook.ID, services.Page{Limit: 2, After: &services.Cursor{ID: deliveries[3].ID}})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, deliveries[2].ID, found[0].ID)
+	assert.Equal(t, deliveries[1].ID, found[1].ID)
+
+	relayed := func() *models.Delivery {
+		return &models.Delivery{
+			WebhookID: webhook.ID,
+			EventID:   7,
+			EventType: "task.created",
+			Payload:   json.RawMessage(`{"id":7}`),
+			Status:    models.DeliveryPending,
+		}
+	}
+	saved, err := deliveryDAO.Save(relayed())
+	require.NoError(t, err)
+	stored, err = deliveryDAO.FindOneById(saved.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), stored.EventID)
+	assert.Zero(t, deliveries[0].EventID)
+	_, err = deliveryDAO.Save(relayed())
+	assert.Equal(t, services.ErrRecordAlreadyExist, err, "a webhook gets a single delivery of an event")
+
+	require.NoError(t, webhookDAO.Delete(webhook.ID))
+	_, err = deliveryDAO.FindOneById(delivery.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+// ActivityDAO is a data access object for the activity history
+type ActivityDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewActivityDAO represents an ActivityDAO constructor
+func NewActivityDAO(db querier, log log.Logger) ActivityDAO {
+	return ActivityDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided activity entry into the database and return a pointer
+// to the saved entry. The board of an entry on a task is resolved from the task, which
+// may be deleted. Returns nil and an error in case of error.
+func (dao ActivityDAO) Save(entry *models.Activity) (*models.Activity, error) {
+	if entry == nil {
+		dao.log.Error("activity storage: nil pointer given")
+		return nil, errors.New("nil activity pointer given")
+	}
+	if entry.ID > 0 {
+		dao.log.Warnf("activity storage: %v, ID: %d", sv.ErrRecordAlreadyExist, entry.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	saved := *entry
+	if err := dao.db.QueryRow(`
+		insert into activities (board, task, entity, entity_id, action, actor, changes)
+		values (
+			coalesce((select c.board from tasks t join columns c on c.id = t."column" where t.id = $1), $2),
+			nullif($1, 0), $3, $4, $5, nullif($6, 0), $7
+		)
+		returning id, created_at, board;`,
+		entry.TaskID,
+		entry.BoardID,
+		entry.Entity,
+		entry.EntityID,
+		entry.Action,
+		entry.ActorID,
+		entry.Changes,
+	).Scan(&saved.ID, &saved.CreatedAt, &saved.BoardID); err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Constraint == "activities_board_fkey" {
+			dao.log.Errorf("activity storage: integrity constraint violation: %v", err)
+			return nil, sv.ErrBoardRelation
+		}
+		dao.log.Errorf("activity storage: error while inserting a row: %v", err)
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// Find will return the activity entries that meet the provided demand and fit the
+// provided page from the newest to the oldest or an error
+func (dao ActivityDAO) Find(demand sv.ActivityDemand, page sv.Page) ([]*models.Activity, error) {
+	where, args := "true", make([]interface{}, 0)
+	if boardID, ok := demand["board"]; ok {
+		where = where + fmt.Sprintf(" and board = %d", boardID)
+	}
+	if taskID, ok := demand["task"]; ok {
+		where = where + fmt.Sprintf(" and task = %d", taskID)
+	}
+	if actorID, ok := demand["actor"]; ok {
+		where = where + fmt.Sprintf(" and actor = %d", actorID)
+	}
+	if entity, ok := demand["entity"]; ok {
+		args = append(args, entity)
+		where = where + fmt.Sprintf(" and entity = $%d", len(args))
+	}
+	if page.After != nil {
+		args = append(args, page.After.ID)
+		where = where + fmt.Sprintf(" and id < $%d", len(args))
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(
+			`select id, created_at, board, coalesce(task, 0), entity, entity_id, action, coalesce(actor, 0), changes
+			from activities where %s order by id desc%s;`,
+			where,
+			limit(page),
+		),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("activity storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	entries := make([]*models.Activity, 0)
+	for rows.Next() {
+		entry := &models.Activity{}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.CreatedAt,
+			&entry.BoardID,
+			&entry.TaskID,
+			&entry.Entity,
+			&entry.EntityID,
+			&entry.Action,
+			&entry.ActorID,
+			&entry.Changes,
+		); err != nil {
+			dao.log.Errorf("activity storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("activity storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// WithTx will return the ActivityDAO that will use the provided transaction
+func (dao ActivityDAO) WithTx(tx *sql.Tx) sv.ActivityStorage {
+	dao.db = tx
+	return dao
+}
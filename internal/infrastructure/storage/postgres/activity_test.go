@@ -0,0 +1,53 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActivityDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_entry", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		activityDAO := NewActivityDAO(new(QuerierMock), logger)
+		res, err := activityDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+	t.Run("error_on_saved_entry", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+		activityDAO := NewActivityDAO(new(QuerierMock), logger)
+		res, err := activityDAO.Save(&models.Activity{ID: 1})
+
+		assert.Nil(t, res)
+		assert.Equal(t, services.ErrRecordAlreadyExist, err)
+	})
+}
+
+func TestActivityDAO_Find(t *testing.T) {
+	t.Run("query_error", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Query", mock.Anything, []interface{}{models.EntityTask, uint(7)}).Return((*sql.Rows)(nil), errors.New("dummy"))
+		activityDAO := NewActivityDAO(db, logger)
+		demand := services.ActivityDemand{"board": uint(1), "entity": models.EntityTask}
+		res, err := activityDAO.Find(demand, services.Page{After: &services.Cursor{ID: 7}})
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
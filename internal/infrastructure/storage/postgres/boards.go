@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/dnozdrin/detask/internal/app/log"
 	"github.com/pkg/errors"
 	"time"
@@ -37,9 +38,9 @@ func (dao BoardDAO) Save(board *models.Board) (*models.Board, error) {
 	}
 
 	stmt, err := dao.db.Prepare(`
-		insert into boards (name, description)
-		values ($1, $2)
-		returning id, created_at, updated_at, name, description;`,
+		insert into boards (name, description, created_by, template, template_id)
+		values ($1, $2, nullif($3, 0), $4, nullif($5, 0))
+		returning id, created_at, updated_at, version, name, description, coalesce(created_by, 0), template, coalesce(template_id, 0);`,
 	)
 	if err != nil {
 		dao.log.Errorf("boards storage: failed to prepare statement: %v", err)
@@ -47,12 +48,16 @@ func (dao BoardDAO) Save(board *models.Board) (*models.Board, error) {
 	}
 
 	defer deferred(dao.log, stmt.Close)
-	if err = stmt.QueryRow(board.Name, board.Description).Scan(
+	if err = stmt.QueryRow(board.Name, board.Description, board.CreatedBy, board.Template, board.TemplateID).Scan(
 		&board.ID,
 		&board.CreatedAt,
 		&board.UpdatedAt,
+		&board.Version,
 		&board.Name,
 		&board.Description,
+		&board.CreatedBy,
+		&board.Template,
+		&board.TemplateID,
 	); err != nil {
 		dao.log.Errorf("boards storage: error while querying a row: %v", err)
 		return nil, err
@@ -66,17 +71,21 @@ func (dao BoardDAO) Save(board *models.Board) (*models.Board, error) {
 func (dao BoardDAO) FindOneById(ID uint) (*models.Board, error) {
 	board := &models.Board{}
 	if err := dao.db.QueryRow(`
-		select id, created_at, updated_at, name, description
+		select id, created_at, updated_at, version, name, description, coalesce(created_by, 0), template, coalesce(template_id, 0)
 		from boards
-		where id = $1
+		where id = $1 and deleted_at is null
 		order by name
 		`, ID).
 		Scan(
 			&board.ID,
 			&board.CreatedAt,
 			&board.UpdatedAt,
+			&board.Version,
 			&board.Name,
 			&board.Description,
+			&board.CreatedBy,
+			&board.Template,
+			&board.TemplateID,
 		); err != nil {
 		if err != sql.ErrNoRows {
 			dao.log.Errorf("boards storage: error while querying a row: %v", err)
@@ -89,11 +98,28 @@ func (dao BoardDAO) FindOneById(ID uint) (*models.Board, error) {
 	return board, nil
 }
 
-// Find will return all found boards or an error
-func (dao BoardDAO) Find() ([]*models.Board, error) {
+// Find will return all found boards that meet the provided demand and fit
+// the provided page or an error
+func (dao BoardDAO) Find(demand sv.BoardDemand, page sv.Page) ([]*models.Board, error) {
 	boards := make([]*models.Board, 0)
 
-	rows, err := dao.db.Query(`select id, created_at, updated_at, name, description from boards`)
+	where, args := "deleted_at is null", make([]interface{}, 0)
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(" and id in (select board_id from board_members where user_id = %d)", userID)
+	}
+	if template, ok := demand["template"]; ok {
+		args = append(args, template)
+		where = where + fmt.Sprintf(" and template = $%d", len(args))
+	}
+	if page.After != nil {
+		args = append(args, page.After.ID)
+		where = where + fmt.Sprintf(" and id > $%d", len(args))
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(`select id, created_at, updated_at, version, name, description, coalesce(created_by, 0), template, coalesce(template_id, 0) from boards where %s order by id%s`, where, limit(page)),
+		args...,
+	)
 	if err != nil {
 		dao.log.Errorf("boards storage: error while querying rows: %v", err)
 		return nil, err
@@ -106,8 +132,12 @@ func (dao BoardDAO) Find() ([]*models.Board, error) {
 			&board.ID,
 			&board.CreatedAt,
 			&board.UpdatedAt,
+			&board.Version,
 			&board.Name,
 			&board.Description,
+			&board.CreatedBy,
+			&board.Template,
+			&board.TemplateID,
 		); err != nil {
 			dao.log.Errorf("boards storage: error while querying next row: %v", err)
 			return nil, err
@@ -123,8 +153,9 @@ func (dao BoardDAO) Find() ([]*models.Board, error) {
 	return boards, nil
 }
 
-// Update will update the name and description of the persistent representation
-// of the board
+// Update will update the name, the description and the template flag of the
+// persistent representation of the board and bump its version. The board is
+// updated only if it has the version of the provided one, unless it is zero
 func (dao BoardDAO) Update(board *models.Board) (*models.Board, error) {
 	if board == nil {
 		dao.log.Error("boards storage: nil pointer given")
@@ -132,21 +163,25 @@ func (dao BoardDAO) Update(board *models.Board) (*models.Board, error) {
 	}
 	stmt, err := dao.db.Prepare(`
 		update boards
-		set updated_at = $1, name = $2, description = $3
-		where id = $4
-		returning id, created_at, updated_at, name, description
+		set updated_at = $1, name = $2, description = $3, template = $4, version = version + 1
+		where id = $5 and version = coalesce(nullif($6, 0), version) and deleted_at is null
+		returning id, created_at, updated_at, version, name, description, coalesce(created_by, 0), template, coalesce(template_id, 0)
 	`)
 	if err != nil {
 		dao.log.Errorf("boards storage: failed to prepare statement: %v", err)
 		return nil, err
 	}
 	defer deferred(dao.log, stmt.Close)
-	if err = stmt.QueryRow(time.Now(), board.Name, board.Description, board.ID).Scan(
+	if err = stmt.QueryRow(time.Now(), board.Name, board.Description, board.Template, board.ID, board.Version).Scan(
 		&board.ID,
 		&board.CreatedAt,
 		&board.UpdatedAt,
+		&board.Version,
 		&board.Name,
 		&board.Description,
+		&board.CreatedBy,
+		&board.Template,
+		&board.TemplateID,
 	); err != nil {
 		if err != sql.ErrNoRows {
 			dao.log.Errorf("boards storage: error while updating a row: %v", err)
@@ -159,9 +194,20 @@ func (dao BoardDAO) Update(board *models.Board) (*models.Board, error) {
 	return board, nil
 }
 
-// Delete will delete the record in the database
+// Delete will mark the board as deleted along with its columns, their tasks and comments
 func (dao BoardDAO) Delete(ID uint) error {
-	_, err := dao.db.Exec(`delete from boards where id = $1`, ID)
+	_, err := dao.db.Exec(`
+		with deleted_columns as (
+			update columns set deleted_at = $2 where board = $1 and deleted_at is null returning id
+		), deleted_tasks as (
+			update tasks set deleted_at = $2 where "column" in (select id from deleted_columns) and deleted_at is null returning id
+		), deleted_comments as (
+			update comments set deleted_at = $2 where task in (select id from deleted_tasks) and deleted_at is null
+		)
+		update boards set deleted_at = $2 where id = $1 and deleted_at is null`,
+		ID,
+		time.Now(),
+	)
 	if err != nil {
 		dao.log.Errorf("boards storage: error while deleting a row: %v", err)
 		return err
@@ -170,6 +216,67 @@ func (dao BoardDAO) Delete(ID uint) error {
 	return nil
 }
 
+// Copy will copy the columns of the source board to the target board, along with
+// the labels, the tasks and their comments if the tasks are requested. The copied
+// tasks are matched with the originals by the names of their columns and their
+// positions, the labels are matched by their names. The deleted records are not copied.
+func (dao BoardDAO) Copy(sourceID, targetID uint, withTasks bool, userID uint) error {
+	exec := func(query string, args ...interface{}) error {
+		if _, err := dao.db.Exec(query, args...); err != nil {
+			dao.log.Errorf("boards storage: error while copying a board: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	if err := exec(`
+		insert into columns (name, board, position, wip_limit)
+		select name, $2, position, wip_limit
+		from columns
+		where board = $1 and deleted_at is null;`, sourceID, targetID); err != nil || !withTasks {
+		return err
+	}
+	if err := exec(`
+		insert into labels (name, color, board)
+		select name, color, $2
+		from labels
+		where board = $1;`, sourceID, targetID); err != nil {
+		return err
+	}
+	if err := exec(`
+		insert into tasks (name, description, "column", position, created_by, reporter, start_at, due_at, priority)
+		select t.name, t.description, nc.id, t.position, $3, $3, t.start_at, t.due_at, t.priority
+		from tasks t
+		join columns oc on oc.id = t."column"
+		join columns nc on nc.board = $2 and nc.name = oc.name
+		where oc.board = $1 and t.deleted_at is null;`, sourceID, targetID, userID); err != nil {
+		return err
+	}
+	if err := exec(`
+		insert into task_labels (task_id, label_id)
+		select nt.id, nl.id
+		from task_labels tl
+		join labels ol on ol.id = tl.label_id
+		join labels nl on nl.board = $2 and nl.name = ol.name
+		join tasks ot on ot.id = tl.task_id
+		join columns oc on oc.id = ot."column"
+		join columns nc on nc.board = $2 and nc.name = oc.name
+		join tasks nt on nt."column" = nc.id and nt.position = ot.position
+		where oc.board = $1 and ot.deleted_at is null;`, sourceID, targetID); err != nil {
+		return err
+	}
+
+	return exec(`
+		insert into comments (created_at, text, task, created_by)
+		select c.created_at, c.text, nt.id, c.created_by
+		from comments c
+		join tasks ot on ot.id = c.task
+		join columns oc on oc.id = ot."column"
+		join columns nc on nc.board = $2 and nc.name = oc.name
+		join tasks nt on nt."column" = nc.id and nt.position = ot.position
+		where oc.board = $1 and c.deleted_at is null;`, sourceID, targetID)
+}
+
 // WithTx will return the BoardDAO that will use the provided transaction
 func (dao BoardDAO) WithTx(tx *sql.Tx) sv.BoardStorage {
 	dao.db = tx
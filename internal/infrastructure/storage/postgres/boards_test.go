@@ -61,7 +61,7 @@ func TestBoardDAO_Find(t *testing.T) {
 		db := new(QuerierMock)
 		db.On("Query", mock.Anything, mock.Anything).Return(&sql.Rows{}, errors.New("dummy"))
 		boardDAO := NewBoardDAO(db, logger)
-		res, err := boardDAO.Find()
+		res, err := boardDAO.Find(make(services.BoardDemand), services.Page{Limit: 10, After: &services.Cursor{ID: 1}})
 
 		assert.Nil(t, res)
 		assert.Error(t, err)
@@ -103,7 +103,7 @@ func TestBoardDAO_Delete(t *testing.T) {
 		logger.On("Errorf", mock.Anything, mock.Anything).Return()
 
 		db := new(QuerierMock)
-		db.On("Exec", mock.Anything, []interface{}{ID}).Return(result, errors.New("dummy"))
+		db.On("Exec", mock.Anything, deletionArgs(ID)).Return(result, errors.New("dummy"))
 		boardDAO := NewBoardDAO(db, logger)
 		err := boardDAO.Delete(ID)
 
@@ -38,22 +38,24 @@ func (dao ColumnDAO) Save(column *models.Column) (*models.Column, error) {
 	}
 
 	stmt, err := dao.db.Prepare(`
-		insert into columns (name, board, position)
-		values ($1, $2, $3)
-		returning id, created_at, updated_at, name, board, position;`,
+		insert into columns (name, board, position, wip_limit)
+		values ($1, $2, $3, nullif($4, 0))
+		returning id, created_at, updated_at, version, name, board, position, coalesce(wip_limit, 0);`,
 	)
 	if err != nil {
 		dao.log.Errorf("columns storage: failed to prepare statement: %v", err)
 		return nil, err
 	}
 	defer deferred(dao.log, stmt.Close)
-	if err = stmt.QueryRow(column.Name, column.BoardID, column.Position).Scan(
+	if err = stmt.QueryRow(column.Name, column.BoardID, column.Position, column.WIPLimit).Scan(
 		&column.ID,
 		&column.CreatedAt,
 		&column.UpdatedAt,
+		&column.Version,
 		&column.Name,
 		&column.BoardID,
 		&column.Position,
+		&column.WIPLimit,
 	); err != nil {
 		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code.Class().Name() == "integrity_constraint_violation" {
 			switch pgErr.Constraint {
@@ -81,17 +83,19 @@ func (dao ColumnDAO) Save(column *models.Column) (*models.Column, error) {
 func (dao ColumnDAO) FindOneById(ID uint) (*models.Column, error) {
 	column := &models.Column{}
 	err := dao.db.QueryRow(`
-		select id, created_at, updated_at, name, board, position
+		select id, created_at, updated_at, version, name, board, position, coalesce(wip_limit, 0)
 		from columns
-		where id = $1
+		where id = $1 and deleted_at is null
 		`, ID).
 		Scan(
 			&column.ID,
 			&column.CreatedAt,
 			&column.UpdatedAt,
+			&column.Version,
 			&column.Name,
 			&column.BoardID,
 			&column.Position,
+			&column.WIPLimit,
 		)
 	if err != nil {
 		if err != sql.ErrNoRows {
@@ -105,16 +109,26 @@ func (dao ColumnDAO) FindOneById(ID uint) (*models.Column, error) {
 	return column, nil
 }
 
-// Find will return all found columns or an error
-func (dao ColumnDAO) Find(demand sv.ColumnDemand) ([]*models.Column, error) {
-	const querySelect = "id, created_at, updated_at, name, board, position"
+// Find will return all found columns that fit the provided page or an error
+func (dao ColumnDAO) Find(demand sv.ColumnDemand, page sv.Page) ([]*models.Column, error) {
+	const querySelect = "id, created_at, updated_at, version, name, board, position, coalesce(wip_limit, 0)"
 	columns := make([]*models.Column, 0)
-	where := "1=1"
+	where, args := "deleted_at is null", make([]interface{}, 0)
 	if taskID, ok := demand["board"]; ok {
 		where = where + fmt.Sprintf(" and board = %d", taskID)
 	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(" and board in (select board_id from board_members where user_id = %d)", userID)
+	}
+	if page.After != nil {
+		args = append(args, page.After.Position, page.After.ID)
+		where = where + fmt.Sprintf(" and (position, id) > ($%d, $%d)", len(args)-1, len(args))
+	}
 
-	rows, err := dao.db.Query(fmt.Sprintf(`select %s from columns where %s order by position;`, querySelect, where))
+	rows, err := dao.db.Query(
+		fmt.Sprintf(`select %s from columns where %s order by position, id%s;`, querySelect, where, limit(page)),
+		args...,
+	)
 	if err != nil {
 		dao.log.Errorf("columns storage: error while querying rows: %v", err)
 		return nil, err
@@ -127,9 +141,11 @@ func (dao ColumnDAO) Find(demand sv.ColumnDemand) ([]*models.Column, error) {
 			&column.ID,
 			&column.CreatedAt,
 			&column.UpdatedAt,
+			&column.Version,
 			&column.Name,
 			&column.BoardID,
 			&column.Position,
+			&column.WIPLimit,
 		); err != nil {
 			dao.log.Errorf("columns storage: error while querying next row: %v", err)
 			return nil, err
@@ -145,9 +161,10 @@ func (dao ColumnDAO) Find(demand sv.ColumnDemand) ([]*models.Column, error) {
 	return columns, nil
 }
 
-// Update will update the name of the persistent representation
-// of the column. Returns pointer to a updated column or to a empty column
-// entity and an error
+// Update will update the name, the position and the WIP limit of the persistent
+// representation of the column and bump its version. The column is updated only if
+// it has the version of the provided one, unless it is zero. Returns pointer to a
+// updated column or to a empty column entity and an error
 func (dao ColumnDAO) Update(column *models.Column) (*models.Column, error) {
 	if column == nil {
 		dao.log.Error("columns storage: nil pointer given")
@@ -155,22 +172,24 @@ func (dao ColumnDAO) Update(column *models.Column) (*models.Column, error) {
 	}
 	stmt, err := dao.db.Prepare(`
 		update columns
-		set updated_at = $1, name = $2, position = $3
-		where id = $4
-		returning id, created_at, updated_at, name, board, position
+		set updated_at = $1, name = $2, position = $3, wip_limit = nullif($5, 0), version = version + 1
+		where id = $4 and version = coalesce(nullif($6, 0), version) and deleted_at is null
+		returning id, created_at, updated_at, version, name, board, position, coalesce(wip_limit, 0)
 	`)
 	if err != nil {
 		dao.log.Errorf("columns storage: failed to prepare statement: %v", err)
 		return nil, err
 	}
 	defer deferred(dao.log, stmt.Close)
-	if err = stmt.QueryRow(time.Now(), column.Name, column.Position, column.ID).Scan(
+	if err = stmt.QueryRow(time.Now(), column.Name, column.Position, column.ID, column.WIPLimit, column.Version).Scan(
 		&column.ID,
 		&column.CreatedAt,
 		&column.UpdatedAt,
+		&column.Version,
 		&column.Name,
 		&column.BoardID,
 		&column.Position,
+		&column.WIPLimit,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			err = sv.ErrRecordNotFound
@@ -193,9 +212,19 @@ func (dao ColumnDAO) Update(column *models.Column) (*models.Column, error) {
 	return column, nil
 }
 
-// Delete will the column with the provided ID.
+// Delete will mark the column with the provided ID as deleted along with its tasks
+// and their comments
 func (dao ColumnDAO) Delete(ID uint) error {
-	res, err := dao.db.Exec(`delete from "columns" where id = $1`, ID)
+	res, err := dao.db.Exec(`
+		with deleted_tasks as (
+			update tasks set deleted_at = $2 where "column" = $1 and deleted_at is null returning id
+		), deleted_comments as (
+			update comments set deleted_at = $2 where task in (select id from deleted_tasks) and deleted_at is null
+		)
+		update "columns" set deleted_at = $2 where id = $1 and deleted_at is null`,
+		ID,
+		time.Now(),
+	)
 	if err != nil {
 		dao.log.Errorf("columns storage: error while deleting a column ID: %d: %v", ID, err)
 		return err
@@ -216,6 +245,46 @@ func (dao ColumnDAO) Delete(ID uint) error {
 	return nil
 }
 
+// Rebalance will place the columns with the provided IDs in the given order, with
+// the positions evenly spread by the step. The columns are moved below the lowest
+// position of the board first, so that the new positions do not collide with the
+// old ones on the unique (position, board) constraint.
+func (dao ColumnDAO) Rebalance(boardID uint, IDs []uint, step float64) error {
+	var lowest float64
+	if err := dao.db.QueryRow(
+		`select least(coalesce(min(position), 0), 0) from columns where board = $1`,
+		boardID,
+	).Scan(&lowest); err != nil {
+		dao.log.Errorf("columns storage: error while rebalancing board %d: %v", boardID, err)
+		return err
+	}
+	for i, ID := range IDs {
+		if _, err := dao.db.Exec(
+			`update columns set position = $1 where id = $2 and board = $3`,
+			lowest-float64(i+1),
+			ID,
+			boardID,
+		); err != nil {
+			dao.log.Errorf("columns storage: error while rebalancing board %d: %v", boardID, err)
+			return err
+		}
+	}
+	now := time.Now()
+	for i, ID := range IDs {
+		if _, err := dao.db.Exec(
+			`update columns set position = $1, updated_at = $2, version = version + 1 where id = $3`,
+			step*float64(i+1),
+			now,
+			ID,
+		); err != nil {
+			dao.log.Errorf("columns storage: error while rebalancing board %d: %v", boardID, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
 // WithTx will return the ColumnDAO that will use the provided transaction
 func (dao ColumnDAO) WithTx(tx *sql.Tx) sv.ColumnStorage {
 	dao.db = tx
@@ -225,7 +294,7 @@ func (dao ColumnDAO) WithTx(tx *sql.Tx) sv.ColumnStorage {
 // FindLeftColumn will find a column to the left of the one with the provided ID
 func (dao ColumnDAO) CountColumnsByBoard(ID uint) (int, error) {
 	var num int
-	if err := dao.db.QueryRow(`select count(c1.id) from "columns" c1 where c1.board = $1`, ID).
+	if err := dao.db.QueryRow(`select count(c1.id) from "columns" c1 where c1.board = $1 and c1.deleted_at is null`, ID).
 		Scan(&num); err != nil {
 		dao.log.Errorf("columns storage: error while counting columns by board: %v", err)
 		return 0, err
@@ -238,7 +307,11 @@ func (dao ColumnDAO) FindColumnToTheLeft(ID uint) (uint, error) {
 	var prev sql.NullInt64
 	if err := dao.db.QueryRow(`
 		select prev
-		from (select id, lag(id) over (order by position) as prev from "columns") sub
+		from (
+			select id, lag(id) over (partition by board order by position) as prev
+			from "columns"
+			where deleted_at is null
+		) sub
 		where id = $1`, ID).Scan(&prev); err != nil {
 		dao.log.Errorf("columns storage: error while querying prev record: %v", err)
 		return 0, err
@@ -257,7 +330,11 @@ func (dao ColumnDAO) FindColumnToTheRight(ID uint) (uint, error) {
 	var next sql.NullInt64
 	if err := dao.db.QueryRow(`
 		select next
-		from (select id, lead(id) over (order by position) as next from "columns") sub
+		from (
+			select id, lead(id) over (partition by board order by position) as next
+			from "columns"
+			where deleted_at is null
+		) sub
 		where id = $1`, ID).Scan(&next); err != nil {
 		dao.log.Errorf("columns storage: error while querying prev record: %v", err)
 		return 0, err
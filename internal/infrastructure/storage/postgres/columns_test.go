@@ -75,7 +75,7 @@ func TestColumnDAO_Delete(t *testing.T) {
 		logger.On("Errorf", mock.Anything, mock.Anything).Return()
 
 		db := new(QuerierMock)
-		db.On("Exec", mock.Anything, []interface{}{ID}).Return(result, errors.New("dummy"))
+		db.On("Exec", mock.Anything, deletionArgs(ID)).Return(result, errors.New("dummy"))
 		columnDAO := NewColumnDAO(db, logger)
 		err := columnDAO.Delete(ID)
 
@@ -39,21 +39,23 @@ func (dao CommentsDAO) Save(comment *models.Comment) (*models.Comment, error) {
 	}
 
 	stmt, err := dao.db.Prepare(`
-		insert into comments (text, task)
-		values ($1, $2)
-		returning id, created_at, updated_at, text, task;`,
+		insert into comments (text, task, created_by)
+		values ($1, $2, nullif($3, 0))
+		returning id, created_at, updated_at, version, text, task, coalesce(created_by, 0);`,
 	)
 	if err != nil {
 		dao.log.Errorf("comments storage: failed to prepare statement: %v", err)
 		return nil, err
 	}
 	defer deferred(dao.log, stmt.Close)
-	if err = stmt.QueryRow(comment.Text, comment.TaskID).Scan(
+	if err = stmt.QueryRow(comment.Text, comment.TaskID, comment.CreatedBy).Scan(
 		&comment.ID,
 		&comment.CreatedAt,
 		&comment.UpdatedAt,
+		&comment.Version,
 		&comment.Text,
 		&comment.TaskID,
+		&comment.CreatedBy,
 	); err != nil {
 		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code.Class().Name() == "integrity_constraint_violation" {
 			switch pgErr.Constraint {
@@ -77,11 +79,11 @@ func (dao CommentsDAO) Save(comment *models.Comment) (*models.Comment, error) {
 func (dao CommentsDAO) FindOneById(ID uint) (*models.Comment, error) {
 	comment := &models.Comment{}
 	err := dao.db.QueryRow(`
-		select id, created_at, updated_at, text, task
+		select id, created_at, updated_at, version, text, task, coalesce(created_by, 0)
 		from comments
-		where id = $1
+		where id = $1 and deleted_at is null
 		`, ID).
-		Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt, &comment.Text, &comment.TaskID)
+		Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt, &comment.Version, &comment.Text, &comment.TaskID, &comment.CreatedBy)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			dao.log.Errorf("comments storage: error while querying a row: %v", err)
@@ -93,16 +95,33 @@ func (dao CommentsDAO) FindOneById(ID uint) (*models.Comment, error) {
 	return comment, err
 }
 
-// Find will return all found comments that meet the provided demand or an error
-func (dao CommentsDAO) Find(demand services.CommentDemand) ([]*models.Comment, error) {
-	const querySelect = "id, created_at, updated_at, text, task"
-	where := "1=1"
+// Find will return all found comments that meet the provided demand and fit
+// the provided page or an error
+func (dao CommentsDAO) Find(demand services.CommentDemand, page services.Page) ([]*models.Comment, error) {
+	const querySelect = "id, created_at, updated_at, version, text, task, coalesce(created_by, 0)"
+	where, args := "t.deleted_at is null", make([]interface{}, 0)
 	if taskID, ok := demand["task"]; ok {
 		where = where + fmt.Sprintf(" and t.task = %d", taskID)
 	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(
+			` and t.task in (select mt.id from tasks mt join columns mc on mt."column" = mc.id join board_members m on m.board_id = mc.board where m.user_id = %d)`,
+			userID,
+		)
+	}
+	if page.After != nil {
+		args = append(args, page.After.CreatedAt, page.After.ID)
+		where = where + fmt.Sprintf(" and (t.created_at, t.id) < ($%d, $%d)", len(args)-1, len(args))
+	}
 
 	rows, err := dao.db.Query(
-		fmt.Sprintf(`select %s from comments t where %s order by created_at desc;`, querySelect, where),
+		fmt.Sprintf(
+			`select %s from comments t where %s order by created_at desc, id desc%s;`,
+			querySelect,
+			where,
+			limit(page),
+		),
+		args...,
 	)
 	if err != nil {
 		dao.log.Errorf("comments storage: error while querying rows: %v", err)
@@ -117,8 +136,10 @@ func (dao CommentsDAO) Find(demand services.CommentDemand) ([]*models.Comment, e
 			&comment.ID,
 			&comment.CreatedAt,
 			&comment.UpdatedAt,
+			&comment.Version,
 			&comment.Text,
 			&comment.TaskID,
+			&comment.CreatedBy,
 		); err != nil {
 			dao.log.Errorf("comments storage: error while querying next row: %v", err)
 			return nil, err
@@ -134,7 +155,9 @@ func (dao CommentsDAO) Find(demand services.CommentDemand) ([]*models.Comment, e
 	return comments, nil
 }
 
-// Update will update text of the persistent representation of the comment
+// Update will update text of the persistent representation of the comment and bump
+// its version. The comment is updated only if it has the version of the provided one,
+// unless it is zero
 func (dao CommentsDAO) Update(comment *models.Comment) (*models.Comment, error) {
 	if comment == nil {
 		dao.log.Error("comments storage: nil pointer given")
@@ -142,21 +165,23 @@ func (dao CommentsDAO) Update(comment *models.Comment) (*models.Comment, error)
 	}
 	stmt, err := dao.db.Prepare(`
 		update comments
-		set updated_at = $1, text = $2
-		where id = $3
-		returning id, created_at, updated_at, text, task
+		set updated_at = $1, text = $2, version = version + 1
+		where id = $3 and version = coalesce(nullif($4, 0), version) and deleted_at is null
+		returning id, created_at, updated_at, version, text, task, coalesce(created_by, 0)
 	`)
 	if err != nil {
 		dao.log.Errorf("comments storage: failed to prepare statement: %v", err)
 		return nil, err
 	}
 	defer deferred(dao.log, stmt.Close)
-	if err = stmt.QueryRow(time.Now(), comment.Text, comment.ID).Scan(
+	if err = stmt.QueryRow(time.Now(), comment.Text, comment.ID, comment.Version).Scan(
 		&comment.ID,
 		&comment.CreatedAt,
 		&comment.UpdatedAt,
+		&comment.Version,
 		&comment.Text,
 		&comment.TaskID,
+		&comment.CreatedBy,
 	); err != nil {
 		if err != sql.ErrNoRows {
 			dao.log.Errorf("comments storage: error while updating a row: %v", err)
@@ -169,9 +194,9 @@ func (dao CommentsDAO) Update(comment *models.Comment) (*models.Comment, error)
 	return comment, nil
 }
 
-// Delete will delete the record in the database
+// Delete will mark the comment as deleted
 func (dao CommentsDAO) Delete(ID uint) error {
-	_, err := dao.db.Exec("delete from comments where id = $1", ID)
+	_, err := dao.db.Exec("update comments set deleted_at = $2 where id = $1 and deleted_at is null", ID, time.Now())
 	if err != nil {
 		dao.log.Errorf("comments storage: error while deleting a row: %v", err)
 		return err
@@ -179,3 +204,9 @@ func (dao CommentsDAO) Delete(ID uint) error {
 
 	return err
 }
+
+// WithTx will return the CommentsDAO that will use the provided transaction
+func (dao CommentsDAO) WithTx(tx *sql.Tx) services.CommentStorage {
+	dao.db = tx
+	return dao
+}
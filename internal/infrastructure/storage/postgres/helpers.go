@@ -2,7 +2,11 @@ package postgres
 
 import (
 	"database/sql"
+	"fmt"
 	"github.com/dnozdrin/detask/internal/app/log"
+
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/lib/pq"
 )
 
 func deferred(log log.Logger, f func() error) {
@@ -10,3 +14,50 @@ func deferred(log log.Logger, f func() error) {
 		log.Errorf("%v", err)
 	}
 }
+
+// limit returns the limit clause for the provided page
+func limit(page sv.Page) string {
+	if page.Limit == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" limit %d", page.Limit)
+}
+
+// expectOneRow returns ErrRecordNotFound if the statement has not affected any rows
+func expectOneRow(res sql.Result) error {
+	rowsNum, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsNum == 0 {
+		return sv.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// uintSlice scans a database array of integers into a slice of unsigned integers
+type uintSlice struct {
+	dest *[]uint
+}
+
+// uintArray returns a scanner of a database array into the provided slice
+func uintArray(dest *[]uint) uintSlice {
+	return uintSlice{dest: dest}
+}
+
+// Scan implements the sql.Scanner interface
+func (s uintSlice) Scan(src interface{}) error {
+	var values pq.Int64Array
+	if err := values.Scan(src); err != nil {
+		return err
+	}
+
+	*s.dest = make([]uint, len(values))
+	for i, value := range values {
+		(*s.dest)[i] = uint(value)
+	}
+
+	return nil
+}
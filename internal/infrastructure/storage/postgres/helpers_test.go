@@ -4,7 +4,9 @@ package postgres
 
 import (
 	"database/sql"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
 )
@@ -27,3 +29,8 @@ func TestDeferred(t *testing.T) {
 		})
 	}
 }
+
+func TestLimit(t *testing.T) {
+	assert.Equal(t, "", limit(sv.Page{}))
+	assert.Equal(t, " limit 10", limit(sv.Page{Limit: 10}))
+}
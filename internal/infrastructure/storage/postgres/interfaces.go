@@ -10,3 +10,8 @@ type querier interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 	Exec(query string, args ...interface{}) (sql.Result, error)
 }
+
+// scanner is a row of a query result, either *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+// LabelDAO is a data access object for labels
+type LabelDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewLabelDAO represents a LabelDAO constructor
+func NewLabelDAO(db querier, log log.Logger) LabelDAO {
+	return LabelDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided label into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao LabelDAO) Save(label *models.Label) (*models.Label, error) {
+	if label == nil {
+		dao.log.Error("labels storage: nil pointer given")
+		return nil, errors.New("nil label pointer given")
+	}
+	if label.ID > 0 {
+		dao.log.Warnf("labels storage: %v, ID: %d", sv.ErrRecordAlreadyExist, label.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	stmt, err := dao.db.Prepare(`
+		insert into labels (name, color, board)
+		values ($1, $2, $3)
+		returning id, created_at, updated_at, name, color, board;`,
+	)
+	if err != nil {
+		dao.log.Errorf("labels storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	if err = stmt.QueryRow(label.Name, label.Color, label.BoardID).Scan(
+		&label.ID,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+		&label.Name,
+		&label.Color,
+		&label.BoardID,
+	); err != nil {
+		return nil, dao.translateError(err)
+	}
+
+	return label, nil
+}
+
+// FindOneById will return a pointer to a label with the provided ID or
+// nil and an error
+func (dao LabelDAO) FindOneById(ID uint) (*models.Label, error) {
+	label := &models.Label{}
+	if err := dao.db.QueryRow(
+		`select l.id, l.created_at, l.updated_at, l.name, l.color, l.board
+		from labels l
+		join boards b on b.id = l.board
+		where l.id = $1 and b.deleted_at is null`,
+		ID,
+	).Scan(
+		&label.ID,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+		&label.Name,
+		&label.Color,
+		&label.BoardID,
+	); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("labels storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return label, nil
+}
+
+// Find will return all found labels that meet the provided demand and fit
+// the provided page or an error
+func (dao LabelDAO) Find(demand sv.LabelDemand, page sv.Page) ([]*models.Label, error) {
+	labels := make([]*models.Label, 0)
+
+	where, args := "1=1", make([]interface{}, 0)
+	if boardID, ok := demand["board"]; ok {
+		where = where + fmt.Sprintf(" and board = %d", boardID)
+	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(
+			" and board in (select m.board_id from board_members m join boards b on b.id = m.board_id where m.user_id = %d and b.deleted_at is null)",
+			userID,
+		)
+	}
+	if page.After != nil {
+		args = append(args, page.After.ID)
+		where = where + fmt.Sprintf(" and id > $%d", len(args))
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(`select id, created_at, updated_at, name, color, board from labels where %s order by id%s`, where, limit(page)),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("labels storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	for rows.Next() {
+		label := &models.Label{}
+		if err := rows.Scan(
+			&label.ID,
+			&label.CreatedAt,
+			&label.UpdatedAt,
+			&label.Name,
+			&label.Color,
+			&label.BoardID,
+		); err != nil {
+			dao.log.Errorf("labels storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("labels storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// Update will update the name and the color of the label
+func (dao LabelDAO) Update(label *models.Label) (*models.Label, error) {
+	if label == nil {
+		dao.log.Error("labels storage: nil pointer given")
+		return nil, errors.New("nil label pointer given")
+	}
+
+	stmt, err := dao.db.Prepare(`
+		update labels
+		set updated_at = $1, name = $2, color = $3
+		where id = $4
+		returning id, created_at, updated_at, name, color, board
+	`)
+	if err != nil {
+		dao.log.Errorf("labels storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	if err = stmt.QueryRow(time.Now(), label.Name, label.Color, label.ID).Scan(
+		&label.ID,
+		&label.CreatedAt,
+		&label.UpdatedAt,
+		&label.Name,
+		&label.Color,
+		&label.BoardID,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sv.ErrRecordNotFound
+		}
+
+		return nil, dao.translateError(err)
+	}
+
+	return label, nil
+}
+
+// Delete will delete the label and bump the versions of the tasks it is attached
+// to, the label is detached from the tasks by the cascade foreign key
+func (dao LabelDAO) Delete(ID uint) error {
+	if _, err := dao.db.Exec(
+		"update tasks set version = version + 1, updated_at = now() where id in (select task_id from task_labels where label_id = $1)",
+		ID,
+	); err != nil {
+		dao.log.Errorf("labels storage: error while updating the labelled rows: %v", err)
+		return err
+	}
+	if _, err := dao.db.Exec("delete from labels where id = $1", ID); err != nil {
+		dao.log.Errorf("labels storage: error while deleting a row: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (dao LabelDAO) translateError(err error) error {
+	pgErr, ok := err.(*pq.Error)
+	if !ok || pgErr.Code.Class().Name() != "integrity_constraint_violation" {
+		dao.log.Errorf("labels storage: error while writing a row: %v", err)
+		return err
+	}
+
+	switch pgErr.Constraint {
+	case "labels_name_board_key":
+		return sv.ErrNameDuplicate
+	case "labels_board_fkey":
+		return sv.ErrBoardRelation
+	default:
+		dao.log.Errorf("labels storage: integrity constraint violation: %v", err)
+		return err
+	}
+}
+
+// WithTx will return the LabelDAO that will use the provided transaction
+func (dao LabelDAO) WithTx(tx *sql.Tx) sv.LabelStorage {
+	dao.db = tx
+	return dao
+}
@@ -0,0 +1,54 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLabelDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_label", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		labelDAO := NewLabelDAO(new(QuerierMock), logger)
+		res, err := labelDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestLabelDAO_Update(t *testing.T) {
+	t.Run("error_on_nil_label", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		labelDAO := NewLabelDAO(new(QuerierMock), logger)
+		res, err := labelDAO.Update(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestLabelDAO_Delete(t *testing.T) {
+	t.Run("exec_error", func(t *testing.T) {
+		const ID uint = 0
+		var result driver.RowsAffected = 0
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, []interface{}{ID}).Return(result, errors.New("dummy"))
+		labelDAO := NewLabelDAO(db, logger)
+		err := labelDAO.Delete(ID)
+
+		assert.Error(t, err)
+	})
+}
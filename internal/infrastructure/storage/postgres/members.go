@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+)
+
+// MemberDAO is a data access object for board members
+type MemberDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewMemberDAO represents a MemberDAO constructor
+func NewMemberDAO(db querier, log log.Logger) MemberDAO {
+	return MemberDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided membership into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao MemberDAO) Save(member *models.Member) (*models.Member, error) {
+	if member == nil {
+		dao.log.Error("members storage: nil pointer given")
+		return nil, errors.New("nil member pointer given")
+	}
+
+	if _, err := dao.db.Exec(
+		`insert into board_members (board_id, user_id, role) values ($1, $2, $3)`,
+		member.BoardID,
+		member.UserID,
+		member.Role,
+	); err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code.Class().Name() == "integrity_constraint_violation" {
+			switch pgErr.Constraint {
+			case "board_members_pkey":
+				return nil, sv.ErrRecordAlreadyExist
+			case "board_members_board_id_fkey":
+				return nil, sv.ErrBoardRelation
+			case "board_members_user_id_fkey":
+				return nil, sv.ErrUserRelation
+			}
+		}
+		dao.log.Errorf("members storage: error while inserting a row: %v", err)
+
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// Find will return all members of the board with the provided ID or an error
+func (dao MemberDAO) Find(boardID uint) ([]*models.Member, error) {
+	members := make([]*models.Member, 0)
+
+	rows, err := dao.db.Query(
+		`select board_id, user_id, role from board_members where board_id = $1 order by user_id`,
+		boardID,
+	)
+	if err != nil {
+		dao.log.Errorf("members storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	for rows.Next() {
+		member := &models.Member{}
+		if err := rows.Scan(&member.BoardID, &member.UserID, &member.Role); err != nil {
+			dao.log.Errorf("members storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("members storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// Update will update the role of the member
+func (dao MemberDAO) Update(member *models.Member) (*models.Member, error) {
+	if member == nil {
+		dao.log.Error("members storage: nil pointer given")
+		return nil, errors.New("nil member pointer given")
+	}
+
+	res, err := dao.db.Exec(
+		`update board_members set role = $1 where board_id = $2 and user_id = $3`,
+		member.Role,
+		member.BoardID,
+		member.UserID,
+	)
+	if err != nil {
+		dao.log.Errorf("members storage: error while updating a row: %v", err)
+		return nil, err
+	}
+	if err = expectOneRow(res); err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// Delete will delete the membership of the user on the board
+func (dao MemberDAO) Delete(boardID, userID uint) error {
+	res, err := dao.db.Exec(`delete from board_members where board_id = $1 and user_id = $2`, boardID, userID)
+	if err != nil {
+		dao.log.Errorf("members storage: error while deleting a row: %v", err)
+		return err
+	}
+
+	return expectOneRow(res)
+}
+
+// CountOwners will return the number of owners of the board with the provided ID
+func (dao MemberDAO) CountOwners(boardID uint) (int, error) {
+	var num int
+	if err := dao.db.QueryRow(
+		`select count(*) from board_members where board_id = $1 and role = $2`,
+		boardID,
+		models.RoleOwner,
+	).Scan(&num); err != nil {
+		dao.log.Errorf("members storage: error while counting owners: %v", err)
+		return 0, err
+	}
+
+	return num, nil
+}
+
+// FindOwnerless will return the IDs of the boards that are not deleted and have
+// no owners sorted by ID
+func (dao MemberDAO) FindOwnerless() ([]uint, error) {
+	rows, err := dao.db.Query(
+		`select b.id from boards b
+		where b.deleted_at is null
+			and not exists (select 1 from board_members m where m.board_id = b.id and m.role = $1)
+		order by b.id`,
+		models.RoleOwner,
+	)
+	if err != nil {
+		dao.log.Errorf("members storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	IDs := make([]uint, 0)
+	for rows.Next() {
+		var ID uint
+		if err := rows.Scan(&ID); err != nil {
+			dao.log.Errorf("members storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		IDs = append(IDs, ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("members storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return IDs, nil
+}
+
+// FindRoleByBoard will return the role of the user on the board with the provided ID
+func (dao MemberDAO) FindRoleByBoard(boardID, userID uint) (models.Role, error) {
+	return dao.findRole(`
+		select coalesce(m.role, '')
+		from boards b
+		left join board_members m on m.board_id = b.id and m.user_id = $2
+		where b.id = $1 and b.deleted_at is null`,
+		boardID,
+		userID,
+	)
+}
+
+// FindRoleByColumn will return the role of the user on the board of the column
+// with the provided ID
+func (dao MemberDAO) FindRoleByColumn(columnID, userID uint) (models.Role, error) {
+	return dao.findRole(`
+		select coalesce(m.role, '')
+		from columns c
+		left join board_members m on m.board_id = c.board and m.user_id = $2
+		where c.id = $1 and c.deleted_at is null`,
+		columnID,
+		userID,
+	)
+}
+
+// FindRoleByTask will return the role of the user on the board of the task
+// with the provided ID
+func (dao MemberDAO) FindRoleByTask(taskID, userID uint) (models.Role, error) {
+	return dao.findRole(`
+		select coalesce(m.role, '')
+		from tasks t
+		join columns c on t.column = c.id
+		left join board_members m on m.board_id = c.board and m.user_id = $2
+		where t.id = $1 and t.deleted_at is null`,
+		taskID,
+		userID,
+	)
+}
+
+// WithTx will return the MemberDAO that will use the provided transaction
+func (dao MemberDAO) WithTx(tx *sql.Tx) sv.MemberStorage {
+	dao.db = tx
+	return dao
+}
+
+func (dao MemberDAO) findRole(query string, ID, userID uint) (models.Role, error) {
+	var role models.Role
+	if err := dao.db.QueryRow(query, ID, userID).Scan(&role); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("members storage: error while querying a role: %v", err)
+			return "", err
+		}
+
+		return "", sv.ErrRecordNotFound
+	}
+
+	return role, nil
+}
@@ -0,0 +1,36 @@
+// +build unit
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMemberDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_member", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		memberDAO := NewMemberDAO(new(QuerierMock), logger)
+		res, err := memberDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestMemberDAO_Update(t *testing.T) {
+	t.Run("error_on_nil_member", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		memberDAO := NewMemberDAO(new(QuerierMock), logger)
+		res, err := memberDAO.Update(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
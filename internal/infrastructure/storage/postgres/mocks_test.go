@@ -5,6 +5,7 @@ package postgres
 import (
 	"database/sql"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 type LoggerMock struct {
@@ -76,3 +77,15 @@ func (db *QuerierMock) Exec(query string, args ...interface{}) (sql.Result, erro
 	returnValues := db.Called(query, args)
 	return returnValues.Get(0).(sql.Result), returnValues.Error(1)
 }
+
+// deletionArgs matches the arguments of the statement that marks the record
+// with the provided ID as deleted
+func deletionArgs(ID uint) interface{} {
+	return mock.MatchedBy(func(args []interface{}) bool {
+		if len(args) != 2 {
+			return false
+		}
+		_, ok := args[1].(time.Time)
+		return ok && args[0] == ID
+	})
+}
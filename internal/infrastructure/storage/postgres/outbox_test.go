@@ -0,0 +1,110 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutboxDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_event", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		outboxDAO := NewOutboxDAO(new(QuerierMock), logger)
+		res, err := outboxDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+	t.Run("error_on_saved_event", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+		outboxDAO := NewOutboxDAO(new(QuerierMock), logger)
+		res, err := outboxDAO.Save(&models.Event{ID: 1})
+
+		assert.Nil(t, res)
+		assert.Equal(t, services.ErrRecordAlreadyExist, err)
+	})
+}
+
+func TestOutboxDAO_FindPending(t *testing.T) {
+	t.Run("query_error", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Query", mock.Anything, mock.Anything).Return((*sql.Rows)(nil), errors.New("dummy"))
+		outboxDAO := NewOutboxDAO(db, logger)
+		res, err := outboxDAO.FindPending(time.Now(), 10)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestOutboxDAO_MarkDelivered(t *testing.T) {
+	t.Run("exec_error", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, mock.Anything).Return(driver.RowsAffected(0), errors.New("dummy"))
+		outboxDAO := NewOutboxDAO(db, logger)
+
+		assert.Error(t, outboxDAO.MarkDelivered(1))
+	})
+}
+
+func TestOutboxDAO_MarkConsumed(t *testing.T) {
+	t.Run("exec_error", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, mock.Anything).Return(driver.RowsAffected(0), errors.New("dummy"))
+		outboxDAO := NewOutboxDAO(db, logger)
+
+		assert.Error(t, outboxDAO.MarkConsumed(1, "events"))
+	})
+	t.Run("event_not_found", func(t *testing.T) {
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, mock.Anything).
+			Return(driver.RowsAffected(0), &pq.Error{Constraint: "outbox_consumers_event_fkey"})
+		outboxDAO := NewOutboxDAO(db, new(LoggerMock))
+
+		assert.Equal(t, services.ErrRecordNotFound, outboxDAO.MarkConsumed(1, "events"))
+	})
+}
+
+func TestOutboxDAO_MarkFailed(t *testing.T) {
+	t.Run("dead", func(t *testing.T) {
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, mock.Anything).Return(driver.RowsAffected(1), nil)
+		outboxDAO := NewOutboxDAO(db, new(LoggerMock))
+
+		assert.Nil(t, outboxDAO.MarkFailed(1, nil))
+		args := db.Calls[0].Arguments.Get(1).([]interface{})
+		assert.Nil(t, args[0])
+		assert.IsType(t, time.Time{}, args[1])
+	})
+	t.Run("not_found", func(t *testing.T) {
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, mock.Anything).Return(driver.RowsAffected(0), nil)
+		outboxDAO := NewOutboxDAO(db, new(LoggerMock))
+		next := time.Now()
+
+		assert.Equal(t, services.ErrRecordNotFound, outboxDAO.MarkFailed(1, &next))
+	})
+}
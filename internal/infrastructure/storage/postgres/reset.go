@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Reset removes all the records of the current schema and restarts the ID
+// sequences, the migrations history is kept. It is used for end to end tests.
+func Reset(db querier) error {
+	rows, err := db.Query(`
+		select tablename from pg_tables
+		where schemaname = current_schema() and tablename <> 'schema_migrations';`,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	tables := make([]string, 0)
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			return err
+		}
+		tables = append(tables, pq.QuoteIdentifier(table))
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+
+	_, err = db.Exec("truncate " + strings.Join(tables, ", ") + " restart identity cascade;")
+
+	return err
+}
@@ -0,0 +1,22 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReset(t *testing.T) {
+	t.Run("query_error", func(t *testing.T) {
+		db := new(QuerierMock)
+		db.On("Query", mock.Anything, mock.Anything).Return((*sql.Rows)(nil), errors.New("dummy"))
+
+		assert.Error(t, Reset(db))
+		db.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,351 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+const ruleColumns = `id, created_at, updated_at, board, name, "trigger", conditions, actions, disabled, created_by`
+
+// RuleDAO is a data access object for the automation rules
+type RuleDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewRuleDAO represents a RuleDAO constructor
+func NewRuleDAO(db querier, log log.Logger) RuleDAO {
+	return RuleDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided rule into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao RuleDAO) Save(rule *models.Rule) (*models.Rule, error) {
+	if rule == nil {
+		dao.log.Error("rules storage: nil pointer given")
+		return nil, errors.New("nil rule pointer given")
+	}
+	if rule.ID > 0 {
+		dao.log.Warnf("rules storage: %v, ID: %d", sv.ErrRecordAlreadyExist, rule.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	if err := dao.scan(dao.db.QueryRow(
+		`insert into rules (board, name, "trigger", conditions, actions, disabled, created_by)
+		values ($1, $2, $3, $4, $5, $6, $7)
+		returning `+ruleColumns,
+		rule.BoardID,
+		rule.Name,
+		rule.Trigger,
+		rule.Conditions,
+		rule.Actions,
+		rule.Disabled,
+		rule.CreatedBy,
+	), rule); err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Constraint == "rules_board_fkey" {
+			return nil, sv.ErrBoardRelation
+		}
+		dao.log.Errorf("rules storage: error while inserting a row: %v", err)
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// FindOneById will return a pointer to a rule with the provided ID or
+// nil and an error
+func (dao RuleDAO) FindOneById(ID uint) (*models.Rule, error) {
+	rule := &models.Rule{}
+	if err := dao.scan(
+		dao.db.QueryRow(`select `+ruleColumns+` from rules where id = $1`, ID),
+		rule,
+	); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("rules storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return rule, nil
+}
+
+// Find will return the rules of the board that fit the provided page sorted
+// by ID or an error
+func (dao RuleDAO) Find(boardID uint, page sv.Page) ([]*models.Rule, error) {
+	where, args := "board = $1", []interface{}{boardID}
+	if page.After != nil {
+		args = append(args, page.After.ID)
+		where = where + fmt.Sprintf(" and id > $%d", len(args))
+	}
+
+	return dao.query(
+		fmt.Sprintf(`select %s from rules where %s order by id%s`, ruleColumns, where, limit(page)),
+		args...,
+	)
+}
+
+// FindTriggered will return the enabled rules with the provided trigger type sorted by
+// ID, of the board with the provided ID or of all the boards that are not deleted for
+// the zero ID
+func (dao RuleDAO) FindTriggered(trigger models.TriggerType, boardID uint) ([]*models.Rule, error) {
+	where, args := `not disabled and "trigger"->>'type' = $1`, []interface{}{trigger}
+	if boardID != 0 {
+		args = append(args, boardID)
+		where = where + fmt.Sprintf(" and board = $%d", len(args))
+	} else {
+		where = where + " and board in (select id from boards where deleted_at is null)"
+	}
+
+	return dao.query(
+		fmt.Sprintf(`select %s from rules where %s order by id`, ruleColumns, where),
+		args...,
+	)
+}
+
+// Update will update the name, the trigger, the conditions, the actions and the
+// disabled flag of the rule
+func (dao RuleDAO) Update(rule *models.Rule) (*models.Rule, error) {
+	if rule == nil {
+		dao.log.Error("rules storage: nil pointer given")
+		return nil, errors.New("nil rule pointer given")
+	}
+
+	if err := dao.scan(dao.db.QueryRow(
+		`update rules
+		set updated_at = $1, name = $2, "trigger" = $3, conditions = $4, actions = $5, disabled = $6
+		where id = $7
+		returning `+ruleColumns,
+		time.Now(),
+		rule.Name,
+		rule.Trigger,
+		rule.Conditions,
+		rule.Actions,
+		rule.Disabled,
+		rule.ID,
+	), rule); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sv.ErrRecordNotFound
+		}
+		dao.log.Errorf("rules storage: error while updating a row: %v", err)
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// Delete will delete the rule, its executions are deleted by the cascade
+// foreign key
+func (dao RuleDAO) Delete(ID uint) error {
+	res, err := dao.db.Exec("delete from rules where id = $1", ID)
+	if err != nil {
+		dao.log.Errorf("rules storage: error while deleting a row: %v", err)
+		return err
+	}
+
+	return expectOneRow(res)
+}
+
+func (dao RuleDAO) query(query string, args ...interface{}) ([]*models.Rule, error) {
+	rows, err := dao.db.Query(query, args...)
+	if err != nil {
+		dao.log.Errorf("rules storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	rules := make([]*models.Rule, 0)
+	for rows.Next() {
+		rule := &models.Rule{}
+		if err := dao.scan(rows, rule); err != nil {
+			dao.log.Errorf("rules storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("rules storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (dao RuleDAO) scan(row scanner, rule *models.Rule) error {
+	return row.Scan(
+		&rule.ID,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+		&rule.BoardID,
+		&rule.Name,
+		&rule.Trigger,
+		&rule.Conditions,
+		&rule.Actions,
+		&rule.Disabled,
+		&rule.CreatedBy,
+	)
+}
+
+const executionColumns = "id, created_at, rule, event, task, status, error"
+
+// ExecutionDAO is a data access object for the execution log of the automation rules
+type ExecutionDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewExecutionDAO represents an ExecutionDAO constructor
+func NewExecutionDAO(db querier, log log.Logger) ExecutionDAO {
+	return ExecutionDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided execution into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao ExecutionDAO) Save(execution *models.RuleExecution) (*models.RuleExecution, error) {
+	if execution == nil {
+		dao.log.Error("executions storage: nil pointer given")
+		return nil, errors.New("nil execution pointer given")
+	}
+	if execution.ID > 0 {
+		dao.log.Warnf("executions storage: %v, ID: %d", sv.ErrRecordAlreadyExist, execution.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	var eventID *uint
+	if execution.EventID != 0 {
+		eventID = &execution.EventID
+	}
+	// the time is compared to the due dates of the tasks, which are stored in UTC
+	if err := dao.scan(dao.db.QueryRow(
+		`insert into rule_executions (created_at, rule, event, task, status, error)
+		values ($1, $2, $3, $4, $5, $6)
+		returning `+executionColumns,
+		time.Now().UTC(),
+		execution.RuleID,
+		eventID,
+		execution.TaskID,
+		execution.Status,
+		execution.Error,
+	), execution); err != nil {
+		dao.log.Errorf("executions storage: error while inserting a row: %v", err)
+		return nil, err
+	}
+
+	return execution, nil
+}
+
+// Find will return the executions of the rule that fit the provided page from
+// the newest to the oldest or an error
+func (dao ExecutionDAO) Find(ruleID uint, page sv.Page) ([]*models.RuleExecution, error) {
+	where, args := "rule = $1", []interface{}{ruleID}
+	if page.After != nil {
+		args = append(args, page.After.ID)
+		where = where + fmt.Sprintf(" and id < $%d", len(args))
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(`select %s from rule_executions where %s order by id desc%s`, executionColumns, where, limit(page)),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("executions storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	executions := make([]*models.RuleExecution, 0)
+	for rows.Next() {
+		execution := &models.RuleExecution{}
+		if err := dao.scan(rows, execution); err != nil {
+			dao.log.Errorf("executions storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		executions = append(executions, execution)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("executions storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return executions, nil
+}
+
+// FindLast will return a pointer to the latest execution of the rule on the task or
+// nil and an error
+func (dao ExecutionDAO) FindLast(ruleID, taskID uint) (*models.RuleExecution, error) {
+	execution := &models.RuleExecution{}
+	if err := dao.scan(
+		dao.db.QueryRow(
+			`select `+executionColumns+` from rule_executions where rule = $1 and task = $2 order by id desc limit 1`,
+			ruleID,
+			taskID,
+		),
+		execution,
+	); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("executions storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return execution, nil
+}
+
+// FindByEvent will return a pointer to the execution of the rule run by the event or
+// nil and an error
+func (dao ExecutionDAO) FindByEvent(ruleID, eventID uint) (*models.RuleExecution, error) {
+	execution := &models.RuleExecution{}
+	if err := dao.scan(
+		dao.db.QueryRow(
+			`select `+executionColumns+` from rule_executions where rule = $1 and event = $2 order by id limit 1`,
+			ruleID,
+			eventID,
+		),
+		execution,
+	); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("executions storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return execution, nil
+}
+
+func (dao ExecutionDAO) scan(row scanner, execution *models.RuleExecution) error {
+	var eventID sql.NullInt64
+	if err := row.Scan(
+		&execution.ID,
+		&execution.CreatedAt,
+		&execution.RuleID,
+		&eventID,
+		&execution.TaskID,
+		&execution.Status,
+		&execution.Error,
+	); err != nil {
+		return err
+	}
+	execution.EventID = uint(eventID.Int64)
+
+	return nil
+}
@@ -0,0 +1,67 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRuleDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_rule", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		ruleDAO := NewRuleDAO(new(QuerierMock), logger)
+		res, err := ruleDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestRuleDAO_Update(t *testing.T) {
+	t.Run("error_on_nil_rule", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		ruleDAO := NewRuleDAO(new(QuerierMock), logger)
+		res, err := ruleDAO.Update(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestRuleDAO_Delete(t *testing.T) {
+	t.Run("exec_error", func(t *testing.T) {
+		const ID uint = 0
+		var result driver.RowsAffected = 0
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, []interface{}{ID}).Return(result, errors.New("dummy"))
+		ruleDAO := NewRuleDAO(db, logger)
+		err := ruleDAO.Delete(ID)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutionDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_execution", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		executionDAO := NewExecutionDAO(new(QuerierMock), logger)
+		res, err := executionDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+)
+
+// SearchDAO is a data access object for the full-text search of tasks and comments
+type SearchDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewSearchDAO represents a SearchDAO constructor
+func NewSearchDAO(db querier, log log.Logger) SearchDAO {
+	return SearchDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// searchSelect lists the tasks and the comments matching the query ($1) along with
+// their ranks and the snippets of their texts with the matched words highlighted
+const searchSelect = `
+	select 'task' as type, t.id, t.id as task, t."column", c.board, coalesce(t.name, '') as name,
+		ts_headline('english', coalesce(t.name, '') || ' ' || t.description, q.query, 'MaxFragments=2') as snippet,
+		ts_rank(t.search, q.query)::float8 as rank
+	from tasks t
+	join columns c on c.id = t."column"
+	join (select websearch_to_tsquery('english', $1) as query) q on t.search @@ q.query
+	where t.deleted_at is null
+	union all
+	select 'comment', cm.id, cm.task, t."column", c.board, coalesce(t.name, ''),
+		ts_headline('english', coalesce(cm.text, ''), q.query, 'MaxFragments=2'),
+		ts_rank(cm.search, q.query)::float8
+	from comments cm
+	join tasks t on t.id = cm.task
+	join columns c on c.id = t."column"
+	join (select websearch_to_tsquery('english', $1) as query) q on cm.search @@ q.query
+	where cm.deleted_at is null`
+
+// Find will return the tasks and the comments matching the query of the provided demand
+// that meet its other constraints and fit the provided page, from the highest rank to
+// the lowest, or an error
+func (dao SearchDAO) Find(demand sv.SearchDemand, page sv.Page) ([]*models.SearchHit, error) {
+	where, args := "true", []interface{}{demand["q"]}
+	if boardID, ok := demand["board"]; ok {
+		args = append(args, boardID)
+		where = where + fmt.Sprintf(" and board = $%d", len(args))
+	}
+	if userID, ok := demand["member"]; ok {
+		args = append(args, userID)
+		where = where + fmt.Sprintf(" and board in (select board_id from board_members where user_id = $%d)", len(args))
+	}
+	if page.After != nil {
+		args = append(args, page.After.Rank, page.After.Type, page.After.ID)
+		where = where + fmt.Sprintf(
+			" and (rank < $%d or rank = $%d and (type, id) > ($%d, $%d))",
+			len(args)-2, len(args)-2, len(args)-1, len(args),
+		)
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(
+			`select type, id, task, "column", board, name, snippet, rank from (%s) hits where %s order by rank desc, type, id%s;`,
+			searchSelect,
+			where,
+			limit(page),
+		),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("search storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	hits := make([]*models.SearchHit, 0)
+	for rows.Next() {
+		hit := &models.SearchHit{}
+		if err := rows.Scan(
+			&hit.Type,
+			&hit.ID,
+			&hit.TaskID,
+			&hit.ColumnID,
+			&hit.BoardID,
+			&hit.Name,
+			&hit.Snippet,
+			&hit.Rank,
+		); err != nil {
+			dao.log.Errorf("search storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("search storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,30 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSearchDAO_Find(t *testing.T) {
+	t.Run("query_error", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Query", mock.Anything, []interface{}{"release", uint(1), 0.5, "task", uint(7)}).
+			Return((*sql.Rows)(nil), errors.New("dummy"))
+		searchDAO := NewSearchDAO(db, logger)
+		demand := services.SearchDemand{"q": "release", "board": uint(1)}
+		res, err := searchDAO.Find(demand, services.Page{After: &services.Cursor{ID: 7, Type: "task", Rank: 0.5}})
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
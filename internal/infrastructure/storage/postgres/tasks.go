@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/dnozdrin/detask/internal/app/log"
 	"github.com/pkg/errors"
+	"strings"
 	"time"
 
 	sv "github.com/dnozdrin/detask/internal/domain/services"
@@ -13,6 +14,12 @@ import (
 	"github.com/dnozdrin/detask/internal/domain/models"
 )
 
+// assigneesSelect selects the sorted IDs of the task assignees as an array
+const assigneesSelect = `array(select a.user_id from task_assignees a where a.task_id = t.id order by a.user_id)`
+
+// labelsSelect selects the sorted IDs of the task labels as an array
+const labelsSelect = `array(select l.label_id from task_labels l where l.task_id = t.id order by l.label_id)`
+
 // TaskDAO is a data access object for boards
 type TaskDAO struct {
 	db  querier
@@ -40,28 +47,47 @@ func (dao TaskDAO) Save(task *models.Task) (*models.Task, error) {
 	}
 
 	stmt, err := dao.db.Prepare(`
-		insert into tasks (name, description, "column", position)
-		values ($1, $2, $3, $4)
-		returning id, created_at, updated_at, name, description, "column", position;`,
+		insert into tasks (name, description, "column", position, created_by, reporter, start_at, due_at, priority)
+		values ($1, $2, $3, $4, nullif($5, 0), nullif($6, 0), $7, $8, coalesce(nullif($9, 0), 3))
+		returning id, created_at, updated_at, version, name, description, "column", position,
+			coalesce(created_by, 0), coalesce(reporter, 0), start_at, due_at, priority;`,
 	)
 	if err != nil {
 		dao.log.Errorf("tasks storage: failed to prepare statement: %v", err)
 		return nil, err
 	}
 	defer deferred(dao.log, stmt.Close)
-	if err = stmt.QueryRow(task.Name, task.Description, task.ColumnID, task.Position).Scan(
+	if err = stmt.QueryRow(
+		task.Name,
+		task.Description,
+		task.ColumnID,
+		task.Position,
+		task.CreatedBy,
+		task.ReporterID,
+		task.StartAt,
+		task.DueAt,
+		task.Priority,
+	).Scan(
 		&task.ID,
 		&task.CreatedAt,
 		&task.UpdatedAt,
+		&task.Version,
 		&task.Name,
 		&task.Description,
 		&task.ColumnID,
 		&task.Position,
+		&task.CreatedBy,
+		&task.ReporterID,
+		&task.StartAt,
+		&task.DueAt,
+		&task.Priority,
 	); err != nil {
 		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code.Class().Name() == "integrity_constraint_violation" {
 			switch pgErr.Constraint {
 			case "tasks_column_fkey":
 				err = sv.ErrColumnRelation
+			case "tasks_reporter_fkey":
+				err = sv.ErrUserRelation
 			case "tasks_position_column_key":
 				err = sv.ErrPositionDuplicate
 			default:
@@ -82,11 +108,29 @@ func (dao TaskDAO) Save(task *models.Task) (*models.Task, error) {
 func (dao TaskDAO) FindOneById(ID uint) (*models.Task, error) {
 	task := &models.Task{}
 	err := dao.db.QueryRow(`
-		select id, created_at, updated_at, name, description, "column", position
-		from tasks
-		where id = $1
+		select t.id, t.created_at, t.updated_at, t.version, t.name, t.description, t.column, t.position,
+			coalesce(t.created_by, 0), coalesce(t.reporter, 0), t.start_at, t.due_at, t.priority,
+			`+assigneesSelect+`, `+labelsSelect+`
+		from tasks t
+		where t.id = $1 and t.deleted_at is null
 		`, ID).
-		Scan(&task.ID, &task.CreatedAt, &task.UpdatedAt, &task.Name, &task.Description, &task.ColumnID, &task.Position)
+		Scan(
+			&task.ID,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+			&task.Version,
+			&task.Name,
+			&task.Description,
+			&task.ColumnID,
+			&task.Position,
+			&task.CreatedBy,
+			&task.ReporterID,
+			&task.StartAt,
+			&task.DueAt,
+			&task.Priority,
+			uintArray(&task.Assignees),
+			uintArray(&task.Labels),
+		)
 	if err != nil {
 		if err != sql.ErrNoRows {
 			dao.log.Errorf("tasks storage: error while querying a row: %v", err)
@@ -99,14 +143,18 @@ func (dao TaskDAO) FindOneById(ID uint) (*models.Task, error) {
 	return task, err
 }
 
-// Find will return all found tasks that meet the provided demand or an error
-func (dao TaskDAO) Find(demand sv.TaskDemand) ([]*models.Task, error) {
+// Find will return all found tasks that meet the provided demand and fit
+// the provided page or an error
+func (dao TaskDAO) Find(demand sv.TaskDemand, page sv.Page) ([]*models.Task, error) {
 	tasks := make([]*models.Task, 0)
 
-	const querySelect = "t.id, t.created_at, t.updated_at, t.name, t.description, t.column, t.position"
+	const querySelect = `t.id, t.created_at, t.updated_at, t.version, t.name, t.description, t.column, t.position,
+		coalesce(t.created_by, 0), coalesce(t.reporter, 0), t.start_at, t.due_at, t.priority,
+		` + assigneesSelect + `, ` + labelsSelect
 	var join, where string
+	args := make([]interface{}, 0)
 
-	where = "1=1"
+	where = "t.deleted_at is null"
 	if boardID, ok := demand["board"]; ok {
 		join = `join "columns" c on t.column = c.id`
 		where = where + fmt.Sprintf(" and c.board = %d", boardID)
@@ -114,8 +162,55 @@ func (dao TaskDAO) Find(demand sv.TaskDemand) ([]*models.Task, error) {
 	if columnID, ok := demand["column"]; ok {
 		where = where + fmt.Sprintf(" and t.column = %d", columnID)
 	}
+	if userID, ok := demand["assignee"]; ok {
+		where = where + fmt.Sprintf(" and t.id in (select task_id from task_assignees where user_id = %d)", userID)
+	}
+	if labelID, ok := demand["label"]; ok {
+		where = where + fmt.Sprintf(" and t.id in (select task_id from task_labels where label_id = %d)", labelID)
+	}
+	if due, ok := demand["due_before"]; ok {
+		args = append(args, due)
+		where = where + fmt.Sprintf(" and t.due_at < $%d", len(args))
+	}
+	if due, ok := demand["due_after"]; ok {
+		args = append(args, due)
+		where = where + fmt.Sprintf(" and t.due_at > $%d", len(args))
+	}
+	if overdue, ok := demand["overdue"].(bool); ok {
+		args = append(args, time.Now().UTC())
+		if overdue {
+			where = where + fmt.Sprintf(" and t.due_at < $%d", len(args))
+		} else {
+			where = where + fmt.Sprintf(" and (t.due_at is null or t.due_at >= $%d)", len(args))
+		}
+	}
+	if priority, ok := demand["priority"]; ok {
+		args = append(args, priority)
+		where = where + fmt.Sprintf(" and t.priority = $%d", len(args))
+	}
+	if query, ok := demand["q"]; ok {
+		args = append(args, query)
+		where = where + fmt.Sprintf(" and t.search @@ websearch_to_tsquery('english', $%d)", len(args))
+	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(
+			` and t.column in (select mc.id from columns mc join board_members m on m.board_id = mc.board where m.user_id = %d)`,
+			userID,
+		)
+	}
+	if page.After != nil {
+		var keyset string
+		keyset, args = taskKeyset(demand.Sort(), page.After, args)
+		where = where + " and " + keyset
+	}
 
-	rows, err := dao.db.Query(fmt.Sprintf(`select %s from tasks t %s where %s order by position;`, querySelect, join, where))
+	rows, err := dao.db.Query(
+		fmt.Sprintf(
+			`select %s from tasks t %s where %s order by %s%s;`,
+			querySelect, join, where, taskOrder(demand.Sort()), limit(page),
+		),
+		args...,
+	)
 	if err != nil {
 		dao.log.Errorf("tasks storage: error while querying rows: %v", err)
 		return nil, err
@@ -129,10 +224,18 @@ func (dao TaskDAO) Find(demand sv.TaskDemand) ([]*models.Task, error) {
 			&task.ID,
 			&task.CreatedAt,
 			&task.UpdatedAt,
+			&task.Version,
 			&task.Name,
 			&task.Description,
 			&task.ColumnID,
 			&task.Position,
+			&task.CreatedBy,
+			&task.ReporterID,
+			&task.StartAt,
+			&task.DueAt,
+			&task.Priority,
+			uintArray(&task.Assignees),
+			uintArray(&task.Labels),
 		); err != nil {
 			return nil, err
 		}
@@ -147,7 +250,9 @@ func (dao TaskDAO) Find(demand sv.TaskDemand) ([]*models.Task, error) {
 	return tasks, nil
 }
 
-// Update will update text of the persistent representation of the task
+// Update will update text of the persistent representation of the task and bump
+// its version, the reporter and the priority are updated only if new ones are provided.
+// The task is updated only if it has the version of the provided one, unless it is zero
 func (dao TaskDAO) Update(task *models.Task) (*models.Task, error) {
 	if task == nil {
 		dao.log.Error("tasks storage: nil pointer given")
@@ -155,23 +260,44 @@ func (dao TaskDAO) Update(task *models.Task) (*models.Task, error) {
 	}
 	stmt, err := dao.db.Prepare(`
 		update tasks
-		set updated_at = $1, name = $2, description = $3, position = $4, "column" = $5
-		where id = $6
-		returning id, created_at, updated_at, name, description, "column", position
+		set updated_at = $1, name = $2, description = $3, position = $4, "column" = $5,
+			reporter = coalesce(nullif($7, 0), reporter), start_at = $8, due_at = $9,
+			priority = coalesce(nullif($10, 0), priority), version = version + 1
+		where id = $6 and version = coalesce(nullif($11, 0), version) and deleted_at is null
+		returning id, created_at, updated_at, version, name, description, "column", position,
+			coalesce(created_by, 0), coalesce(reporter, 0), start_at, due_at, priority
 	`)
 	if err != nil {
 		dao.log.Errorf("tasks storage: failed to prepare statement: %v", err)
 		return nil, err
 	}
 	defer deferred(dao.log, stmt.Close)
-	if err = stmt.QueryRow(time.Now(), task.Name, task.Description, task.Position, task.ColumnID, task.ID).Scan(
+	if err = stmt.QueryRow(
+		time.Now(),
+		task.Name,
+		task.Description,
+		task.Position,
+		task.ColumnID,
+		task.ID,
+		task.ReporterID,
+		task.StartAt,
+		task.DueAt,
+		task.Priority,
+		task.Version,
+	).Scan(
 		&task.ID,
 		&task.CreatedAt,
 		&task.UpdatedAt,
+		&task.Version,
 		&task.Name,
 		&task.Description,
 		&task.ColumnID,
 		&task.Position,
+		&task.CreatedBy,
+		&task.ReporterID,
+		&task.StartAt,
+		&task.DueAt,
+		&task.Priority,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			err = sv.ErrRecordNotFound
@@ -179,6 +305,8 @@ func (dao TaskDAO) Update(task *models.Task) (*models.Task, error) {
 			switch pgErr.Constraint {
 			case "tasks_column_fkey":
 				err = sv.ErrColumnRelation
+			case "tasks_reporter_fkey":
+				err = sv.ErrUserRelation
 			case "tasks_position_column_key":
 				err = sv.ErrPositionDuplicate
 			default:
@@ -194,9 +322,121 @@ func (dao TaskDAO) Update(task *models.Task) (*models.Task, error) {
 	return task, nil
 }
 
-// MoveToColumn will move all tasks from source column to target column
+// SetAssignees will replace the assignees of the task with the provided ID
+func (dao TaskDAO) SetAssignees(taskID uint, userIDs []uint) error {
+	if _, err := dao.db.Exec(`delete from task_assignees where task_id = $1`, taskID); err != nil {
+		dao.log.Errorf("tasks storage: error while deleting assignees: %v", err)
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	IDs := make(pq.Int64Array, len(userIDs))
+	for i, userID := range userIDs {
+		IDs[i] = int64(userID)
+	}
+	if _, err := dao.db.Exec(
+		`insert into task_assignees (task_id, user_id) select $1, unnest($2::int[])`,
+		taskID,
+		IDs,
+	); err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Constraint == "task_assignees_user_id_fkey" {
+			return sv.ErrUserRelation
+		}
+		dao.log.Errorf("tasks storage: error while inserting assignees: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetLabels will replace the labels of the task with the provided ID,
+// the labels must belong to the board of the task
+func (dao TaskDAO) SetLabels(taskID uint, labelIDs []uint) error {
+	if _, err := dao.db.Exec(`delete from task_labels where task_id = $1`, taskID); err != nil {
+		dao.log.Errorf("tasks storage: error while deleting labels: %v", err)
+		return err
+	}
+	if len(labelIDs) == 0 {
+		return nil
+	}
+
+	IDs := make(pq.Int64Array, len(labelIDs))
+	for i, labelID := range labelIDs {
+		IDs[i] = int64(labelID)
+	}
+	res, err := dao.db.Exec(`
+		insert into task_labels (task_id, label_id)
+		select t.id, l.id
+		from tasks t
+		join columns c on c.id = t."column"
+		join labels l on l.board = c.board
+		where t.id = $1 and l.id = any($2::int[])`,
+		taskID,
+		IDs,
+	)
+	if err != nil {
+		dao.log.Errorf("tasks storage: error while inserting labels: %v", err)
+		return err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if inserted != int64(len(labelIDs)) {
+		return sv.ErrLabelRelation
+	}
+
+	return nil
+}
+
+// MatchLabels will replace the labels of the task with the labels of the same names
+// on the board of its column, the labels without a match are dropped
+func (dao TaskDAO) MatchLabels(taskID uint) error {
+	if _, err := dao.db.Exec(`
+		delete from task_labels
+		where task_id = $1 and label_id not in (
+			select source.id
+			from tasks t
+			join columns c on c.id = t."column"
+			join labels target on target.board = c.board
+			join labels source on source.name = target.name
+			where t.id = $1
+		)`,
+		taskID,
+	); err != nil {
+		dao.log.Errorf("tasks storage: error while dropping unmatched labels: %v", err)
+		return err
+	}
+	if _, err := dao.db.Exec(`
+		update task_labels
+		set label_id = (
+			select target.id
+			from tasks t
+			join columns c on c.id = t."column"
+			join labels target on target.board = c.board
+			join labels source on source.name = target.name
+			where t.id = task_labels.task_id and source.id = task_labels.label_id
+		)
+		where task_id = $1`,
+		taskID,
+	); err != nil {
+		dao.log.Errorf("tasks storage: error while matching labels: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// MoveToColumn will move all tasks from source column to target column, the deleted
+// tasks are left in the source column
 func (dao TaskDAO) MoveToColumn(sourceID, targetID uint) error {
-	if _, err := dao.db.Exec(`update tasks set "column" = $1 where "column" = $2`, targetID, sourceID); err != nil {
+	if _, err := dao.db.Exec(
+		`update tasks set "column" = $1, version = version + 1 where "column" = $2 and deleted_at is null`,
+		targetID,
+		sourceID,
+	); err != nil {
 		dao.log.Errorf(
 			"tasks storage: error while moving tasks from column %d to column %d: %v",
 			sourceID,
@@ -209,9 +449,94 @@ func (dao TaskDAO) MoveToColumn(sourceID, targetID uint) error {
 	return nil
 }
 
-// Delete will delete the record in the database
+// ColumnLoad will return the WIP limit of the column with the provided ID and the
+// number of tasks in it. The column row is locked until the end of the transaction,
+// so that concurrent transactions can not add tasks to the column meanwhile.
+func (dao TaskDAO) ColumnLoad(columnID uint) (limit, count uint, err error) {
+	if err = dao.db.QueryRow(
+		`select coalesce(wip_limit, 0) from columns where id = $1 and deleted_at is null for update`,
+		columnID,
+	).Scan(&limit); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, sv.ErrColumnRelation
+		}
+		dao.log.Errorf("tasks storage: error while locking column %d: %v", columnID, err)
+		return 0, 0, err
+	}
+	if err = dao.db.QueryRow(`select count(*) from tasks where "column" = $1 and deleted_at is null`, columnID).Scan(&count); err != nil {
+		dao.log.Errorf("tasks storage: error while counting tasks of column %d: %v", columnID, err)
+		return 0, 0, err
+	}
+
+	return limit, count, nil
+}
+
+// ColumnBoard will return the ID of the board of the column with the provided ID
+func (dao TaskDAO) ColumnBoard(columnID uint) (boardID uint, err error) {
+	if err = dao.db.QueryRow(
+		`select board from columns where id = $1 and deleted_at is null`,
+		columnID,
+	).Scan(&boardID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, sv.ErrColumnRelation
+		}
+		dao.log.Errorf("tasks storage: error while finding board of column %d: %v", columnID, err)
+		return 0, err
+	}
+
+	return boardID, nil
+}
+
+// Rebalance will place the tasks with the provided IDs into the column in the given
+// order, with the positions evenly spread by the step. The tasks are moved below the
+// lowest position of the column first, so that the new positions do not collide with
+// the old ones on the unique (position, column) constraint.
+func (dao TaskDAO) Rebalance(columnID uint, IDs []uint, step float64) error {
+	var lowest float64
+	if err := dao.db.QueryRow(
+		`select least(coalesce(min(position), 0), 0) from tasks where "column" = $1`,
+		columnID,
+	).Scan(&lowest); err != nil {
+		dao.log.Errorf("tasks storage: error while rebalancing column %d: %v", columnID, err)
+		return err
+	}
+	for i, ID := range IDs {
+		if _, err := dao.db.Exec(
+			`update tasks set "column" = $1, position = $2 where id = $3`,
+			columnID,
+			lowest-float64(i+1),
+			ID,
+		); err != nil {
+			dao.log.Errorf("tasks storage: error while rebalancing column %d: %v", columnID, err)
+			return err
+		}
+	}
+	now := time.Now()
+	for i, ID := range IDs {
+		if _, err := dao.db.Exec(
+			`update tasks set position = $1, updated_at = $2, version = version + 1 where id = $3`,
+			step*float64(i+1),
+			now,
+			ID,
+		); err != nil {
+			dao.log.Errorf("tasks storage: error while rebalancing column %d: %v", columnID, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete will mark the task as deleted along with its comments
 func (dao TaskDAO) Delete(ID uint) error {
-	if _, err := dao.db.Exec("delete from tasks where id = $1", ID); err != nil {
+	if _, err := dao.db.Exec(`
+		with deleted_comments as (
+			update comments set deleted_at = $2 where task = $1 and deleted_at is null
+		)
+		update tasks set deleted_at = $2 where id = $1 and deleted_at is null`,
+		ID,
+		time.Now(),
+	); err != nil {
 		dao.log.Errorf("tasks storage: error while deleting a row: %v", err)
 		return err
 	}
@@ -224,3 +549,64 @@ func (dao TaskDAO) WithTx(tx *sql.Tx) sv.TaskStorage {
 	dao.db = tx
 	return dao
 }
+
+// taskSortColumns maps the fields the tasks may be sorted by to the columns
+var taskSortColumns = map[string]string{
+	"position": "t.position",
+	"priority": "t.priority",
+	"due":      "t.due_at",
+}
+
+// taskOrder builds the order by clause of the provided sort, the tasks without
+// a due date go last in both directions
+func taskOrder(sort sv.Sort) string {
+	order := make([]string, 0, len(sort)+1)
+	for _, key := range sort {
+		column := taskSortColumns[key.Field]
+		if key.Field == "due" {
+			order = append(order, column+" is null")
+		}
+		if key.Desc {
+			column = column + " desc"
+		}
+		order = append(order, column)
+	}
+
+	return strings.Join(append(order, "t.id"), ", ")
+}
+
+// taskKeyset builds the condition that selects the tasks following the one
+// the cursor points to in the provided sort order, and appends its values to the args
+func taskKeyset(sort sv.Sort, after *sv.Cursor, args []interface{}) (string, []interface{}) {
+	values := map[string]interface{}{
+		"position": after.Position,
+		"priority": after.Priority,
+		"due":      after.DueAt,
+	}
+
+	follows, equal := make([]string, 0, len(sort)+1), make([]string, 0, len(sort))
+	for _, key := range sort {
+		column := taskSortColumns[key.Field]
+		if key.Field == "due" && after.DueAt == nil {
+			// only the tasks without a due date follow the one without it
+			equal = append(equal, column+" is null")
+			continue
+		}
+
+		args = append(args, values[key.Field])
+		operator := ">"
+		if key.Desc {
+			operator = "<"
+		}
+		next := fmt.Sprintf("%s %s $%d", column, operator, len(args))
+		if key.Field == "due" {
+			next = fmt.Sprintf("(%s or %s is null)", next, column)
+		}
+		follows = append(follows, strings.Join(append(equal, next), " and "))
+		equal = append(equal, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	args = append(args, after.ID)
+	follows = append(follows, strings.Join(append(equal, fmt.Sprintf("t.id > $%d", len(args))), " and "))
+
+	return "(" + strings.Join(follows, " or ") + ")", args
+}
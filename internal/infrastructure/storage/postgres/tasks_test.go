@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestTaskDAO_Save(t *testing.T) {
@@ -75,7 +76,7 @@ func TestTaskDAO_Delete(t *testing.T) {
 		logger.On("Errorf", mock.Anything, mock.Anything).Return()
 
 		db := new(QuerierMock)
-		db.On("Exec", mock.Anything, []interface{}{ID}).Return(result, errors.New("dummy"))
+		db.On("Exec", mock.Anything, deletionArgs(ID)).Return(result, errors.New("dummy"))
 		tasksDAO := NewTaskDAO(db, logger)
 		err := tasksDAO.Delete(ID)
 
@@ -118,3 +119,36 @@ func TestTaskDAO_WithTx(t *testing.T) {
 	assert.NotEqual(t, taskDAO, txTaskDAO)
 	assert.Equal(t, txTaskDAO.(TaskDAO).db, tx)
 }
+
+func TestTaskOrder(t *testing.T) {
+	assert.Equal(t, "t.position, t.id", taskOrder(services.Sort{{Field: "position"}}))
+	assert.Equal(
+		t,
+		"t.priority desc, t.due_at is null, t.due_at, t.id",
+		taskOrder(services.Sort{{Field: "priority", Desc: true}, {Field: "due"}}),
+	)
+}
+
+func TestTaskKeyset(t *testing.T) {
+	sort := services.Sort{{Field: "priority", Desc: true}, {Field: "due"}}
+	due := time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("with_due", func(t *testing.T) {
+		after := &services.Cursor{ID: 7, Priority: models.PriorityHigh, DueAt: &due}
+		keyset, args := taskKeyset(sort, after, []interface{}{uint(1)})
+		assert.Equal(
+			t,
+			"(t.priority < $2 or t.priority = $2 and (t.due_at > $3 or t.due_at is null) "+
+				"or t.priority = $2 and t.due_at = $3 and t.id > $4)",
+			keyset,
+		)
+		assert.Equal(t, []interface{}{uint(1), models.PriorityHigh, &due, uint(7)}, args)
+	})
+
+	t.Run("without_due", func(t *testing.T) {
+		after := &services.Cursor{ID: 7, Priority: models.PriorityHigh}
+		keyset, args := taskKeyset(sort, after, nil)
+		assert.Equal(t, "(t.priority < $1 or t.priority = $1 and t.due_at is null and t.id > $2)", keyset)
+		assert.Equal(t, []interface{}{models.PriorityHigh, uint(7)}, args)
+	})
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/lib/pq"
+)
+
+// TrashDAO is a data access object for the deleted boards, columns, tasks and comments
+type TrashDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewTrashDAO represents a TrashDAO constructor
+func NewTrashDAO(db querier, log log.Logger) TrashDAO {
+	return TrashDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// trashSelect lists the deleted records of all types along with the flag telling
+// if the parent of the record is deleted as well
+const trashSelect = `
+	select 'board' as type, b.id, b.name, b.id as board, 0 as parent, b.deleted_at, false as parent_deleted
+	from boards b
+	where b.deleted_at is not null
+	union all
+	select 'column', c.id, c.name, c.board, c.board, c.deleted_at, b.deleted_at is not null
+	from columns c
+	join boards b on b.id = c.board
+	where c.deleted_at is not null
+	union all
+	select 'task', t.id, t.name, c.board, t."column", t.deleted_at, c.deleted_at is not null
+	from tasks t
+	join columns c on c.id = t."column"
+	where t.deleted_at is not null
+	union all
+	select 'comment', cm.id, cm.text, c.board, cm.task, cm.deleted_at, t.deleted_at is not null
+	from comments cm
+	join tasks t on t.id = cm.task
+	join columns c on c.id = t."column"
+	where cm.deleted_at is not null`
+
+// trashRestore clears the deletion time ($2) of the record with the provided ID ($1)
+// and of the dependant records that were deleted along with it
+var trashRestore = map[models.TrashType]string{
+	models.TrashBoard: `
+		with restored_columns as (
+			update columns set deleted_at = null where board = $1 and deleted_at = $2 returning id
+		), restored_tasks as (
+			update tasks set deleted_at = null where "column" in (select id from restored_columns) and deleted_at = $2 returning id
+		), restored_comments as (
+			update comments set deleted_at = null where task in (select id from restored_tasks) and deleted_at = $2
+		)
+		update boards set deleted_at = null where id = $1 and deleted_at = $2`,
+	models.TrashColumn: `
+		with restored_tasks as (
+			update tasks set deleted_at = null where "column" = $1 and deleted_at = $2 returning id
+		), restored_comments as (
+			update comments set deleted_at = null where task in (select id from restored_tasks) and deleted_at = $2
+		)
+		update columns set deleted_at = null where id = $1 and deleted_at = $2`,
+	models.TrashTask: `
+		with restored_comments as (
+			update comments set deleted_at = null where task = $1 and deleted_at = $2
+		)
+		update tasks set deleted_at = null where id = $1 and deleted_at = $2`,
+	models.TrashComment: `update comments set deleted_at = null where id = $1 and deleted_at = $2`,
+}
+
+// trashPlace moves the record with the provided ID ($1) after the last one by the
+// step ($2) if its position has been taken since the record was deleted
+var trashPlace = map[models.TrashType]string{
+	models.TrashColumn: `
+		update columns c
+		set position = (select max(o.position) + $2 from columns o where o.board = c.board and o.deleted_at is null),
+			version = version + 1
+		where c.id = $1 and exists (
+			select 1 from columns o where o.board = c.board and o.position = c.position and o.deleted_at is null
+		)`,
+	models.TrashTask: `
+		update tasks t
+		set position = (select max(o.position) + $2 from tasks o where o."column" = t."column" and o.deleted_at is null),
+			version = version + 1
+		where t.id = $1 and exists (
+			select 1 from tasks o where o."column" = t."column" and o.position = t.position and o.deleted_at is null
+		)`,
+}
+
+// Find will return the deleted records that meet the provided demand and fit the provided
+// page or an error. The records deleted along with their parents are not listed.
+func (dao TrashDAO) Find(demand sv.TrashDemand, page sv.Page) ([]*models.TrashItem, error) {
+	where, args := "not parent_deleted", make([]interface{}, 0)
+	if boardID, ok := demand["board"]; ok {
+		where = where + fmt.Sprintf(" and board = %d", boardID)
+	}
+	if kind, ok := demand["type"]; ok {
+		args = append(args, kind)
+		where = where + fmt.Sprintf(" and type = $%d", len(args))
+	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(" and board in (select board_id from board_members where user_id = %d)", userID)
+	}
+	if page.After != nil {
+		args = append(args, page.After.DeletedAt, page.After.Type, page.After.ID)
+		where = where + fmt.Sprintf(
+			" and (deleted_at < $%d or deleted_at = $%d and (type, id) > ($%d, $%d))",
+			len(args)-2, len(args)-2, len(args)-1, len(args),
+		)
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(
+			`select type, id, name, board, parent, deleted_at from (%s) trash where %s order by deleted_at desc, type, id%s;`,
+			trashSelect,
+			where,
+			limit(page),
+		),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("trash storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	items := make([]*models.TrashItem, 0)
+	for rows.Next() {
+		item := &models.TrashItem{}
+		if err := rows.Scan(&item.Type, &item.ID, &item.Name, &item.BoardID, &item.ParentID, &item.DeletedAt); err != nil {
+			dao.log.Errorf("trash storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("trash storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// FindOne will return the deleted record of the provided type with the provided ID
+// or ErrRecordNotFound if there is no such deleted record
+func (dao TrashDAO) FindOne(kind models.TrashType, ID uint) (*models.TrashItem, error) {
+	item, _, err := dao.findOne(kind, ID)
+
+	return item, err
+}
+
+// Restore will restore the deleted record of the provided type with the provided ID
+// along with the records deleted with it. A restored column or task is placed after
+// the last one by the provided step if its position has been taken meanwhile.
+// The statements should be run within a transaction.
+func (dao TrashDAO) Restore(kind models.TrashType, ID uint, step float64) error {
+	item, parentDeleted, err := dao.findOne(kind, ID)
+	if err != nil {
+		return err
+	}
+	if parentDeleted {
+		return sv.ErrTrashedParent
+	}
+
+	if place, ok := trashPlace[kind]; ok {
+		if _, err = dao.db.Exec(place, ID, step); err != nil {
+			dao.log.Errorf("trash storage: error while placing a restored %s: %v", kind, err)
+			return err
+		}
+	}
+	if _, err = dao.db.Exec(trashRestore[kind], ID, item.DeletedAt); err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code.Class().Name() == "integrity_constraint_violation" {
+			switch pgErr.Constraint {
+			case "columns_name_board_key":
+				return sv.ErrNameDuplicate
+			case "columns_position_board_key", "tasks_position_column_key":
+				return sv.ErrPositionDuplicate
+			}
+		}
+		dao.log.Errorf("trash storage: error while restoring a %s: %v", kind, err)
+		return err
+	}
+
+	return nil
+}
+
+// Purge will permanently delete the records deleted before the provided time along
+// with all the dependant records
+func (dao TrashDAO) Purge(before time.Time) error {
+	for _, table := range []string{"comments", "tasks", "columns", "boards"} {
+		if _, err := dao.db.Exec(fmt.Sprintf(`delete from %s where deleted_at < $1`, table), before); err != nil {
+			dao.log.Errorf("trash storage: error while purging %s: %v", table, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithTx will return the TrashDAO that will use the provided transaction
+func (dao TrashDAO) WithTx(tx *sql.Tx) sv.TrashStorage {
+	dao.db = tx
+	return dao
+}
+
+// findOne will return the deleted record of the provided type with the provided ID
+// and report if its parent is deleted as well
+func (dao TrashDAO) findOne(kind models.TrashType, ID uint) (*models.TrashItem, bool, error) {
+	var parentDeleted bool
+	item := &models.TrashItem{}
+	if err := dao.db.QueryRow(
+		fmt.Sprintf(
+			`select type, id, name, board, parent, deleted_at, parent_deleted from (%s) trash where type = $1 and id = $2`,
+			trashSelect,
+		),
+		kind,
+		ID,
+	).Scan(&item.Type, &item.ID, &item.Name, &item.BoardID, &item.ParentID, &item.DeletedAt, &parentDeleted); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("trash storage: error while querying a row: %v", err)
+			return nil, false, err
+		}
+
+		return nil, false, sv.ErrRecordNotFound
+	}
+
+	return item, parentDeleted, nil
+}
@@ -0,0 +1,55 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestTrashDAO_Find(t *testing.T) {
+	t.Run("query_error", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Query", mock.Anything, []interface{}{"task"}).Return((*sql.Rows)(nil), errors.New("dummy"))
+		trashDAO := NewTrashDAO(db, logger)
+		res, err := trashDAO.Find(services.TrashDemand{"type": "task"}, services.Page{})
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestTrashDAO_Purge(t *testing.T) {
+	before := time.Now()
+
+	t.Run("success", func(t *testing.T) {
+		var result driver.RowsAffected = 1
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, []interface{}{before}).Return(result, nil)
+		trashDAO := NewTrashDAO(db, new(LoggerMock))
+
+		assert.NoError(t, trashDAO.Purge(before))
+		db.AssertNumberOfCalls(t, "Exec", 4)
+	})
+	t.Run("exec_error", func(t *testing.T) {
+		var result driver.RowsAffected = 0
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, []interface{}{before}).Return(result, errors.New("dummy"))
+		trashDAO := NewTrashDAO(db, logger)
+
+		assert.Error(t, trashDAO.Purge(before))
+		db.AssertNumberOfCalls(t, "Exec", 1)
+	})
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+)
+
+// UserDAO is a data access object for users
+type UserDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewUserDAO represents a UserDAO constructor
+func NewUserDAO(db querier, log log.Logger) UserDAO {
+	return UserDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided user into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao UserDAO) Save(user *models.User) (*models.User, error) {
+	if user == nil {
+		dao.log.Error("users storage: nil pointer given")
+		return nil, errors.New("nil user pointer given")
+	}
+	if user.ID > 0 {
+		dao.log.Warnf("users storage: %v, ID: %d", sv.ErrRecordAlreadyExist, user.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	stmt, err := dao.db.Prepare(`
+		insert into users (email, name, password_hash)
+		values ($1, $2, $3)
+		returning id, created_at, updated_at, email, name, password_hash;`,
+	)
+	if err != nil {
+		dao.log.Errorf("users storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	if err = stmt.QueryRow(user.Email, user.Name, user.PasswordHash).Scan(
+		&user.ID,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.Email,
+		&user.Name,
+		&user.PasswordHash,
+	); err != nil {
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Constraint == "users_email_key" {
+			return nil, sv.ErrEmailDuplicate
+		}
+		dao.log.Errorf("users storage: error while querying a row: %v", err)
+
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// FindOneById will return a pointer to a user with the provided ID or
+// nil and an error
+func (dao UserDAO) FindOneById(ID uint) (*models.User, error) {
+	return dao.findOne("id = $1", ID)
+}
+
+// FindOneByEmail will return a pointer to a user with the provided email or
+// nil and an error
+func (dao UserDAO) FindOneByEmail(email string) (*models.User, error) {
+	return dao.findOne("email = $1", email)
+}
+
+func (dao UserDAO) findOne(where string, arg interface{}) (*models.User, error) {
+	user := &models.User{}
+	if err := dao.db.QueryRow(`
+		select id, created_at, updated_at, email, name, password_hash
+		from users
+		where `+where, arg).
+		Scan(
+			&user.ID,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.Email,
+			&user.Name,
+			&user.PasswordHash,
+		); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("users storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return user, nil
+}
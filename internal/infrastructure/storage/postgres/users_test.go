@@ -0,0 +1,51 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestUserDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_user", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		db := new(QuerierMock)
+		userDAO := NewUserDAO(db, logger)
+		res, err := userDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+	t.Run("error_on_existing_ID", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Warnf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		userDAO := NewUserDAO(db, logger)
+		user := &models.User{Model: models.Model{ID: 1}}
+		res, err := userDAO.Save(user)
+
+		assert.Nil(t, res)
+		assert.Equal(t, services.ErrRecordAlreadyExist, err)
+	})
+	t.Run("stmt_prepare_error", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Prepare", mock.Anything).Return(&sql.Stmt{}, errors.New("dummy"))
+		userDAO := NewUserDAO(db, logger)
+		res, err := userDAO.Save(&models.User{})
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
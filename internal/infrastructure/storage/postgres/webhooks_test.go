@@ -0,0 +1,80 @@
+// +build unit
+
+package postgres
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWebhookDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_webhook", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		webhookDAO := NewWebhookDAO(new(QuerierMock), logger)
+		res, err := webhookDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookDAO_Update(t *testing.T) {
+	t.Run("error_on_nil_webhook", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		webhookDAO := NewWebhookDAO(new(QuerierMock), logger)
+		res, err := webhookDAO.Update(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookDAO_Delete(t *testing.T) {
+	t.Run("exec_error", func(t *testing.T) {
+		const ID uint = 0
+		var result driver.RowsAffected = 0
+		logger := new(LoggerMock)
+		logger.On("Errorf", mock.Anything, mock.Anything).Return()
+
+		db := new(QuerierMock)
+		db.On("Exec", mock.Anything, []interface{}{ID}).Return(result, errors.New("dummy"))
+		webhookDAO := NewWebhookDAO(db, logger)
+		err := webhookDAO.Delete(ID)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDeliveryDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_delivery", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		deliveryDAO := NewDeliveryDAO(new(QuerierMock), logger)
+		res, err := deliveryDAO.Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
+
+func TestDeliveryDAO_Update(t *testing.T) {
+	t.Run("error_on_nil_delivery", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		deliveryDAO := NewDeliveryDAO(new(QuerierMock), logger)
+		res, err := deliveryDAO.Update(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,78 @@
+// +build unit
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActivityDAO(t *testing.T) {
+	db := openTestDB(t)
+	boardDAO := NewBoardDAO(db, new(LoggerMock))
+	board, err := boardDAO.Save(&models.Board{Name: "dummy"})
+	assert.NoError(t, err)
+	column, err := NewColumnDAO(db, new(LoggerMock)).Save(&models.Column{Name: "dummy", BoardID: board.ID})
+	assert.NoError(t, err)
+	taskDAO := NewTaskDAO(db, new(LoggerMock))
+	task, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: column.ID})
+	assert.NoError(t, err)
+	logger := new(LoggerMock)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+	activityDAO := NewActivityDAO(db, logger)
+
+	_, err = activityDAO.Save(&models.Activity{BoardID: board.ID + 10, Entity: models.EntityBoard, Action: models.ActionCreate})
+	assert.Equal(t, services.ErrBoardRelation, err)
+
+	created, err := activityDAO.Save(&models.Activity{
+		BoardID:  board.ID,
+		Entity:   models.EntityBoard,
+		EntityID: board.ID,
+		Action:   models.ActionCreate,
+		Changes:  models.Changes{"name": {To: "dummy"}},
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, created.ID)
+
+	assert.NoError(t, taskDAO.Delete(task.ID))
+	deleted, err := activityDAO.Save(&models.Activity{
+		TaskID:   task.ID,
+		Entity:   models.EntityTask,
+		EntityID: task.ID,
+		Action:   models.ActionDelete,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, board.ID, deleted.BoardID)
+
+	entries, err := activityDAO.Find(services.ActivityDemand{"board": board.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, deleted.ID, entries[0].ID)
+	assert.Equal(t, models.Changes{"name": {To: "dummy"}}, entries[1].Changes)
+
+	entries, err = activityDAO.Find(services.ActivityDemand{"task": task.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, models.ActionDelete, entries[0].Action)
+	assert.Equal(t, models.Changes{}, entries[0].Changes)
+
+	entries, err = activityDAO.Find(services.ActivityDemand{"entity": models.EntityBoard}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = activityDAO.Find(services.ActivityDemand{"board": board.ID}, services.Page{After: &services.Cursor{ID: deleted.ID}})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, created.ID, entries[0].ID)
+
+	assert.NoError(t, boardDAO.Delete(board.ID))
+	assert.NoError(t, NewTrashDAO(db, new(LoggerMock)).Purge(time.Now().Add(time.Second)))
+	entries, err = activityDAO.Find(services.ActivityDemand{"board": board.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,281 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// BoardDAO is a data access object for boards
+type BoardDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewBoardDAO represents a BoardDAO constructor
+func NewBoardDAO(db querier, log log.Logger) BoardDAO {
+	return BoardDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided board into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao BoardDAO) Save(board *models.Board) (*models.Board, error) {
+	if board == nil {
+		dao.log.Error("boards storage: nil pointer given")
+		return nil, errors.New("nil board pointer given")
+	}
+	if board.ID > 0 {
+		dao.log.Warnf("boards storage: %v, ID: %d", sv.ErrRecordAlreadyExist, board.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	stmt, err := dao.db.Prepare(`
+		insert into boards (created_at, updated_at, name, description, created_by, template, template_id)
+		values (?, ?, ?, ?, nullif(?, 0), ?, nullif(?, 0));`,
+	)
+	if err != nil {
+		dao.log.Errorf("boards storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+
+	defer deferred(dao.log, stmt.Close)
+	now := time.Now().UTC()
+	res, err := stmt.Exec(now, now, board.Name, board.Description, board.CreatedBy, board.Template, board.TemplateID)
+	if err != nil {
+		dao.log.Errorf("boards storage: error while inserting a row: %v", err)
+		return nil, err
+	}
+
+	ID, err := res.LastInsertId()
+	if err != nil {
+		dao.log.Errorf("boards storage: error while getting inserted row ID: %v", err)
+		return nil, err
+	}
+
+	return dao.reload(uint(ID), board)
+}
+
+// FindOneById will return a pointer to a board with the provided ID or
+// nil and an error
+func (dao BoardDAO) FindOneById(ID uint) (*models.Board, error) {
+	board := &models.Board{}
+	if err := dao.db.QueryRow(`
+		select id, created_at, updated_at, version, name, description, coalesce(created_by, 0), template, coalesce(template_id, 0)
+		from boards
+		where id = ? and deleted_at is null
+		`, ID).
+		Scan(
+			&board.ID,
+			&board.CreatedAt,
+			&board.UpdatedAt,
+			&board.Version,
+			&board.Name,
+			&board.Description,
+			&board.CreatedBy,
+			&board.Template,
+			&board.TemplateID,
+		); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("boards storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return board, nil
+}
+
+// Find will return all found boards that meet the provided demand and fit
+// the provided page or an error
+func (dao BoardDAO) Find(demand sv.BoardDemand, page sv.Page) ([]*models.Board, error) {
+	boards := make([]*models.Board, 0)
+
+	where, args := "deleted_at is null", make([]interface{}, 0)
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(" and id in (select board_id from board_members where user_id = %d)", userID)
+	}
+	if template, ok := demand["template"]; ok {
+		where, args = where+" and template = ?", append(args, template)
+	}
+	if page.After != nil {
+		where, args = where+" and id > ?", append(args, page.After.ID)
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(`select id, created_at, updated_at, version, name, description, coalesce(created_by, 0), template, coalesce(template_id, 0) from boards where %s order by id%s`, where, limit(page)),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("boards storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	for rows.Next() {
+		board := &models.Board{}
+		if err := rows.Scan(
+			&board.ID,
+			&board.CreatedAt,
+			&board.UpdatedAt,
+			&board.Version,
+			&board.Name,
+			&board.Description,
+			&board.CreatedBy,
+			&board.Template,
+			&board.TemplateID,
+		); err != nil {
+			dao.log.Errorf("boards storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		boards = append(boards, board)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("boards storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return boards, nil
+}
+
+// Update will update the name, the description and the template flag of the
+// persistent representation of the board and bump its version. The board is
+// updated only if it has the version of the provided one, unless it is zero
+func (dao BoardDAO) Update(board *models.Board) (*models.Board, error) {
+	if board == nil {
+		dao.log.Error("boards storage: nil pointer given")
+		return nil, errors.New("nil board pointer given")
+	}
+	stmt, err := dao.db.Prepare(`
+		update boards
+		set updated_at = ?, name = ?, description = ?, template = ?, version = version + 1
+		where id = ? and version = coalesce(nullif(?, 0), version) and deleted_at is null
+	`)
+	if err != nil {
+		dao.log.Errorf("boards storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	res, err := stmt.Exec(time.Now().UTC(), board.Name, board.Description, board.Template, board.ID, board.Version)
+	if err != nil {
+		dao.log.Errorf("boards storage: error while updating a row: %v", err)
+		return nil, err
+	}
+
+	if err = expectOneRow(res); err != nil {
+		return nil, err
+	}
+
+	return dao.reload(board.ID, board)
+}
+
+// Delete will mark the board as deleted along with its columns, their tasks and comments.
+// The statements should be run within a transaction.
+func (dao BoardDAO) Delete(ID uint) error {
+	now := time.Now().UTC()
+	for _, query := range []string{
+		`update boards set deleted_at = ? where id = ? and deleted_at is null`,
+		`update columns set deleted_at = ? where board = ? and deleted_at is null`,
+		`update tasks set deleted_at = ?
+		where deleted_at is null and "column" in (select id from columns where board = ?)`,
+		`update comments set deleted_at = ?
+		where deleted_at is null and task in (
+			select t.id from tasks t join columns c on c.id = t."column" where c.board = ?
+		)`,
+	} {
+		if _, err := dao.db.Exec(query, now, ID); err != nil {
+			dao.log.Errorf("boards storage: error while deleting a row: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Copy will copy the columns of the source board to the target board, along with
+// the labels, the tasks and their comments if the tasks are requested. The copied
+// tasks are matched with the originals by the names of their columns and their
+// positions, the labels are matched by their names. The deleted records are not copied.
+func (dao BoardDAO) Copy(sourceID, targetID uint, withTasks bool, userID uint) error {
+	exec := func(query string, args ...interface{}) error {
+		if _, err := dao.db.Exec(query, args...); err != nil {
+			dao.log.Errorf("boards storage: error while copying a board: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if err := exec(`
+		insert into columns (created_at, updated_at, name, board, position, wip_limit)
+		select ?, ?, name, ?, position, wip_limit
+		from columns
+		where board = ? and deleted_at is null;`, now, now, targetID, sourceID); err != nil || !withTasks {
+		return err
+	}
+	if err := exec(`
+		insert into labels (created_at, updated_at, name, color, board)
+		select ?, ?, name, color, ?
+		from labels
+		where board = ?;`, now, now, targetID, sourceID); err != nil {
+		return err
+	}
+	if err := exec(`
+		insert into tasks (created_at, updated_at, name, description, "column", position, created_by, reporter, start_at, due_at, priority)
+		select ?, ?, t.name, t.description, nc.id, t.position, ?, ?, t.start_at, t.due_at, t.priority
+		from tasks t
+		join columns oc on oc.id = t."column"
+		join columns nc on nc.board = ? and nc.name = oc.name
+		where oc.board = ? and t.deleted_at is null;`, now, now, userID, userID, targetID, sourceID); err != nil {
+		return err
+	}
+	if err := exec(`
+		insert into task_labels (task_id, label_id)
+		select nt.id, nl.id
+		from task_labels tl
+		join labels ol on ol.id = tl.label_id
+		join labels nl on nl.board = ? and nl.name = ol.name
+		join tasks ot on ot.id = tl.task_id
+		join columns oc on oc.id = ot."column"
+		join columns nc on nc.board = ? and nc.name = oc.name
+		join tasks nt on nt."column" = nc.id and nt.position = ot.position
+		where oc.board = ? and ot.deleted_at is null;`, targetID, targetID, sourceID); err != nil {
+		return err
+	}
+
+	return exec(`
+		insert into comments (created_at, updated_at, text, task, created_by)
+		select c.created_at, ?, c.text, nt.id, c.created_by
+		from comments c
+		join tasks ot on ot.id = c.task
+		join columns oc on oc.id = ot."column"
+		join columns nc on nc.board = ? and nc.name = oc.name
+		join tasks nt on nt."column" = nc.id and nt.position = ot.position
+		where oc.board = ? and c.deleted_at is null;`, now, targetID, sourceID)
+}
+
+// WithTx will return the BoardDAO that will use the provided transaction
+func (dao BoardDAO) WithTx(tx *sql.Tx) sv.BoardStorage {
+	dao.db = tx
+	return dao
+}
+
+// reload fetches the stored state of the board with the provided ID into the given entity
+func (dao BoardDAO) reload(ID uint, board *models.Board) (*models.Board, error) {
+	stored, err := dao.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	*board = *stored
+
+	return board, nil
+}
@@ -0,0 +1,143 @@
+// +build unit
+
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBoardDAO_Save(t *testing.T) {
+	t.Run("error_on_nil_board", func(t *testing.T) {
+		logger := new(LoggerMock)
+		logger.On("Error", mock.Anything).Return()
+
+		res, err := NewBoardDAO(new(QuerierMock), logger).Save(nil)
+
+		assert.Nil(t, res)
+		assert.Error(t, err)
+	})
+	t.Run("success", func(t *testing.T) {
+		boardDAO := NewBoardDAO(openTestDB(t), new(LoggerMock))
+		board := &models.Board{Name: "dummy", Description: "dummy"}
+
+		res, err := boardDAO.Save(board)
+		assert.NoError(t, err)
+		assert.Equal(t, board, res)
+		assert.Equal(t, uint(1), res.ID)
+		assert.False(t, res.CreatedAt.IsZero())
+	})
+}
+
+func TestBoardDAO_Update(t *testing.T) {
+	boardDAO := NewBoardDAO(openTestDB(t), new(LoggerMock))
+
+	_, err := boardDAO.Update(&models.Board{Model: models.Model{ID: 1}})
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	board, _ := boardDAO.Save(&models.Board{Name: "dummy"})
+	updated, err := boardDAO.Update(&models.Board{Model: models.Model{ID: board.ID}, Name: "updated"})
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", updated.Name)
+	assert.True(t, updated.UpdatedAt.After(board.CreatedAt))
+	assert.Equal(t, board.Version+1, updated.Version)
+
+	_, err = boardDAO.Update(&models.Board{Model: models.Model{ID: board.ID, Version: board.Version}, Name: "stale"})
+	assert.Equal(t, services.ErrRecordNotFound, err)
+}
+
+func TestBoardDAO_Delete(t *testing.T) {
+	db := openTestDB(t)
+	board, _ := NewBoardDAO(db, new(LoggerMock)).Save(&models.Board{Name: "dummy"})
+	column, _ := NewColumnDAO(db, new(LoggerMock)).Save(&models.Column{Name: "dummy", BoardID: board.ID, Position: 1})
+	task, err := NewTaskDAO(db, new(LoggerMock)).Save(&models.Task{Name: "dummy", ColumnID: column.ID, Position: 1})
+	assert.NoError(t, err)
+
+	assert.NoError(t, NewBoardDAO(db, new(LoggerMock)).Delete(board.ID))
+
+	_, err = NewTaskDAO(db, new(LoggerMock)).FindOneById(task.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+}
+
+func TestBoardDAO_Copy(t *testing.T) {
+	db := openTestDB(t)
+	user, err := NewUserDAO(db, new(LoggerMock)).Save(&models.User{Email: "john@example.com", Name: "John"})
+	assert.NoError(t, err)
+	boardDAO := NewBoardDAO(db, new(LoggerMock))
+	columnDAO := NewColumnDAO(db, new(LoggerMock))
+	labelDAO := NewLabelDAO(db, new(LoggerMock))
+	taskDAO := NewTaskDAO(db, new(LoggerMock))
+	commentDAO := NewCommentsDAO(db, new(LoggerMock))
+
+	source, err := boardDAO.Save(&models.Board{Name: "source", Template: true})
+	assert.NoError(t, err)
+	todo, err := columnDAO.Save(&models.Column{Name: "todo", BoardID: source.ID, Position: 1000, WIPLimit: 5})
+	assert.NoError(t, err)
+	_, err = columnDAO.Save(&models.Column{Name: "done", BoardID: source.ID, Position: 2000})
+	assert.NoError(t, err)
+	label, err := labelDAO.Save(&models.Label{Name: "bug", Color: "#ff0000", BoardID: source.ID})
+	assert.NoError(t, err)
+	task, err := taskDAO.Save(&models.Task{Name: "task", ColumnID: todo.ID, Position: 1000})
+	assert.NoError(t, err)
+	assert.NoError(t, taskDAO.SetLabels(task.ID, []uint{label.ID}))
+	_, err = commentDAO.Save(&models.Comment{Text: "comment", TaskID: task.ID})
+	assert.NoError(t, err)
+
+	templates, err := boardDAO.Find(services.BoardDemand{"template": true}, services.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, templates, 1) {
+		assert.Equal(t, source.ID, templates[0].ID)
+	}
+
+	t.Run("columns_only", func(t *testing.T) {
+		target, err := boardDAO.Save(&models.Board{Name: "target", TemplateID: source.ID})
+		assert.NoError(t, err)
+		assert.NoError(t, boardDAO.Copy(source.ID, target.ID, false, user.ID))
+
+		columns, err := columnDAO.Find(services.ColumnDemand{"board": target.ID}, services.Page{})
+		assert.NoError(t, err)
+		if assert.Len(t, columns, 2) {
+			assert.Equal(t, "todo", columns[0].Name)
+			assert.Equal(t, uint(5), columns[0].WIPLimit)
+			assert.Equal(t, "done", columns[1].Name)
+		}
+		tasks, err := taskDAO.Find(services.TaskDemand{"board": target.ID}, services.Page{})
+		assert.NoError(t, err)
+		assert.Empty(t, tasks)
+
+		stored, err := boardDAO.FindOneById(target.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, source.ID, stored.TemplateID)
+		assert.False(t, stored.Template)
+	})
+	t.Run("with_tasks", func(t *testing.T) {
+		target, err := boardDAO.Save(&models.Board{Name: "target"})
+		assert.NoError(t, err)
+		assert.NoError(t, boardDAO.Copy(source.ID, target.ID, true, user.ID))
+
+		labels, err := labelDAO.Find(services.LabelDemand{"board": target.ID}, services.Page{})
+		assert.NoError(t, err)
+		if !assert.Len(t, labels, 1) {
+			return
+		}
+		tasks, err := taskDAO.Find(services.TaskDemand{"board": target.ID}, services.Page{})
+		assert.NoError(t, err)
+		if !assert.Len(t, tasks, 1) {
+			return
+		}
+		assert.NotEqual(t, task.ID, tasks[0].ID)
+		assert.Equal(t, "task", tasks[0].Name)
+		assert.Equal(t, user.ID, tasks[0].ReporterID)
+		assert.Equal(t, []uint{labels[0].ID}, tasks[0].Labels)
+
+		comments, err := commentDAO.Find(services.CommentDemand{"task": tasks[0].ID}, services.Page{})
+		assert.NoError(t, err)
+		if assert.Len(t, comments, 1) {
+			assert.Equal(t, "comment", comments[0].Text)
+		}
+	})
+}
@@ -0,0 +1,345 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// ColumnDAO is a data access object for columns
+type ColumnDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewColumnDAO represents a ColumnDAO constructor
+func NewColumnDAO(db querier, log log.Logger) ColumnDAO {
+	return ColumnDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided column into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao ColumnDAO) Save(column *models.Column) (*models.Column, error) {
+	if column == nil {
+		dao.log.Error("columns storage: nil pointer given")
+		return nil, errors.New("nil column pointer given")
+	}
+	if column.ID > 0 {
+		dao.log.Warnf("columns storage: %v, ID: %d", sv.ErrRecordAlreadyExist, column.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	stmt, err := dao.db.Prepare(`
+		insert into columns (created_at, updated_at, name, board, position, wip_limit)
+		values (?, ?, ?, ?, ?, nullif(?, 0));`,
+	)
+	if err != nil {
+		dao.log.Errorf("columns storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	now := time.Now().UTC()
+	res, err := stmt.Exec(now, now, column.Name, column.BoardID, column.Position, column.WIPLimit)
+	if err != nil {
+		return nil, dao.translateError(err)
+	}
+
+	ID, err := res.LastInsertId()
+	if err != nil {
+		dao.log.Errorf("columns storage: error while getting inserted row ID: %v", err)
+		return nil, err
+	}
+
+	return dao.reload(uint(ID), column)
+}
+
+// FindOneById will return a pointer to a column with the provided ID or
+// nil and an error
+func (dao ColumnDAO) FindOneById(ID uint) (*models.Column, error) {
+	column := &models.Column{}
+	err := dao.db.QueryRow(`
+		select id, created_at, updated_at, version, name, board, position, coalesce(wip_limit, 0)
+		from columns
+		where id = ? and deleted_at is null
+		`, ID).
+		Scan(
+			&column.ID,
+			&column.CreatedAt,
+			&column.UpdatedAt,
+			&column.Version,
+			&column.Name,
+			&column.BoardID,
+			&column.Position,
+			&column.WIPLimit,
+		)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("columns storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return column, nil
+}
+
+// Find will return all found columns that fit the provided page or an error
+func (dao ColumnDAO) Find(demand sv.ColumnDemand, page sv.Page) ([]*models.Column, error) {
+	const querySelect = "id, created_at, updated_at, version, name, board, position, coalesce(wip_limit, 0)"
+	columns := make([]*models.Column, 0)
+	where, args := "deleted_at is null", make([]interface{}, 0)
+	if boardID, ok := demand["board"]; ok {
+		where = where + fmt.Sprintf(" and board = %d", boardID)
+	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(" and board in (select board_id from board_members where user_id = %d)", userID)
+	}
+	if page.After != nil {
+		where, args = where+" and (position, id) > (?, ?)", append(args, page.After.Position, page.After.ID)
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(`select %s from columns where %s order by position, id%s;`, querySelect, where, limit(page)),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("columns storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	for rows.Next() {
+		column := &models.Column{}
+		if err := rows.Scan(
+			&column.ID,
+			&column.CreatedAt,
+			&column.UpdatedAt,
+			&column.Version,
+			&column.Name,
+			&column.BoardID,
+			&column.Position,
+			&column.WIPLimit,
+		); err != nil {
+			dao.log.Errorf("columns storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("columns storage: rows query error: %v", err)
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// Update will update the name, the position and the WIP limit of the persistent
+// representation of the column and bump its version. The column is updated only if
+// it has the version of the provided one, unless it is zero. Returns pointer to the
+// updated column or nil and an error
+func (dao ColumnDAO) Update(column *models.Column) (*models.Column, error) {
+	if column == nil {
+		dao.log.Error("columns storage: nil pointer given")
+		return nil, errors.New("nil column pointer given")
+	}
+	stmt, err := dao.db.Prepare(`
+		update columns
+		set updated_at = ?, name = ?, position = ?, wip_limit = nullif(?, 0), version = version + 1
+		where id = ? and version = coalesce(nullif(?, 0), version) and deleted_at is null
+	`)
+	if err != nil {
+		dao.log.Errorf("columns storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	res, err := stmt.Exec(time.Now().UTC(), column.Name, column.Position, column.WIPLimit, column.ID, column.Version)
+	if err != nil {
+		return nil, dao.translateError(err)
+	}
+
+	if err = expectOneRow(res); err != nil {
+		return nil, err
+	}
+
+	return dao.reload(column.ID, column)
+}
+
+// Delete will mark the column with the provided ID as deleted along with its tasks
+// and their comments. The statements should be run within a transaction.
+func (dao ColumnDAO) Delete(ID uint) error {
+	now := time.Now().UTC()
+	res, err := dao.db.Exec(`update columns set deleted_at = ? where id = ? and deleted_at is null`, now, ID)
+	if err != nil {
+		dao.log.Errorf("columns storage: error while deleting a column ID: %d: %v", ID, err)
+		return err
+	}
+	for _, query := range []string{
+		`update tasks set deleted_at = ? where "column" = ? and deleted_at is null`,
+		`update comments set deleted_at = ?
+		where deleted_at is null and task in (select id from tasks where "column" = ?)`,
+	} {
+		if _, err = dao.db.Exec(query, now, ID); err != nil {
+			dao.log.Errorf("columns storage: error while deleting a column ID: %d: %v", ID, err)
+			return err
+		}
+	}
+
+	rowsNum, err := res.RowsAffected()
+	if err != nil {
+		dao.log.Error(err)
+		return err
+	}
+
+	if rowsNum != 1 {
+		err = errors.Errorf("tried to delete %d rows, want 1", rowsNum)
+		dao.log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// Rebalance will place the columns with the provided IDs in the given order, with
+// the positions evenly spread by the step. The columns are moved below the lowest
+// position of the board first, so that the new positions do not collide with the
+// old ones on the unique (position, board) constraint.
+func (dao ColumnDAO) Rebalance(boardID uint, IDs []uint, step float64) error {
+	var lowest float64
+	if err := dao.db.QueryRow(
+		`select min(coalesce(min(position), 0), 0) from columns where board = ?`,
+		boardID,
+	).Scan(&lowest); err != nil {
+		dao.log.Errorf("columns storage: error while rebalancing board %d: %v", boardID, err)
+		return err
+	}
+	for i, ID := range IDs {
+		if _, err := dao.db.Exec(
+			`update columns set position = ? where id = ? and board = ?`,
+			lowest-float64(i+1),
+			ID,
+			boardID,
+		); err != nil {
+			return dao.translateError(err)
+		}
+	}
+	now := time.Now()
+	for i, ID := range IDs {
+		if _, err := dao.db.Exec(
+			`update columns set position = ?, updated_at = ?, version = version + 1 where id = ?`,
+			step*float64(i+1),
+			now,
+			ID,
+		); err != nil {
+			return dao.translateError(err)
+		}
+	}
+
+	return nil
+}
+
+// WithTx will return the ColumnDAO that will use the provided transaction
+func (dao ColumnDAO) WithTx(tx *sql.Tx) sv.ColumnStorage {
+	dao.db = tx
+	return dao
+}
+
+// CountColumnsByBoard will count columns that are related to the provided board ID
+func (dao ColumnDAO) CountColumnsByBoard(ID uint) (int, error) {
+	var num int
+	if err := dao.db.QueryRow(`select count(id) from columns where board = ? and deleted_at is null`, ID).
+		Scan(&num); err != nil {
+		dao.log.Errorf("columns storage: error while counting columns by board: %v", err)
+		return 0, err
+	}
+
+	return num, nil
+}
+
+// FindColumnToTheLeft will find an ID of the column to the left of the one with the provided ID
+func (dao ColumnDAO) FindColumnToTheLeft(ID uint) (uint, error) {
+	var prev sql.NullInt64
+	if err := dao.db.QueryRow(`
+		select prev
+		from (
+			select id, lag(id) over (order by position) as prev
+			from columns
+			where board = (select board from columns where id = ?) and deleted_at is null
+		) sub
+		where id = ?`, ID, ID).Scan(&prev); err != nil {
+		dao.log.Errorf("columns storage: error while querying prev record: %v", err)
+		return 0, err
+	}
+
+	if !prev.Valid || prev.Int64 <= 0 {
+		err := errors.New("columns storage: invalid left column record")
+		dao.log.Errorf("%v: %v", err, prev)
+		return 0, err
+	}
+
+	return uint(prev.Int64), nil
+}
+
+// FindColumnToTheRight will find an ID of the column to the right of the one with the provided ID
+func (dao ColumnDAO) FindColumnToTheRight(ID uint) (uint, error) {
+	var next sql.NullInt64
+	if err := dao.db.QueryRow(`
+		select next
+		from (
+			select id, lead(id) over (order by position) as next
+			from columns
+			where board = (select board from columns where id = ?) and deleted_at is null
+		) sub
+		where id = ?`, ID, ID).Scan(&next); err != nil {
+		dao.log.Errorf("columns storage: error while querying next record: %v", err)
+		return 0, err
+	}
+
+	if !next.Valid || next.Int64 <= 0 {
+		err := errors.New("columns storage: invalid right column record")
+		dao.log.Errorf("%v: %v", err, next)
+		return 0, err
+	}
+
+	return uint(next.Int64), nil
+}
+
+func (dao ColumnDAO) translateError(err error) error {
+	constraint, ok := violatedConstraint(err, "columns_board_fkey")
+	if !ok {
+		dao.log.Errorf("columns storage: error while writing a row: %v", err)
+		return err
+	}
+
+	switch constraint {
+	case "columns_name_board_key":
+		return sv.ErrNameDuplicate
+	case "columns_position_board_key":
+		return sv.ErrPositionDuplicate
+	case "columns_board_fkey":
+		return sv.ErrBoardRelation
+	default:
+		dao.log.Errorf("columns storage: integrity constraint violation: %v", err)
+		return err
+	}
+}
+
+// reload fetches the stored state of the column with the provided ID into the given entity
+func (dao ColumnDAO) reload(ID uint, column *models.Column) (*models.Column, error) {
+	stored, err := dao.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	*column = *stored
+
+	return column, nil
+}
@@ -0,0 +1,109 @@
+// +build unit
+
+package sqlite
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func seedColumns(t *testing.T, db *sql.DB) (uint, []*models.Column) {
+	board, err := NewBoardDAO(db, new(LoggerMock)).Save(&models.Board{Name: "dummy"})
+	assert.NoError(t, err)
+
+	columnDAO := NewColumnDAO(db, new(LoggerMock))
+	columns := make([]*models.Column, 0)
+	for _, c := range []models.Column{
+		{Name: "second", BoardID: board.ID, Position: 2000},
+		{Name: "first", BoardID: board.ID, Position: 1000},
+		{Name: "third", BoardID: board.ID, Position: 3000},
+	} {
+		c := c
+		column, err := columnDAO.Save(&c)
+		assert.NoError(t, err)
+		columns = append(columns, column)
+	}
+
+	return board.ID, columns
+}
+
+func TestColumnDAO_Save(t *testing.T) {
+	db := openTestDB(t)
+	boardID, _ := seedColumns(t, db)
+	columnDAO := NewColumnDAO(db, new(LoggerMock))
+
+	_, err := columnDAO.Save(&models.Column{Name: "first", BoardID: boardID, Position: 5000})
+	assert.Equal(t, services.ErrNameDuplicate, err)
+
+	_, err = columnDAO.Save(&models.Column{Name: "fourth", BoardID: boardID, Position: 3000})
+	assert.Equal(t, services.ErrPositionDuplicate, err)
+
+	_, err = columnDAO.Save(&models.Column{Name: "fourth", BoardID: boardID + 1, Position: 3000})
+	assert.Equal(t, services.ErrBoardRelation, err)
+}
+
+func TestColumnDAO_Update(t *testing.T) {
+	db := openTestDB(t)
+	_, columns := seedColumns(t, db)
+	columnDAO := NewColumnDAO(db, new(LoggerMock))
+
+	_, err := columnDAO.Update(&models.Column{Model: models.Model{ID: 100}, Name: "dummy"})
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	_, err = columnDAO.Update(&models.Column{Model: models.Model{ID: columns[0].ID}, Name: "first", Position: 2000})
+	assert.Equal(t, services.ErrNameDuplicate, err)
+
+	updated, err := columnDAO.Update(&models.Column{Model: models.Model{ID: columns[0].ID}, Name: "new", Position: 500})
+	assert.NoError(t, err)
+	assert.Equal(t, columns[0].BoardID, updated.BoardID)
+}
+
+func TestColumnDAO_Neighbours(t *testing.T) {
+	db := openTestDB(t)
+	boardID, columns := seedColumns(t, db)
+	logger := new(LoggerMock)
+	logger.On("Errorf", mock.Anything, mock.Anything).Return()
+	columnDAO := NewColumnDAO(db, logger)
+
+	found, err := columnDAO.Find(services.ColumnDemand{"board": boardID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "third"}, []string{found[0].Name, found[1].Name, found[2].Name})
+
+	num, err := columnDAO.CountColumnsByBoard(boardID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, num)
+
+	left, err := columnDAO.FindColumnToTheLeft(columns[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, columns[1].ID, left)
+
+	right, err := columnDAO.FindColumnToTheRight(columns[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, columns[2].ID, right)
+
+	_, err = columnDAO.FindColumnToTheLeft(columns[1].ID)
+	assert.Error(t, err)
+}
+
+func TestColumnDAO_Rebalance(t *testing.T) {
+	db := openTestDB(t)
+	boardID, columns := seedColumns(t, db)
+	columnDAO := NewColumnDAO(db, new(LoggerMock))
+
+	order := []uint{columns[2].ID, columns[0].ID, columns[1].ID}
+	assert.NoError(t, columnDAO.Rebalance(boardID, order, 1000))
+
+	stored, err := columnDAO.Find(services.ColumnDemand{"board": boardID}, services.Page{})
+	assert.NoError(t, err)
+	if assert.Len(t, stored, 3) {
+		for i, column := range stored {
+			assert.Equal(t, order[i], column.ID)
+			assert.Equal(t, float64(1000*(i+1)), column.Position)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// CommentsDAO is a data access object for comments
+type CommentsDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewCommentsDAO represents a CommentsDAO constructor
+func NewCommentsDAO(db querier, log log.Logger) *CommentsDAO {
+	return &CommentsDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided comment into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao CommentsDAO) Save(comment *models.Comment) (*models.Comment, error) {
+	if comment == nil {
+		dao.log.Error("comments storage: nil pointer given")
+		return nil, errors.New("nil comment pointer given")
+	}
+	if comment.ID > 0 {
+		dao.log.Warnf("comments storage: %v, ID: %d", services.ErrRecordAlreadyExist, comment.ID)
+		return nil, services.ErrRecordAlreadyExist
+	}
+
+	stmt, err := dao.db.Prepare(`
+		insert into comments (created_at, updated_at, text, task, created_by)
+		values (?, ?, ?, ?, nullif(?, 0));`,
+	)
+	if err != nil {
+		dao.log.Errorf("comments storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	now := time.Now().UTC()
+	res, err := stmt.Exec(now, now, comment.Text, comment.TaskID, comment.CreatedBy)
+	if err != nil {
+		if constraint, ok := violatedConstraint(err, "comments_task_fkey"); ok {
+			switch constraint {
+			case "comments_task_fkey":
+				err = services.ErrTaskRelation
+			default:
+				dao.log.Errorf("comments storage: integrity constraint violation: %v", err)
+			}
+		} else {
+			dao.log.Errorf("comments storage: error while inserting a row: %v", err)
+		}
+
+		return nil, err
+	}
+
+	ID, err := res.LastInsertId()
+	if err != nil {
+		dao.log.Errorf("comments storage: error while getting inserted row ID: %v", err)
+		return nil, err
+	}
+
+	return dao.reload(uint(ID), comment)
+}
+
+// FindOneById will return a pointer to a comment with the provided ID or
+// nil and an error
+func (dao CommentsDAO) FindOneById(ID uint) (*models.Comment, error) {
+	comment := &models.Comment{}
+	err := dao.db.QueryRow(`
+		select id, created_at, updated_at, version, text, task, coalesce(created_by, 0)
+		from comments
+		where id = ? and deleted_at is null
+		`, ID).
+		Scan(&comment.ID, &comment.CreatedAt, &comment.UpdatedAt, &comment.Version, &comment.Text, &comment.TaskID, &comment.CreatedBy)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("comments storage: error while querying a row: %v", err)
+			return nil, err
+		}
+		return nil, services.ErrRecordNotFound
+	}
+
+	return comment, nil
+}
+
+// Find will return all found comments that meet the provided demand and fit
+// the provided page or an error
+func (dao CommentsDAO) Find(demand services.CommentDemand, page services.Page) ([]*models.Comment, error) {
+	const querySelect = "id, created_at, updated_at, version, text, task, coalesce(created_by, 0)"
+	where, args := "t.deleted_at is null", make([]interface{}, 0)
+	if taskID, ok := demand["task"]; ok {
+		where = where + fmt.Sprintf(" and t.task = %d", taskID)
+	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(
+			` and t.task in (select mt.id from tasks mt join columns mc on mt."column" = mc.id join board_members m on m.board_id = mc.board where m.user_id = %d)`,
+			userID,
+		)
+	}
+	if page.After != nil {
+		where = where + " and (t.created_at, t.id) < (?, ?)"
+		args = append(args, page.After.CreatedAt.UTC(), page.After.ID)
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(
+			`select %s from comments t where %s order by created_at desc, id desc%s;`,
+			querySelect,
+			where,
+			limit(page),
+		),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("comments storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	comments := make([]*models.Comment, 0)
+	for rows.Next() {
+		comment := &models.Comment{}
+		if err := rows.Scan(
+			&comment.ID,
+			&comment.CreatedAt,
+			&comment.UpdatedAt,
+			&comment.Version,
+			&comment.Text,
+			&comment.TaskID,
+			&comment.CreatedBy,
+		); err != nil {
+			dao.log.Errorf("comments storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("comments storage: error while querying rows: %v", err)
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// Update will update text of the persistent representation of the comment and bump
+// its version. The comment is updated only if it has the version of the provided one,
+// unless it is zero
+func (dao CommentsDAO) Update(comment *models.Comment) (*models.Comment, error) {
+	if comment == nil {
+		dao.log.Error("comments storage: nil pointer given")
+		return nil, errors.New("nil comment pointer given")
+	}
+	stmt, err := dao.db.Prepare(`
+		update comments
+		set updated_at = ?, text = ?, version = version + 1
+		where id = ? and version = coalesce(nullif(?, 0), version) and deleted_at is null
+	`)
+	if err != nil {
+		dao.log.Errorf("comments storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	res, err := stmt.Exec(time.Now().UTC(), comment.Text, comment.ID, comment.Version)
+	if err != nil {
+		dao.log.Errorf("comments storage: error while updating a row: %v", err)
+		return nil, err
+	}
+
+	if err = expectOneRow(res); err != nil {
+		return nil, err
+	}
+
+	return dao.reload(comment.ID, comment)
+}
+
+// Delete will mark the comment as deleted
+func (dao CommentsDAO) Delete(ID uint) error {
+	_, err := dao.db.Exec(
+		"update comments set deleted_at = ? where id = ? and deleted_at is null",
+		time.Now().UTC(),
+		ID,
+	)
+	if err != nil {
+		dao.log.Errorf("comments storage: error while deleting a row: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// reload fetches the stored state of the comment with the provided ID into the given entity
+func (dao CommentsDAO) reload(ID uint, comment *models.Comment) (*models.Comment, error) {
+	stored, err := dao.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	*comment = *stored
+
+	return comment, nil
+}
+
+// WithTx will return the CommentsDAO that will use the provided transaction
+func (dao CommentsDAO) WithTx(tx *sql.Tx) services.CommentStorage {
+	dao.db = tx
+	return dao
+}
@@ -0,0 +1,45 @@
+// +build unit
+
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentsDAO(t *testing.T) {
+	db := openTestDB(t)
+	_, columns := seedColumns(t, db)
+	task, err := NewTaskDAO(db, new(LoggerMock)).Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1})
+	assert.NoError(t, err)
+	commentsDAO := NewCommentsDAO(db, new(LoggerMock))
+
+	_, err = commentsDAO.Save(&models.Comment{Text: "dummy", TaskID: task.ID + 1})
+	assert.Equal(t, services.ErrTaskRelation, err)
+
+	first, err := commentsDAO.Save(&models.Comment{Text: "first", TaskID: task.ID})
+	assert.NoError(t, err)
+	second, err := commentsDAO.Save(&models.Comment{Text: "second", TaskID: task.ID})
+	assert.NoError(t, err)
+
+	comments, err := commentsDAO.Find(services.CommentDemand{"task": task.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{second.ID, first.ID}, []uint{comments[0].ID, comments[1].ID})
+
+	comments, err = commentsDAO.Find(
+		services.CommentDemand{"task": task.ID},
+		services.Page{Limit: 1, After: &services.Cursor{ID: second.ID, CreatedAt: second.CreatedAt}},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{first.ID}, []uint{comments[0].ID})
+
+	_, err = commentsDAO.Update(&models.Comment{Model: models.Model{ID: second.ID + 1}, Text: "updated"})
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	assert.NoError(t, commentsDAO.Delete(first.ID))
+	_, err = commentsDAO.FindOneById(first.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+}
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+)
+
+const uniqueViolationPrefix = "UNIQUE constraint failed: "
+
+func deferred(log log.Logger, f func() error) {
+	if err := f(); err != nil && err != sql.ErrTxDone {
+		log.Errorf("%v", err)
+	}
+}
+
+// expectOneRow returns ErrRecordNotFound if the statement has not affected any rows
+func expectOneRow(res sql.Result) error {
+	rowsNum, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsNum == 0 {
+		return sv.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// violatedConstraint checks if the error is an integrity constraint violation and
+// returns the name of the violated constraint in the way Postgres names it, e.g.
+// "tasks_position_column_key". SQLite does not report which foreign key has failed,
+// so the provided fkey name is returned for foreign key violations.
+func violatedConstraint(err error, fkey string) (string, bool) {
+	sqliteErr, ok := err.(sqlite3.Error)
+	if !ok || sqliteErr.Code != sqlite3.ErrConstraint {
+		return "", false
+	}
+
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintForeignKey:
+		return fkey, true
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		var table string
+		columns := make([]string, 0)
+		for _, field := range strings.Split(strings.TrimPrefix(sqliteErr.Error(), uniqueViolationPrefix), ", ") {
+			parts := strings.SplitN(field, ".", 2)
+			if len(parts) != 2 {
+				return "", true
+			}
+			table = parts[0]
+			columns = append(columns, parts[1])
+		}
+
+		return table + "_" + strings.Join(columns, "_") + "_key", true
+	default:
+		return "", true
+	}
+}
+
+// limit returns the limit clause for the provided page
+func limit(page sv.Page) string {
+	if page.Limit == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(" limit %d", page.Limit)
+}
+
+// uintSlice scans a comma separated list of integers into a slice of unsigned integers
+type uintSlice struct {
+	dest *[]uint
+}
+
+// uintList returns a scanner of a comma separated list into the provided slice
+func uintList(dest *[]uint) uintSlice {
+	return uintSlice{dest: dest}
+}
+
+// Scan implements the sql.Scanner interface, NULL is scanned as an empty slice
+func (s uintSlice) Scan(src interface{}) error {
+	*s.dest = make([]uint, 0)
+
+	var list string
+	switch value := src.(type) {
+	case nil:
+		return nil
+	case string:
+		list = value
+	case []byte:
+		list = string(value)
+	default:
+		return errors.Errorf("unsupported list type %T", src)
+	}
+
+	for _, item := range strings.Split(list, ",") {
+		value, err := strconv.ParseUint(item, 10, 64)
+		if err != nil {
+			return err
+		}
+		*s.dest = append(*s.dest, uint(value))
+	}
+
+	return nil
+}
+
+// stringSlice scans a comma separated list of strings into a slice of strings
+type stringSlice struct {
+	dest *[]string
+}
+
+// stringList returns a scanner of a comma separated list into the provided slice
+func stringList(dest *[]string) stringSlice {
+	return stringSlice{dest: dest}
+}
+
+// Scan implements the sql.Scanner interface, NULL is scanned as an empty slice
+func (s stringSlice) Scan(src interface{}) error {
+	*s.dest = make([]string, 0)
+
+	switch value := src.(type) {
+	case nil:
+	case string:
+		*s.dest = strings.Split(value, ",")
+	case []byte:
+		*s.dest = strings.Split(string(value), ",")
+	default:
+		return errors.Errorf("unsupported list type %T", src)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// +build unit
+
+package sqlite
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+)
+
+const migrationsPath = "../../../db/sqlite_migrations"
+
+// openTestDB opens a new database in a temporary directory and applies
+// all the up migrations to it
+func openTestDB(t *testing.T) *sql.DB {
+	dir, err := ioutil.TempDir("", "detask")
+	if err != nil {
+		t.Fatalf("testing: failed to create a temp dir: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(dir, "test.db")+"?_foreign_keys=1")
+	if err != nil {
+		t.Fatalf("testing: failed to open a database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+		_ = os.RemoveAll(dir)
+	})
+
+	files, err := filepath.Glob(filepath.Join(migrationsPath, "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("testing: failed to find migrations: %v", err)
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		query, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("testing: failed to read migration %s: %v", file, err)
+		}
+		if _, err = db.Exec(string(query)); err != nil {
+			t.Fatalf("testing: failed to apply migration %s: %v", file, err)
+		}
+	}
+
+	return db
+}
+
+func TestDeferred(t *testing.T) {
+	logger := new(LoggerMock)
+	logger.On("Errorf", "%v", mock.Anything).Return().Once()
+
+	tests := []struct {
+		name string
+		f    func() error
+	}{
+		{"sql_error", func() error { return sql.ErrTxDone }},
+		{"any_other_error", func() error { return errors.New("test") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deferred(logger, tt.f)
+		})
+	}
+}
+
+func TestViolatedConstraint(t *testing.T) {
+	_, ok := violatedConstraint(errors.New("test"), "dummy_fkey")
+	if ok {
+		t.Errorf("violatedConstraint() reported a violation for a non sqlite error")
+	}
+}
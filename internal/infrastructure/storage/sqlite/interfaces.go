@@ -0,0 +1,17 @@
+package sqlite
+
+import (
+	"database/sql"
+)
+
+type querier interface {
+	Prepare(query string) (*sql.Stmt, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// scanner is a row of a query result, either *sql.Row or *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
@@ -0,0 +1,182 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// OutboxDAO is a data access object for the outbox of the change events
+type OutboxDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewOutboxDAO represents an OutboxDAO constructor
+func NewOutboxDAO(db querier, log log.Logger) OutboxDAO {
+	return OutboxDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided event into the database and return a pointer to
+// the saved event. Returns nil and an error in case of error.
+func (dao OutboxDAO) Save(event *models.Event) (*models.Event, error) {
+	if event == nil {
+		dao.log.Error("outbox storage: nil pointer given")
+		return nil, errors.New("nil event pointer given")
+	}
+	if event.ID > 0 {
+		dao.log.Warnf("outbox storage: %v, ID: %d", sv.ErrRecordAlreadyExist, event.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		dao.log.Errorf("outbox storage: error while encoding an event: %v", err)
+		return nil, err
+	}
+	res, err := dao.db.Exec(
+		`insert into outbox (created_at, event, board, payload) values (?, ?, ?, ?);`,
+		time.Now().UTC(),
+		event.Type,
+		event.BoardID,
+		string(payload),
+	)
+	if err != nil {
+		dao.log.Errorf("outbox storage: error while inserting a row: %v", err)
+		return nil, err
+	}
+
+	ID, err := res.LastInsertId()
+	if err != nil {
+		dao.log.Errorf("outbox storage: error while getting inserted row ID: %v", err)
+		return nil, err
+	}
+	event.ID = uint(ID)
+
+	return event, nil
+}
+
+// FindPending will return up to the limit of the events that are neither delivered
+// nor dead yet and are due by the provided time sorted by ID
+func (dao OutboxDAO) FindPending(now time.Time, limit uint) ([]*models.Event, error) {
+	rows, err := dao.db.Query(
+		`select o.id, o.payload, o.attempts,
+			(select group_concat(c.consumer) from outbox_consumers c where c.event = o.id)
+		from outbox o
+		where o.delivered_at is null and o.dead_at is null and (o.next_attempt is null or o.next_attempt <= ?)
+		order by o.id
+		limit ?`,
+		now.UTC(),
+		limit,
+	)
+	if err != nil {
+		dao.log.Errorf("outbox storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	events := make([]*models.Event, 0)
+	for rows.Next() {
+		var (
+			ID        uint
+			payload   string
+			attempts  uint
+			consumers []string
+		)
+		if err := rows.Scan(&ID, &payload, &attempts, stringList(&consumers)); err != nil {
+			dao.log.Errorf("outbox storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		event := &models.Event{}
+		if err := json.Unmarshal([]byte(payload), event); err != nil {
+			dao.log.Errorf("outbox storage: error while decoding the event %d: %v", ID, err)
+			return nil, err
+		}
+		event.ID, event.Attempts, event.Consumers = ID, attempts, consumers
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("outbox storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// MarkConsumed will record that the consumer with the provided name has accepted
+// the event with the provided ID
+func (dao OutboxDAO) MarkConsumed(ID uint, consumer string) error {
+	if _, err := dao.db.Exec(
+		`insert or ignore into outbox_consumers (event, consumer) values (?, ?)`,
+		ID,
+		consumer,
+	); err != nil {
+		if constraint, ok := violatedConstraint(err, "outbox_consumers_event_fkey"); ok && constraint == "outbox_consumers_event_fkey" {
+			return sv.ErrRecordNotFound
+		}
+		dao.log.Errorf("outbox storage: error while inserting a row: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed will count a failed relay of the event with the provided ID and postpone
+// its next attempt, the nil time marks the event dead
+func (dao OutboxDAO) MarkFailed(ID uint, next *time.Time) error {
+	var nextAttempt, deadAt interface{}
+	if next == nil {
+		deadAt = time.Now().UTC()
+	} else {
+		nextAttempt = next.UTC()
+	}
+	res, err := dao.db.Exec(
+		`update outbox set attempts = attempts + 1, next_attempt = ?, dead_at = ? where id = ?`,
+		nextAttempt,
+		deadAt,
+		ID,
+	)
+	if err != nil {
+		dao.log.Errorf("outbox storage: error while updating a row: %v", err)
+		return err
+	}
+
+	return expectOneRow(res)
+}
+
+// MarkDelivered will mark the event with the provided ID as delivered
+func (dao OutboxDAO) MarkDelivered(ID uint) error {
+	res, err := dao.db.Exec(`update outbox set delivered_at = ? where id = ?`, time.Now().UTC(), ID)
+	if err != nil {
+		dao.log.Errorf("outbox storage: error while updating a row: %v", err)
+		return err
+	}
+
+	return expectOneRow(res)
+}
+
+// Purge will delete the events delivered before the provided time, the dead events
+// are kept
+func (dao OutboxDAO) Purge(before time.Time) error {
+	if _, err := dao.db.Exec(`delete from outbox where delivered_at < ?`, before.UTC()); err != nil {
+		dao.log.Errorf("outbox storage: error while purging the delivered events: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// WithTx will return the OutboxDAO that will use the provided transaction
+func (dao OutboxDAO) WithTx(tx *sql.Tx) sv.OutboxStorage {
+	dao.db = tx
+	return dao
+}
@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// Reset removes all the records and restarts the ID sequences, the migrations
+// history is kept. The full-text indexes are cleared by the triggers of the
+// indexed tables, so the virtual tables and their shadow tables are skipped.
+// It is used for end to end tests.
+func Reset(db *sql.DB) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	tables, err := resettable(tx)
+	if err != nil {
+		return err
+	}
+	// the records are deleted regardless of the references between the tables,
+	// the foreign keys are checked on commit when all of them are gone
+	if _, err = tx.Exec(`pragma defer_foreign_keys = on;`); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err = tx.Exec(`delete from "` + table + `";`); err != nil {
+			return err
+		}
+	}
+	if _, err = tx.Exec(`delete from sqlite_sequence;`); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// resettable returns the names of the tables that keep the records
+func resettable(tx *sql.Tx) ([]string, error) {
+	rows, err := tx.Query(`
+		select name, sql like 'create virtual table%' from sqlite_master
+		where type = 'table' and name not like 'sqlite_%' and name <> 'schema_migrations';`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables, virtual []string
+	for rows.Next() {
+		var (
+			name      string
+			isVirtual bool
+		)
+		if err = rows.Scan(&name, &isVirtual); err != nil {
+			return nil, err
+		}
+		if isVirtual {
+			virtual = append(virtual, name)
+		} else {
+			tables = append(tables, name)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if !isShadow(table, virtual) {
+			result = append(result, table)
+		}
+	}
+
+	return result, nil
+}
+
+// isShadow reports if the table keeps the data of one of the virtual tables
+func isShadow(table string, virtual []string) bool {
+	for _, name := range virtual {
+		if strings.HasPrefix(table, name+"_") {
+			return true
+		}
+	}
+
+	return false
+}
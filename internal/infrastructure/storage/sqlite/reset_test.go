@@ -0,0 +1,41 @@
+// +build unit
+
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReset(t *testing.T) {
+	db := openTestDB(t)
+	_, err := NewUserDAO(db, new(LoggerMock)).Save(&models.User{Email: "john@example.com", Name: "John"})
+	assert.NoError(t, err)
+	_, columns := seedColumns(t, db)
+	task, err := NewTaskDAO(db, new(LoggerMock)).Save(&models.Task{
+		Name: "Release", Description: "Release notes", ColumnID: columns[0].ID, Position: 1000,
+	})
+	assert.NoError(t, err)
+	_, err = NewCommentsDAO(db, new(LoggerMock)).Save(&models.Comment{Text: "Release after the deploy", TaskID: task.ID})
+	assert.NoError(t, err)
+
+	assert.NoError(t, Reset(db))
+
+	boards, err := NewBoardDAO(db, new(LoggerMock)).Find(services.BoardDemand{}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, boards, 0)
+	hits, err := NewSearchDAO(db, new(LoggerMock)).Find(services.SearchDemand{"q": "release"}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, hits, 0)
+
+	user, err := NewUserDAO(db, new(LoggerMock)).Save(&models.User{Email: "john@example.com", Name: "John"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), user.ID)
+
+	var indexes int
+	assert.NoError(t, db.QueryRow(`select count(*) from sqlite_master where name = 'tasks_search'`).Scan(&indexes))
+	assert.Equal(t, 1, indexes)
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+)
+
+// SearchDAO is a data access object for the full-text search of tasks and comments
+type SearchDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewSearchDAO represents a SearchDAO constructor
+func NewSearchDAO(db querier, log log.Logger) SearchDAO {
+	return SearchDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// searchSelect lists the tasks and the comments matching the full-text query along with
+// the snippets of their texts with the matched words highlighted, the rank of a record
+// is the number of the matched words in its text
+const searchSelect = `
+	select 'task' as type, t.id, t.id as task, t."column", c.board, coalesce(t.name, '') as name,
+		snippet(tasks_search, '<b>', '</b>', '...', -1, 32) as snippet,
+		(length(offsets(tasks_search)) - length(replace(offsets(tasks_search), ' ', '')) + 1) / 4.0 as rank
+	from tasks_search
+	join tasks t on t.id = tasks_search.docid
+	join columns c on c.id = t."column"
+	where tasks_search match ? and t.deleted_at is null
+	union all
+	select 'comment', cm.id, cm.task, t."column", c.board, coalesce(t.name, ''),
+		snippet(comments_search, '<b>', '</b>', '...', -1, 32),
+		(length(offsets(comments_search)) - length(replace(offsets(comments_search), ' ', '')) + 1) / 4.0
+	from comments_search
+	join comments cm on cm.id = comments_search.docid
+	join tasks t on t.id = cm.task
+	join columns c on c.id = t."column"
+	where comments_search match ? and cm.deleted_at is null`
+
+// Find will return the tasks and the comments matching the query of the provided demand
+// that meet its other constraints and fit the provided page, from the highest rank to
+// the lowest, or an error
+func (dao SearchDAO) Find(demand sv.SearchDemand, page sv.Page) ([]*models.SearchHit, error) {
+	query, _ := demand["q"].(string)
+	match := matchQuery(query)
+	where, args := "true", []interface{}{match, match}
+	if boardID, ok := demand["board"]; ok {
+		where = where + " and board = ?"
+		args = append(args, boardID)
+	}
+	if userID, ok := demand["member"]; ok {
+		where = where + " and board in (select board_id from board_members where user_id = ?)"
+		args = append(args, userID)
+	}
+	if page.After != nil {
+		where = where + " and (rank < ? or rank = ? and (type, id) > (?, ?))"
+		args = append(args, page.After.Rank, page.After.Rank, page.After.Type, page.After.ID)
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(
+			`select type, id, task, "column", board, name, snippet, rank from (%s) hits where %s order by rank desc, type, id%s;`,
+			searchSelect,
+			where,
+			limit(page),
+		),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("search storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	hits := make([]*models.SearchHit, 0)
+	for rows.Next() {
+		hit := &models.SearchHit{}
+		if err := rows.Scan(
+			&hit.Type,
+			&hit.ID,
+			&hit.TaskID,
+			&hit.ColumnID,
+			&hit.BoardID,
+			&hit.Name,
+			&hit.Snippet,
+			&hit.Rank,
+		); err != nil {
+			dao.log.Errorf("search storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("search storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return hits, nil
+}
+
+// matchQuery returns the full-text query matching the records that contain all the
+// words of the provided text. The words are quoted, so the text can not break the
+// query syntax, and the query of a text without words matches nothing.
+func matchQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for i, word := range words {
+		words[i] = `"` + word + `"`
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,580 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+// assigneesSelect selects the sorted IDs of the task assignees as a comma separated list
+const assigneesSelect = `(select group_concat(user_id) from (
+		select a.user_id from task_assignees a where a.task_id = t.id order by a.user_id
+	))`
+
+// labelsSelect selects the sorted IDs of the task labels as a comma separated list
+const labelsSelect = `(select group_concat(label_id) from (
+		select l.label_id from task_labels l where l.task_id = t.id order by l.label_id
+	))`
+
+// TaskDAO is a data access object for tasks
+type TaskDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewTaskDAO represents a TaskDAO constructor
+func NewTaskDAO(db querier, log log.Logger) TaskDAO {
+	return TaskDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided task into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao TaskDAO) Save(task *models.Task) (*models.Task, error) {
+	if task == nil {
+		dao.log.Error("tasks storage: nil pointer given")
+		return nil, errors.New("nil tasks pointer given")
+	}
+	if task.ID > 0 {
+		dao.log.Warnf("tasks storage: %v, ID: %d", sv.ErrRecordAlreadyExist, task.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	stmt, err := dao.db.Prepare(`
+		insert into tasks (
+			created_at, updated_at, name, description, "column", position, created_by, reporter, start_at, due_at, priority
+		)
+		values (?, ?, ?, ?, ?, ?, nullif(?, 0), nullif(?, 0), ?, ?, coalesce(nullif(?, 0), 3));`,
+	)
+	if err != nil {
+		dao.log.Errorf("tasks storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	now := time.Now().UTC()
+	res, err := stmt.Exec(
+		now,
+		now,
+		task.Name,
+		task.Description,
+		task.ColumnID,
+		task.Position,
+		task.CreatedBy,
+		task.ReporterID,
+		task.StartAt,
+		task.DueAt,
+		task.Priority,
+	)
+	if err != nil {
+		return nil, dao.translateError(err)
+	}
+
+	ID, err := res.LastInsertId()
+	if err != nil {
+		dao.log.Errorf("tasks storage: error while getting inserted row ID: %v", err)
+		return nil, err
+	}
+
+	return dao.reload(uint(ID), task)
+}
+
+// FindOneById will return a pointer to a task with the provided ID or
+// nil and an error
+func (dao TaskDAO) FindOneById(ID uint) (*models.Task, error) {
+	task := &models.Task{}
+	err := dao.db.QueryRow(`
+		select t.id, t.created_at, t.updated_at, t.version, t.name, t.description, t."column", t.position,
+			coalesce(t.created_by, 0), coalesce(t.reporter, 0), t.start_at, t.due_at, t.priority,
+			`+assigneesSelect+`, `+labelsSelect+`
+		from tasks t
+		where t.id = ? and t.deleted_at is null
+		`, ID).
+		Scan(
+			&task.ID,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+			&task.Version,
+			&task.Name,
+			&task.Description,
+			&task.ColumnID,
+			&task.Position,
+			&task.CreatedBy,
+			&task.ReporterID,
+			&task.StartAt,
+			&task.DueAt,
+			&task.Priority,
+			uintList(&task.Assignees),
+			uintList(&task.Labels),
+		)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("tasks storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return task, nil
+}
+
+// Find will return all found tasks that meet the provided demand and fit
+// the provided page or an error
+func (dao TaskDAO) Find(demand sv.TaskDemand, page sv.Page) ([]*models.Task, error) {
+	tasks := make([]*models.Task, 0)
+
+	const querySelect = `t.id, t.created_at, t.updated_at, t.version, t.name, t.description, t."column", t.position,
+		coalesce(t.created_by, 0), coalesce(t.reporter, 0), t.start_at, t.due_at, t.priority,
+		` + assigneesSelect + `, ` + labelsSelect
+	var join, where string
+	args := make([]interface{}, 0)
+
+	where = "t.deleted_at is null"
+	if boardID, ok := demand["board"]; ok {
+		join = `join columns c on t."column" = c.id`
+		where = where + fmt.Sprintf(" and c.board = %d", boardID)
+	}
+	if columnID, ok := demand["column"]; ok {
+		where = where + fmt.Sprintf(` and t."column" = %d`, columnID)
+	}
+	if userID, ok := demand["assignee"]; ok {
+		where = where + fmt.Sprintf(" and t.id in (select task_id from task_assignees where user_id = %d)", userID)
+	}
+	if labelID, ok := demand["label"]; ok {
+		where = where + fmt.Sprintf(" and t.id in (select task_id from task_labels where label_id = %d)", labelID)
+	}
+	if due, ok := demand["due_before"]; ok {
+		where, args = where+" and t.due_at < ?", append(args, due)
+	}
+	if due, ok := demand["due_after"]; ok {
+		where, args = where+" and t.due_at > ?", append(args, due)
+	}
+	if overdue, ok := demand["overdue"].(bool); ok {
+		if overdue {
+			where = where + " and t.due_at < ?"
+		} else {
+			where = where + " and (t.due_at is null or t.due_at >= ?)"
+		}
+		args = append(args, time.Now().UTC())
+	}
+	if priority, ok := demand["priority"]; ok {
+		where, args = where+" and t.priority = ?", append(args, priority)
+	}
+	if query, ok := demand["q"].(string); ok {
+		where = where + " and t.id in (select docid from tasks_search where tasks_search match ?)"
+		args = append(args, matchQuery(query))
+	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(
+			` and t."column" in (select mc.id from columns mc join board_members m on m.board_id = mc.board where m.user_id = %d)`,
+			userID,
+		)
+	}
+	if page.After != nil {
+		var keyset string
+		keyset, args = taskKeyset(demand.Sort(), page.After, args)
+		where = where + " and " + keyset
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(
+			`select %s from tasks t %s where %s order by %s%s;`,
+			querySelect, join, where, taskOrder(demand.Sort()), limit(page),
+		),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("tasks storage: error while querying rows: %v", err)
+		return nil, err
+	}
+
+	defer deferred(dao.log, rows.Close)
+
+	for rows.Next() {
+		task := &models.Task{}
+		if err := rows.Scan(
+			&task.ID,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+			&task.Version,
+			&task.Name,
+			&task.Description,
+			&task.ColumnID,
+			&task.Position,
+			&task.CreatedBy,
+			&task.ReporterID,
+			&task.StartAt,
+			&task.DueAt,
+			&task.Priority,
+			uintList(&task.Assignees),
+			uintList(&task.Labels),
+		); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("tasks storage: rows query error: %v", err)
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Update will update the persistent representation of the task and bump its version,
+// the reporter and the priority are updated only if new ones are provided. The task is
+// updated only if it has the version of the provided one, unless it is zero
+func (dao TaskDAO) Update(task *models.Task) (*models.Task, error) {
+	if task == nil {
+		dao.log.Error("tasks storage: nil pointer given")
+		return nil, errors.New("nil tasks pointer given")
+	}
+	stmt, err := dao.db.Prepare(`
+		update tasks
+		set updated_at = ?, name = ?, description = ?, position = ?, "column" = ?,
+			reporter = coalesce(nullif(?, 0), reporter), start_at = ?, due_at = ?,
+			priority = coalesce(nullif(?, 0), priority), version = version + 1
+		where id = ? and version = coalesce(nullif(?, 0), version) and deleted_at is null
+	`)
+	if err != nil {
+		dao.log.Errorf("tasks storage: failed to prepare statement: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, stmt.Close)
+	res, err := stmt.Exec(
+		time.Now().UTC(),
+		task.Name,
+		task.Description,
+		task.Position,
+		task.ColumnID,
+		task.ReporterID,
+		task.StartAt,
+		task.DueAt,
+		task.Priority,
+		task.ID,
+		task.Version,
+	)
+	if err != nil {
+		return nil, dao.translateError(err)
+	}
+
+	if err = expectOneRow(res); err != nil {
+		return nil, err
+	}
+
+	return dao.reload(task.ID, task)
+}
+
+// SetAssignees will replace the assignees of the task with the provided ID
+func (dao TaskDAO) SetAssignees(taskID uint, userIDs []uint) error {
+	if _, err := dao.db.Exec(`delete from task_assignees where task_id = ?`, taskID); err != nil {
+		dao.log.Errorf("tasks storage: error while deleting assignees: %v", err)
+		return err
+	}
+	for _, userID := range userIDs {
+		if _, err := dao.db.Exec(
+			`insert into task_assignees (task_id, user_id) values (?, ?)`,
+			taskID,
+			userID,
+		); err != nil {
+			if _, ok := violatedConstraint(err, "task_assignees_user_id_fkey"); ok {
+				return sv.ErrUserRelation
+			}
+			dao.log.Errorf("tasks storage: error while inserting assignees: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetLabels will replace the labels of the task with the provided ID,
+// the labels must belong to the board of the task
+func (dao TaskDAO) SetLabels(taskID uint, labelIDs []uint) error {
+	if _, err := dao.db.Exec(`delete from task_labels where task_id = ?`, taskID); err != nil {
+		dao.log.Errorf("tasks storage: error while deleting labels: %v", err)
+		return err
+	}
+	for _, labelID := range labelIDs {
+		res, err := dao.db.Exec(`
+			insert into task_labels (task_id, label_id)
+			select t.id, l.id
+			from tasks t
+			join columns c on c.id = t."column"
+			join labels l on l.board = c.board
+			where t.id = ? and l.id = ?`,
+			taskID,
+			labelID,
+		)
+		if err != nil {
+			dao.log.Errorf("tasks storage: error while inserting labels: %v", err)
+			return err
+		}
+		if err = expectOneRow(res); err != nil {
+			if err == sv.ErrRecordNotFound {
+				return sv.ErrLabelRelation
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MatchLabels will replace the labels of the task with the labels of the same names
+// on the board of its column, the labels without a match are dropped
+func (dao TaskDAO) MatchLabels(taskID uint) error {
+	if _, err := dao.db.Exec(`
+		delete from task_labels
+		where task_id = ? and label_id not in (
+			select source.id
+			from tasks t
+			join columns c on c.id = t."column"
+			join labels target on target.board = c.board
+			join labels source on source.name = target.name
+			where t.id = ?
+		)`,
+		taskID,
+		taskID,
+	); err != nil {
+		dao.log.Errorf("tasks storage: error while dropping unmatched labels: %v", err)
+		return err
+	}
+	if _, err := dao.db.Exec(`
+		update task_labels
+		set label_id = (
+			select target.id
+			from tasks t
+			join columns c on c.id = t."column"
+			join labels target on target.board = c.board
+			join labels source on source.name = target.name
+			where t.id = task_labels.task_id and source.id = task_labels.label_id
+		)
+		where task_id = ?`,
+		taskID,
+	); err != nil {
+		dao.log.Errorf("tasks storage: error while matching labels: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// MoveToColumn will move all tasks from source column to target column, the deleted
+// tasks are left in the source column
+func (dao TaskDAO) MoveToColumn(sourceID, targetID uint) error {
+	if _, err := dao.db.Exec(
+		`update tasks set "column" = ?, version = version + 1 where "column" = ? and deleted_at is null`,
+		targetID,
+		sourceID,
+	); err != nil {
+		dao.log.Errorf(
+			"tasks storage: error while moving tasks from column %d to column %d: %v",
+			sourceID,
+			targetID,
+			err,
+		)
+		return err
+	}
+
+	return nil
+}
+
+// ColumnLoad will return the WIP limit of the column with the provided ID and the
+// number of tasks in it. The transactions are started with the immediate lock (see
+// the _txlock connection parameter), so that concurrent transactions can not add
+// tasks to the column meanwhile.
+func (dao TaskDAO) ColumnLoad(columnID uint) (limit, count uint, err error) {
+	if err = dao.db.QueryRow(`
+		select coalesce(c.wip_limit, 0), (select count(*) from tasks t where t."column" = c.id and t.deleted_at is null)
+		from columns c
+		where c.id = ? and c.deleted_at is null`,
+		columnID,
+	).Scan(&limit, &count); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, sv.ErrColumnRelation
+		}
+		dao.log.Errorf("tasks storage: error while counting tasks of column %d: %v", columnID, err)
+		return 0, 0, err
+	}
+
+	return limit, count, nil
+}
+
+// ColumnBoard will return the ID of the board of the column with the provided ID
+func (dao TaskDAO) ColumnBoard(columnID uint) (boardID uint, err error) {
+	if err = dao.db.QueryRow(
+		`select board from columns where id = ? and deleted_at is null`,
+		columnID,
+	).Scan(&boardID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, sv.ErrColumnRelation
+		}
+		dao.log.Errorf("tasks storage: error while finding board of column %d: %v", columnID, err)
+		return 0, err
+	}
+
+	return boardID, nil
+}
+
+// Rebalance will place the tasks with the provided IDs into the column in the given
+// order, with the positions evenly spread by the step. The tasks are moved below the
+// lowest position of the column first, so that the new positions do not collide with
+// the old ones on the unique (position, column) constraint.
+func (dao TaskDAO) Rebalance(columnID uint, IDs []uint, step float64) error {
+	var lowest float64
+	if err := dao.db.QueryRow(
+		`select min(coalesce(min(position), 0), 0) from tasks where "column" = ?`,
+		columnID,
+	).Scan(&lowest); err != nil {
+		dao.log.Errorf("tasks storage: error while rebalancing column %d: %v", columnID, err)
+		return err
+	}
+	for i, ID := range IDs {
+		if _, err := dao.db.Exec(
+			`update tasks set "column" = ?, position = ? where id = ?`,
+			columnID,
+			lowest-float64(i+1),
+			ID,
+		); err != nil {
+			return dao.translateError(err)
+		}
+	}
+	now := time.Now()
+	for i, ID := range IDs {
+		if _, err := dao.db.Exec(
+			`update tasks set position = ?, updated_at = ?, version = version + 1 where id = ?`,
+			step*float64(i+1),
+			now,
+			ID,
+		); err != nil {
+			return dao.translateError(err)
+		}
+	}
+
+	return nil
+}
+
+// Delete will mark the task as deleted along with its comments. The statements
+// should be run within a transaction.
+func (dao TaskDAO) Delete(ID uint) error {
+	now := time.Now().UTC()
+	for _, query := range []string{
+		`update tasks set deleted_at = ? where id = ? and deleted_at is null`,
+		`update comments set deleted_at = ? where task = ? and deleted_at is null`,
+	} {
+		if _, err := dao.db.Exec(query, now, ID); err != nil {
+			dao.log.Errorf("tasks storage: error while deleting a row: %v", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithTx will return the TaskDAO that will use the provided transaction
+func (dao TaskDAO) WithTx(tx *sql.Tx) sv.TaskStorage {
+	dao.db = tx
+	return dao
+}
+
+func (dao TaskDAO) translateError(err error) error {
+	constraint, ok := violatedConstraint(err, "tasks_column_fkey")
+	if !ok {
+		dao.log.Errorf("tasks storage: error while writing a row: %v", err)
+		return err
+	}
+
+	switch constraint {
+	case "tasks_column_fkey":
+		return sv.ErrColumnRelation
+	case "tasks_position_column_key":
+		return sv.ErrPositionDuplicate
+	default:
+		dao.log.Errorf("tasks storage: integrity constraint violation: %v", err)
+		return err
+	}
+}
+
+// reload fetches the stored state of the task with the provided ID into the given entity
+func (dao TaskDAO) reload(ID uint, task *models.Task) (*models.Task, error) {
+	stored, err := dao.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	*task = *stored
+
+	return task, nil
+}
+
+// taskSortColumns maps the fields the tasks may be sorted by to the columns
+var taskSortColumns = map[string]string{
+	"position": "t.position",
+	"priority": "t.priority",
+	"due":      "t.due_at",
+}
+
+// taskOrder builds the order by clause of the provided sort, the tasks without
+// a due date go last in both directions
+func taskOrder(sort sv.Sort) string {
+	order := make([]string, 0, len(sort)+1)
+	for _, key := range sort {
+		column := taskSortColumns[key.Field]
+		if key.Field == "due" {
+			order = append(order, column+" is null")
+		}
+		if key.Desc {
+			column = column + " desc"
+		}
+		order = append(order, column)
+	}
+
+	return strings.Join(append(order, "t.id"), ", ")
+}
+
+// taskKeyset builds the condition that selects the tasks following the one
+// the cursor points to in the provided sort order, and appends its values to the args
+func taskKeyset(sort sv.Sort, after *sv.Cursor, args []interface{}) (string, []interface{}) {
+	values := map[string]interface{}{
+		"position": after.Position,
+		"priority": after.Priority,
+		"due":      after.DueAt,
+	}
+
+	// every placeholder takes its own argument, so the values of the sort keys
+	// are collected to be repeated in each of the alternatives
+	follows, equal, equalArgs := make([]string, 0, len(sort)+1), make([]string, 0, len(sort)), make([]interface{}, 0)
+	for _, key := range sort {
+		column := taskSortColumns[key.Field]
+		if key.Field == "due" && after.DueAt == nil {
+			// only the tasks without a due date follow the one without it
+			equal = append(equal, column+" is null")
+			continue
+		}
+
+		operator := ">"
+		if key.Desc {
+			operator = "<"
+		}
+		next := column + " " + operator + " ?"
+		if key.Field == "due" {
+			next = "(" + next + " or " + column + " is null)"
+		}
+		follows = append(follows, strings.Join(append(equal, next), " and "))
+		args = append(append(args, equalArgs...), values[key.Field])
+		equal, equalArgs = append(equal, column+" = ?"), append(equalArgs, values[key.Field])
+	}
+	follows = append(follows, strings.Join(append(equal, "t.id > ?"), " and "))
+	args = append(append(args, equalArgs...), after.ID)
+
+	return "(" + strings.Join(follows, " or ") + ")", args
+}
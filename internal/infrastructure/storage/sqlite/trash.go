@@ -0,0 +1,245 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+)
+
+// TrashDAO is a data access object for the deleted boards, columns, tasks and comments
+type TrashDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewTrashDAO represents a TrashDAO constructor
+func NewTrashDAO(db querier, log log.Logger) TrashDAO {
+	return TrashDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// trashSelect lists the deleted records of all types along with the flag telling
+// if the parent of the record is deleted as well
+const trashSelect = `
+	select 'board' as type, b.id, b.name, b.id as board, 0 as parent, b.deleted_at, 0 as parent_deleted
+	from boards b
+	where b.deleted_at is not null
+	union all
+	select 'column', c.id, c.name, c.board, c.board, c.deleted_at, b.deleted_at is not null
+	from columns c
+	join boards b on b.id = c.board
+	where c.deleted_at is not null
+	union all
+	select 'task', t.id, t.name, c.board, t."column", t.deleted_at, c.deleted_at is not null
+	from tasks t
+	join columns c on c.id = t."column"
+	where t.deleted_at is not null
+	union all
+	select 'comment', cm.id, cm.text, c.board, cm.task, cm.deleted_at, t.deleted_at is not null
+	from comments cm
+	join tasks t on t.id = cm.task
+	join columns c on c.id = t."column"
+	where cm.deleted_at is not null`
+
+// trashRestore clears the deletion time of the record with the provided ID and of
+// the dependant records that were deleted along with it. Each statement takes the
+// deletion time and the ID of the record.
+var trashRestore = map[models.TrashType][]string{
+	models.TrashBoard: {
+		`update boards set deleted_at = null where deleted_at = ? and id = ?`,
+		`update columns set deleted_at = null where deleted_at = ? and board = ?`,
+		`update tasks set deleted_at = null
+		where deleted_at = ? and "column" in (select id from columns where board = ?)`,
+		`update comments set deleted_at = null
+		where deleted_at = ? and task in (
+			select t.id from tasks t join columns c on c.id = t."column" where c.board = ?
+		)`,
+	},
+	models.TrashColumn: {
+		`update columns set deleted_at = null where deleted_at = ? and id = ?`,
+		`update tasks set deleted_at = null where deleted_at = ? and "column" = ?`,
+		`update comments set deleted_at = null
+		where deleted_at = ? and task in (select id from tasks where "column" = ?)`,
+	},
+	models.TrashTask: {
+		`update tasks set deleted_at = null where deleted_at = ? and id = ?`,
+		`update comments set deleted_at = null where deleted_at = ? and task = ?`,
+	},
+	models.TrashComment: {
+		`update comments set deleted_at = null where deleted_at = ? and id = ?`,
+	},
+}
+
+// trashPlace moves the record with the provided ID after the last one by the step
+// if its position has been taken since the record was deleted. The statement takes
+// the step and the ID of the record.
+var trashPlace = map[models.TrashType]string{
+	models.TrashColumn: `
+		update columns
+		set position = (select max(o.position) + ? from columns o where o.board = columns.board and o.deleted_at is null),
+			version = version + 1
+		where id = ? and exists (
+			select 1 from columns o where o.board = columns.board and o.position = columns.position and o.deleted_at is null
+		)`,
+	models.TrashTask: `
+		update tasks
+		set position = (select max(o.position) + ? from tasks o where o."column" = tasks."column" and o.deleted_at is null),
+			version = version + 1
+		where id = ? and exists (
+			select 1 from tasks o where o."column" = tasks."column" and o.position = tasks.position and o.deleted_at is null
+		)`,
+}
+
+// Find will return the deleted records that meet the provided demand and fit the provided
+// page or an error. The records deleted along with their parents are not listed.
+func (dao TrashDAO) Find(demand sv.TrashDemand, page sv.Page) ([]*models.TrashItem, error) {
+	where, args := "not parent_deleted", make([]interface{}, 0)
+	if boardID, ok := demand["board"]; ok {
+		where = where + fmt.Sprintf(" and board = %d", boardID)
+	}
+	if kind, ok := demand["type"]; ok {
+		where, args = where+" and type = ?", append(args, kind)
+	}
+	if userID, ok := demand["member"]; ok {
+		where = where + fmt.Sprintf(" and board in (select board_id from board_members where user_id = %d)", userID)
+	}
+	if page.After != nil {
+		where = where + " and (deleted_at < ? or deleted_at = ? and (type, id) > (?, ?))"
+		args = append(args, page.After.DeletedAt, page.After.DeletedAt, page.After.Type, page.After.ID)
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(
+			`select type, id, name, board, parent, deleted_at from (%s) trash where %s order by deleted_at desc, type, id%s;`,
+			trashSelect,
+			where,
+			limit(page),
+		),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("trash storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	items := make([]*models.TrashItem, 0)
+	for rows.Next() {
+		item := &models.TrashItem{}
+		if err := rows.Scan(&item.Type, &item.ID, &item.Name, &item.BoardID, &item.ParentID, &item.DeletedAt); err != nil {
+			dao.log.Errorf("trash storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("trash storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// FindOne will return the deleted record of the provided type with the provided ID
+// or ErrRecordNotFound if there is no such deleted record
+func (dao TrashDAO) FindOne(kind models.TrashType, ID uint) (*models.TrashItem, error) {
+	item, _, err := dao.findOne(kind, ID)
+
+	return item, err
+}
+
+// Restore will restore the deleted record of the provided type with the provided ID
+// along with the records deleted with it. A restored column or task is placed after
+// the last one by the provided step if its position has been taken meanwhile.
+// The statements should be run within a transaction.
+func (dao TrashDAO) Restore(kind models.TrashType, ID uint, step float64) error {
+	item, parentDeleted, err := dao.findOne(kind, ID)
+	if err != nil {
+		return err
+	}
+	if parentDeleted {
+		return sv.ErrTrashedParent
+	}
+
+	if place, ok := trashPlace[kind]; ok {
+		if _, err = dao.db.Exec(place, step, ID); err != nil {
+			dao.log.Errorf("trash storage: error while placing a restored %s: %v", kind, err)
+			return err
+		}
+	}
+	for _, query := range trashRestore[kind] {
+		if _, err = dao.db.Exec(query, item.DeletedAt, ID); err != nil {
+			return dao.translateError(kind, err)
+		}
+	}
+
+	return nil
+}
+
+// Purge will permanently delete the records deleted before the provided time along
+// with all the dependant records
+func (dao TrashDAO) Purge(before time.Time) error {
+	for _, table := range []string{"comments", "tasks", "columns", "boards"} {
+		if _, err := dao.db.Exec(fmt.Sprintf(`delete from %s where deleted_at < ?`, table), before.UTC()); err != nil {
+			dao.log.Errorf("trash storage: error while purging %s: %v", table, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WithTx will return the TrashDAO that will use the provided transaction
+func (dao TrashDAO) WithTx(tx *sql.Tx) sv.TrashStorage {
+	dao.db = tx
+	return dao
+}
+
+// findOne will return the deleted record of the provided type with the provided ID
+// and report if its parent is deleted as well
+func (dao TrashDAO) findOne(kind models.TrashType, ID uint) (*models.TrashItem, bool, error) {
+	var parentDeleted bool
+	item := &models.TrashItem{}
+	if err := dao.db.QueryRow(
+		fmt.Sprintf(
+			`select type, id, name, board, parent, deleted_at, parent_deleted from (%s) trash where type = ? and id = ?`,
+			trashSelect,
+		),
+		kind,
+		ID,
+	).Scan(&item.Type, &item.ID, &item.Name, &item.BoardID, &item.ParentID, &item.DeletedAt, &parentDeleted); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("trash storage: error while querying a row: %v", err)
+			return nil, false, err
+		}
+
+		return nil, false, sv.ErrRecordNotFound
+	}
+
+	return item, parentDeleted, nil
+}
+
+func (dao TrashDAO) translateError(kind models.TrashType, err error) error {
+	constraint, ok := violatedConstraint(err, "")
+	if !ok {
+		dao.log.Errorf("trash storage: error while restoring a %s: %v", kind, err)
+		return err
+	}
+
+	switch constraint {
+	case "columns_name_board_key":
+		return sv.ErrNameDuplicate
+	case "columns_position_board_key", "tasks_position_column_key":
+		return sv.ErrPositionDuplicate
+	default:
+		dao.log.Errorf("trash storage: integrity constraint violation: %v", err)
+		return err
+	}
+}
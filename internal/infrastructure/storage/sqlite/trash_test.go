@@ -0,0 +1,103 @@
+// +build unit
+
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashDAO(t *testing.T) {
+	db := openTestDB(t)
+	user, err := NewUserDAO(db, new(LoggerMock)).Save(&models.User{Email: "john@example.com", Name: "John"})
+	assert.NoError(t, err)
+	boardID, columns := seedColumns(t, db)
+	_, err = NewMemberDAO(db, new(LoggerMock)).Save(&models.Member{BoardID: boardID, UserID: user.ID, Role: models.RoleOwner})
+	assert.NoError(t, err)
+	columnDAO := NewColumnDAO(db, new(LoggerMock))
+	taskDAO := NewTaskDAO(db, new(LoggerMock))
+	task, err := taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1000})
+	assert.NoError(t, err)
+	commentDAO := NewCommentsDAO(db, new(LoggerMock))
+	comment, err := commentDAO.Save(&models.Comment{Text: "dummy", TaskID: task.ID})
+	assert.NoError(t, err)
+	trashDAO := NewTrashDAO(db, new(LoggerMock))
+
+	assert.NoError(t, commentDAO.Delete(comment.ID))
+	_, err = commentDAO.FindOneById(comment.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+	assert.NoError(t, taskDAO.Delete(task.ID))
+	_, err = taskDAO.FindOneById(task.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	items, err := trashDAO.Find(services.TrashDemand{"member": user.ID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, models.TrashTask, items[0].Type)
+	assert.Equal(t, boardID, items[0].BoardID)
+	assert.Equal(t, columns[0].ID, items[0].ParentID)
+	items, err = trashDAO.Find(services.TrashDemand{"member": user.ID + 1}, services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	assert.Equal(t, services.ErrTrashedParent, trashDAO.Restore(models.TrashComment, comment.ID, 1000))
+	_, err = taskDAO.Save(&models.Task{Name: "dummy", ColumnID: columns[0].ID, Position: 1000})
+	assert.NoError(t, err)
+	assert.NoError(t, trashDAO.Restore(models.TrashTask, task.ID, 1000))
+	restored, err := taskDAO.FindOneById(task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2000), restored.Position)
+	_, err = commentDAO.FindOneById(comment.ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+	assert.NoError(t, trashDAO.Restore(models.TrashComment, comment.ID, 1000))
+	_, err = commentDAO.FindOneById(comment.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, services.ErrRecordNotFound, trashDAO.Restore(models.TrashComment, comment.ID, 1000))
+
+	assert.NoError(t, columnDAO.Delete(columns[1].ID))
+	_, err = columnDAO.Save(&models.Column{Name: columns[1].Name, BoardID: boardID, Position: 4000})
+	assert.NoError(t, err)
+	assert.Equal(t, services.ErrNameDuplicate, trashDAO.Restore(models.TrashColumn, columns[1].ID, 1000))
+
+	assert.NoError(t, columnDAO.Delete(columns[2].ID))
+	items, err = trashDAO.Find(services.TrashDemand{"board": boardID}, services.Page{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, columns[2].ID, items[0].ID)
+	items, err = trashDAO.Find(
+		services.TrashDemand{"board": boardID},
+		services.Page{After: &services.Cursor{ID: items[0].ID, Type: string(items[0].Type), DeletedAt: &items[0].DeletedAt}},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, columns[1].ID, items[0].ID)
+
+	assert.NoError(t, NewBoardDAO(db, new(LoggerMock)).Delete(boardID))
+	_, err = columnDAO.FindOneById(columns[0].ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+	items, err = trashDAO.Find(services.TrashDemand{"type": models.TrashColumn}, services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+	items, err = trashDAO.Find(make(services.TrashDemand), services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, models.TrashBoard, items[0].Type)
+
+	assert.NoError(t, trashDAO.Restore(models.TrashBoard, boardID, 1000))
+	tasks, err := taskDAO.Find(services.TaskDemand{"board": boardID}, services.Page{})
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 2)
+	_, err = columnDAO.FindOneById(columns[1].ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	assert.NoError(t, trashDAO.Purge(time.Now().Add(time.Second)))
+	_, err = trashDAO.FindOne(models.TrashColumn, columns[1].ID)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+	items, err = trashDAO.Find(make(services.TrashDemand), services.Page{})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
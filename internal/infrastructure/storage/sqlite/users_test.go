@@ -0,0 +1,35 @@
+// +build unit
+
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserDAO(t *testing.T) {
+	db := openTestDB(t)
+	userDAO := NewUserDAO(db, new(LoggerMock))
+
+	user, err := userDAO.Save(&models.User{Email: "john@example.com", Name: "John", PasswordHash: "hash"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), user.ID)
+	assert.False(t, user.CreatedAt.IsZero())
+
+	_, err = userDAO.Save(&models.User{Email: "john@example.com", Name: "Johnny", PasswordHash: "hash"})
+	assert.Equal(t, services.ErrEmailDuplicate, err)
+
+	found, err := userDAO.FindOneByEmail("john@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, user, found)
+
+	_, err = userDAO.FindOneById(user.ID + 1)
+	assert.Equal(t, services.ErrRecordNotFound, err)
+
+	board, err := NewBoardDAO(db, new(LoggerMock)).Save(&models.Board{Name: "dummy", CreatedBy: user.ID})
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, board.CreatedBy)
+}
@@ -0,0 +1,426 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/app/log"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	"github.com/pkg/errors"
+)
+
+const webhookColumns = "id, created_at, updated_at, board, url, secret, events, disabled"
+
+// WebhookDAO is a data access object for webhooks
+type WebhookDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewWebhookDAO represents a WebhookDAO constructor
+func NewWebhookDAO(db querier, log log.Logger) WebhookDAO {
+	return WebhookDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided webhook into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error.
+func (dao WebhookDAO) Save(webhook *models.Webhook) (*models.Webhook, error) {
+	if webhook == nil {
+		dao.log.Error("webhooks storage: nil pointer given")
+		return nil, errors.New("nil webhook pointer given")
+	}
+	if webhook.ID > 0 {
+		dao.log.Warnf("webhooks storage: %v, ID: %d", sv.ErrRecordAlreadyExist, webhook.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	now := time.Now().UTC()
+	res, err := dao.db.Exec(
+		`insert into webhooks (created_at, updated_at, board, url, secret, events, disabled)
+		values (?, ?, ?, ?, ?, ?, ?);`,
+		now,
+		now,
+		webhook.BoardID,
+		webhook.URL,
+		webhook.Secret,
+		webhook.Events,
+		webhook.Disabled,
+	)
+	if err != nil {
+		if constraint, ok := violatedConstraint(err, "webhooks_board_fkey"); ok && constraint == "webhooks_board_fkey" {
+			return nil, sv.ErrBoardRelation
+		}
+		dao.log.Errorf("webhooks storage: error while inserting a row: %v", err)
+		return nil, err
+	}
+
+	ID, err := res.LastInsertId()
+	if err != nil {
+		dao.log.Errorf("webhooks storage: error while getting inserted row ID: %v", err)
+		return nil, err
+	}
+
+	return dao.reload(uint(ID), webhook)
+}
+
+// FindOneById will return a pointer to a webhook with the provided ID or
+// nil and an error
+func (dao WebhookDAO) FindOneById(ID uint) (*models.Webhook, error) {
+	webhook := &models.Webhook{}
+	if err := dao.scan(
+		dao.db.QueryRow(`select `+webhookColumns+` from webhooks where id = ?`, ID),
+		webhook,
+	); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("webhooks storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return webhook, nil
+}
+
+// Find will return the webhooks of the board that fit the provided page sorted
+// by ID or an error
+func (dao WebhookDAO) Find(boardID uint, page sv.Page) ([]*models.Webhook, error) {
+	where, args := "board = ?", []interface{}{boardID}
+	if page.After != nil {
+		where, args = where+" and id > ?", append(args, page.After.ID)
+	}
+
+	rows, err := dao.db.Query(
+		fmt.Sprintf(`select %s from webhooks where %s order by id%s`, webhookColumns, where, limit(page)),
+		args...,
+	)
+	if err != nil {
+		dao.log.Errorf("webhooks storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	webhooks := make([]*models.Webhook, 0)
+	for rows.Next() {
+		webhook := &models.Webhook{}
+		if err := dao.scan(rows, webhook); err != nil {
+			dao.log.Errorf("webhooks storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("webhooks storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Update will update the URL, the secret, the event types and the disabled flag
+// of the webhook
+func (dao WebhookDAO) Update(webhook *models.Webhook) (*models.Webhook, error) {
+	if webhook == nil {
+		dao.log.Error("webhooks storage: nil pointer given")
+		return nil, errors.New("nil webhook pointer given")
+	}
+
+	res, err := dao.db.Exec(
+		`update webhooks
+		set updated_at = ?, url = ?, secret = ?, events = ?, disabled = ?
+		where id = ?`,
+		time.Now().UTC(),
+		webhook.URL,
+		webhook.Secret,
+		webhook.Events,
+		webhook.Disabled,
+		webhook.ID,
+	)
+	if err != nil {
+		dao.log.Errorf("webhooks storage: error while updating a row: %v", err)
+		return nil, err
+	}
+	if err = expectOneRow(res); err != nil {
+		return nil, err
+	}
+
+	return dao.reload(webhook.ID, webhook)
+}
+
+// Delete will delete the webhook, its deliveries are deleted by the cascade
+// foreign key
+func (dao WebhookDAO) Delete(ID uint) error {
+	res, err := dao.db.Exec("delete from webhooks where id = ?", ID)
+	if err != nil {
+		dao.log.Errorf("webhooks storage: error while deleting a row: %v", err)
+		return err
+	}
+
+	return expectOneRow(res)
+}
+
+func (dao WebhookDAO) reload(ID uint, webhook *models.Webhook) (*models.Webhook, error) {
+	stored, err := dao.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	*webhook = *stored
+
+	return webhook, nil
+}
+
+func (dao WebhookDAO) scan(row scanner, webhook *models.Webhook) error {
+	return row.Scan(
+		&webhook.ID,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+		&webhook.BoardID,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.Events,
+		&webhook.Disabled,
+	)
+}
+
+const deliveryColumns = `id, created_at, updated_at, webhook, coalesce(event_id, 0), event, payload, status,
+	attempts, response_code, error, next_attempt`
+
+// DeliveryDAO is a data access object for the webhook deliveries queue
+type DeliveryDAO struct {
+	db  querier
+	log log.Logger
+}
+
+// NewDeliveryDAO represents a DeliveryDAO constructor
+func NewDeliveryDAO(db querier, log log.Logger) DeliveryDAO {
+	return DeliveryDAO{
+		db:  db,
+		log: log,
+	}
+}
+
+// Save will store the provided delivery into the database and return
+// a pointer to the saved entity. Returns nil and an error in case of error,
+// ErrRecordAlreadyExist if the webhook already has a delivery of the event.
+func (dao DeliveryDAO) Save(delivery *models.Delivery) (*models.Delivery, error) {
+	if delivery == nil {
+		dao.log.Error("deliveries storage: nil pointer given")
+		return nil, errors.New("nil delivery pointer given")
+	}
+	if delivery.ID > 0 {
+		dao.log.Warnf("deliveries storage: %v, ID: %d", sv.ErrRecordAlreadyExist, delivery.ID)
+		return nil, sv.ErrRecordAlreadyExist
+	}
+
+	now := time.Now().UTC()
+	res, err := dao.db.Exec(
+		`insert into webhook_deliveries (created_at, updated_at, webhook, event_id, event, payload, status, attempts,
+			response_code, error, next_attempt)
+		values (?, ?, ?, nullif(?, 0), ?, ?, ?, ?, ?, ?, ?);`,
+		now,
+		now,
+		delivery.WebhookID,
+		delivery.EventID,
+		delivery.EventType,
+		string(delivery.Payload),
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseCode,
+		delivery.Error,
+		utcTime(delivery.NextAttemptAt),
+	)
+	if err != nil {
+		if constraint, ok := violatedConstraint(err, ""); ok && constraint == "webhook_deliveries_webhook_event_id_key" {
+			return nil, sv.ErrRecordAlreadyExist
+		}
+		dao.log.Errorf("deliveries storage: error while inserting a row: %v", err)
+		return nil, err
+	}
+
+	ID, err := res.LastInsertId()
+	if err != nil {
+		dao.log.Errorf("deliveries storage: error while getting inserted row ID: %v", err)
+		return nil, err
+	}
+
+	return dao.reload(uint(ID), delivery)
+}
+
+// FindOneById will return a pointer to a delivery with the provided ID or
+// nil and an error
+func (dao DeliveryDAO) FindOneById(ID uint) (*models.Delivery, error) {
+	delivery := &models.Delivery{}
+	if err := dao.scan(
+		dao.db.QueryRow(`select `+deliveryColumns+` from webhook_deliveries where id = ?`, ID),
+		delivery,
+	); err != nil {
+		if err != sql.ErrNoRows {
+			dao.log.Errorf("deliveries storage: error while querying a row: %v", err)
+			return nil, err
+		}
+
+		return nil, sv.ErrRecordNotFound
+	}
+
+	return delivery, nil
+}
+
+// Find will return the deliveries of the webhook that fit the provided page from
+// the newest to the oldest or an error
+func (dao DeliveryDAO) Find(webhookID uint, page sv.Page) ([]*models.Delivery, error) {
+	where, args := "webhook = ?", []interface{}{webhookID}
+	if page.After != nil {
+		where, args = where+" and id < ?", append(args, page.After.ID)
+	}
+
+	return dao.query(
+		fmt.Sprintf(`select %s from webhook_deliveries where %s order by id desc%s`, deliveryColumns, where, limit(page)),
+		args...,
+	)
+}
+
+// Claim will postpone the next attempts of the pending deliveries due by the provided
+// time till the lease expires and return them. A delivery is claimed by the update
+// conditional on its due next attempt, so the deliveries claimed concurrently are skipped
+func (dao DeliveryDAO) Claim(now, lease time.Time, limit uint) ([]*models.Delivery, error) {
+	due, err := dao.query(
+		`select `+deliveryColumns+` from webhook_deliveries
+		where status = 'pending' and next_attempt <= ?
+		order by next_attempt, id
+		limit ?`,
+		now.UTC(),
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]*models.Delivery, 0, len(due))
+	for _, delivery := range due {
+		res, err := dao.db.Exec(
+			"update webhook_deliveries set next_attempt = ? where id = ? and status = 'pending' and next_attempt <= ?",
+			lease.UTC(),
+			delivery.ID,
+			now.UTC(),
+		)
+		if err != nil {
+			dao.log.Errorf("deliveries storage: error while updating a row: %v", err)
+			return nil, err
+		}
+		if err = expectOneRow(res); err == sv.ErrRecordNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		claimed = append(claimed, delivery)
+	}
+
+	return claimed, nil
+}
+
+// Update will update the status, the number of attempts, the response code, the
+// error and the next attempt of the delivery
+func (dao DeliveryDAO) Update(delivery *models.Delivery) (*models.Delivery, error) {
+	if delivery == nil {
+		dao.log.Error("deliveries storage: nil pointer given")
+		return nil, errors.New("nil delivery pointer given")
+	}
+
+	res, err := dao.db.Exec(
+		`update webhook_deliveries
+		set updated_at = ?, status = ?, attempts = ?, response_code = ?, error = ?, next_attempt = ?
+		where id = ?`,
+		time.Now().UTC(),
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseCode,
+		delivery.Error,
+		utcTime(delivery.NextAttemptAt),
+		delivery.ID,
+	)
+	if err != nil {
+		dao.log.Errorf("deliveries storage: error while updating a row: %v", err)
+		return nil, err
+	}
+	if err = expectOneRow(res); err != nil {
+		return nil, err
+	}
+
+	return dao.reload(delivery.ID, delivery)
+}
+
+func (dao DeliveryDAO) query(query string, args ...interface{}) ([]*models.Delivery, error) {
+	rows, err := dao.db.Query(query, args...)
+	if err != nil {
+		dao.log.Errorf("deliveries storage: error while querying rows: %v", err)
+		return nil, err
+	}
+	defer deferred(dao.log, rows.Close)
+
+	deliveries := make([]*models.Delivery, 0)
+	for rows.Next() {
+		delivery := &models.Delivery{}
+		if err := dao.scan(rows, delivery); err != nil {
+			dao.log.Errorf("deliveries storage: error while querying next row: %v", err)
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		dao.log.Errorf("deliveries storage: an error on rows query: %v", err)
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (dao DeliveryDAO) reload(ID uint, delivery *models.Delivery) (*models.Delivery, error) {
+	stored, err := dao.FindOneById(ID)
+	if err != nil {
+		return nil, err
+	}
+	*delivery = *stored
+
+	return delivery, nil
+}
+
+func (dao DeliveryDAO) scan(row scanner, delivery *models.Delivery) error {
+	var payload string
+	if err := row.Scan(
+		&delivery.ID,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseCode,
+		&delivery.Error,
+		&delivery.NextAttemptAt,
+	); err != nil {
+		return err
+	}
+	delivery.Payload = json.RawMessage(payload)
+
+	return nil
+}
+
+// utcTime returns the provided time in UTC, as the times are compared as texts
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+
+	return &utc
+}
@@ -0,0 +1,96 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidToken is returned in case of a malformed, forged or expired token
+var ErrInvalidToken = errors.New("token is invalid")
+
+// header is the only JWT header supported: HMAC SHA-256 signed token
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// JWT issues and verifies HS256 signed JSON Web Tokens
+type JWT struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewJWT is a JWT constructor
+func NewJWT(secret []byte, ttl time.Duration) *JWT {
+	return &JWT{
+		secret: secret,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Issue will return a signed token for the user with the provided ID
+func (j *JWT) Issue(userID uint) (string, error) {
+	now := j.now()
+	payload, err := json.Marshal(claims{
+		Subject:   strconv.FormatUint(uint64(userID), 10),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(j.ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	return unsigned + "." + j.sign(unsigned), nil
+}
+
+// Parse will verify the provided token and return the ID of the user it
+// was issued for
+func (j *JWT) Parse(token string) (uint, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != header {
+		return 0, ErrInvalidToken
+	}
+
+	signature := j.sign(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(signature), []byte(parts[2])) {
+		return 0, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	var c claims
+	if err = json.Unmarshal(payload, &c); err != nil || j.now().Unix() >= c.ExpiresAt {
+		return 0, ErrInvalidToken
+	}
+
+	userID, err := strconv.ParseUint(c.Subject, 10, 32)
+	if err != nil || userID == 0 {
+		return 0, ErrInvalidToken
+	}
+
+	return uint(userID), nil
+}
+
+func (j *JWT) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, j.secret)
+	mac.Write([]byte(unsigned))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
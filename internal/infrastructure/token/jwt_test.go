@@ -0,0 +1,41 @@
+// +build unit
+
+package token
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWT(t *testing.T) {
+	jwt := NewJWT([]byte("secret"), time.Hour)
+
+	token, err := jwt.Issue(42)
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(token, "."), 3)
+
+	userID, err := jwt.Parse(token)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), userID)
+
+	t.Run("forged_signature", func(t *testing.T) {
+		_, err := NewJWT([]byte("other"), time.Hour).Parse(token)
+		assert.Equal(t, ErrInvalidToken, err)
+	})
+	t.Run("malformed", func(t *testing.T) {
+		for _, malformed := range []string{"", "dummy", "a.b.c", token + "."} {
+			_, err := jwt.Parse(malformed)
+			assert.Equal(t, ErrInvalidToken, err)
+		}
+	})
+	t.Run("expired", func(t *testing.T) {
+		expired := NewJWT([]byte("secret"), time.Hour)
+		expired.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+		_, err := expired.Parse(token)
+		assert.Equal(t, ErrInvalidToken, err)
+	})
+}
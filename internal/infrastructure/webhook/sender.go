@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// HeaderEvent is the header of the type of the delivered event
+	HeaderEvent = "X-Detask-Event"
+	// HeaderDelivery is the header of the ID of the delivery, it is kept on retries
+	HeaderDelivery = "X-Detask-Delivery"
+	// HeaderSignature is the header of the HMAC SHA-256 signature of the request body
+	HeaderSignature = "X-Detask-Signature-256"
+
+	userAgent = "Detask-Webhook/1.0"
+	// bodyLimit is the number of the response body bytes that are read before the
+	// connection is reused
+	bodyLimit = 4096
+)
+
+// ErrForbiddenAddress is returned when a webhook resolves to an address that is not allowed
+var ErrForbiddenAddress = errors.New("the webhook address is not allowed")
+
+// internalNets are the networks that are not reachable by webhooks unless the private
+// addresses are allowed, so that board owners cannot reach the services that are not
+// exposed to the internet through the application
+var internalNets = []*net.IPNet{
+	cidr("0.0.0.0/8"),
+	cidr("10.0.0.0/8"),
+	cidr("100.64.0.0/10"),
+	cidr("127.0.0.0/8"),
+	cidr("169.254.0.0/16"),
+	cidr("172.16.0.0/12"),
+	cidr("192.168.0.0/16"),
+	cidr("::1/128"),
+	cidr("::/128"),
+	cidr("fc00::/7"),
+	cidr("fe80::/10"),
+}
+
+// Sender posts the deliveries to the webhooks over HTTP
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender is a Sender constructor, the provided timeout limits the time of
+// a delivery attempt. The webhooks are not allowed to reach the loopback, private
+// and link-local addresses unless allowPrivate is set, which is meant for development.
+// The addresses are checked once the host is resolved, right before the connection
+// is made. Redirects are not followed.
+func NewSender(timeout time.Duration, allowPrivate bool) *Sender {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = checkAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// the addresses of the webhooks are checked on dial, which a proxy would bypass
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Sender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send will post the payload of the delivery to the URL of the webhook signed with its
+// secret. Returns the status code of the response, or an error if the request has
+// failed or the response status is not 2xx
+func (s *Sender) Send(ctx context.Context, webhook models.Webhook, delivery models.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, bodyLimit))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, errors.Errorf("unexpected response status: %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature of the payload for the signature header: the hex encoded
+// HMAC SHA-256 of the payload keyed with the secret prefixed with the algorithm name
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkAddress rejects the connections to the internal networks
+func checkAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ErrForbiddenAddress
+	}
+	for _, internal := range internalNets {
+		if internal.Contains(ip) {
+			return ErrForbiddenAddress
+		}
+	}
+
+	return nil
+}
+
+func cidr(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return network
+}
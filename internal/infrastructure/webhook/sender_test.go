@@ -0,0 +1,144 @@
+// +build unit
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dnozdrin/detask/internal/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receiverStub returns the test server that records the last request and responds
+// with the provided status
+func receiverStub(t *testing.T, status int) (*httptest.Server, *http.Request, *[]byte) {
+	req, body := &http.Request{}, new([]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*req = *r
+		*body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, req, body
+}
+
+func TestSender_Send(t *testing.T) {
+	delivery := models.Delivery{ID: 7, EventType: "task.created", Payload: json.RawMessage(`{"id":1}`)}
+
+	t.Run("success", func(t *testing.T) {
+		server, req, body := receiverStub(t, http.StatusNoContent)
+		webhook := models.Webhook{URL: server.URL + "/hook", Secret: "0123456789abcdef"}
+
+		code, err := NewSender(time.Second, true).Send(context.Background(), webhook, delivery)
+		require.Nil(t, err)
+		assert.Equal(t, http.StatusNoContent, code)
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "/hook", req.URL.Path)
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "task.created", req.Header.Get(HeaderEvent))
+		assert.Equal(t, "7", req.Header.Get(HeaderDelivery))
+		assert.Equal(t, Sign(webhook.Secret, delivery.Payload), req.Header.Get(HeaderSignature))
+		assert.Equal(t, `{"id":1}`, string(*body))
+	})
+
+	t.Run("unsuccessful_status", func(t *testing.T) {
+		server, _, _ := receiverStub(t, http.StatusInternalServerError)
+
+		code, err := NewSender(time.Second, true).Send(context.Background(), models.Webhook{URL: server.URL}, delivery)
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusInternalServerError, code)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		t.Cleanup(server.Close)
+
+		code, err := NewSender(50*time.Millisecond, true).Send(context.Background(), models.Webhook{URL: server.URL}, delivery)
+		assert.NotNil(t, err)
+		assert.Equal(t, 0, code)
+	})
+}
+
+func TestSender_Send_Addresses(t *testing.T) {
+	delivery := models.Delivery{ID: 7, EventType: "task.created", Payload: json.RawMessage(`{"id":1}`)}
+
+	t.Run("private_address", func(t *testing.T) {
+		server, req, _ := receiverStub(t, http.StatusNoContent)
+
+		code, err := NewSender(time.Second, false).Send(context.Background(), models.Webhook{URL: server.URL}, delivery)
+		assert.True(t, errors.Is(err, ErrForbiddenAddress))
+		assert.Equal(t, 0, code)
+		assert.Empty(t, req.Method)
+	})
+
+	t.Run("private_host", func(t *testing.T) {
+		server, _, _ := receiverStub(t, http.StatusNoContent)
+		_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+		webhook := models.Webhook{URL: "http://localhost:" + port}
+
+		_, err := NewSender(time.Second, false).Send(context.Background(), webhook, delivery)
+		assert.True(t, errors.Is(err, ErrForbiddenAddress))
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		target, req, _ := receiverStub(t, http.StatusNoContent)
+		server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+		t.Cleanup(server.Close)
+
+		code, err := NewSender(time.Second, true).Send(context.Background(), models.Webhook{URL: server.URL}, delivery)
+		assert.NotNil(t, err)
+		assert.Equal(t, http.StatusTemporaryRedirect, code)
+		assert.Empty(t, req.Method)
+	})
+}
+
+func TestCheckAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{"93.184.216.34:80", true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true},
+		{"127.0.0.1:80", false},
+		{"10.1.2.3:80", false},
+		{"172.20.0.1:80", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"0.0.0.0:80", false},
+		{"[::1]:80", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"[fd00::1]:80", false},
+		{"[fe80::1]:80", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := checkAddress("tcp", tt.address, nil)
+			if tt.allowed {
+				assert.Nil(t, err)
+			} else {
+				assert.Equal(t, ErrForbiddenAddress, err)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// the signature computed with openssl dgst -sha256 -hmac secret
+	assert.Equal(
+		t,
+		"sha256=b82fcb791acec57859b989b430a826488ce2e479fdf92326bd0a2e8375a42ba4",
+		Sign("secret", []byte("payload")),
+	)
+}
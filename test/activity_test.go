@@ -0,0 +1,56 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestActivity(t *testing.T) {
+	resetData(t)
+
+	var (
+		entries []map[string]interface{}
+
+		assert = testify.New(t)
+		stubs  = seedTasks(t)
+	)
+
+	request := func(method, path, body string) int {
+		req, err := http.NewRequest(method, path, bytes.NewBuffer([]byte(body)))
+		must(t, err, "testing: failed to make a %s request to '%s'", method, path)
+		return executeRequest(req).Code
+	}
+	list := func(path string) []map[string]interface{} {
+		req, err := http.NewRequest("GET", path, nil)
+		must(t, err, "testing: failed to make a GET request to '%s'", path)
+		response := executeRequest(req)
+		assert.Equal(http.StatusOK, response.Code)
+		err = json.Unmarshal(response.Body.Bytes(), &entries)
+		must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+		return entries
+	}
+
+	assert.Equal(http.StatusOK, request("PUT", "/api/v1/tasks/1", `{"name":"renamed","description":"test description 1","column":1,"position":1000}`))
+	assert.Equal(http.StatusNoContent, request("DELETE", "/api/v1/tasks/2", ""))
+
+	entries = list("/api/v1/tasks/1/activity")
+	assert.Len(entries, 1)
+	assert.Equal("task", entries[0]["entity"])
+	assert.Equal("update", entries[0]["action"])
+	changes := entries[0]["changes"].(map[string]interface{})
+	assert.Equal(map[string]interface{}{"from": stubs[0].name, "to": "renamed"}, changes["name"])
+
+	entries = list("/api/v1/boards/1/activity")
+	assert.Len(entries, 2)
+	assert.Equal("delete", entries[0]["action"])
+	assert.Equal(2.0, entries[0]["task"])
+
+	entries = list("/api/v1/boards/1/activity?entity=task&limit=1")
+	assert.Len(entries, 1)
+	assert.Equal(http.StatusBadRequest, request("GET", "/api/v1/boards/1/activity?entity=user", ""))
+}
@@ -0,0 +1,78 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthRegister(t *testing.T) {
+	assert := testify.New(t)
+	const payload = `{"email":"John@Example.com","name":"John","password":"secret password"}`
+
+	resetData(t)
+
+	req, err := http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBufferString(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/auth/register'")
+	response := executeRequest(req)
+
+	var user map[string]interface{}
+	must(t, json.Unmarshal(response.Body.Bytes(), &user), "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusCreated, response.Code)
+	assert.Equal("john@example.com", user["email"])
+	assert.Equal("John", user["name"])
+	assert.NotContains(user, "password")
+
+	req, err = http.NewRequest("POST", "/api/v1/auth/register", bytes.NewBufferString(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/auth/register'")
+	assert.Equal(http.StatusConflict, executeRequest(req).Code)
+}
+
+func TestAuthLogin(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		code    int
+	}{
+		{"success", `{"email":"tester@example.com","password":"test password"}`, http.StatusOK},
+		{"wrong_password", `{"email":"tester@example.com","password":"wrong password"}`, http.StatusUnauthorized},
+		{"unknown_user", `{"email":"nobody@example.com","password":"test password"}`, http.StatusUnauthorized},
+		{"invalid_payload", `{"email":"tester"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/api/v1/auth/login", bytes.NewBufferString(tt.payload))
+			must(t, err, "testing: failed to make a POST request to '/api/v1/auth/login'")
+
+			testify.Equal(t, tt.code, executeRequest(req).Code)
+		})
+	}
+}
+
+func TestAuthRequired(t *testing.T) {
+	assert := testify.New(t)
+
+	req, err := http.NewRequest("GET", "/api/v1/boards", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/boards'")
+	response := httptest.NewRecorder()
+	a.ServeHTTPInternal(response, req)
+	assert.Equal(http.StatusUnauthorized, response.Code)
+	assert.Equal("Bearer", response.Header().Get("WWW-Authenticate"))
+
+	req, err = http.NewRequest("GET", "/api/v1/boards", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/boards'")
+	req.Header.Set("Authorization", "Bearer invalid")
+	assert.Equal(http.StatusUnauthorized, executeRequest(req).Code)
+
+	req, err = http.NewRequest("GET", "/api/v1/me", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/me'")
+	response = executeRequest(req)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Contains(response.Body.String(), `"email":"tester@example.com"`)
+}
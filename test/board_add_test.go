@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
 	testify "github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
@@ -13,7 +14,7 @@ import (
 )
 
 func TestBoardAdd_OK(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	var (
 		err   error
@@ -35,38 +36,31 @@ func TestBoardAdd_OK(t *testing.T) {
 
 	assert.Equal(http.StatusCreated, response.Code)
 	assert.Equal("/api/v1/boards/1", response.Header().Get("Location"))
+	assert.Equal(`"1"`, response.Header().Get("ETag"))
 	assert.Equal(name, board["name"])
 	assert.Equal(description, board["description"])
 	assert.Equal(1.0, board["id"])
-
-	var (
-		bID, cID                                       uint
-		bName, bDescription, cName                     string
-		bCreatedAt, bUpdatedAt, cCreatedAt, cUpdatedAt time.Time
-		cPosition                                      float64
-	)
-	err = a.DB.QueryRow(
-		`select
-				b.id, b.name, b.description, b.created_at, b.updated_at,
-       			c.id, c.name, c.position, c.created_at, c.updated_at
-       		from boards b join columns c on b.id = c.board where b.id = 1;`,
-	).Scan(
-		&bID, &bName, &bDescription, &bCreatedAt, &bUpdatedAt,
-		&cID, &cName, &cPosition, &cCreatedAt, &cUpdatedAt,
-	)
-	must(t, err, "testing: failed to make database query on board add test")
-
-	assert.Equal(uint(1), bID)
-	assert.Equal(name, bName)
-	assert.Equal(description, bDescription)
-	assert.WithinDuration(time.Now(), bCreatedAt, maxTestsRunExpected)
-	assert.WithinDuration(time.Now(), bUpdatedAt, maxTestsRunExpected)
-
-	assert.Equal(uint(1), cID)
-	assert.Equal("Default", cName)
-	assert.Equal(float64(1000), cPosition)
-	assert.WithinDuration(time.Now(), cCreatedAt, maxTestsRunExpected)
-	assert.WithinDuration(time.Now(), cUpdatedAt, maxTestsRunExpected)
+	assert.NotZero(board["created_by"])
+
+	storages := a.StoragesInternal()
+	saved, err := storages.Boards.FindOneById(1)
+	must(t, err, "testing: failed to find the board on board add test")
+	columns, err := storages.Columns.Find(sv.ColumnDemand{"board": uint(1)}, sv.Page{})
+	must(t, err, "testing: failed to find the columns on board add test")
+
+	assert.Equal(uint(1), saved.ID)
+	assert.Equal(name, saved.Name)
+	assert.Equal(description, saved.Description)
+	assert.WithinDuration(time.Now(), saved.CreatedAt, maxTestsRunExpected)
+	assert.WithinDuration(time.Now(), saved.UpdatedAt, maxTestsRunExpected)
+
+	if assert.Len(columns, 1) {
+		assert.Equal(uint(1), columns[0].ID)
+		assert.Equal("Default", columns[0].Name)
+		assert.Equal(float64(1000), columns[0].Position)
+		assert.WithinDuration(time.Now(), columns[0].CreatedAt, maxTestsRunExpected)
+		assert.WithinDuration(time.Now(), columns[0].UpdatedAt, maxTestsRunExpected)
+	}
 }
 
 func TestBoardAdd_BadRequest(t *testing.T) {
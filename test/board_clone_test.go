@@ -0,0 +1,111 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBoardClone_WithTasks(t *testing.T) {
+	resetData(t)
+
+	var (
+		err   error
+		board map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	// the board 1 has a column with a task commented three times
+	_ = seedComments(t)
+	payload := []byte(`{"name":"copy","include":"tasks"}`)
+	req, err := http.NewRequest("POST", "/api/v1/boards/1/clone", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/boards/1/clone'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &board)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusCreated, response.Code)
+	assert.Equal("/api/v1/boards/2", response.Header().Get("Location"))
+	assert.Equal("copy", board["name"])
+	assert.Equal("test description 1", board["description"])
+
+	storages := a.StoragesInternal()
+	tasks, err := storages.Tasks.Find(sv.TaskDemand{"board": uint(2)}, sv.Page{})
+	must(t, err, "testing: failed to find the copied tasks")
+	if assert.Len(tasks, 1) {
+		comments, err := storages.Comments.Find(sv.CommentDemand{"task": tasks[0].ID}, sv.Page{})
+		must(t, err, "testing: failed to find the copied comments")
+		assert.Len(comments, 3)
+	}
+}
+
+func TestBoardAdd_FromTemplate(t *testing.T) {
+	resetData(t)
+
+	var (
+		err   error
+		board map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedColumns(t)
+	storages := a.StoragesInternal()
+	template, err := storages.Boards.FindOneById(1)
+	must(t, err, "testing: failed to find the template")
+	template.Template = true
+	_, err = storages.Boards.Update(template)
+	must(t, err, "testing: failed to flag the template")
+
+	payload := []byte(`{"name":"project","description":"from template","template_id":1}`)
+	req, err := http.NewRequest("POST", "/api/v1/board", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/board'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &board)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusCreated, response.Code)
+	assert.Equal(1.0, board["template_id"])
+
+	var copied int
+	columns, err := storages.Columns.Find(sv.ColumnDemand{"board": uint(2)}, sv.Page{})
+	must(t, err, "testing: failed to find columns")
+	for _, column := range columns {
+		if strings.HasPrefix(column.Name, "test name ") {
+			copied++
+		}
+	}
+	assert.Equal(3, copied)
+}
+
+func TestBoardAdd_NotTemplate(t *testing.T) {
+	resetData(t)
+
+	var (
+		err  error
+		body map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedColumns(t)
+	payload := []byte(`{"name":"project","description":"from template","template_id":1}`)
+	req, err := http.NewRequest("POST", "/api/v1/board", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/board'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &body)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusBadRequest, response.Code)
+	assert.Equal("a template with the provided ID was not found", body["error"])
+}
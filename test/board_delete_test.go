@@ -10,7 +10,7 @@ import (
 )
 
 func TestBoardDelete(t *testing.T) {
-	clearTable(t, "boards")
+	resetData(t)
 
 	var (
 		num int
@@ -3,15 +3,18 @@
 package test
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	testify "github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestBoardGet_OK(t *testing.T) {
-	clearTable(t, "boards")
+	resetData(t)
 	var (
 		board map[string]interface{}
 
@@ -36,7 +39,7 @@ func TestBoardGet_OK(t *testing.T) {
 }
 
 func TestBoardGet_NotFound(t *testing.T) {
-	clearTable(t, "boards")
+	resetData(t)
 	var (
 		err  error
 		body map[string]interface{}
@@ -54,3 +57,60 @@ func TestBoardGet_NotFound(t *testing.T) {
 	assert.Equal(http.StatusNotFound, response.Code)
 	assert.Equal("resource was not found", body["error"])
 }
+
+func TestBoardGet_NotModified(t *testing.T) {
+	resetData(t)
+	seedBoards(t)
+
+	assert := testify.New(t)
+	get := func(path, header, value string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", path, nil)
+		must(t, err, "testing: failed to make a GET request to '%s'", path)
+		req.Header.Set(header, value)
+		return executeRequest(req)
+	}
+
+	response := get("/api/v1/boards/1", "", "")
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(http.StatusNotModified, get("/api/v1/boards/1", "If-None-Match", response.Header().Get("ETag")).Code)
+	assert.Equal(http.StatusNotModified, get("/api/v1/boards/1", "If-Modified-Since", response.Header().Get("Last-Modified")).Code)
+
+	// the collections are validated by the tag alone
+	response = get("/api/v1/boards", "", "")
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Empty(response.Header().Get("Last-Modified"))
+	assert.Equal(http.StatusNotModified, get("/api/v1/boards", "If-None-Match", response.Header().Get("ETag")).Code)
+	assert.Equal(http.StatusOK, get("/api/v1/boards", "If-Modified-Since", time.Now().UTC().Format(http.TimeFormat)).Code)
+
+	tag := get("/api/v1/boards", "", "").Header().Get("ETag")
+	req, err := http.NewRequest("PUT", "/api/v1/boards/1", bytes.NewBuffer([]byte(`{"name":"renamed","description":"test description 1"}`)))
+	must(t, err, "testing: failed to make a PUT request to '/api/v1/boards/1'")
+	assert.Equal(http.StatusOK, executeRequest(req).Code)
+
+	assert.Equal(http.StatusOK, get("/api/v1/boards", "If-None-Match", tag).Code)
+	assert.Equal(http.StatusOK, get("/api/v1/boards/1", "If-None-Match", `"1"`).Code)
+}
+
+func TestBoardGet_NotModifiedAfterDelete(t *testing.T) {
+	resetData(t)
+	seedBoards(t)
+
+	assert := testify.New(t)
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", "/api/v1/boards", nil)
+		must(t, err, "testing: failed to make a GET request to '/api/v1/boards'")
+		req.Header.Set(header, value)
+		return executeRequest(req)
+	}
+
+	response := get("", "")
+	assert.Equal(http.StatusOK, response.Code)
+	since := time.Now().UTC().Add(time.Second).Format(http.TimeFormat)
+
+	req, err := http.NewRequest("DELETE", "/api/v1/boards/2", nil)
+	must(t, err, "testing: failed to make a DELETE request to '/api/v1/boards/2'")
+	assert.Equal(http.StatusNoContent, executeRequest(req).Code)
+
+	assert.Equal(http.StatusOK, get("If-None-Match", response.Header().Get("ETag")).Code)
+	assert.Equal(http.StatusOK, get("If-Modified-Since", since).Code)
+}
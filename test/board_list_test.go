@@ -10,7 +10,7 @@ import (
 )
 
 func TestBoardList_OK(t *testing.T) {
-	clearTable(t, "boards")
+	resetData(t)
 	var (
 		err    error
 		boards []map[string]interface{}
@@ -35,7 +35,7 @@ func TestBoardList_OK(t *testing.T) {
 }
 
 func TestBoardList_NoItems(t *testing.T) {
-	clearTable(t, "boards")
+	resetData(t)
 	var (
 		err    error
 		boards []map[string]interface{}
@@ -0,0 +1,55 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBoardPatch(t *testing.T) {
+	resetData(t)
+	stubs := seedBoards(t)
+
+	assert := testify.New(t)
+	request := func(contentType, ifMatch, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("PATCH", "/api/v1/boards/1", bytes.NewBuffer([]byte(body)))
+		must(t, err, "testing: failed to make a PATCH request to '/api/v1/boards/1'")
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("If-Match", ifMatch)
+		return executeRequest(req)
+	}
+
+	var board map[string]interface{}
+	response := request("application/merge-patch+json", `"1"`, `{"name":"merged"}`)
+	err := json.Unmarshal(response.Body.Bytes(), &board)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(`"2"`, response.Header().Get("ETag"))
+	assert.Equal("merged", board["name"])
+	assert.Equal(stubs[0].description, board["description"])
+
+	response = request("application/json-patch+json", "", `[
+		{"op":"test","path":"/name","value":"merged"},
+		{"op":"copy","from":"/name","path":"/description"}
+	]`)
+	err = json.Unmarshal(response.Body.Bytes(), &board)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal("merged", board["description"])
+
+	response = request("application/json", "", `{"name":"plain"}`)
+	assert.Equal(http.StatusUnsupportedMediaType, response.Code)
+	assert.Equal("application/merge-patch+json, application/json-patch+json", response.Header().Get("Accept-Patch"))
+
+	assert.Equal(http.StatusBadRequest, request("application/merge-patch+json", "", `{"name":""}`).Code)
+	assert.Equal(http.StatusBadRequest, request("application/json-patch+json", "", `[{"op":"remove","path":"/color"}]`).Code)
+	assert.Equal(http.StatusConflict, request("application/json-patch+json", "", `[{"op":"test","path":"/name","value":"x"}]`).Code)
+	assert.Equal(http.StatusPreconditionFailed, request("application/merge-patch+json", `"1"`, `{"name":"stale"}`).Code)
+}
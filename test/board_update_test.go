@@ -8,12 +8,12 @@ import (
 	"fmt"
 	testify "github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
 	"testing"
-	"time"
 )
 
 func TestBoardUpdate_OK(t *testing.T) {
-	clearTable(t, "boards")
+	resetData(t)
 
 	var (
 		assert = testify.New(t)
@@ -49,21 +49,13 @@ func TestBoardUpdate_OK(t *testing.T) {
 		assert.Equal(expectedDescription, board["description"])
 		assert.Equal(float64(ID), board["id"])
 
-		var (
-			uID                  uint
-			name, description    string
-			createdAt, updatedAt time.Time
-		)
-		err = a.DB.QueryRow(`select id, name, description, created_at, updated_at from boards where id = $1;`, ID).
-			Scan(&uID, &name, &description, &createdAt, &updatedAt)
-		must(t, err, "testing: failed to make a query on board update test")
-
-		assert.Equal(uint(ID), uID)
-		assert.Equal(expectedName, name)
-		assert.Equal(expectedDescription, description)
-		assert.NotNil(createdAt)
-		assert.NotNil(updatedAt)
-		assert.True(updatedAt.After(createdAt))
+		saved, err := a.StoragesInternal().Boards.FindOneById(uint(ID))
+		must(t, err, "testing: failed to find the board on board update test")
+
+		assert.Equal(uint(ID), saved.ID)
+		assert.Equal(expectedName, saved.Name)
+		assert.Equal(expectedDescription, saved.Description)
+		assert.True(saved.UpdatedAt.After(saved.CreatedAt))
 	}
 }
 
@@ -130,7 +122,7 @@ func TestBoardUpdate_ValidationError(t *testing.T) {
 }
 
 func TestBoardUpdate_RecordNotFound(t *testing.T) {
-	clearTable(t, "boards")
+	resetData(t)
 
 	const (
 		name        = "test name"
@@ -155,3 +147,29 @@ func TestBoardUpdate_RecordNotFound(t *testing.T) {
 	assert.Equal(http.StatusNotFound, response.Code)
 	assert.Equal("resource was not found", body["error"])
 }
+
+func TestBoardUpdate_PreconditionFailed(t *testing.T) {
+	resetData(t)
+	seedBoards(t)
+
+	assert := testify.New(t)
+	request := func(method, ifMatch, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, "/api/v1/boards/1", bytes.NewBuffer([]byte(body)))
+		must(t, err, "testing: failed to make a %s request to '/api/v1/boards/1'", method)
+		req.Header.Set("If-Match", ifMatch)
+		return executeRequest(req)
+	}
+
+	response := request("GET", "", "")
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(`"1"`, response.Header().Get("ETag"))
+
+	response = request("PUT", `"1"`, `{"name":"renamed","description":"test description 1"}`)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(`"2"`, response.Header().Get("ETag"))
+
+	assert.Equal(http.StatusPreconditionFailed, request("PUT", `"1"`, `{"name":"stale","description":"test description 1"}`).Code)
+	assert.Equal(http.StatusPreconditionFailed, request("DELETE", `"1"`, "").Code)
+	assert.Equal(http.StatusPreconditionFailed, request("DELETE", "1", "").Code)
+	assert.Equal(http.StatusNoContent, request("DELETE", `"2"`, "").Code)
+}
@@ -13,7 +13,7 @@ import (
 )
 
 func TestColumnAdd_OK(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	const (
 		name             = "test name"
@@ -29,8 +29,7 @@ func TestColumnAdd_OK(t *testing.T) {
 		jsonStr = fmt.Sprintf(`{"name":"%s","board":%d,"position":%f}`, name, board, position)
 	)
 
-	_, err = a.DB.Exec(`insert into boards (name, description) values ('test board', 'test description');`)
-	must(t, err, "testing: failed to insert a board for column add test")
+	seedBoard(t, "test board", "test description")
 
 	req, err := http.NewRequest("POST", "/api/v1/column", bytes.NewBuffer([]byte(jsonStr)))
 	must(t, err, "testing: failed to make a POST request to '/api/v1/column'")
@@ -42,26 +41,20 @@ func TestColumnAdd_OK(t *testing.T) {
 
 	assert.Equal(http.StatusCreated, response.Code)
 	assert.Equal("/api/v1/columns/1", response.Header().Get("Location"))
+	assert.Equal(`"1"`, response.Header().Get("ETag"))
 	assert.Equal(name, column["name"])
 	assert.Equal(position, column["position"])
 	assert.Equal(1.0, column["board"])
 	assert.Equal(1.0, column["id"])
 
-	var (
-		ID                   uint
-		checkName            string
-		createdAt, updatedAt time.Time
-		checkPosition        float64
-	)
-	err = a.DB.QueryRow(`select id, name, position, created_at, updated_at from "columns" where id = 1;`).
-		Scan(&ID, &checkName, &checkPosition, &createdAt, &updatedAt)
-	must(t, err, "testing: failed to make database query on column add test")
-
-	assert.Equal(uint(1), ID)
-	assert.Equal(name, checkName)
-	assert.Equal(position, checkPosition)
-	assert.WithinDuration(time.Now(), createdAt, maxTestsRunExpected)
-	assert.WithinDuration(time.Now(), updatedAt, maxTestsRunExpected)
+	saved, err := a.StoragesInternal().Columns.FindOneById(1)
+	must(t, err, "testing: failed to find the column on column add test")
+
+	assert.Equal(uint(1), saved.ID)
+	assert.Equal(name, saved.Name)
+	assert.Equal(position, saved.Position)
+	assert.WithinDuration(time.Now(), saved.CreatedAt, maxTestsRunExpected)
+	assert.WithinDuration(time.Now(), saved.UpdatedAt, maxTestsRunExpected)
 }
 
 func TestColumnAdd_BadRequest(t *testing.T) {
@@ -121,7 +114,7 @@ func TestColumnAdd_ValidationError(t *testing.T) {
 }
 
 func TestColumnAdd_WrongBoard(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	const (
 		name             = "test name"
@@ -148,7 +141,7 @@ func TestColumnAdd_WrongBoard(t *testing.T) {
 }
 
 func TestColumnAdd_PositionDuplicate(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	const (
 		name             = "test name"
@@ -163,10 +156,8 @@ func TestColumnAdd_PositionDuplicate(t *testing.T) {
 		jsonStr = fmt.Sprintf(`{"name":"%s","board":%d,"position":%.0f}`, name, board, position)
 	)
 
-	_, err = a.DB.Exec(`insert into boards (name, description) values ('test board', 'test description');`)
-	must(t, err, "testing: failed to insert a board for column add test")
-	_, err = a.DB.Exec(`insert into columns (name, board, position) values ('test name 2', $1, $2);`, board, position)
-	must(t, err, "testing: failed to insert a column for column add test")
+	seedBoard(t, "test board", "test description")
+	seedColumn(t, "test name 2", board, position)
 
 	req, err := http.NewRequest("POST", "/api/v1/column", bytes.NewBuffer([]byte(jsonStr)))
 	must(t, err, "testing: failed to make a POST request to '/api/v1/column'")
@@ -180,7 +171,7 @@ func TestColumnAdd_PositionDuplicate(t *testing.T) {
 }
 
 func TestColumnAdd_NameDuplicate(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	const (
 		name             = "test name"
@@ -195,10 +186,8 @@ func TestColumnAdd_NameDuplicate(t *testing.T) {
 		jsonStr = fmt.Sprintf(`{"name":"%s","board":%d,"position":%.0f}`, name, board, position)
 	)
 
-	_, err = a.DB.Exec(`insert into boards (name, description) values ('test board', 'test description');`)
-	must(t, err, "testing: failed to insert a board for column add test")
-	_, err = a.DB.Exec(`insert into columns (name, board, position) values ($1, $2, 1001);`, name, board)
-	must(t, err, "testing: failed to insert a column for column add test")
+	seedBoard(t, "test board", "test description")
+	seedColumn(t, name, board, 1001)
 
 	req, err := http.NewRequest("POST", "/api/v1/column", bytes.NewBuffer([]byte(jsonStr)))
 	must(t, err, "testing: failed to make a POST request to '/api/v1/column'")
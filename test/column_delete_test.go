@@ -11,7 +11,7 @@ import (
 )
 
 func TestColumnDelete(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	var (
 		assert   = testify.New(t)
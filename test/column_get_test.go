@@ -11,7 +11,7 @@ import (
 )
 
 func TestColumnGet_OK(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 	var (
 		column map[string]interface{}
 
@@ -37,7 +37,7 @@ func TestColumnGet_OK(t *testing.T) {
 }
 
 func TestColumnGet_NotFound(t *testing.T) {
-	clearTables(t, "columns")
+	resetData(t)
 	var (
 		err  error
 		body map[string]interface{}
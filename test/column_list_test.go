@@ -10,7 +10,7 @@ import (
 )
 
 func TestColumnList_OK(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 	var (
 		err     error
 		columns []map[string]interface{}
@@ -36,7 +36,7 @@ func TestColumnList_OK(t *testing.T) {
 }
 
 func TestColumnList_NoItems(t *testing.T) {
-	clearTable(t, "columns")
+	resetData(t)
 	var (
 		err     error
 		columns []map[string]interface{}
@@ -56,7 +56,7 @@ func TestColumnList_NoItems(t *testing.T) {
 }
 
 func TestColumnList_Demand(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 	var (
 		comments []map[string]interface{}
 		stubs    = seedColumns(t)
@@ -77,10 +77,8 @@ func TestColumnList_Demand(t *testing.T) {
 		req, err := http.NewRequest("GET", "/api/v1/columns?board=2", nil)
 		must(t, err, "testing: failed to make a GET request to '/api/v1/columns?board=2'")
 
-		_, err = a.DB.Exec(`insert into boards (name, description) values ('test board 2', 'test description 2');`)
-		must(t, err, "testing: failed seed a board for comment demand")
-		_, err = a.DB.Exec(`insert into columns (name, board, position) values ('test column 2', 2, 2000);`)
-		must(t, err, "testing: failed seed a column for comment demand")
+		boardID := seedBoard(t, "test board 2", "test description 2")
+		seedColumn(t, "test column 2", boardID, 2000)
 
 		response := executeRequest(req)
 		err = json.Unmarshal(response.Body.Bytes(), &comments)
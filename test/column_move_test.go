@@ -0,0 +1,90 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestColumnMove_OK(t *testing.T) {
+	resetData(t)
+
+	var (
+		err    error
+		column map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedColumns(t)
+	req, err := http.NewRequest("POST", "/api/v1/columns/3/move", bytes.NewBuffer([]byte(`{"after":1}`)))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/columns/3/move'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &column)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(3.0, column["id"])
+	assert.Equal(1500.0, column["position"])
+
+	saved, err := a.StoragesInternal().Columns.FindOneById(3)
+	must(t, err, "testing: failed to find the column on column move test")
+	assert.Equal(fmt.Sprintf(`"%d"`, saved.Version), response.Header().Get("ETag"))
+}
+
+func TestColumnReorder_OK(t *testing.T) {
+	resetData(t)
+
+	var (
+		err     error
+		columns []map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedColumns(t)
+	payload := []byte(`{"columns":[3,1,2]}`)
+	req, err := http.NewRequest("PUT", "/api/v1/boards/1/columns/order", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a PUT request to '/api/v1/boards/1/columns/order'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &columns)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	if assert.Len(columns, 3) {
+		for i, ID := range []float64{3, 1, 2} {
+			assert.Equal(ID, columns[i]["id"])
+			assert.Equal(float64(1000*(i+1)), columns[i]["position"])
+		}
+	}
+}
+
+func TestColumnReorder_IncompleteOrder(t *testing.T) {
+	resetData(t)
+
+	var (
+		err  error
+		body map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedColumns(t)
+	payload := []byte(`{"columns":[3,1]}`)
+	req, err := http.NewRequest("PUT", "/api/v1/boards/1/columns/order", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a PUT request to '/api/v1/boards/1/columns/order'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &body)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusBadRequest, response.Code)
+	assert.Equal("the order must list every column of the board exactly once", body["error"])
+}
@@ -9,11 +9,10 @@ import (
 	testify "github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
-	"time"
 )
 
 func TestColumnUpdate_OK(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	var (
 		assert = testify.New(t)
@@ -50,23 +49,14 @@ func TestColumnUpdate_OK(t *testing.T) {
 		assert.Equal(expectedPosition, column["position"])
 		assert.Equal(float64(ID), column["id"])
 
-		var (
-			uID, board           uint
-			position             float64
-			name                 string
-			createdAt, updatedAt time.Time
-		)
-		err = a.DB.QueryRow(`select id, name, position, board, created_at, updated_at from columns where id = $1;`, ID).
-			Scan(&uID, &name, &position, &board, &createdAt, &updatedAt)
-		must(t, err, "testing: failed to make a query on board update test")
-
-		assert.Equal(uint(ID), uID)
-		assert.Equal(expectedName, name)
-		assert.Equal(expectedPosition, position)
-		assert.Equal(stubs[ID-1].board, board)
-		assert.NotNil(createdAt)
-		assert.NotNil(updatedAt)
-		assert.True(updatedAt.After(createdAt))
+		saved, err := a.StoragesInternal().Columns.FindOneById(uint(ID))
+		must(t, err, "testing: failed to find the column on column update test")
+
+		assert.Equal(uint(ID), saved.ID)
+		assert.Equal(expectedName, saved.Name)
+		assert.Equal(expectedPosition, saved.Position)
+		assert.Equal(stubs[ID-1].board, saved.BoardID)
+		assert.True(saved.UpdatedAt.After(saved.CreatedAt))
 	}
 }
 
@@ -127,7 +117,7 @@ func TestColumnUpdate_ValidationError(t *testing.T) {
 }
 
 func TestColumnUpdate_RecordNotFound(t *testing.T) {
-	clearTable(t, "columns")
+	resetData(t)
 
 	var (
 		err  error
@@ -149,7 +139,7 @@ func TestColumnUpdate_RecordNotFound(t *testing.T) {
 }
 
 func TestColumnUpdate_PositionDuplicate(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	var (
 		err  error
@@ -172,14 +162,14 @@ func TestColumnUpdate_PositionDuplicate(t *testing.T) {
 }
 
 func TestColumnUpdate_NameDuplicate(t *testing.T) {
-	clearTables(t, "boards", "columns")
+	resetData(t)
 
 	var (
 		err  error
 		body map[string]interface{}
 
 		assert  = testify.New(t)
-		jsonStr = `{"name":"test name 1", "board":1, "position":1000}`
+		jsonStr = `{"name":"test name 1", "board":1, "position":2000}`
 	)
 
 	_ = seedColumns(t)
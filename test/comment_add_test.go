@@ -13,7 +13,7 @@ import (
 )
 
 func TestCommentAdd_OK(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks", "comments")
+	resetData(t)
 
 	const (
 		text = "any text here"
@@ -40,24 +40,19 @@ func TestCommentAdd_OK(t *testing.T) {
 
 	assert.Equal(http.StatusCreated, response.Code)
 	assert.Equal("/api/v1/comments/1", response.Header().Get("Location"))
+	assert.Equal(`"1"`, response.Header().Get("ETag"))
 	assert.Equal(1.0, comment["id"])
 	assert.Equal(text, comment["text"])
 	assert.Equal(1.0, comment["task"])
 
-	var (
-		ID, checkTask        uint
-		checkText            string
-		createdAt, updatedAt time.Time
-	)
-	err = a.DB.QueryRow(`select id, text, task, created_at, updated_at from comments where id = 1;`).
-		Scan(&ID, &checkText, &checkTask, &createdAt, &updatedAt)
-	must(t, err, "testing: failed to make database query on column add test")
-
-	assert.Equal(uint(1), ID)
-	assert.Equal(text, checkText)
-	assert.Equal(uint(task), checkTask)
-	assert.WithinDuration(time.Now(), createdAt, maxTestsRunExpected)
-	assert.WithinDuration(time.Now(), updatedAt, maxTestsRunExpected)
+	saved, err := a.StoragesInternal().Comments.FindOneById(1)
+	must(t, err, "testing: failed to find the comment on comment add test")
+
+	assert.Equal(uint(1), saved.ID)
+	assert.Equal(text, saved.Text)
+	assert.Equal(uint(task), saved.TaskID)
+	assert.WithinDuration(time.Now(), saved.CreatedAt, maxTestsRunExpected)
+	assert.WithinDuration(time.Now(), saved.UpdatedAt, maxTestsRunExpected)
 }
 
 func TestCommentAdd_BadRequest(t *testing.T) {
@@ -115,7 +110,7 @@ func TestCommentAdd_ValidationError(t *testing.T) {
 }
 
 func TestCommentAdd_WrongTask(t *testing.T) {
-	clearTables(t, "tasks", "comments")
+	resetData(t)
 
 	const (
 		text = "any text here"
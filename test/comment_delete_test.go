@@ -10,7 +10,7 @@ import (
 )
 
 func TestCommentDelete(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks", "comments")
+	resetData(t)
 
 	var (
 		assert   = testify.New(t)
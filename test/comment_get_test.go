@@ -11,7 +11,7 @@ import (
 )
 
 func TestCommentGet_OK(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks", "comments")
+	resetData(t)
 	var (
 		comment map[string]interface{}
 
@@ -36,7 +36,7 @@ func TestCommentGet_OK(t *testing.T) {
 }
 
 func TestCommentGet_NotFound(t *testing.T) {
-	clearTable(t, "comments")
+	resetData(t)
 	var (
 		err  error
 		body map[string]interface{}
@@ -10,7 +10,7 @@ import (
 )
 
 func TestCommentList_OK(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks", "comments")
+	resetData(t)
 	var (
 		err      error
 		comments []map[string]interface{}
@@ -28,14 +28,16 @@ func TestCommentList_OK(t *testing.T) {
 
 	assert.Equal(http.StatusOK, response.Code)
 	assert.Len(comments, len(stubs))
+	// the comments are listed from the newest to the oldest one
 	for k, b := range comments {
-		assert.Equal(stubs[k].text, b["text"])
-		assert.Equal(float64(stubs[k].task), b["task"])
+		stub := stubs[len(stubs)-1-k]
+		assert.Equal(stub.text, b["text"])
+		assert.Equal(float64(stub.task), b["task"])
 	}
 }
 
 func TestCommentList_NoItems(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks", "comments")
+	resetData(t)
 	var (
 		err      error
 		comments []map[string]interface{}
@@ -55,7 +57,7 @@ func TestCommentList_NoItems(t *testing.T) {
 }
 
 func TestCommentList_Demand(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks", "comments")
+	resetData(t)
 	var (
 		comments []map[string]interface{}
 		stubs    = seedComments(t)
@@ -76,14 +78,10 @@ func TestCommentList_Demand(t *testing.T) {
 		req, err := http.NewRequest("GET", "/api/v1/comments?task=2", nil)
 		must(t, err, "testing: failed to make a GET request to '/api/v1/comments?task=2'")
 
-		_, err = a.DB.Exec(`insert into boards (name, description) values ('test board 2', 'test description 2');`)
-		must(t, err, "testing: failed seed a board for comment demand")
-		_, err = a.DB.Exec(`insert into columns (name, board, position) values ('test column 2', 2, 2000);`)
-		must(t, err, "testing: failed seed a column for comment demand")
-		_, err = a.DB.Exec(`insert into tasks (name, "column", position) values ('test task 2', 2, 0.5);`)
-		must(t, err, "testing: failed seed a task for comment demand")
-		_, err = a.DB.Exec(`insert into comments (text, task) values ('test comment 2', 2);`)
-		must(t, err, "testing: failed seed a comment for comment demand")
+		boardID := seedBoard(t, "test board 2", "test description 2")
+		columnID := seedColumn(t, "test column 2", boardID, 2000)
+		taskID := seedTask(t, "test task 2", "test description 2", columnID, 0.5)
+		seedComment(t, "test comment 2", taskID)
 
 		response := executeRequest(req)
 		err = json.Unmarshal(response.Body.Bytes(), &comments)
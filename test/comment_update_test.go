@@ -9,11 +9,10 @@ import (
 	testify "github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
-	"time"
 )
 
 func TestCommentUpdate_OK(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks", "comments")
+	resetData(t)
 
 	var (
 		assert = testify.New(t)
@@ -44,21 +43,13 @@ func TestCommentUpdate_OK(t *testing.T) {
 		assert.Equal(expectedText, comment["text"])
 		assert.Equal(float64(1), comment["task"])
 
-		var (
-			uID, taskID          uint
-			text                 string
-			createdAt, updatedAt time.Time
-		)
-		err = a.DB.QueryRow(`select id, text, task, created_at, updated_at from comments where id = $1;`, ID).
-			Scan(&uID, &text, &taskID, &createdAt, &updatedAt)
-		must(t, err, "testing: failed to make a query on board update test")
-
-		assert.Equal(uint(ID), uID)
-		assert.Equal(expectedText, text)
-		assert.Equal(uint(1), taskID)
-		assert.NotNil(createdAt)
-		assert.NotNil(updatedAt)
-		assert.True(updatedAt.After(createdAt))
+		saved, err := a.StoragesInternal().Comments.FindOneById(uint(ID))
+		must(t, err, "testing: failed to find the comment on comment update test")
+
+		assert.Equal(uint(ID), saved.ID)
+		assert.Equal(expectedText, saved.Text)
+		assert.Equal(uint(1), saved.TaskID)
+		assert.True(saved.UpdatedAt.After(saved.CreatedAt))
 	}
 }
 
@@ -117,7 +108,7 @@ func TestCommentUpdate_ValidationError(t *testing.T) {
 }
 
 func TestCommentUpdate_RecordNotFound(t *testing.T) {
-	clearTable(t, "comments")
+	resetData(t)
 
 	var (
 		err  error
@@ -139,7 +130,7 @@ func TestCommentUpdate_RecordNotFound(t *testing.T) {
 }
 
 func TestCommentUpdate_WrongTask(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks", "comments")
+	resetData(t)
 
 	var (
 		err  error
@@ -0,0 +1,56 @@
+// +build integrational
+
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestBoardEvents(t *testing.T) {
+	resetData(t)
+	seedTasks(t)
+	assert := testify.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(a.ServeHTTPInternal))
+	defer server.Close()
+	req, err := http.NewRequest("GET", server.URL+"/api/v1/boards/1/events?access_token="+token, nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/boards/1/events'")
+	req.Header.Set("Accept", "text/event-stream")
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	must(t, err, "testing: failed to subscribe to the board events")
+	defer resp.Body.Close()
+
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	req, err = http.NewRequest("POST", "/api/v1/tasks/1/move", bytes.NewBufferString(`{"column":1}`))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/tasks/1/move'")
+	assert.Equal(http.StatusOK, executeRequest(req).Code)
+	must(t, a.RelayInternal(), "testing: failed to relay the events")
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && scanner.Text() != "" {
+		lines = append(lines, scanner.Text())
+	}
+	assert.Len(lines, 3)
+	assert.Equal("event: task.moved", lines[1])
+	assert.True(strings.HasPrefix(lines[2], `data: {"id":`))
+	assert.Contains(lines[2], `"entity_id":1`)
+}
+
+func TestBoardEvents_NotAcceptable(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/v1/boards/1/events", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/boards/1/events'")
+
+	testify.Equal(t, http.StatusNotAcceptable, executeRequest(req).Code)
+}
@@ -3,7 +3,8 @@
 package test
 
 import (
-	"fmt"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -12,22 +13,31 @@ import (
 
 const maxTestsRunExpected = time.Second * 30
 
-func clearTables(t *testing.T, tables ...string) {
-	for _, table := range tables {
-		clearTable(t, table)
-	}
-}
+// token is an access token of the test user, it is sent with every request
+// that has no Authorization header set explicitly
+var token string
 
-func clearTable(t *testing.T, table string) {
-	if _, err := a.DB.Exec(fmt.Sprintf("DELETE FROM %s", table)); err != nil {
-		t.Fatalf("testing: table clearing failed: %v", err)
-	}
-	if _, err := a.DB.Exec(fmt.Sprintf("ALTER SEQUENCE %s_id_seq RESTART WITH 1", table)); err != nil {
-		t.Fatalf("testing: sequence restart failed: %v", err)
+// tester is the test user, it is the owner of all the seeded boards
+var tester models.User
+
+// resetData removes all the records and saves the test user again. The user gets
+// its former ID, so the issued token stays valid.
+func resetData(t *testing.T) {
+	must(t, a.ResetInternal(), "testing: data reset failed")
+
+	user := tester
+	user.ID = 0
+	_, err := a.StoragesInternal().Users.Save(&user)
+	must(t, err, "testing: failed to restore the test user")
+	if user.ID != tester.ID {
+		t.Fatalf("testing: the test user is restored with ID %d instead of %d", user.ID, tester.ID)
 	}
 }
 
 func executeRequest(req *http.Request) *httptest.ResponseRecorder {
+	if req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 	rr := httptest.NewRecorder()
 	a.ServeHTTPInternal(rr, req)
 
@@ -42,10 +52,36 @@ func makeStringStub(len uint) string {
 	return string(b)
 }
 
-func countItems(t *testing.T, table string) (num int) {
-	if err := a.DB.QueryRow(fmt.Sprintf(`select count(*) from %s`, table)).Scan(&num); err != nil {
-		t.Fatalf("testing: items counting failed: %v", err)
+// countItems counts the records of the table that are not in the trash
+func countItems(t *testing.T, table string) int {
+	var (
+		num int
+		err error
+
+		storages = a.StoragesInternal()
+	)
+	switch table {
+	case "boards":
+		var boards []*models.Board
+		boards, err = storages.Boards.Find(sv.BoardDemand{}, sv.Page{})
+		num = len(boards)
+	case "columns":
+		var columns []*models.Column
+		columns, err = storages.Columns.Find(sv.ColumnDemand{}, sv.Page{})
+		num = len(columns)
+	case "tasks":
+		var tasks []*models.Task
+		tasks, err = storages.Tasks.Find(sv.TaskDemand{}, sv.Page{})
+		num = len(tasks)
+	case "comments":
+		var comments []*models.Comment
+		comments, err = storages.Comments.Find(sv.CommentDemand{}, sv.Page{})
+		num = len(comments)
+	default:
+		t.Fatalf("testing: counting of %s is not supported", table)
 	}
+	must(t, err, "testing: items counting failed")
+
 	return num
 }
 
@@ -55,66 +91,86 @@ func must(t *testing.T, err error, message string, arg ...interface{}) {
 	}
 }
 
+// seedBoard saves a board the test user is an owner of and returns its ID
+func seedBoard(t *testing.T, name, description string) uint {
+	storages := a.StoragesInternal()
+	board, err := storages.Boards.Save(&models.Board{Name: name, Description: description})
+	must(t, err, "testing: failed to seed a board")
+	_, err = storages.Members.Save(&models.Member{BoardID: board.ID, UserID: tester.ID, Role: models.RoleOwner})
+	must(t, err, "testing: failed to join the board %d", board.ID)
+
+	return board.ID
+}
+
+// seedColumn saves a column and returns its ID
+func seedColumn(t *testing.T, name string, boardID uint, position float64) uint {
+	column, err := a.StoragesInternal().Columns.Save(&models.Column{Name: name, BoardID: boardID, Position: position})
+	must(t, err, "testing: failed to seed a column")
+
+	return column.ID
+}
+
+// seedTask saves a task and returns its ID
+func seedTask(t *testing.T, name, description string, columnID uint, position float64) uint {
+	task, err := a.StoragesInternal().Tasks.Save(&models.Task{
+		Name: name, Description: description, ColumnID: columnID, Position: position,
+	})
+	must(t, err, "testing: failed to seed a task")
+
+	return task.ID
+}
+
+// seedComment saves a comment and returns its ID
+func seedComment(t *testing.T, text string, taskID uint) uint {
+	comment, err := a.StoragesInternal().Comments.Save(&models.Comment{Text: text, TaskID: taskID})
+	must(t, err, "testing: failed to seed a comment")
+
+	return comment.ID
+}
+
+// updateTask applies the change to the saved task and stores the result
+func updateTask(t *testing.T, ID uint, change func(task *models.Task)) {
+	storages := a.StoragesInternal()
+	task, err := storages.Tasks.FindOneById(ID)
+	must(t, err, "testing: failed to find the task %d", ID)
+	change(task)
+	_, err = storages.Tasks.Update(task)
+	must(t, err, "testing: failed to update the task %d", ID)
+}
+
 type boardStub struct {
 	name, description string
-	timestamp         time.Time
 }
 
 func seedBoards(t *testing.T) []boardStub {
-	var err error
-	timestamp := time.Unix(1589932800, 0)
 	boards := []boardStub{
-		{"test name 1", "test description 1", timestamp},
-		{"test name 2", "test description 2", timestamp},
-		{"test name 3", "test description 3", timestamp},
+		{"test name 1", "test description 1"},
+		{"test name 2", "test description 2"},
+		{"test name 3", "test description 3"},
 	}
 	for _, b := range boards {
-		_, err = a.DB.Exec(`
-			insert into boards (name, description, created_at, updated_at)
-			values ($1, $2, $3, $3);`,
-			b.name, b.description, b.timestamp,
-		)
-		must(t, err, "testing: failed to seed boards")
+		seedBoard(t, b.name, b.description)
 	}
 
 	return boards
 }
 
 type columnStub struct {
-	name      string
-	board     uint
-	position  float64
-	timestamp time.Time
+	name     string
+	board    uint
+	position float64
 }
 
 func seedColumns(t *testing.T) []columnStub {
-	var (
-		err     error
-		boardID uint
-
-		timestamp = time.Unix(1589932800, 0)
-	)
-
-	err = a.DB.QueryRow(`
-			insert into boards (name, description, created_at, updated_at)
-			values ($1, $2, $3, $3)
-			returning id;`,
-		"test name 1", "test description 1", timestamp,
-	).Scan(&boardID)
-	must(t, err, "testing: failed to seed board for columns")
+	boardID := seedBoard(t, "test name 1", "test description 1")
 
 	columns := []columnStub{
-		{"test name 1", boardID, 1000, timestamp},
-		{"test name 2", boardID, 2000, timestamp},
-		{"test name 3", boardID, 3000, timestamp},
+		{"test name 1", boardID, 1000},
+		{"test name 2", boardID, 2000},
+		{"test name 3", boardID, 3000},
 	}
 	for _, c := range columns {
-		_, err = a.DB.Exec(`
-			insert into columns (name, board, position, created_at, updated_at)
-			values ($1, $2, $3, $4, $4);`,
-			c.name, c.board, c.position, c.timestamp,
-		)
-		must(t, err, "testing: failed to seed columns")
+		seedColumn(t, c.name, c.board, c.position)
 	}
 
 	return columns
@@ -124,100 +180,41 @@ type taskStub struct {
 	name, description string
 	column            uint
 	position          float64
-	timestamp         time.Time
 }
 
 func seedTasks(t *testing.T) []taskStub {
-	var (
-		err               error
-		boardID, columnID uint
-
-		timestamp = time.Unix(1589932800, 0)
-	)
-
-	err = a.DB.QueryRow(`
-			insert into boards (name, description, created_at, updated_at)
-			values ($1, $2, $3, $3)
-			returning id;`,
-		"test name 1", "test description 1", timestamp,
-	).Scan(&boardID)
-	must(t, err, "testing: failed to seed board for tasks")
-
-	err = a.DB.QueryRow(`
-			insert into columns (name, board, position, created_at, updated_at)
-			values ($1, $2, $3, $4, $4)
-			returning id;`,
-		"test name 1", boardID, 1000, timestamp,
-	).Scan(&columnID)
-	must(t, err, "testing: failed to seed column for tasks")
+	boardID := seedBoard(t, "test name 1", "test description 1")
+	columnID := seedColumn(t, "test name 1", boardID, 1000)
 
 	tasks := []taskStub{
-		{"test name 1", "test description 1", columnID, 1000, timestamp},
-		{"test name 2", "test description 1", columnID, 2000, timestamp},
-		{"test name 3", "test description 1", columnID, 3000, timestamp},
+		{"test name 1", "test description 1", columnID, 1000},
+		{"test name 2", "test description 1", columnID, 2000},
+		{"test name 3", "test description 1", columnID, 3000},
 	}
 	for _, task := range tasks {
-		_, err = a.DB.Exec(`
-			insert into tasks (name, description, "column", position, created_at, updated_at)
-			values ($1, $2, $3, $4, $5, $5);`,
-			task.name, task.description, task.column, task.position, task.timestamp,
-		)
-		must(t, err, "testing: failed to seed tasks")
+		seedTask(t, task.name, task.description, task.column, task.position)
 	}
 
 	return tasks
 }
 
 type commentStub struct {
-	text      string
-	task      uint
-	timestamp time.Time
+	text string
+	task uint
 }
 
 func seedComments(t *testing.T) []commentStub {
-	var (
-		err                       error
-		boardID, columnID, taskID uint
-
-		timestamp = time.Unix(1589932800, 0)
-	)
-
-	err = a.DB.QueryRow(`
-			insert into boards (name, description, created_at, updated_at)
-			values ($1, $2, $3, $3)
-			returning id;`,
-		"test name 1", "test description 1", timestamp,
-	).Scan(&boardID)
-	must(t, err, "testing: failed to seed a board for comments")
-
-	err = a.DB.QueryRow(`
-			insert into columns (name, board, position, created_at, updated_at)
-			values ($1, $2, $3, $4, $4)
-			returning id;`,
-		"test name 1", boardID, 1000, timestamp,
-	).Scan(&columnID)
-	must(t, err, "testing: failed to seed a column for comments")
-
-	err = a.DB.QueryRow(`
-			insert into tasks (name, description, "column", position, created_at, updated_at)
-			values ('test name 1', 'test description 1', $1, 1000, $2, $2)
-			returning id;`,
-		columnID, timestamp,
-	).Scan(&taskID)
-	must(t, err, "testing: failed to seed a task for comments")
+	boardID := seedBoard(t, "test name 1", "test description 1")
+	columnID := seedColumn(t, "test name 1", boardID, 1000)
+	taskID := seedTask(t, "test name 1", "test description 1", columnID, 1000)
 
 	comments := []commentStub{
-		{"test text 1", taskID, timestamp},
-		{"test text 2", taskID, timestamp},
-		{"test text 3", taskID, timestamp},
+		{"test text 1", taskID},
+		{"test text 2", taskID},
+		{"test text 3", taskID},
 	}
 	for _, c := range comments {
-		_, err = a.DB.Exec(`
-			insert into comments (text, task, created_at, updated_at)
-			values ($1, $2, $3, $3);`,
-			c.text, c.task, c.timestamp,
-		)
-		must(t, err, "testing: failed to seed comments")
+		seedComment(t, c.text, c.task)
 	}
 
 	return comments
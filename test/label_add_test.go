@@ -0,0 +1,65 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestLabelAdd_OK(t *testing.T) {
+	resetData(t)
+	var (
+		err    error
+		label  map[string]interface{}
+		tasks  []map[string]interface{}
+		assert = testify.New(t)
+		stubs  = seedTasks(t)
+	)
+
+	payload := []byte(`{"name":"bug","color":"#ff0000","board":1}`)
+	req, err := http.NewRequest("POST", "/api/v1/label", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request")
+
+	response := executeRequest(req)
+	err = json.Unmarshal(response.Body.Bytes(), &label)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusCreated, response.Code)
+	assert.Equal("/api/v1/labels/1", response.Header().Get("Location"))
+	assert.Equal("bug", label["name"])
+	assert.Equal(float64(1), label["board"])
+
+	req, err = http.NewRequest("POST", "/api/v1/label", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request")
+	assert.Equal(http.StatusConflict, executeRequest(req).Code)
+
+	err = a.StoragesInternal().Tasks.SetLabels(2, []uint{1})
+	must(t, err, "testing: failed to label a task")
+
+	req, err = http.NewRequest("GET", "/api/v1/tasks?label=1", nil)
+	must(t, err, "testing: failed to make a GET request")
+
+	response = executeRequest(req)
+	err = json.Unmarshal(response.Body.Bytes(), &tasks)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Len(tasks, 1)
+	assert.Equal(stubs[1].name, tasks[0]["name"])
+	assert.Equal([]interface{}{float64(1)}, tasks[0]["labels"])
+}
+
+func TestLabelAdd_WrongBoard(t *testing.T) {
+	resetData(t)
+	assert := testify.New(t)
+
+	payload := []byte(`{"name":"bug","color":"#ff0000","board":1}`)
+	req, err := http.NewRequest("POST", "/api/v1/label", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request")
+
+	assert.Equal(http.StatusBadRequest, executeRequest(req).Code)
+}
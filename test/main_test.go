@@ -6,7 +6,12 @@
 package test
 
 import (
+	"bytes"
+	"encoding/json"
 	"github.com/dnozdrin/detask/internal/app"
+	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -16,7 +21,7 @@ var a app.App
 func TestMain(m *testing.M) {
 	a.Initialize(
 		app.NewDBConfig(
-			"postgres",
+			os.Getenv("DB_DRIVER"),
 			os.Getenv("DB_HOST"),
 			os.Getenv("DB_NAME"),
 			os.Getenv("DB_USER"),
@@ -28,8 +33,59 @@ func TestMain(m *testing.M) {
 			app.Test,
 			"stderr",
 			"",
+			"test secret",
+			"",
+			"",
+			"",
 		),
 	)
+	token = signIn()
 	code := m.Run()
 	os.Exit(code)
 }
+
+// signIn registers the test user and returns an access token for it
+func signIn() string {
+	if err := a.ResetInternal(); err != nil {
+		log.Fatalf("testing: data reset failed: %v", err)
+	}
+
+	postJSON := func(path, body string, status int) []byte {
+		req, err := http.NewRequest("POST", path, bytes.NewBufferString(body))
+		if err != nil {
+			log.Fatalf("testing: failed to make a POST request to '%s': %v", path, err)
+		}
+		rr := httptest.NewRecorder()
+		a.ServeHTTPInternal(rr, req)
+		if rr.Code != status {
+			log.Fatalf("testing: unexpected response from '%s': %d %s", path, rr.Code, rr.Body.String())
+		}
+
+		return rr.Body.Bytes()
+	}
+
+	postJSON(
+		"/api/v1/auth/register",
+		`{"email":"tester@example.com","name":"tester","password":"test password"}`,
+		http.StatusCreated,
+	)
+	login := postJSON(
+		"/api/v1/auth/login",
+		`{"email":"tester@example.com","password":"test password"}`,
+		http.StatusOK,
+	)
+
+	var res struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(login, &res); err != nil {
+		log.Fatalf("testing: failed to unmarshal the login response: %v", err)
+	}
+	user, err := a.StoragesInternal().Users.FindOneByEmail("tester@example.com")
+	if err != nil {
+		log.Fatalf("testing: failed to find the test user: %v", err)
+	}
+	tester = *user
+
+	return res.Token
+}
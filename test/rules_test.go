@@ -0,0 +1,84 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRules(t *testing.T) {
+	resetData(t)
+	seedTasks(t)
+	assert := testify.New(t)
+
+	request := func(method, path, body string) (int, []byte) {
+		req, err := http.NewRequest(method, path, bytes.NewBuffer([]byte(body)))
+		must(t, err, "testing: failed to make a %s request to '%s'", method, path)
+		response := executeRequest(req)
+		return response.Code, response.Body.Bytes()
+	}
+	executions := func(ruleID string) []map[string]interface{} {
+		var executions []map[string]interface{}
+		code, body := request("GET", "/api/v1/rules/"+ruleID+"/executions", "")
+		assert.Equal(http.StatusOK, code)
+		must(t, json.Unmarshal(body, &executions), "testing: failed to unmarshal %s", body)
+		return executions
+	}
+
+	code, body := request("POST", "/api/v1/boards/1/rules",
+		`{"name":"welcome","trigger":{"type":"task.created"},"actions":[{"type":"comment","text":"welcome"}]}`)
+	assert.Equal(http.StatusCreated, code)
+	var rule map[string]interface{}
+	must(t, json.Unmarshal(body, &rule), "testing: failed to unmarshal %s", body)
+	assert.NotZero(rule["created_by"])
+
+	// the rule comments on every comment, including its own ones
+	code, _ = request("POST", "/api/v1/boards/1/rules",
+		`{"name":"echo","trigger":{"type":"comment.created"},"actions":[{"type":"comment","text":"echo"}]}`)
+	assert.Equal(http.StatusCreated, code)
+	code, _ = request("POST", "/api/v1/boards/1/rules",
+		`{"name":"late","trigger":{"type":"task.overdue"},"actions":[{"type":"comment","text":"late"}]}`)
+	assert.Equal(http.StatusCreated, code)
+	code, _ = request("POST", "/api/v1/boards/1/rules",
+		`{"name":"invalid","trigger":{"type":"task.created"},"actions":[{"type":"move"}]}`)
+	assert.Equal(http.StatusBadRequest, code)
+
+	code, _ = request("POST", "/api/v1/task", `{"name":"new","description":"new","column":1,"position":5000}`)
+	assert.Equal(http.StatusCreated, code)
+	must(t, a.RelayInternal(), "testing: failed to relay the events")
+
+	var comments []map[string]interface{}
+	code, body = request("GET", "/api/v1/comments?task=4", "")
+	assert.Equal(http.StatusOK, code)
+	must(t, json.Unmarshal(body, &comments), "testing: failed to unmarshal %s", body)
+	assert.Len(comments, 2)
+
+	assert.Len(executions("1"), 1)
+	if echoes := executions("2"); assert.Len(echoes, 2) {
+		assert.Equal("skipped", echoes[0]["status"])
+		assert.Equal("succeeded", echoes[1]["status"])
+	}
+
+	updateTask(t, 2, func(task *models.Task) {
+		dueAt := time.Now().UTC().Add(-time.Hour)
+		task.DueAt = &dueAt
+	})
+	must(t, a.CheckOverdueInternal(), "testing: failed to check the overdue tasks")
+	must(t, a.CheckOverdueInternal(), "testing: failed to check the overdue tasks")
+	if late := executions("3"); assert.Len(late, 1) {
+		assert.Equal(2.0, late[0]["task"])
+	}
+
+	code, _ = request("PUT", "/api/v1/rules/2", `{"name":"echo","trigger":{"type":"comment.created"},"actions":[{"type":"comment","text":"echo"}],"disabled":true}`)
+	assert.Equal(http.StatusOK, code)
+	code, _ = request("DELETE", "/api/v1/rules/2", "")
+	assert.Equal(http.StatusNoContent, code)
+	code, _ = request("GET", "/api/v1/rules/2/executions", "")
+	assert.Equal(http.StatusNotFound, code)
+}
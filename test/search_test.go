@@ -0,0 +1,72 @@
+// +build integrational
+
+package test
+
+import (
+	"encoding/json"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestSearch_OK(t *testing.T) {
+	resetData(t)
+	var (
+		err  error
+		hits []map[string]interface{}
+
+		assert = testify.New(t)
+		stubs  = seedComments(t)
+	)
+
+	req, err := http.NewRequest("GET", "/api/v1/search?q=test", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/search'")
+
+	response := executeRequest(req)
+	err = json.Unmarshal(response.Body.Bytes(), &hits)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Len(hits, len(stubs)+1)
+	for _, hit := range hits {
+		assert.Equal(float64(stubs[0].task), hit["task"])
+		assert.Equal("test name 1", hit["name"])
+		assert.Contains(hit["snippet"], "<b>test</b>")
+	}
+
+	req, err = http.NewRequest("GET", "/api/v1/search?q=text+2", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/search'")
+
+	response = executeRequest(req)
+	err = json.Unmarshal(response.Body.Bytes(), &hits)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Len(hits, 1)
+	assert.Equal("comment", hits[0]["type"])
+	assert.Equal("test <b>text</b> <b>2</b>", hits[0]["snippet"])
+}
+
+func TestSearch_Paginated(t *testing.T) {
+	resetData(t)
+	seedComments(t)
+	assert := testify.New(t)
+
+	req, err := http.NewRequest("GET", "/api/v1/search?q=test&limit=2", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/search'")
+
+	response := executeRequest(req)
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Contains(response.Header().Get("Link"), `rel="next"`)
+}
+
+func TestSearch_BadRequest(t *testing.T) {
+	assert := testify.New(t)
+
+	for _, query := range []string{"", "?q=+", "?q=test&board=first"} {
+		req, err := http.NewRequest("GET", "/api/v1/search"+query, nil)
+		must(t, err, "testing: failed to make a GET request to '/api/v1/search'")
+
+		assert.Equal(http.StatusBadRequest, executeRequest(req).Code, query)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
 	testify "github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
@@ -13,7 +14,7 @@ import (
 )
 
 func TestTaskAdd_OK(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 
 	const (
 		name                = "test name"
@@ -48,29 +49,22 @@ func TestTaskAdd_OK(t *testing.T) {
 
 	assert.Equal(http.StatusCreated, response.Code)
 	assert.Equal("/api/v1/tasks/1", response.Header().Get("Location"))
+	assert.Equal(`"1"`, response.Header().Get("ETag"))
 	assert.Equal(1.0, task["id"])
 	assert.Equal(name, task["name"])
 	assert.Equal(1.0, task["column"])
 	assert.Equal(position, task["position"])
 
-	var (
-		ID, checkColumn      uint
-		checkName            string
-		checkDescription     string
-		createdAt, updatedAt time.Time
-		checkPosition        float64
-	)
-	err = a.DB.QueryRow(`select id, name, description, "column", position, created_at, updated_at from tasks where id = 1;`).
-		Scan(&ID, &checkName, &checkDescription, &checkColumn, &checkPosition, &createdAt, &updatedAt)
-	must(t, err, "testing: failed to make database query on column add test")
-
-	assert.Equal(uint(1), ID)
-	assert.Equal(name, checkName)
-	assert.Equal(description, checkDescription)
-	assert.Equal(uint(column), checkColumn)
-	assert.Equal(position, checkPosition)
-	assert.WithinDuration(time.Now(), createdAt, maxTestsRunExpected)
-	assert.WithinDuration(time.Now(), updatedAt, maxTestsRunExpected)
+	saved, err := a.StoragesInternal().Tasks.FindOneById(1)
+	must(t, err, "testing: failed to find the task on task add test")
+
+	assert.Equal(uint(1), saved.ID)
+	assert.Equal(name, saved.Name)
+	assert.Equal(description, saved.Description)
+	assert.Equal(uint(column), saved.ColumnID)
+	assert.Equal(position, saved.Position)
+	assert.WithinDuration(time.Now(), saved.CreatedAt, maxTestsRunExpected)
+	assert.WithinDuration(time.Now(), saved.UpdatedAt, maxTestsRunExpected)
 }
 
 func TestTaskAdd_BadRequest(t *testing.T) {
@@ -131,7 +125,7 @@ func TestTaskAdd_ValidationError(t *testing.T) {
 }
 
 func TestTaskAdd_WrongColumn(t *testing.T) {
-	clearTables(t, "columns", "tasks")
+	resetData(t)
 
 	var (
 		err  error
@@ -153,7 +147,7 @@ func TestTaskAdd_WrongColumn(t *testing.T) {
 }
 
 func TestTaskAdd_PositionDuplicate(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 
 	const (
 		name                = "test name"
@@ -170,12 +164,9 @@ func TestTaskAdd_PositionDuplicate(t *testing.T) {
 		jsonStr = fmt.Sprintf(`{"name":"%s","column":%d,"position":%f, "description": "%s"}`, name, column, position, description)
 	)
 
-	_, err = a.DB.Exec(`insert into boards (name, description) values ($1, 'test description');`, name)
-	must(t, err, "testing: failed to insert a board for task add test")
-	_, err = a.DB.Exec(`insert into columns (name, board, position) values ($1, $2, $3);`, name, board, position)
-	must(t, err, "testing: failed to insert a column for task add test")
-	_, err = a.DB.Exec(`insert into tasks (name, "column", position) values ($1, $2, $3);`, name, column, position)
-	must(t, err, "testing: failed to insert a task for task add test")
+	seedBoard(t, name, "test description")
+	seedColumn(t, name, board, position)
+	seedTask(t, name, description, column, position)
 
 	req, err := http.NewRequest("POST", "/api/v1/task", bytes.NewBuffer([]byte(jsonStr)))
 	must(t, err, "testing: failed to make a POST request to '/api/v1/task'")
@@ -187,3 +178,27 @@ func TestTaskAdd_PositionDuplicate(t *testing.T) {
 	assert.Equal(http.StatusConflict, response.Code)
 	assert.Equal("this position has been already taken", body["error"])
 }
+
+func TestTaskAdd_WIPLimitExceeded(t *testing.T) {
+	resetData(t)
+	assert := testify.New(t)
+	_ = seedTasks(t)
+
+	storages := a.StoragesInternal()
+	column, err := storages.Columns.FindOneById(1)
+	must(t, err, "testing: failed to find the column")
+	column.WIPLimit = 3
+	_, err = storages.Columns.Update(column)
+	must(t, err, "testing: failed to set the column WIP limit")
+
+	payload := []byte(`{"name":"test name 4","description":"test description","column":1,"position":4000}`)
+	req, err := http.NewRequest("POST", "/api/v1/task", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/task'")
+
+	response := executeRequest(req)
+	assert.Equal(http.StatusConflict, response.Code)
+
+	tasks, err := storages.Tasks.Find(sv.TaskDemand{"column": uint(1)}, sv.Page{})
+	must(t, err, "testing: failed to count tasks")
+	assert.Len(tasks, 3)
+}
@@ -0,0 +1,41 @@
+// +build integrational
+
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestTaskAssigned_OK(t *testing.T) {
+	resetData(t)
+	var (
+		err    error
+		userID uint
+		tasks  []map[string]interface{}
+
+		assert = testify.New(t)
+		stubs  = seedTasks(t)
+	)
+
+	userID = tester.ID
+	err = a.StoragesInternal().Tasks.SetAssignees(2, []uint{userID})
+	must(t, err, "testing: failed to assign a task")
+
+	for _, path := range []string{"/api/v1/me/tasks", fmt.Sprintf("/api/v1/tasks?assignee=%d", userID)} {
+		req, err := http.NewRequest("GET", path, nil)
+		must(t, err, "testing: failed to make a GET request to '%s'", path)
+
+		response := executeRequest(req)
+		err = json.Unmarshal(response.Body.Bytes(), &tasks)
+		must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+		assert.Equal(http.StatusOK, response.Code)
+		assert.Len(tasks, 1)
+		assert.Equal(stubs[1].name, tasks[0]["name"])
+		assert.Equal([]interface{}{float64(userID)}, tasks[0]["assignees"])
+	}
+}
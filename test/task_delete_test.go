@@ -10,7 +10,7 @@ import (
 )
 
 func TestTaskDelete(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 
 	var (
 		assert   = testify.New(t)
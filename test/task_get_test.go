@@ -11,7 +11,7 @@ import (
 )
 
 func TestTaskGet_OK(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 	var (
 		task map[string]interface{}
 
@@ -38,7 +38,7 @@ func TestTaskGet_OK(t *testing.T) {
 }
 
 func TestTaskGet_NotFound(t *testing.T) {
-	clearTables(t, "tasks")
+	resetData(t)
 	var (
 		err  error
 		body map[string]interface{}
@@ -4,13 +4,14 @@ package test
 
 import (
 	"encoding/json"
+	"github.com/dnozdrin/detask/internal/domain/models"
 	testify "github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
 )
 
 func TestTaskList_OK(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 	var (
 		err   error
 		tasks []map[string]interface{}
@@ -37,7 +38,7 @@ func TestTaskList_OK(t *testing.T) {
 }
 
 func TestTaskList_NoItems(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 	var (
 		err   error
 		tasks []map[string]interface{}
@@ -57,7 +58,7 @@ func TestTaskList_NoItems(t *testing.T) {
 }
 
 func TestTaskList_Demand(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 	var (
 		tasks  []map[string]interface{}
 		stubs  = seedTasks(t)
@@ -79,12 +80,9 @@ func TestTaskList_Demand(t *testing.T) {
 		req, err := http.NewRequest("GET", "/api/v1/tasks?column=2", nil)
 		must(t, err, "testing: failed to make a GET request to '/api/v1/tasks?column=2'")
 
-		_, err = a.DB.Exec(`insert into boards (name, description) values ('test board 2', 'test description 2');`)
-		must(t, err, "testing: failed seed a board for task demand")
-		_, err = a.DB.Exec(`insert into columns (name, board, position) values ('test column 2', 2, 2000);`)
-		must(t, err, "testing: failed seed a column for task demand")
-		_, err = a.DB.Exec(`insert into tasks (name, "column", position) values ('test task N', 2, 0.5);`)
-		must(t, err, "testing: failed seed a task for task demand")
+		boardID := seedBoard(t, "test board 2", "test description 2")
+		columnID := seedColumn(t, "test column 2", boardID, 2000)
+		seedTask(t, "test task N", "test description N", columnID, 0.5)
 
 		response := executeRequest(req)
 		err = json.Unmarshal(response.Body.Bytes(), &tasks)
@@ -129,3 +127,34 @@ func TestTaskList_Demand(t *testing.T) {
 		assert.Equal("invalid filter params", body["error"])
 	})
 }
+
+func TestTaskList_SortByPriority(t *testing.T) {
+	resetData(t)
+	var (
+		err   error
+		tasks []map[string]interface{}
+
+		assert = testify.New(t)
+		stubs  = seedTasks(t)
+	)
+
+	updateTask(t, 2, func(task *models.Task) {
+		task.Priority = models.PriorityHighest
+	})
+
+	req, err := http.NewRequest("GET", "/api/v1/tasks?sort=-priority,position", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/tasks'")
+
+	response := executeRequest(req)
+	err = json.Unmarshal(response.Body.Bytes(), &tasks)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	if assert.Len(tasks, len(stubs)) {
+		assert.Equal(stubs[1].name, tasks[0]["name"])
+		assert.Equal("highest", tasks[0]["priority"])
+		assert.Equal(stubs[0].name, tasks[1]["name"])
+		assert.Equal("medium", tasks[1]["priority"])
+		assert.Equal(stubs[2].name, tasks[2]["name"])
+	}
+}
@@ -0,0 +1,118 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/dnozdrin/detask/internal/domain/models"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestTaskMove_OK(t *testing.T) {
+	resetData(t)
+
+	var (
+		err  error
+		body map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedTasks(t)
+	payload := []byte(`{"column":1,"before":1}`)
+	req, err := http.NewRequest("POST", "/api/v1/tasks/3/move", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/tasks/3/move'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &body)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(3.0, body["id"])
+	assert.Equal(1.0, body["column"])
+	assert.Equal(500.0, body["position"])
+
+	saved, err := a.StoragesInternal().Tasks.FindOneById(3)
+	must(t, err, "testing: failed to find the task on task move test")
+	assert.Equal(fmt.Sprintf(`"%d"`, saved.Version), response.Header().Get("ETag"))
+}
+
+func TestTaskMove_Rebalance(t *testing.T) {
+	resetData(t)
+	assert := testify.New(t)
+	_ = seedTasks(t)
+
+	updateTask(t, 2, func(task *models.Task) {
+		task.Position = 1.0000000000000002
+	})
+	updateTask(t, 1, func(task *models.Task) {
+		task.Position = 1
+	})
+
+	payload := []byte(`{"column":1,"after":1}`)
+	req, err := http.NewRequest("POST", "/api/v1/tasks/3/move", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/tasks/3/move'")
+	response := executeRequest(req)
+	assert.Equal(http.StatusOK, response.Code)
+
+	tasks, err := a.StoragesInternal().Tasks.Find(sv.TaskDemand{"column": uint(1)}, sv.Page{})
+	must(t, err, "testing: failed to find tasks")
+
+	positions := make([][2]float64, 0, len(tasks))
+	for _, task := range tasks {
+		positions = append(positions, [2]float64{float64(task.ID), task.Position})
+	}
+	assert.Equal([][2]float64{{1, 1000}, {3, 2000}, {2, 3000}}, positions)
+}
+
+func TestTaskMove_ForeignTask(t *testing.T) {
+	resetData(t)
+
+	var (
+		err  error
+		body map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedTasks(t)
+	payload := []byte(`{"column":1,"after":999}`)
+	req, err := http.NewRequest("POST", "/api/v1/tasks/3/move", bytes.NewBuffer(payload))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/tasks/3/move'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &body)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusBadRequest, response.Code)
+	assert.Equal("a task with the provided ID was not found", body["error"])
+}
+
+func TestTaskMove_AnotherBoard(t *testing.T) {
+	resetData(t)
+
+	var (
+		err  error
+		body map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	// the task 1 is on the board 1, the column 2 is on the board 2
+	_ = seedComments(t)
+	_ = seedTasks(t)
+	req, err := http.NewRequest("POST", "/api/v1/tasks/1/move", bytes.NewBuffer([]byte(`{"column":2}`)))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/tasks/1/move'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &body)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusBadRequest, response.Code)
+	assert.Contains(response.Body.String(), "transfer the task instead")
+}
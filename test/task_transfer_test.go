@@ -0,0 +1,81 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	sv "github.com/dnozdrin/detask/internal/domain/services"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestTaskTransfer_OK(t *testing.T) {
+	resetData(t)
+
+	var (
+		err  error
+		task map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	// the task 1 with comments is on the board 1, the column 2 is on the board 2
+	_ = seedComments(t)
+	_ = seedTasks(t)
+	req, err := http.NewRequest("POST", "/api/v1/tasks/1/transfer", bytes.NewBuffer([]byte(`{"column":2}`)))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/tasks/1/transfer'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &task)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Equal(2.0, task["column"])
+	assert.Equal(4000.0, task["position"])
+
+	saved, err := a.StoragesInternal().Tasks.FindOneById(1)
+	must(t, err, "testing: failed to find the task on task transfer test")
+	assert.Equal(fmt.Sprintf(`"%d"`, saved.Version), response.Header().Get("ETag"))
+
+	comments, err := a.StoragesInternal().Comments.Find(sv.CommentDemand{"task": uint(1)}, sv.Page{})
+	must(t, err, "testing: failed to count comments")
+	assert.Len(comments, 3)
+}
+
+func TestTaskTransfer_WrongColumn(t *testing.T) {
+	resetData(t)
+
+	var (
+		err  error
+		body map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedTasks(t)
+	req, err := http.NewRequest("POST", "/api/v1/tasks/1/transfer", bytes.NewBuffer([]byte(`{"column":999}`)))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/tasks/1/transfer'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &body)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusBadRequest, response.Code)
+	assert.Equal("a column with the provided ID was not found", body["error"])
+}
+
+func TestTaskTransfer_SameBoard(t *testing.T) {
+	resetData(t)
+	assert := testify.New(t)
+
+	_ = seedTasks(t)
+	req, err := http.NewRequest("POST", "/api/v1/tasks/1/transfer", bytes.NewBuffer([]byte(`{"column":1}`)))
+	must(t, err, "testing: failed to make a POST request to '/api/v1/tasks/1/transfer'")
+	response := executeRequest(req)
+
+	assert.Equal(http.StatusBadRequest, response.Code)
+	assert.Contains(response.Body.String(), "move the task instead")
+}
@@ -9,19 +9,17 @@ import (
 	testify "github.com/stretchr/testify/assert"
 	"net/http"
 	"testing"
-	"time"
 )
 
 func TestTaskUpdate_OK(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 
 	var (
 		assert = testify.New(t)
 		stubs  = seedTasks(t)
 	)
 
-	_, err := a.DB.Exec(`insert into columns (name, board, position) values ('test column 2', 1, 2000);`)
-	must(t, err, "testing: failed seed a column for task update")
+	seedColumn(t, "test column 2", 1, 2000)
 
 	itemsNum := len(stubs)
 	for ID := 1; ID <= len(stubs); ID++ {
@@ -58,24 +56,15 @@ func TestTaskUpdate_OK(t *testing.T) {
 		assert.Equal(expectedPosition, task["position"])
 		assert.Equal(float64(expectedColumn), task["column"])
 
-		var (
-			uID, columnID        uint
-			position             float64
-			name, description    string
-			createdAt, updatedAt time.Time
-		)
-		err = a.DB.QueryRow(`select id, name, description, position, "column", created_at, updated_at from tasks where id = $1;`, ID).
-			Scan(&uID, &name, &description, &position, &columnID, &createdAt, &updatedAt)
-		must(t, err, "testing: failed to make a query on board update test")
-
-		assert.Equal(uint(ID), uID)
-		assert.Equal(expectedName, name)
-		assert.Equal(expectedDescription, description)
-		assert.Equal(expectedPosition, position)
-		assert.Equal(uint(expectedColumn), columnID)
-		assert.NotNil(createdAt)
-		assert.NotNil(updatedAt)
-		assert.True(updatedAt.After(createdAt))
+		saved, err := a.StoragesInternal().Tasks.FindOneById(uint(ID))
+		must(t, err, "testing: failed to find the task on task update test")
+
+		assert.Equal(uint(ID), saved.ID)
+		assert.Equal(expectedName, saved.Name)
+		assert.Equal(expectedDescription, saved.Description)
+		assert.Equal(expectedPosition, saved.Position)
+		assert.Equal(uint(expectedColumn), saved.ColumnID)
+		assert.True(saved.UpdatedAt.After(saved.CreatedAt))
 	}
 }
 
@@ -137,7 +126,7 @@ func TestTaskUpdate_ValidationError(t *testing.T) {
 }
 
 func TestTaskUpdate_RecordNotFound(t *testing.T) {
-	clearTable(t, "tasks")
+	resetData(t)
 
 	var (
 		err  error
@@ -159,7 +148,7 @@ func TestTaskUpdate_RecordNotFound(t *testing.T) {
 }
 
 func TestTaskUpdate_PositionDuplicate(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 
 	var (
 		err  error
@@ -182,7 +171,7 @@ func TestTaskUpdate_PositionDuplicate(t *testing.T) {
 }
 
 func TestTaskUpdate_WrongColumn(t *testing.T) {
-	clearTables(t, "boards", "columns", "tasks")
+	resetData(t)
 
 	var (
 		err  error
@@ -203,3 +192,32 @@ func TestTaskUpdate_WrongColumn(t *testing.T) {
 	assert.Equal(http.StatusBadRequest, response.Code)
 	assert.Equal("a column with the provided ID was not found", body["error"])
 }
+
+func TestTaskUpdate_AnotherBoard(t *testing.T) {
+	resetData(t)
+
+	var (
+		err  error
+		body map[string]interface{}
+
+		assert  = testify.New(t)
+		jsonStr = fmt.Sprintf(`{"name":"test", "description":"test", "position": 5000, "column": 2}`)
+	)
+
+	// the task 1 is on the board 1, the column 2 is on the board 2
+	_ = seedComments(t)
+	_ = seedTasks(t)
+	req, err := http.NewRequest("PUT", "/api/v1/tasks/1", bytes.NewBuffer([]byte(jsonStr)))
+	must(t, err, "testing: failed to make a PUT request to '/api/v1/tasks/1'")
+	response := executeRequest(req)
+
+	err = json.Unmarshal(response.Body.Bytes(), &body)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusBadRequest, response.Code)
+	assert.Contains(response.Body.String(), "transfer the task instead")
+
+	saved, err := a.StoragesInternal().Tasks.FindOneById(1)
+	must(t, err, "testing: failed to find the task on task update test")
+	assert.Equal(uint(1), saved.ColumnID)
+}
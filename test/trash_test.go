@@ -0,0 +1,55 @@
+// +build integrational
+
+package test
+
+import (
+	"encoding/json"
+	testify "github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestTrash(t *testing.T) {
+	resetData(t)
+
+	var (
+		err   error
+		items []map[string]interface{}
+
+		assert = testify.New(t)
+	)
+
+	_ = seedTasks(t)
+	request := func(method, path string) int {
+		req, err := http.NewRequest(method, path, nil)
+		must(t, err, "testing: failed to make a %s request to '%s'", method, path)
+		return executeRequest(req).Code
+	}
+
+	assert.Equal(http.StatusNoContent, request("DELETE", "/api/v1/tasks/1"))
+	assert.Equal(http.StatusNotFound, request("GET", "/api/v1/tasks/1"))
+	assert.Equal(2, countItems(t, "tasks"))
+
+	req, err := http.NewRequest("GET", "/api/v1/trash", nil)
+	must(t, err, "testing: failed to make a GET request to '/api/v1/trash'")
+	response := executeRequest(req)
+	err = json.Unmarshal(response.Body.Bytes(), &items)
+	must(t, err, "testing: failed to unmarshal %v", response.Body.Bytes())
+
+	assert.Equal(http.StatusOK, response.Code)
+	assert.Len(items, 1)
+	assert.Equal("task", items[0]["type"])
+	assert.Equal(1.0, items[0]["id"])
+
+	assert.Equal(http.StatusNoContent, request("POST", "/api/v1/tasks/1/restore"))
+	assert.Equal(http.StatusNotFound, request("POST", "/api/v1/tasks/1/restore"))
+	assert.Equal(http.StatusOK, request("GET", "/api/v1/tasks/1"))
+	assert.Equal(3, countItems(t, "tasks"))
+
+	assert.Equal(http.StatusNoContent, request("DELETE", "/api/v1/boards/1"))
+	assert.Equal(http.StatusNotFound, request("GET", "/api/v1/tasks/1"))
+	assert.Equal(http.StatusConflict, request("POST", "/api/v1/columns/1/restore"))
+	assert.Equal(http.StatusNoContent, request("POST", "/api/v1/boards/1/restore"))
+	assert.Equal(http.StatusOK, request("GET", "/api/v1/tasks/1"))
+	assert.Equal(1, countItems(t, "boards"))
+}
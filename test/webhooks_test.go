@@ -0,0 +1,63 @@
+// +build integrational
+
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestWebhooks(t *testing.T) {
+	resetData(t)
+	seedTasks(t)
+	assert := testify.New(t)
+
+	request := func(method, path, body string) (int, []byte) {
+		req, err := http.NewRequest(method, path, bytes.NewBuffer([]byte(body)))
+		must(t, err, "testing: failed to make a %s request to '%s'", method, path)
+		response := executeRequest(req)
+		return response.Code, response.Body.Bytes()
+	}
+
+	code, body := request("POST", "/api/v1/boards/1/webhooks", `{"url":"http://127.0.0.1:1/hook","events":["task.moved"]}`)
+	assert.Equal(http.StatusCreated, code)
+	var webhook map[string]interface{}
+	must(t, json.Unmarshal(body, &webhook), "testing: failed to unmarshal %s", body)
+	assert.Len(webhook["secret"], 64)
+
+	code, _ = request("POST", "/api/v1/boards/1/webhooks", `{"url":"http://127.0.0.1:1/hook","events":["task.exploded"]}`)
+	assert.Equal(http.StatusBadRequest, code)
+
+	code, body = request("GET", "/api/v1/webhooks/1", "")
+	assert.Equal(http.StatusOK, code)
+	assert.NotContains(string(body), "secret")
+
+	code, _ = request("POST", "/api/v1/tasks/1/move", `{"column":1}`)
+	assert.Equal(http.StatusOK, code)
+	code, _ = request("PUT", "/api/v1/tasks/1", `{"name":"renamed","description":"test description 1","column":1,"position":1000}`)
+	assert.Equal(http.StatusOK, code)
+	must(t, a.RelayInternal(), "testing: failed to relay the events")
+
+	var deliveries []map[string]interface{}
+	code, body = request("GET", "/api/v1/webhooks/1/deliveries", "")
+	assert.Equal(http.StatusOK, code)
+	must(t, json.Unmarshal(body, &deliveries), "testing: failed to unmarshal %s", body)
+	if assert.Len(deliveries, 1) {
+		assert.Equal("task.moved", deliveries[0]["event"])
+		assert.Equal("pending", deliveries[0]["status"])
+	}
+
+	code, _ = request("POST", "/api/v1/webhooks/1/deliveries/1/redeliver", "")
+	assert.Equal(http.StatusAccepted, code)
+	code, _ = request("POST", "/api/v1/webhooks/1/deliveries/9/redeliver", "")
+	assert.Equal(http.StatusNotFound, code)
+
+	code, _ = request("DELETE", "/api/v1/webhooks/1", "")
+	assert.Equal(http.StatusNoContent, code)
+	code, _ = request("GET", "/api/v1/webhooks/1/deliveries", "")
+	assert.Equal(http.StatusNotFound, code)
+}